@@ -2,24 +2,33 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	_ "net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/auth"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/interceptors"
 	static "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
 	http3 "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/metrics"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/carrier"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/paymentgateway"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/postgres"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/redis"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase/service"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/scheduler"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	_ "github.com/grafana/loki-client-go/loki"
 	_ "github.com/grafana/loki-client-go/pkg/urlutil"
@@ -57,6 +66,11 @@ func main() {
 		logger.Error("failed to init config", zap.Error(err))
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "app", cfg.Tracing)
+	if err != nil {
+		logger.Fatal("failed to init tracing", zap.Error(err))
+	}
+
 	router, err := Init(cfg)
 	if err != nil {
 		logger.Error("failed to initialize router", zap.Error(err))
@@ -64,6 +78,7 @@ func main() {
 
 	router.Use(middleware.RequestIDMiddleware)
 	router.Use(middleware.LoggerMiddleware)
+	router.Use(middleware.TimeoutMiddleware(config.GetRequestTimeout()))
 	// router.Use(middleware.NewLokiMiddleware(lokiClient, logger).Handler)
 
 	corsHandler := cors.New(cors.Options{
@@ -71,9 +86,9 @@ func main() {
 			"http://5.188.141.136:8008",
 			"http://localhost:8008",
 		},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"X-Authenticated", "X-CSRF-Token"},
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-CSRF-Token", "Upload-Length", "Upload-Offset", "Upload-Metadata", "Idempotency-Key"},
+		ExposedHeaders:   []string{"X-Authenticated", "X-CSRF-Token", "Location", "Upload-Length", "Upload-Offset", "Upload-Expires", "Idempotent-Replayed"},
 		AllowCredentials: true,
 	}).Handler(router)
 
@@ -104,6 +119,9 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("server forced to shutdown", zap.Error(err))
 	}
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
 
 	logger.Info("server exiting")
 }
@@ -113,6 +131,7 @@ func Init(cfg config.Config) (*mux.Router, error) {
 
 	router := mux.NewRouter()
 	router.Use(recoveryMiddleware)
+	router.Use(middleware.TracingMiddleware("app"))
 
 	metric, err := metrics.NewHTTPMetrics("app")
 	if err != nil {
@@ -150,6 +169,14 @@ func Init(cfg config.Config) (*mux.Router, error) {
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create session repository")
 	}
+	viewCounter, err := redis.NewViewCounterRepository(rdb, cfg.Advert.ViewDedupWindow, ctx, zap.L())
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create view counter repository")
+	}
+	idempotencyRepo, err := redis.NewIdempotencyRepository(rdb, cfg.Idempotency.TTL, cfg.Idempotency.LockTTL, ctx, zap.L())
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create idempotency repository")
+	}
 	userRepo, err := postgres.NewUserRepository(dbPool, ctx, cfg.PGTimeout)
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create user repository")
@@ -158,54 +185,154 @@ func Init(cfg config.Config) (*mux.Router, error) {
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create seller repository")
 	}
+	advertVideoRepo, err := postgres.NewAdvertVideoRepository(ctx, dbPool, zap.L(), cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create advert video repository")
+	}
+	analyticsRepo, err := postgres.NewAnalyticsRepository(dbPool, ctx, cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create analytics repository")
+	}
+	cartRepo, err := postgres.NewCartRepository(dbPool, ctx)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create cart repository")
+	}
+	purchaseRepo, err := postgres.NewPurchaseRepository(dbPool, ctx, cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create purchase repository")
+	}
+	paymentRepo, err := postgres.NewPaymentRepository(dbPool, ctx, cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create payment repository")
+	}
+	dealRepo, err := postgres.NewSafeDealRepository(dbPool, ctx, cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create safe deal repository")
+	}
+	deliveryRepo, err := postgres.NewDeliveryRepository(dbPool, ctx, cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create delivery repository")
+	}
+	if cfg.GuestCart.Secret == "" {
+		return nil, handleRepoError(errors.New("guest_cart.secret is not set"), "invalid guest cart config")
+	}
+	guestCartRepo, err := redis.NewGuestCartRepository(rdb, cfg.GuestCart.TTL, ctx, zap.L())
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create guest cart repository")
+	}
+	addressRepo, err := postgres.NewAddressRepository(dbPool, ctx, cfg.PGTimeout)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create address repository")
+	}
+	carrierProvider, err := newCarrierProvider(cfg.Delivery)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create carrier provider")
+	}
+	paymentGateway, paymentSimulator, err := newPaymentGateway(cfg.Payment)
+	if err != nil {
+		return nil, handleRepoError(err, "unable to create payment gateway")
+	}
+	dealAdmins, err := parseUserIDs(cfg.SafeDeal.Admins)
+	if err != nil {
+		return nil, handleRepoError(err, "invalid safe deal admins")
+	}
 	csrfToken, err := utils.NewAesCryptHashToken(zap.L())
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create csrf token")
 	}
-	authGrpcClient, err := auth.NewGrpcClient(config.GetAuthAddress())
+	authGrpcClient, err := auth.NewGrpcClient(config.GetAuthAddress(), cfg.GRPC, interceptors.RequestIDDialOptions()...)
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create grpc client")
 	}
 	
-	cartPurchaseClient, err := cart_purchase.NewCartPurchaseClient(config.GetCartPurchaseAddress())
+	cartPurchaseClient, err := cart_purchase.NewCartPurchaseClient(config.GetCartPurchaseAddress(), cfg.GRPC,
+		interceptors.RequestIDDialOptions()...)
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create cart purchase client")
 	}
-	staticClient, err := static.NewStaticGrpcClient(config.GetStaticAddress(), cfg.PGTimeout)
+	staticClient, err := static.NewStaticGrpcClient(config.GetStaticAddress(), cfg.GRPC, interceptors.RequestIDDialOptions()...)
 	if err != nil {
 		return nil, handleRepoError(err, "unable to create static client")
 	}
 
-	advertsUseCase := service.NewAdvertService(advertsRepo, sellerRepo, userRepo)
+	analyticsUseCase := service.NewAnalyticsService(analyticsRepo, advertsRepo, sellerRepo,
+		cfg.Analytics.RollupWindow, cfg.Analytics.MaxRange)
+	advertsUseCase := service.NewAdvertService(advertsRepo, sellerRepo, userRepo, viewCounter,
+		service.NewLogExpiryNotifier(), cfg.Advert.BumpCooldown, cfg.Advert.ExpiryReminder)
+	scheduler.Start(ctx,
+		scheduler.Job{Name: "expire adverts", Interval: cfg.Advert.LifecycleInterval, Run: advertsUseCase.ExpireOutdated},
+		scheduler.Job{Name: "remind expiring adverts", Interval: cfg.Advert.LifecycleInterval, Run: advertsUseCase.RemindExpiring},
+		scheduler.Job{Name: "publish scheduled adverts", Interval: cfg.Advert.PublishInterval, Run: advertsUseCase.PublishScheduled},
+		scheduler.Job{Name: "flush advert views", Interval: cfg.Advert.ViewFlushInterval, Run: advertsUseCase.FlushViews},
+		scheduler.Job{Name: "roll up advert analytics", Interval: cfg.Analytics.RollupInterval, Run: analyticsUseCase.Rollup},
+	)
+	advertVideoUseCase := service.NewAdvertVideoService(advertVideoRepo, advertsRepo, sellerRepo)
+	advertImportUseCase := service.NewAdvertImportService(advertsRepo, sellerRepo, categoryRepo)
 	categoryUseCase := service.NewCategoryService(categoryRepo)
+	paymentUseCase := service.NewPaymentService(paymentRepo, purchaseRepo, cartRepo, advertsRepo, dealRepo, deliveryRepo, paymentGateway,
+		cfg.Payment.Currency, cfg.Payment.ReturnURL, cfg.Payment.AutoCapture)
+	dealUseCase := service.NewSafeDealService(dealRepo, purchaseRepo, cartRepo, advertsRepo, paymentUseCase,
+		cfg.SafeDeal.ShipTimeout, cfg.SafeDeal.ConfirmTimeout, cfg.SafeDeal.SettleTimeout, cfg.SafeDeal.BatchSize, dealAdmins)
+	deliveryUseCase := service.NewDeliveryService(deliveryRepo, purchaseRepo, cartRepo, advertsRepo, sellerRepo, carrierProvider,
+		cfg.Delivery.BatchSize)
+	addressUseCase := service.NewAddressService(addressRepo)
+	guestCartUseCase := service.NewGuestCartService(guestCartRepo, advertsRepo, cfg.GuestCart.MaxAdverts)
+	scheduler.Start(ctx,
+		scheduler.Job{Name: "process expired safe deals", Interval: cfg.SafeDeal.Interval, Run: dealUseCase.ProcessExpired},
+		scheduler.Job{Name: "refresh shipment tracking", Interval: cfg.Delivery.TrackInterval, Run: deliveryUseCase.RefreshTracking},
+	)
 	userUC := service.NewUserService(userRepo, sellerRepo)
 	sessionUC := service.NewAuthService(sessionRepo)
 	sessionManager := utils.NewSessionManager(authGrpcClient, int(cfg.Session.ExpirationTime.Seconds()), cfg.Session.SecureCookie, logger)
 	router.Use(middleware.NewAuthMiddleware(sessionManager).AuthMiddleware)
+	idempotency := middleware.NewIdempotencyMiddleware(idempotencyRepo, sessionManager.GetUserID)
 
-	advertsHandler := http3.NewAdvertEndpoint(advertsUseCase, *staticClient, sessionManager, policy)
+	advertsHandler := http3.NewAdvertEndpoint(advertsUseCase, *staticClient, sessionManager, idempotency, policy)
+	advertVideoHandler := http3.NewAdvertVideoEndpoint(advertVideoUseCase, *staticClient, sessionManager)
+	advertImportHandler := http3.NewAdvertImportEndpoint(advertImportUseCase, sessionManager, policy)
+	analyticsHandler := http3.NewAnalyticsEndpoint(analyticsUseCase, sessionManager)
 	authHandler := http3.NewAuthEndpoint(sessionUC, sessionManager)
-	userHandler := http3.NewUserEndpoint(userUC, sessionUC, sessionManager, *staticClient, policy)
+	guestCartHandler := http3.NewGuestCartEndpoint(guestCartUseCase, cartPurchaseClient,
+		utils.NewGuestCartCookie(cfg.GuestCart.Secret, cfg.GuestCart.TTL, cfg.Session.SecureCookie), sessionManager)
+	userHandler := http3.NewUserEndpoint(userUC, sessionUC, sessionManager, *staticClient, policy, guestCartHandler)
 	sellerHandler := http3.NewSellerEndpoint(sellerRepo)
-	purchaseHandler := http3.NewPurchaseEndpoint(cartPurchaseClient)
-	cartHandler := http3.NewCartEndpoint(cartPurchaseClient)
+	purchaseHandler := http3.NewPurchaseEndpoint(cartPurchaseClient, paymentUseCase, dealUseCase, deliveryUseCase, addressUseCase,
+		sessionManager, idempotency)
+	dealHandler := http3.NewDealEndpoint(dealUseCase, sessionManager)
+	deliveryHandler := http3.NewDeliveryEndpoint(deliveryUseCase, sessionManager)
+	addressHandler := http3.NewAddressEndpoint(addressUseCase, sessionManager)
+	paymentHandler := http3.NewPaymentEndpoint(paymentUseCase, sessionManager, paymentSimulator)
+	cartHandler := http3.NewCartEndpoint(cartPurchaseClient, sessionManager, idempotency)
 	categoryHandler := http3.NewCategoryEndpoint(categoryUseCase)
 	staticHandler := http3.NewStaticEndpoint(*staticClient)
+	uploadHandler := http3.NewUploadEndpoint(*staticClient, sessionManager)
 
 	csrfEndpoints := http3.NewCSRFEndpoint(csrfToken, sessionManager)
 	csrfEndpoints.Configure(router)
 	userHandler.ConfigureUnprotectedRoutes(router)
 	advertsHandler.ConfigureRoutes(router)
+	advertVideoHandler.ConfigureRoutes(router)
+	paymentHandler.ConfigureRoutes(router)
+	deliveryHandler.ConfigureRoutes(router)
+	guestCartHandler.ConfigureRoutes(router)
 
 	authRouter.Use(middleware.CSRFMiddleware(csrfToken, sessionManager))
 
 	advertsHandler.ConfigureProtectedRoutes(authRouter)
+	advertVideoHandler.ConfigureProtectedRoutes(authRouter)
+	advertImportHandler.ConfigureProtectedRoutes(authRouter)
+	analyticsHandler.ConfigureProtectedRoutes(authRouter)
 	categoryHandler.ConfigureRoutes(authRouter)
 	authHandler.Configure(authRouter)
 	userHandler.ConfigureProtectedRoutes(authRouter)
 	sellerHandler.Configure(authRouter)
-	cartHandler.Configure(authRouter)
-	purchaseHandler.ConfigureRoutes(authRouter)
+	cartHandler.ConfigureProtectedRoutes(authRouter)
+	purchaseHandler.ConfigureProtectedRoutes(authRouter)
+	paymentHandler.ConfigureProtectedRoutes(authRouter)
+	dealHandler.ConfigureProtectedRoutes(authRouter)
+	deliveryHandler.ConfigureProtectedRoutes(authRouter)
+	addressHandler.ConfigureProtectedRoutes(authRouter)
+	uploadHandler.ConfigureProtectedRoutes(authRouter)
 	staticHandler.ConfigureRoutes(router)
 	router.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
 	router.PathPrefix("/api/v1/metrics").Handler(promhttp.Handler())
@@ -213,6 +340,49 @@ func Init(cfg config.Config) (*mux.Router, error) {
 	return router, nil
 }
 
+// newPaymentGateway выбирает платежного провайдера по payment.provider. Локальный шлюз
+// с имитацией оплаты запускается, только если она явно включена payment.simulator_enabled
+func newPaymentGateway(cfg config.PaymentConfig) (repository.PaymentGateway, http3.PaymentSimulator, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil, fmt.Errorf("payment provider is not set")
+	case config.PaymentProviderFake:
+		if !cfg.SimulatorEnabled {
+			return nil, nil, fmt.Errorf("payment provider %q requires payment.simulator_enabled", cfg.Provider)
+		}
+		gateway := paymentgateway.NewFakeGateway(cfg.WebhookSecret)
+		return gateway, gateway, nil
+	case config.PaymentProviderYooKassa:
+		return paymentgateway.NewYooKassaGateway(cfg.YooKassa.APIURL, cfg.YooKassa.ShopID, cfg.YooKassa.SecretKey,
+			cfg.WebhookSecret, cfg.YooKassa.Timeout, zap.L()), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown payment provider %q", cfg.Provider)
+	}
+}
+
+// newCarrierProvider выбирает перевозчика по delivery.carrier
+func newCarrierProvider(cfg config.DeliveryConfig) (repository.CarrierProvider, error) {
+	switch cfg.Carrier {
+	case config.CarrierFake, "":
+		return carrier.NewFakeCarrier(cfg.FakeStep), nil
+	default:
+		return nil, fmt.Errorf("unknown carrier %q", cfg.Carrier)
+	}
+}
+
+// parseUserIDs разбирает идентификаторы пользователей из конфигурации
+func parseUserIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid user id %q: %w", value, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func handleRepoError(err error, message string) error {
 	zap.L().Error(message, zap.Error(err))
 	return errors.Wrap(err, message)
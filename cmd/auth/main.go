@@ -12,6 +12,7 @@ import (
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/metrics"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/interceptors"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
@@ -29,6 +30,12 @@ func main() {
 		zap.L().Fatal("Error initializing configuration", zap.Error(err))
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "auth", cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("Error initializing tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	rdb, err := connector.GetRedisConnector(cfg.RdAddr, cfg.RdPass, cfg.RdDB)
 	if err != nil {
 		zap.L().Fatal("Error connecting to Redis", zap.Error(err))
@@ -47,14 +54,22 @@ func main() {
 	}
 
 	metricsInterceptor := interceptors.NewMetricsInterceptor(*metrics)
+	creds, err := connector.GetGrpcServerCredentials(cfg.GRPC.TLS)
+	if err != nil {
+		zap.L().Fatal("Error loading grpc credentials", zap.Error(err))
+	}
+
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(metricsInterceptor.NewMetricsInterceptor),
+		creds,
+		tracing.GRPCServerOption(),
+		grpc.ChainUnaryInterceptor(interceptors.RequestIDUnaryInterceptor, metricsInterceptor.NewMetricsInterceptor),
 	)
 	authServer := auth.NewGrpcServer(authService)
 
 	healthServer := health.NewServer()
 	healthProto.RegisterHealthServer(server, healthServer)
 	healthServer.SetServingStatus("", healthProto.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(authProto.AuthService_ServiceDesc.ServiceName, healthProto.HealthCheckResponse_SERVING)
 
 	authProto.RegisterAuthServiceServer(server, authServer)
 
@@ -13,6 +13,7 @@ import (
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/metrics"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/interceptors"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
 	"go.uber.org/zap"
 	"net/http"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -30,6 +31,12 @@ func main() {
 		zap.L().Fatal("Ошибка при инициализации конфигурации", zap.Error(err))
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "cart_purchase", cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("Ошибка при инициализации трассировки", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	dbPool, err := connector.GetPostgresConnector(cfg.GetConnectURL())
 	if err != nil {
 		zap.L().Error("Failed to connect to Postgres", zap.Error(err))
@@ -57,8 +64,16 @@ func main() {
 		zap.L().Fatal("Ошибка при инициализации метрик", zap.Error(err))
 	}
 
+	creds, err := connector.GetGrpcServerCredentials(cfg.GRPC.TLS)
+	if err != nil {
+		zap.L().Fatal("Failed to load grpc credentials", zap.Error(err))
+	}
+
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptors.NewMetricsInterceptor(*metrics).NewMetricsInterceptor),
+		creds,
+		tracing.GRPCServerOption(),
+		grpc.ChainUnaryInterceptor(interceptors.RequestIDUnaryInterceptor,
+			interceptors.NewMetricsInterceptor(*metrics).NewMetricsInterceptor),
 	)
 	cartUC := service.NewCartService(cartRepo, advertRepo)
 	purchaseUC := service.NewPurchaseService(purchaseRepo, advertRepo, cartRepo)
@@ -67,6 +82,7 @@ func main() {
 	healthServer := health.NewServer()
 	healthProto.RegisterHealthServer(server, healthServer)
 	healthServer.SetServingStatus("", healthProto.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(cartPurchaseProto.CartPurchaseService_ServiceDesc.ServiceName, healthProto.HealthCheckResponse_SERVING)
 
 	cartPurchaseProto.RegisterCartPurchaseServiceServer(server, cartPurchaseServer)
 	address := config.GetCartPurchaseAddress()
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"go.uber.org/zap"
 	"net"
 	"os/signal"
@@ -9,10 +10,17 @@ import (
 	"strconv"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/scheduler"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/blobstore"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/postgres"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase/service"
 	staticProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthProto "google.golang.org/grpc/health/grpc_health_v1"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/metrics"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/interceptors"
@@ -31,28 +39,66 @@ func main() {
 		zap.L().Error("Failed to init config", zap.Error(err))
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), "static", cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("Failed to init tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	dbPool, err := connector.GetPostgresConnector(cfg.GetConnectURL())
 	if err != nil {
 		zap.L().Error("Failed to connect to Postgres", zap.Error(err))
 	}
 
-	staticRepo, err := postgres.NewStaticRepository(context.Background(), dbPool, cfg.Static.Path, cfg.Static.MaxSize, zap.L(), cfg.PGTimeout)
+	blobStore, err := newBlobStore(cfg.Static)
+	if err != nil {
+		zap.L().Fatal("Failed to create blob store", zap.String("storage", cfg.Static.Storage), zap.Error(err))
+	}
+
+	staticRepo, err := postgres.NewStaticRepository(context.Background(), dbPool, blobStore, cfg.Static.Path, cfg.Static.MaxSize, zap.L(), cfg.PGTimeout)
 	if err != nil {
 		zap.L().Error("Failed to create static repository", zap.Error(err))
 	}
 
-	staticUseCase := service.NewStaticService(staticRepo)
+	inspectors, err := newImageInspectors(cfg.Static.Moderation)
+	if err != nil {
+		zap.L().Fatal("Failed to create image inspectors", zap.Error(err))
+	}
+
+	staticUseCase := service.NewStaticService(staticRepo, blobStore, cfg.Static.Path,
+		cfg.Static.GCGracePeriod, cfg.Static.GCBatchSize, newVideoProcessor(cfg.Static.Video), inspectors...)
+
+	uploadRepo, err := postgres.NewStaticUploadRepository(context.Background(), dbPool, blobStore, zap.L(), cfg.PGTimeout)
+	if err != nil {
+		zap.L().Error("Failed to create static upload repository", zap.Error(err))
+	}
+
+	uploadUseCase := service.NewStaticUploadService(uploadRepo, staticRepo, staticUseCase,
+		cfg.Static.UploadTTL, cfg.Static.UploadChunkSize, cfg.Static.GCBatchSize)
 
 	metrics, err := metrics.NewGRPCMetrics("static")
 	if err != nil {
 		zap.L().Fatal("Ошибка при инициализации метрик", zap.Error(err))
 	}
 
-	staticService := static.NewStaticGrpc(staticUseCase)
+	staticService := static.NewStaticGrpc(staticUseCase, uploadUseCase)
+	creds, err := connector.GetGrpcServerCredentials(cfg.GRPC.TLS)
+	if err != nil {
+		zap.L().Fatal("Failed to load grpc credentials", zap.Error(err))
+	}
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptors.NewMetricsInterceptor(*metrics).NewMetricsInterceptor),
+		creds,
+		tracing.GRPCServerOption(),
+		grpc.ChainUnaryInterceptor(interceptors.RequestIDUnaryInterceptor,
+			interceptors.NewMetricsInterceptor(*metrics).NewMetricsInterceptor),
+		grpc.ChainStreamInterceptor(interceptors.RequestIDStreamInterceptor),
 	)
 	staticProto.RegisterStaticServiceServer(server, staticService)
+
+	healthServer := health.NewServer()
+	healthProto.RegisterHealthServer(server, healthServer)
+	healthServer.SetServingStatus("", healthProto.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(staticProto.StaticService_ServiceDesc.ServiceName, healthProto.HealthCheckResponse_SERVING)
 	addr := cfg.StaticHost + ":" + strconv.Itoa(cfg.StaticPort)
 
 	http.Handle("/api/v1/metrics", promhttp.Handler())
@@ -70,6 +116,10 @@ func main() {
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGKILL)
 	defer stop()
+	scheduler.Start(ctx,
+		scheduler.Job{Name: "collect unreferenced static", Interval: cfg.Static.GCInterval, Run: staticUseCase.CollectGarbage},
+		scheduler.Job{Name: "expire abandoned uploads", Interval: cfg.Static.GCInterval, Run: uploadUseCase.ExpireUploads},
+	)
 	go func() {
 		err := server.Serve(lis)
 		if err != nil {
@@ -77,5 +127,61 @@ func main() {
 		}
 	}()
 	<-ctx.Done()
+	// клиенты перестают направлять сюда вызовы, пока сервер завершает текущие
+	healthServer.Shutdown()
 	server.GracefulStop()
 }
+
+// newBlobStore выбирает хранилище файлов статики по static.storage
+func newBlobStore(cfg config.StaticConfig) (repository.BlobStore, error) {
+	switch cfg.Storage {
+	case config.StaticStorageLocal, "":
+		return blobstore.NewLocalStore(cfg.Path, zap.L())
+	case config.StaticStorageS3:
+		client, err := connector.GetS3Connector(cfg.S3.Endpoint, cfg.S3.AccessKey, cfg.S3.SecretKey, cfg.S3.Region, cfg.S3.UseSSL)
+		if err != nil {
+			return nil, err
+		}
+		return blobstore.NewS3Store(context.Background(), client, cfg.S3.Bucket, cfg.Timeout, zap.L())
+	default:
+		return nil, fmt.Errorf("unknown static storage %q", cfg.Storage)
+	}
+}
+
+// newImageInspectors создает проверки загружаемых изображений по static.moderation
+func newImageInspectors(cfg config.ModerationConfig) ([]usecase.ImageInspector, error) {
+	var inspectors []usecase.ImageInspector
+	if len(cfg.BannedHashes) > 0 {
+		banned, err := service.ParseImageHashes(cfg.BannedHashes)
+		if err != nil {
+			return nil, err
+		}
+		inspectors = append(inspectors, service.NewPerceptualHashInspector(banned, cfg.MaxHashDistance))
+	}
+	if cfg.NSFW.Enabled {
+		scorer, err := newNSFWScorer(cfg.NSFW.Scorer)
+		if err != nil {
+			return nil, err
+		}
+		inspectors = append(inspectors, service.NewNSFWInspector(scorer, cfg.NSFW.FlagScore, cfg.NSFW.RejectScore))
+	}
+	return inspectors, nil
+}
+
+// newNSFWScorer выбирает оценку откровенности по static.moderation.nsfw.scorer
+func newNSFWScorer(name string) (usecase.NSFWScorer, error) {
+	switch name {
+	case config.NSFWScorerSkinTone, "":
+		return service.NewSkinToneScorer(), nil
+	default:
+		return nil, fmt.Errorf("unknown nsfw scorer %q", name)
+	}
+}
+
+// newVideoProcessor возвращает обработчик видео или nil, если загрузка видео отключена
+func newVideoProcessor(cfg config.VideoConfig) usecase.VideoProcessor {
+	if !cfg.Enabled {
+		return nil
+	}
+	return service.NewFFmpegVideoProcessor(cfg.FFmpegPath, cfg.FFprobePath, cfg.Timeout)
+}
@@ -4,18 +4,23 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
+// ServerConfig - HTTP-сервер шлюза. RequestTimeout - дедлайн обработки запроса, который
+// передается во все слои и в вызовы сервисов; он меньше WriteTimeout, чтобы шлюз успел
+// ответить ошибкой
 type ServerConfig struct {
 	IP              string        `yaml:"ip" default:"0.0.0.0"`
 	Port            int           `yaml:"port" default:"8080"`
 	ReadTimeout     time.Duration `yaml:"read_timeout" default:"10s"`
 	WriteTimeout    time.Duration `yaml:"write_timeout" default:"10s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" default:"10s"`
+	RequestTimeout  time.Duration `yaml:"request_timeout" default:"8s"`
 }
 
 type SessionConfig struct {
@@ -23,27 +28,147 @@ type SessionConfig struct {
 	SecureCookie   bool          `yaml:"secure_cookie" default:"false"`
 }
 
+type AdvertConfig struct {
+	BumpCooldown      time.Duration `yaml:"bump_cooldown" default:"24h"`
+	ExpiryReminder    time.Duration `yaml:"expiry_reminder" default:"72h"`
+	LifecycleInterval time.Duration `yaml:"lifecycle_interval" default:"10m"`
+	PublishInterval   time.Duration `yaml:"publish_interval" default:"1m"`
+	ViewDedupWindow   time.Duration `yaml:"view_dedup_window" default:"30m"`
+	ViewFlushInterval time.Duration `yaml:"view_flush_interval" default:"1m"`
+}
+
+type AnalyticsConfig struct {
+	RollupInterval time.Duration `yaml:"rollup_interval" default:"15m"`
+	RollupWindow   time.Duration `yaml:"rollup_window" default:"48h"`
+	MaxRange       time.Duration `yaml:"max_range" default:"2208h"`
+}
+
 type Config struct {
-	Server           ServerConfig  `yaml:"server"`
-	Session          SessionConfig `yaml:"session"`
-	PGIP             string        `yaml:"pg_ip"`
-	PGPort           int           `yaml:"pg_port"`
-	PGUser           string        `yaml:"pg_user"`
-	PGPass           string        `yaml:"pg_password"`
-	PGTimeout        time.Duration `yaml:"pg_timeout" default:"5s"`
-	PGDB             string        `yaml:"pg_db"`
-	RdAddr           string        `yaml:"rd_addr"`
-	RdPass           string        `yaml:"rd_password"`
-	RdDB             int           `yaml:"rd_db"`
-	Static           StaticConfig  `yaml:"static"`
-	CSRFSecret       string        `yaml:"csrf_secret"`
-	AuthPort         int           `yaml:"auth_port"`
-	AuthHost         string        `yaml:"auth_host"`
-	CartPurchaseHost string        `yaml:"cart_purchase_host"`
-	CartPurchasePort int           `yaml:"cart_purchase_port"`
-	StaticHost       string        `yaml:"static_host"`
-	StaticPort       int           `yaml:"static_port"`
-	SearchBatchSize  int           `yaml:"search_batch_size"`
+	Server           ServerConfig      `yaml:"server"`
+	Session          SessionConfig     `yaml:"session"`
+	PGIP             string            `yaml:"pg_ip"`
+	PGPort           int               `yaml:"pg_port"`
+	PGUser           string            `yaml:"pg_user"`
+	PGPass           string            `yaml:"pg_password"`
+	PGTimeout        time.Duration     `yaml:"pg_timeout" default:"5s"`
+	PGDB             string            `yaml:"pg_db"`
+	RdAddr           string            `yaml:"rd_addr"`
+	RdPass           string            `yaml:"rd_password"`
+	RdDB             int               `yaml:"rd_db"`
+	Static           StaticConfig      `yaml:"static"`
+	CSRFSecret       string            `yaml:"csrf_secret"`
+	AuthPort         int               `yaml:"auth_port"`
+	AuthHost         string            `yaml:"auth_host"`
+	CartPurchaseHost string            `yaml:"cart_purchase_host"`
+	CartPurchasePort int               `yaml:"cart_purchase_port"`
+	StaticHost       string            `yaml:"static_host"`
+	StaticPort       int               `yaml:"static_port"`
+	SearchBatchSize  int               `yaml:"search_batch_size"`
+	Advert           AdvertConfig      `yaml:"advert"`
+	Analytics        AnalyticsConfig   `yaml:"analytics"`
+	GRPC             GRPCConfig        `yaml:"grpc"`
+	Tracing          TracingConfig     `yaml:"tracing"`
+	Idempotency      IdempotencyConfig `yaml:"idempotency"`
+	Payment          PaymentConfig     `yaml:"payment"`
+	SafeDeal         SafeDealConfig    `yaml:"safe_deal"`
+	Delivery         DeliveryConfig    `yaml:"delivery"`
+	GuestCart        GuestCartConfig   `yaml:"guest_cart"`
+}
+
+// PaymentConfig - оплата покупок картой. Provider: yookassa - платежи ЮKassa, fake - локальный
+// шлюз без внешних запросов для разработки и тестов. Провайдер обязателен, а fake включается
+// только вместе с SimulatorEnabled, чтобы имитацию оплаты нельзя было включить по ошибке.
+// Уведомления о платежах подписываются WebhookSecret; если AutoCapture = false,
+// заблокированные на карте средства списываются отдельным запросом
+type PaymentConfig struct {
+	Provider         string         `yaml:"provider"`
+	SimulatorEnabled bool           `yaml:"simulator_enabled" default:"false"`
+	Currency         string         `yaml:"currency"          default:"RUB"`
+	ReturnURL        string         `yaml:"return_url"`
+	WebhookSecret    string         `yaml:"webhook_secret"`
+	AutoCapture      bool           `yaml:"auto_capture"      default:"true"`
+	YooKassa         YooKassaConfig `yaml:"yookassa"`
+}
+
+type YooKassaConfig struct {
+	APIURL    string        `yaml:"api_url"    default:"https://api.yookassa.ru/v3"`
+	ShopID    string        `yaml:"shop_id"`
+	SecretKey string        `yaml:"secret_key"`
+	Timeout   time.Duration `yaml:"timeout"    default:"10s"`
+}
+
+// SafeDealConfig - безопасные сделки. Если продавец не отправил товар за ShipTimeout после
+// блокировки средств, они возвращаются покупателю; если покупатель не подтвердил получение
+// за ConfirmTimeout после отправки, средства списываются в пользу продавца. Расчет, который
+// не завершился за SettleTimeout, повторяется. Сроки проверяются раз в Interval пачками
+// по BatchSize. Admins - пользователи, которые разрешают споры
+type SafeDealConfig struct {
+	ShipTimeout    time.Duration `yaml:"ship_timeout"    default:"72h"`
+	ConfirmTimeout time.Duration `yaml:"confirm_timeout" default:"168h"`
+	SettleTimeout  time.Duration `yaml:"settle_timeout"  default:"10m"`
+	Interval       time.Duration `yaml:"interval"        default:"1m"`
+	BatchSize      int           `yaml:"batch_size"      default:"100"`
+	Admins         []string      `yaml:"admins"`
+}
+
+// DeliveryConfig - отслеживание отправлений. Carrier: fake - локальный перевозчик, который
+// проводит отправление по этапам доставки через каждые FakeStep. Статусы отправлений
+// запрашиваются раз в TrackInterval пачками по BatchSize
+type DeliveryConfig struct {
+	Carrier       string        `yaml:"carrier"        default:"fake"`
+	TrackInterval time.Duration `yaml:"track_interval" default:"5m"`
+	BatchSize     int           `yaml:"batch_size"     default:"100"`
+	FakeStep      time.Duration `yaml:"fake_step"      default:"1h"`
+}
+
+// GuestCartConfig - корзины посетителей без входа. Корзина хранится TTL с последнего изменения
+// и вмещает не больше MaxAdverts объявлений; cookie с ее идентификатором подписывается Secret.
+// При входе или регистрации корзина переносится в корзину пользователя
+type GuestCartConfig struct {
+	TTL        time.Duration `yaml:"ttl"         default:"720h"`
+	MaxAdverts int           `yaml:"max_adverts" default:"50"`
+	Secret     string        `yaml:"secret"`
+}
+
+// IdempotencyConfig - хранение ответов на запросы с заголовком Idempotency-Key. TTL - сколько
+// повтор получает сохраненный ответ, LockTTL - сколько ключ остается занятым запросом, который
+// не завершился; LockTTL должен быть больше server.request_timeout
+type IdempotencyConfig struct {
+	TTL     time.Duration `yaml:"ttl"      default:"24h"`
+	LockTTL time.Duration `yaml:"lock_ttl" default:"1m"`
+}
+
+// TracingConfig - экспорт трасс OpenTelemetry. Exporter: otlp - по gRPC на Endpoint,
+// stdout - в вывод процесса, none - трассы не собираются. SampleRatio - доля сохраняемых
+// трасс запросов, пришедших без решения вызывающей стороны
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"     default:"none"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio" default:"1"`
+}
+
+// GRPCConfig - соединения между сервисами. Timeout ограничивает вызов, если у контекста
+// запроса нет своего дедлайна. Идемпотентные вызовы повторяются до RetryAttempts раз,
+// пока сервис недоступен; при старте клиент ждет ответа SERVING от проверки здоровья
+// не дольше HealthCheckTimeout
+type GRPCConfig struct {
+	Timeout            time.Duration `yaml:"timeout"`
+	RetryAttempts      int           `yaml:"retry_attempts"`
+	RetryBackoff       time.Duration `yaml:"retry_backoff"`
+	RetryMaxBackoff    time.Duration `yaml:"retry_max_backoff"`
+	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
+	TLS                GRPCTLSConfig `yaml:"tls"`
+}
+
+// GRPCTLSConfig - взаимная TLS-аутентификация сервисов. Сертификаты сервера и клиента
+// должны быть подписаны центром CAFile; ServerName переопределяет имя сервера при проверке
+type GRPCTLSConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
 }
 
 type StaticConfig struct {
@@ -52,6 +177,79 @@ type StaticConfig struct {
 	Path    string        `yaml:"path"`
 	MaxSize int           `yaml:"max_size"`
 	Timeout time.Duration `yaml:"timeout"`
+	Storage string        `yaml:"storage"       default:"local"`
+	S3      S3Config      `yaml:"s3"`
+	// GCInterval - период сборки статики без ссылок, GCGracePeriod - сколько хранится
+	// статика, еще не привязанная к пользователю или объявлению
+	GCInterval    time.Duration `yaml:"gc_interval"`
+	GCGracePeriod time.Duration `yaml:"gc_grace_period"`
+	GCBatchSize   int           `yaml:"gc_batch_size"`
+	// UploadTTL - сколько хранится загрузка по частям без новых частей,
+	// UploadChunkSize - максимальный размер одной части
+	UploadTTL       time.Duration    `yaml:"upload_ttl"`
+	UploadChunkSize int64            `yaml:"upload_chunk_size"`
+	Moderation      ModerationConfig `yaml:"moderation"`
+	Video           VideoConfig      `yaml:"video"`
+}
+
+// VideoConfig - обработка загружаемых видео. Если Enabled = false, видео не принимаются;
+// Timeout ограничивает каждый запуск ffmpeg и ffprobe
+type VideoConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	FFmpegPath  string        `yaml:"ffmpeg_path"`
+	FFprobePath string        `yaml:"ffprobe_path"`
+	Timeout     time.Duration `yaml:"timeout"`
+}
+
+// ModerationConfig - проверки загружаемых изображений. BannedHashes - хэши запрещенных
+// изображений в шестнадцатеричной записи; похожими считаются изображения, хэши которых
+// отличаются не больше чем на MaxHashDistance бит
+type ModerationConfig struct {
+	BannedHashes    []string   `yaml:"banned_hashes"`
+	MaxHashDistance int        `yaml:"max_hash_distance"`
+	NSFW            NSFWConfig `yaml:"nsfw"`
+}
+
+// NSFWConfig - оценка откровенности изображений. Изображения с оценкой не ниже FlagScore
+// не показываются до ручной проверки, с оценкой не ниже RejectScore - не принимаются
+type NSFWConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Scorer      string  `yaml:"scorer"`
+	FlagScore   float64 `yaml:"flag_score"`
+	RejectScore float64 `yaml:"reject_score"`
+}
+
+const (
+	StaticStorageLocal = "local"
+	StaticStorageS3    = "s3"
+)
+
+const (
+	NSFWScorerSkinTone = "skin_tone"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+)
+
+const (
+	PaymentProviderFake     = "fake"
+	PaymentProviderYooKassa = "yookassa"
+)
+
+const (
+	CarrierFake = "fake"
+)
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	UseSSL    bool   `yaml:"use_ssl"`
 }
 
 var cfg Config
@@ -105,6 +303,57 @@ func Init() (Config, error) {
 	if maxSize := os.Getenv("STATIC_MAX_SIZE"); maxSize != "" {
 		cfg.Static.MaxSize, _ = strconv.Atoi(maxSize)
 	}
+	if storage := os.Getenv("STATIC_STORAGE"); storage != "" {
+		cfg.Static.Storage = storage
+	}
+	if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+		cfg.Static.S3.Endpoint = endpoint
+	}
+	if accessKey := os.Getenv("S3_ACCESS_KEY"); accessKey != "" {
+		cfg.Static.S3.AccessKey = accessKey
+	}
+	if secretKey := os.Getenv("S3_SECRET_KEY"); secretKey != "" {
+		cfg.Static.S3.SecretKey = secretKey
+	}
+	if enabled := os.Getenv("STATIC_NSFW_ENABLED"); enabled != "" {
+		cfg.Static.Moderation.NSFW.Enabled, _ = strconv.ParseBool(enabled)
+	}
+
+	if enabled := os.Getenv("GRPC_TLS_ENABLED"); enabled != "" {
+		cfg.GRPC.TLS.Enabled, _ = strconv.ParseBool(enabled)
+	}
+
+	if exporter := os.Getenv("TRACING_EXPORTER"); exporter != "" {
+		cfg.Tracing.Exporter = exporter
+	}
+	if endpoint := os.Getenv("TRACING_ENDPOINT"); endpoint != "" {
+		cfg.Tracing.Endpoint = endpoint
+	}
+
+	if provider := os.Getenv("PAYMENT_PROVIDER"); provider != "" {
+		cfg.Payment.Provider = provider
+	}
+	if enabled := os.Getenv("PAYMENT_SIMULATOR_ENABLED"); enabled != "" {
+		cfg.Payment.SimulatorEnabled, _ = strconv.ParseBool(enabled)
+	}
+	if secret := os.Getenv("PAYMENT_WEBHOOK_SECRET"); secret != "" {
+		cfg.Payment.WebhookSecret = secret
+	}
+	if shopID := os.Getenv("YOOKASSA_SHOP_ID"); shopID != "" {
+		cfg.Payment.YooKassa.ShopID = shopID
+	}
+	if secretKey := os.Getenv("YOOKASSA_SECRET_KEY"); secretKey != "" {
+		cfg.Payment.YooKassa.SecretKey = secretKey
+	}
+	if admins := os.Getenv("SAFE_DEAL_ADMINS"); admins != "" {
+		cfg.SafeDeal.Admins = strings.Split(admins, ",")
+	}
+	if carrier := os.Getenv("DELIVERY_CARRIER"); carrier != "" {
+		cfg.Delivery.Carrier = carrier
+	}
+	if secret := os.Getenv("GUEST_CART_SECRET"); secret != "" {
+		cfg.GuestCart.Secret = secret
+	}
 
 	return cfg, nil
 }
@@ -147,6 +396,10 @@ func GetWriteTimeout() time.Duration {
 	return cfg.Server.WriteTimeout
 }
 
+func GetRequestTimeout() time.Duration {
+	return cfg.Server.RequestTimeout
+}
+
 func GetShutdownTimeout() time.Duration {
 	return cfg.Server.ShutdownTimeout
 }
@@ -159,6 +412,14 @@ func GetStaticConfig() StaticConfig {
 	return cfg.Static
 }
 
+func GetGRPCConfig() GRPCConfig {
+	return cfg.GRPC
+}
+
+func GetTracingConfig() TracingConfig {
+	return cfg.Tracing
+}
+
 func GetCSRFSecret() string {
 	return cfg.CSRFSecret
 }
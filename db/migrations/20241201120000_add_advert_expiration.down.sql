@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_advert_bumped_at;
+DROP INDEX IF EXISTS idx_advert_expires_at;
+
+ALTER TABLE advert
+    DROP COLUMN IF EXISTS expiry_reminded_at,
+    DROP COLUMN IF EXISTS bumped_at,
+    DROP COLUMN IF EXISTS expires_at;
+
+ALTER TABLE category
+    DROP COLUMN IF EXISTS advert_lifetime;
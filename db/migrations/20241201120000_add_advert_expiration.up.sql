@@ -0,0 +1,24 @@
+-- Время жизни объявлений задается на уровне категории
+ALTER TABLE category
+    ADD COLUMN IF NOT EXISTS advert_lifetime INTERVAL DEFAULT INTERVAL '30 days' NOT NULL;
+
+-- Срок публикации объявления и время последнего поднятия в ленте
+ALTER TABLE advert
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP DEFAULT (CURRENT_TIMESTAMP + INTERVAL '30 days'),
+    ADD COLUMN IF NOT EXISTS bumped_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS expiry_reminded_at TIMESTAMP NULL;
+
+-- Уже опубликованные объявления получают полный срок с момента миграции
+UPDATE advert a
+SET bumped_at = a.created_at,
+    expires_at = CURRENT_TIMESTAMP + COALESCE(
+        (SELECT c.advert_lifetime FROM category c WHERE c.id = a.category_id),
+        INTERVAL '30 days'
+    );
+
+ALTER TABLE advert
+    ALTER COLUMN expires_at SET NOT NULL,
+    ALTER COLUMN bumped_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_advert_expires_at ON advert (expires_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_advert_bumped_at ON advert (bumped_at DESC);
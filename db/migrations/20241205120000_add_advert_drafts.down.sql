@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_advert_publish_at;
+
+ALTER TABLE advert
+    DROP COLUMN IF EXISTS publish_at;
+
+-- Значения enum нельзя удалить, поэтому черновики просто снимаются с публикации
+UPDATE advert SET status = 'inactive' WHERE status::text IN ('draft', 'scheduled');
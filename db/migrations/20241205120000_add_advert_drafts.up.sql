@@ -0,0 +1,8 @@
+-- Черновики и отложенная публикация объявлений
+ALTER TYPE advert_status ADD VALUE IF NOT EXISTS 'draft';
+ALTER TYPE advert_status ADD VALUE IF NOT EXISTS 'scheduled';
+
+ALTER TABLE advert
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_advert_publish_at ON advert (publish_at) WHERE publish_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_advert_seller_external_sku;
+
+ALTER TABLE advert
+    DROP COLUMN IF EXISTS external_sku;
@@ -0,0 +1,7 @@
+-- Артикул продавца для массового импорта объявлений
+ALTER TABLE advert
+    ADD COLUMN IF NOT EXISTS external_sku TEXT NULL
+        CONSTRAINT advert_external_sku_length CHECK (LENGTH(external_sku) <= 100);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_advert_seller_external_sku
+    ON advert (seller_id, external_sku) WHERE external_sku IS NOT NULL;
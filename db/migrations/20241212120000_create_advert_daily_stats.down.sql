@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_cart_advert_created_at;
+DROP INDEX IF EXISTS idx_saved_advert_created_at;
+DROP INDEX IF EXISTS idx_viewed_advert_created_at;
+
+DROP TABLE IF EXISTS category_daily_stats;
+DROP TABLE IF EXISTS advert_daily_stats;
+
+ALTER TABLE cart_advert
+    DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,34 @@
+-- Время добавления объявления в корзину нужно для дневной статистики
+ALTER TABLE cart_advert
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+-- Дневные агрегаты по объявлениям, пересчитываются фоновой задачей
+CREATE TABLE IF NOT EXISTS advert_daily_stats (
+    advert_id UUID NOT NULL,
+    day DATE NOT NULL,
+    views INTEGER DEFAULT 0 NOT NULL,
+    unique_viewers INTEGER DEFAULT 0 NOT NULL,
+    saves INTEGER DEFAULT 0 NOT NULL,
+    cart_additions INTEGER DEFAULT 0 NOT NULL,
+    purchases INTEGER DEFAULT 0 NOT NULL,
+    PRIMARY KEY (advert_id, day),
+    FOREIGN KEY (advert_id) REFERENCES advert(id) ON DELETE CASCADE
+);
+
+-- Дневные агрегаты по категориям для сравнения объявления со средним по категории
+CREATE TABLE IF NOT EXISTS category_daily_stats (
+    category_id UUID NOT NULL,
+    day DATE NOT NULL,
+    adverts INTEGER DEFAULT 0 NOT NULL,
+    views INTEGER DEFAULT 0 NOT NULL,
+    unique_viewers INTEGER DEFAULT 0 NOT NULL,
+    saves INTEGER DEFAULT 0 NOT NULL,
+    cart_additions INTEGER DEFAULT 0 NOT NULL,
+    purchases INTEGER DEFAULT 0 NOT NULL,
+    PRIMARY KEY (category_id, day),
+    FOREIGN KEY (category_id) REFERENCES category(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_viewed_advert_created_at ON viewed_advert (created_at);
+CREATE INDEX IF NOT EXISTS idx_saved_advert_created_at ON saved_advert (created_at);
+CREATE INDEX IF NOT EXISTS idx_cart_advert_created_at ON cart_advert (created_at);
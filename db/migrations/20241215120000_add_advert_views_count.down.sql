@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS update_advert_updated_at ON advert;
+CREATE TRIGGER update_advert_updated_at
+BEFORE UPDATE ON advert
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+DROP FUNCTION IF EXISTS update_advert_updated_at_column();
+
+DROP INDEX IF EXISTS idx_viewed_advert_advert_user;
+
+ALTER TABLE viewed_advert
+    DROP COLUMN IF EXISTS visitor_id;
+
+ALTER TABLE advert
+    DROP COLUMN IF EXISTS views_count;
@@ -0,0 +1,30 @@
+-- Счетчик просмотров хранится в объявлении и периодически пополняется из Redis
+ALTER TABLE advert
+    ADD COLUMN IF NOT EXISTS views_count INTEGER DEFAULT 0 NOT NULL;
+
+UPDATE advert a
+SET views_count = v.views
+FROM (SELECT advert_id, COUNT(*) AS views FROM viewed_advert GROUP BY advert_id) v
+WHERE v.advert_id = a.id;
+
+-- Идентификатор анонимного посетителя для просмотров без авторизации
+ALTER TABLE viewed_advert
+    ADD COLUMN IF NOT EXISTS visitor_id TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_viewed_advert_advert_user ON viewed_advert (advert_id, user_id);
+
+-- Перенос счетчика просмотров не считается изменением объявления
+CREATE OR REPLACE FUNCTION update_advert_updated_at_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF to_jsonb(NEW) - 'views_count' IS DISTINCT FROM to_jsonb(OLD) - 'views_count' THEN
+        NEW.updated_at = NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS update_advert_updated_at ON advert;
+CREATE TRIGGER update_advert_updated_at
+BEFORE UPDATE ON advert
+FOR EACH ROW EXECUTE FUNCTION update_advert_updated_at_column();
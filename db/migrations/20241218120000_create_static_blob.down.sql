@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_advert_image_id;
+DROP INDEX IF EXISTS idx_user_image_id;
+DROP INDEX IF EXISTS idx_static_blob_key;
+
+ALTER TABLE static
+    DROP CONSTRAINT IF EXISTS static_blob_key_fk,
+    DROP COLUMN IF EXISTS blob_key;
+
+DROP TABLE IF EXISTS static_blob;
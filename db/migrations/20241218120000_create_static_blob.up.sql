@@ -0,0 +1,32 @@
+-- Файл статики хранится один раз на каждое уникальное содержимое, строки static ссылаются на него
+CREATE TABLE IF NOT EXISTS static_blob (
+    key TEXT PRIMARY KEY
+        CONSTRAINT static_blob_key_length CHECK (LENGTH(key) <= 255),
+    ref_count INTEGER DEFAULT 0 NOT NULL
+        CONSTRAINT static_blob_ref_count_non_negative CHECK (ref_count >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE static
+    ADD COLUMN IF NOT EXISTS blob_key TEXT NULL;
+
+-- Ключ файла в хранилище - путь без префикса static_files/
+UPDATE static
+SET blob_key = regexp_replace(rtrim(path, '/') || '/' || name, '^static_files/', '')
+WHERE blob_key IS NULL;
+
+INSERT INTO static_blob (key, ref_count)
+SELECT blob_key, COUNT(*)
+FROM static
+GROUP BY blob_key
+ON CONFLICT (key) DO NOTHING;
+
+ALTER TABLE static
+    ADD CONSTRAINT static_blob_key_fk FOREIGN KEY (blob_key) REFERENCES static_blob(key);
+
+CREATE INDEX IF NOT EXISTS idx_static_blob_key ON static (blob_key);
+CREATE INDEX IF NOT EXISTS idx_static_blob_unreferenced ON static_blob (key) WHERE ref_count = 0;
+
+-- Сборщик мусора ищет статику, на которую не ссылаются пользователи и объявления
+CREATE INDEX IF NOT EXISTS idx_user_image_id ON "user" (image_id);
+CREATE INDEX IF NOT EXISTS idx_advert_image_id ON advert (image_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_static_upload_expires_at;
+DROP TABLE IF EXISTS static_upload_part;
+DROP TABLE IF EXISTS static_upload;
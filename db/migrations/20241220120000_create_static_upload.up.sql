@@ -0,0 +1,30 @@
+-- Незавершенные загрузки по частям. received - сколько байт уже получено,
+-- загрузка без активности до expires_at считается брошенной и удаляется
+CREATE TABLE IF NOT EXISTS static_upload (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    owner_id UUID NOT NULL,
+    purpose TEXT NOT NULL
+        CONSTRAINT static_upload_purpose_length CHECK (LENGTH(purpose) <= 32),
+    filename TEXT DEFAULT '' NOT NULL
+        CONSTRAINT static_upload_filename_length CHECK (LENGTH(filename) <= 255),
+    content_type TEXT DEFAULT '' NOT NULL
+        CONSTRAINT static_upload_content_type_length CHECK (LENGTH(content_type) <= 255),
+    size BIGINT NOT NULL
+        CONSTRAINT static_upload_size_positive CHECK (size > 0),
+    received BIGINT DEFAULT 0 NOT NULL
+        CONSTRAINT static_upload_received_range CHECK (received >= 0 AND received <= size),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Части загрузки хранятся отдельными объектами с ключами uploads/<upload_id>/<start>
+CREATE TABLE IF NOT EXISTS static_upload_part (
+    upload_id UUID NOT NULL REFERENCES static_upload(id) ON DELETE CASCADE,
+    start BIGINT NOT NULL
+        CONSTRAINT static_upload_part_start_non_negative CHECK (start >= 0),
+    size BIGINT NOT NULL
+        CONSTRAINT static_upload_part_size_positive CHECK (size > 0),
+    PRIMARY KEY (upload_id, start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_static_upload_expires_at ON static_upload (expires_at);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_static_flagged;
+
+ALTER TABLE static
+    DROP COLUMN IF EXISTS moderation_reason,
+    DROP COLUMN IF EXISTS moderation_status;
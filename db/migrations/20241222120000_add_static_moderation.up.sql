@@ -0,0 +1,8 @@
+-- Результат проверки изображения при загрузке: помеченные файлы сохраняются и ждут ручной проверки
+ALTER TABLE static
+    ADD COLUMN IF NOT EXISTS moderation_status TEXT DEFAULT 'approved' NOT NULL
+        CONSTRAINT static_moderation_status_valid CHECK (moderation_status IN ('approved', 'flagged')),
+    ADD COLUMN IF NOT EXISTS moderation_reason TEXT NULL
+        CONSTRAINT static_moderation_reason_length CHECK (LENGTH(moderation_reason) <= 255);
+
+CREATE INDEX IF NOT EXISTS idx_static_flagged ON static (created_at) WHERE moderation_status = 'flagged';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_advert_video_static_id;
+
+DROP TABLE IF EXISTS advert_video;
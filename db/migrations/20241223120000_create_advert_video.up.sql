@@ -0,0 +1,12 @@
+-- Видео, прикрепленные к объявлению; position задает порядок показа
+CREATE TABLE IF NOT EXISTS advert_video (
+    advert_id UUID NOT NULL REFERENCES advert(id) ON DELETE CASCADE,
+    static_id UUID NOT NULL REFERENCES static(id),
+    position INTEGER NOT NULL
+        CONSTRAINT advert_video_position_non_negative CHECK (position >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (advert_id, static_id)
+);
+
+-- Сборщик мусора ищет статику, на которую не ссылаются объявления
+CREATE INDEX IF NOT EXISTS idx_advert_video_static_id ON advert_video (static_id);
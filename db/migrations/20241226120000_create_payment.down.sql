@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_payment_updated_at ON payment;
+
+DROP INDEX IF EXISTS idx_payment_purchase_id;
+
+DROP TABLE IF EXISTS payment;
+
+DROP TYPE IF EXISTS payment_status;
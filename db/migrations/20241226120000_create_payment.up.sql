@@ -0,0 +1,29 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'payment_status') THEN
+        CREATE TYPE payment_status AS ENUM ('pending', 'waiting_for_capture', 'succeeded', 'canceled', 'refunded');
+    END IF;
+END $$;
+
+-- Оплата покупки картой через платежный шлюз; amount - сумма в копейках,
+-- external_id - идентификатор платежа у провайдера
+CREATE TABLE IF NOT EXISTS payment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    purchase_id UUID NOT NULL REFERENCES purchase(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    amount BIGINT NOT NULL
+        CONSTRAINT payment_amount_positive CHECK (amount > 0),
+    currency TEXT NOT NULL DEFAULT 'RUB',
+    status payment_status NOT NULL DEFAULT 'pending',
+    confirmation_url TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT payment_provider_external_id_unique UNIQUE (provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_purchase_id ON payment (purchase_id);
+
+CREATE TRIGGER update_payment_updated_at
+BEFORE UPDATE ON payment
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS update_safe_deal_updated_at ON safe_deal;
+
+DROP INDEX IF EXISTS idx_safe_deal_status;
+
+DROP TABLE IF EXISTS safe_deal;
+
+DROP TYPE IF EXISTS deal_status;
@@ -0,0 +1,30 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'deal_status') THEN
+        CREATE TYPE deal_status AS ENUM ('awaiting_payment', 'held', 'shipped', 'disputed', 'completed', 'refunded', 'canceled');
+    END IF;
+END $$;
+
+-- Безопасная сделка: средства покупателя заблокированы, пока он не подтвердит получение
+-- товара; held_at и shipped_at отсчитывают сроки отправки и подтверждения
+CREATE TABLE IF NOT EXISTS safe_deal (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    purchase_id UUID NOT NULL REFERENCES purchase(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES seller(id) ON DELETE CASCADE,
+    status deal_status NOT NULL DEFAULT 'awaiting_payment',
+    held_at TIMESTAMP,
+    shipped_at TIMESTAMP,
+    disputed_by UUID REFERENCES "user"(id) ON DELETE SET NULL,
+    dispute_reason TEXT
+        CONSTRAINT safe_deal_dispute_reason_length CHECK (LENGTH(dispute_reason) <= 1000),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT safe_deal_purchase_id_unique UNIQUE (purchase_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_safe_deal_status ON safe_deal (status);
+
+CREATE TRIGGER update_safe_deal_updated_at
+BEFORE UPDATE ON safe_deal
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
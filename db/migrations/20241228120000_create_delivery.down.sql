@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS shipment_event;
+
+DROP TRIGGER IF EXISTS update_shipment_updated_at ON shipment;
+
+DROP INDEX IF EXISTS idx_shipment_status;
+
+DROP TABLE IF EXISTS shipment;
+
+DROP TABLE IF EXISTS advert_delivery_option;
+
+DROP TYPE IF EXISTS shipment_status;
+
+DROP TYPE IF EXISTS delivery_option_type;
@@ -0,0 +1,61 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'delivery_option_type') THEN
+        CREATE TYPE delivery_option_type AS ENUM ('courier', 'parcel_locker', 'post');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'shipment_status') THEN
+        CREATE TYPE shipment_status AS ENUM ('awaiting_shipment', 'in_transit', 'ready_for_pickup', 'delivered', 'returned');
+    END IF;
+END $$;
+
+-- Способы доставки объявления: цена в рублях и срок в днях
+CREATE TABLE IF NOT EXISTS advert_delivery_option (
+    advert_id UUID NOT NULL REFERENCES advert(id) ON DELETE CASCADE,
+    type delivery_option_type NOT NULL,
+    price INT NOT NULL CONSTRAINT advert_delivery_option_price_positive CHECK (price >= 0),
+    min_days INT NOT NULL,
+    max_days INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT advert_delivery_option_days CHECK (min_days >= 0 AND min_days <= max_days),
+    PRIMARY KEY (advert_id, type)
+);
+
+-- Отправление продавца по покупке: объявления одного продавца уезжают одной посылкой.
+-- Статус обновляется по данным перевозчика, tracked_at - время последней проверки
+CREATE TABLE IF NOT EXISTS shipment (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    purchase_id UUID NOT NULL REFERENCES purchase(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES seller(id) ON DELETE CASCADE,
+    buyer_id UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    type delivery_option_type NOT NULL,
+    cost INT NOT NULL,
+    min_days INT NOT NULL,
+    max_days INT NOT NULL,
+    carrier TEXT,
+    tracking_number TEXT
+        CONSTRAINT shipment_tracking_number_length CHECK (LENGTH(tracking_number) <= 64),
+    status shipment_status NOT NULL DEFAULT 'awaiting_shipment',
+    shipped_at TIMESTAMP,
+    delivered_at TIMESTAMP,
+    tracked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT shipment_purchase_seller_unique UNIQUE (purchase_id, seller_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_status ON shipment (status, tracked_at);
+
+-- История отправления от перевозчика
+CREATE TABLE IF NOT EXISTS shipment_event (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    shipment_id UUID NOT NULL REFERENCES shipment(id) ON DELETE CASCADE,
+    status shipment_status NOT NULL,
+    description TEXT NOT NULL,
+    location TEXT,
+    occurred_at TIMESTAMP NOT NULL,
+    CONSTRAINT shipment_event_unique UNIQUE (shipment_id, status, occurred_at)
+);
+
+CREATE TRIGGER update_shipment_updated_at
+BEFORE UPDATE ON shipment
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS purchase_address;
+
+DROP TRIGGER IF EXISTS update_user_address_updated_at ON user_address;
+
+DROP INDEX IF EXISTS user_address_default_unique;
+
+DROP INDEX IF EXISTS idx_user_address_user_id;
+
+DROP TABLE IF EXISTS user_address;
+
+ALTER TABLE purchase DROP CONSTRAINT IF EXISTS purchase_address_length;
+ALTER TABLE purchase RENAME COLUMN address TO adress;
+ALTER TABLE purchase ADD CONSTRAINT adress_length CHECK (LENGTH(adress) <= 150) NOT VALID;
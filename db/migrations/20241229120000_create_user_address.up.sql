@@ -0,0 +1,49 @@
+-- Адрес покупки хранится в виде текста, собранного из структурированного адреса
+ALTER TABLE purchase RENAME COLUMN adress TO address;
+ALTER TABLE purchase DROP CONSTRAINT IF EXISTS adress_length;
+ALTER TABLE purchase ADD CONSTRAINT purchase_address_length CHECK (LENGTH(address) <= 500);
+
+-- Адресная книга пользователя: у пользователя может быть только один адрес по умолчанию
+CREATE TABLE IF NOT EXISTS user_address (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4() NOT NULL,
+    user_id UUID NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    recipient_name TEXT NOT NULL
+        CONSTRAINT user_address_recipient_name_length CHECK (LENGTH(recipient_name) <= 100),
+    phone TEXT NOT NULL
+        CONSTRAINT user_address_phone_length CHECK (LENGTH(phone) <= 16),
+    city TEXT NOT NULL
+        CONSTRAINT user_address_city_length CHECK (LENGTH(city) <= 100),
+    street TEXT NOT NULL
+        CONSTRAINT user_address_street_length CHECK (LENGTH(street) <= 150),
+    building TEXT NOT NULL
+        CONSTRAINT user_address_building_length CHECK (LENGTH(building) <= 20),
+    apartment TEXT NOT NULL DEFAULT ''
+        CONSTRAINT user_address_apartment_length CHECK (LENGTH(apartment) <= 20),
+    postcode TEXT NOT NULL DEFAULT ''
+        CONSTRAINT user_address_postcode_length CHECK (LENGTH(postcode) <= 6),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_address_user_id ON user_address (user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_address_default_unique ON user_address (user_id) WHERE is_default;
+
+CREATE TRIGGER update_user_address_updated_at
+BEFORE UPDATE ON user_address
+FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Снимок адреса на момент покупки: не меняется при изменении или удалении адреса из книги
+CREATE TABLE IF NOT EXISTS purchase_address (
+    purchase_id UUID PRIMARY KEY REFERENCES purchase(id) ON DELETE CASCADE,
+    address_id UUID REFERENCES user_address(id) ON DELETE SET NULL,
+    recipient_name TEXT NOT NULL,
+    phone TEXT NOT NULL,
+    city TEXT NOT NULL,
+    street TEXT NOT NULL,
+    building TEXT NOT NULL,
+    apartment TEXT NOT NULL DEFAULT '',
+    postcode TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
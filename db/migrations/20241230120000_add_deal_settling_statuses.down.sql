@@ -0,0 +1,3 @@
+-- Значения enum нельзя удалить, поэтому незавершенные списания и возвраты
+-- передаются администратору как спор
+UPDATE safe_deal SET status = 'disputed' WHERE status::text IN ('releasing', 'refunding');
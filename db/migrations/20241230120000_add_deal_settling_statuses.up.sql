@@ -0,0 +1,4 @@
+-- Промежуточные этапы сделки: статус меняется до запроса к платежному провайдеру,
+-- чтобы спор нельзя было открыть, пока средства списываются или возвращаются
+ALTER TYPE deal_status ADD VALUE IF NOT EXISTS 'releasing';
+ALTER TYPE deal_status ADD VALUE IF NOT EXISTS 'refunding';
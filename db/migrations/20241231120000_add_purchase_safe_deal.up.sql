@@ -0,0 +1,5 @@
+-- Признак безопасной сделки хранится вместе с покупкой: платеж за такую покупку
+-- создается только с удержанием средств, даже если сделку не удалось создать сразу
+ALTER TABLE purchase ADD COLUMN IF NOT EXISTS safe_deal BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE purchase SET safe_deal = TRUE WHERE id IN (SELECT purchase_id FROM safe_deal);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS advert_view_batch;
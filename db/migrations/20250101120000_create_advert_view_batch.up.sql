@@ -0,0 +1,6 @@
+-- Перенесенные пачки просмотров: повторный перенос той же пачки после сбоя
+-- подтверждения в Redis не увеличивает счетчики второй раз
+CREATE TABLE IF NOT EXISTS advert_view_batch (
+    id UUID PRIMARY KEY,
+    flushed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_static_name_source;
@@ -0,0 +1,3 @@
+-- По имени файла без расширения отдача файлов статики находит строки статики,
+-- к которым относятся сам файл, его копия с исходными пропорциями и варианты
+CREATE INDEX IF NOT EXISTS idx_static_name_source ON static (split_part(name, '.', 1));
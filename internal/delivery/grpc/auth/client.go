@@ -3,52 +3,46 @@ package auth
 import (
 	"context"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
 	authProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/auth/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// idempotentMethods - вызовы, которые безопасно повторить, если сервис авторизации недоступен
+var idempotentMethods = []string{"GetUserIDBySession", "DeleteSession", "Ping"}
+
 type GrpcClient struct {
 	authManager authProto.AuthServiceClient
 }
 
-func NewGrpcClient(addr string) (*GrpcClient, error) {
-	conn, err := grpc.Dial(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	authManager := authProto.NewAuthServiceClient(conn)
-
-	_, err = authManager.Ping(context.Background(), &authProto.NoContent{})
+func NewGrpcClient(addr string, cfg config.GRPCConfig, opts ...grpc.DialOption) (*GrpcClient, error) {
+	conn, err := connector.GetGrpcConnector(addr, authProto.AuthService_ServiceDesc.ServiceName, cfg, idempotentMethods, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &GrpcClient{authManager: authManager}, nil
+	return &GrpcClient{authManager: authProto.NewAuthServiceClient(conn)}, nil
 }
 
-func (c *GrpcClient) GetUserIDBySession(sessionID string) (string, error) {
-	user, err := c.authManager.GetUserIDBySession(context.Background(), &authProto.Session{Id: sessionID})
+func (c *GrpcClient) GetUserIDBySession(ctx context.Context, sessionID string) (string, error) {
+	user, err := c.authManager.GetUserIDBySession(ctx, &authProto.Session{Id: sessionID})
 	if err != nil {
-		return "", err
+		return "", authErrors.FromStatus(err)
 	}
 	return user.Id, nil
 }
 
-func (c *GrpcClient) CreateSession(userID uuid.UUID) (string, error) {
-	session, err := c.authManager.CreateSession(context.Background(), &authProto.User{Id: userID.String()})
+func (c *GrpcClient) CreateSession(ctx context.Context, userID uuid.UUID) (string, error) {
+	session, err := c.authManager.CreateSession(ctx, &authProto.User{Id: userID.String()})
 	if err != nil {
-		return "", err
+		return "", authErrors.FromStatus(err)
 	}
 	return session.Id, nil
 }
 
-func (c *GrpcClient) DeleteSession(sessionID string) error {
-	_, err := c.authManager.DeleteSession(context.Background(), &authProto.Session{Id: sessionID})
-	return err
+func (c *GrpcClient) DeleteSession(ctx context.Context, sessionID string) error {
+	_, err := c.authManager.DeleteSession(ctx, &authProto.Session{Id: sessionID})
+	return authErrors.FromStatus(err)
 }
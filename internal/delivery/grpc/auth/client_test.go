@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	authProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/auth/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -57,7 +58,7 @@ func TestGetUserIDBySession(t *testing.T) {
 
 	mockAuthClient.On("GetUserIDBySession", mock.Anything, &authProto.Session{Id: sessionID}).Return(&authProto.User{Id: userID}, nil)
 
-	result, err := client.GetUserIDBySession(sessionID)
+	result, err := client.GetUserIDBySession(context.Background(), sessionID)
 	assert.NoError(t, err)
 	assert.Equal(t, userID, result)
 
@@ -72,13 +73,29 @@ func TestGetUserIDBySession_Error(t *testing.T) {
 
 	mockAuthClient.On("GetUserIDBySession", mock.Anything, &authProto.Session{Id: sessionID}).Return(&authProto.User{}, errors.New("session not found"))
 
-	result, err := client.GetUserIDBySession(sessionID)
+	result, err := client.GetUserIDBySession(context.Background(), sessionID)
 	assert.Error(t, err)
 	assert.Empty(t, result)
 
 	mockAuthClient.AssertExpectations(t)
 }
 
+func TestGetUserIDBySession_SessionNotFound(t *testing.T) {
+	mockAuthClient := new(MockAuthServiceClient)
+	client := &GrpcClient{authManager: mockAuthClient}
+
+	sessionID := "test-session-id"
+	// так сервер авторизации сообщает о ненайденной сессии
+	statusErr := authErrors.ToStatus(usecase.ErrUserNotFound)
+
+	mockAuthClient.On("GetUserIDBySession", mock.Anything, &authProto.Session{Id: sessionID}).Return(&authProto.User{}, statusErr)
+
+	_, err := client.GetUserIDBySession(context.Background(), sessionID)
+	assert.ErrorIs(t, err, usecase.ErrSessionNotFound)
+
+	mockAuthClient.AssertExpectations(t)
+}
+
 func TestCreateSession(t *testing.T) {
 	mockAuthClient := new(MockAuthServiceClient)
 	client := &GrpcClient{authManager: mockAuthClient}
@@ -88,7 +105,7 @@ func TestCreateSession(t *testing.T) {
 
 	mockAuthClient.On("CreateSession", mock.Anything, &authProto.User{Id: userID.String()}).Return(&authProto.Session{Id: sessionID}, nil)
 
-	result, err := client.CreateSession(userID)
+	result, err := client.CreateSession(context.Background(), userID)
 	assert.NoError(t, err)
 	assert.Equal(t, sessionID, result)
 
@@ -103,7 +120,7 @@ func TestCreateSession_Error(t *testing.T) {
 
 	mockAuthClient.On("CreateSession", mock.Anything, &authProto.User{Id: userID.String()}).Return(&authProto.Session{}, errors.New("failed to create session"))
 
-	result, err := client.CreateSession(userID)
+	result, err := client.CreateSession(context.Background(), userID)
 	assert.Error(t, err)
 	assert.Empty(t, result)
 
@@ -118,7 +135,7 @@ func TestDeleteSession(t *testing.T) {
 
 	mockAuthClient.On("DeleteSession", mock.Anything, &authProto.Session{Id: sessionID}).Return(&authProto.NoContent{}, nil)
 
-	err := client.DeleteSession(sessionID)
+	err := client.DeleteSession(context.Background(), sessionID)
 	assert.NoError(t, err)
 
 	mockAuthClient.AssertExpectations(t)
@@ -132,7 +149,7 @@ func TestDeleteSession_Error(t *testing.T) {
 
 	mockAuthClient.On("DeleteSession", mock.Anything, &authProto.Session{Id: sessionID}).Return(&authProto.NoContent{}, errors.New("failed to delete session"))
 
-	err := client.DeleteSession(sessionID)
+	err := client.DeleteSession(context.Background(), sessionID)
 	assert.Error(t, err)
 
 	mockAuthClient.AssertExpectations(t)
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	authProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/auth/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/grpcerr"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"google.golang.org/grpc/codes"
+)
+
+// authErrors - ошибки сервиса авторизации, которые передаются клиенту кодами gRPC.
+// Сервис сообщает о ненайденной сессии ошибкой ErrUserNotFound, клиент получает ErrSessionNotFound
+var authErrors = grpcerr.NewMapper(authProto.AuthService_ServiceDesc.ServiceName,
+	grpcerr.Reason{Err: usecase.ErrSessionNotFound, Code: codes.Unauthenticated, Reason: "SESSION_NOT_FOUND"},
+	grpcerr.Reason{Err: usecase.ErrUserNotFound, Code: codes.Unauthenticated, Reason: "SESSION_NOT_FOUND"},
+	grpcerr.Reason{Err: repository.ErrSessionNotFound, Code: codes.Unauthenticated, Reason: "SESSION_NOT_FOUND"},
+	grpcerr.Reason{Err: repository.ErrIncorrectID, Code: codes.InvalidArgument, Reason: "INCORRECT_USER_ID"},
+)
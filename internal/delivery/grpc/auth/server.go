@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 
 	authProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/auth/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/grpcerr"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 )
@@ -19,30 +20,30 @@ func NewGrpcServer(authUC usecase.Auth) *GrpcServer {
 	return &GrpcServer{AuthUC: authUC}
 }
 
-func (s *GrpcServer) GetUserIDBySession(_ context.Context, in *authProto.Session) (*authProto.User, error) {
-	userID, err := s.AuthUC.GetUserIdBySession(in.Id)
+func (s *GrpcServer) GetUserIDBySession(ctx context.Context, in *authProto.Session) (*authProto.User, error) {
+	userID, err := s.AuthUC.GetUserIdBySession(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, authErrors.ToStatus(err)
 	}
 	return &authProto.User{Id: userID.String()}, nil
 }
 
-func (s *GrpcServer) CreateSession(_ context.Context, in *authProto.User) (*authProto.Session, error) {
+func (s *GrpcServer) CreateSession(ctx context.Context, in *authProto.User) (*authProto.Session, error) {
 	userID, err := uuid.Parse(in.Id)
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.InvalidArgument("id", err)
 	}
-	sessionID, err := s.AuthUC.CreateSession(userID)
+	sessionID, err := s.AuthUC.CreateSession(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, authErrors.ToStatus(err)
 	}
 	return &authProto.Session{Id: sessionID}, nil
 }
 
-func (s *GrpcServer) DeleteSession(_ context.Context, in *authProto.Session) (*authProto.NoContent, error) {
-	err := s.AuthUC.Logout(in.Id)
+func (s *GrpcServer) DeleteSession(ctx context.Context, in *authProto.Session) (*authProto.NoContent, error) {
+	err := s.AuthUC.Logout(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, authErrors.ToStatus(err)
 	}
 	return &authProto.NoContent{}, nil
 }
@@ -14,17 +14,17 @@ type MockAuth struct {
 	mock.Mock
 }
 
-func (m *MockAuth) GetUserIdBySession(sessionID string) (uuid.UUID, error) {
+func (m *MockAuth) GetUserIdBySession(_ context.Context, sessionID string) (uuid.UUID, error) {
 	args := m.Called(sessionID)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *MockAuth) CreateSession(userID uuid.UUID) (string, error) {
+func (m *MockAuth) CreateSession(_ context.Context, userID uuid.UUID) (string, error) {
 	args := m.Called(userID)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuth) Logout(sessionID string) error {
+func (m *MockAuth) Logout(_ context.Context, sessionID string) error {
 	args := m.Called(sessionID)
 	return args.Error(0)
 }
@@ -3,13 +3,16 @@ package cart_purchase
 import (
 	"context"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
 	cartPurchaseProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
@@ -17,29 +20,35 @@ var (
 	ErrInvalidPaymentMethod  = errors.New("invalid payment method")
 	ErrInvalidDeliveryMethod = errors.New("invalid delivery method")
 	ErrPurchaseNotFound      = errors.New("purchase not found")
-	ErrCartNotFound          = errors.New("cart not found")
+	ErrCallerUnauthenticated = errors.New("caller is not authenticated")
+	ErrForbidden             = errors.New("cart or purchases belong to another user")
+	// Ошибки корзины совпадают с ошибками репозитория, в которые клиент переводит статусы сервера
+	ErrCartNotFound         = repository.ErrCartNotFound
+	ErrCartOrAdvertNotFound = repository.ErrCartOrAdvertNotFound
+	ErrAdvertNotFound       = repository.ErrAdvertNotFound
+	// ErrAdvertUnavailable - объявление нельзя купить. Оформление корзины возвращает
+	// *usecase.UnavailableAdvertsError со списком таких объявлений
+	ErrOwnAdvert         = usecase.ErrCartOwnAdvert
+	ErrAdvertUnavailable = usecase.ErrCartAdvertUnavailable
 )
 
+// idempotentMethods - вызовы, которые безопасно повторить, если сервис корзин недоступен
+var idempotentMethods = []string{"GetPurchasesByUserID", "GetCartByID", "GetCartByUserID", "CheckCartExists", "Ping"}
+
 type CartPurchaseClient struct {
 	client cartPurchaseProto.CartPurchaseServiceClient
 	conn   *grpc.ClientConn
 }
 
-func NewCartPurchaseClient(addr string) (*CartPurchaseClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, err
-	}
-
-	client := cartPurchaseProto.NewCartPurchaseServiceClient(conn)
-
-	_, err = client.Ping(context.Background(), &cartPurchaseProto.NoContent{})
+func NewCartPurchaseClient(addr string, cfg config.GRPCConfig, opts ...grpc.DialOption) (*CartPurchaseClient, error) {
+	conn, err := connector.GetGrpcConnector(addr, cartPurchaseProto.CartPurchaseService_ServiceDesc.ServiceName,
+		cfg, idempotentMethods, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &CartPurchaseClient{
-		client: client,
+		client: cartPurchaseProto.NewCartPurchaseServiceClient(conn),
 		conn:   conn,
 	}, nil
 }
@@ -49,17 +58,22 @@ func (c *CartPurchaseClient) Close() error {
 }
 
 func (c *CartPurchaseClient) AddPurchase(ctx context.Context, req dto.PurchaseRequest) (*dto.PurchaseResponse, error) {
+	// неизвестные значения, как и раньше, становятся картой и самовывозом
+	protoPaymentMethod, _ := ConvertDBPaymentMethodToEnum(string(req.PaymentMethod))
+	protoDeliveryMethod, _ := ConvertDBDeliveryMethodToEnum(string(req.DeliveryMethod))
 	protoReq := &cartPurchaseProto.AddPurchaseRequest{
-		CartId:         req.CartID.String(),
-		Address:        req.Address,
-		PaymentMethod:  cartPurchaseProto.PaymentMethod(cartPurchaseProto.PaymentMethod_value[string(req.PaymentMethod)]),
-		DeliveryMethod: cartPurchaseProto.DeliveryMethod(cartPurchaseProto.DeliveryMethod_value[string(req.DeliveryMethod)]),
-		UserId:         req.UserID.String(),
+		CartId:          req.CartID.String(),
+		Address:         req.Address,
+		PaymentMethod:   protoPaymentMethod,
+		DeliveryMethod:  protoDeliveryMethod,
+		UserId:          req.UserID.String(),
+		SafeDeal:        req.SafeDeal,
+		PurchaseAddress: ConvertPurchaseAddressToProto(req.PurchaseAddress),
 	}
 
 	resp, err := c.client.AddPurchase(ctx, protoReq)
 	if err != nil {
-		return nil, errors.Wrap(ErrPurchaseNotFound, err.Error())
+		return nil, purchaseErrorFromStatus(err)
 	}
 
 	purchaseStatus:= ConvertPurchaseStatusToDB(resp.Status)
@@ -73,6 +87,7 @@ func (c *CartPurchaseClient) AddPurchase(ctx context.Context, req dto.PurchaseRe
 		Status:         dto.PurchaseStatus(purchaseStatus),
 		PaymentMethod:  dto.PaymentMethod(paymentMethod),
 		DeliveryMethod: dto.DeliveryMethod(deliveryMethod),
+		SafeDeal:       resp.SafeDeal,
 	}
 
 	return response, nil
@@ -85,7 +100,7 @@ func (c *CartPurchaseClient) GetPurchasesByUserID(ctx context.Context, userID uu
 
 	resp, err := c.client.GetPurchasesByUserID(ctx, protoReq)
 	if err != nil {
-		return nil, errors.Wrap(ErrPurchaseNotFound, "purchases not found")
+		return nil, cartPurchaseErrors.FromStatus(err)
 	}
 
 	var purchases []*dto.PurchaseResponse
@@ -101,6 +116,7 @@ func (c *CartPurchaseClient) GetPurchasesByUserID(ctx context.Context, userID uu
 			Status:         dto.PurchaseStatus(purchaseStatus),
 			PaymentMethod:  dto.PaymentMethod(paymentMethod),
 			DeliveryMethod: dto.DeliveryMethod(deliveryMethod),
+			SafeDeal:       p.SafeDeal,
 		})
 	}
 
@@ -114,7 +130,7 @@ func (c *CartPurchaseClient) GetCartByID(ctx context.Context, cartID uuid.UUID)
 
 	resp, err := c.client.GetCartByID(ctx, protoReq)
 	if err != nil {
-		return nil, errors.Wrap(ErrCartNotFound, "cart not found")
+		return nil, cartPurchaseErrors.FromStatus(err)
 	}
 
 	cart := &dto.Cart{
@@ -151,7 +167,7 @@ func (c *CartPurchaseClient) GetCartByUserID(ctx context.Context, userID uuid.UU
 
 	resp, err := c.client.GetCartByUserID(ctx, protoReq)
 	if err != nil {
-		return nil, errors.Wrap(ErrCartNotFound, "cart not found")
+		return nil, cartPurchaseErrors.FromStatus(err)
 	}
 
 	cart := &dto.Cart{
@@ -189,7 +205,7 @@ func (c *CartPurchaseClient) AddAdvertToCart(ctx context.Context, userID uuid.UU
 
 	resp, err := c.client.AddAdvertToCart(ctx, protoReq)
 	if err != nil {
-		return "", errors.Wrap(ErrCartNotFound, "cart not found")
+		return "", cartPurchaseErrors.FromStatus(err)
 	}
 
 	return resp.Message, nil
@@ -203,7 +219,7 @@ func (c *CartPurchaseClient) DeleteAdvertFromCart(ctx context.Context, cartID uu
 
 	resp, err := c.client.DeleteAdvertFromCart(ctx, protoReq)
 	if err != nil {
-		return "", err
+		return "", cartPurchaseErrors.FromStatus(err)
 	}
 
 	return resp.Message, nil
@@ -216,12 +232,53 @@ func (c *CartPurchaseClient) CheckCartExists(ctx context.Context, userID uuid.UU
 
 	resp, err := c.client.CheckCartExists(ctx, protoReq)
 	if err != nil {
-		return uuid.Nil, errors.Wrap(ErrCartNotFound, "cart not found")
+		return uuid.Nil, cartPurchaseErrors.FromStatus(err)
 	}
 
 	return uuid.MustParse(resp.CartId), nil
 }
 
+// MergeCart переносит объявления гостевой корзины в корзину пользователя
+func (c *CartPurchaseClient) MergeCart(ctx context.Context, userID uuid.UUID, advertIDs []uuid.UUID) (*dto.CartMergeResult, error) {
+	protoReq := &cartPurchaseProto.MergeCartRequest{
+		UserId:    userID.String(),
+		AdvertIds: make([]string, 0, len(advertIDs)),
+	}
+	for _, advertID := range advertIDs {
+		protoReq.AdvertIds = append(protoReq.AdvertIds, advertID.String())
+	}
+
+	resp, err := c.client.MergeCart(ctx, protoReq)
+	if err != nil {
+		return nil, cartPurchaseErrors.FromStatus(err)
+	}
+
+	result := &dto.CartMergeResult{
+		CartID:      uuid.MustParse(resp.CartId),
+		Added:       make([]uuid.UUID, 0, len(resp.AddedAdvertIds)),
+		Duplicates:  make([]uuid.UUID, 0, len(resp.DuplicateAdvertIds)),
+		Unavailable: make([]dto.UnavailableAdvert, 0, len(resp.Unavailable)),
+	}
+	for _, advertID := range resp.AddedAdvertIds {
+		result.Added = append(result.Added, uuid.MustParse(advertID))
+	}
+	for _, advertID := range resp.DuplicateAdvertIds {
+		result.Duplicates = append(result.Duplicates, uuid.MustParse(advertID))
+	}
+	for _, advert := range resp.Unavailable {
+		unavailable := dto.UnavailableAdvert{
+			AdvertID: uuid.MustParse(advert.AdvertId),
+			Reason:   dto.UnavailableReason(advert.Reason),
+		}
+		if unavailable.Reason != dto.UnavailableNotFound {
+			unavailable.Status = entity.AdvertStatus(ConvertAdvertStatusToDB(advert.Status))
+		}
+		result.Unavailable = append(result.Unavailable, unavailable)
+	}
+
+	return result, nil
+}
+
 func (c *CartPurchaseClient) Ping(ctx context.Context) error {
 	_, err := c.client.Ping(ctx, &cartPurchaseProto.NoContent{})
 	return err
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	cartPurchaseProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -57,6 +58,11 @@ func (m *MockCartPurchaseServiceClient) CheckCartExists(ctx context.Context, in
 	return args.Get(0).(*cartPurchaseProto.CheckCartExistsResponse), args.Error(1)
 }
 
+func (m *MockCartPurchaseServiceClient) MergeCart(ctx context.Context, in *cartPurchaseProto.MergeCartRequest, opts ...grpc.CallOption) (*cartPurchaseProto.MergeCartResponse, error) {
+	args := m.Called(ctx, in)
+	return args.Get(0).(*cartPurchaseProto.MergeCartResponse), args.Error(1)
+}
+
 func TestNewCartPurchaseClient(t *testing.T) {
 	mockClient := new(MockCartPurchaseServiceClient)
 	mockConn, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
@@ -262,6 +268,43 @@ func TestAddAdvertToCart_Error(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestMergeCart(t *testing.T) {
+	mockClient := new(MockCartPurchaseServiceClient)
+	client := &CartPurchaseClient{client: mockClient}
+
+	userID, cartID := uuid.New(), uuid.New()
+	added, duplicate, reserved, deleted := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	protoReq := &cartPurchaseProto.MergeCartRequest{
+		UserId:    userID.String(),
+		AdvertIds: []string{added.String(), duplicate.String(), reserved.String(), deleted.String()},
+	}
+	protoResp := &cartPurchaseProto.MergeCartResponse{
+		CartId:             cartID.String(),
+		AddedAdvertIds:     []string{added.String()},
+		DuplicateAdvertIds: []string{duplicate.String()},
+		Unavailable: []*cartPurchaseProto.UnavailableAdvert{
+			{AdvertId: reserved.String(), Status: cartPurchaseProto.AdvertStatus_ADVERT_STATUS_RESERVED, Reason: "reserved"},
+			{AdvertId: deleted.String(), Reason: "not_found"},
+		},
+	}
+
+	mockClient.On("MergeCart", mock.Anything, protoReq).Return(protoResp, nil)
+
+	result, err := client.MergeCart(context.Background(), userID, []uuid.UUID{added, duplicate, reserved, deleted})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.CartMergeResult{
+		CartID:     cartID,
+		Added:      []uuid.UUID{added},
+		Duplicates: []uuid.UUID{duplicate},
+		Unavailable: []dto.UnavailableAdvert{
+			{AdvertID: reserved, Status: entity.AdvertStatusReserved, Reason: dto.UnavailableReserved},
+			{AdvertID: deleted, Reason: dto.UnavailableNotFound},
+		},
+	}, result)
+	mockClient.AssertExpectations(t)
+}
+
 func TestDeleteAdvertFromCart(t *testing.T) {
 	mockClient := new(MockCartPurchaseServiceClient)
 	client := &CartPurchaseClient{client: mockClient}
@@ -2,18 +2,21 @@ package cart_purchase
 
 import (
 	proto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/pkg/errors"
 )
 
+// ConvertDBPurchaseStatusToEnum принимает как значения перечисления purchase_status из БД,
+// так и имена значений протокола
 func ConvertDBPurchaseStatusToEnum(dbStatus string) (proto.PurchaseStatus, error) {
 	switch dbStatus {
-	case "PURCHASE_STATUS_PENDING":
+	case "PURCHASE_STATUS_PENDING", "pending":
 		return proto.PurchaseStatus_PURCHASE_STATUS_PENDING, nil
-	case "PURCHASE_STATUS_IN_PROGRESS":
+	case "PURCHASE_STATUS_IN_PROGRESS", "in_progress":
 		return proto.PurchaseStatus_PURCHASE_STATUS_IN_PROGRESS, nil
-	case "PURCHASE_STATUS_COMPLETED":
+	case "PURCHASE_STATUS_COMPLETED", "completed":
 		return proto.PurchaseStatus_PURCHASE_STATUS_COMPLETED, nil
-	case "PURCHASE_STATUS_CANCELED":
+	case "PURCHASE_STATUS_CANCELED", "cancelled", "canceled":
 		return proto.PurchaseStatus_PURCHASE_STATUS_CANCELED, nil
 	default:
 		return proto.PurchaseStatus_PURCHASE_STATUS_PENDING, errors.New("unknown purchase status")
@@ -130,3 +133,51 @@ func ConvertAdvertStatusToDB(status proto.AdvertStatus) string {
 		return "unknown"
 	}
 }
+
+// ConvertPurchaseAddressToProto передает адрес покупки. Адрес, введенный при оформлении,
+// передается с пустым address_id
+func ConvertPurchaseAddressToProto(address *dto.PurchaseAddress) *proto.PurchaseAddress {
+	if address == nil {
+		return nil
+	}
+	protoAddress := &proto.PurchaseAddress{
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		City:          address.City,
+		Street:        address.Street,
+		Building:      address.Building,
+		Apartment:     address.Apartment,
+		Postcode:      address.Postcode,
+	}
+	if address.AddressID != nil {
+		protoAddress.AddressId = address.AddressID.String()
+	}
+	return protoAddress
+}
+
+// ConvertPurchaseAddressFromProto возвращает адрес покупки или ошибку InvalidArgument,
+// если address_id не является UUID
+func ConvertPurchaseAddressFromProto(address *proto.PurchaseAddress) (*dto.PurchaseAddress, error) {
+	if address == nil {
+		return nil, nil
+	}
+	purchaseAddress := &dto.PurchaseAddress{
+		AddressDetails: dto.AddressDetails{
+			RecipientName: address.RecipientName,
+			Phone:         address.Phone,
+			City:          address.City,
+			Street:        address.Street,
+			Building:      address.Building,
+			Apartment:     address.Apartment,
+			Postcode:      address.Postcode,
+		},
+	}
+	if address.AddressId != "" {
+		addressID, err := parseID("purchase_address.address_id", address.AddressId)
+		if err != nil {
+			return nil, err
+		}
+		purchaseAddress.AddressID = &addressID
+	}
+	return purchaseAddress, nil
+}
@@ -4,8 +4,13 @@ import (
 	"testing"
 
 	proto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestConvertDBPurchaseStatusToEnum(t *testing.T) {
@@ -18,6 +23,8 @@ func TestConvertDBPurchaseStatusToEnum(t *testing.T) {
 		{"PURCHASE_STATUS_IN_PROGRESS", proto.PurchaseStatus_PURCHASE_STATUS_IN_PROGRESS, nil},
 		{"PURCHASE_STATUS_COMPLETED", proto.PurchaseStatus_PURCHASE_STATUS_COMPLETED, nil},
 		{"PURCHASE_STATUS_CANCELED", proto.PurchaseStatus_PURCHASE_STATUS_CANCELED, nil},
+		{"in_progress", proto.PurchaseStatus_PURCHASE_STATUS_IN_PROGRESS, nil},
+		{"cancelled", proto.PurchaseStatus_PURCHASE_STATUS_CANCELED, nil},
 		{"unknown", proto.PurchaseStatus_PURCHASE_STATUS_PENDING, errors.New("unknown purchase status")},
 	}
 
@@ -202,3 +209,22 @@ func TestConvertAdvertStatusToDB(t *testing.T) {
 		assert.Equal(t, test.expected, result)
 	}
 }
+
+func TestConvertPurchaseAddress(t *testing.T) {
+	addressID := uuid.New()
+	details := dto.AddressDetails{RecipientName: "Иван Иванов", Phone: "+79991234567", City: "Москва",
+		Street: "Тверская ул.", Building: "1", Apartment: "5", Postcode: "125009"}
+
+	for _, address := range []*dto.PurchaseAddress{
+		{AddressID: &addressID, AddressDetails: details},
+		{AddressDetails: details},
+		nil,
+	} {
+		converted, err := ConvertPurchaseAddressFromProto(ConvertPurchaseAddressToProto(address))
+		require.NoError(t, err)
+		assert.Equal(t, address, converted)
+	}
+
+	_, err := ConvertPurchaseAddressFromProto(&proto.PurchaseAddress{AddressId: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
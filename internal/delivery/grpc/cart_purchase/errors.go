@@ -0,0 +1,84 @@
+package cart_purchase
+
+import (
+	"errors"
+
+	proto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/grpcerr"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// cartPurchaseErrors - ошибки сервиса корзин и покупок, которые передаются клиенту кодами gRPC
+var cartPurchaseErrors = grpcerr.NewMapper(proto.CartPurchaseService_ServiceDesc.ServiceName,
+	grpcerr.Reason{Err: repository.ErrCartNotFound, Code: codes.NotFound, Reason: "CART_NOT_FOUND"},
+	grpcerr.Reason{Err: repository.ErrCartOrAdvertNotFound, Code: codes.NotFound, Reason: "CART_OR_ADVERT_NOT_FOUND"},
+	grpcerr.Reason{Err: repository.ErrCartAlreadyExists, Code: codes.AlreadyExists, Reason: "CART_ALREADY_EXISTS"},
+	grpcerr.Reason{Err: repository.ErrAdvertNotFound, Code: codes.NotFound, Reason: "ADVERT_NOT_FOUND"},
+	grpcerr.Reason{Err: usecase.ErrCartOwnAdvert, Code: codes.FailedPrecondition, Reason: "OWN_ADVERT"},
+	grpcerr.Reason{Err: usecase.ErrCartAdvertUnavailable, Code: codes.FailedPrecondition, Reason: "ADVERT_UNAVAILABLE"},
+	grpcerr.Reason{Err: usecase.ErrPurchaseEmptyCart, Code: codes.FailedPrecondition, Reason: "EMPTY_CART"},
+	grpcerr.Reason{Err: usecase.ErrPurchaseAddressRequired, Code: codes.InvalidArgument, Reason: "ADDRESS_REQUIRED"},
+	grpcerr.Reason{Err: ErrCallerUnauthenticated, Code: codes.Unauthenticated, Reason: "CALLER_UNAUTHENTICATED"},
+	grpcerr.Reason{Err: ErrForbidden, Code: codes.PermissionDenied, Reason: "FORBIDDEN"},
+)
+
+// parseID разбирает идентификатор из поля field запроса
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, grpcerr.InvalidArgument(field, err)
+	}
+	return id, nil
+}
+
+// purchaseErrorToStatus переводит ошибку оформления в статус. Объявления, которые нельзя купить,
+// передаются в PreconditionFailure: причина в Type, объявление в Subject, его статус в Description
+func purchaseErrorToStatus(err error) error {
+	st := cartPurchaseErrors.ToStatus(err)
+	var unavailable *usecase.UnavailableAdvertsError
+	if !errors.As(err, &unavailable) {
+		return st
+	}
+
+	violations := make([]*errdetails.PreconditionFailure_Violation, 0, len(unavailable.Adverts))
+	for _, advert := range unavailable.Adverts {
+		violations = append(violations, &errdetails.PreconditionFailure_Violation{
+			Type:        string(advert.Reason),
+			Subject:     advert.AdvertID.String(),
+			Description: string(advert.Status),
+		})
+	}
+	return grpcerr.WithPreconditionFailure(st, violations...)
+}
+
+// purchaseErrorFromStatus восстанавливает на клиенте ошибку оформления вместе с объявлениями,
+// которые нельзя купить
+func purchaseErrorFromStatus(err error) error {
+	mapped := cartPurchaseErrors.FromStatus(err)
+	if !errors.Is(mapped, usecase.ErrCartAdvertUnavailable) {
+		return mapped
+	}
+
+	unavailable := &usecase.UnavailableAdvertsError{}
+	for _, violation := range grpcerr.PreconditionViolations(err) {
+		advertID, parseErr := uuid.Parse(violation.GetSubject())
+		if parseErr != nil {
+			continue
+		}
+		unavailable.Adverts = append(unavailable.Adverts, dto.UnavailableAdvert{
+			AdvertID: advertID,
+			Status:   entity.AdvertStatus(violation.GetDescription()),
+			Reason:   dto.UnavailableReason(violation.GetType()),
+		})
+	}
+	if len(unavailable.Adverts) == 0 {
+		return mapped
+	}
+	return unavailable
+}
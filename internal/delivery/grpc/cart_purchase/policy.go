@@ -0,0 +1,63 @@
+package cart_purchase
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// callerMetadataKey - ключ метаданных gRPC с пользователем, от имени которого вызывается сервис
+const callerMetadataKey = "x-user-id"
+
+// WithCaller передает сервису корзин пользователя сессии. Сервер не доверяет идентификаторам
+// пользователей в запросах и сверяет их с этим пользователем
+func WithCaller(ctx context.Context, userID uuid.UUID) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, callerMetadataKey, userID.String())
+}
+
+// callerID возвращает пользователя, переданного клиентом через WithCaller
+func callerID(ctx context.Context) (uuid.UUID, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(callerMetadataKey)
+	if len(values) != 1 {
+		return uuid.Nil, ErrCallerUnauthenticated
+	}
+
+	userID, err := uuid.Parse(values[0])
+	if err != nil {
+		return uuid.Nil, errors.Wrap(ErrCallerUnauthenticated, err.Error())
+	}
+	return userID, nil
+}
+
+// authorizeUser проверяет, что вызов касается данных самого пользователя
+func (s *GrpcServer) authorizeUser(ctx context.Context, userID uuid.UUID) error {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+	if caller != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeCart возвращает корзину, если она принадлежит пользователю, от имени которого сделан вызов
+func (s *GrpcServer) authorizeCart(ctx context.Context, cartID uuid.UUID) (dto.Cart, error) {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return dto.Cart{}, err
+	}
+
+	cart, err := s.cartUC.GetById(ctx, cartID)
+	if err != nil {
+		return dto.Cart{}, errors.Wrap(err, "failed to get cart")
+	}
+	if cart.UserID != caller {
+		return dto.Cart{}, ErrForbidden
+	}
+	return cart, nil
+}
@@ -584,21 +584,307 @@ func (x *CheckCartExistsResponse) GetCartId() string {
 	return ""
 }
 
+type MergeCartRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId    string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AdvertIds []string `protobuf:"bytes,2,rep,name=advert_ids,json=advertIds,proto3" json:"advert_ids,omitempty"`
+}
+
+func (x *MergeCartRequest) Reset() {
+	*x = MergeCartRequest{}
+	mi := &file_cart_purchase_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartRequest) ProtoMessage() {}
+
+func (x *MergeCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_purchase_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartRequest.ProtoReflect.Descriptor instead.
+func (*MergeCartRequest) Descriptor() ([]byte, []int) {
+	return file_cart_purchase_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MergeCartRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MergeCartRequest) GetAdvertIds() []string {
+	if x != nil {
+		return x.AdvertIds
+	}
+	return nil
+}
+
+type UnavailableAdvert struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AdvertId string       `protobuf:"bytes,1,opt,name=advert_id,json=advertId,proto3" json:"advert_id,omitempty"`
+	Status   AdvertStatus `protobuf:"varint,2,opt,name=status,proto3,enum=cart_purchase.AdvertStatus" json:"status,omitempty"`
+	Reason   string       `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *UnavailableAdvert) Reset() {
+	*x = UnavailableAdvert{}
+	mi := &file_cart_purchase_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnavailableAdvert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnavailableAdvert) ProtoMessage() {}
+
+func (x *UnavailableAdvert) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_purchase_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnavailableAdvert.ProtoReflect.Descriptor instead.
+func (*UnavailableAdvert) Descriptor() ([]byte, []int) {
+	return file_cart_purchase_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UnavailableAdvert) GetAdvertId() string {
+	if x != nil {
+		return x.AdvertId
+	}
+	return ""
+}
+
+func (x *UnavailableAdvert) GetStatus() AdvertStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AdvertStatus_ADVERT_STATUS_ACTIVE
+}
+
+func (x *UnavailableAdvert) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type MergeCartResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CartId             string               `protobuf:"bytes,1,opt,name=cart_id,json=cartId,proto3" json:"cart_id,omitempty"`
+	AddedAdvertIds     []string             `protobuf:"bytes,2,rep,name=added_advert_ids,json=addedAdvertIds,proto3" json:"added_advert_ids,omitempty"`
+	DuplicateAdvertIds []string             `protobuf:"bytes,3,rep,name=duplicate_advert_ids,json=duplicateAdvertIds,proto3" json:"duplicate_advert_ids,omitempty"`
+	Unavailable        []*UnavailableAdvert `protobuf:"bytes,4,rep,name=unavailable,proto3" json:"unavailable,omitempty"`
+}
+
+func (x *MergeCartResponse) Reset() {
+	*x = MergeCartResponse{}
+	mi := &file_cart_purchase_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeCartResponse) ProtoMessage() {}
+
+func (x *MergeCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_purchase_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeCartResponse.ProtoReflect.Descriptor instead.
+func (*MergeCartResponse) Descriptor() ([]byte, []int) {
+	return file_cart_purchase_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MergeCartResponse) GetCartId() string {
+	if x != nil {
+		return x.CartId
+	}
+	return ""
+}
+
+func (x *MergeCartResponse) GetAddedAdvertIds() []string {
+	if x != nil {
+		return x.AddedAdvertIds
+	}
+	return nil
+}
+
+func (x *MergeCartResponse) GetDuplicateAdvertIds() []string {
+	if x != nil {
+		return x.DuplicateAdvertIds
+	}
+	return nil
+}
+
+func (x *MergeCartResponse) GetUnavailable() []*UnavailableAdvert {
+	if x != nil {
+		return x.Unavailable
+	}
+	return nil
+}
+
+type PurchaseAddress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AddressId     string `protobuf:"bytes,1,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	RecipientName string `protobuf:"bytes,2,opt,name=recipient_name,json=recipientName,proto3" json:"recipient_name,omitempty"`
+	Phone         string `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	City          string `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Street        string `protobuf:"bytes,5,opt,name=street,proto3" json:"street,omitempty"`
+	Building      string `protobuf:"bytes,6,opt,name=building,proto3" json:"building,omitempty"`
+	Apartment     string `protobuf:"bytes,7,opt,name=apartment,proto3" json:"apartment,omitempty"`
+	Postcode      string `protobuf:"bytes,8,opt,name=postcode,proto3" json:"postcode,omitempty"`
+}
+
+func (x *PurchaseAddress) Reset() {
+	*x = PurchaseAddress{}
+	mi := &file_cart_purchase_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurchaseAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurchaseAddress) ProtoMessage() {}
+
+func (x *PurchaseAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_purchase_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurchaseAddress.ProtoReflect.Descriptor instead.
+func (*PurchaseAddress) Descriptor() ([]byte, []int) {
+	return file_cart_purchase_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PurchaseAddress) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetRecipientName() string {
+	if x != nil {
+		return x.RecipientName
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetStreet() string {
+	if x != nil {
+		return x.Street
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetBuilding() string {
+	if x != nil {
+		return x.Building
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetApartment() string {
+	if x != nil {
+		return x.Apartment
+	}
+	return ""
+}
+
+func (x *PurchaseAddress) GetPostcode() string {
+	if x != nil {
+		return x.Postcode
+	}
+	return ""
+}
+
 type AddPurchaseRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	CartId         string         `protobuf:"bytes,1,opt,name=cart_id,json=cartId,proto3" json:"cart_id,omitempty"`
-	Address        string         `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
-	PaymentMethod  PaymentMethod  `protobuf:"varint,3,opt,name=payment_method,json=paymentMethod,proto3,enum=cart_purchase.PaymentMethod" json:"payment_method,omitempty"`
-	DeliveryMethod DeliveryMethod `protobuf:"varint,4,opt,name=delivery_method,json=deliveryMethod,proto3,enum=cart_purchase.DeliveryMethod" json:"delivery_method,omitempty"`
-	UserId         string         `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CartId          string           `protobuf:"bytes,1,opt,name=cart_id,json=cartId,proto3" json:"cart_id,omitempty"`
+	Address         string           `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	PaymentMethod   PaymentMethod    `protobuf:"varint,3,opt,name=payment_method,json=paymentMethod,proto3,enum=cart_purchase.PaymentMethod" json:"payment_method,omitempty"`
+	DeliveryMethod  DeliveryMethod   `protobuf:"varint,4,opt,name=delivery_method,json=deliveryMethod,proto3,enum=cart_purchase.DeliveryMethod" json:"delivery_method,omitempty"`
+	UserId          string           `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SafeDeal        bool             `protobuf:"varint,6,opt,name=safe_deal,json=safeDeal,proto3" json:"safe_deal,omitempty"`
+	PurchaseAddress *PurchaseAddress `protobuf:"bytes,7,opt,name=purchase_address,json=purchaseAddress,proto3" json:"purchase_address,omitempty"`
 }
 
 func (x *AddPurchaseRequest) Reset() {
 	*x = AddPurchaseRequest{}
-	mi := &file_cart_purchase_proto_msgTypes[7]
+	mi := &file_cart_purchase_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -610,7 +896,7 @@ func (x *AddPurchaseRequest) String() string {
 func (*AddPurchaseRequest) ProtoMessage() {}
 
 func (x *AddPurchaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[7]
+	mi := &file_cart_purchase_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -623,7 +909,7 @@ func (x *AddPurchaseRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddPurchaseRequest.ProtoReflect.Descriptor instead.
 func (*AddPurchaseRequest) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{7}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *AddPurchaseRequest) GetCartId() string {
@@ -661,6 +947,20 @@ func (x *AddPurchaseRequest) GetUserId() string {
 	return ""
 }
 
+func (x *AddPurchaseRequest) GetSafeDeal() bool {
+	if x != nil {
+		return x.SafeDeal
+	}
+	return false
+}
+
+func (x *AddPurchaseRequest) GetPurchaseAddress() *PurchaseAddress {
+	if x != nil {
+		return x.PurchaseAddress
+	}
+	return nil
+}
+
 type AddPurchaseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -672,11 +972,12 @@ type AddPurchaseResponse struct {
 	Status         PurchaseStatus `protobuf:"varint,4,opt,name=status,proto3,enum=cart_purchase.PurchaseStatus" json:"status,omitempty"`
 	PaymentMethod  PaymentMethod  `protobuf:"varint,5,opt,name=payment_method,json=paymentMethod,proto3,enum=cart_purchase.PaymentMethod" json:"payment_method,omitempty"`
 	DeliveryMethod DeliveryMethod `protobuf:"varint,6,opt,name=delivery_method,json=deliveryMethod,proto3,enum=cart_purchase.DeliveryMethod" json:"delivery_method,omitempty"`
+	SafeDeal       bool           `protobuf:"varint,7,opt,name=safe_deal,json=safeDeal,proto3" json:"safe_deal,omitempty"`
 }
 
 func (x *AddPurchaseResponse) Reset() {
 	*x = AddPurchaseResponse{}
-	mi := &file_cart_purchase_proto_msgTypes[8]
+	mi := &file_cart_purchase_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -688,7 +989,7 @@ func (x *AddPurchaseResponse) String() string {
 func (*AddPurchaseResponse) ProtoMessage() {}
 
 func (x *AddPurchaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[8]
+	mi := &file_cart_purchase_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -701,7 +1002,7 @@ func (x *AddPurchaseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddPurchaseResponse.ProtoReflect.Descriptor instead.
 func (*AddPurchaseResponse) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{8}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *AddPurchaseResponse) GetId() string {
@@ -746,6 +1047,13 @@ func (x *AddPurchaseResponse) GetDeliveryMethod() DeliveryMethod {
 	return DeliveryMethod_DELIVERY_METHOD_PICKUP
 }
 
+func (x *AddPurchaseResponse) GetSafeDeal() bool {
+	if x != nil {
+		return x.SafeDeal
+	}
+	return false
+}
+
 type GetPurchasesByUserIDRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -756,7 +1064,7 @@ type GetPurchasesByUserIDRequest struct {
 
 func (x *GetPurchasesByUserIDRequest) Reset() {
 	*x = GetPurchasesByUserIDRequest{}
-	mi := &file_cart_purchase_proto_msgTypes[9]
+	mi := &file_cart_purchase_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -768,7 +1076,7 @@ func (x *GetPurchasesByUserIDRequest) String() string {
 func (*GetPurchasesByUserIDRequest) ProtoMessage() {}
 
 func (x *GetPurchasesByUserIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[9]
+	mi := &file_cart_purchase_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -781,7 +1089,7 @@ func (x *GetPurchasesByUserIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPurchasesByUserIDRequest.ProtoReflect.Descriptor instead.
 func (*GetPurchasesByUserIDRequest) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{9}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetPurchasesByUserIDRequest) GetUserId() string {
@@ -801,7 +1109,7 @@ type GetPurchasesByUserIDResponse struct {
 
 func (x *GetPurchasesByUserIDResponse) Reset() {
 	*x = GetPurchasesByUserIDResponse{}
-	mi := &file_cart_purchase_proto_msgTypes[10]
+	mi := &file_cart_purchase_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -813,7 +1121,7 @@ func (x *GetPurchasesByUserIDResponse) String() string {
 func (*GetPurchasesByUserIDResponse) ProtoMessage() {}
 
 func (x *GetPurchasesByUserIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[10]
+	mi := &file_cart_purchase_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -826,7 +1134,7 @@ func (x *GetPurchasesByUserIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPurchasesByUserIDResponse.ProtoReflect.Descriptor instead.
 func (*GetPurchasesByUserIDResponse) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{10}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetPurchasesByUserIDResponse) GetPurchases() []*PurchaseResponse {
@@ -846,7 +1154,7 @@ type GetCartByIDRequest struct {
 
 func (x *GetCartByIDRequest) Reset() {
 	*x = GetCartByIDRequest{}
-	mi := &file_cart_purchase_proto_msgTypes[11]
+	mi := &file_cart_purchase_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -858,7 +1166,7 @@ func (x *GetCartByIDRequest) String() string {
 func (*GetCartByIDRequest) ProtoMessage() {}
 
 func (x *GetCartByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[11]
+	mi := &file_cart_purchase_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -871,7 +1179,7 @@ func (x *GetCartByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCartByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetCartByIDRequest) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{11}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetCartByIDRequest) GetCartId() string {
@@ -891,7 +1199,7 @@ type GetCartByIDResponse struct {
 
 func (x *GetCartByIDResponse) Reset() {
 	*x = GetCartByIDResponse{}
-	mi := &file_cart_purchase_proto_msgTypes[12]
+	mi := &file_cart_purchase_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -903,7 +1211,7 @@ func (x *GetCartByIDResponse) String() string {
 func (*GetCartByIDResponse) ProtoMessage() {}
 
 func (x *GetCartByIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[12]
+	mi := &file_cart_purchase_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -916,7 +1224,7 @@ func (x *GetCartByIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCartByIDResponse.ProtoReflect.Descriptor instead.
 func (*GetCartByIDResponse) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{12}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetCartByIDResponse) GetCart() *Cart {
@@ -936,7 +1244,7 @@ type GetCartByUserIDRequest struct {
 
 func (x *GetCartByUserIDRequest) Reset() {
 	*x = GetCartByUserIDRequest{}
-	mi := &file_cart_purchase_proto_msgTypes[13]
+	mi := &file_cart_purchase_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -948,7 +1256,7 @@ func (x *GetCartByUserIDRequest) String() string {
 func (*GetCartByUserIDRequest) ProtoMessage() {}
 
 func (x *GetCartByUserIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[13]
+	mi := &file_cart_purchase_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -961,7 +1269,7 @@ func (x *GetCartByUserIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCartByUserIDRequest.ProtoReflect.Descriptor instead.
 func (*GetCartByUserIDRequest) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{13}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetCartByUserIDRequest) GetUserId() string {
@@ -981,7 +1289,7 @@ type GetCartByUserIDResponse struct {
 
 func (x *GetCartByUserIDResponse) Reset() {
 	*x = GetCartByUserIDResponse{}
-	mi := &file_cart_purchase_proto_msgTypes[14]
+	mi := &file_cart_purchase_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -993,7 +1301,7 @@ func (x *GetCartByUserIDResponse) String() string {
 func (*GetCartByUserIDResponse) ProtoMessage() {}
 
 func (x *GetCartByUserIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[14]
+	mi := &file_cart_purchase_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1006,7 +1314,7 @@ func (x *GetCartByUserIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCartByUserIDResponse.ProtoReflect.Descriptor instead.
 func (*GetCartByUserIDResponse) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{14}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetCartByUserIDResponse) GetCart() *Cart {
@@ -1034,7 +1342,7 @@ type PreviewAdvert struct {
 
 func (x *PreviewAdvert) Reset() {
 	*x = PreviewAdvert{}
-	mi := &file_cart_purchase_proto_msgTypes[15]
+	mi := &file_cart_purchase_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1046,7 +1354,7 @@ func (x *PreviewAdvert) String() string {
 func (*PreviewAdvert) ProtoMessage() {}
 
 func (x *PreviewAdvert) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[15]
+	mi := &file_cart_purchase_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1059,7 +1367,7 @@ func (x *PreviewAdvert) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PreviewAdvert.ProtoReflect.Descriptor instead.
 func (*PreviewAdvert) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{15}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *PreviewAdvert) GetAdvertId() string {
@@ -1137,7 +1445,7 @@ type PreviewAdvertCard struct {
 
 func (x *PreviewAdvertCard) Reset() {
 	*x = PreviewAdvertCard{}
-	mi := &file_cart_purchase_proto_msgTypes[16]
+	mi := &file_cart_purchase_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1149,7 +1457,7 @@ func (x *PreviewAdvertCard) String() string {
 func (*PreviewAdvertCard) ProtoMessage() {}
 
 func (x *PreviewAdvertCard) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[16]
+	mi := &file_cart_purchase_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1162,7 +1470,7 @@ func (x *PreviewAdvertCard) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PreviewAdvertCard.ProtoReflect.Descriptor instead.
 func (*PreviewAdvertCard) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{16}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *PreviewAdvertCard) GetPreview() *PreviewAdvert {
@@ -1199,7 +1507,7 @@ type Cart struct {
 
 func (x *Cart) Reset() {
 	*x = Cart{}
-	mi := &file_cart_purchase_proto_msgTypes[17]
+	mi := &file_cart_purchase_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1211,7 +1519,7 @@ func (x *Cart) String() string {
 func (*Cart) ProtoMessage() {}
 
 func (x *Cart) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[17]
+	mi := &file_cart_purchase_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1224,7 +1532,7 @@ func (x *Cart) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Cart.ProtoReflect.Descriptor instead.
 func (*Cart) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{17}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *Cart) GetId() string {
@@ -1266,11 +1574,12 @@ type PurchaseResponse struct {
 	Status         PurchaseStatus `protobuf:"varint,4,opt,name=status,proto3,enum=cart_purchase.PurchaseStatus" json:"status,omitempty"`
 	PaymentMethod  PaymentMethod  `protobuf:"varint,5,opt,name=payment_method,json=paymentMethod,proto3,enum=cart_purchase.PaymentMethod" json:"payment_method,omitempty"`
 	DeliveryMethod DeliveryMethod `protobuf:"varint,6,opt,name=delivery_method,json=deliveryMethod,proto3,enum=cart_purchase.DeliveryMethod" json:"delivery_method,omitempty"`
+	SafeDeal       bool           `protobuf:"varint,7,opt,name=safe_deal,json=safeDeal,proto3" json:"safe_deal,omitempty"`
 }
 
 func (x *PurchaseResponse) Reset() {
 	*x = PurchaseResponse{}
-	mi := &file_cart_purchase_proto_msgTypes[18]
+	mi := &file_cart_purchase_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1282,7 +1591,7 @@ func (x *PurchaseResponse) String() string {
 func (*PurchaseResponse) ProtoMessage() {}
 
 func (x *PurchaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cart_purchase_proto_msgTypes[18]
+	mi := &file_cart_purchase_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1295,7 +1604,7 @@ func (x *PurchaseResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PurchaseResponse.ProtoReflect.Descriptor instead.
 func (*PurchaseResponse) Descriptor() ([]byte, []int) {
-	return file_cart_purchase_proto_rawDescGZIP(), []int{18}
+	return file_cart_purchase_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *PurchaseResponse) GetId() string {
@@ -1340,6 +1649,13 @@ func (x *PurchaseResponse) GetDeliveryMethod() DeliveryMethod {
 	return DeliveryMethod_DELIVERY_METHOD_PICKUP
 }
 
+func (x *PurchaseResponse) GetSafeDeal() bool {
+	if x != nil {
+		return x.SafeDeal
+	}
+	return false
+}
+
 var File_cart_purchase_proto protoreflect.FileDescriptor
 
 var file_cart_purchase_proto_rawDesc = []byte{
@@ -1369,198 +1685,254 @@ var file_cart_purchase_proto_rawDesc = []byte{
 	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x32, 0x0a, 0x17, 0x43, 0x68, 0x65, 0x63,
 	0x6b, 0x43, 0x61, 0x72, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
 	0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x22, 0xed, 0x01, 0x0a,
-	0x12, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x43, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c,
-	0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x46, 0x0a, 0x0f, 0x64,
-	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63,
-	0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74,
-	0x68, 0x6f, 0x64, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74,
-	0x68, 0x6f, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x9c, 0x02, 0x0a,
-	0x13, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a,
-	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
-	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x43,
-	0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75,
-	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65,
-	0x74, 0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74,
-	0x68, 0x6f, 0x64, 0x12, 0x46, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f,
-	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63,
-	0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c,
-	0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x0e, 0x64, 0x65, 0x6c,
-	0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x22, 0x36, 0x0a, 0x1b, 0x47,
-	0x65, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73,
-	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x64, 0x22, 0x5d, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61,
-	0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x09, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75,
-	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73,
-	0x65, 0x73, 0x22, 0x2d, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x49,
-	0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x72, 0x74,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49,
-	0x64, 0x22, 0x3e, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x49, 0x44,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75,
-	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x74, 0x52, 0x04, 0x63, 0x61, 0x72,
-	0x74, 0x22, 0x31, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x55, 0x73,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x22, 0x4a, 0x0a, 0x10,
+	0x4d, 0x65, 0x72, 0x67, 0x65, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x64, 0x76,
+	0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61,
+	0x64, 0x76, 0x65, 0x72, 0x74, 0x49, 0x64, 0x73, 0x22, 0x7d, 0x0a, 0x11, 0x55, 0x6e, 0x61, 0x76,
+	0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x76, 0x65, 0x72,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xcc, 0x01, 0x0a, 0x11, 0x4d, 0x65, 0x72, 0x67,
+	0x65, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a,
+	0x07, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x64, 0x64, 0x65, 0x64, 0x5f,
+	0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0e, 0x61, 0x64, 0x64, 0x65, 0x64, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x49, 0x64, 0x73,
+	0x12, 0x30, 0x0a, 0x14, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x61, 0x64,
+	0x76, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x12,
+	0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x49,
+	0x64, 0x73, 0x12, 0x42, 0x0a, 0x0b, 0x75, 0x6e, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x55, 0x6e, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x52, 0x0b, 0x75, 0x6e, 0x61, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x22, 0xef, 0x01, 0x0a, 0x0f, 0x50, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x63,
+	0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x70, 0x68, 0x6f, 0x6e, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x72, 0x65, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65,
+	0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1c,
+	0x0a, 0x09, 0x61, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x70, 0x61, 0x72, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x6f, 0x73, 0x74, 0x63, 0x6f, 0x64, 0x65, 0x22, 0xd5, 0x02, 0x0a, 0x12, 0x41, 0x64, 0x64,
+	0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x43, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x46, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52,
+	0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x61, 0x66, 0x65,
+	0x5f, 0x64, 0x65, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x61, 0x66,
+	0x65, 0x44, 0x65, 0x61, 0x6c, 0x12, 0x49, 0x0a, 0x10, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73,
+	0x65, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e,
+	0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52,
+	0x0f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x22, 0xb9, 0x02, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x72, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74, 0x49,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x35, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x61,
+	0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x43, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x46, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52,
+	0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x61, 0x66, 0x65, 0x5f, 0x64, 0x65, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x08, 0x73, 0x61, 0x66, 0x65, 0x44, 0x65, 0x61, 0x6c, 0x22, 0x36, 0x0a, 0x1b,
+	0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73,
 	0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75,
 	0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73,
-	0x65, 0x72, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42,
-	0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x27, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
-	0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61,
-	0x72, 0x74, 0x52, 0x04, 0x63, 0x61, 0x72, 0x74, 0x22, 0xa5, 0x02, 0x0a, 0x0d, 0x50, 0x72, 0x65,
-	0x76, 0x69, 0x65, 0x77, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x64,
-	0x76, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61,
-	0x64, 0x76, 0x65, 0x72, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6c, 0x6c, 0x65,
-	0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x6c,
-	0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79,
-	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x74, 0x65, 0x67,
-	0x6f, 0x72, 0x79, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
-	0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63,
-	0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x63,
-	0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x76,
-	0x65, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a,
-	0x0c, 0x68, 0x61, 0x73, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
-	0x22, 0x83, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x41, 0x64, 0x76, 0x65,
-	0x72, 0x74, 0x43, 0x61, 0x72, 0x64, 0x12, 0x36, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65,
-	0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
-	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x41,
-	0x64, 0x76, 0x65, 0x72, 0x74, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x19,
-	0x0a, 0x08, 0x69, 0x73, 0x5f, 0x73, 0x61, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x69, 0x73, 0x53, 0x61, 0x76, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73, 0x5f,
-	0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73,
-	0x56, 0x69, 0x65, 0x77, 0x65, 0x64, 0x22, 0x9e, 0x01, 0x0a, 0x04, 0x43, 0x61, 0x72, 0x74, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x07, 0x61, 0x64, 0x76, 0x65,
-	0x72, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x61, 0x72, 0x74,
-	0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65,
-	0x77, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x43, 0x61, 0x72, 0x64, 0x52, 0x07, 0x61, 0x64, 0x76,
-	0x65, 0x72, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63,
-	0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x99, 0x02, 0x0a, 0x10, 0x50, 0x75, 0x72, 0x63,
-	0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07,
-	0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
-	0x61, 0x72, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
-	0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e,
-	0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x43, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c,
-	0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x46, 0x0a, 0x0f, 0x64,
-	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63,
-	0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74,
-	0x68, 0x6f, 0x64, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65, 0x74,
-	0x68, 0x6f, 0x64, 0x2a, 0x8b, 0x01, 0x0a, 0x0e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41,
-	0x53, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e,
-	0x47, 0x10, 0x00, 0x12, 0x1f, 0x0a, 0x1b, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45, 0x5f,
-	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45,
-	0x53, 0x53, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45,
-	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45,
-	0x44, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45, 0x5f,
-	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10,
-	0x03, 0x2a, 0x41, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68,
-	0x6f, 0x64, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45,
-	0x54, 0x48, 0x4f, 0x44, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x50,
-	0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x43, 0x41,
-	0x53, 0x48, 0x10, 0x01, 0x2a, 0x4a, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
-	0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x1a, 0x0a, 0x16, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45,
-	0x52, 0x59, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x50, 0x49, 0x43, 0x4b, 0x55, 0x50,
-	0x10, 0x00, 0x12, 0x1c, 0x0a, 0x18, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x4d,
-	0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x10, 0x01,
-	0x2a, 0x57, 0x0a, 0x0a, 0x43, 0x61, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16,
-	0x0a, 0x12, 0x43, 0x41, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43,
-	0x54, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x41, 0x52, 0x54, 0x5f, 0x53,
-	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01,
-	0x12, 0x17, 0x0a, 0x13, 0x43, 0x41, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
-	0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x60, 0x0a, 0x0c, 0x41, 0x64, 0x76,
-	0x65, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x44, 0x56,
-	0x45, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56,
-	0x45, 0x10, 0x00, 0x12, 0x1a, 0x0a, 0x16, 0x41, 0x44, 0x56, 0x45, 0x52, 0x54, 0x5f, 0x53, 0x54,
-	0x41, 0x54, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x12,
-	0x1a, 0x0a, 0x16, 0x41, 0x44, 0x56, 0x45, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
-	0x5f, 0x52, 0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x44, 0x10, 0x02, 0x32, 0x85, 0x06, 0x0a, 0x13,
-	0x43, 0x61, 0x72, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x54, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61,
-	0x73, 0x65, 0x12, 0x21, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61,
-	0x73, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72,
-	0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x14, 0x47, 0x65, 0x74,
-	0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49,
-	0x44, 0x12, 0x2a, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73,
-	0x65, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79,
-	0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e,
-	0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65,
-	0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72,
-	0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0b, 0x47, 0x65,
-	0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x21, 0x2e, 0x63, 0x61, 0x72, 0x74,
-	0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72,
-	0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63,
-	0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74,
-	0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x60, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68,
-	0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63, 0x61, 0x72,
+	0x65, 0x72, 0x49, 0x64, 0x22, 0x5d, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x09, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x73, 0x65, 0x73, 0x22, 0x2d, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79,
+	0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x72, 0x74,
+	0x49, 0x64, 0x22, 0x3e, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x49,
+	0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x04, 0x63, 0x61, 0x72,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x74, 0x52, 0x04, 0x63, 0x61,
+	0x72, 0x74, 0x22, 0x31, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
+	0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74,
+	0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x27, 0x0a, 0x04, 0x63, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43,
+	0x61, 0x72, 0x74, 0x52, 0x04, 0x63, 0x61, 0x72, 0x74, 0x22, 0xa5, 0x02, 0x0a, 0x0d, 0x50, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61,
+	0x64, 0x76, 0x65, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x61, 0x64, 0x76, 0x65, 0x72, 0x74, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6c, 0x6c,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c,
+	0x6c, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x74, 0x65,
+	0x67, 0x6f, 0x72, 0x79, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x70, 0x72, 0x69,
+	0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x49, 0x64, 0x12, 0x33, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e,
+	0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64,
+	0x76, 0x65, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21,
+	0x0a, 0x0c, 0x68, 0x61, 0x73, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x68, 0x61, 0x73, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x22, 0x83, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x41, 0x64, 0x76,
+	0x65, 0x72, 0x74, 0x43, 0x61, 0x72, 0x64, 0x12, 0x36, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f,
+	0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12,
+	0x19, 0x0a, 0x08, 0x69, 0x73, 0x5f, 0x73, 0x61, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x69, 0x73, 0x53, 0x61, 0x76, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73,
+	0x5f, 0x76, 0x69, 0x65, 0x77, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69,
+	0x73, 0x56, 0x69, 0x65, 0x77, 0x65, 0x64, 0x22, 0x9e, 0x01, 0x0a, 0x04, 0x43, 0x61, 0x72, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x3a, 0x0a, 0x07, 0x61, 0x64, 0x76,
+	0x65, 0x72, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x43, 0x61, 0x72, 0x64, 0x52, 0x07, 0x61, 0x64,
+	0x76, 0x65, 0x72, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xb6, 0x02, 0x0a, 0x10, 0x50, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x61, 0x72, 0x74, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x12, 0x35, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x2e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x43, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1c, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e,
+	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x52, 0x0d, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x46, 0x0a, 0x0f,
+	0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x61, 0x66, 0x65, 0x5f, 0x64, 0x65, 0x61,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x61, 0x66, 0x65, 0x44, 0x65, 0x61,
+	0x6c, 0x2a, 0x8b, 0x01, 0x0a, 0x0e, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x1b, 0x0a, 0x17, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10,
+	0x00, 0x12, 0x1f, 0x0a, 0x1b, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53,
+	0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x1c, 0x0a, 0x18, 0x50, 0x55, 0x52, 0x43, 0x48, 0x41, 0x53, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x2a,
+	0x41, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64,
+	0x12, 0x17, 0x0a, 0x13, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x48,
+	0x4f, 0x44, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x41, 0x59,
+	0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x43, 0x41, 0x53, 0x48,
+	0x10, 0x01, 0x2a, 0x4a, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x12, 0x1a, 0x0a, 0x16, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59,
+	0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x5f, 0x50, 0x49, 0x43, 0x4b, 0x55, 0x50, 0x10, 0x00,
+	0x12, 0x1c, 0x0a, 0x18, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x4d, 0x45, 0x54,
+	0x48, 0x4f, 0x44, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x10, 0x01, 0x2a, 0x57,
+	0x0a, 0x0a, 0x43, 0x61, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x12,
+	0x43, 0x41, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54, 0x49,
+	0x56, 0x45, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x41, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x12, 0x17,
+	0x0a, 0x13, 0x43, 0x41, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x45,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x60, 0x0a, 0x0c, 0x41, 0x64, 0x76, 0x65, 0x72,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x44, 0x56, 0x45, 0x52,
+	0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10,
+	0x00, 0x12, 0x1a, 0x0a, 0x16, 0x41, 0x44, 0x56, 0x45, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x49, 0x4e, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x01, 0x12, 0x1a, 0x0a,
+	0x16, 0x41, 0x44, 0x56, 0x45, 0x52, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52,
+	0x45, 0x53, 0x45, 0x52, 0x56, 0x45, 0x44, 0x10, 0x02, 0x32, 0xd5, 0x06, 0x0a, 0x13, 0x43, 0x61,
+	0x72, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x54, 0x0a, 0x0b, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x12, 0x21, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65,
+	0x2e, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x50, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12,
+	0x2a, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e,
+	0x47, 0x65, 0x74, 0x50, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x63, 0x61,
+	0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x50,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x73, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43,
+	0x61, 0x72, 0x74, 0x42, 0x79, 0x49, 0x44, 0x12, 0x21, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42,
+	0x79, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x61, 0x72,
 	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61,
-	0x72, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x60, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x54,
-	0x6f, 0x43, 0x61, 0x72, 0x74, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72,
-	0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x54,
-	0x6f, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63,
-	0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x64,
-	0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x54, 0x6f, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x64,
-	0x76, 0x65, 0x72, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x12, 0x2a, 0x2e, 0x63,
-	0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f,
-	0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41,
-	0x64, 0x76, 0x65, 0x72, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x61,
-	0x72, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f,
-	0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x61,
-	0x72, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x26, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e,
-	0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x61, 0x72, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12,
-	0x18, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e,
-	0x4e, 0x6f, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x18, 0x2e, 0x63, 0x61, 0x72, 0x74,
-	0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x4e, 0x6f, 0x43, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x42, 0x12, 0x5a, 0x10, 0x2e, 0x2f, 0x3b, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
-	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x74, 0x42, 0x79, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73,
+	0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74, 0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f,
+	0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x74,
+	0x42, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x60, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x54, 0x6f, 0x43,
+	0x61, 0x72, 0x74, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x54, 0x6f, 0x43,
+	0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x64,
+	0x76, 0x65, 0x72, 0x74, 0x54, 0x6f, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x6f, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x64, 0x76, 0x65,
+	0x72, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x12, 0x2a, 0x2e, 0x63, 0x61, 0x72,
+	0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x41, 0x64, 0x76, 0x65, 0x72, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x64, 0x76,
+	0x65, 0x72, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x0f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x61, 0x72, 0x74,
+	0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75,
+	0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x43, 0x61, 0x72, 0x74,
+	0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e,
+	0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x68,
+	0x65, 0x63, 0x6b, 0x43, 0x61, 0x72, 0x74, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x09, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x43, 0x61,
+	0x72, 0x74, 0x12, 0x1f, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61,
+	0x73, 0x65, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68,
+	0x61, 0x73, 0x65, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x43, 0x61, 0x72, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x2e,
+	0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x4e, 0x6f,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x1a, 0x18, 0x2e, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70,
+	0x75, 0x72, 0x63, 0x68, 0x61, 0x73, 0x65, 0x2e, 0x4e, 0x6f, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x42, 0x12, 0x5a, 0x10, 0x2e, 0x2f, 0x3b, 0x63, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x73, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1576,7 +1948,7 @@ func file_cart_purchase_proto_rawDescGZIP() []byte {
 }
 
 var file_cart_purchase_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_cart_purchase_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_cart_purchase_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_cart_purchase_proto_goTypes = []any{
 	(PurchaseStatus)(0),                  // 0: cart_purchase.PurchaseStatus
 	(PaymentMethod)(0),                   // 1: cart_purchase.PaymentMethod
@@ -1590,56 +1962,65 @@ var file_cart_purchase_proto_goTypes = []any{
 	(*DeleteAdvertFromCartResponse)(nil), // 9: cart_purchase.DeleteAdvertFromCartResponse
 	(*CheckCartExistsRequest)(nil),       // 10: cart_purchase.CheckCartExistsRequest
 	(*CheckCartExistsResponse)(nil),      // 11: cart_purchase.CheckCartExistsResponse
-	(*AddPurchaseRequest)(nil),           // 12: cart_purchase.AddPurchaseRequest
-	(*AddPurchaseResponse)(nil),          // 13: cart_purchase.AddPurchaseResponse
-	(*GetPurchasesByUserIDRequest)(nil),  // 14: cart_purchase.GetPurchasesByUserIDRequest
-	(*GetPurchasesByUserIDResponse)(nil), // 15: cart_purchase.GetPurchasesByUserIDResponse
-	(*GetCartByIDRequest)(nil),           // 16: cart_purchase.GetCartByIDRequest
-	(*GetCartByIDResponse)(nil),          // 17: cart_purchase.GetCartByIDResponse
-	(*GetCartByUserIDRequest)(nil),       // 18: cart_purchase.GetCartByUserIDRequest
-	(*GetCartByUserIDResponse)(nil),      // 19: cart_purchase.GetCartByUserIDResponse
-	(*PreviewAdvert)(nil),                // 20: cart_purchase.PreviewAdvert
-	(*PreviewAdvertCard)(nil),            // 21: cart_purchase.PreviewAdvertCard
-	(*Cart)(nil),                         // 22: cart_purchase.Cart
-	(*PurchaseResponse)(nil),             // 23: cart_purchase.PurchaseResponse
+	(*MergeCartRequest)(nil),             // 12: cart_purchase.MergeCartRequest
+	(*UnavailableAdvert)(nil),            // 13: cart_purchase.UnavailableAdvert
+	(*MergeCartResponse)(nil),            // 14: cart_purchase.MergeCartResponse
+	(*PurchaseAddress)(nil),              // 15: cart_purchase.PurchaseAddress
+	(*AddPurchaseRequest)(nil),           // 16: cart_purchase.AddPurchaseRequest
+	(*AddPurchaseResponse)(nil),          // 17: cart_purchase.AddPurchaseResponse
+	(*GetPurchasesByUserIDRequest)(nil),  // 18: cart_purchase.GetPurchasesByUserIDRequest
+	(*GetPurchasesByUserIDResponse)(nil), // 19: cart_purchase.GetPurchasesByUserIDResponse
+	(*GetCartByIDRequest)(nil),           // 20: cart_purchase.GetCartByIDRequest
+	(*GetCartByIDResponse)(nil),          // 21: cart_purchase.GetCartByIDResponse
+	(*GetCartByUserIDRequest)(nil),       // 22: cart_purchase.GetCartByUserIDRequest
+	(*GetCartByUserIDResponse)(nil),      // 23: cart_purchase.GetCartByUserIDResponse
+	(*PreviewAdvert)(nil),                // 24: cart_purchase.PreviewAdvert
+	(*PreviewAdvertCard)(nil),            // 25: cart_purchase.PreviewAdvertCard
+	(*Cart)(nil),                         // 26: cart_purchase.Cart
+	(*PurchaseResponse)(nil),             // 27: cart_purchase.PurchaseResponse
 }
 var file_cart_purchase_proto_depIdxs = []int32{
-	1,  // 0: cart_purchase.AddPurchaseRequest.payment_method:type_name -> cart_purchase.PaymentMethod
-	2,  // 1: cart_purchase.AddPurchaseRequest.delivery_method:type_name -> cart_purchase.DeliveryMethod
-	0,  // 2: cart_purchase.AddPurchaseResponse.status:type_name -> cart_purchase.PurchaseStatus
-	1,  // 3: cart_purchase.AddPurchaseResponse.payment_method:type_name -> cart_purchase.PaymentMethod
-	2,  // 4: cart_purchase.AddPurchaseResponse.delivery_method:type_name -> cart_purchase.DeliveryMethod
-	23, // 5: cart_purchase.GetPurchasesByUserIDResponse.purchases:type_name -> cart_purchase.PurchaseResponse
-	22, // 6: cart_purchase.GetCartByIDResponse.cart:type_name -> cart_purchase.Cart
-	22, // 7: cart_purchase.GetCartByUserIDResponse.cart:type_name -> cart_purchase.Cart
-	4,  // 8: cart_purchase.PreviewAdvert.status:type_name -> cart_purchase.AdvertStatus
-	20, // 9: cart_purchase.PreviewAdvertCard.preview:type_name -> cart_purchase.PreviewAdvert
-	21, // 10: cart_purchase.Cart.adverts:type_name -> cart_purchase.PreviewAdvertCard
-	3,  // 11: cart_purchase.Cart.status:type_name -> cart_purchase.CartStatus
-	0,  // 12: cart_purchase.PurchaseResponse.status:type_name -> cart_purchase.PurchaseStatus
-	1,  // 13: cart_purchase.PurchaseResponse.payment_method:type_name -> cart_purchase.PaymentMethod
-	2,  // 14: cart_purchase.PurchaseResponse.delivery_method:type_name -> cart_purchase.DeliveryMethod
-	12, // 15: cart_purchase.CartPurchaseService.AddPurchase:input_type -> cart_purchase.AddPurchaseRequest
-	14, // 16: cart_purchase.CartPurchaseService.GetPurchasesByUserID:input_type -> cart_purchase.GetPurchasesByUserIDRequest
-	16, // 17: cart_purchase.CartPurchaseService.GetCartByID:input_type -> cart_purchase.GetCartByIDRequest
-	18, // 18: cart_purchase.CartPurchaseService.GetCartByUserID:input_type -> cart_purchase.GetCartByUserIDRequest
-	6,  // 19: cart_purchase.CartPurchaseService.AddAdvertToCart:input_type -> cart_purchase.AddAdvertToCartRequest
-	8,  // 20: cart_purchase.CartPurchaseService.DeleteAdvertFromCart:input_type -> cart_purchase.DeleteAdvertFromCartRequest
-	10, // 21: cart_purchase.CartPurchaseService.CheckCartExists:input_type -> cart_purchase.CheckCartExistsRequest
-	5,  // 22: cart_purchase.CartPurchaseService.Ping:input_type -> cart_purchase.NoContent
-	13, // 23: cart_purchase.CartPurchaseService.AddPurchase:output_type -> cart_purchase.AddPurchaseResponse
-	15, // 24: cart_purchase.CartPurchaseService.GetPurchasesByUserID:output_type -> cart_purchase.GetPurchasesByUserIDResponse
-	17, // 25: cart_purchase.CartPurchaseService.GetCartByID:output_type -> cart_purchase.GetCartByIDResponse
-	19, // 26: cart_purchase.CartPurchaseService.GetCartByUserID:output_type -> cart_purchase.GetCartByUserIDResponse
-	7,  // 27: cart_purchase.CartPurchaseService.AddAdvertToCart:output_type -> cart_purchase.AddAdvertToCartResponse
-	9,  // 28: cart_purchase.CartPurchaseService.DeleteAdvertFromCart:output_type -> cart_purchase.DeleteAdvertFromCartResponse
-	11, // 29: cart_purchase.CartPurchaseService.CheckCartExists:output_type -> cart_purchase.CheckCartExistsResponse
-	5,  // 30: cart_purchase.CartPurchaseService.Ping:output_type -> cart_purchase.NoContent
-	23, // [23:31] is the sub-list for method output_type
-	15, // [15:23] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	4,  // 0: cart_purchase.UnavailableAdvert.status:type_name -> cart_purchase.AdvertStatus
+	13, // 1: cart_purchase.MergeCartResponse.unavailable:type_name -> cart_purchase.UnavailableAdvert
+	1,  // 2: cart_purchase.AddPurchaseRequest.payment_method:type_name -> cart_purchase.PaymentMethod
+	2,  // 3: cart_purchase.AddPurchaseRequest.delivery_method:type_name -> cart_purchase.DeliveryMethod
+	15, // 4: cart_purchase.AddPurchaseRequest.purchase_address:type_name -> cart_purchase.PurchaseAddress
+	0,  // 5: cart_purchase.AddPurchaseResponse.status:type_name -> cart_purchase.PurchaseStatus
+	1,  // 6: cart_purchase.AddPurchaseResponse.payment_method:type_name -> cart_purchase.PaymentMethod
+	2,  // 7: cart_purchase.AddPurchaseResponse.delivery_method:type_name -> cart_purchase.DeliveryMethod
+	27, // 8: cart_purchase.GetPurchasesByUserIDResponse.purchases:type_name -> cart_purchase.PurchaseResponse
+	26, // 9: cart_purchase.GetCartByIDResponse.cart:type_name -> cart_purchase.Cart
+	26, // 10: cart_purchase.GetCartByUserIDResponse.cart:type_name -> cart_purchase.Cart
+	4,  // 11: cart_purchase.PreviewAdvert.status:type_name -> cart_purchase.AdvertStatus
+	24, // 12: cart_purchase.PreviewAdvertCard.preview:type_name -> cart_purchase.PreviewAdvert
+	25, // 13: cart_purchase.Cart.adverts:type_name -> cart_purchase.PreviewAdvertCard
+	3,  // 14: cart_purchase.Cart.status:type_name -> cart_purchase.CartStatus
+	0,  // 15: cart_purchase.PurchaseResponse.status:type_name -> cart_purchase.PurchaseStatus
+	1,  // 16: cart_purchase.PurchaseResponse.payment_method:type_name -> cart_purchase.PaymentMethod
+	2,  // 17: cart_purchase.PurchaseResponse.delivery_method:type_name -> cart_purchase.DeliveryMethod
+	16, // 18: cart_purchase.CartPurchaseService.AddPurchase:input_type -> cart_purchase.AddPurchaseRequest
+	18, // 19: cart_purchase.CartPurchaseService.GetPurchasesByUserID:input_type -> cart_purchase.GetPurchasesByUserIDRequest
+	20, // 20: cart_purchase.CartPurchaseService.GetCartByID:input_type -> cart_purchase.GetCartByIDRequest
+	22, // 21: cart_purchase.CartPurchaseService.GetCartByUserID:input_type -> cart_purchase.GetCartByUserIDRequest
+	6,  // 22: cart_purchase.CartPurchaseService.AddAdvertToCart:input_type -> cart_purchase.AddAdvertToCartRequest
+	8,  // 23: cart_purchase.CartPurchaseService.DeleteAdvertFromCart:input_type -> cart_purchase.DeleteAdvertFromCartRequest
+	10, // 24: cart_purchase.CartPurchaseService.CheckCartExists:input_type -> cart_purchase.CheckCartExistsRequest
+	12, // 25: cart_purchase.CartPurchaseService.MergeCart:input_type -> cart_purchase.MergeCartRequest
+	5,  // 26: cart_purchase.CartPurchaseService.Ping:input_type -> cart_purchase.NoContent
+	17, // 27: cart_purchase.CartPurchaseService.AddPurchase:output_type -> cart_purchase.AddPurchaseResponse
+	19, // 28: cart_purchase.CartPurchaseService.GetPurchasesByUserID:output_type -> cart_purchase.GetPurchasesByUserIDResponse
+	21, // 29: cart_purchase.CartPurchaseService.GetCartByID:output_type -> cart_purchase.GetCartByIDResponse
+	23, // 30: cart_purchase.CartPurchaseService.GetCartByUserID:output_type -> cart_purchase.GetCartByUserIDResponse
+	7,  // 31: cart_purchase.CartPurchaseService.AddAdvertToCart:output_type -> cart_purchase.AddAdvertToCartResponse
+	9,  // 32: cart_purchase.CartPurchaseService.DeleteAdvertFromCart:output_type -> cart_purchase.DeleteAdvertFromCartResponse
+	11, // 33: cart_purchase.CartPurchaseService.CheckCartExists:output_type -> cart_purchase.CheckCartExistsResponse
+	14, // 34: cart_purchase.CartPurchaseService.MergeCart:output_type -> cart_purchase.MergeCartResponse
+	5,  // 35: cart_purchase.CartPurchaseService.Ping:output_type -> cart_purchase.NoContent
+	27, // [27:36] is the sub-list for method output_type
+	18, // [18:27] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_cart_purchase_proto_init() }
@@ -1653,7 +2034,7 @@ func file_cart_purchase_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cart_purchase_proto_rawDesc,
 			NumEnums:      5,
-			NumMessages:   19,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
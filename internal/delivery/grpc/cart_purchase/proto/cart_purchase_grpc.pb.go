@@ -26,6 +26,7 @@ const (
 	CartPurchaseService_AddAdvertToCart_FullMethodName      = "/cart_purchase.CartPurchaseService/AddAdvertToCart"
 	CartPurchaseService_DeleteAdvertFromCart_FullMethodName = "/cart_purchase.CartPurchaseService/DeleteAdvertFromCart"
 	CartPurchaseService_CheckCartExists_FullMethodName      = "/cart_purchase.CartPurchaseService/CheckCartExists"
+	CartPurchaseService_MergeCart_FullMethodName            = "/cart_purchase.CartPurchaseService/MergeCart"
 	CartPurchaseService_Ping_FullMethodName                 = "/cart_purchase.CartPurchaseService/Ping"
 )
 
@@ -40,6 +41,7 @@ type CartPurchaseServiceClient interface {
 	AddAdvertToCart(ctx context.Context, in *AddAdvertToCartRequest, opts ...grpc.CallOption) (*AddAdvertToCartResponse, error)
 	DeleteAdvertFromCart(ctx context.Context, in *DeleteAdvertFromCartRequest, opts ...grpc.CallOption) (*DeleteAdvertFromCartResponse, error)
 	CheckCartExists(ctx context.Context, in *CheckCartExistsRequest, opts ...grpc.CallOption) (*CheckCartExistsResponse, error)
+	MergeCart(ctx context.Context, in *MergeCartRequest, opts ...grpc.CallOption) (*MergeCartResponse, error)
 	Ping(ctx context.Context, in *NoContent, opts ...grpc.CallOption) (*NoContent, error)
 }
 
@@ -121,6 +123,16 @@ func (c *cartPurchaseServiceClient) CheckCartExists(ctx context.Context, in *Che
 	return out, nil
 }
 
+func (c *cartPurchaseServiceClient) MergeCart(ctx context.Context, in *MergeCartRequest, opts ...grpc.CallOption) (*MergeCartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeCartResponse)
+	err := c.cc.Invoke(ctx, CartPurchaseService_MergeCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cartPurchaseServiceClient) Ping(ctx context.Context, in *NoContent, opts ...grpc.CallOption) (*NoContent, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(NoContent)
@@ -142,6 +154,7 @@ type CartPurchaseServiceServer interface {
 	AddAdvertToCart(context.Context, *AddAdvertToCartRequest) (*AddAdvertToCartResponse, error)
 	DeleteAdvertFromCart(context.Context, *DeleteAdvertFromCartRequest) (*DeleteAdvertFromCartResponse, error)
 	CheckCartExists(context.Context, *CheckCartExistsRequest) (*CheckCartExistsResponse, error)
+	MergeCart(context.Context, *MergeCartRequest) (*MergeCartResponse, error)
 	Ping(context.Context, *NoContent) (*NoContent, error)
 	mustEmbedUnimplementedCartPurchaseServiceServer()
 }
@@ -174,6 +187,9 @@ func (UnimplementedCartPurchaseServiceServer) DeleteAdvertFromCart(context.Conte
 func (UnimplementedCartPurchaseServiceServer) CheckCartExists(context.Context, *CheckCartExistsRequest) (*CheckCartExistsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckCartExists not implemented")
 }
+func (UnimplementedCartPurchaseServiceServer) MergeCart(context.Context, *MergeCartRequest) (*MergeCartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCart not implemented")
+}
 func (UnimplementedCartPurchaseServiceServer) Ping(context.Context, *NoContent) (*NoContent, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
@@ -324,6 +340,24 @@ func _CartPurchaseService_CheckCartExists_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CartPurchaseService_MergeCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartPurchaseServiceServer).MergeCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CartPurchaseService_MergeCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartPurchaseServiceServer).MergeCart(ctx, req.(*MergeCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CartPurchaseService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NoContent)
 	if err := dec(in); err != nil {
@@ -377,6 +411,10 @@ var CartPurchaseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckCartExists",
 			Handler:    _CartPurchaseService_CheckCartExists_Handler,
 		},
+		{
+			MethodName: "MergeCart",
+			Handler:    _CartPurchaseService_MergeCart_Handler,
+		},
 		{
 			MethodName: "Ping",
 			Handler:    _CartPurchaseService_Ping_Handler,
@@ -2,16 +2,13 @@ package cart_purchase
 
 import (
 	"context"
-	"errors"
 
-	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
 	proto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
-	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/google/uuid"
 )
 
 type GrpcServer struct {
@@ -28,24 +25,45 @@ func NewGrpcServer(cartUC usecase.Cart, purchaseUC usecase.Purchase) *GrpcServer
 }
 
 func (s *GrpcServer) AddPurchase(ctx context.Context, req *proto.AddPurchaseRequest) (*proto.AddPurchaseResponse, error) {
+	cartID, err := parseID("cart_id", req.CartId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	if _, err := s.authorizeCart(ctx, cartID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	purchaseAddress, err := ConvertPurchaseAddressFromProto(req.PurchaseAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	paymentMethod := ConvertPaymentMethodToDB(req.PaymentMethod)
 	deliveryMethod := ConvertDeliveryMethodToDB(req.DeliveryMethod)
 	purchaseReq := dto.PurchaseRequest{
-		CartID:         uuid.MustParse(req.CartId),
-		Address:        req.Address,
-		PaymentMethod:  dto.PaymentMethod(paymentMethod),
-		DeliveryMethod: dto.DeliveryMethod(deliveryMethod),
-		UserID:         uuid.MustParse(req.UserId),
+		CartID:          cartID,
+		Address:         req.Address,
+		PaymentMethod:   dto.PaymentMethod(paymentMethod),
+		DeliveryMethod:  dto.DeliveryMethod(deliveryMethod),
+		UserID:          userID,
+		SafeDeal:        req.SafeDeal,
+		PurchaseAddress: purchaseAddress,
 	}
 
-	purchaseResp, err := s.purchaseUC.Add(purchaseReq, purchaseReq.UserID)
+	purchaseResp, err := s.purchaseUC.Add(ctx, purchaseReq, purchaseReq.UserID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to add purchase: %v", err)
+		return nil, purchaseErrorToStatus(errors.Wrap(err, "failed to add purchase"))
 	}
 
-	purchaseStatus := proto.PurchaseStatus(proto.PurchaseStatus_value[string(purchaseResp.Status)])
-	purchasePaymentMethod := proto.PaymentMethod(proto.PaymentMethod_value[string(purchaseResp.PaymentMethod)])
-	purchaseDeliveryMethod := proto.DeliveryMethod(proto.DeliveryMethod_value[string(purchaseResp.DeliveryMethod)])
+	purchaseStatus, _ := ConvertDBPurchaseStatusToEnum(string(purchaseResp.Status))
+	purchasePaymentMethod, _ := ConvertDBPaymentMethodToEnum(string(purchaseResp.PaymentMethod))
+	purchaseDeliveryMethod, _ := ConvertDBDeliveryMethodToEnum(string(purchaseResp.DeliveryMethod))
 
 	purchaseRespProto := &proto.AddPurchaseResponse{
 		Id:             purchaseResp.ID.String(),
@@ -54,23 +72,30 @@ func (s *GrpcServer) AddPurchase(ctx context.Context, req *proto.AddPurchaseRequ
 		Status:         purchaseStatus,
 		PaymentMethod:  purchasePaymentMethod,
 		DeliveryMethod: purchaseDeliveryMethod,
+		SafeDeal:       purchaseResp.SafeDeal,
 	}
 
 	return purchaseRespProto, nil
 }
 
 func (s *GrpcServer) GetPurchasesByUserID(ctx context.Context, req *proto.GetPurchasesByUserIDRequest) (*proto.GetPurchasesByUserIDResponse, error) {
-	userID := uuid.MustParse(req.UserId)
-	purchases, err := s.purchaseUC.GetByUserId(userID)
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	purchases, err := s.purchaseUC.GetByUserId(ctx, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get purchases: %v", err)
+		return nil, cartPurchaseErrors.ToStatus(errors.Wrap(err, "failed to get purchases"))
 	}
 
 	var protoPurchases []*proto.PurchaseResponse
 	for _, p := range purchases {
-		purchaseStatus := proto.PurchaseStatus(proto.PurchaseStatus_value[string(p.Status)])
-		purchasePaymentMethod := proto.PaymentMethod(proto.PaymentMethod_value[string(p.PaymentMethod)])
-		purchaseDeliveryMethod := proto.DeliveryMethod(proto.DeliveryMethod_value[string(p.DeliveryMethod)])
+		purchaseStatus, _ := ConvertDBPurchaseStatusToEnum(string(p.Status))
+		purchasePaymentMethod, _ := ConvertDBPaymentMethodToEnum(string(p.PaymentMethod))
+		purchaseDeliveryMethod, _ := ConvertDBDeliveryMethodToEnum(string(p.DeliveryMethod))
 
 		protoPurchases = append(protoPurchases, &proto.PurchaseResponse{
 			Id:             p.ID.String(),
@@ -79,6 +104,7 @@ func (s *GrpcServer) GetPurchasesByUserID(ctx context.Context, req *proto.GetPur
 			Status:         purchaseStatus,
 			PaymentMethod:  purchasePaymentMethod,
 			DeliveryMethod: purchaseDeliveryMethod,
+			SafeDeal:       p.SafeDeal,
 		})
 	}
 
@@ -88,9 +114,19 @@ func (s *GrpcServer) GetPurchasesByUserID(ctx context.Context, req *proto.GetPur
 }
 
 func (s *GrpcServer) AddAdvertToCart(ctx context.Context, req *proto.AddAdvertToCartRequest) (*proto.AddAdvertToCartResponse, error) {
-	err := s.cartUC.AddAdvert(uuid.MustParse(req.UserId), uuid.MustParse(req.AdvertId))
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	advertID, err := parseID("advert_id", req.AdvertId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to add advert to cart: %v", err)
+		return nil, err
+	}
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	if err := s.cartUC.AddAdvert(ctx, userID, advertID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(errors.Wrap(err, "failed to add advert to cart"))
 	}
 
 	return &proto.AddAdvertToCartResponse{
@@ -99,9 +135,19 @@ func (s *GrpcServer) AddAdvertToCart(ctx context.Context, req *proto.AddAdvertTo
 }
 
 func (s *GrpcServer) DeleteAdvertFromCart(ctx context.Context, req *proto.DeleteAdvertFromCartRequest) (*proto.DeleteAdvertFromCartResponse, error) {
-	err := s.cartUC.DeleteAdvert(uuid.MustParse(req.CartId), uuid.MustParse(req.AdvertId))
+	cartID, err := parseID("cart_id", req.CartId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to delete advert from cart: %v", err)
+		return nil, err
+	}
+	advertID, err := parseID("advert_id", req.AdvertId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.authorizeCart(ctx, cartID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	if err := s.cartUC.DeleteAdvert(ctx, cartID, advertID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(errors.Wrap(err, "failed to delete advert from cart"))
 	}
 
 	return &proto.DeleteAdvertFromCartResponse{
@@ -110,9 +156,16 @@ func (s *GrpcServer) DeleteAdvertFromCart(ctx context.Context, req *proto.Delete
 }
 
 func (s *GrpcServer) CheckCartExists(ctx context.Context, req *proto.CheckCartExistsRequest) (*proto.CheckCartExistsResponse, error) {
-	cart, err := s.cartUC.CheckExists(uuid.MustParse(req.UserId))
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	cart, err := s.cartUC.CheckExists(ctx, userID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to check cart existence: %v", err)
+		return nil, cartPurchaseErrors.ToStatus(errors.Wrap(err, "failed to check cart existence"))
 	}
 
 	return &proto.CheckCartExistsResponse{
@@ -120,10 +173,63 @@ func (s *GrpcServer) CheckCartExists(ctx context.Context, req *proto.CheckCartEx
 	}, nil
 }
 
+func (s *GrpcServer) MergeCart(ctx context.Context, req *proto.MergeCartRequest) (*proto.MergeCartResponse, error) {
+	userID, err := parseID("user_id", req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	advertIDs := make([]uuid.UUID, 0, len(req.AdvertIds))
+	for _, rawID := range req.AdvertIds {
+		advertID, err := parseID("advert_ids", rawID)
+		if err != nil {
+			return nil, err
+		}
+		advertIDs = append(advertIDs, advertID)
+	}
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+
+	result, err := s.cartUC.MergeAdverts(ctx, userID, advertIDs)
+	if err != nil {
+		return nil, cartPurchaseErrors.ToStatus(errors.Wrap(err, "failed to merge cart"))
+	}
+
+	resp := &proto.MergeCartResponse{
+		CartId:             result.CartID.String(),
+		AddedAdvertIds:     make([]string, 0, len(result.Added)),
+		DuplicateAdvertIds: make([]string, 0, len(result.Duplicates)),
+		Unavailable:        make([]*proto.UnavailableAdvert, 0, len(result.Unavailable)),
+	}
+	for _, advertID := range result.Added {
+		resp.AddedAdvertIds = append(resp.AddedAdvertIds, advertID.String())
+	}
+	for _, advertID := range result.Duplicates {
+		resp.DuplicateAdvertIds = append(resp.DuplicateAdvertIds, advertID.String())
+	}
+	for _, advert := range result.Unavailable {
+		// черновики и отложенные объявления в перечислении передаются как снятые с публикации
+		advertStatus, err := ConvertDBAdvertStatusToEnum(string(advert.Status))
+		if err != nil {
+			advertStatus = proto.AdvertStatus_ADVERT_STATUS_INACTIVE
+		}
+		resp.Unavailable = append(resp.Unavailable, &proto.UnavailableAdvert{
+			AdvertId: advert.AdvertID.String(),
+			Status:   advertStatus,
+			Reason:   string(advert.Reason),
+		})
+	}
+	return resp, nil
+}
+
 func (s *GrpcServer) GetCartByID(ctx context.Context, req *proto.GetCartByIDRequest) (*proto.GetCartByIDResponse, error) {
-	cart, err := s.cartUC.GetById(uuid.MustParse(req.CartId))
+	cartID, err := parseID("cart_id", req.CartId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
+		return nil, err
+	}
+	cart, err := s.authorizeCart(ctx, cartID)
+	if err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
 	}
 
 	protoCart := &proto.Cart{
@@ -154,13 +260,17 @@ func (s *GrpcServer) GetCartByID(ctx context.Context, req *proto.GetCartByIDRequ
 	}, nil
 }
 
-func (s *GrpcServer) GetByUserID(ctx context.Context, req *proto.GetCartByUserIDRequest) (*proto.GetCartByUserIDResponse, error) {
-	cart, err := s.cartUC.GetByUserId(uuid.MustParse(req.UserId))
+func (s *GrpcServer) GetCartByUserID(ctx context.Context, req *proto.GetCartByUserIDRequest) (*proto.GetCartByUserIDResponse, error) {
+	userID, err := parseID("user_id", req.UserId)
 	if err != nil {
-		if errors.Is(err, repository.ErrCartNotFound) {
-			return nil, status.Errorf(codes.NotFound, "cart not found")
-		}
-		return nil, status.Errorf(codes.Internal, "failed to get cart: %v", err)
+		return nil, err
+	}
+	if err := s.authorizeUser(ctx, userID); err != nil {
+		return nil, cartPurchaseErrors.ToStatus(err)
+	}
+	cart, err := s.cartUC.GetByUserId(ctx, userID)
+	if err != nil {
+		return nil, cartPurchaseErrors.ToStatus(errors.Wrap(err, "failed to get cart"))
 	}
 
 	protoCart := &proto.Cart{
@@ -4,39 +4,51 @@ import (
 	"context"
 	"testing"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/grpcerr"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	cartPurchaseProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase/proto"
-	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 )
 
 type MockCartService struct {
 	mock.Mock
 }
 
-func (m *MockCartService) DeleteAdvert(cartID uuid.UUID, advertID uuid.UUID) error {
+func (m *MockCartService) DeleteAdvert(_ context.Context, cartID uuid.UUID, advertID uuid.UUID) error {
 	args := m.Called(cartID, advertID)
 	return args.Error(0)
 }
 
-func (m *MockCartService) CheckExists(userID uuid.UUID) (uuid.UUID, error) {
+func (m *MockCartService) CheckExists(_ context.Context, userID uuid.UUID) (uuid.UUID, error) {
 	args := m.Called(userID)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *MockCartService) GetById(cartID uuid.UUID) (dto.Cart, error) {
+func (m *MockCartService) GetById(_ context.Context, cartID uuid.UUID) (dto.Cart, error) {
 	args := m.Called(cartID)
 	return args.Get(0).(dto.Cart), args.Error(1)
 }
 
-func (m *MockCartService) GetByUserId(userID uuid.UUID) (dto.Cart, error) {
+func (m *MockCartService) GetByUserId(_ context.Context, userID uuid.UUID) (dto.Cart, error) {
 	args := m.Called(userID)
 	return args.Get(0).(dto.Cart), args.Error(1)
 }
 
-func (m *MockCartService) AddAdvert(userID uuid.UUID, advertID uuid.UUID) error {
+func (m *MockCartService) MergeAdverts(_ context.Context, userID uuid.UUID, advertIDs []uuid.UUID) (dto.CartMergeResult, error) {
+	args := m.Called(userID, advertIDs)
+	return args.Get(0).(dto.CartMergeResult), args.Error(1)
+}
+
+func (m *MockCartService) AddAdvert(_ context.Context, userID uuid.UUID, advertID uuid.UUID) error {
 	args := m.Called(userID, advertID)
 	return args.Error(0)
 }
@@ -45,34 +57,42 @@ type MockPurchaseService struct {
 	mock.Mock
 }
 
-func (m *MockPurchaseService) Add(req dto.PurchaseRequest, userID uuid.UUID) (*dto.PurchaseResponse, error) {
+func (m *MockPurchaseService) Add(_ context.Context, req dto.PurchaseRequest, userID uuid.UUID) (*dto.PurchaseResponse, error) {
 	args := m.Called(req, userID)
 	return args.Get(0).(*dto.PurchaseResponse), args.Error(1)
 }
 
-func (m *MockPurchaseService) GetPurchasesByUserID(userID uuid.UUID) ([]dto.PurchaseResponse, error) {
+func (m *MockPurchaseService) GetPurchasesByUserID(_ context.Context, userID uuid.UUID) ([]dto.PurchaseResponse, error) {
 	args := m.Called(userID)
 	return args.Get(0).([]dto.PurchaseResponse), args.Error(1)
 }
 
-func (m *MockPurchaseService) GetByUserId(userID uuid.UUID) ([]*dto.PurchaseResponse, error) {
+func (m *MockPurchaseService) GetByUserId(_ context.Context, userID uuid.UUID) ([]*dto.PurchaseResponse, error) {
 	args := m.Called(userID)
 	return args.Get(0).([]*dto.PurchaseResponse), args.Error(1)
 }
 
+// callerContext - входящий контекст вызова от имени пользователя userID
+func callerContext(userID uuid.UUID) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(callerMetadataKey, userID.String()))
+}
+
 func TestServerDeleteAdvertFromCart(t *testing.T) {
 	mockCartUC := new(MockCartService)
 	mockService := new(MockPurchaseService)
 	server := NewGrpcServer(mockCartUC, mockService)
 
+	userID := uuid.New()
+	cartID := uuid.New()
 	req := &cartPurchaseProto.DeleteAdvertFromCartRequest{
-		CartId:   uuid.New().String(),
+		CartId:   cartID.String(),
 		AdvertId: uuid.New().String(),
 	}
 
+	mockCartUC.On("GetById", cartID).Return(dto.Cart{ID: cartID, UserID: userID}, nil)
 	mockCartUC.On("DeleteAdvert", mock.Anything, mock.Anything).Return(nil)
 
-	result, err := server.DeleteAdvertFromCart(context.Background(), req)
+	result, err := server.DeleteAdvertFromCart(callerContext(userID), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "advert deleted from user cart", result.Message)
@@ -95,13 +115,49 @@ func TestServerCheckCartExists(t *testing.T) {
 
 	mockCartUC.On("CheckExists", mock.Anything).Return(uuid.MustParse(resp.CartId), nil)
 
-	result, err := server.CheckCartExists(context.Background(), req)
+	result, err := server.CheckCartExists(callerContext(userID), req)
 
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil.String(), result.CartId)
 	mockCartUC.AssertExpectations(t)
 }
 
+func TestServerMergeCart(t *testing.T) {
+	mockCartUC := new(MockCartService)
+	mockService := new(MockPurchaseService)
+	server := NewGrpcServer(mockCartUC, mockService)
+
+	userID, cartID, added, draft := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	mockCartUC.On("MergeAdverts", userID, []uuid.UUID{added, draft}).Return(dto.CartMergeResult{
+		CartID:      cartID,
+		Added:       []uuid.UUID{added},
+		Unavailable: []dto.UnavailableAdvert{{AdvertID: draft, Status: entity.AdvertStatusDraft, Reason: dto.UnavailableInactive}},
+	}, nil)
+
+	result, err := server.MergeCart(callerContext(userID), &cartPurchaseProto.MergeCartRequest{
+		UserId:    userID.String(),
+		AdvertIds: []string{added.String(), draft.String()},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, cartID.String(), result.CartId)
+	assert.Equal(t, []string{added.String()}, result.AddedAdvertIds)
+	assert.Empty(t, result.DuplicateAdvertIds)
+	assert.Len(t, result.Unavailable, 1)
+	assert.Equal(t, cartPurchaseProto.AdvertStatus_ADVERT_STATUS_INACTIVE, result.Unavailable[0].Status)
+	assert.Equal(t, "inactive", result.Unavailable[0].Reason)
+	mockCartUC.AssertExpectations(t)
+
+	_, err = server.MergeCart(callerContext(uuid.New()), &cartPurchaseProto.MergeCartRequest{UserId: userID.String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = server.MergeCart(callerContext(userID), &cartPurchaseProto.MergeCartRequest{
+		UserId:    userID.String(),
+		AdvertIds: []string{"invalid"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestServerGetCartByID(t *testing.T) {
 	mockCartUC := new(MockCartService)
 	mockService := new(MockPurchaseService)
@@ -121,9 +177,119 @@ func TestServerGetCartByID(t *testing.T) {
 
 	mockCartUC.On("GetById", mock.Anything).Return(mockCart, nil)
 
-	result, err := server.GetCartByID(context.Background(), req)
+	result, err := server.GetCartByID(callerContext(mockCart.UserID), req)
 
 	assert.NoError(t, err)
 	assert.Equal(t, cartID.String(), result.Cart.Id)
 	mockCartUC.AssertExpectations(t)
 }
+
+func TestServerGetCartByUserID_NotFound(t *testing.T) {
+	mockCartUC := new(MockCartService)
+	server := NewGrpcServer(mockCartUC, new(MockPurchaseService))
+
+	userID := uuid.New()
+	mockCartUC.On("GetByUserId", userID).Return(dto.Cart{}, entity.UsecaseWrap(repository.ErrCartNotFound))
+
+	_, err := server.GetCartByUserID(callerContext(userID), &cartPurchaseProto.GetCartByUserIDRequest{UserId: userID.String()})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.ErrorIs(t, cartPurchaseErrors.FromStatus(err), ErrCartNotFound)
+	mockCartUC.AssertExpectations(t)
+}
+
+func TestServerDeleteAdvertFromCart_InvalidID(t *testing.T) {
+	server := NewGrpcServer(new(MockCartService), new(MockPurchaseService))
+
+	_, err := server.DeleteAdvertFromCart(context.Background(), &cartPurchaseProto.DeleteAdvertFromCartRequest{
+		CartId:   "not-a-uuid",
+		AdvertId: uuid.New().String(),
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	violations := grpcerr.FieldViolations(err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "cart_id", violations[0].GetField())
+}
+
+func TestServerPolicy(t *testing.T) {
+	t.Run("NoCaller", func(t *testing.T) {
+		server := NewGrpcServer(new(MockCartService), new(MockPurchaseService))
+
+		_, err := server.GetPurchasesByUserID(context.Background(), &cartPurchaseProto.GetPurchasesByUserIDRequest{
+			UserId: uuid.New().String(),
+		})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		assert.ErrorIs(t, cartPurchaseErrors.FromStatus(err), ErrCallerUnauthenticated)
+	})
+
+	t.Run("AnotherUser", func(t *testing.T) {
+		server := NewGrpcServer(new(MockCartService), new(MockPurchaseService))
+
+		_, err := server.CheckCartExists(callerContext(uuid.New()), &cartPurchaseProto.CheckCartExistsRequest{
+			UserId: uuid.New().String(),
+		})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.ErrorIs(t, cartPurchaseErrors.FromStatus(err), ErrForbidden)
+	})
+
+	t.Run("AnotherUsersCart", func(t *testing.T) {
+		mockCartUC := new(MockCartService)
+		server := NewGrpcServer(mockCartUC, new(MockPurchaseService))
+
+		cartID := uuid.New()
+		mockCartUC.On("GetById", cartID).Return(dto.Cart{ID: cartID, UserID: uuid.New()}, nil)
+
+		_, err := server.DeleteAdvertFromCart(callerContext(uuid.New()), &cartPurchaseProto.DeleteAdvertFromCartRequest{
+			CartId:   cartID.String(),
+			AdvertId: uuid.New().String(),
+		})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockCartUC.AssertNotCalled(t, "DeleteAdvert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("AnotherUsersPurchaseCart", func(t *testing.T) {
+		mockCartUC := new(MockCartService)
+		mockPurchaseUC := new(MockPurchaseService)
+		server := NewGrpcServer(mockCartUC, mockPurchaseUC)
+
+		userID := uuid.New()
+		cartID := uuid.New()
+		mockCartUC.On("GetById", cartID).Return(dto.Cart{ID: cartID, UserID: uuid.New()}, nil)
+
+		_, err := server.AddPurchase(callerContext(userID), &cartPurchaseProto.AddPurchaseRequest{
+			CartId: cartID.String(),
+			UserId: userID.String(),
+		})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockPurchaseUC.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+	})
+}
+
+func TestServerAddPurchase_UnavailableAdverts(t *testing.T) {
+	mockCartUC := new(MockCartService)
+	mockPurchaseUC := new(MockPurchaseService)
+	server := NewGrpcServer(mockCartUC, mockPurchaseUC)
+
+	userID, cartID, advertID := uuid.New(), uuid.New(), uuid.New()
+	adverts := []dto.UnavailableAdvert{{AdvertID: advertID, Status: entity.AdvertStatusReserved, Reason: dto.UnavailableReserved}}
+	mockCartUC.On("GetById", cartID).Return(dto.Cart{ID: cartID, UserID: userID}, nil)
+	mockPurchaseUC.On("Add", mock.Anything, userID).
+		Return((*dto.PurchaseResponse)(nil), &usecase.UnavailableAdvertsError{Adverts: adverts})
+
+	_, err := server.AddPurchase(callerContext(userID), &cartPurchaseProto.AddPurchaseRequest{
+		CartId: cartID.String(),
+		UserId: userID.String(),
+	})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	clientErr := purchaseErrorFromStatus(err)
+	assert.ErrorIs(t, clientErr, ErrAdvertUnavailable)
+	var unavailable *usecase.UnavailableAdvertsError
+	assert.ErrorAs(t, clientErr, &unavailable)
+	assert.Equal(t, adverts, unavailable.Adverts)
+}
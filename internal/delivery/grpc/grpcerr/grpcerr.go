@@ -0,0 +1,130 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Reason связывает доменную ошибку с кодом gRPC и причиной, которую клиент получает в ErrorInfo
+type Reason struct {
+	Err    error
+	Code   codes.Code
+	Reason string
+}
+
+// Mapper переводит доменные ошибки сервиса в статусы gRPC и обратно. Причины сравниваются
+// только в пределах домена сервиса, поэтому одинаковые причины разных сервисов не путаются.
+// Если одной причине соответствует несколько ошибок, клиент получает первую из них
+type Mapper struct {
+	domain  string
+	reasons []Reason
+}
+
+func NewMapper(domain string, reasons ...Reason) *Mapper {
+	return &Mapper{domain: domain, reasons: reasons}
+}
+
+// ToStatus возвращает статус gRPC для ошибки сервера. Уже готовые статусы и ошибки контекста
+// передаются со своими кодами, ошибки не из списка - как Internal
+func (m *Mapper) ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return status.FromContextError(err).Err()
+	}
+
+	for _, reason := range m.reasons {
+		if errors.Is(err, reason.Err) {
+			return withDetails(status.New(reason.Code, err.Error()),
+				&errdetails.ErrorInfo{Reason: reason.Reason, Domain: m.domain})
+		}
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// FromStatus переводит статус, полученный клиентом, обратно в доменную ошибку по причине
+// из ErrorInfo. Статусы без известной причины возвращаются как есть
+func (m *Mapper) FromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != m.domain {
+			continue
+		}
+		for _, reason := range m.reasons {
+			if reason.Reason == info.GetReason() {
+				return reason.Err
+			}
+		}
+	}
+	return err
+}
+
+// InvalidArgument возвращает статус InvalidArgument для некорректного поля запроса;
+// поле и причина передаются клиенту в BadRequest
+func InvalidArgument(field string, err error) error {
+	return withDetails(status.New(codes.InvalidArgument, fmt.Sprintf("invalid %s: %v", field, err)),
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: err.Error(),
+		}}})
+}
+
+// FieldViolations возвращает поля запроса, которые сервер отклонил как некорректные
+func FieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, badRequest.GetFieldViolations()...)
+		}
+	}
+	return violations
+}
+
+// WithPreconditionFailure дополняет статус err нарушенными условиями, которые клиент получает
+// в PreconditionFailure. Ошибка, которая еще не является статусом, передается как Internal
+func WithPreconditionFailure(err error, violations ...*errdetails.PreconditionFailure_Violation) error {
+	return withDetails(status.Convert(err), &errdetails.PreconditionFailure{Violations: violations})
+}
+
+// PreconditionViolations возвращает нарушенные условия, из-за которых сервер отклонил запрос
+func PreconditionViolations(err error) []*errdetails.PreconditionFailure_Violation {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	var violations []*errdetails.PreconditionFailure_Violation
+	for _, detail := range st.Details() {
+		if failure, ok := detail.(*errdetails.PreconditionFailure); ok {
+			violations = append(violations, failure.GetViolations()...)
+		}
+	}
+	return violations
+}
+
+func withDetails(st *status.Status, detail protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(detail)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
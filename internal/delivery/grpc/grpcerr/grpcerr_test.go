@@ -0,0 +1,105 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errNotFound = errors.New("item not found")
+	errGone     = errors.New("item removed")
+)
+
+func testMapper(domain string) *Mapper {
+	return NewMapper(domain,
+		Reason{Err: errNotFound, Code: codes.NotFound, Reason: "ITEM_NOT_FOUND"},
+		Reason{Err: errGone, Code: codes.NotFound, Reason: "ITEM_NOT_FOUND"},
+	)
+}
+
+func TestMapper_ToStatus(t *testing.T) {
+	mapper := testMapper("test.TestService")
+
+	t.Run("Known", func(t *testing.T) {
+		err := mapper.ToStatus(fmt.Errorf("get item: %w", errNotFound))
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		assert.Equal(t, "get item: item not found", st.Message())
+		assert.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, "ITEM_NOT_FOUND", info.GetReason())
+		assert.Equal(t, "test.TestService", info.GetDomain())
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		assert.Equal(t, codes.Internal, status.Code(mapper.ToStatus(errors.New("db is down"))))
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(mapper.ToStatus(context.DeadlineExceeded)))
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		err := status.Error(codes.PermissionDenied, "forbidden")
+		assert.Equal(t, err, mapper.ToStatus(err))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.NoError(t, mapper.ToStatus(nil))
+	})
+}
+
+func TestMapper_FromStatus(t *testing.T) {
+	mapper := testMapper("test.TestService")
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		assert.Equal(t, errNotFound, mapper.FromStatus(mapper.ToStatus(errGone)))
+	})
+
+	t.Run("OtherDomain", func(t *testing.T) {
+		err := testMapper("other.OtherService").ToStatus(errNotFound)
+		assert.Equal(t, err, mapper.FromStatus(err))
+	})
+
+	t.Run("NoDetails", func(t *testing.T) {
+		err := status.Error(codes.Unavailable, "connection refused")
+		assert.Equal(t, err, mapper.FromStatus(err))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.NoError(t, mapper.FromStatus(nil))
+	})
+}
+
+func TestInvalidArgument(t *testing.T) {
+	err := InvalidArgument("user_id", errors.New("invalid UUID length: 3"))
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	violations := FieldViolations(err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "user_id", violations[0].GetField())
+	assert.Equal(t, "invalid UUID length: 3", violations[0].GetDescription())
+	assert.Empty(t, FieldViolations(errors.New("plain error")))
+}
+
+func TestPreconditionViolations(t *testing.T) {
+	mapper := testMapper("test.TestService")
+	err := WithPreconditionFailure(mapper.ToStatus(errGone),
+		&errdetails.PreconditionFailure_Violation{Type: "RESERVED", Subject: "item-1", Description: "reserved"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, errNotFound, mapper.FromStatus(err))
+	violations := PreconditionViolations(err)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "item-1", violations[0].GetSubject())
+	assert.Empty(t, PreconditionViolations(errors.New("plain error")))
+}
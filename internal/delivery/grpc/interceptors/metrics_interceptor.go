@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/metrics"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"strconv"
 	"time"
 )
@@ -19,8 +21,31 @@ func NewMetricsInterceptor(metrics metrics.GRPCMetrics) *Interceptor {
 	}
 }
 
-func getCode(err string) int {
-	switch err {
+// getCode переводит код статуса gRPC в код HTTP для метрик. Ошибки без статуса
+// сопоставляются по тексту
+func getCode(err error) int {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.Canceled, codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Internal, codes.DataLoss:
+		return http.StatusInternalServerError
+	}
+
+	switch err.Error() {
 	case "session does not exist":
 		return http.StatusUnauthorized
 	case "no such cookie in userStorage":
@@ -44,7 +69,7 @@ func (interceptor *Interceptor) NewMetricsInterceptor(ctx context.Context, req i
 	code := http.StatusOK
 
 	if err != nil {
-		code = getCode(err.Error())
+		code = getCode(err)
 	}
 
 	codeStr := strconv.Itoa(code)
@@ -0,0 +1,85 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDMetadataKey - ключ метаданных gRPC с X-Request-ID запроса к шлюзу
+const requestIDMetadataKey = "x-request-id"
+
+// RequestIDDialOptions передают сервисам X-Request-ID из контекста вызова, чтобы логи
+// шлюза и сервисов можно было сопоставить
+func RequestIDDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(requestIDUnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(requestIDStreamClientInterceptor),
+	}
+}
+
+func requestIDUnaryClientInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(outgoingRequestID(ctx), method, req, reply, cc, opts...)
+}
+
+func requestIDStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+	method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(outgoingRequestID(ctx), desc, cc, method, opts...)
+}
+
+func outgoingRequestID(ctx context.Context) context.Context {
+	requestID := middleware.GetRequestID(ctx)
+	if requestID == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, requestIDMetadataKey, requestID)
+}
+
+// RequestIDUnaryInterceptor кладет в контекст вызова X-Request-ID клиента и логгер с ним
+// и с идентификаторами трассы вызова. Вызовам без X-Request-ID, например из фоновых задач, назначается новый
+func RequestIDUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(incomingRequestID(ctx, info.FullMethod), req)
+}
+
+// RequestIDStreamInterceptor работает как RequestIDUnaryInterceptor для потоковых вызовов
+func RequestIDStreamInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &requestIDServerStream{
+		ServerStream: stream,
+		ctx:          incomingRequestID(stream.Context(), info.FullMethod),
+	})
+}
+
+type requestIDServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func incomingRequestID(ctx context.Context, method string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := ""
+	if values := md.Get(requestIDMetadataKey); len(values) > 0 {
+		requestID = values[0]
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	logger := zap.L().With(append([]zap.Field{
+		zap.String("request_id", requestID),
+		zap.String("grpc_method", method),
+	}, tracing.LogFields(ctx)...)...)
+	ctx = middleware.WithLogger(ctx, logger)
+	return middleware.WithRequestID(ctx, requestID)
+}
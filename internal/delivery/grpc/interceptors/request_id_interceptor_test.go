@@ -0,0 +1,67 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// sendRequestID передает контекст клиента через перехватчики клиента и сервера
+// и возвращает X-Request-ID, который получил обработчик сервиса
+func sendRequestID(t *testing.T, ctx context.Context) string {
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	assert.NoError(t, requestIDUnaryClientInterceptor(ctx, "/test.TestService/Get", nil, nil, nil, invoker))
+
+	var requestID string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		requestID = middleware.GetRequestID(ctx)
+		assert.NotSame(t, middleware.GetLogger(context.Background()), middleware.GetLogger(ctx))
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.TestService/Get"}
+	_, err := RequestIDUnaryInterceptor(metadata.NewIncomingContext(context.Background(), outgoing), nil, info, handler)
+	assert.NoError(t, err)
+	return requestID
+}
+
+func TestRequestIDInterceptors(t *testing.T) {
+	t.Run("Propagated", func(t *testing.T) {
+		ctx := middleware.WithRequestID(context.Background(), "gateway-request")
+		assert.Equal(t, "gateway-request", sendRequestID(t, ctx))
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		requestID := sendRequestID(t, context.Background())
+		_, err := uuid.Parse(requestID)
+		assert.NoError(t, err)
+	})
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRequestIDStreamInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDMetadataKey, "gateway-request"))
+	info := &grpc.StreamServerInfo{FullMethod: "/test.TestService/Upload"}
+
+	err := RequestIDStreamInterceptor(nil, &contextStream{ctx: ctx}, info, func(_ interface{}, stream grpc.ServerStream) error {
+		assert.Equal(t, "gateway-request", middleware.GetRequestID(stream.Context()))
+		return nil
+	})
+	assert.NoError(t, err)
+}
@@ -3,64 +3,56 @@ package static
 import (
 	"bytes"
 	"context"
-	"image"
-	"image/jpeg"
 	"io"
-	"strings"
 	"time"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
 	static "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static/proto"
-	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
-	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 const (
-	bufSize = 1024
+	bufSize = 32 << 10
+	// streamTimeout ограничивает передачу файла потоком: таймаут обычных вызовов для нее слишком мал
+	streamTimeout = 30 * time.Second
 )
 
+// idempotentMethods - вызовы, которые безопасно повторить, если сервис статики недоступен
+var idempotentMethods = []string{
+	"GetStatic", "GetStaticFile", "GetStaticFileInfo", "GetStaticVariant", "GetUpload", "CancelUpload", "Ping",
+}
+
 type StaticGrpcClient struct {
-	timeout       time.Duration
 	staticManager static.StaticServiceClient
 }
 
-func NewStaticGrpcClient(connectAddr string, timeout time.Duration) (*StaticGrpcClient, error) {
-	grpcConn, err := grpc.Dial(
-		connectAddr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithTimeout(timeout),
-	)
+func NewStaticGrpcClient(connectAddr string, cfg config.GRPCConfig, opts ...grpc.DialOption) (*StaticGrpcClient, error) {
+	conn, err := connector.GetGrpcConnector(connectAddr, static.StaticService_ServiceDesc.ServiceName, cfg, idempotentMethods, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	staticManager := static.NewStaticServiceClient(grpcConn)
-
-	_, err = staticManager.Ping(context.Background(), &static.Nothing{})
-	if err != nil {
-		return nil, err
-	}
-
-	return &StaticGrpcClient{staticManager: staticManager, timeout: timeout}, nil
+	return &StaticGrpcClient{staticManager: static.NewStaticServiceClient(conn)}, nil
 }
 
-func (gate *StaticGrpcClient) GetStatic(staticID uuid.UUID) (string, error) {
-	staticFile, err := gate.staticManager.GetStatic(context.Background(), &static.Static{Id: staticID.String()})
+func (gate *StaticGrpcClient) GetStatic(ctx context.Context, staticID uuid.UUID) (string, error) {
+	staticFile, err := gate.staticManager.GetStatic(ctx, &static.Static{Id: staticID.String()})
 	if err != nil {
-		if strings.Contains(err.Error(), repository.ErrStaticNotFound.Error()) {
-			return "", usecase.ErrStaticNotFound
-		}
-		return "", err
+		return "", staticErrors.FromStatus(err)
 	}
 	return staticFile.Uri, nil
 }
 
-func (gate *StaticGrpcClient) UploadStatic(reader io.ReadSeeker) (uuid.UUID, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// UploadStatic передает метаданные первым сообщением, а затем содержимое частями, не читая файл целиком.
+// Метаданные изображения (EXIF и т.п.) не сохраняются: сервис статики поворачивает изображение
+// по ориентации из EXIF и перекодирует файл без метаданных
+func (gate *StaticGrpcClient) UploadStatic(ctx context.Context, reader io.Reader, metadata entity.UploadMetadata) (uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
 	defer cancel()
 
 	stream, err := gate.staticManager.UploadStatic(ctx)
@@ -69,75 +61,230 @@ func (gate *StaticGrpcClient) UploadStatic(reader io.ReadSeeker) (uuid.UUID, err
 		return uuid.Nil, err
 	}
 
-	zap.L().Info("Начало загрузки статического файла")
+	zap.L().Info("Начало загрузки статического файла", zap.String("filename", metadata.Filename),
+		zap.String("purpose", string(metadata.Purpose)), zap.Int64("size", metadata.Size))
 
-	imgData, err := io.ReadAll(reader)
-	if err != nil {
-		zap.L().Error("Ошибка чтения данных изображения", zap.Error(err))
+	err = stream.Send(&static.StaticUpload{Metadata: &static.UploadMetadata{
+		Filename:    metadata.Filename,
+		ContentType: metadata.ContentType,
+		Purpose:     string(metadata.Purpose),
+		Size:        metadata.Size,
+	}})
+
+	chunk := make([]byte, bufSize)
+	for err == nil {
+		bytesRead, readErr := reader.Read(chunk)
+		if bytesRead > 0 {
+			err = stream.Send(&static.StaticUpload{Chunk: chunk[:bytesRead]})
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			zap.L().Error("Ошибка чтения чанка", zap.Error(readErr))
+			return uuid.Nil, readErr
+		}
+	}
+	// io.EOF при отправке означает, что сервер уже ответил, например отклонил слишком большой файл
+	if err != nil && err != io.EOF {
+		zap.L().Error("Ошибка отправки чанка", zap.Error(err))
 		return uuid.Nil, err
 	}
 
-	cleanImgData, err := removeMetadata(imgData)
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		zap.L().Warn("Сервис статики отклонил файл", zap.Error(err))
+		return uuid.Nil, staticErrors.FromStatus(err)
+	}
+
+	staticID, err := uuid.Parse(response.Id)
 	if err != nil {
-		zap.L().Error("Ошибка удаления метаданных", zap.Error(err))
 		return uuid.Nil, err
 	}
+	zap.L().Info("Статический файл успешно загружен", zap.String("id", response.Id))
+	return staticID, nil
+}
 
-	buffer := bytes.NewBuffer(cleanImgData)
+func fromProtoUpload(upload *static.ResumableUpload) (*entity.ResumableUpload, error) {
+	id, err := uuid.Parse(upload.GetId())
+	if err != nil {
+		return nil, err
+	}
+	ownerID, err := uuid.Parse(upload.GetOwnerId())
+	if err != nil {
+		return nil, err
+	}
+	metadata := upload.GetMetadata()
+	return &entity.ResumableUpload{
+		ID:      id,
+		OwnerID: ownerID,
+		Metadata: entity.UploadMetadata{
+			Filename:    metadata.GetFilename(),
+			ContentType: metadata.GetContentType(),
+			Purpose:     entity.UploadPurpose(metadata.GetPurpose()),
+			Size:        metadata.GetSize(),
+		},
+		Offset:    upload.GetOffset(),
+		ExpiresAt: time.Unix(upload.GetExpiresAt(), 0),
+	}, nil
+}
+
+// CreateUpload начинает загрузку по частям файла, описанного metadata
+func (gate *StaticGrpcClient) CreateUpload(ctx context.Context, ownerID uuid.UUID, metadata entity.UploadMetadata) (*entity.ResumableUpload, error) {
+	upload, err := gate.staticManager.CreateUpload(ctx, &static.ResumableUpload{
+		OwnerId: ownerID.String(),
+		Metadata: &static.UploadMetadata{
+			Filename:    metadata.Filename,
+			ContentType: metadata.ContentType,
+			Purpose:     string(metadata.Purpose),
+			Size:        metadata.Size,
+		},
+	})
+	if err != nil {
+		return nil, staticErrors.FromStatus(err)
+	}
+	return fromProtoUpload(upload)
+}
+
+func (gate *StaticGrpcClient) GetUpload(ctx context.Context, uploadID, ownerID uuid.UUID) (*entity.ResumableUpload, error) {
+	upload, err := gate.staticManager.GetUpload(ctx, &static.ResumableUpload{
+		Id:      uploadID.String(),
+		OwnerId: ownerID.String(),
+	})
+	if err != nil {
+		return nil, staticErrors.FromStatus(err)
+	}
+	return fromProtoUpload(upload)
+}
+
+// AppendUpload передает часть загрузки из reader, начинающуюся с offset, и возвращает новое состояние загрузки
+func (gate *StaticGrpcClient) AppendUpload(ctx context.Context, uploadID, ownerID uuid.UUID, offset int64, reader io.Reader) (*entity.ResumableUpload, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+
+	stream, err := gate.staticManager.AppendUpload(ctx)
+	if err != nil {
+		zap.L().Error("Ошибка при инициализации потока AppendUpload", zap.Error(err))
+		return nil, err
+	}
+
+	err = stream.Send(&static.UploadChunk{Id: uploadID.String(), OwnerId: ownerID.String(), Offset: offset})
 
 	chunk := make([]byte, bufSize)
-	for {
-		bytesRead, err := buffer.Read(chunk)
-		if err == io.EOF {
+	for err == nil {
+		bytesRead, readErr := reader.Read(chunk)
+		if bytesRead > 0 {
+			err = stream.Send(&static.UploadChunk{Chunk: chunk[:bytesRead]})
+		}
+		if readErr == io.EOF {
 			break
 		}
-		if err != nil {
-			zap.L().Error("Ошибка чтения чанка", zap.Error(err))
-			return uuid.Nil, err
+		if readErr != nil {
+			zap.L().Error("Ошибка чтения части загрузки", zap.Error(readErr))
+			return nil, readErr
 		}
+	}
+	// io.EOF при отправке означает, что сервер уже ответил, например отклонил смещение
+	if err != nil && err != io.EOF {
+		zap.L().Error("Ошибка отправки части загрузки", zap.Error(err))
+		return nil, err
+	}
 
-		err = stream.Send(&static.StaticUpload{
-			Chunk: chunk[:bytesRead],
-		})
-		if err != nil {
-			zap.L().Error("Ошибка отправки чанка", zap.Error(err))
-			return uuid.Nil, err
-		}
+	upload, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, staticErrors.FromStatus(err)
 	}
+	return fromProtoUpload(upload)
+}
 
-	response, err := stream.CloseAndRecv()
+// FinishUpload завершает полностью переданную загрузку и возвращает id статики
+func (gate *StaticGrpcClient) FinishUpload(ctx context.Context, uploadID, ownerID uuid.UUID, purpose entity.UploadPurpose) (uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+
+	response, err := gate.staticManager.FinishUpload(ctx, &static.ResumableUpload{
+		Id:       uploadID.String(),
+		OwnerId:  ownerID.String(),
+		Metadata: &static.UploadMetadata{Purpose: string(purpose)},
+	})
 	if err != nil {
-		zap.L().Error("Ошибка при закрытии и получении ответа", zap.Error(err))
-		// Обработка ошибок, как ранее
-		switch {
-		case strings.Contains(err.Error(), usecase.ErrStaticTooBigFile.Error()):
-			return uuid.Nil, usecase.ErrStaticTooBigFile
-		case strings.Contains(err.Error(), usecase.ErrStaticNotImage.Error()):
-			return uuid.Nil, usecase.ErrStaticNotImage
-		case strings.Contains(err.Error(), usecase.ErrStaticImageDimensions.Error()):
-			return uuid.Nil, usecase.ErrStaticImageDimensions
-		case strings.Contains(err.Error(), "context deadline exceeded"):
-			return uuid.Nil, errors.New("context deadline exceeded")
-		default:
-			return uuid.Nil, err
-		}
+		return uuid.Nil, staticErrors.FromStatus(err)
 	}
+	return uuid.Parse(response.GetId())
+}
 
-	zap.L().Info("Статический файл успешно загружен", zap.String("id", response.Id))
-	return uuid.MustParse(response.Id), nil
+func (gate *StaticGrpcClient) CancelUpload(ctx context.Context, uploadID, ownerID uuid.UUID) error {
+	_, err := gate.staticManager.CancelUpload(ctx, &static.ResumableUpload{
+		Id:      uploadID.String(),
+		OwnerId: ownerID.String(),
+	})
+	if err != nil {
+		return staticErrors.FromStatus(err)
+	}
+	return nil
+}
+
+func (gate *StaticGrpcClient) GetStaticFile(ctx context.Context, staticURI string) (io.ReadSeeker, error) {
+	return gate.GetStaticFileRange(ctx, staticURI, 0, 0)
 }
 
-func (gate *StaticGrpcClient) GetStaticFile(staticURI string) (io.ReadSeeker, error) {
-	zap.L().Info("Getting static file", zap.String("uri", staticURI))
+// GetStaticFileRange читает length байт файла начиная с offset, length = 0 - до конца файла
+func (gate *StaticGrpcClient) GetStaticFileRange(ctx context.Context, staticURI string, offset, length int64) (io.ReadSeeker, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+
+	zap.L().Info("Getting static file", zap.String("uri", staticURI),
+		zap.Int64("offset", offset), zap.Int64("length", length))
 
-	stream, err := gate.staticManager.GetStaticFile(context.Background(), &static.Static{Uri: staticURI})
+	stream, err := gate.staticManager.GetStaticFile(ctx, &static.Static{
+		Uri:    staticURI,
+		Offset: offset,
+		Length: length,
+	})
 	if err != nil {
-		if strings.Contains(err.Error(), repository.ErrStaticNotFound.Error()) {
-			return nil, usecase.ErrStaticNotFound
-		}
-		return nil, err
+		return nil, staticErrors.FromStatus(err)
 	}
 
+	return receiveFile(stream)
+}
+
+func (gate *StaticGrpcClient) GetStaticFileInfo(ctx context.Context, staticURI string) (*entity.BlobInfo, error) {
+	info, err := gate.staticManager.GetStaticFileInfo(ctx, &static.Static{Uri: staticURI})
+	if err != nil {
+		return nil, staticErrors.FromStatus(err)
+	}
+	return &entity.BlobInfo{
+		Key:         info.Uri,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		ModTime:     time.Unix(info.ModTime, 0),
+		ETag:        info.Etag,
+	}, nil
+}
+
+func (gate *StaticGrpcClient) GetStaticVariant(ctx context.Context, staticID uuid.UUID, variant entity.ImageVariant) (io.ReadSeeker, error) {
+	ctx, cancel := context.WithTimeout(ctx, streamTimeout)
+	defer cancel()
+
+	zap.L().Info("Getting static variant", zap.String("id", staticID.String()),
+		zap.Int("width", variant.Width), zap.Int("height", variant.Height))
+
+	stream, err := gate.staticManager.GetStaticVariant(ctx, &static.ImageVariant{
+		Id:     staticID.String(),
+		Width:  int32(variant.Width),
+		Height: int32(variant.Height),
+		Fit:    string(variant.Fit),
+		Format: string(variant.Format),
+	})
+	if err != nil {
+		return nil, staticErrors.FromStatus(err)
+	}
+
+	return receiveFile(stream)
+}
+
+// receiveFile собирает файл из потока частей
+func receiveFile(stream static.StaticService_GetStaticFileClient) (io.ReadSeeker, error) {
 	var buffer []byte
 	for {
 		chunk, err := stream.Recv()
@@ -147,15 +294,12 @@ func (gate *StaticGrpcClient) GetStaticFile(staticURI string) (io.ReadSeeker, er
 		}
 
 		if err != nil {
-			if strings.Contains(err.Error(), repository.ErrStaticNotFound.Error()) {
-				return nil, usecase.ErrStaticNotFound
-			}
-			return nil, err
+			return nil, staticErrors.FromStatus(err)
 		}
 		buffer = append(buffer, chunk.Chunk...)
 	}
 
-	err = stream.CloseSend()
+	err := stream.CloseSend()
 	if err != nil {
 		return nil, err
 	}
@@ -163,34 +307,6 @@ func (gate *StaticGrpcClient) GetStaticFile(staticURI string) (io.ReadSeeker, er
 	return io.ReadSeeker(bytes.NewReader(buffer)), nil
 }
 
-func removeMetadata(imgData []byte) ([]byte, error) {
-	var (
-		img image.Image
-		err error
-	)
-
-	zap.L().Info("Начало удаления метаданных из изображения")
-
-	// Попытка декодировать изображение стандартными методами
-	img, _, err = image.Decode(bytes.NewReader(imgData))
-	if err != nil {
-		zap.L().Error("Не удалось декодировать изображение стандартными методами", zap.Error(err))
-		return nil, err
-	}
-
-	var buf bytes.Buffer
-
-	// Кодирование изображения обратно в JPEG без метаданных
-	err = jpeg.Encode(&buf, img, nil)
-	if err != nil {
-		zap.L().Error("Ошибка кодирования изображения в JPEG", zap.Error(err))
-		return nil, err
-	}
-
-	zap.L().Info("Метаданные успешно удалены")
-	return buf.Bytes(), nil
-}
-
 func isAnimatedWebP(data []byte) (bool, error) {
 	const (
 		webpHeader = "RIFF"
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"google.golang.org/grpc/metadata"
 	staticProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,29 +19,50 @@ type mockClientStaticUseCase struct {
 	mock.Mock
 }
 
-func (m *mockClientStaticUseCase) GetStatic(id uuid.UUID) (string, error) {
+func (m *mockClientStaticUseCase) GetStatic(_ context.Context, id uuid.UUID) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *mockClientStaticUseCase) UploadStatic(reader io.ReadSeeker) (uuid.UUID, error) {
-	args := m.Called(reader)
+func (m *mockClientStaticUseCase) UploadStatic(_ context.Context, reader io.Reader, metadata entity.UploadMetadata) (uuid.UUID, error) {
+	args := m.Called(reader, metadata)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *mockClientStaticUseCase) GetStaticFile(uri string) (io.ReadSeeker, error) {
+func (m *mockClientStaticUseCase) GetStaticFile(_ context.Context, uri string) (io.ReadSeeker, error) {
 	args := m.Called(uri)
 	return args.Get(0).(io.ReadSeeker), args.Error(1)
 }
 
-func (m *mockClientStaticUseCase) GetAvatar(id uuid.UUID) (string, error) {
+func (m *mockClientStaticUseCase) GetAvatar(_ context.Context, id uuid.UUID) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockClientStaticUseCase) GetStaticFileInfo(_ context.Context, uri string) (*entity.BlobInfo, error) {
+	args := m.Called(uri)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BlobInfo), args.Error(1)
+}
+
+func (m *mockClientStaticUseCase) CollectGarbage(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *mockClientStaticUseCase) GetStaticVariant(_ context.Context, id uuid.UUID, variant entity.ImageVariant) (io.ReadSeeker, error) {
+	args := m.Called(id, variant)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadSeeker), args.Error(1)
+}
+
 func TestClientGetStatic_Success(t *testing.T) {
 	mockUC := new(mockClientStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	staticID := uuid.New()
 	expectedURI := "http://example.com/static/" + staticID.String()
@@ -58,7 +80,7 @@ func TestClientGetStatic_Success(t *testing.T) {
 
 func TestClientGetStatic_InvalidUUID(t *testing.T) {
 	mockUC := new(mockClientStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	static := &staticProto.Static{Id: "invalid-uuid"}
 
@@ -71,10 +93,11 @@ func TestClientGetStatic_InvalidUUID(t *testing.T) {
 
 func TestClientGetStaticFile_Success(t *testing.T) {
 	mockUC := new(mockClientStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	static := &staticProto.Static{Uri: "http://example.com/static/file"}
 	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
 	stream.On("Send", &staticProto.StaticUpload{Chunk: []byte("file content")}).Return(nil)
 
 	mockUC.On("GetStaticFile", "http://example.com/static/file").Return(bytes.NewReader([]byte("file content")), nil)
@@ -87,10 +110,11 @@ func TestClientGetStaticFile_Success(t *testing.T) {
 
 func TestClientGetStaticFile_ErrorGettingFile(t *testing.T) {
 	mockUC := new(mockClientStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	static := &staticProto.Static{Uri: "http://example.com/static/file"}
 	stream := &mockClientStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
 
 	readSeeker := bytes.NewReader([]byte("file content"))
 
@@ -103,7 +127,7 @@ func TestClientGetStaticFile_ErrorGettingFile(t *testing.T) {
 
 func TestClientPing(t *testing.T) {
 	mockUC := new(mockClientStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	result, err := grpcServer.Ping(context.Background(), &staticProto.Nothing{})
 
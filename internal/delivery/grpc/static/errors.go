@@ -0,0 +1,37 @@
+package static
+
+import (
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/grpcerr"
+	staticProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"google.golang.org/grpc/codes"
+)
+
+// staticErrors - ошибки сервиса статики, которые передаются клиенту кодами gRPC.
+// Ошибки репозитория перечислены после ошибок сервиса с той же причиной, поэтому
+// клиент получает ошибки сервиса
+var staticErrors = grpcerr.NewMapper(staticProto.StaticService_ServiceDesc.ServiceName,
+	grpcerr.Reason{Err: usecase.ErrStaticNotFound, Code: codes.NotFound, Reason: "STATIC_NOT_FOUND"},
+	grpcerr.Reason{Err: repository.ErrStaticNotFound, Code: codes.NotFound, Reason: "STATIC_NOT_FOUND"},
+	grpcerr.Reason{Err: usecase.ErrStaticFileNotFound, Code: codes.NotFound, Reason: "STATIC_FILE_NOT_FOUND"},
+	grpcerr.Reason{Err: usecase.ErrStaticTooBigFile, Code: codes.InvalidArgument, Reason: "STATIC_TOO_BIG_FILE"},
+	grpcerr.Reason{Err: repository.ErrStaticTooLarge, Code: codes.InvalidArgument, Reason: "STATIC_TOO_BIG_FILE"},
+	grpcerr.Reason{Err: usecase.ErrStaticNotImage, Code: codes.InvalidArgument, Reason: "STATIC_NOT_IMAGE"},
+	grpcerr.Reason{Err: usecase.ErrStaticImageDimensions, Code: codes.InvalidArgument, Reason: "STATIC_IMAGE_DIMENSIONS"},
+	grpcerr.Reason{Err: usecase.ErrStaticInvalidVariant, Code: codes.InvalidArgument, Reason: "STATIC_INVALID_VARIANT"},
+	grpcerr.Reason{Err: usecase.ErrStaticUnsupportedFormat, Code: codes.InvalidArgument, Reason: "STATIC_UNSUPPORTED_FORMAT"},
+	grpcerr.Reason{Err: usecase.ErrStaticUnknownPurpose, Code: codes.InvalidArgument, Reason: "STATIC_UNKNOWN_PURPOSE"},
+	grpcerr.Reason{Err: usecase.ErrStaticImageRejected, Code: codes.PermissionDenied, Reason: "STATIC_IMAGE_REJECTED"},
+	grpcerr.Reason{Err: usecase.ErrStaticNotVideo, Code: codes.InvalidArgument, Reason: "STATIC_NOT_VIDEO"},
+	grpcerr.Reason{Err: usecase.ErrStaticVideoDuration, Code: codes.InvalidArgument, Reason: "STATIC_VIDEO_DURATION"},
+	grpcerr.Reason{Err: usecase.ErrStaticVideoDisabled, Code: codes.Unimplemented, Reason: "STATIC_VIDEO_DISABLED"},
+	grpcerr.Reason{Err: usecase.ErrStaticUploadNotFound, Code: codes.NotFound, Reason: "STATIC_UPLOAD_NOT_FOUND"},
+	grpcerr.Reason{Err: repository.ErrStaticUploadNotFound, Code: codes.NotFound, Reason: "STATIC_UPLOAD_NOT_FOUND"},
+	grpcerr.Reason{Err: usecase.ErrStaticUploadOffset, Code: codes.FailedPrecondition, Reason: "STATIC_UPLOAD_OFFSET"},
+	grpcerr.Reason{Err: repository.ErrStaticUploadOffset, Code: codes.FailedPrecondition, Reason: "STATIC_UPLOAD_OFFSET"},
+	grpcerr.Reason{Err: usecase.ErrStaticUploadIncomplete, Code: codes.FailedPrecondition, Reason: "STATIC_UPLOAD_INCOMPLETE"},
+	grpcerr.Reason{Err: usecase.ErrStaticUploadPurpose, Code: codes.InvalidArgument, Reason: "STATIC_UPLOAD_PURPOSE"},
+	grpcerr.Reason{Err: usecase.ErrStaticUploadLength, Code: codes.InvalidArgument, Reason: "STATIC_UPLOAD_LENGTH"},
+	grpcerr.Reason{Err: ErrInvalidRange, Code: codes.OutOfRange, Reason: "STATIC_INVALID_RANGE"},
+)
@@ -19,10 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StaticService_GetStatic_FullMethodName     = "/static.StaticService/GetStatic"
-	StaticService_UploadStatic_FullMethodName  = "/static.StaticService/UploadStatic"
-	StaticService_GetStaticFile_FullMethodName = "/static.StaticService/GetStaticFile"
-	StaticService_Ping_FullMethodName          = "/static.StaticService/Ping"
+	StaticService_GetStatic_FullMethodName         = "/static.StaticService/GetStatic"
+	StaticService_UploadStatic_FullMethodName      = "/static.StaticService/UploadStatic"
+	StaticService_GetStaticFile_FullMethodName     = "/static.StaticService/GetStaticFile"
+	StaticService_GetStaticFileInfo_FullMethodName = "/static.StaticService/GetStaticFileInfo"
+	StaticService_GetStaticVariant_FullMethodName  = "/static.StaticService/GetStaticVariant"
+	StaticService_CreateUpload_FullMethodName      = "/static.StaticService/CreateUpload"
+	StaticService_GetUpload_FullMethodName         = "/static.StaticService/GetUpload"
+	StaticService_AppendUpload_FullMethodName      = "/static.StaticService/AppendUpload"
+	StaticService_FinishUpload_FullMethodName      = "/static.StaticService/FinishUpload"
+	StaticService_CancelUpload_FullMethodName      = "/static.StaticService/CancelUpload"
+	StaticService_Ping_FullMethodName              = "/static.StaticService/Ping"
 )
 
 // StaticServiceClient is the client API for StaticService service.
@@ -32,6 +39,13 @@ type StaticServiceClient interface {
 	GetStatic(ctx context.Context, in *Static, opts ...grpc.CallOption) (*Static, error)
 	UploadStatic(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StaticUpload, Static], error)
 	GetStaticFile(ctx context.Context, in *Static, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StaticUpload], error)
+	GetStaticFileInfo(ctx context.Context, in *Static, opts ...grpc.CallOption) (*StaticInfo, error)
+	GetStaticVariant(ctx context.Context, in *ImageVariant, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StaticUpload], error)
+	CreateUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*ResumableUpload, error)
+	GetUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*ResumableUpload, error)
+	AppendUpload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, ResumableUpload], error)
+	FinishUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*Static, error)
+	CancelUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*Nothing, error)
 	Ping(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*Nothing, error)
 }
 
@@ -85,6 +99,88 @@ func (c *staticServiceClient) GetStaticFile(ctx context.Context, in *Static, opt
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type StaticService_GetStaticFileClient = grpc.ServerStreamingClient[StaticUpload]
 
+func (c *staticServiceClient) GetStaticFileInfo(ctx context.Context, in *Static, opts ...grpc.CallOption) (*StaticInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StaticInfo)
+	err := c.cc.Invoke(ctx, StaticService_GetStaticFileInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *staticServiceClient) GetStaticVariant(ctx context.Context, in *ImageVariant, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StaticUpload], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StaticService_ServiceDesc.Streams[2], StaticService_GetStaticVariant_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImageVariant, StaticUpload]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StaticService_GetStaticVariantClient = grpc.ServerStreamingClient[StaticUpload]
+
+func (c *staticServiceClient) CreateUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*ResumableUpload, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumableUpload)
+	err := c.cc.Invoke(ctx, StaticService_CreateUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *staticServiceClient) GetUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*ResumableUpload, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumableUpload)
+	err := c.cc.Invoke(ctx, StaticService_GetUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *staticServiceClient) AppendUpload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, ResumableUpload], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StaticService_ServiceDesc.Streams[3], StaticService_AppendUpload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadChunk, ResumableUpload]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StaticService_AppendUploadClient = grpc.ClientStreamingClient[UploadChunk, ResumableUpload]
+
+func (c *staticServiceClient) FinishUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*Static, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Static)
+	err := c.cc.Invoke(ctx, StaticService_FinishUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *staticServiceClient) CancelUpload(ctx context.Context, in *ResumableUpload, opts ...grpc.CallOption) (*Nothing, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Nothing)
+	err := c.cc.Invoke(ctx, StaticService_CancelUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *staticServiceClient) Ping(ctx context.Context, in *Nothing, opts ...grpc.CallOption) (*Nothing, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Nothing)
@@ -102,6 +198,13 @@ type StaticServiceServer interface {
 	GetStatic(context.Context, *Static) (*Static, error)
 	UploadStatic(grpc.ClientStreamingServer[StaticUpload, Static]) error
 	GetStaticFile(*Static, grpc.ServerStreamingServer[StaticUpload]) error
+	GetStaticFileInfo(context.Context, *Static) (*StaticInfo, error)
+	GetStaticVariant(*ImageVariant, grpc.ServerStreamingServer[StaticUpload]) error
+	CreateUpload(context.Context, *ResumableUpload) (*ResumableUpload, error)
+	GetUpload(context.Context, *ResumableUpload) (*ResumableUpload, error)
+	AppendUpload(grpc.ClientStreamingServer[UploadChunk, ResumableUpload]) error
+	FinishUpload(context.Context, *ResumableUpload) (*Static, error)
+	CancelUpload(context.Context, *ResumableUpload) (*Nothing, error)
 	Ping(context.Context, *Nothing) (*Nothing, error)
 	mustEmbedUnimplementedStaticServiceServer()
 }
@@ -122,6 +225,27 @@ func (UnimplementedStaticServiceServer) UploadStatic(grpc.ClientStreamingServer[
 func (UnimplementedStaticServiceServer) GetStaticFile(*Static, grpc.ServerStreamingServer[StaticUpload]) error {
 	return status.Errorf(codes.Unimplemented, "method GetStaticFile not implemented")
 }
+func (UnimplementedStaticServiceServer) GetStaticFileInfo(context.Context, *Static) (*StaticInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStaticFileInfo not implemented")
+}
+func (UnimplementedStaticServiceServer) GetStaticVariant(*ImageVariant, grpc.ServerStreamingServer[StaticUpload]) error {
+	return status.Errorf(codes.Unimplemented, "method GetStaticVariant not implemented")
+}
+func (UnimplementedStaticServiceServer) CreateUpload(context.Context, *ResumableUpload) (*ResumableUpload, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUpload not implemented")
+}
+func (UnimplementedStaticServiceServer) GetUpload(context.Context, *ResumableUpload) (*ResumableUpload, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpload not implemented")
+}
+func (UnimplementedStaticServiceServer) AppendUpload(grpc.ClientStreamingServer[UploadChunk, ResumableUpload]) error {
+	return status.Errorf(codes.Unimplemented, "method AppendUpload not implemented")
+}
+func (UnimplementedStaticServiceServer) FinishUpload(context.Context, *ResumableUpload) (*Static, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishUpload not implemented")
+}
+func (UnimplementedStaticServiceServer) CancelUpload(context.Context, *ResumableUpload) (*Nothing, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelUpload not implemented")
+}
 func (UnimplementedStaticServiceServer) Ping(context.Context, *Nothing) (*Nothing, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
@@ -182,6 +306,114 @@ func _StaticService_GetStaticFile_Handler(srv interface{}, stream grpc.ServerStr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type StaticService_GetStaticFileServer = grpc.ServerStreamingServer[StaticUpload]
 
+func _StaticService_GetStaticFileInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Static)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StaticServiceServer).GetStaticFileInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StaticService_GetStaticFileInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StaticServiceServer).GetStaticFileInfo(ctx, req.(*Static))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StaticService_GetStaticVariant_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ImageVariant)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StaticServiceServer).GetStaticVariant(m, &grpc.GenericServerStream[ImageVariant, StaticUpload]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StaticService_GetStaticVariantServer = grpc.ServerStreamingServer[StaticUpload]
+
+func _StaticService_CreateUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumableUpload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StaticServiceServer).CreateUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StaticService_CreateUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StaticServiceServer).CreateUpload(ctx, req.(*ResumableUpload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StaticService_GetUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumableUpload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StaticServiceServer).GetUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StaticService_GetUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StaticServiceServer).GetUpload(ctx, req.(*ResumableUpload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StaticService_AppendUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StaticServiceServer).AppendUpload(&grpc.GenericServerStream[UploadChunk, ResumableUpload]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StaticService_AppendUploadServer = grpc.ClientStreamingServer[UploadChunk, ResumableUpload]
+
+func _StaticService_FinishUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumableUpload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StaticServiceServer).FinishUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StaticService_FinishUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StaticServiceServer).FinishUpload(ctx, req.(*ResumableUpload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StaticService_CancelUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumableUpload)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StaticServiceServer).CancelUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StaticService_CancelUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StaticServiceServer).CancelUpload(ctx, req.(*ResumableUpload))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _StaticService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Nothing)
 	if err := dec(in); err != nil {
@@ -211,6 +443,26 @@ var StaticService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStatic",
 			Handler:    _StaticService_GetStatic_Handler,
 		},
+		{
+			MethodName: "GetStaticFileInfo",
+			Handler:    _StaticService_GetStaticFileInfo_Handler,
+		},
+		{
+			MethodName: "CreateUpload",
+			Handler:    _StaticService_CreateUpload_Handler,
+		},
+		{
+			MethodName: "GetUpload",
+			Handler:    _StaticService_GetUpload_Handler,
+		},
+		{
+			MethodName: "FinishUpload",
+			Handler:    _StaticService_FinishUpload_Handler,
+		},
+		{
+			MethodName: "CancelUpload",
+			Handler:    _StaticService_CancelUpload_Handler,
+		},
 		{
 			MethodName: "Ping",
 			Handler:    _StaticService_Ping_Handler,
@@ -227,6 +479,16 @@ var StaticService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _StaticService_GetStaticFile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetStaticVariant",
+			Handler:       _StaticService_GetStaticVariant_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "AppendUpload",
+			Handler:       _StaticService_AppendUpload_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "static.proto",
 }
@@ -1,7 +1,6 @@
 package static
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -9,66 +8,93 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/grpcerr"
 	staticProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"go.uber.org/zap"
 )
 
+const fileChunkSize = 32 << 10
+
+var ErrInvalidRange = errors.New("invalid file range")
+
 type Grpc struct {
 	staticProto.UnimplementedStaticServiceServer
 	staticUC usecase.StaticUseCase
+	uploadUC usecase.StaticUpload
 }
 
-func NewStaticGrpc(staticUC usecase.StaticUseCase) *Grpc {
-	return &Grpc{staticUC: staticUC}
+func NewStaticGrpc(staticUC usecase.StaticUseCase, uploadUC usecase.StaticUpload) *Grpc {
+	return &Grpc{staticUC: staticUC, uploadUC: uploadUC}
 }
 
-func (service *Grpc) GetStatic(_ context.Context, static *staticProto.Static) (*staticProto.Static, error) {
+func (service *Grpc) GetStatic(ctx context.Context, static *staticProto.Static) (*staticProto.Static, error) {
 	staticID, err := uuid.Parse(static.GetId())
 	if err != nil {
-		return nil, err
+		return nil, grpcerr.InvalidArgument("id", err)
 	}
-	uri, err := service.staticUC.GetStatic(staticID)
+	uri, err := service.staticUC.GetStatic(ctx, staticID)
 	if err != nil {
-		return nil, err
+		return nil, staticErrors.ToStatus(err)
 	}
 	return &staticProto.Static{Uri: uri}, nil
 }
 
-func (service *Grpc) UploadStatic(stream staticProto.StaticService_UploadStaticServer) error {
-	zap.L().Info("Begin upload static")
-	var bytesAvatar []byte
+// uploadStreamReader отдает содержимое входящего потока по мере чтения,
+// поэтому файл не накапливается в памяти сверх того, что прочитал сервис.
+// recv получает следующую часть из потока
+type uploadStreamReader struct {
+	recv    func() ([]byte, error)
+	pending []byte
+}
 
-	for {
-		chunk, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
+func (r *uploadStreamReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		chunk, err := r.recv()
 		if err != nil {
-			zap.L().Error("Error getting chunk", zap.Error(err))
-			return err
-		}
-		bytesAvatar = append(bytesAvatar, chunk.GetChunk()...)
-	}
-
-	zap.L().Info("All chunks received, begin processing")
-	reader := bytes.NewReader(bytesAvatar)
-	staticID, err := service.staticUC.UploadStatic(reader)
-	if err != nil {
-		zap.L().Error("Error uploading static", zap.Error(err))
-		switch {
-		case errors.Is(err, usecase.ErrStaticTooBigFile):
-			return stream.SendAndClose(&staticProto.Static{Error: "ErrStaticTooBigFile"})
-		case errors.Is(err, usecase.ErrStaticNotImage):
-			return stream.SendAndClose(&staticProto.Static{Error: "ErrStaticNotImage"})
-		case errors.Is(err, usecase.ErrStaticImageDimensions):
-			return stream.SendAndClose(&staticProto.Static{Error: "ErrStaticImageDimensions"})
-		default:
-			return err
+			return 0, err
 		}
+		r.pending = chunk
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (service *Grpc) UploadStatic(stream staticProto.StaticService_UploadStaticServer) error {
+	logger := middleware.GetLogger(stream.Context())
+	logger.Info("Begin upload static")
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return staticErrors.ToStatus(usecase.ErrStaticNotImage)
+	}
+	if err != nil {
+		logger.Error("Error getting chunk", zap.Error(err))
+		return staticErrors.ToStatus(err)
 	}
 
-	zap.L().Info("Static file uploaded", zap.String("id", staticID.String()))
+	// Клиенты без метаданных сразу присылают содержимое, к ним применяются общие ограничения
+	metadata := fromProtoMetadata(first.GetMetadata())
+	logger.Info("Upload metadata received", zap.String("filename", metadata.Filename),
+		zap.String("purpose", string(metadata.Purpose)), zap.Int64("size", metadata.Size))
+
+	reader := &uploadStreamReader{
+		recv: func() ([]byte, error) {
+			message, err := stream.Recv()
+			return message.GetChunk(), err
+		},
+		pending: first.GetChunk(),
+	}
+	staticID, err := service.staticUC.UploadStatic(stream.Context(), reader, metadata)
+	if err != nil {
+		logger.Error("Error uploading static", zap.Error(err))
+		return staticErrors.ToStatus(err)
+	}
+
+	logger.Info("Static file uploaded", zap.String("id", staticID.String()))
 	return stream.SendAndClose(&staticProto.Static{Id: staticID.String()})
 }
 func (service *Grpc) GetStaticFile(
@@ -77,29 +103,206 @@ func (service *Grpc) GetStaticFile(
 ) error {
 	uri, err := url.QueryUnescape(static.GetUri())
 	if err != nil {
-		return err
+		return grpcerr.InvalidArgument("uri", err)
 	}
-	file, err := service.staticUC.GetStaticFile(uri)
+	file, err := service.staticUC.GetStaticFile(stream.Context(), uri)
 	if err != nil {
-		return err
+		return staticErrors.ToStatus(err)
+	}
+	if closer, ok := file.(io.Closer); ok {
+		defer closer.Close()
 	}
-	buffer := make([]byte, 1024)
+
+	if static.GetOffset() < 0 || static.GetLength() < 0 {
+		return staticErrors.ToStatus(ErrInvalidRange)
+	}
+	var reader io.Reader = file
+	if static.GetOffset() > 0 {
+		if _, err := file.Seek(static.GetOffset(), io.SeekStart); err != nil {
+			return staticErrors.ToStatus(err)
+		}
+	}
+	if static.GetLength() > 0 {
+		reader = io.LimitReader(file, static.GetLength())
+	}
+	return staticErrors.ToStatus(sendFile(reader, stream))
+}
+
+func (service *Grpc) GetStaticFileInfo(ctx context.Context, static *staticProto.Static) (*staticProto.StaticInfo, error) {
+	uri, err := url.QueryUnescape(static.GetUri())
+	if err != nil {
+		return nil, grpcerr.InvalidArgument("uri", err)
+	}
+	info, err := service.staticUC.GetStaticFileInfo(ctx, uri)
+	if err != nil {
+		return nil, staticErrors.ToStatus(err)
+	}
+	return &staticProto.StaticInfo{
+		Uri:         static.GetUri(),
+		Size:        info.Size,
+		Etag:        info.ETag,
+		ModTime:     info.ModTime.Unix(),
+		ContentType: info.ContentType,
+	}, nil
+}
+
+func (service *Grpc) GetStaticVariant(
+	variant *staticProto.ImageVariant,
+	stream staticProto.StaticService_GetStaticVariantServer,
+) error {
+	staticID, err := uuid.Parse(variant.GetId())
+	if err != nil {
+		return grpcerr.InvalidArgument("id", err)
+	}
+	file, err := service.staticUC.GetStaticVariant(stream.Context(), staticID, entity.ImageVariant{
+		Width:  int(variant.GetWidth()),
+		Height: int(variant.GetHeight()),
+		Fit:    entity.ImageFit(variant.GetFit()),
+		Format: entity.ImageFormat(variant.GetFormat()),
+	})
+	if err != nil {
+		return staticErrors.ToStatus(err)
+	}
+	if closer, ok := file.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return staticErrors.ToStatus(sendFile(file, stream))
+}
+
+// sendFile передает файл в поток частями
+func sendFile(file io.Reader, stream staticProto.StaticService_GetStaticFileServer) error {
+	buffer := make([]byte, fileChunkSize)
 	for {
-		bytesCount, err := file.Read(buffer)
-		if err == io.EOF {
-			break
+		bytesCount, readErr := file.Read(buffer)
+		if bytesCount > 0 {
+			if err := stream.Send(&staticProto.StaticUpload{Chunk: buffer[:bytesCount]}); err != nil {
+				return err
+			}
 		}
-		if err != nil {
-			return err
+		if readErr == io.EOF {
+			break
 		}
-		err = stream.Send(&staticProto.StaticUpload{Chunk: buffer[:bytesCount]})
-		if err != nil {
-			return err
+		if readErr != nil {
+			return readErr
 		}
 	}
 	return nil
 }
 
+func fromProtoMetadata(metadata *staticProto.UploadMetadata) entity.UploadMetadata {
+	return entity.UploadMetadata{
+		Filename:    metadata.GetFilename(),
+		ContentType: metadata.GetContentType(),
+		Purpose:     entity.UploadPurpose(metadata.GetPurpose()),
+		Size:        metadata.GetSize(),
+	}
+}
+
+func toProtoUpload(upload *entity.ResumableUpload) *staticProto.ResumableUpload {
+	return &staticProto.ResumableUpload{
+		Id:      upload.ID.String(),
+		OwnerId: upload.OwnerID.String(),
+		Metadata: &staticProto.UploadMetadata{
+			Filename:    upload.Metadata.Filename,
+			ContentType: upload.Metadata.ContentType,
+			Purpose:     string(upload.Metadata.Purpose),
+			Size:        upload.Metadata.Size,
+		},
+		Offset:    upload.Offset,
+		ExpiresAt: upload.ExpiresAt.Unix(),
+	}
+}
+
+// parseUploadIDs разбирает id загрузки и ее владельца
+func parseUploadIDs(id, ownerID string) (uuid.UUID, uuid.UUID, error) {
+	uploadID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, grpcerr.InvalidArgument("id", err)
+	}
+	owner, err := uuid.Parse(ownerID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, grpcerr.InvalidArgument("owner_id", err)
+	}
+	return uploadID, owner, nil
+}
+
+func (service *Grpc) CreateUpload(ctx context.Context, request *staticProto.ResumableUpload) (*staticProto.ResumableUpload, error) {
+	ownerID, err := uuid.Parse(request.GetOwnerId())
+	if err != nil {
+		return nil, grpcerr.InvalidArgument("owner_id", err)
+	}
+	upload, err := service.uploadUC.CreateUpload(ctx, ownerID, fromProtoMetadata(request.GetMetadata()))
+	if err != nil {
+		return nil, staticErrors.ToStatus(err)
+	}
+	middleware.GetLogger(ctx).Info("Upload created", zap.String("id", upload.ID.String()), zap.Int64("size", upload.Metadata.Size))
+	return toProtoUpload(upload), nil
+}
+
+func (service *Grpc) GetUpload(ctx context.Context, request *staticProto.ResumableUpload) (*staticProto.ResumableUpload, error) {
+	uploadID, ownerID, err := parseUploadIDs(request.GetId(), request.GetOwnerId())
+	if err != nil {
+		return nil, err
+	}
+	upload, err := service.uploadUC.GetUpload(ctx, uploadID, ownerID)
+	if err != nil {
+		return nil, staticErrors.ToStatus(err)
+	}
+	return toProtoUpload(upload), nil
+}
+
+func (service *Grpc) AppendUpload(stream staticProto.StaticService_AppendUploadServer) error {
+	logger := middleware.GetLogger(stream.Context())
+	first, err := stream.Recv()
+	if err != nil {
+		logger.Error("Error getting upload chunk", zap.Error(err))
+		return staticErrors.ToStatus(err)
+	}
+	uploadID, ownerID, err := parseUploadIDs(first.GetId(), first.GetOwnerId())
+	if err != nil {
+		return err
+	}
+
+	reader := &uploadStreamReader{
+		recv: func() ([]byte, error) {
+			message, err := stream.Recv()
+			return message.GetChunk(), err
+		},
+		pending: first.GetChunk(),
+	}
+	upload, err := service.uploadUC.AppendUpload(stream.Context(), uploadID, ownerID, first.GetOffset(), reader)
+	if err != nil {
+		logger.Error("Error appending upload chunk", zap.String("id", uploadID.String()), zap.Error(err))
+		return staticErrors.ToStatus(err)
+	}
+	return stream.SendAndClose(toProtoUpload(upload))
+}
+
+func (service *Grpc) FinishUpload(ctx context.Context, request *staticProto.ResumableUpload) (*staticProto.Static, error) {
+	uploadID, ownerID, err := parseUploadIDs(request.GetId(), request.GetOwnerId())
+	if err != nil {
+		return nil, err
+	}
+	purpose := entity.UploadPurpose(request.GetMetadata().GetPurpose())
+	staticID, err := service.uploadUC.FinishUpload(ctx, uploadID, ownerID, purpose)
+	if err != nil {
+		middleware.GetLogger(ctx).Error("Error finishing upload", zap.String("id", uploadID.String()), zap.Error(err))
+		return nil, staticErrors.ToStatus(err)
+	}
+	return &staticProto.Static{Id: staticID.String()}, nil
+}
+
+func (service *Grpc) CancelUpload(ctx context.Context, request *staticProto.ResumableUpload) (*staticProto.Nothing, error) {
+	uploadID, ownerID, err := parseUploadIDs(request.GetId(), request.GetOwnerId())
+	if err != nil {
+		return nil, err
+	}
+	if err := service.uploadUC.CancelUpload(ctx, uploadID, ownerID); err != nil {
+		return nil, staticErrors.ToStatus(err)
+	}
+	return &staticProto.Nothing{}, nil
+}
+
 func (service *Grpc) Ping(context.Context, *staticProto.Nothing) (*staticProto.Nothing, error) {
 	return &staticProto.Nothing{}, nil
 }
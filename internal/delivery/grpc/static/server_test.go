@@ -6,10 +6,15 @@ import (
 	"errors"
 	"io"
 	"testing"
+	"time"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"github.com/google/uuid"
 	staticProto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static/proto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,29 +24,50 @@ type mockStaticUseCase struct {
 	mock.Mock
 }
 
-func (m *mockStaticUseCase) GetStatic(id uuid.UUID) (string, error) {
+func (m *mockStaticUseCase) GetStatic(_ context.Context, id uuid.UUID) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
-func (m *mockStaticUseCase) UploadStatic(reader io.ReadSeeker) (uuid.UUID, error) {
-	args := m.Called(reader)
+func (m *mockStaticUseCase) UploadStatic(_ context.Context, reader io.Reader, metadata entity.UploadMetadata) (uuid.UUID, error) {
+	args := m.Called(reader, metadata)
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
-func (m *mockStaticUseCase) GetStaticFile(uri string) (io.ReadSeeker, error) {
+func (m *mockStaticUseCase) GetStaticFile(_ context.Context, uri string) (io.ReadSeeker, error) {
 	args := m.Called(uri)
 	return args.Get(0).(io.ReadSeeker), args.Error(1)
 }
 
-func (m *mockStaticUseCase) GetAvatar(id uuid.UUID) (string, error) {
+func (m *mockStaticUseCase) GetAvatar(_ context.Context, id uuid.UUID) (string, error) {
 	args := m.Called(id)
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockStaticUseCase) GetStaticFileInfo(_ context.Context, uri string) (*entity.BlobInfo, error) {
+	args := m.Called(uri)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BlobInfo), args.Error(1)
+}
+
+func (m *mockStaticUseCase) CollectGarbage(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *mockStaticUseCase) GetStaticVariant(_ context.Context, id uuid.UUID, variant entity.ImageVariant) (io.ReadSeeker, error) {
+	args := m.Called(id, variant)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadSeeker), args.Error(1)
+}
+
 func TestGetStatic_Success(t *testing.T) {
 	mockUC := new(mockStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	staticID := uuid.New()
 	expectedURI := "http://example.com/static/" + staticID.String()
@@ -56,7 +82,7 @@ func TestGetStatic_Success(t *testing.T) {
 
 func TestGetStatic_InvalidID(t *testing.T) {
 	mockUC := new(mockStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	result, err := grpcServer.GetStatic(context.Background(), &staticProto.Static{Id: "invalid-id"})
 
@@ -66,12 +92,13 @@ func TestGetStatic_InvalidID(t *testing.T) {
 
 func TestGetStaticFile_Success(t *testing.T) {
 	mockUC := new(mockStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	staticURI := "http://example.com/static/file"
 	mockUC.On("GetStaticFile", staticURI).Return(bytes.NewReader([]byte("file content")), nil)
 
 	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
 	stream.On("Send", &staticProto.StaticUpload{Chunk: []byte("file content")}).Return(nil)
 
 	err := grpcServer.GetStaticFile(&staticProto.Static{Uri: staticURI}, stream)
@@ -83,13 +110,14 @@ func TestGetStaticFile_Success(t *testing.T) {
 
 func TestGetStaticFile_Error(t *testing.T) {
 	mockUC := new(mockStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 	readSeeker := bytes.NewReader([]byte("file content"))
 
 	staticURI := "http://example.com/static/file"
 	mockUC.On("GetStaticFile", staticURI).Return(readSeeker, errors.New("file not found"))
 
 	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
 
 	err := grpcServer.GetStaticFile(&staticProto.Static{Uri: staticURI}, stream)
 
@@ -98,9 +126,232 @@ func TestGetStaticFile_Error(t *testing.T) {
 	mockUC.AssertExpectations(t)
 }
 
+func TestGetStaticFile_Range(t *testing.T) {
+	mockUC := new(mockStaticUseCase)
+	grpcServer := NewStaticGrpc(mockUC, nil)
+
+	staticURI := "http://example.com/static/file"
+	mockUC.On("GetStaticFile", staticURI).Return(bytes.NewReader([]byte("file content")), nil)
+
+	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
+	stream.On("Send", &staticProto.StaticUpload{Chunk: []byte("con")}).Return(nil)
+
+	err := grpcServer.GetStaticFile(&staticProto.Static{Uri: staticURI, Offset: 5, Length: 3}, stream)
+
+	assert.NoError(t, err)
+	stream.AssertExpectations(t)
+
+	err = grpcServer.GetStaticFile(&staticProto.Static{Uri: staticURI, Offset: -1}, stream)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+	assert.ErrorIs(t, staticErrors.FromStatus(err), ErrInvalidRange)
+}
+
+func TestGetStaticFileInfo(t *testing.T) {
+	mockUC := new(mockStaticUseCase)
+	grpcServer := NewStaticGrpc(mockUC, nil)
+
+	staticURI := "static_files/images/file.webp"
+	modTime := time.Unix(1700000000, 0)
+	mockUC.On("GetStaticFileInfo", staticURI).Return(&entity.BlobInfo{
+		Size:        12,
+		ContentType: "image/webp",
+		ModTime:     modTime,
+		ETag:        "abc",
+	}, nil)
+
+	info, err := grpcServer.GetStaticFileInfo(context.Background(), &staticProto.Static{Uri: staticURI})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), info.Size)
+	assert.Equal(t, "abc", info.Etag)
+	assert.Equal(t, modTime.Unix(), info.ModTime)
+	assert.Equal(t, "image/webp", info.ContentType)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetStaticVariant_Success(t *testing.T) {
+	mockUC := new(mockStaticUseCase)
+	grpcServer := NewStaticGrpc(mockUC, nil)
+
+	staticID := uuid.New()
+	variant := entity.ImageVariant{Width: 200, Height: 200, Fit: entity.ImageFitCover, Format: entity.ImageFormatWebP}
+	mockUC.On("GetStaticVariant", staticID, variant).Return(bytes.NewReader([]byte("variant")), nil)
+
+	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
+	stream.On("Send", &staticProto.StaticUpload{Chunk: []byte("variant")}).Return(nil)
+
+	err := grpcServer.GetStaticVariant(&staticProto.ImageVariant{
+		Id:     staticID.String(),
+		Width:  200,
+		Height: 200,
+		Fit:    "cover",
+		Format: "webp",
+	}, stream)
+
+	assert.NoError(t, err)
+	stream.AssertExpectations(t)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetStaticVariant_InvalidID(t *testing.T) {
+	mockUC := new(mockStaticUseCase)
+	grpcServer := NewStaticGrpc(mockUC, nil)
+
+	err := grpcServer.GetStaticVariant(&staticProto.ImageVariant{Id: "invalid-id"}, &mockStream{})
+
+	assert.Error(t, err)
+	mockUC.AssertNotCalled(t, "GetStaticVariant")
+}
+
+func TestUploadStatic_MetadataFirst(t *testing.T) {
+	mockUC := new(mockStaticUseCase)
+	grpcServer := NewStaticGrpc(mockUC, nil)
+	staticID := uuid.New()
+
+	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
+	stream.On("Recv").Return(&staticProto.StaticUpload{Metadata: &staticProto.UploadMetadata{
+		Filename: "avatar.png", ContentType: "image/png", Purpose: "avatar", Size: 11,
+	}}, nil).Once()
+	stream.On("Recv").Return(&staticProto.StaticUpload{Chunk: []byte("image ")}, nil).Once()
+	stream.On("Recv").Return(&staticProto.StaticUpload{Chunk: []byte("data")}, nil).Once()
+	stream.On("Recv").Return((*staticProto.StaticUpload)(nil), io.EOF)
+	stream.On("SendAndClose", &staticProto.Static{Id: staticID.String()}).Return(nil)
+
+	expected := entity.UploadMetadata{
+		Filename: "avatar.png", ContentType: "image/png", Purpose: entity.UploadPurposeAvatar, Size: 11,
+	}
+	mockUC.On("UploadStatic", mock.Anything, expected).Return(staticID, nil).Run(func(args mock.Arguments) {
+		data, err := io.ReadAll(args.Get(0).(io.Reader))
+		assert.NoError(t, err)
+		assert.Equal(t, "image data", string(data))
+	})
+
+	err := grpcServer.UploadStatic(stream)
+
+	assert.NoError(t, err)
+	stream.AssertExpectations(t)
+	mockUC.AssertExpectations(t)
+}
+
+func TestUploadStatic_TooBig(t *testing.T) {
+	mockUC := new(mockStaticUseCase)
+	grpcServer := NewStaticGrpc(mockUC, nil)
+
+	stream := &mockStream{}
+	stream.On("Context").Return(context.Background()).Maybe()
+	stream.On("Recv").Return(&staticProto.StaticUpload{Chunk: []byte("data")}, nil).Once()
+	mockUC.On("UploadStatic", mock.Anything, entity.UploadMetadata{}).Return(uuid.Nil, usecase.ErrStaticTooBigFile)
+
+	err := grpcServer.UploadStatic(stream)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.ErrorIs(t, staticErrors.FromStatus(err), usecase.ErrStaticTooBigFile)
+	stream.AssertExpectations(t)
+}
+
+// mockChunkStream - поток частей загрузки AppendUpload
+type mockChunkStream struct {
+	mockStream
+	chunks   []*staticProto.UploadChunk
+	response *staticProto.ResumableUpload
+}
+
+func (m *mockChunkStream) Recv() (*staticProto.UploadChunk, error) {
+	if len(m.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := m.chunks[0]
+	m.chunks = m.chunks[1:]
+	return chunk, nil
+}
+
+func (m *mockChunkStream) Context() context.Context {
+	return context.Background()
+}
+
+func (m *mockChunkStream) SendAndClose(resp *staticProto.ResumableUpload) error {
+	m.response = resp
+	return nil
+}
+
+func TestAppendUpload(t *testing.T) {
+	mockUploadUC := new(mockStaticUploadUseCase)
+	grpcServer := NewStaticGrpc(new(mockStaticUseCase), mockUploadUC)
+	uploadID, ownerID := uuid.New(), uuid.New()
+
+	stream := &mockChunkStream{chunks: []*staticProto.UploadChunk{
+		{Id: uploadID.String(), OwnerId: ownerID.String(), Offset: 4},
+		{Chunk: []byte("new ")},
+		{Chunk: []byte("part")},
+	}}
+	mockUploadUC.On("AppendUpload", uploadID, ownerID, int64(4), mock.Anything).Return(&entity.ResumableUpload{
+		ID:       uploadID,
+		OwnerID:  ownerID,
+		Metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvert, Size: 20},
+		Offset:   12,
+	}, nil).Run(func(args mock.Arguments) {
+		data, err := io.ReadAll(args.Get(3).(io.Reader))
+		assert.NoError(t, err)
+		assert.Equal(t, "new part", string(data))
+	})
+
+	err := grpcServer.AppendUpload(stream)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), stream.response.GetOffset())
+	assert.Equal(t, "advert", stream.response.GetMetadata().GetPurpose())
+	mockUploadUC.AssertExpectations(t)
+}
+
+func TestAppendUpload_InvalidID(t *testing.T) {
+	grpcServer := NewStaticGrpc(new(mockStaticUseCase), new(mockStaticUploadUseCase))
+
+	stream := &mockChunkStream{chunks: []*staticProto.UploadChunk{{Id: "invalid", OwnerId: uuid.NewString()}}}
+
+	err := grpcServer.AppendUpload(stream)
+	assert.Error(t, err)
+}
+
+type mockStaticUploadUseCase struct {
+	mock.Mock
+}
+
+func (m *mockStaticUploadUseCase) CreateUpload(_ context.Context, ownerID uuid.UUID, metadata entity.UploadMetadata) (*entity.ResumableUpload, error) {
+	args := m.Called(ownerID, metadata)
+	return args.Get(0).(*entity.ResumableUpload), args.Error(1)
+}
+
+func (m *mockStaticUploadUseCase) GetUpload(_ context.Context, uploadID, ownerID uuid.UUID) (*entity.ResumableUpload, error) {
+	args := m.Called(uploadID, ownerID)
+	return args.Get(0).(*entity.ResumableUpload), args.Error(1)
+}
+
+func (m *mockStaticUploadUseCase) AppendUpload(_ context.Context, uploadID, ownerID uuid.UUID, offset int64, data io.Reader) (*entity.ResumableUpload, error) {
+	args := m.Called(uploadID, ownerID, offset, data)
+	return args.Get(0).(*entity.ResumableUpload), args.Error(1)
+}
+
+func (m *mockStaticUploadUseCase) FinishUpload(_ context.Context, uploadID, ownerID uuid.UUID, purpose entity.UploadPurpose) (uuid.UUID, error) {
+	args := m.Called(uploadID, ownerID, purpose)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *mockStaticUploadUseCase) CancelUpload(_ context.Context, uploadID, ownerID uuid.UUID) error {
+	args := m.Called(uploadID, ownerID)
+	return args.Error(0)
+}
+
+func (m *mockStaticUploadUseCase) ExpireUploads(_ context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 func TestPing(t *testing.T) {
 	mockUC := new(mockStaticUseCase)
-	grpcServer := NewStaticGrpc(mockUC)
+	grpcServer := NewStaticGrpc(mockUC, nil)
 
 	result, err := grpcServer.Ping(context.Background(), &staticProto.Nothing{})
 
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// AddressEndpoint - адресная книга пользователя
+type AddressEndpoint struct {
+	addressUC      usecase.AddressUseCase
+	sessionManager *utils.SessionManager
+}
+
+func NewAddressEndpoint(addressUC usecase.AddressUseCase, sessionManager *utils.SessionManager) *AddressEndpoint {
+	return &AddressEndpoint{
+		addressUC:      addressUC,
+		sessionManager: sessionManager,
+	}
+}
+
+func (h *AddressEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.HandleFunc("/addresses", h.GetAddresses).Methods(http.MethodGet)
+	protected.HandleFunc("/addresses", h.AddAddress).Methods(http.MethodPost)
+	protected.HandleFunc("/addresses/{address_id}", h.UpdateAddress).Methods(http.MethodPut)
+	protected.HandleFunc("/addresses/{address_id}", h.DeleteAddress).Methods(http.MethodDelete)
+	protected.HandleFunc("/addresses/{address_id}/default", h.SetDefault).Methods(http.MethodPost)
+}
+
+// GetAddresses godoc
+// @Summary Get address book
+// @Description Returns the saved addresses of the current user, the default one first.
+// @Tags addresses
+// @Produce json
+// @Success 200 {array} dto.AddressResponse "Addresses"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 500 {object} utils.ErrResponse "Failed to get addresses"
+// @Router /api/v1/addresses [get]
+func (h *AddressEndpoint) GetAddresses(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	addresses, err := h.addressUC.GetAddresses(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get addresses")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, addresses)
+}
+
+// AddAddress godoc
+// @Summary Add address
+// @Description Saves an address to the address book of the current user. The first address becomes the default one.
+// @Description recipient_name, phone, city, street and building are required; phone has 10-15 digits, postcode has 6 digits.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param address body dto.AddressRequest true "Address"
+// @Success 201 {object} dto.AddressResponse "Saved address"
+// @Failure 400 {object} utils.ErrResponse "Invalid address"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 409 {object} utils.ErrResponse "Address book is full"
+// @Failure 500 {object} utils.ErrResponse "Failed to add address"
+// @Router /api/v1/addresses [post]
+func (h *AddressEndpoint) AddAddress(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req dto.AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+
+	address, err := h.addressUC.AddAddress(r.Context(), userID, req)
+	if err != nil {
+		h.handleError(w, r, err, "failed to add address")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusCreated, address)
+}
+
+// UpdateAddress godoc
+// @Summary Update address
+// @Description Replaces the fields of a saved address. is_default true makes it the default one, false keeps the flag as is.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Param address_id path string true "Address ID"
+// @Param address body dto.AddressRequest true "Address"
+// @Success 200 {object} dto.AddressResponse "Updated address"
+// @Failure 400 {object} utils.ErrResponse "Invalid address ID or address"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrResponse "Address not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to update address"
+// @Router /api/v1/addresses/{address_id} [put]
+func (h *AddressEndpoint) UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	addressID, userID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+
+	address, err := h.addressUC.UpdateAddress(r.Context(), addressID, userID, req)
+	if err != nil {
+		h.handleError(w, r, err, "failed to update address")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, address)
+}
+
+// DeleteAddress godoc
+// @Summary Delete address
+// @Description Deletes a saved address. If it was the default one, the most recently added address becomes the default.
+// @Description Purchases keep the address they were placed with.
+// @Tags addresses
+// @Param address_id path string true "Address ID"
+// @Success 204 "Address deleted"
+// @Failure 400 {object} utils.ErrResponse "Invalid address ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrResponse "Address not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to delete address"
+// @Router /api/v1/addresses/{address_id} [delete]
+func (h *AddressEndpoint) DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	addressID, userID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.addressUC.DeleteAddress(r.Context(), addressID, userID); err != nil {
+		h.handleError(w, r, err, "failed to delete address")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetDefault godoc
+// @Summary Set default address
+// @Description Makes a saved address the default one of the current user.
+// @Tags addresses
+// @Produce json
+// @Param address_id path string true "Address ID"
+// @Success 200 {object} dto.AddressResponse "Default address"
+// @Failure 400 {object} utils.ErrResponse "Invalid address ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 404 {object} utils.ErrResponse "Address not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to set default address"
+// @Router /api/v1/addresses/{address_id}/default [post]
+func (h *AddressEndpoint) SetDefault(w http.ResponseWriter, r *http.Request) {
+	addressID, userID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	address, err := h.addressUC.SetDefault(r.Context(), addressID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to set default address")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, address)
+}
+
+func (h *AddressEndpoint) parseRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	addressID, err := uuid.Parse(mux.Vars(r)["address_id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return addressID, userID, true
+}
+
+// sendAddressError отвечает на ошибки адреса и сообщает, была ли ошибка таковой
+func sendAddressError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, usecase.ErrAddressBadRequest):
+		utils.SendErrorResponse(w, http.StatusBadRequest, usecase.ErrAddressBadRequest.Error())
+	case errors.Is(err, repository.ErrAddressNotFound):
+		utils.SendErrorResponse(w, http.StatusNotFound, "address not found")
+	case errors.Is(err, repository.ErrAddressLimit):
+		utils.SendErrorResponse(w, http.StatusConflict, "address book is full")
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *AddressEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	logger := middleware.GetLogger(r.Context())
+
+	if sendAddressError(w, err) {
+		logger.Warn(message, zap.Error(err))
+		return
+	}
+	logger.Error(message, zap.Error(err))
+	utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/gorilla/mux"
+	"github.com/microcosm-cc/bluemonday"
+	"go.uber.org/zap"
+)
+
+const maxImportFileSize = 10 << 20
+
+var (
+	ErrUnsupportedFileFormat = errors.New("unsupported file format, use csv or json")
+	ErrInvalidImportFile     = errors.New("invalid import file")
+)
+
+type AdvertImportEndpoint struct {
+	importUC       usecase.AdvertImportUseCase
+	sessionManager *utils.SessionManager
+	policy         *bluemonday.Policy
+}
+
+func NewAdvertImportEndpoint(importUC usecase.AdvertImportUseCase,
+	sessionManager *utils.SessionManager,
+	policy *bluemonday.Policy) *AdvertImportEndpoint {
+	return &AdvertImportEndpoint{
+		importUC:       importUC,
+		sessionManager: sessionManager,
+		policy:         policy,
+	}
+}
+
+func (h *AdvertImportEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.HandleFunc("/adverts/import", h.Import).Methods("POST")
+	protected.HandleFunc("/adverts/export", h.Export).Methods("GET")
+}
+
+// fileFormat определяет формат файла по параметру format, а при его отсутствии - по Content-Type
+func fileFormat(r *http.Request) (dto.AdvertFileFormat, error) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		contentType := r.Header.Get("Content-Type")
+		switch {
+		case strings.Contains(contentType, "csv"):
+			format = string(dto.AdvertFileFormatCSV)
+		default:
+			format = string(dto.AdvertFileFormatJSON)
+		}
+	}
+
+	switch dto.AdvertFileFormat(format) {
+	case dto.AdvertFileFormatCSV, dto.AdvertFileFormatJSON:
+		return dto.AdvertFileFormat(format), nil
+	default:
+		return "", ErrUnsupportedFileFormat
+	}
+}
+
+// Import godoc
+// @Summary Import adverts from a CSV or JSON file
+// @Description Create or update the seller's adverts in bulk. Rows with an external_sku are upserted,
+// @Description rows without it are created. Each row is validated separately and reported in the response.
+// @Description Exported reserved and scheduled rows are skipped, adverts reserved by a purchase are not overwritten.
+// @Tags adverts
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "File format: csv or json (defaults to Content-Type)"
+// @Param dry_run query bool false "Only validate rows without saving"
+// @Success 200 {object} dto.AdvertImportReport "Import report"
+// @Failure 400 {object} utils.ErrResponse "Invalid import file"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 413 {object} utils.ErrResponse "Too many rows"
+// @Failure 500 {object} utils.ErrResponse "Failed to import adverts"
+// @Router /api/v1/adverts/import [post]
+func (h *AdvertImportEndpoint) Import(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("import adverts request")
+
+	format, err := fileFormat(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "unsupported import format", nil)
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			h.sendError(writer, r, http.StatusBadRequest, ErrBadRequest, "invalid dry_run parameter", nil)
+			return
+		}
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	rows, err := utils.DecodeAdvertImport(http.MaxBytesReader(writer, r.Body, maxImportFileSize), format)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.sendError(writer, r, http.StatusRequestEntityTooLarge, ErrTooLargeFile, "import file too large", nil)
+			return
+		}
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidImportFile, "failed to decode import file",
+			map[string]string{"error": err.Error()})
+		return
+	}
+	utils.SanitizeImportRows(rows, h.policy)
+
+	report, err := h.importUC.Import(r.Context(), userID, rows, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrImportTooManyRows):
+			h.sendError(writer, r, http.StatusRequestEntityTooLarge, usecase.ErrImportTooManyRows, "failed to import adverts", nil)
+		default:
+			h.sendError(writer, r, http.StatusInternalServerError, err, "failed to import adverts", nil)
+		}
+		return
+	}
+
+	logger.Info("adverts imported", zap.Int("created", report.Created), zap.Int("updated", report.Updated),
+		zap.Int("failed", report.Failed), zap.Int("skipped", report.Skipped), zap.Bool("dry_run", report.DryRun))
+	utils.SendJSONResponse(writer, http.StatusOK, report)
+}
+
+// Export godoc
+// @Summary Export the seller's adverts
+// @Description Download all adverts of the current seller in the import format.
+// @Tags adverts
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "File format: csv or json (default json)"
+// @Success 200 {array} dto.AdvertImportRow "Exported adverts"
+// @Failure 400 {object} utils.ErrResponse "Unsupported format"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to export adverts"
+// @Router /api/v1/adverts/export [get]
+func (h *AdvertImportEndpoint) Export(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("export adverts request")
+
+	format, err := fileFormat(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "unsupported export format", nil)
+		return
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	rows, err := h.importUC.Export(r.Context(), userID)
+	if err != nil {
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to export adverts", nil)
+		return
+	}
+
+	if format == dto.AdvertFileFormatCSV {
+		writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		writer.Header().Set("Content-Disposition", `attachment; filename="adverts.csv"`)
+	} else {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Header().Set("Content-Disposition", `attachment; filename="adverts.json"`)
+	}
+	writer.WriteHeader(http.StatusOK)
+	if err := utils.EncodeAdvertExport(writer, rows, format); err != nil {
+		logger.Error("failed to encode exported adverts", zap.Error(err))
+		return
+	}
+
+	logger.Info("adverts exported", zap.Int("count", len(rows)))
+}
+
+func (h *AdvertImportEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
+
+	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
+	utils.SendErrorResponse(w, statusCode, err.Error())
+}
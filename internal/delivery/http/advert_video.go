@@ -0,0 +1,189 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// AdvertVideoEndpoint - видео объявлений. Сами файлы отдаются ручками статики:
+// видео - по частям через /api/v1/files/stream/{id}, обложка - как вариант изображения
+type AdvertVideoEndpoint struct {
+	videoUC          usecase.AdvertVideoUseCase
+	staticGrpcClient static.StaticGrpcClient
+	sessionManager   *utils.SessionManager
+}
+
+func NewAdvertVideoEndpoint(videoUC usecase.AdvertVideoUseCase,
+	staticGrpcClient static.StaticGrpcClient,
+	sessionManager *utils.SessionManager) *AdvertVideoEndpoint {
+	return &AdvertVideoEndpoint{
+		videoUC:          videoUC,
+		staticGrpcClient: staticGrpcClient,
+		sessionManager:   sessionManager,
+	}
+}
+
+func (h *AdvertVideoEndpoint) ConfigureRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/adverts/{advertId}/videos", h.GetVideos).Methods(http.MethodGet)
+}
+
+func (h *AdvertVideoEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.HandleFunc("/adverts/{advertId}/videos", h.AddVideo).Methods(http.MethodPost)
+	protected.HandleFunc("/adverts/{advertId}/videos/{videoId}", h.DeleteVideo).Methods(http.MethodDelete)
+}
+
+// GetVideos godoc
+// @Summary Get advert videos
+// @Description Get videos attached to an advert in display order. A video is streamed with range requests
+// @Description from /api/v1/files/stream/{id}, its poster frame is available as an image variant of /api/v1/files/{id}.
+// @Tags adverts
+// @Produce json
+// @Param advertId path string true "Advert ID"
+// @Success 200 {array} dto.AdvertVideo "Advert videos"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get advert videos"
+// @Router /api/v1/adverts/{advertId}/videos [get]
+func (h *AdvertVideoEndpoint) GetVideos(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("get advert videos request")
+
+	advertID, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+
+	videos, err := h.videoUC.GetVideos(r.Context(), advertID)
+	if err != nil {
+		h.handleError(writer, r, err, "failed to get advert videos")
+		return
+	}
+
+	utils.SendJSONResponse(writer, http.StatusOK, videos)
+}
+
+// AddVideo godoc
+// @Summary Add a video to an advert
+// @Description Upload an MP4 or WebM video up to a minute long and attach it to the advert.
+// @Tags adverts
+// @Accept multipart/form-data
+// @Produce json
+// @Param advertId path string true "Advert ID"
+// @Param video formData file false "Video file to upload"
+// @Param upload_id query string false "ID of a completed resumable upload with purpose advert_video to use instead of the video field"
+// @Success 201 {object} dto.AdvertVideo "Video attached"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID, file not attached, not a video or too long"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 403 {object} utils.ErrResponse "Advert belongs to another seller"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 409 {object} utils.ErrResponse "Advert already has the maximum number of videos or upload is incomplete"
+// @Failure 413 {object} utils.ErrResponse "File size exceeds limit"
+// @Failure 415 {object} utils.ErrResponse "Video uploads are not supported"
+// @Failure 422 {object} utils.ErrResponse "Video was rejected by moderation"
+// @Failure 500 {object} utils.ErrResponse "Failed to add video"
+// @Router /api/v1/adverts/{advertId}/videos [post]
+func (h *AdvertVideoEndpoint) AddVideo(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("add advert video request")
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+	advertID, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+
+	videoID, err := receiveFile(h.staticGrpcClient, r, "video", userID, entity.UploadPurposeAdvertVideo)
+	if err != nil {
+		statusCode, respErr := uploadErrorStatus(err)
+		h.sendError(writer, r, statusCode, respErr, "failed to upload video", nil)
+		return
+	}
+
+	video, err := h.videoUC.AddVideo(r.Context(), advertID, videoID, userID)
+	if err != nil {
+		h.handleError(writer, r, err, "failed to add advert video")
+		return
+	}
+
+	logger.Info("advert video added", zap.String("advert_id", advertID.String()), zap.String("video_id", videoID.String()))
+	utils.SendJSONResponse(writer, http.StatusCreated, video)
+}
+
+// DeleteVideo godoc
+// @Summary Remove a video from an advert
+// @Tags adverts
+// @Param advertId path string true "Advert ID"
+// @Param videoId path string true "Video ID"
+// @Success 204 "Video removed"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert or video ID"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 403 {object} utils.ErrResponse "Advert belongs to another seller"
+// @Failure 404 {object} utils.ErrResponse "Advert or video not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to remove video"
+// @Router /api/v1/adverts/{advertId}/videos/{videoId} [delete]
+func (h *AdvertVideoEndpoint) DeleteVideo(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("delete advert video request")
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+	advertID, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+	videoID, err := uuid.Parse(mux.Vars(r)["videoId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid video ID", nil)
+		return
+	}
+
+	if err := h.videoUC.DeleteVideo(r.Context(), advertID, videoID, userID); err != nil {
+		h.handleError(writer, r, err, "failed to delete advert video")
+		return
+	}
+
+	logger.Info("advert video deleted", zap.String("advert_id", advertID.String()), zap.String("video_id", videoID.String()))
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdvertVideoEndpoint) handleError(writer http.ResponseWriter, r *http.Request, err error, contextInfo string) {
+	switch {
+	case errors.Is(err, usecase.ErrAdvertVideoAdvertNotFound), errors.Is(err, usecase.ErrAdvertVideoNotFound):
+		h.sendError(writer, r, http.StatusNotFound, err, contextInfo, nil)
+	case errors.Is(err, usecase.ErrAdvertVideoForbidden):
+		h.sendError(writer, r, http.StatusForbidden, err, contextInfo, nil)
+	case errors.Is(err, usecase.ErrAdvertVideoLimit):
+		h.sendError(writer, r, http.StatusConflict, usecase.ErrAdvertVideoLimit, contextInfo, nil)
+	default:
+		h.sendError(writer, r, http.StatusInternalServerError, err, contextInfo, nil)
+	}
+}
+
+func (h *AdvertVideoEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
+
+	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
+	utils.SendErrorResponse(w, statusCode, err.Error())
+}
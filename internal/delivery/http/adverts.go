@@ -1,8 +1,6 @@
 package http
 
 import (
-	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -17,12 +15,11 @@ import (
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/gorilla/mux"
 	"github.com/microcosm-cc/bluemonday"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 var (
@@ -44,17 +41,20 @@ type AdvertEndpoint struct {
 	advertUC         usecase.AdvertUseCase
 	staticGrpcClient static.StaticGrpcClient
 	sessionManager   *utils.SessionManager
+	idempotency      *middleware.IdempotencyMiddleware
 	policy           *bluemonday.Policy
 }
 
 func NewAdvertEndpoint(advertUC usecase.AdvertUseCase,
 	staticGrpcClient static.StaticGrpcClient,
 	sessionManager *utils.SessionManager,
+	idempotency *middleware.IdempotencyMiddleware,
 	policy *bluemonday.Policy) *AdvertEndpoint {
 	return &AdvertEndpoint{
 		advertUC:         advertUC,
 		staticGrpcClient: staticGrpcClient,
 		sessionManager:   sessionManager,
+		idempotency:      idempotency,
 		policy:           policy,
 	}
 }
@@ -73,7 +73,7 @@ func (h *AdvertEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
 	sessionMiddleware := middleware.NewAuthMiddleware(h.sessionManager)
 	protected.Use(sessionMiddleware.SessionMiddleware)
 
-	protected.HandleFunc("/adverts", h.Add).Methods("POST")
+	protected.Handle("/adverts", h.idempotency.Handler(http.HandlerFunc(h.Add))).Methods("POST")
 	protected.HandleFunc("/adverts/my", h.GetByUserId).Methods("GET")
 	protected.HandleFunc("/adverts/saved", h.GetSavedByUserId).Methods("GET")
 	protected.HandleFunc("/adverts/cart/{cartId}", h.GetByCartId).Methods("GET")
@@ -81,6 +81,9 @@ func (h *AdvertEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
 	protected.HandleFunc("/adverts/{advertId}", h.Delete).Methods("DELETE")
 	protected.HandleFunc("/adverts/{advertId}/status", h.UpdateStatus).Methods("PUT")
 	protected.HandleFunc("/adverts/{advertId}/image", h.UploadImage).Methods("PUT")
+	protected.HandleFunc("/adverts/{advertId}/renew", h.Renew).Methods("POST")
+	protected.HandleFunc("/adverts/{advertId}/bump", h.Bump).Methods("POST")
+	protected.HandleFunc("/adverts/{advertId}/publish", h.Publish).Methods("POST")
 	protected.HandleFunc("/adverts/saved/{advertId}", h.AddToSaved).Methods("POST")
 	protected.HandleFunc("/adverts/saved/{advertId}", h.RemoveFromSaved).Methods("DELETE")
 }
@@ -107,19 +110,19 @@ func (h *AdvertEndpoint) Get(writer http.ResponseWriter, r *http.Request) {
 
 	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
 	if err != nil || limit <= 0 {
-		h.sendError(writer, http.StatusBadRequest, ErrBadRequest, "invalid limit", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrBadRequest, "invalid limit", nil)
 		return
 	}
 
 	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
 	if err != nil || offset < 0 {
-		h.sendError(writer, http.StatusBadRequest, ErrBadRequest, "invalid offset", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrBadRequest, "invalid offset", nil)
 		return
 	}
 
-	adverts, err := h.advertUC.Get(limit, offset, userId)
+	adverts, err := h.advertUC.Get(r.Context(), limit, offset, userId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to get adverts", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to get adverts", nil)
 		return
 	}
 
@@ -147,20 +150,20 @@ func (h *AdvertEndpoint) GetBySellerId(writer http.ResponseWriter, r *http.Reque
 	logger.Info("get adverts by seller id request")
 	userId, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, err, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, err, "user not found", nil)
 		return
 	}
 
 	sellerIdStr := mux.Vars(r)["sellerId"]
 	sellerId, err := uuid.Parse(sellerIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid seller ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid seller ID", nil)
 		return
 	}
 
-	adverts, err := h.advertUC.GetBySellerId(userId, sellerId)
+	adverts, err := h.advertUC.GetBySellerId(r.Context(), userId, sellerId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to get adverts by seller ID", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to get adverts by seller ID", nil)
 		return
 	}
 
@@ -187,20 +190,20 @@ func (h *AdvertEndpoint) GetByCartId(writer http.ResponseWriter, r *http.Request
 	logger.Info("get adverts by cart id request")
 	userId, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, err, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, err, "user not found", nil)
 		return
 	}
 
 	cartIdStr := mux.Vars(r)["cartId"]
 	cartId, err := uuid.Parse(cartIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid cart ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid cart ID", nil)
 		return
 	}
 
-	adverts, err := h.advertUC.GetByCartId(cartId, userId)
+	adverts, err := h.advertUC.GetByCartId(r.Context(), cartId, userId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to get adverts by cart ID", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to get adverts by cart ID", nil)
 		return
 	}
 
@@ -227,13 +230,13 @@ func (h *AdvertEndpoint) GetSavedByUserId(writer http.ResponseWriter, r *http.Re
 	logger.Info("get adverts by user id request")
 	userId, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, err, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, err, "user not found", nil)
 		return
 	}
 
-	adverts, err := h.advertUC.GetSavedByUserId(userId)
+	adverts, err := h.advertUC.GetSavedByUserId(r.Context(), userId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to get adverts by user ID", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to get adverts by user ID", nil)
 		return
 	}
 
@@ -266,13 +269,13 @@ func (h *AdvertEndpoint) GetById(writer http.ResponseWriter, r *http.Request) {
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
-	advert, err := h.advertUC.GetById(advertId, userId)
+	advert, err := h.advertUC.GetById(r.Context(), advertId, userId)
 	if err != nil {
-		h.handleError(writer, err, "failed to get advert by ID")
+		h.handleError(writer, r, err, "failed to get advert by ID")
 		return
 	}
 	logger.Info("advert sent", zap.Any("advert", advert))
@@ -287,8 +290,11 @@ func (h *AdvertEndpoint) GetById(writer http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param advert body dto.AdvertRequest true "Advert data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Success 201 {object} dto.Advert "Advert created"
 // @Failure 400 {object} utils.ErrResponse "Invalid advert data"
+// @Failure 409 {object} utils.ErrResponse "Request with the same Idempotency-Key is in progress"
+// @Failure 422 {object} utils.ErrResponse "Idempotency-Key is used with a different request"
 // @Failure 500 {object} utils.ErrResponse "Failed to create advert"
 // @Router /api/v1/adverts [post]
 func (h *AdvertEndpoint) Add(writer http.ResponseWriter, r *http.Request) {
@@ -296,7 +302,7 @@ func (h *AdvertEndpoint) Add(writer http.ResponseWriter, r *http.Request) {
 	logger.Info("add advert request")
 	var advert dto.AdvertRequest
 	if err := json.NewDecoder(r.Body).Decode(&advert); err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidAdvertData, "invalid advert data", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidAdvertData, "invalid advert data", nil)
 		return
 	}
 
@@ -304,13 +310,13 @@ func (h *AdvertEndpoint) Add(writer http.ResponseWriter, r *http.Request) {
 
 	userID, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, err, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, err, "user not found", nil)
 		return
 	}
 
-	newAdvert, err := h.advertUC.Add(&advert, userID)
+	newAdvert, err := h.advertUC.Add(r.Context(), &advert, userID)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to add advert", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to add advert", nil)
 		return
 	}
 
@@ -330,6 +336,7 @@ func (h *AdvertEndpoint) Add(writer http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} utils.ErrResponse "Invalid advert data"
 // @Failure 404 {object} utils.ErrResponse "Advert not found"
 // @Failure 403 {object} utils.ErrResponse "Forbidden"
+// @Failure 409 {object} utils.ErrResponse "Advert is reserved, is a draft to publish or changed its status"
 // @Failure 500 {object} utils.ErrResponse "Failed to update advert"
 // @Router /api/v1/adverts/{advertId} [put]
 func (h *AdvertEndpoint) Update(writer http.ResponseWriter, r *http.Request) {
@@ -337,7 +344,7 @@ func (h *AdvertEndpoint) Update(writer http.ResponseWriter, r *http.Request) {
 	logger.Info("update advert request")
 	var advert dto.AdvertRequest
 	if err := json.NewDecoder(r.Body).Decode(&advert); err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidAdvertData, "invalid advert data", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidAdvertData, "invalid advert data", nil)
 		return
 	}
 
@@ -345,19 +352,19 @@ func (h *AdvertEndpoint) Update(writer http.ResponseWriter, r *http.Request) {
 
 	userID, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
-	if err := h.advertUC.Update(&advert, userID, advertId); err != nil {
-		h.handleError(writer, err, "failed to update advert")
+	if err := h.advertUC.Update(r.Context(), &advert, userID, advertId); err != nil {
+		h.handleError(writer, r, err, "failed to update advert")
 		return
 	}
 
@@ -382,18 +389,18 @@ func (h *AdvertEndpoint) Delete(writer http.ResponseWriter, r *http.Request) {
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
 	userID, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
-	if err := h.advertUC.DeleteById(advertId, userID); err != nil {
-		h.handleError(writer, err, "failed to delete advert")
+	if err := h.advertUC.DeleteById(r.Context(), advertId, userID); err != nil {
+		h.handleError(writer, r, err, "failed to delete advert")
 		return
 	}
 
@@ -419,24 +426,24 @@ func (h *AdvertEndpoint) UpdateStatus(writer http.ResponseWriter, r *http.Reques
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
 	status := r.FormValue("status")
 	if status != string(dto.AdvertStatusActive) && status != string(dto.AdvertStatusInactive) {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidAdvertStatus, "invalid advert status", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidAdvertStatus, "invalid advert status", nil)
 		return
 	}
 
 	userID, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
-	if err := h.advertUC.UpdateStatus(advertId, userID, dto.AdvertStatus(status)); err != nil {
-		h.handleError(writer, err, "failed to update advert status")
+	if err := h.advertUC.UpdateStatus(r.Context(), advertId, userID, dto.AdvertStatus(status)); err != nil {
+		h.handleError(writer, r, err, "failed to update advert status")
 		return
 	}
 
@@ -444,6 +451,125 @@ func (h *AdvertEndpoint) UpdateStatus(writer http.ResponseWriter, r *http.Reques
 	utils.SendJSONResponse(writer, http.StatusOK, "Advert status updated")
 }
 
+// Renew godoc
+// @Summary Renew an advert
+// @Description Extend the publication period of an advert and make it active again.
+// @Tags adverts
+// @Param advertId path string true "Advert ID"
+// @Success 200 {string} string "Advert renewed"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 403 {object} utils.ErrResponse "Forbidden"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 409 {object} utils.ErrResponse "Advert is reserved by a purchase or not published"
+// @Failure 500 {object} utils.ErrResponse "Failed to renew advert"
+// @Router /api/v1/adverts/{advertId}/renew [post]
+func (h *AdvertEndpoint) Renew(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("renew advert request")
+	advertId, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	if err := h.advertUC.Renew(r.Context(), advertId, userID); err != nil {
+		h.handleError(writer, r, err, "failed to renew advert")
+		return
+	}
+
+	logger.Info("advert renewed")
+	utils.SendJSONResponse(writer, http.StatusOK, "Advert renewed")
+}
+
+// Bump godoc
+// @Summary Bump an advert
+// @Description Lift an active advert to the top of the feed. Can be repeated only after a cooldown.
+// @Tags adverts
+// @Param advertId path string true "Advert ID"
+// @Success 200 {string} string "Advert bumped"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 403 {object} utils.ErrResponse "Forbidden"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 429 {object} utils.ErrResponse "Advert cannot be bumped yet"
+// @Failure 500 {object} utils.ErrResponse "Failed to bump advert"
+// @Router /api/v1/adverts/{advertId}/bump [post]
+func (h *AdvertEndpoint) Bump(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("bump advert request")
+	advertId, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	if err := h.advertUC.Bump(r.Context(), advertId, userID); err != nil {
+		h.handleError(writer, r, err, "failed to bump advert")
+		return
+	}
+
+	logger.Info("advert bumped")
+	utils.SendJSONResponse(writer, http.StatusOK, "Advert bumped")
+}
+
+// Publish godoc
+// @Summary Publish a draft advert
+// @Description Publish a draft immediately or schedule its publication when publish_at is set.
+// @Tags adverts
+// @Accept json
+// @Param advertId path string true "Advert ID"
+// @Param request body dto.PublishAdvertRequest false "Publication time"
+// @Success 200 {string} string "Advert published"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID or incomplete advert"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 403 {object} utils.ErrResponse "Forbidden"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 409 {object} utils.ErrResponse "Advert is already published"
+// @Failure 500 {object} utils.ErrResponse "Failed to publish advert"
+// @Router /api/v1/adverts/{advertId}/publish [post]
+func (h *AdvertEndpoint) Publish(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("publish advert request")
+	advertId, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+
+	var request dto.PublishAdvertRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidAdvertData, "invalid publish request", nil)
+		return
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	if err := h.advertUC.Publish(r.Context(), advertId, userID, request.PublishAt); err != nil {
+		h.handleError(writer, r, err, "failed to publish advert")
+		return
+	}
+
+	logger.Info("advert published", zap.String("advert_id", advertId.String()))
+	utils.SendJSONResponse(writer, http.StatusOK, "Advert published")
+}
+
 // GetByCategoryId godoc
 // @Summary Retrieve adverts by category ID
 // @Description Fetch a list of adverts associated with a specific category ID.
@@ -460,7 +586,7 @@ func (h *AdvertEndpoint) GetByCategoryId(writer http.ResponseWriter, r *http.Req
 	categoryIdStr := mux.Vars(r)["categoryId"]
 	categoryId, err := uuid.Parse(categoryIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid category ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid category ID", nil)
 		return
 	}
 
@@ -469,9 +595,9 @@ func (h *AdvertEndpoint) GetByCategoryId(writer http.ResponseWriter, r *http.Req
 		userId = uuid.Nil
 	}
 
-	adverts, err := h.advertUC.GetByCategoryId(categoryId, userId)
+	adverts, err := h.advertUC.GetByCategoryId(r.Context(), categoryId, userId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to get adverts by category ID", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "failed to get adverts by category ID", nil)
 		return
 	}
 
@@ -487,9 +613,13 @@ func (h *AdvertEndpoint) GetByCategoryId(writer http.ResponseWriter, r *http.Req
 // @Description Upload an image associated with an advert by its ID.
 // @Tags adverts
 // @Param advertId path string true "Advert ID"
-// @Param image formData file true "Image file to upload"
+// @Param image formData file false "Image file to upload"
+// @Param upload_id query string false "ID of a completed resumable upload to use instead of the image field"
 // @Success 200 {string} string "Image uploaded"
-// @Failure 400 {object} utils.ErrResponse "Invalid advert ID or file not attached"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID, file not attached or not an image"
+// @Failure 409 {object} utils.ErrResponse "Resumable upload is incomplete"
+// @Failure 413 {object} utils.ErrResponse "File size exceeds limit"
+// @Failure 422 {object} utils.ErrResponse "Image was rejected by moderation or is held for manual review"
 // @Failure 500 {object} utils.ErrResponse "Failed to upload image"
 // @Router /api/v1/adverts/{advertId}/image [put]
 func (h *AdvertEndpoint) UploadImage(writer http.ResponseWriter, r *http.Request) {
@@ -497,58 +627,33 @@ func (h *AdvertEndpoint) UploadImage(writer http.ResponseWriter, r *http.Request
 	logger.Info("upload image request")
 	userID, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
-		return
-	}
-
-	fileHeader, _, err := r.FormFile("image")
-	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrFileNotAttached, "file not attached or size too large", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
-	data, err := io.ReadAll(fileHeader)
+	imageId, err := receiveFile(h.staticGrpcClient, r, "image", userID, entity.UploadPurposeAdvert)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, ErrFailedToReadFile, "failed to read file", nil)
-		return
-	}
-
-	if err = fileHeader.Close(); err != nil {
-		h.sendError(writer, http.StatusInternalServerError, ErrFailedToCloseFile, "failed to close file", nil)
+		statusCode, respErr := uploadErrorStatus(err)
+		h.sendError(writer, r, statusCode, respErr, "failed to upload image", nil)
 		return
 	}
 
-	imageId, err := h.staticGrpcClient.UploadStatic(bytes.NewReader(data))
-	if err != nil {
-		if status, ok := status.FromError(err); ok {
-			switch status.Code() {
-			case codes.DeadlineExceeded:
-				h.sendError(writer, http.StatusGatewayTimeout, ErrTimeout, "upload image timeout deadline exceeded", nil)
-			case codes.ResourceExhausted:
-				h.sendError(writer, http.StatusRequestEntityTooLarge, ErrTooLargeFile, "file size exceeds limit", nil)
-			default:
-				h.sendError(writer, http.StatusInternalServerError, ErrFailedToUploadFile, "failed to upload image", nil)
-			}
-		} else {
-			h.sendError(writer, http.StatusInternalServerError, ErrFailedToUploadFile, "failed to upload image", nil)
-		}
-		return
-	}
-
-	if err := h.advertUC.UploadImage(advertId, imageId, userID); err != nil {
+	if err := h.advertUC.UploadImage(r.Context(), advertId, imageId, userID); err != nil {
 		if errors.Is(err, ErrAdvertNotFound) {
-			h.sendError(writer, http.StatusNotFound, err, "advert not found", nil)
+			h.sendError(writer, r, http.StatusNotFound, err, "advert not found", nil)
 		} else if errors.Is(err, ErrForbidden) {
-			h.sendError(writer, http.StatusForbidden, err, "forbidden", nil)
+			h.sendError(writer, r, http.StatusForbidden, err, "forbidden", nil)
+		} else if errors.Is(err, usecase.ErrAdvertImageOnModeration) {
+			h.sendError(writer, r, http.StatusUnprocessableEntity, usecase.ErrAdvertImageOnModeration, "image is held for moderation", nil)
 		} else {
-			h.sendError(writer, http.StatusInternalServerError, ErrFailedToUploadFile, "failed to upload image", nil)
+			h.sendError(writer, r, http.StatusInternalServerError, ErrFailedToUploadFile, "failed to upload image", nil)
 		}
 		return
 	}
@@ -572,19 +677,19 @@ func (h *AdvertEndpoint) AddToSaved(writer http.ResponseWriter, r *http.Request)
 	logger.Info("add advert to saved request")
 	userId, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
-	if err := h.advertUC.AddToSaved(advertId, userId); err != nil {
-		h.handleError(writer, err, "failed to add advert to saved")
+	if err := h.advertUC.AddToSaved(r.Context(), advertId, userId); err != nil {
+		h.handleError(writer, r, err, "failed to add advert to saved")
 		return
 	}
 
@@ -607,19 +712,19 @@ func (h *AdvertEndpoint) RemoveFromSaved(writer http.ResponseWriter, r *http.Req
 	logger.Info("remove advert from saved request")
 	userId, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
-	if err := h.advertUC.RemoveFromSaved(advertId, userId); err != nil {
-		h.handleError(writer, err, "failed to remove advert from saved")
+	if err := h.advertUC.RemoveFromSaved(r.Context(), advertId, userId); err != nil {
+		h.handleError(writer, r, err, "failed to remove advert from saved")
 		return
 	}
 
@@ -627,21 +732,35 @@ func (h *AdvertEndpoint) RemoveFromSaved(writer http.ResponseWriter, r *http.Req
 	utils.SendJSONResponse(writer, http.StatusOK, "Advert removed from saved")
 }
 
-func (h *AdvertEndpoint) sendError(w http.ResponseWriter, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
-	logger := middleware.GetLogger(context.Background())
+func (h *AdvertEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
 
 	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
 	utils.SendErrorResponse(w, statusCode, err.Error())
 }
 
-func (h *AdvertEndpoint) handleError(writer http.ResponseWriter, err error, context string) {
+func (h *AdvertEndpoint) handleError(writer http.ResponseWriter, r *http.Request, err error, context string) {
 	switch {
 	case errors.Is(err, ErrAdvertNotFound):
-		h.sendError(writer, http.StatusNotFound, err, context, nil)
+		h.sendError(writer, r, http.StatusNotFound, err, context, nil)
 	case errors.Is(err, ErrForbidden):
-		h.sendError(writer, http.StatusForbidden, err, context, nil)
+		h.sendError(writer, r, http.StatusForbidden, err, context, nil)
+	case errors.Is(err, usecase.ErrAdvertBumpCooldown):
+		h.sendError(writer, r, http.StatusTooManyRequests, usecase.ErrAdvertBumpCooldown, context, nil)
+	case errors.Is(err, usecase.ErrAdvertNotDraft):
+		h.sendError(writer, r, http.StatusConflict, usecase.ErrAdvertNotDraft, context, nil)
+	case errors.Is(err, usecase.ErrAdvertNotPublished):
+		h.sendError(writer, r, http.StatusConflict, usecase.ErrAdvertNotPublished, context, nil)
+	case errors.Is(err, usecase.ErrAdvertNotRenewable):
+		h.sendError(writer, r, http.StatusConflict, usecase.ErrAdvertNotRenewable, context, nil)
+	case errors.Is(err, usecase.ErrAdvertReserved):
+		h.sendError(writer, r, http.StatusConflict, usecase.ErrAdvertReserved, context, nil)
+	case errors.Is(err, usecase.ErrAdvertStatusChanged):
+		h.sendError(writer, r, http.StatusConflict, usecase.ErrAdvertStatusChanged, context, nil)
+	case errors.Is(err, entity.ErrAdvertIncomplete):
+		h.sendError(writer, r, http.StatusBadRequest, entity.ErrAdvertIncomplete, context, nil)
 	default:
-		h.sendError(writer, http.StatusInternalServerError, err, context, nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, context, nil)
 	}
 }
 
@@ -656,12 +775,17 @@ func (h *AdvertEndpoint) AddToViewed(writer http.ResponseWriter, r *http.Request
 	advertIdStr := mux.Vars(r)["advertId"]
 	advertId, err := uuid.Parse(advertIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
 		return
 	}
 
-	if err := h.advertUC.AddViewed(advertId, userId); err != nil {
-		h.handleError(writer, err, "failed to add advert to viewed")
+	visitorId := ""
+	if userId == uuid.Nil {
+		visitorId = utils.VisitorID(writer, r, h.sessionManager.SecureCookie)
+	}
+
+	if err := h.advertUC.AddViewed(r.Context(), advertId, userId, visitorId); err != nil {
+		h.handleError(writer, r, err, "failed to add advert to viewed")
 		return
 	}
 
@@ -682,13 +806,13 @@ func (h *AdvertEndpoint) GetByUserId(writer http.ResponseWriter, r *http.Request
 	logger.Info("get adverts by user id request")
 	userId, err := h.sessionManager.GetUserID(r)
 	if err != nil {
-		h.sendError(writer, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
-	adverts, err := h.advertUC.GetByUserId(userId)
+	adverts, err := h.advertUC.GetByUserId(r.Context(), userId)
 	if err != nil {
-		h.handleError(writer, err, "failed to get adverts by user ID")
+		h.handleError(writer, r, err, "failed to get adverts by user ID")
 		return
 	}
 
@@ -717,7 +841,7 @@ func (h *AdvertEndpoint) Search(writer http.ResponseWriter, r *http.Request) {
 	logger.Info("search adverts request")
 	query := r.URL.Query().Get("query")
 	if strings.TrimSpace(query) == "" {
-		h.sendError(writer, http.StatusBadRequest, errors.New("search query is empty"), "empty search query", nil)
+		h.sendError(writer, r, http.StatusBadRequest, errors.New("search query is empty"), "empty search query", nil)
 		return
 	}
 
@@ -738,9 +862,9 @@ func (h *AdvertEndpoint) Search(writer http.ResponseWriter, r *http.Request) {
 		userId = uuid.Nil
 	}
 
-	adverts, err := h.advertUC.Search(query, batchSize, limit, offset, userId)
+	adverts, err := h.advertUC.Search(r.Context(), query, batchSize, limit, offset, userId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "error during search execution", nil)
+		h.sendError(writer, r, http.StatusInternalServerError, err, "error during search execution", nil)
 		return
 	}
 
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	analyticsDateLayout   = "2006-01-02"
+	defaultAnalyticsRange = 30 * 24 * time.Hour
+)
+
+var ErrInvalidDateRange = errors.New("invalid date range, use from and to in YYYY-MM-DD format")
+
+type AnalyticsEndpoint struct {
+	analyticsUC    usecase.AnalyticsUseCase
+	sessionManager *utils.SessionManager
+}
+
+func NewAnalyticsEndpoint(analyticsUC usecase.AnalyticsUseCase, sessionManager *utils.SessionManager) *AnalyticsEndpoint {
+	return &AnalyticsEndpoint{
+		analyticsUC:    analyticsUC,
+		sessionManager: sessionManager,
+	}
+}
+
+func (h *AnalyticsEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.HandleFunc("/analytics/adverts/{advertId}", h.GetAdvertAnalytics).Methods("GET")
+	protected.HandleFunc("/analytics/my", h.GetSellerAnalytics).Methods("GET")
+}
+
+// parseDateRange читает период из параметров from и to, по умолчанию - последние 30 дней
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now()
+	from := to.Add(-defaultAnalyticsRange)
+
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse(analyticsDateLayout, value); err != nil {
+			return from, to, ErrInvalidDateRange
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse(analyticsDateLayout, value); err != nil {
+			return from, to, ErrInvalidDateRange
+		}
+	}
+	return from, to, nil
+}
+
+// GetAdvertAnalytics godoc
+// @Summary Get analytics of an advert
+// @Description Daily views, unique viewers, saves, cart additions and purchases of the seller's advert,
+// @Description conversion funnel and comparison against the category average.
+// @Tags analytics
+// @Produce json
+// @Param advertId path string true "Advert ID"
+// @Param from query string false "Start day (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End day (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} dto.AdvertAnalytics "Advert analytics"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID or date range"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 403 {object} utils.ErrResponse "Forbidden"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get analytics"
+// @Router /api/v1/analytics/adverts/{advertId} [get]
+func (h *AnalyticsEndpoint) GetAdvertAnalytics(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("get advert analytics request")
+
+	advertId, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidID, "invalid advert ID", nil)
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid date range", nil)
+		return
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	analytics, err := h.analyticsUC.GetAdvertAnalytics(r.Context(), advertId, userID, from, to)
+	if err != nil {
+		h.handleError(writer, r, err, "failed to get advert analytics")
+		return
+	}
+
+	utils.SendJSONResponse(writer, http.StatusOK, analytics)
+}
+
+// GetSellerAnalytics godoc
+// @Summary Get analytics of the seller's adverts
+// @Description Totals and conversion funnel for every advert of the current seller.
+// @Tags analytics
+// @Produce json
+// @Param from query string false "Start day (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End day (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} dto.SellerAnalytics "Seller analytics"
+// @Failure 400 {object} utils.ErrResponse "Invalid date range"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get analytics"
+// @Router /api/v1/analytics/my [get]
+func (h *AnalyticsEndpoint) GetSellerAnalytics(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("get seller analytics request")
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid date range", nil)
+		return
+	}
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	analytics, err := h.analyticsUC.GetSellerAnalytics(r.Context(), userID, from, to)
+	if err != nil {
+		h.handleError(writer, r, err, "failed to get seller analytics")
+		return
+	}
+
+	utils.SendJSONResponse(writer, http.StatusOK, analytics)
+}
+
+func (h *AnalyticsEndpoint) handleError(writer http.ResponseWriter, r *http.Request, err error, context string) {
+	switch {
+	case errors.Is(err, usecase.ErrAnalyticsInvalidRange):
+		h.sendError(writer, r, http.StatusBadRequest, usecase.ErrAnalyticsInvalidRange, context, nil)
+	case errors.Is(err, repository.ErrAdvertNotFound):
+		h.sendError(writer, r, http.StatusNotFound, ErrAdvertNotFound, context, nil)
+	case errors.Is(err, repository.ErrSellerNotFound):
+		h.sendError(writer, r, http.StatusNotFound, repository.ErrSellerNotFound, context, nil)
+	case errors.Is(err, usecase.ErrAnalyticsForbidden):
+		h.sendError(writer, r, http.StatusForbidden, usecase.ErrAnalyticsForbidden, context, nil)
+	default:
+		h.sendError(writer, r, http.StatusInternalServerError, err, context, nil)
+	}
+}
+
+func (h *AnalyticsEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
+
+	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
+	utils.SendErrorResponse(w, statusCode, err.Error())
+}
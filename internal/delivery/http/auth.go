@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
@@ -31,8 +30,8 @@ func (a *AuthEndpoint) Configure(router *mux.Router) {
 	protected.HandleFunc("/logout", a.Logout).Methods(http.MethodPost)
 }
 
-func (a *AuthEndpoint) handleError(w http.ResponseWriter, err error, method string, data map[string]string) {
-	logger := middleware.GetLogger(context.Background())
+func (a *AuthEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, method string, data map[string]string) {
+	logger := middleware.GetLogger(r.Context())
 	logger.Error(method+" error", zap.Error(err), zap.Any("data", data))
 	utils.SendErrorResponse(w, http.StatusInternalServerError, err.Error())
 }
@@ -53,17 +52,17 @@ func (a *AuthEndpoint) Logout(w http.ResponseWriter, r *http.Request) {
 	logger.Info("logout request")
 	userID, err := a.sessionManager.GetUserID(r)
 	if err != nil {
-		a.handleError(w, err, "Logout", nil)
+		a.handleError(w, r, err, "Logout", nil)
 		return
 	}
 	cookie, err := r.Cookie("session_id")
 	if err != nil {
-		a.handleError(w, err, "Logout", nil)
+		a.handleError(w, r, err, "Logout", nil)
 		return
 	}
-	err = a.sessionManager.DeleteSession(cookie.Value)
+	err = a.sessionManager.DeleteSession(r.Context(), cookie.Value)
 	if err != nil {
-		a.handleError(w, err, "Logout", map[string]string{"userID": userID.String()})
+		a.handleError(w, r, err, "Logout", map[string]string{"userID": userID.String()})
 		return
 	}
 
@@ -1,11 +1,9 @@
 package http
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
-	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
@@ -17,21 +15,31 @@ import (
 )
 
 type CartEndpoint struct {
-	cartClient *cart_purchase.CartPurchaseClient
+	cartClient     *cart_purchase.CartPurchaseClient
+	sessionManager *utils.SessionManager
+	idempotency    *middleware.IdempotencyMiddleware
+	policy         ownershipPolicy
 }
 
-func NewCartEndpoint(cartClient *cart_purchase.CartPurchaseClient) *CartEndpoint {
+func NewCartEndpoint(cartClient *cart_purchase.CartPurchaseClient, sessionManager *utils.SessionManager,
+	idempotency *middleware.IdempotencyMiddleware) *CartEndpoint {
 	return &CartEndpoint{
-		cartClient: cartClient,
+		cartClient:     cartClient,
+		sessionManager: sessionManager,
+		idempotency:    idempotency,
+		policy:         ownershipPolicy{sessionManager: sessionManager},
 	}
 }
 
-func (h *CartEndpoint) Configure(router *mux.Router) {
-	router.HandleFunc("/api/v1/cart/{cart_id}", h.GetByID).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/cart/user/{user_id}", h.GetByUserID).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/cart/add", h.AddToCart).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/cart/delete", h.DeleteFromCart).Methods(http.MethodDelete)
-	router.HandleFunc("/api/v1/cart/exists/{user_id}", h.CheckExists).Methods(http.MethodGet)
+func (h *CartEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.HandleFunc("/cart/{cart_id}", h.GetByID).Methods(http.MethodGet)
+	protected.HandleFunc("/cart/user/{user_id}", h.GetByUserID).Methods(http.MethodGet)
+	protected.Handle("/cart/add", h.idempotency.Handler(http.HandlerFunc(h.AddToCart))).Methods(http.MethodPost)
+	protected.HandleFunc("/cart/delete", h.DeleteFromCart).Methods(http.MethodDelete)
+	protected.HandleFunc("/cart/exists/{user_id}", h.CheckExists).Methods(http.MethodGet)
 }
 
 // GetByID Retrieves the cart by its ID
@@ -43,6 +51,9 @@ func (h *CartEndpoint) Configure(router *mux.Router) {
 // @Param cart_id path string true "Cart ID"
 // @Success 200 {object} dto.CartResponse "Successfully retrieved cart"
 // @Failure 400 {object} utils.ErrResponse "Invalid cart ID format"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Cart not found"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
 // @Router /api/v1/cart/{cart_id} [get]
 func (h *CartEndpoint) GetByID(w http.ResponseWriter, r *http.Request) {
@@ -62,9 +73,19 @@ func (h *CartEndpoint) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	userID, ctx, err := h.policy.actingUser(r)
+	if err != nil {
+		sendPolicyError(w, err)
+		return
+	}
 	cart, err := h.cartClient.GetCartByID(ctx, cartID)
+	if err == nil && cart.UserID != userID {
+		err = ErrForbidden
+	}
+	if sendPolicyError(w, err) {
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	}
 	if errors.Is(err, cart_purchase.ErrCartNotFound) {
 		logger.Error("cart not found", zap.Error(err))
 		utils.SendErrorResponse(w, http.StatusNotFound, "cart not found")
@@ -89,8 +110,11 @@ func (h *CartEndpoint) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Param user_id path string true "User ID"
 // @Success 200 {object} dto.CartResponse "Successfully retrieved cart"
 // @Failure 400 {object} utils.ErrResponse "Invalid user ID format"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Cart not found"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
-// @Router /cart/user/{user_id} [get]
+// @Router /api/v1/cart/user/{user_id} [get]
 func (h *CartEndpoint) GetByUserID(w http.ResponseWriter, r *http.Request) {
 	logger := middleware.GetLogger(r.Context())
 	logger.Info("get cart by user id request")
@@ -108,9 +132,16 @@ func (h *CartEndpoint) GetByUserID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	_, ctx, err := h.policy.authorizeUser(r, userID)
+	if sendPolicyError(w, err) {
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	}
 	cart, err := h.cartClient.GetCartByUserID(ctx, userID)
+	if sendPolicyError(w, err) {
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	}
 	if errors.Is(err, cart_purchase.ErrCartNotFound) {
 		logger.Error("cart not found", zap.Error(err))
 		utils.SendErrorResponse(w, http.StatusNotFound, "cart not found")
@@ -127,13 +158,20 @@ func (h *CartEndpoint) GetByUserID(w http.ResponseWriter, r *http.Request) {
 
 // AddToCart Adds an advert to the user's cart
 // @Summary Add advert to user's cart
-// @Description Adds a new advert to the cart associated with a user
+// @Description Adds a new advert to the cart of the session user. user_id in the body may be omitted
+// @Description and must match the session user otherwise. Only active adverts of other sellers can be added
 // @Tags Cart
 // @Accept json
 // @Produce json
 // @Param purchase body dto.AddAdvertToUserCartRequest true "Data to add advert to cart"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Success 200 {object} map[string]string "Successfully added advert"
 // @Failure 400 {object} utils.ErrResponse "Invalid request data"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart belongs to another user or advert is the user's own"
+// @Failure 404 {object} utils.ErrResponse "Cart or advert not found"
+// @Failure 409 {object} utils.ErrResponse "Request with the same Idempotency-Key is in progress or advert is not active"
+// @Failure 422 {object} utils.ErrResponse "Idempotency-Key is used with a different request"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
 // @Router /api/v1/cart/add [post]
 func (h *CartEndpoint) AddToCart(w http.ResponseWriter, r *http.Request) {
@@ -144,14 +182,34 @@ func (h *CartEndpoint) AddToCart(w http.ResponseWriter, r *http.Request) {
 		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err := h.cartClient.AddAdvertToCart(ctx, req.UserID, req.AdvertID)
+
+	userID, ctx, err := h.policy.actingUser(r)
+	if err == nil && req.UserID != uuid.Nil && req.UserID != userID {
+		err = ErrForbidden
+	}
+	if sendPolicyError(w, err) {
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	}
+	_, err = h.cartClient.AddAdvertToCart(ctx, userID, req.AdvertID)
 
 	switch {
+	case sendPolicyError(w, err):
+		return
 	case errors.Is(err, cart_purchase.ErrCartNotFound):
 		utils.SendErrorResponse(w, http.StatusNotFound, "cart not found")
 		return
+	case errors.Is(err, cart_purchase.ErrAdvertNotFound):
+		utils.SendErrorResponse(w, http.StatusNotFound, "advert not found")
+		return
+	case errors.Is(err, cart_purchase.ErrOwnAdvert):
+		logger.Warn("own advert is added to cart", zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusForbidden, cart_purchase.ErrOwnAdvert.Error())
+		return
+	case errors.Is(err, cart_purchase.ErrAdvertUnavailable):
+		logger.Warn("unavailable advert is added to cart", zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, cart_purchase.ErrAdvertUnavailable.Error())
+		return
 	case err != nil:
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "failed to add advert to user cart")
 		return
@@ -170,6 +228,8 @@ func (h *CartEndpoint) AddToCart(w http.ResponseWriter, r *http.Request) {
 // @Param purchase body dto.DeleteAdvertFromUserCartRequest true "Data to delete advert from cart"
 // @Success 200 {object} map[string]string "Successfully deleted advert from user cart"
 // @Failure 400 {object} utils.ErrResponse "Invalid request data"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart belongs to another user"
 // @Failure 404 {object} utils.ErrResponse "Cart or advert not found"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
 // @Router /api/v1/cart/delete [delete]
@@ -182,14 +242,21 @@ func (h *CartEndpoint) DeleteFromCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err := h.cartClient.DeleteAdvertFromCart(ctx, req.CartID, req.AdvertID)
+	_, ctx, err := h.policy.actingUser(r)
+	if sendPolicyError(w, err) {
+		return
+	}
+	_, err = h.cartClient.DeleteAdvertFromCart(ctx, req.CartID, req.AdvertID)
 	switch {
-	case errors.Is(err, cart_purchase.ErrCartNotFound):
+	case sendPolicyError(w, err):
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	case errors.Is(err, cart_purchase.ErrCartNotFound), errors.Is(err, cart_purchase.ErrCartOrAdvertNotFound):
 		utils.SendErrorResponse(w, http.StatusNotFound, "cart or advert not found")
+		return
 	case err != nil:
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "failed to delete advert from user cart")
+		return
 	}
 
 	logger.Info("advert deleted from user cart")
@@ -205,6 +272,8 @@ func (h *CartEndpoint) DeleteFromCart(w http.ResponseWriter, r *http.Request) {
 // @Param user_id path string true "User ID"
 // @Success 200 {object} map[string]bool "Cart existence check result"
 // @Failure 400 {object} utils.ErrResponse "Invalid user ID format"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart belongs to another user"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
 // @Router /api/v1/cart/exists/{user_id} [get]
 func (h *CartEndpoint) CheckExists(w http.ResponseWriter, r *http.Request) {
@@ -223,9 +292,16 @@ func (h *CartEndpoint) CheckExists(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	_, ctx, err := h.policy.authorizeUser(r, userID)
+	if sendPolicyError(w, err) {
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	}
 	exists, err := h.cartClient.CheckCartExists(ctx, userID)
+	if sendPolicyError(w, err) {
+		logger.Warn("cart access denied", zap.Error(err))
+		return
+	}
 	if err != nil {
 		logger.Error("failed to check cart existence", zap.Error(err))
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "failed to check cart existence")
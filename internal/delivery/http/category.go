@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
@@ -35,19 +34,19 @@ func (e *CategoryEndpoint) ConfigureRoutes(router *mux.Router) {
 // @Failure 500 {object} utils.ErrResponse
 // @Router /api/v1/categories [get]
 func (e *CategoryEndpoint) Get(w http.ResponseWriter, r *http.Request) {
-	logger := middleware.GetLogger(context.Background())
+	logger := middleware.GetLogger(r.Context())
 	logger.Info("get categories request")
-	categories, err := e.categoryUC.Get()
+	categories, err := e.categoryUC.Get(r.Context())
 	if err != nil {
-		e.sendError(w, http.StatusInternalServerError, err, "error getting categories", nil)
+		e.sendError(w, r, http.StatusInternalServerError, err, "error getting categories", nil)
 		return
 	}
 	logger.Info("get categories response", zap.Any("categories", categories))
 	utils.SendJSONResponse(w, http.StatusOK, categories)
 }
 
-func (e *CategoryEndpoint) sendError(w http.ResponseWriter, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
-	logger := middleware.GetLogger(context.Background())
+func (e *CategoryEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
 	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
 	utils.SendErrorResponse(w, statusCode, err.Error())
 }
@@ -21,7 +21,7 @@ func TestGetCategories_Success(t *testing.T) {
 
 	mockUseCase := mocks.NewMockCategoryUseCase(ctrl)
 
-	mockUseCase.EXPECT().Get().Return([]*entity.Category{{ID: uuid.New(), Title: "Category1"}}, nil)
+	mockUseCase.EXPECT().Get(gomock.Any()).Return([]*entity.Category{{ID: uuid.New(), Title: "Category1"}}, nil)
 
 	endpoints := NewCategoryEndpoint(mockUseCase)
 
@@ -45,7 +45,7 @@ func TestGetCategories_Error(t *testing.T) {
 
 	mockUseCase := mocks.NewMockCategoryUseCase(ctrl)
 
-	mockUseCase.EXPECT().Get().Return(nil, errors.New("some error"))
+	mockUseCase.EXPECT().Get(gomock.Any()).Return(nil, errors.New("some error"))
 
 	endpoints := NewCategoryEndpoint(mockUseCase)
 
@@ -0,0 +1,310 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type DealEndpoint struct {
+	dealUC         usecase.SafeDealUseCase
+	sessionManager *utils.SessionManager
+}
+
+func NewDealEndpoint(dealUC usecase.SafeDealUseCase, sessionManager *utils.SessionManager) *DealEndpoint {
+	return &DealEndpoint{
+		dealUC:         dealUC,
+		sessionManager: sessionManager,
+	}
+}
+
+func (h *DealEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.HandleFunc("/deals/disputes", h.GetDisputes).Methods("GET")
+	protected.HandleFunc("/deals/purchase/{purchase_id}", h.GetByPurchaseID).Methods("GET")
+	protected.HandleFunc("/deals/purchase/{purchase_id}", h.Create).Methods("POST")
+	protected.HandleFunc("/deals/{deal_id}/ship", h.Ship).Methods("POST")
+	protected.HandleFunc("/deals/{deal_id}/confirm", h.Confirm).Methods("POST")
+	protected.HandleFunc("/deals/{deal_id}/dispute", h.OpenDispute).Methods("POST")
+	protected.HandleFunc("/deals/{deal_id}/resolve", h.Resolve).Methods("POST")
+}
+
+// Create godoc
+// @Summary Pay for a purchase with a safe deal
+// @Description Turns a pending card purchase into a safe deal: the payment only holds the funds of the buyer, the seller ships,
+// @Description and the funds are captured after the buyer confirms receipt. Returns the deal with the payment to confirm.
+// @Description A repeated request returns the same deal.
+// @Tags deals
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Success 201 {object} dto.DealResponse "Safe deal"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Purchase belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Purchase not found"
+// @Failure 409 {object} utils.ErrResponse "Purchase is not paid by card, is not pending or has adverts of several sellers"
+// @Failure 500 {object} utils.ErrResponse "Failed to create safe deal"
+// @Router /api/v1/deals/purchase/{purchase_id} [post]
+func (h *DealEndpoint) Create(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r, "purchase_id")
+	if !ok {
+		return
+	}
+
+	deal, err := h.dealUC.Create(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to create safe deal")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusCreated, deal)
+}
+
+// GetByPurchaseID godoc
+// @Summary Get the safe deal of a purchase
+// @Description Returns the deal with its payment and the deadline of the current stage to the buyer, the seller or an administrator.
+// @Tags deals
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Success 200 {object} dto.DealResponse "Safe deal"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not a party to the deal"
+// @Failure 404 {object} utils.ErrResponse "Deal not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get safe deal"
+// @Router /api/v1/deals/purchase/{purchase_id} [get]
+func (h *DealEndpoint) GetByPurchaseID(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r, "purchase_id")
+	if !ok {
+		return
+	}
+
+	deal, err := h.dealUC.GetByPurchaseId(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get safe deal")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, deal)
+}
+
+// Ship godoc
+// @Summary Mark the goods as shipped
+// @Description The seller marks the goods as shipped; the buyer then has confirm_timeout to confirm receipt or open a dispute.
+// @Tags deals
+// @Produce json
+// @Param deal_id path string true "Deal ID"
+// @Success 200 {object} dto.DealResponse "Safe deal"
+// @Failure 400 {object} utils.ErrResponse "Invalid deal ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not the seller"
+// @Failure 404 {object} utils.ErrResponse "Deal not found"
+// @Failure 409 {object} utils.ErrResponse "Funds are not held or the goods are already shipped"
+// @Failure 500 {object} utils.ErrResponse "Failed to ship"
+// @Router /api/v1/deals/{deal_id}/ship [post]
+func (h *DealEndpoint) Ship(w http.ResponseWriter, r *http.Request) {
+	dealID, userID, ok := h.parseRequest(w, r, "deal_id")
+	if !ok {
+		return
+	}
+
+	deal, err := h.dealUC.Ship(r.Context(), dealID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to mark safe deal shipped")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, deal)
+}
+
+// Confirm godoc
+// @Summary Confirm receipt of the goods
+// @Description The buyer confirms receipt and the held funds are captured in favor of the seller.
+// @Tags deals
+// @Produce json
+// @Param deal_id path string true "Deal ID"
+// @Success 200 {object} dto.DealResponse "Safe deal"
+// @Failure 400 {object} utils.ErrResponse "Invalid deal ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not the buyer"
+// @Failure 404 {object} utils.ErrResponse "Deal not found"
+// @Failure 409 {object} utils.ErrResponse "Funds are not held or the deal is disputed"
+// @Failure 500 {object} utils.ErrResponse "Failed to confirm"
+// @Router /api/v1/deals/{deal_id}/confirm [post]
+func (h *DealEndpoint) Confirm(w http.ResponseWriter, r *http.Request) {
+	dealID, userID, ok := h.parseRequest(w, r, "deal_id")
+	if !ok {
+		return
+	}
+
+	deal, err := h.dealUC.Confirm(r.Context(), dealID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to confirm safe deal")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, deal)
+}
+
+// OpenDispute godoc
+// @Summary Open a dispute
+// @Description The buyer or the seller opens a dispute. The funds stay held and the deadlines stop until an administrator resolves it.
+// @Tags deals
+// @Accept json
+// @Produce json
+// @Param deal_id path string true "Deal ID"
+// @Param dispute body dto.DealDisputeRequest true "Reason of the dispute, up to 1000 characters"
+// @Success 200 {object} dto.DealResponse "Safe deal"
+// @Failure 400 {object} utils.ErrResponse "Invalid deal ID or reason"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not a party to the deal"
+// @Failure 404 {object} utils.ErrResponse "Deal not found"
+// @Failure 409 {object} utils.ErrResponse "Funds are not held or the dispute is already open"
+// @Failure 500 {object} utils.ErrResponse "Failed to open dispute"
+// @Router /api/v1/deals/{deal_id}/dispute [post]
+func (h *DealEndpoint) OpenDispute(w http.ResponseWriter, r *http.Request) {
+	dealID, userID, ok := h.parseRequest(w, r, "deal_id")
+	if !ok {
+		return
+	}
+
+	var req dto.DealDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+
+	deal, err := h.dealUC.OpenDispute(r.Context(), dealID, userID, req.Reason)
+	if err != nil {
+		h.handleError(w, r, err, "failed to open safe deal dispute")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, deal)
+}
+
+// GetDisputes godoc
+// @Summary List open disputes
+// @Description Returns open disputes, oldest first. Available to administrators only.
+// @Tags deals
+// @Produce json
+// @Param limit query int true "Limit"
+// @Param offset query int true "Offset"
+// @Success 200 {array} dto.DealResponse "Disputed deals"
+// @Failure 400 {object} utils.ErrResponse "Invalid limit or offset"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not an administrator"
+// @Failure 500 {object} utils.ErrResponse "Failed to get disputes"
+// @Router /api/v1/deals/disputes [get]
+func (h *DealEndpoint) GetDisputes(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid offset")
+		return
+	}
+
+	deals, err := h.dealUC.GetDisputes(r.Context(), userID, limit, offset)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get safe deal disputes")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, deals)
+}
+
+// Resolve godoc
+// @Summary Resolve a dispute
+// @Description An administrator closes the dispute: release captures the funds in favor of the seller, refund returns them to the buyer.
+// @Tags deals
+// @Accept json
+// @Produce json
+// @Param deal_id path string true "Deal ID"
+// @Param resolution body dto.DealResolveRequest true "release or refund"
+// @Success 200 {object} dto.DealResponse "Safe deal"
+// @Failure 400 {object} utils.ErrResponse "Invalid deal ID or resolution"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not an administrator"
+// @Failure 404 {object} utils.ErrResponse "Deal not found"
+// @Failure 409 {object} utils.ErrResponse "Deal is not disputed"
+// @Failure 500 {object} utils.ErrResponse "Failed to resolve dispute"
+// @Router /api/v1/deals/{deal_id}/resolve [post]
+func (h *DealEndpoint) Resolve(w http.ResponseWriter, r *http.Request) {
+	dealID, userID, ok := h.parseRequest(w, r, "deal_id")
+	if !ok {
+		return
+	}
+
+	var req dto.DealResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+
+	deal, err := h.dealUC.Resolve(r.Context(), dealID, userID, entity.DealResolution(req.Resolution))
+	if err != nil {
+		h.handleError(w, r, err, "failed to resolve safe deal dispute")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, deal)
+}
+
+func (h *DealEndpoint) parseRequest(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)[param])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, userID, true
+}
+
+func (h *DealEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	logger := middleware.GetLogger(r.Context())
+
+	switch {
+	case errors.Is(err, usecase.ErrDealBadRequest):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusBadRequest, usecase.ErrDealBadRequest.Error())
+	case errors.Is(err, usecase.ErrDealForbidden), errors.Is(err, usecase.ErrPaymentForbidden):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusForbidden, "Forbidden")
+	case errors.Is(err, repository.ErrDealNotFound), errors.Is(err, repository.ErrPurchaseNotFound):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusNotFound, "safe deal not found")
+	case errors.Is(err, usecase.ErrDealNotAllowed), errors.Is(err, usecase.ErrPaymentNotRequired):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrDealNotAllowed.Error())
+	case errors.Is(err, usecase.ErrDealMultipleSellers):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrDealMultipleSellers.Error())
+	case errors.Is(err, usecase.ErrDealInvalidState), errors.Is(err, usecase.ErrPaymentInvalidState),
+		errors.Is(err, repository.ErrGatewayPaymentState):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrDealInvalidState.Error())
+	default:
+		logger.Error(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// DeliveryEndpoint - способы доставки объявлений, стоимость доставки корзины и отправления покупок
+type DeliveryEndpoint struct {
+	deliveryUC     usecase.DeliveryUseCase
+	sessionManager *utils.SessionManager
+}
+
+func NewDeliveryEndpoint(deliveryUC usecase.DeliveryUseCase, sessionManager *utils.SessionManager) *DeliveryEndpoint {
+	return &DeliveryEndpoint{
+		deliveryUC:     deliveryUC,
+		sessionManager: sessionManager,
+	}
+}
+
+func (h *DeliveryEndpoint) ConfigureRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/adverts/{advertId}/delivery-options", h.GetOptions).Methods(http.MethodGet)
+}
+
+func (h *DeliveryEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.HandleFunc("/adverts/{advertId}/delivery-options", h.SetOptions).Methods(http.MethodPut)
+	protected.HandleFunc("/delivery/cart/{cart_id}/quotes", h.GetQuotes).Methods(http.MethodGet)
+	protected.HandleFunc("/shipments/purchase/{purchase_id}", h.GetShipments).Methods(http.MethodGet)
+	protected.HandleFunc("/shipments/purchase/{purchase_id}", h.CreateShipments).Methods(http.MethodPost)
+	protected.HandleFunc("/shipments/{shipment_id}/tracking", h.SetTracking).Methods(http.MethodPost)
+}
+
+// GetOptions godoc
+// @Summary Get advert delivery options
+// @Description Returns the delivery options the seller offers for the advert: courier, parcel_locker or post with price in rubles and delivery time in days.
+// @Tags delivery
+// @Produce json
+// @Param advertId path string true "Advert ID"
+// @Success 200 {array} dto.DeliveryOption "Delivery options"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get delivery options"
+// @Router /api/v1/adverts/{advertId}/delivery-options [get]
+func (h *DeliveryEndpoint) GetOptions(w http.ResponseWriter, r *http.Request) {
+	advertID, err := uuid.Parse(mux.Vars(r)["advertId"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return
+	}
+
+	options, err := h.deliveryUC.GetOptions(r.Context(), advertID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get delivery options")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, options)
+}
+
+// SetOptions godoc
+// @Summary Set advert delivery options
+// @Description Replaces the delivery options of the advert of the current seller. An empty list turns delivery off.
+// @Description Every type may be listed once, min_days must not exceed max_days.
+// @Tags delivery
+// @Accept json
+// @Produce json
+// @Param advertId path string true "Advert ID"
+// @Param options body dto.DeliveryOptionsRequest true "Delivery options"
+// @Success 200 {array} dto.DeliveryOption "Delivery options"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID or delivery options"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Advert belongs to another seller"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to set delivery options"
+// @Router /api/v1/adverts/{advertId}/delivery-options [put]
+func (h *DeliveryEndpoint) SetOptions(w http.ResponseWriter, r *http.Request) {
+	advertID, userID, ok := h.parseRequest(w, r, "advertId")
+	if !ok {
+		return
+	}
+
+	var req dto.DeliveryOptionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+	options := make([]*dto.DeliveryOption, 0, len(req.Options))
+	for i := range req.Options {
+		options = append(options, &req.Options[i])
+	}
+
+	result, err := h.deliveryUC.SetOptions(r.Context(), advertID, userID, options)
+	if err != nil {
+		h.handleError(w, r, err, "failed to set delivery options")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, result)
+}
+
+// GetQuotes godoc
+// @Summary Get delivery cost of a cart
+// @Description Returns the delivery cost for every type available for all adverts of the cart. Adverts of one seller
+// @Description are shipped in one parcel, so its cost and time are the largest among them; cost is in rubles.
+// @Tags delivery
+// @Produce json
+// @Param cart_id path string true "Cart ID"
+// @Success 200 {array} dto.DeliveryQuote "Delivery quotes"
+// @Failure 400 {object} utils.ErrResponse "Invalid cart ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Cart not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get delivery quotes"
+// @Router /api/v1/delivery/cart/{cart_id}/quotes [get]
+func (h *DeliveryEndpoint) GetQuotes(w http.ResponseWriter, r *http.Request) {
+	cartID, userID, ok := h.parseRequest(w, r, "cart_id")
+	if !ok {
+		return
+	}
+
+	quotes, err := h.deliveryUC.GetQuotes(r.Context(), cartID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get delivery quotes")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, quotes)
+}
+
+// CreateShipments godoc
+// @Summary Create shipments of a purchase
+// @Description Creates a shipment per seller of a purchase with delivery_method = delivery. Is called when the shipments
+// @Description were not created at checkout; create them before the payment, so that the delivery cost is paid with it.
+// @Description A repeated request returns the same shipments.
+// @Tags delivery
+// @Accept json
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Param shipments body dto.ShipmentsRequest true "Delivery type"
+// @Success 201 {array} dto.ShipmentResponse "Shipments"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID, delivery type or the purchase is picked up"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Purchase belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Purchase not found"
+// @Failure 409 {object} utils.ErrResponse "Delivery type is not available for every advert"
+// @Failure 500 {object} utils.ErrResponse "Failed to create shipments"
+// @Router /api/v1/shipments/purchase/{purchase_id} [post]
+func (h *DeliveryEndpoint) CreateShipments(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r, "purchase_id")
+	if !ok {
+		return
+	}
+
+	var req dto.ShipmentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+
+	shipments, err := h.deliveryUC.CreateShipments(r.Context(), purchaseID, userID, entity.DeliveryOptionType(req.Type))
+	if err != nil {
+		h.handleError(w, r, err, "failed to create shipments")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusCreated, shipments)
+}
+
+// GetShipments godoc
+// @Summary Get shipments of a purchase
+// @Description Returns shipments with their carrier history: all of them to the buyer, their own ones to a seller.
+// @Tags delivery
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Success 200 {array} dto.ShipmentResponse "Shipments"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "User is not a party to the purchase"
+// @Failure 404 {object} utils.ErrResponse "Purchase not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get shipments"
+// @Router /api/v1/shipments/purchase/{purchase_id} [get]
+func (h *DeliveryEndpoint) GetShipments(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r, "purchase_id")
+	if !ok {
+		return
+	}
+
+	shipments, err := h.deliveryUC.GetShipments(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get shipments")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, shipments)
+}
+
+// SetTracking godoc
+// @Summary Attach a tracking number to a shipment
+// @Description The seller attaches the carrier tracking number; the shipment becomes in_transit and its status
+// @Description is then updated from the carrier.
+// @Tags delivery
+// @Accept json
+// @Produce json
+// @Param shipment_id path string true "Shipment ID"
+// @Param tracking body dto.TrackingRequest true "Tracking number"
+// @Success 200 {object} dto.ShipmentResponse "Shipment"
+// @Failure 400 {object} utils.ErrResponse "Invalid shipment ID or tracking number"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Shipment belongs to another seller"
+// @Failure 404 {object} utils.ErrResponse "Shipment not found"
+// @Failure 409 {object} utils.ErrResponse "Tracking number is already attached"
+// @Failure 500 {object} utils.ErrResponse "Failed to attach tracking number"
+// @Router /api/v1/shipments/{shipment_id}/tracking [post]
+func (h *DeliveryEndpoint) SetTracking(w http.ResponseWriter, r *http.Request) {
+	shipmentID, userID, ok := h.parseRequest(w, r, "shipment_id")
+	if !ok {
+		return
+	}
+
+	var req dto.TrackingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
+		return
+	}
+
+	shipment, err := h.deliveryUC.SetTracking(r.Context(), shipmentID, userID, req.TrackingNumber)
+	if err != nil {
+		h.handleError(w, r, err, "failed to set shipment tracking number")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, shipment)
+}
+
+func (h *DeliveryEndpoint) parseRequest(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)[param])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, userID, true
+}
+
+func (h *DeliveryEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	logger := middleware.GetLogger(r.Context())
+
+	switch {
+	case errors.Is(err, usecase.ErrDeliveryBadRequest):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusBadRequest, usecase.ErrDeliveryBadRequest.Error())
+	case errors.Is(err, usecase.ErrDeliveryForbidden):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusForbidden, "Forbidden")
+	case errors.Is(err, usecase.ErrDeliveryAdvertNotFound):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusNotFound, usecase.ErrDeliveryAdvertNotFound.Error())
+	case errors.Is(err, repository.ErrShipmentNotFound), errors.Is(err, repository.ErrPurchaseNotFound),
+		errors.Is(err, repository.ErrCartNotFound):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusNotFound, "not found")
+	case errors.Is(err, usecase.ErrDeliveryOptionUnavailable):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrDeliveryOptionUnavailable.Error())
+	case errors.Is(err, usecase.ErrShipmentInvalidState):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrShipmentInvalidState.Error())
+	default:
+		logger.Error(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// GuestCartEndpoint - корзина посетителя без входа. Корзину определяет подписанная cookie,
+// при входе или регистрации она переносится в корзину пользователя через MergeInto
+type GuestCartEndpoint struct {
+	guestCartUC    usecase.GuestCart
+	cartClient     *cart_purchase.CartPurchaseClient
+	cookies        *utils.GuestCartCookie
+	sessionManager *utils.SessionManager
+}
+
+func NewGuestCartEndpoint(guestCartUC usecase.GuestCart, cartClient *cart_purchase.CartPurchaseClient,
+	cookies *utils.GuestCartCookie, sessionManager *utils.SessionManager) *GuestCartEndpoint {
+	return &GuestCartEndpoint{
+		guestCartUC:    guestCartUC,
+		cartClient:     cartClient,
+		cookies:        cookies,
+		sessionManager: sessionManager,
+	}
+}
+
+func (h *GuestCartEndpoint) ConfigureRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/guest/cart", h.GetCart).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/guest/cart/add", h.AddToCart).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/guest/cart/{advert_id}", h.DeleteFromCart).Methods(http.MethodDelete)
+}
+
+// GetCart Retrieves the guest cart
+// @Summary Retrieve guest cart
+// @Description Retrieves adverts of the cart of a visitor who has not logged in. Deleted adverts are skipped
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} dto.GuestCart "Guest cart, empty if the visitor has no cart"
+// @Failure 500 {object} utils.ErrResponse "Internal server error"
+// @Router /api/v1/guest/cart [get]
+func (h *GuestCartEndpoint) GetCart(w http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	guestID, ok := h.cookies.GuestID(r)
+	if !ok {
+		utils.SendJSONResponse(w, http.StatusOK, dto.GuestCart{Adverts: []dto.PreviewAdvertCard{}})
+		return
+	}
+
+	cart, err := h.guestCartUC.GetCart(r.Context(), guestID)
+	if err != nil {
+		logger.Error("failed to get guest cart", zap.String("guest_id", guestID.String()), zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "failed to get guest cart")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, cart)
+}
+
+// AddToCart Adds an advert to the guest cart
+// @Summary Add advert to guest cart
+// @Description Adds an active advert to the cart of a visitor who has not logged in and issues the signed
+// @Description guest_cart cookie. The cart is merged into the user's cart on login or signup
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param advert body dto.AddAdvertToGuestCartRequest true "Advert to add"
+// @Success 200 {object} map[string]string "Successfully added advert"
+// @Failure 400 {object} utils.ErrResponse "Invalid request data"
+// @Failure 404 {object} utils.ErrResponse "Advert not found"
+// @Failure 409 {object} utils.ErrResponse "Visitor is logged in, advert is not active or guest cart is full"
+// @Failure 500 {object} utils.ErrResponse "Internal server error"
+// @Router /api/v1/guest/cart/add [post]
+func (h *GuestCartEndpoint) AddToCart(w http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	var req dto.AddAdvertToGuestCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AdvertID == uuid.Nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if _, err := h.sessionManager.GetUserID(r); err == nil {
+		utils.SendErrorResponse(w, http.StatusConflict, "logged in users add adverts to their own cart")
+		return
+	}
+
+	guestID := h.cookies.Issue(w, r)
+	err := h.guestCartUC.AddAdvert(r.Context(), guestID, req.AdvertID)
+	switch {
+	case errors.Is(err, repository.ErrAdvertNotFound):
+		utils.SendErrorResponse(w, http.StatusNotFound, "advert not found")
+		return
+	case errors.Is(err, usecase.ErrCartAdvertUnavailable):
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrCartAdvertUnavailable.Error())
+		return
+	case errors.Is(err, repository.ErrGuestCartFull):
+		utils.SendErrorResponse(w, http.StatusConflict, repository.ErrGuestCartFull.Error())
+		return
+	case err != nil:
+		logger.Error("failed to add advert to guest cart", zap.String("guest_id", guestID.String()), zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "failed to add advert to guest cart")
+		return
+	}
+
+	utils.SendJSONResponse(w, http.StatusOK, map[string]string{"message": "advert added to guest cart"})
+}
+
+// DeleteFromCart Deletes an advert from the guest cart
+// @Summary Delete advert from guest cart
+// @Description Deletes an advert from the cart of a visitor who has not logged in
+// @Tags Cart
+// @Produce json
+// @Param advert_id path string true "Advert ID"
+// @Success 200 {object} map[string]string "Successfully deleted advert"
+// @Failure 400 {object} utils.ErrResponse "Invalid advert ID"
+// @Failure 404 {object} utils.ErrResponse "Advert is not in the guest cart"
+// @Failure 500 {object} utils.ErrResponse "Internal server error"
+// @Router /api/v1/guest/cart/{advert_id} [delete]
+func (h *GuestCartEndpoint) DeleteFromCart(w http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	advertID, err := uuid.Parse(mux.Vars(r)["advert_id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid advert_id")
+		return
+	}
+	guestID, ok := h.cookies.GuestID(r)
+	if !ok {
+		utils.SendErrorResponse(w, http.StatusNotFound, repository.ErrGuestCartAdvertNotFound.Error())
+		return
+	}
+
+	err = h.guestCartUC.DeleteAdvert(r.Context(), guestID, advertID)
+	switch {
+	case errors.Is(err, repository.ErrGuestCartAdvertNotFound):
+		utils.SendErrorResponse(w, http.StatusNotFound, repository.ErrGuestCartAdvertNotFound.Error())
+		return
+	case err != nil:
+		logger.Error("failed to delete advert from guest cart", zap.String("guest_id", guestID.String()), zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "failed to delete advert from guest cart")
+		return
+	}
+
+	utils.SendJSONResponse(w, http.StatusOK, map[string]string{"message": "advert deleted from guest cart"})
+}
+
+// MergeInto переносит гостевую корзину в корзину пользователя после входа или регистрации.
+// Объявления, которые уже лежат в корзине пользователя или которые нельзя купить, пропускаются.
+// Ошибки только логируются: вход не срывается из-за корзины, а она переносится при следующем входе
+func (h *GuestCartEndpoint) MergeInto(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	guestID, ok := h.cookies.GuestID(r)
+	if !ok {
+		return
+	}
+	logger := middleware.GetLogger(r.Context()).With(zap.String("guest_id", guestID.String()),
+		zap.String("user_id", userID.String()))
+
+	advertIDs, err := h.guestCartUC.GetAdvertIDs(r.Context(), guestID)
+	if err != nil {
+		logger.Error("failed to get guest cart", zap.Error(err))
+		return
+	}
+	if len(advertIDs) > 0 {
+		result, err := h.cartClient.MergeCart(cart_purchase.WithCaller(r.Context(), userID), userID, advertIDs)
+		if err != nil {
+			logger.Error("failed to merge guest cart", zap.Error(err))
+			return
+		}
+		logger.Info("guest cart merged", zap.Int("added", len(result.Added)),
+			zap.Int("duplicates", len(result.Duplicates)), zap.Any("unavailable", result.Unavailable))
+	}
+
+	if err := h.guestCartUC.Clear(r.Context(), guestID); err != nil {
+		logger.Error("failed to clear guest cart", zap.Error(err))
+	}
+	h.cookies.Clear(w)
+}
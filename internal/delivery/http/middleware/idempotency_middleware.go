@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+)
+
+// IdempotencyMiddleware повторяет сохраненный ответ на небезопасный запрос, если клиент
+// прислал его снова с тем же заголовком Idempotency-Key, например после таймаута. Ключи
+// принадлежат пользователю, которого возвращает userID, обычно SessionManager.GetUserID,
+// поэтому middleware ставится после проверки сессии. Запросы без заголовка обрабатываются
+// как обычно
+type IdempotencyMiddleware struct {
+	repo   repository.IdempotencyRepository
+	userID func(r *http.Request) (uuid.UUID, error)
+}
+
+func NewIdempotencyMiddleware(repo repository.IdempotencyRepository,
+	userID func(r *http.Request) (uuid.UUID, error)) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		repo:   repo,
+		userID: userID,
+	}
+}
+
+// Handler обрабатывает первый запрос с ключом и сохраняет ответ. Повтор, пока первый запрос
+// еще обрабатывается, получает 409, повтор с другим телом или на другой адрес - 422.
+// Ответы 5xx не сохраняются, чтобы запрос можно было повторить
+func (m *IdempotencyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		logger := GetLogger(r.Context()).With(zap.String("idempotency_key", key))
+
+		if len(key) > maxIdempotencyKeyLength {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+		userID, err := m.userID(r)
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentRequestBytes+1))
+		if err != nil {
+			utils.SendErrorResponse(w, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+		if len(body) > maxIdempotentRequestBytes {
+			utils.SendErrorResponse(w, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(r, body)
+		stored, reserved, err := m.repo.Reserve(r.Context(), userID, key, fingerprint)
+		if err != nil {
+			logger.Error("failed to reserve idempotency key", zap.Error(err))
+			utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !reserved {
+			replay(w, stored, fingerprint, logger)
+			return
+		}
+
+		// ответ сохраняется и после отмены запроса, например по таймауту, - именно тогда
+		// клиент и повторяет запрос
+		storeCtx := context.WithoutCancel(r.Context())
+		rec := &idempotencyRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		completed := false
+		defer func() {
+			// запрос завершился паникой или ошибкой сервера - ключ освобождается для повтора
+			if completed {
+				return
+			}
+			if err := m.repo.Release(storeCtx, userID, key); err != nil {
+				logger.Error("failed to release idempotency key", zap.Error(err))
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+
+		if rec.statusCode >= http.StatusInternalServerError {
+			return
+		}
+		completed = true
+		err = m.repo.Complete(storeCtx, userID, key, entity.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			StatusCode:  rec.statusCode,
+			ContentType: rec.Header().Get("Content-Type"),
+			Body:        rec.body.Bytes(),
+		})
+		if err != nil {
+			logger.Error("failed to save idempotent response", zap.Error(err))
+		}
+	})
+}
+
+func replay(w http.ResponseWriter, stored *entity.IdempotencyRecord, fingerprint string, logger *zap.Logger) {
+	switch {
+	case stored.Fingerprint != fingerprint:
+		logger.Warn("idempotency key reused with a different request")
+		utils.SendErrorResponse(w, http.StatusUnprocessableEntity,
+			"Idempotency-Key is already used with a different request")
+	case !stored.Completed:
+		logger.Info("request with the same idempotency key is in progress")
+		w.Header().Set("Retry-After", "1")
+		utils.SendErrorResponse(w, http.StatusConflict,
+			"request with this Idempotency-Key is still in progress")
+	default:
+		logger.Info("replaying idempotent response")
+		if stored.ContentType != "" {
+			w.Header().Set("Content-Type", stored.ContentType)
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(stored.StatusCode)
+		if _, err := w.Write(stored.Body); err != nil {
+			logger.Error("failed to write replayed response", zap.Error(err))
+		}
+	}
+}
+
+// requestFingerprint отличает повтор запроса от другого запроса с тем же ключом
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyRecorder передает ответ клиенту и запоминает его для повторов
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.statusCode = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *idempotencyRecorder) Write(p []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(p)
+	return rec.ResponseWriter.Write(p)
+}
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIdempotencyKey = "purchase-1"
+
+func setupIdempotencyMiddleware(t *testing.T) (*IdempotencyMiddleware, *mocks.MockIdempotencyRepository, uuid.UUID) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockIdempotencyRepository(ctrl)
+	userID := uuid.New()
+	m := NewIdempotencyMiddleware(repo, func(*http.Request) (uuid.UUID, error) {
+		return userID, nil
+	})
+	return m, repo, userID
+}
+
+func idempotentRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/purchase", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, testIdempotencyKey)
+	return req
+}
+
+// createdHandler отвечает 201 и считает вызовы
+func createdHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+	})
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	t.Run("WithoutKey", func(t *testing.T) {
+		m, _, _ := setupIdempotencyMiddleware(t)
+		calls := 0
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/purchase", nil))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("FirstRequestStored", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+		calls := 0
+
+		var fingerprint string
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).
+			DoAndReturn(func(_, _, _ interface{}, fp string) (*entity.IdempotencyRecord, bool, error) {
+				fingerprint = fp
+				return nil, true, nil
+			})
+		repo.EXPECT().Complete(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).
+			DoAndReturn(func(_, _, _ interface{}, record entity.IdempotencyRecord) error {
+				assert.Equal(t, fingerprint, record.Fingerprint)
+				assert.Equal(t, http.StatusCreated, record.StatusCode)
+				assert.Equal(t, "application/json", record.ContentType)
+				assert.JSONEq(t, `{"echo":{"cart_id":1}}`, string(record.Body))
+				return nil
+			})
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, idempotentRequest(`{"cart_id":1}`))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"echo":{"cart_id":1}}`, rec.Body.String())
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Replayed", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+		calls := 0
+		req := idempotentRequest(`{"cart_id":1}`)
+
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).
+			DoAndReturn(func(_, _, _ interface{}, fp string) (*entity.IdempotencyRecord, bool, error) {
+				return &entity.IdempotencyRecord{
+					Fingerprint: fp,
+					Completed:   true,
+					StatusCode:  http.StatusCreated,
+					ContentType: "application/json",
+					Body:        []byte(`{"id":"stored"}`),
+				}, false, nil
+			})
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"id":"stored"}`, rec.Body.String())
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+		calls := 0
+
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).
+			DoAndReturn(func(_, _, _ interface{}, fp string) (*entity.IdempotencyRecord, bool, error) {
+				return &entity.IdempotencyRecord{Fingerprint: fp}, false, nil
+			})
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, idempotentRequest(`{"cart_id":1}`))
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("DifferentPayload", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+		calls := 0
+
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).
+			Return(&entity.IdempotencyRecord{Fingerprint: "other", Completed: true, StatusCode: http.StatusCreated}, false, nil)
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, idempotentRequest(`{"cart_id":2}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("ServerErrorReleased", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).Return(nil, true, nil)
+		repo.EXPECT().Release(gomock.Any(), userID, testIdempotencyKey).Return(nil)
+
+		failing := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		rec := httptest.NewRecorder()
+		m.Handler(failing).ServeHTTP(rec, idempotentRequest(`{"cart_id":1}`))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("PanicReleased", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).Return(nil, true, nil)
+		repo.EXPECT().Release(gomock.Any(), userID, testIdempotencyKey).Return(nil)
+
+		panicking := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("handler failed")
+		})
+		require.Panics(t, func() {
+			m.Handler(panicking).ServeHTTP(httptest.NewRecorder(), idempotentRequest(`{"cart_id":1}`))
+		})
+	})
+
+	t.Run("ReserveFailed", func(t *testing.T) {
+		m, repo, userID := setupIdempotencyMiddleware(t)
+		calls := 0
+
+		repo.EXPECT().Reserve(gomock.Any(), userID, testIdempotencyKey, gomock.Any()).
+			Return(nil, false, errors.New("redis is down"))
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, idempotentRequest(`{"cart_id":1}`))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("KeyTooLong", func(t *testing.T) {
+		m, _, _ := setupIdempotencyMiddleware(t)
+		calls := 0
+		req := idempotentRequest(`{}`)
+		req.Header.Set(IdempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLength+1))
+
+		rec := httptest.NewRecorder()
+		m.Handler(createdHandler(&calls)).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	first := requestFingerprint(httptest.NewRequest(http.MethodPost, "/api/v1/cart/add", nil), []byte(`{"a":1}`))
+
+	assert.Equal(t, first,
+		requestFingerprint(httptest.NewRequest(http.MethodPost, "/api/v1/cart/add", nil), []byte(`{"a":1}`)))
+	assert.NotEqual(t, first,
+		requestFingerprint(httptest.NewRequest(http.MethodPost, "/api/v1/adverts", nil), []byte(`{"a":1}`)))
+	assert.NotEqual(t, first,
+		requestFingerprint(httptest.NewRequest(http.MethodPost, "/api/v1/cart/add", nil), []byte(`{"a":2}`)))
+}
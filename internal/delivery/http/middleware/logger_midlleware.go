@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -26,6 +27,10 @@ func GetLogger(ctx context.Context) *zap.Logger {
 	return zap.L()
 }
 
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, requestID)
+}
+
 func GetRequestID(ctx context.Context) string {
 	if reqID, ok := ctx.Value(RequestIDKey).(string); ok {
 		return reqID
@@ -42,16 +47,16 @@ func RequestIDMiddleware(next http.Handler) http.Handler {
 
 		w.Header().Set("X-Request-ID", requestID)
 
-		logger := zap.L().With(
+		logger := zap.L().With(append([]zap.Field{
 			zap.String("request_id", requestID),
 			zap.String("method", r.Method),
 			zap.String("path", r.URL.Path),
-		)
+		}, tracing.LogFields(r.Context())...)...)
 
 		logger.Info("Начало обработки запроса")
 
 		ctx := WithLogger(r.Context(), logger)
-		ctx = context.WithValue(ctx, RequestIDKey, requestID)
+		ctx = WithRequestID(ctx, requestID)
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// TracingMiddleware открывает серверный спан на каждый запрос или продолжает трассу из
+// заголовка traceparent. Спаны называются по шаблону маршрута, чтобы запросы к одному
+// обработчику группировались вместе; запросы метрик не трассируются
+func TracingMiddleware(service string) mux.MiddlewareFunc {
+	return otelhttp.NewMiddleware(service,
+		otelhttp.WithSpanNameFormatter(routeSpanName),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/api/v1/metrics"
+		}),
+	)
+}
+
+func routeSpanName(_ string, r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if path, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + path
+		}
+	}
+	return r.Method
+}
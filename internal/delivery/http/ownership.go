@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/google/uuid"
+)
+
+// ownershipPolicy - доступ к корзинам и покупкам. Пользователь, от имени которого выполняется
+// запрос, берется только из сессии; идентификаторы пользователей в пути и теле запроса
+// должны с ним совпадать. Сервис корзин получает пользователя сессии в метаданных gRPC
+// и повторяет проверки у себя
+type ownershipPolicy struct {
+	sessionManager *utils.SessionManager
+}
+
+// actingUser возвращает пользователя сессии и контекст для вызова сервиса корзин от его имени
+func (p ownershipPolicy) actingUser(r *http.Request) (uuid.UUID, context.Context, error) {
+	userID, err := p.sessionManager.GetUserID(r)
+	if err != nil {
+		return uuid.Nil, nil, ErrUnauthorized
+	}
+	return userID, cart_purchase.WithCaller(r.Context(), userID), nil
+}
+
+// authorizeUser работает как actingUser, но отклоняет запросы к данным другого пользователя owner
+func (p ownershipPolicy) authorizeUser(r *http.Request, owner uuid.UUID) (uuid.UUID, context.Context, error) {
+	userID, ctx, err := p.actingUser(r)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if owner != userID {
+		return uuid.Nil, nil, ErrForbidden
+	}
+	return userID, ctx, nil
+}
+
+// sendPolicyError отвечает 401 или 403, если запрос отклонен политикой доступа здесь
+// или в сервисе корзин, и возвращает false для остальных ошибок
+func sendPolicyError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, cart_purchase.ErrCallerUnauthenticated):
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+	case errors.Is(err, ErrForbidden), errors.Is(err, cart_purchase.ErrForbidden):
+		utils.SendErrorResponse(w, http.StatusForbidden, "Forbidden")
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,248 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const (
+	PaymentSignatureHeader = "X-Payment-Signature"
+	maxWebhookBytes        = 64 << 10
+)
+
+var ErrInvalidPaymentAction = errors.New("invalid payment action, use confirm or decline")
+
+// PaymentSimulator имитирует действия покупателя на странице оплаты. Его реализует только
+// локальный платежный шлюз, с настоящим провайдером маршрут имитации не регистрируется
+type PaymentSimulator interface {
+	Confirm(externalID string) ([]byte, string, error)
+	Decline(externalID string) ([]byte, string, error)
+}
+
+type PaymentEndpoint struct {
+	paymentUC      usecase.PaymentUseCase
+	sessionManager *utils.SessionManager
+	simulator      PaymentSimulator
+}
+
+func NewPaymentEndpoint(paymentUC usecase.PaymentUseCase, sessionManager *utils.SessionManager,
+	simulator PaymentSimulator) *PaymentEndpoint {
+	return &PaymentEndpoint{
+		paymentUC:      paymentUC,
+		sessionManager: sessionManager,
+		simulator:      simulator,
+	}
+}
+
+// ConfigureRoutes регистрирует прием уведомлений провайдера: они приходят без сессии
+// и CSRF-токена и проверяются по подписи
+func (h *PaymentEndpoint) ConfigureRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/payments/webhook", h.Webhook).Methods("POST")
+}
+
+func (h *PaymentEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.HandleFunc("/payments/purchase/{purchase_id}", h.GetByPurchaseID).Methods("GET")
+	protected.HandleFunc("/payments/purchase/{purchase_id}", h.Create).Methods("POST")
+	if h.simulator != nil {
+		protected.HandleFunc("/payments/purchase/{purchase_id}/simulate/{action}", h.Simulate).Methods("POST")
+	}
+}
+
+// Create godoc
+// @Summary Create a payment for a purchase
+// @Description Creates a card payment for the purchase of the current user and returns the URL where the buyer confirms it.
+// @Description If the purchase already has a payment, it is returned instead of a new one, so the request is safe to retry.
+// @Tags payments
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Success 201 {object} dto.PaymentResponse "Payment"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Purchase belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Purchase not found"
+// @Failure 409 {object} utils.ErrResponse "Purchase is not paid by card, is not pending or waits for its safe deal"
+// @Failure 500 {object} utils.ErrResponse "Failed to create payment"
+// @Router /api/v1/payments/purchase/{purchase_id} [post]
+func (h *PaymentEndpoint) Create(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	payment, err := h.paymentUC.Create(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to create payment")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusCreated, payment)
+}
+
+// GetByPurchaseID godoc
+// @Summary Get the payment of a purchase
+// @Description Returns the latest payment of the purchase of the current user.
+// @Tags payments
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Success 200 {object} dto.PaymentResponse "Payment"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Purchase belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Payment not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get payment"
+// @Router /api/v1/payments/purchase/{purchase_id} [get]
+func (h *PaymentEndpoint) GetByPurchaseID(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	payment, err := h.paymentUC.GetByPurchaseId(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get payment")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, payment)
+}
+
+// Webhook godoc
+// @Summary Receive a payment notification
+// @Description Accepts a notification of the payment provider signed with HMAC-SHA256 of the body in the X-Payment-Signature header
+// @Description and moves the payment and the purchase to the new status. Repeated notifications are accepted and ignored.
+// @Tags payments
+// @Accept json
+// @Param X-Payment-Signature header string true "Hex HMAC-SHA256 of the body"
+// @Success 200 "Notification processed"
+// @Failure 400 {object} utils.ErrResponse "Invalid notification"
+// @Failure 401 {object} utils.ErrResponse "Invalid signature"
+// @Failure 404 {object} utils.ErrResponse "Payment not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to process notification"
+// @Router /api/v1/payments/webhook [post]
+func (h *PaymentEndpoint) Webhook(w http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBytes))
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "failed to read notification")
+		return
+	}
+
+	err = h.paymentUC.HandleWebhook(r.Context(), payload, r.Header.Get(PaymentSignatureHeader))
+	if err != nil {
+		h.handleError(w, r, err, "failed to process payment notification")
+		return
+	}
+	logger.Info("payment notification processed")
+	w.WriteHeader(http.StatusOK)
+}
+
+// Simulate godoc
+// @Summary Simulate the buyer on the payment page
+// @Description Available only with the local payment gateway. confirm pays the purchase, decline cancels the payment;
+// @Description the gateway sends the same notification as a real provider would.
+// @Tags payments
+// @Produce json
+// @Param purchase_id path string true "Purchase ID"
+// @Param action path string true "confirm or decline"
+// @Success 200 {object} dto.PaymentResponse "Payment after the notification"
+// @Failure 400 {object} utils.ErrResponse "Invalid purchase ID or action"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Purchase belongs to another user"
+// @Failure 404 {object} utils.ErrResponse "Payment not found"
+// @Failure 409 {object} utils.ErrResponse "Payment is already processed"
+// @Router /api/v1/payments/purchase/{purchase_id}/simulate/{action} [post]
+func (h *PaymentEndpoint) Simulate(w http.ResponseWriter, r *http.Request) {
+	purchaseID, userID, ok := h.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var simulate func(externalID string) ([]byte, string, error)
+	switch mux.Vars(r)["action"] {
+	case "confirm":
+		simulate = h.simulator.Confirm
+	case "decline":
+		simulate = h.simulator.Decline
+	default:
+		utils.SendErrorResponse(w, http.StatusBadRequest, ErrInvalidPaymentAction.Error())
+		return
+	}
+
+	payment, err := h.paymentUC.GetByPurchaseId(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get payment")
+		return
+	}
+	payload, signature, err := simulate(payment.ExternalID)
+	if err == nil {
+		err = h.paymentUC.HandleWebhook(r.Context(), payload, signature)
+	}
+	if err != nil {
+		h.handleError(w, r, err, "failed to simulate payment")
+		return
+	}
+
+	payment, err = h.paymentUC.GetByPurchaseId(r.Context(), purchaseID, userID)
+	if err != nil {
+		h.handleError(w, r, err, "failed to get payment")
+		return
+	}
+	utils.SendJSONResponse(w, http.StatusOK, payment)
+}
+
+func (h *PaymentEndpoint) parseRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	purchaseID, err := uuid.Parse(mux.Vars(r)["purchase_id"])
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, ErrInvalidID.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return purchaseID, userID, true
+}
+
+func (h *PaymentEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	logger := middleware.GetLogger(r.Context())
+
+	switch {
+	case errors.Is(err, repository.ErrGatewayInvalidSignature):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusUnauthorized, "invalid signature")
+	case errors.Is(err, repository.ErrGatewayInvalidWebhook):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid notification")
+	case errors.Is(err, usecase.ErrPaymentForbidden):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusForbidden, "Forbidden")
+	case errors.Is(err, repository.ErrPaymentNotFound), errors.Is(err, repository.ErrPurchaseNotFound),
+		errors.Is(err, repository.ErrGatewayPaymentNotFound):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusNotFound, "payment not found")
+	case errors.Is(err, usecase.ErrPaymentNotRequired):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrPaymentNotRequired.Error())
+	case errors.Is(err, usecase.ErrPaymentDealRequired):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrPaymentDealRequired.Error())
+	case errors.Is(err, usecase.ErrPaymentInvalidState), errors.Is(err, repository.ErrGatewayPaymentState):
+		logger.Warn(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrPaymentInvalidState.Error())
+	default:
+		logger.Error(message, zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -1,15 +1,16 @@
 package http
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/cart_purchase"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -17,26 +18,67 @@ import (
 
 type PurchaseEndpoint struct {
 	purchaseClient *cart_purchase.CartPurchaseClient
+	paymentUC      usecase.PaymentUseCase
+	dealUC         usecase.SafeDealUseCase
+	deliveryUC     usecase.DeliveryUseCase
+	addressUC      usecase.AddressUseCase
+	sessionManager *utils.SessionManager
+	idempotency    *middleware.IdempotencyMiddleware
+	policy         ownershipPolicy
 }
 
-func NewPurchaseEndpoint(purchaseClient *cart_purchase.CartPurchaseClient) *PurchaseEndpoint {
-	return &PurchaseEndpoint{purchaseClient: purchaseClient}
+func NewPurchaseEndpoint(purchaseClient *cart_purchase.CartPurchaseClient, paymentUC usecase.PaymentUseCase,
+	dealUC usecase.SafeDealUseCase, deliveryUC usecase.DeliveryUseCase, addressUC usecase.AddressUseCase,
+	sessionManager *utils.SessionManager, idempotency *middleware.IdempotencyMiddleware) *PurchaseEndpoint {
+	return &PurchaseEndpoint{
+		purchaseClient: purchaseClient,
+		paymentUC:      paymentUC,
+		dealUC:         dealUC,
+		deliveryUC:     deliveryUC,
+		addressUC:      addressUC,
+		sessionManager: sessionManager,
+		idempotency:    idempotency,
+		policy:         ownershipPolicy{sessionManager: sessionManager},
+	}
 }
 
-func (h *PurchaseEndpoint) ConfigureRoutes(router *mux.Router) {
-	router.HandleFunc("/api/v1/purchase/{user_id}", h.Add).Methods("POST")
-	router.HandleFunc("/api/v1/purchase/{user_id}", h.GetByUserID).Methods("GET")
+func (h *PurchaseEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.Use(middleware.NewAuthMiddleware(h.sessionManager).SessionMiddleware)
+
+	protected.Handle("/purchase/{user_id}", h.idempotency.Handler(http.HandlerFunc(h.Add))).Methods("POST")
+	protected.HandleFunc("/purchase/{user_id}", h.GetByUserID).Methods("GET")
 }
 
 // Add processes the addition of a purchase
 // @Summary Adds a purchase
 // @Description Accepts purchase data, validates it, and adds it to the system. Returns a response with purchase data or an error.
+// @Description user_id in the path and in the body must match the session user, the cart must belong to them.
+// @Description A request repeated with the same Idempotency-Key gets the stored response instead of a second purchase.
+// @Description A card purchase is returned with a payment whose confirmation_url the buyer opens to pay. If the payment
+// @Description could not be created, the purchase is returned without it and the payment is created by POST /api/v1/payments/purchase/{purchase_id}.
+// @Description With safe_deal the card purchase is returned with a safe deal instead: the payment only holds the funds until the buyer
+// @Description confirms receipt. If the deal could not be created, it is created by POST /api/v1/deals/purchase/{purchase_id}.
+// @Description With delivery_option the purchase is returned with a shipment per seller and shipping_cost in rubles, which is
+// @Description added to the payment. If the shipments could not be created, the purchase is returned without them and without
+// @Description the payment: create the shipments by POST /api/v1/shipments/purchase/{purchase_id} first, then the payment.
+// @Description The address is required: either address_id of a saved address or address_details with a structured address.
+// @Description It replaces the legacy free text address and is returned in address_details.
+// @Description Every advert of the cart is checked again: if some became reserved, inactive or are the buyer's own,
+// @Description nothing is purchased and 409 lists them in adverts.
 // @Tags Purchases
 // @Accept json
 // @Produce json
+// @Param user_id path string true "User ID"
 // @Param purchase body dto.PurchaseRequest true "Purchase request"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Success 201 {object} dto.PurchaseResponse "Successful purchase"
-// @Failure 400 {object} utils.ErrResponse "Invalid request parameters"
+// @Failure 400 {object} utils.ErrResponse "Invalid request parameters, address is missing"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Cart or purchases belong to another user"
+// @Failure 404 {object} utils.ErrResponse "Saved address not found"
+// @Failure 409 {object} dto.UnavailableAdvertsResponse "Request with the same Idempotency-Key is in progress, delivery option or adverts are not available, cart is empty"
+// @Failure 422 {object} utils.ErrResponse "Idempotency-Key is used with a different request"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
 // @Router /api/v1/purchase/{user_id} [post]
 func (h *PurchaseEndpoint) Add(w http.ResponseWriter, r *http.Request) {
@@ -47,9 +89,9 @@ func (h *PurchaseEndpoint) Add(w http.ResponseWriter, r *http.Request) {
 	var purchase dto.PurchaseRequest
 
 	userIDStr := mux.Vars(r)["user_id"]
-	_, err := uuid.Parse(userIDStr)
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		h.handleError(w, err, "invalid user ID")
+		h.handleError(w, r, err, "invalid user ID")
 		return
 	}
 
@@ -58,17 +100,112 @@ func (h *PurchaseEndpoint) Add(w http.ResponseWriter, r *http.Request) {
 		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid request parameters")
 		return
 	}
+	if purchase.UserID == uuid.Nil {
+		purchase.UserID = userID
+	}
+	if purchase.SafeDeal && purchase.PaymentMethod != dto.PaymentMethodCard {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "safe deal is available only for card payment")
+		return
+	}
+	deliveryOption := entity.DeliveryOptionType(purchase.DeliveryOption)
+	if purchase.DeliveryOption != "" && (purchase.DeliveryMethod != dto.DeliveryMethodDelivery || !deliveryOption.Valid()) {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid delivery option")
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Hour)
-	defer cancel()
+	_, ctx, err := h.policy.authorizeUser(r, userID)
+	if err == nil && purchase.UserID != userID {
+		err = ErrForbidden
+	}
+	if sendPolicyError(w, err) {
+		logger.Warn("purchase access denied", zap.Error(err))
+		return
+	}
+
+	address, err := h.addressUC.ResolveCheckout(ctx, userID, purchase.AddressID, purchase.AddressDetails)
+	if err != nil {
+		if sendAddressError(w, err) {
+			logger.Warn("invalid purchase address", zap.Error(err))
+			return
+		}
+		h.handleError(w, r, err, "failed to resolve purchase address")
+		return
+	}
+	if address == nil {
+		utils.SendErrorResponse(w, http.StatusBadRequest, "address_id or address_details is required")
+		return
+	}
+	purchase.Address = entity.AddressFields(address.AddressDetails).Text()
+	purchase.PurchaseAddress = address
+
+	// способ доставки проверяется до оформления, чтобы не оставить покупку без доставки
+	if purchase.DeliveryOption != "" {
+		if _, err := h.deliveryUC.Quote(ctx, purchase.CartID, userID, deliveryOption); err != nil {
+			h.handleDeliveryError(w, r, err)
+			return
+		}
+	}
 
 	purchaseResponse, err := h.purchaseClient.AddPurchase(ctx, purchase)
+	if sendPolicyError(w, err) {
+		logger.Warn("purchase access denied", zap.Error(err))
+		return
+	}
+	var unavailable *usecase.UnavailableAdvertsError
+	if errors.As(err, &unavailable) {
+		logger.Warn("cart has unavailable adverts", zap.Error(err))
+		utils.SendJSONResponse(w, http.StatusConflict, dto.UnavailableAdvertsResponse{
+			Code:    http.StatusConflict,
+			Status:  usecase.ErrCartAdvertUnavailable.Error(),
+			Adverts: unavailable.Adverts,
+		})
+		return
+	}
+	if errors.Is(err, usecase.ErrPurchaseEmptyCart) {
+		logger.Warn("cart is empty", zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrPurchaseEmptyCart.Error())
+		return
+	}
 	if err != nil {
 		logger.Error("failed to add purchase", zap.Error(err))
 		utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
 		return
 	}
 
+	purchaseResponse.AddressDetails = address
+
+	// покупка уже оформлена, поэтому сбой платежного провайдера не превращается в ошибку:
+	// клиент создаст платеж повторно отдельным запросом
+	shipmentsCreated := true
+	if purchase.DeliveryOption != "" {
+		shipments, err := h.deliveryUC.CreateShipments(ctx, purchaseResponse.ID, userID, deliveryOption)
+		if err != nil {
+			logger.Error("failed to create shipments", zap.Error(err), zap.String("purchase_id", purchaseResponse.ID.String()))
+			shipmentsCreated = false
+		}
+		for _, shipment := range shipments {
+			purchaseResponse.ShippingCost += shipment.Cost
+		}
+		purchaseResponse.Shipments = shipments
+	}
+
+	switch {
+	case !shipmentsCreated:
+		// без отправлений платеж не включил бы доставку, поэтому он тоже создается повторно
+	case purchase.SafeDeal:
+		deal, err := h.dealUC.Create(ctx, purchaseResponse.ID, userID)
+		if err != nil {
+			logger.Error("failed to create safe deal", zap.Error(err), zap.String("purchase_id", purchaseResponse.ID.String()))
+		}
+		purchaseResponse.Deal = deal
+	case purchase.PaymentMethod == dto.PaymentMethodCard:
+		payment, err := h.paymentUC.Create(ctx, purchaseResponse.ID, userID)
+		if err != nil {
+			logger.Error("failed to create payment", zap.Error(err), zap.String("purchase_id", purchaseResponse.ID.String()))
+		}
+		purchaseResponse.Payment = payment
+	}
+
 	logger.Info("purchase added", zap.Any("purchase", purchaseResponse))
 	utils.SendJSONResponse(w, http.StatusCreated, purchaseResponse)
 }
@@ -79,9 +216,11 @@ func (h *PurchaseEndpoint) Add(w http.ResponseWriter, r *http.Request) {
 // @Tags Purchases
 // @Accept json
 // @Produce json
-// @Param user_id path int true "User ID"
+// @Param user_id path string true "User ID"
 // @Success 200 {array} dto.PurchaseResponse "Successful purchase"
 // @Failure 400 {object} utils.ErrResponse "Invalid user ID"
+// @Failure 401 {object} utils.ErrResponse "Unauthorized"
+// @Failure 403 {object} utils.ErrResponse "Purchases belong to another user"
 // @Failure 500 {object} utils.ErrResponse "Internal server error"
 // @Router /api/v1/purchase/{user_id} [get]
 func (h *PurchaseEndpoint) GetByUserID(w http.ResponseWriter, r *http.Request) {
@@ -92,25 +231,63 @@ func (h *PurchaseEndpoint) GetByUserID(w http.ResponseWriter, r *http.Request) {
 	userIDStr := mux.Vars(r)["user_id"]
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		h.handleError(w, err, "invalid user ID")
+		h.handleError(w, r, err, "invalid user ID")
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	_, ctx, err := h.policy.authorizeUser(r, userID)
+	if sendPolicyError(w, err) {
+		logger.Warn("purchase access denied", zap.Error(err))
+		return
+	}
 
 	purchases, err := h.purchaseClient.GetPurchasesByUserID(ctx, userID)
+	if sendPolicyError(w, err) {
+		logger.Warn("purchase access denied", zap.Error(err))
+		return
+	}
 	if err != nil {
-		h.handleError(w, err, "failed to get purchases")
+		h.handleError(w, r, err, "failed to get purchases")
 		return
 	}
 
+	purchaseIDs := make([]uuid.UUID, 0, len(purchases))
+	for _, purchase := range purchases {
+		purchaseIDs = append(purchaseIDs, purchase.ID)
+	}
+	// без снимков адресов покупки все равно отдаются: в них есть адрес в виде текста
+	addresses, err := h.addressUC.GetPurchaseAddresses(ctx, purchaseIDs)
+	if err != nil {
+		logger.Error("failed to get purchase addresses", zap.Error(err))
+	}
+	for _, purchase := range purchases {
+		purchase.AddressDetails = addresses[purchase.ID]
+	}
+
 	logger.Info("purchases found", zap.Any("purchases", purchases))
 	utils.SendJSONResponse(w, http.StatusOK, purchases)
 }
 
-func (h *PurchaseEndpoint) handleError(w http.ResponseWriter, err error, message string) {
-	logger := middleware.GetLogger(context.Background())
+func (h *PurchaseEndpoint) handleDeliveryError(w http.ResponseWriter, r *http.Request, err error) {
+	logger := middleware.GetLogger(r.Context())
+
+	switch {
+	case errors.Is(err, usecase.ErrDeliveryBadRequest):
+		logger.Warn("invalid delivery option", zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusBadRequest, "invalid delivery option")
+	case errors.Is(err, usecase.ErrDeliveryForbidden):
+		logger.Warn("purchase access denied", zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusForbidden, "Forbidden")
+	case errors.Is(err, usecase.ErrDeliveryOptionUnavailable):
+		logger.Warn("delivery option is not available", zap.Error(err))
+		utils.SendErrorResponse(w, http.StatusConflict, usecase.ErrDeliveryOptionUnavailable.Error())
+	default:
+		h.handleError(w, r, err, "failed to quote delivery")
+	}
+}
+
+func (h *PurchaseEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	logger := middleware.GetLogger(r.Context())
 
 	logger.Error(message, zap.Error(err))
 	utils.SendErrorResponse(w, http.StatusInternalServerError, "internal server error")
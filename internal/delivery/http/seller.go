@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"errors"
 	"net/http"
 
@@ -55,16 +54,16 @@ func (s *SellerEndpoint) GetByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	sellerID, err := uuid.Parse(vars["seller_id"])
 	if err != nil {
-		s.handleError(w, err, "error parsing seller_id")
+		s.handleError(w, r, err, "error parsing seller_id")
 		return
 	}
 
-	seller, err := s.sellerRepo.GetById(sellerID)
+	seller, err := s.sellerRepo.GetById(r.Context(), sellerID)
 	switch {
 	case errors.Is(err, repository.ErrSellerNotFound):
-		s.handleError(w, err, "error getting seller by id")
+		s.handleError(w, r, err, "error getting seller by id")
 	case err != nil:
-		s.handleError(w, err, "error getting seller by id")
+		s.handleError(w, r, err, "error getting seller by id")
 	}
 
 	logger.Info("seller found", zap.String("seller_id", sellerID.String()))
@@ -91,35 +90,35 @@ func (s *SellerEndpoint) GetByUserID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := uuid.Parse(vars["user_id"])
 	if err != nil {
-		s.handleError(w, err, "error parsing user_id")
+		s.handleError(w, r, err, "error parsing user_id")
 		return
 	}
 
-	seller, err := s.sellerRepo.GetByUserId(userID)
+	seller, err := s.sellerRepo.GetByUserId(r.Context(), userID)
 	switch {
 	case errors.Is(err, repository.ErrSellerNotFound):
-		s.handleError(w, err, "error getting seller by user_id")
+		s.handleError(w, r, err, "error getting seller by user_id")
 	case err != nil:
-		s.handleError(w, err, "error getting seller by user_id")
+		s.handleError(w, r, err, "error getting seller by user_id")
 	}
 
 	logger.Info("seller found", zap.String("user_id", userID.String()))
 	utils.SendJSONResponse(w, http.StatusOK, seller)
 }
 
-func (s *SellerEndpoint) handleError(w http.ResponseWriter, err error, context string) {
+func (s *SellerEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, context string) {
 	switch {
 	case errors.Is(err, repository.ErrSellerNotFound):
-		s.sendError(w, http.StatusNotFound, ErrSellerNotFound, context, nil)
+		s.sendError(w, r, http.StatusNotFound, ErrSellerNotFound, context, nil)
 	case errors.Is(err, repository.ErrSellerAlreadyExists):
-		s.sendError(w, http.StatusBadRequest, ErrSellerAlreadyExists, context, nil)
+		s.sendError(w, r, http.StatusBadRequest, ErrSellerAlreadyExists, context, nil)
 	case err != nil:
-		s.sendError(w, http.StatusInternalServerError, err, context, nil)
+		s.sendError(w, r, http.StatusInternalServerError, err, context, nil)
 	}
 }
 
-func (s *SellerEndpoint) sendError(w http.ResponseWriter, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
-	logger := middleware.GetLogger(context.Background())
+func (s *SellerEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
 
 	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
 	utils.SendErrorResponse(w, statusCode, err.Error())
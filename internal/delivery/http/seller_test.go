@@ -42,7 +42,7 @@ func TestSellerEndpoints_GetSellerByID(t *testing.T) {
 
 		mockSellerRepo.
 			EXPECT().
-			GetById(sellerID).
+			GetById(gomock.Any(), sellerID).
 			Return(&seller, nil)
 
 		req := httptest.NewRequest("GET", "/api/v1/seller/"+sellerID.String(), nil)
@@ -91,7 +91,7 @@ func TestSellerEndpoints_GetSellerByID(t *testing.T) {
 
 		mockSellerRepo.
 			EXPECT().
-			GetById(sellerID).
+			GetById(gomock.Any(), sellerID).
 			Return(nil, repository.ErrSellerNotFound)
 
 		req := httptest.NewRequest("GET", "/api/v1/seller/"+sellerID.String(), nil)
@@ -117,7 +117,7 @@ func TestSellerEndpoints_GetSellerByID(t *testing.T) {
 
 		mockSellerRepo.
 			EXPECT().
-			GetById(sellerID).
+			GetById(gomock.Any(), sellerID).
 			Return(nil, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/api/v1/seller/"+sellerID.String(), nil)
@@ -153,7 +153,7 @@ func TestSellerEndpoints_GetSellerByUserID(t *testing.T) {
 
 		mockSellerRepo.
 			EXPECT().
-			GetByUserId(userID).
+			GetByUserId(gomock.Any(), userID).
 			Return(&seller, nil)
 
 		req := httptest.NewRequest("GET", "/api/v1/seller/user/"+userID.String(), nil)
@@ -202,7 +202,7 @@ func TestSellerEndpoints_GetSellerByUserID(t *testing.T) {
 
 		mockSellerRepo.
 			EXPECT().
-			GetByUserId(userID).
+			GetByUserId(gomock.Any(), userID).
 			Return(nil, repository.ErrSellerNotFound)
 
 		req := httptest.NewRequest("GET", "/api/v1/seller/user/"+userID.String(), nil)
@@ -228,7 +228,7 @@ func TestSellerEndpoints_GetSellerByUserID(t *testing.T) {
 
 		mockSellerRepo.
 			EXPECT().
-			GetByUserId(userID).
+			GetByUserId(gomock.Any(), userID).
 			Return(nil, errors.New("database error"))
 
 		req := httptest.NewRequest("GET", "/api/v1/seller/user/"+userID.String(), nil)
@@ -1,14 +1,17 @@
 package http
 
 import (
-	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -18,8 +21,12 @@ var (
 	ErrInvalidStaticID    = errors.New("invalid static file ID")
 	ErrStaticFileNotFound = errors.New("static file not found")
 	ErrFailedToGetStatic  = errors.New("failed to get static file")
+	ErrInvalidImageSize   = errors.New("invalid image size, w and h must be non-negative integers")
 )
 
+// Файлы и их варианты неизменяемы: новое содержимое получает новый id
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
 type StaticEndpoint struct {
 	staticGrpcClient static.StaticGrpcClient
 }
@@ -31,8 +38,9 @@ func NewStaticEndpoint(staticGrpcClient static.StaticGrpcClient) *StaticEndpoint
 }
 
 func (h *StaticEndpoint) ConfigureRoutes(router *mux.Router) {
+	router.HandleFunc("/api/v1/files/{fileId}", h.GetImage).Methods("GET").MatcherFunc(isImageVariantRequest)
 	router.HandleFunc("/api/v1/files/{fileId}", h.GetById).Methods("GET")
-	router.HandleFunc("/api/v1/files/stream/{fileId}", h.GetFileStream).Methods("GET")
+	router.HandleFunc("/api/v1/files/stream/{fileId}", h.GetFileStream).Methods("GET", "HEAD")
 }
 
 // GetStaticById godoc
@@ -54,16 +62,16 @@ func (h *StaticEndpoint) GetById(writer http.ResponseWriter, r *http.Request) {
 	staticIdStr := mux.Vars(r)["fileId"]
 	staticId, err := uuid.Parse(staticIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, err, "invalid static ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid static ID", nil)
 		return
 	}
 
-	staticURL, err := h.staticGrpcClient.GetStatic(staticId)
+	staticURL, err := h.staticGrpcClient.GetStatic(r.Context(), staticId)
 	if err != nil {
 		if errors.Is(err, ErrStaticFileNotFound) {
-			h.sendError(writer, http.StatusNotFound, err, "static file not found", nil)
+			h.sendError(writer, r, http.StatusNotFound, err, "static file not found", nil)
 		} else {
-			h.sendError(writer, http.StatusInternalServerError, err, "failed to get static file", nil)
+			h.sendError(writer, r, http.StatusInternalServerError, err, "failed to get static file", nil)
 		}
 		return
 	}
@@ -75,13 +83,19 @@ func (h *StaticEndpoint) GetById(writer http.ResponseWriter, r *http.Request) {
 
 // GetFileStream godoc
 // @Summary Get static file stream by ID
-// @Description Get a static file as a byte stream by its ID
+// @Description Get a static file as a byte stream by its ID. Supports conditional requests
+// @Description (If-None-Match, If-Modified-Since) and single byte ranges (Range, If-Range).
 // @Tags static
 // @Produce octet-stream
 // @Param fileId path string true "File ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Param If-None-Match header string false "ETag of the cached copy"
 // @Success 200 {binary} []byte "Static file content"
+// @Success 206 {binary} []byte "Requested range of the static file"
+// @Success 304 "Not modified"
 // @Failure 400 {object} utils.ErrResponse "Invalid file ID"
 // @Failure 404 {object} utils.ErrResponse "Static file not found"
+// @Failure 416 {object} utils.ErrResponse "Range not satisfiable"
 // @Failure 500 {object} utils.ErrResponse "Failed to get static file"
 // @Router /api/v1/files/stream/{fileId} [get]
 func (h *StaticEndpoint) GetFileStream(writer http.ResponseWriter, r *http.Request) {
@@ -92,39 +106,182 @@ func (h *StaticEndpoint) GetFileStream(writer http.ResponseWriter, r *http.Reque
 	fileIdStr := mux.Vars(r)["fileId"]
 	fileId, err := uuid.Parse(fileIdStr)
 	if err != nil {
-		h.sendError(writer, http.StatusBadRequest, err, "invalid file ID", nil)
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid file ID", nil)
 		return
 	}
 
-	filePath, err := h.staticGrpcClient.GetStatic(fileId)
+	filePath, err := h.staticGrpcClient.GetStatic(r.Context(), fileId)
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to get static file path", nil)
+		h.handleFileError(writer, r, err, "failed to get static file path")
 		return
 	}
 
-	fileStream, err := h.staticGrpcClient.GetStaticFile(filePath)
+	info, err := h.staticGrpcClient.GetStaticFileInfo(r.Context(), filePath)
 	if err != nil {
-		if errors.Is(err, ErrStaticFileNotFound) {
-			h.sendError(writer, http.StatusNotFound, err, "static file not found", nil)
-		} else {
-			h.sendError(writer, http.StatusInternalServerError, err, "failed to get static file", nil)
+		h.handleFileError(writer, r, err, "failed to get static file info")
+		return
+	}
+
+	etag := utils.FormatETag(info.ETag)
+	header := writer.Header()
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	header.Set("Cache-Control", immutableCacheControl)
+	header.Set("Accept-Ranges", "bytes")
+
+	if utils.NotModified(r, etag, info.ModTime) {
+		writer.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	status, offset, length := http.StatusOK, int64(0), info.Size
+	if utils.RangeApplies(r, etag, info.ModTime) {
+		byteRange, err := utils.ParseRange(r.Header.Get("Range"), info.Size)
+		if err != nil {
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", info.Size))
+			h.sendError(writer, r, http.StatusRequestedRangeNotSatisfiable, err, "invalid range", nil)
+			return
 		}
+		if byteRange != nil {
+			status, offset, length = http.StatusPartialContent, byteRange.Start, byteRange.Length
+			header.Set("Content-Range", byteRange.ContentRange(info.Size))
+		}
+	}
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = "image/webp"
+	}
+
+	if r.Method == http.MethodHead {
+		header.Set("Content-Type", contentType)
+		header.Set("Content-Length", strconv.FormatInt(length, 10))
+		writer.WriteHeader(status)
 		return
 	}
 
-	writer.Header().Set("Content-Type", "image/webp")
-	writer.WriteHeader(http.StatusOK)
+	fileStream, err := h.staticGrpcClient.GetStaticFileRange(r.Context(), filePath, offset, length)
+	if err != nil {
+		h.handleFileError(writer, r, err, "failed to get static file")
+		return
+	}
+
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.FormatInt(length, 10))
+	writer.WriteHeader(status)
+
+	if _, err = io.Copy(writer, fileStream); err != nil {
+		logger.Error("failed to write file stream", zap.Error(err))
+		return
+	}
+
+	logger.Info("static file stream sent", zap.Int("status", status), zap.Int64("offset", offset),
+		zap.Int64("length", length))
+}
+
+func (h *StaticEndpoint) handleFileError(writer http.ResponseWriter, r *http.Request, err error, context string) {
+	if errors.Is(err, usecase.ErrStaticNotFound) {
+		h.sendError(writer, r, http.StatusNotFound, ErrStaticFileNotFound, context, nil)
+		return
+	}
+	h.sendError(writer, r, http.StatusInternalServerError, ErrFailedToGetStatic, context,
+		map[string]string{"error": err.Error()})
+}
+
+// isImageVariantRequest отличает запрос изображения от запроса URL файла, который
+// по тому же пути без параметров возвращает JSON
+func isImageVariantRequest(r *http.Request, _ *mux.RouteMatch) bool {
+	query := r.URL.Query()
+	return query.Has("w") || query.Has("h") || query.Has("fit") || query.Has("variant")
+}
+
+func parseImageVariant(r *http.Request) (entity.ImageVariant, error) {
+	query := r.URL.Query()
+	format := utils.NegotiateImageFormat(r.Header.Get("Accept"), entity.ImageFormatsByPreference)
 
-	_, err = io.Copy(writer, fileStream)
+	if preset := query.Get("variant"); preset != "" {
+		return entity.ImagePreset(preset, format)
+	}
+
+	variant := entity.ImageVariant{Fit: entity.ImageFit(query.Get("fit")), Format: format}
+	for param, side := range map[string]*int{"w": &variant.Width, "h": &variant.Height} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return variant, ErrInvalidImageSize
+		}
+		*side = size
+	}
+	return variant, nil
+}
+
+// GetImage godoc
+// @Summary Get image variant by file ID
+// @Description Get a resized copy of the image. The format (WebP or JPEG) is chosen from the Accept header.
+// @Description Sizes are rounded up to a fixed set of steps, variants are generated on first request and cached.
+// @Tags static
+// @Produce image/webp
+// @Produce image/jpeg
+// @Param fileId path string true "File ID"
+// @Param w query int false "Maximum width"
+// @Param h query int false "Maximum height"
+// @Param fit query string false "cover (crop to fill, default when both w and h are set) or contain"
+// @Param variant query string false "Preset: thumb, card, full or original"
+// @Success 200 {binary} []byte "Image content"
+// @Failure 400 {object} utils.ErrResponse "Invalid file ID or variant"
+// @Failure 404 {object} utils.ErrResponse "Static file not found"
+// @Failure 500 {object} utils.ErrResponse "Failed to get static file"
+// @Router /api/v1/files/{fileId} [get]
+func (h *StaticEndpoint) GetImage(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+
+	logger.Info("get image variant request")
+
+	fileId, err := uuid.Parse(mux.Vars(r)["fileId"])
 	if err != nil {
-		h.sendError(writer, http.StatusInternalServerError, err, "failed to write file stream", nil)
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidStaticID, "invalid file ID", nil)
+		return
 	}
 
-	logger.Info("static file stream sent")
+	variant, err := parseImageVariant(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid image variant", nil)
+		return
+	}
+
+	file, err := h.staticGrpcClient.GetStaticVariant(r.Context(), fileId, variant)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrStaticInvalidVariant):
+			h.sendError(writer, r, http.StatusBadRequest, usecase.ErrStaticInvalidVariant, "invalid image variant", nil)
+		case errors.Is(err, usecase.ErrStaticNotFound):
+			h.sendError(writer, r, http.StatusNotFound, ErrStaticFileNotFound, "static file not found", nil)
+		default:
+			h.sendError(writer, r, http.StatusInternalServerError, ErrFailedToGetStatic, "failed to get image variant",
+				map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	writer.Header().Set("Content-Type", variant.Format.ContentType())
+	writer.Header().Set("Cache-Control", immutableCacheControl)
+	writer.Header().Set("Vary", "Accept")
+	writer.WriteHeader(http.StatusOK)
+
+	if _, err = io.Copy(writer, file); err != nil {
+		logger.Error("failed to write image variant", zap.Error(err))
+		return
+	}
+
+	logger.Info("image variant sent", zap.String("file_id", fileId.String()), zap.Int("width", variant.Width),
+		zap.Int("height", variant.Height), zap.String("format", string(variant.Format)))
 }
 
-func (e *StaticEndpoint) sendError(w http.ResponseWriter, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
-	logger := middleware.GetLogger(context.Background())
+func (e *StaticEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
 
 	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
 	utils.SendErrorResponse(w, statusCode, err.Error())
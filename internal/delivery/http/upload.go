@@ -0,0 +1,314 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidUploadID          = errors.New("invalid upload ID")
+	ErrUnsupportedChunkType     = errors.New("chunk content type must be " + utils.UploadChunkContentType)
+	ErrUploadNotFound           = errors.New("upload not found")
+	ErrUploadOffsetConflict     = errors.New("upload offset does not match received size")
+	ErrUploadIncomplete         = errors.New("upload is incomplete")
+	ErrUploadPurposeMismatch    = errors.New("upload was created for another purpose")
+	ErrInvalidUploadDescription = errors.New("invalid upload length, purpose or file type")
+	ErrImageRejected            = errors.New("image was rejected by moderation")
+	ErrVideoUnsupported         = errors.New("video uploads are not supported")
+)
+
+// UploadEndpoint - загрузка изображений и видео по частям: клиент создает загрузку, передает части
+// запросами PATCH с указанием смещения и после обрыва связи узнает смещение запросом HEAD.
+// Полученный файл привязывается к аватару или объявлению параметром upload_id их ручек загрузки
+type UploadEndpoint struct {
+	staticGrpcClient static.StaticGrpcClient
+	sessionManager   *utils.SessionManager
+}
+
+func NewUploadEndpoint(staticGrpcClient static.StaticGrpcClient, sessionManager *utils.SessionManager) *UploadEndpoint {
+	return &UploadEndpoint{
+		staticGrpcClient: staticGrpcClient,
+		sessionManager:   sessionManager,
+	}
+}
+
+func (h *UploadEndpoint) ConfigureProtectedRoutes(router *mux.Router) {
+	protected := router.PathPrefix("/api/v1").Subrouter()
+	protected.HandleFunc("/uploads", h.Create).Methods(http.MethodPost)
+	protected.HandleFunc("/uploads/{uploadId}", h.GetOffset).Methods(http.MethodHead)
+	protected.HandleFunc("/uploads/{uploadId}", h.AppendChunk).Methods(http.MethodPatch)
+	protected.HandleFunc("/uploads/{uploadId}", h.Cancel).Methods(http.MethodDelete)
+}
+
+func setUploadHeaders(writer http.ResponseWriter, upload *entity.ResumableUpload) {
+	writer.Header().Set(utils.UploadOffsetHeader, strconv.FormatInt(upload.Offset, 10))
+	writer.Header().Set(utils.UploadLengthHeader, strconv.FormatInt(upload.Metadata.Size, 10))
+	writer.Header().Set(utils.UploadExpiresHeader, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	writer.Header().Set("Cache-Control", "no-store")
+}
+
+// Create godoc
+// @Summary Create a resumable upload
+// @Description Start uploading an image or a video in chunks. Upload-Metadata is a comma separated list of keys
+// @Description with base64 values: purpose (avatar, advert or advert_video) is required, filename and filetype are optional.
+// @Tags uploads
+// @Produce json
+// @Param Upload-Length header int true "Full file size in bytes"
+// @Param Upload-Metadata header string true "Upload metadata, e.g. purpose YXZhdGFy,filetype aW1hZ2UvcG5n"
+// @Success 201 {object} dto.ResumableUpload "Created upload, its URL is in the Location header"
+// @Failure 400 {object} utils.ErrResponse "Invalid upload length, purpose or file type"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 413 {object} utils.ErrResponse "File size exceeds limit"
+// @Failure 500 {object} utils.ErrResponse "Failed to create upload"
+// @Router /api/v1/uploads [post]
+func (h *UploadEndpoint) Create(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("create upload request")
+
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return
+	}
+
+	size, err := utils.ParseUploadLength(r.Header.Get(utils.UploadLengthHeader))
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid upload length", nil)
+		return
+	}
+	metadata, err := utils.ParseUploadMetadata(r.Header.Get(utils.UploadMetadataHeader))
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid upload metadata", nil)
+		return
+	}
+
+	upload, err := h.staticGrpcClient.CreateUpload(r.Context(), userID, entity.UploadMetadata{
+		Filename:    metadata["filename"],
+		ContentType: metadata["filetype"],
+		Purpose:     entity.UploadPurpose(metadata["purpose"]),
+		Size:        size,
+	})
+	if err != nil {
+		statusCode, respErr := uploadErrorStatus(err)
+		h.sendError(writer, r, statusCode, respErr, "failed to create upload", nil)
+		return
+	}
+
+	logger.Info("upload created", zap.String("upload_id", upload.ID.String()), zap.Int64("size", size))
+	setUploadHeaders(writer, upload)
+	writer.Header().Set("Location", "/api/v1/uploads/"+upload.ID.String())
+	utils.SendJSONResponse(writer, http.StatusCreated, dto.ResumableUpload{
+		ID:        upload.ID,
+		Purpose:   string(upload.Metadata.Purpose),
+		Offset:    upload.Offset,
+		Size:      upload.Metadata.Size,
+		ExpiresAt: upload.ExpiresAt,
+	})
+}
+
+// GetOffset godoc
+// @Summary Get resumable upload offset
+// @Description Get how many bytes of the upload were received, to resume it after a disconnect.
+// @Tags uploads
+// @Param uploadId path string true "Upload ID"
+// @Success 200 "Received size is in the Upload-Offset header"
+// @Failure 400 {object} utils.ErrResponse "Invalid upload ID"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 404 {object} utils.ErrResponse "Upload not found or expired"
+// @Failure 500 {object} utils.ErrResponse "Failed to get upload"
+// @Router /api/v1/uploads/{uploadId} [head]
+func (h *UploadEndpoint) GetOffset(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("get upload offset request")
+
+	uploadID, userID, ok := h.parseRequest(writer, r)
+	if !ok {
+		return
+	}
+
+	upload, err := h.staticGrpcClient.GetUpload(r.Context(), uploadID, userID)
+	if err != nil {
+		statusCode, respErr := uploadErrorStatus(err)
+		h.sendError(writer, r, statusCode, respErr, "failed to get upload", nil)
+		return
+	}
+
+	setUploadHeaders(writer, upload)
+	writer.WriteHeader(http.StatusOK)
+}
+
+// AppendChunk godoc
+// @Summary Upload a chunk of a resumable upload
+// @Description Append the request body to the upload. Upload-Offset must be equal to the received size.
+// @Tags uploads
+// @Accept application/offset+octet-stream
+// @Param uploadId path string true "Upload ID"
+// @Param Upload-Offset header int true "Offset of the chunk"
+// @Success 204 "Chunk received, new offset is in the Upload-Offset header"
+// @Failure 400 {object} utils.ErrResponse "Invalid upload ID or offset"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 404 {object} utils.ErrResponse "Upload not found or expired"
+// @Failure 409 {object} utils.ErrResponse "Offset does not match received size"
+// @Failure 413 {object} utils.ErrResponse "Chunk exceeds upload length or chunk size limit"
+// @Failure 415 {object} utils.ErrResponse "Unsupported chunk content type"
+// @Failure 500 {object} utils.ErrResponse "Failed to upload chunk"
+// @Router /api/v1/uploads/{uploadId} [patch]
+func (h *UploadEndpoint) AppendChunk(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("append upload chunk request")
+
+	uploadID, userID, ok := h.parseRequest(writer, r)
+	if !ok {
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != utils.UploadChunkContentType {
+		h.sendError(writer, r, http.StatusUnsupportedMediaType, ErrUnsupportedChunkType, "unsupported chunk content type", nil)
+		return
+	}
+	offset, err := utils.ParseUploadOffset(r.Header.Get(utils.UploadOffsetHeader))
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, err, "invalid upload offset", nil)
+		return
+	}
+
+	upload, err := h.staticGrpcClient.AppendUpload(r.Context(), uploadID, userID, offset, r.Body)
+	if err != nil {
+		statusCode, respErr := uploadErrorStatus(err)
+		h.sendError(writer, r, statusCode, respErr, "failed to upload chunk",
+			map[string]string{"upload_id": uploadID.String(), "offset": strconv.FormatInt(offset, 10)})
+		return
+	}
+
+	logger.Info("upload chunk received", zap.String("upload_id", uploadID.String()), zap.Int64("offset", upload.Offset))
+	setUploadHeaders(writer, upload)
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// Cancel godoc
+// @Summary Cancel a resumable upload
+// @Description Delete the upload and all received chunks.
+// @Tags uploads
+// @Param uploadId path string true "Upload ID"
+// @Success 204 "Upload deleted"
+// @Failure 400 {object} utils.ErrResponse "Invalid upload ID"
+// @Failure 401 {object} utils.ErrResponse "User not found"
+// @Failure 404 {object} utils.ErrResponse "Upload not found or expired"
+// @Failure 500 {object} utils.ErrResponse "Failed to delete upload"
+// @Router /api/v1/uploads/{uploadId} [delete]
+func (h *UploadEndpoint) Cancel(writer http.ResponseWriter, r *http.Request) {
+	logger := middleware.GetLogger(r.Context())
+	logger.Info("cancel upload request")
+
+	uploadID, userID, ok := h.parseRequest(writer, r)
+	if !ok {
+		return
+	}
+
+	if err := h.staticGrpcClient.CancelUpload(r.Context(), uploadID, userID); err != nil {
+		statusCode, respErr := uploadErrorStatus(err)
+		h.sendError(writer, r, statusCode, respErr, "failed to delete upload", nil)
+		return
+	}
+
+	logger.Info("upload cancelled", zap.String("upload_id", uploadID.String()))
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// parseRequest возвращает id загрузки из пути и id текущего пользователя, отвечая ошибкой, если их нет
+func (h *UploadEndpoint) parseRequest(writer http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := h.sessionManager.GetUserID(r)
+	if err != nil {
+		h.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	uploadID, err := uuid.Parse(mux.Vars(r)["uploadId"])
+	if err != nil {
+		h.sendError(writer, r, http.StatusBadRequest, ErrInvalidUploadID, "invalid upload ID", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return uploadID, userID, true
+}
+
+func (h *UploadEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
+
+	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
+	utils.SendErrorResponse(w, statusCode, err.Error())
+}
+
+// receiveFile загружает файл из поля формы field или, если передан параметр upload_id,
+// завершает загрузку по частям. ownerID - пользователь, создавший загрузку
+func receiveFile(client static.StaticGrpcClient, r *http.Request, field string, ownerID uuid.UUID, purpose entity.UploadPurpose) (uuid.UUID, error) {
+	if uploadIDStr := r.URL.Query().Get("upload_id"); uploadIDStr != "" {
+		uploadID, err := uuid.Parse(uploadIDStr)
+		if err != nil {
+			return uuid.Nil, ErrInvalidUploadID
+		}
+		return client.FinishUpload(r.Context(), uploadID, ownerID, purpose)
+	}
+
+	file, fileHeader, err := r.FormFile(field)
+	if err != nil {
+		return uuid.Nil, ErrFileNotAttached
+	}
+	defer file.Close()
+
+	return client.UploadStatic(r.Context(), file, entity.UploadMetadata{
+		Filename:    fileHeader.Filename,
+		ContentType: fileHeader.Header.Get("Content-Type"),
+		Purpose:     purpose,
+		Size:        fileHeader.Size,
+	})
+}
+
+// uploadErrorStatus возвращает HTTP-статус и ошибку для ответа на неудачную загрузку статики
+func uploadErrorStatus(err error) (int, error) {
+	switch {
+	case errors.Is(err, ErrFileNotAttached), errors.Is(err, ErrInvalidUploadID):
+		return http.StatusBadRequest, err
+	case errors.Is(err, usecase.ErrStaticTooBigFile):
+		return http.StatusRequestEntityTooLarge, ErrTooLargeFile
+	case errors.Is(err, usecase.ErrStaticNotImage), errors.Is(err, usecase.ErrStaticImageDimensions),
+		errors.Is(err, usecase.ErrStaticNotVideo), errors.Is(err, usecase.ErrStaticVideoDuration):
+		return http.StatusBadRequest, err
+	case errors.Is(err, usecase.ErrStaticVideoDisabled):
+		return http.StatusUnsupportedMediaType, ErrVideoUnsupported
+	case errors.Is(err, usecase.ErrStaticUnknownPurpose), errors.Is(err, usecase.ErrStaticUploadLength):
+		return http.StatusBadRequest, ErrInvalidUploadDescription
+	case errors.Is(err, usecase.ErrStaticUploadPurpose):
+		return http.StatusBadRequest, ErrUploadPurposeMismatch
+	case errors.Is(err, usecase.ErrStaticUploadNotFound):
+		return http.StatusNotFound, ErrUploadNotFound
+	case errors.Is(err, usecase.ErrStaticUploadOffset):
+		return http.StatusConflict, ErrUploadOffsetConflict
+	case errors.Is(err, usecase.ErrStaticUploadIncomplete):
+		return http.StatusConflict, ErrUploadIncomplete
+	case errors.Is(err, usecase.ErrStaticImageRejected):
+		return http.StatusUnprocessableEntity, ErrImageRejected
+	}
+
+	if status, ok := status.FromError(err); ok {
+		switch status.Code() {
+		case codes.DeadlineExceeded:
+			return http.StatusGatewayTimeout, ErrTimeout
+		case codes.ResourceExhausted:
+			return http.StatusRequestEntityTooLarge, ErrTooLargeFile
+		}
+	}
+	return http.StatusInternalServerError, ErrFailedToUploadFile
+}
@@ -1,11 +1,8 @@
 package http
 
 import (
-	"bytes"
-	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -14,12 +11,11 @@ import (
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/grpc/static"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/utils"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 var (
@@ -38,15 +34,18 @@ type UserEndpoint struct {
 	sessionManager   *utils.SessionManager
 	staticGrpcClient static.StaticGrpcClient
 	policy           *bluemonday.Policy
+	guestCart        *GuestCartEndpoint
 }
 
-func NewUserEndpoint(userUC usecase.User, authUC usecase.Auth, sessionManager *utils.SessionManager, staticGrpcClient static.StaticGrpcClient, policy *bluemonday.Policy) *UserEndpoint {
+func NewUserEndpoint(userUC usecase.User, authUC usecase.Auth, sessionManager *utils.SessionManager, staticGrpcClient static.StaticGrpcClient,
+	policy *bluemonday.Policy, guestCart *GuestCartEndpoint) *UserEndpoint {
 	return &UserEndpoint{
 		userUC:           userUC,
 		authUC:           authUC,
 		sessionManager:   sessionManager,
 		staticGrpcClient: staticGrpcClient,
 		policy:           policy,
+		guestCart:        guestCart,
 	}
 }
 
@@ -67,27 +66,27 @@ func (u *UserEndpoint) ConfigureUnprotectedRoutes(router *mux.Router) {
 	router.HandleFunc("/api/v1/profile/{user_id}", u.GetProfile).Methods(http.MethodGet)
 }
 
-func (u *UserEndpoint) handleError(w http.ResponseWriter, err error, context string, additionalInfo map[string]string) {
+func (u *UserEndpoint) handleError(w http.ResponseWriter, r *http.Request, err error, context string, additionalInfo map[string]string) {
 	var errUserIncorrectData usecase.UserIncorrectDataError
 
 	switch {
 	case errors.Is(err, usecase.ErrUserNotFound):
-		u.sendError(w, http.StatusNotFound, ErrUserNotFound, context, additionalInfo)
+		u.sendError(w, r, http.StatusNotFound, ErrUserNotFound, context, additionalInfo)
 	case errors.Is(err, usecase.ErrUserAlreadyExists):
-		u.sendError(w, http.StatusBadRequest, ErrUserAlreadyExists, context, additionalInfo)
+		u.sendError(w, r, http.StatusBadRequest, ErrUserAlreadyExists, context, additionalInfo)
 	case errors.Is(err, usecase.ErrInvalidCredentials):
-		u.sendError(w, http.StatusUnauthorized, ErrInvalidCredentials, context, additionalInfo)
+		u.sendError(w, r, http.StatusUnauthorized, ErrInvalidCredentials, context, additionalInfo)
 	case errors.As(err, &errUserIncorrectData):
-		u.sendError(w, http.StatusBadRequest, errUserIncorrectData, context, additionalInfo)
+		u.sendError(w, r, http.StatusBadRequest, errUserIncorrectData, context, additionalInfo)
 	case errors.Is(err, usecase.ErrOldAndNewPasswordAreTheSame):
-		u.sendError(w, http.StatusBadRequest, ErrOldAndNewPasswordAreTheSame, context, additionalInfo)
+		u.sendError(w, r, http.StatusBadRequest, ErrOldAndNewPasswordAreTheSame, context, additionalInfo)
 	case err != nil:
-		u.sendError(w, http.StatusInternalServerError, err, context, additionalInfo)
+		u.sendError(w, r, http.StatusInternalServerError, err, context, additionalInfo)
 	}
 }
 
-func (u *UserEndpoint) sendError(w http.ResponseWriter, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
-	logger := middleware.GetLogger(context.Background())
+func (u *UserEndpoint) sendError(w http.ResponseWriter, r *http.Request, statusCode int, err error, contextInfo string, additionalInfo map[string]string) {
+	logger := middleware.GetLogger(r.Context())
 
 	logger.Error(err.Error(), zap.String("context", contextInfo), zap.Any("info", additionalInfo))
 	utils.SendErrorResponse(w, statusCode, err.Error())
@@ -95,7 +94,7 @@ func (u *UserEndpoint) sendError(w http.ResponseWriter, statusCode int, err erro
 
 // Signup
 // @Summary User registration
-// @Description Creates a new user in the system
+// @Description Creates a new user in the system. The guest cart, if any, is merged into the user's cart
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -112,21 +111,21 @@ func (u *UserEndpoint) Signup(w http.ResponseWriter, r *http.Request) {
 
 	var credentials dto.Signup
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
-		u.sendError(w, http.StatusBadRequest, err, "error decoding signup request", nil)
+		u.sendError(w, r, http.StatusBadRequest, err, "error decoding signup request", nil)
 		return
 	}
 
 	utils.SanitizeRequestSignup(&credentials, u.policy)
 
-	userID, err := u.userUC.Signup(&credentials)
+	userID, err := u.userUC.Signup(r.Context(), &credentials)
 	if err != nil {
-		u.handleError(w, err, "Signup", map[string]string{"email": credentials.Email})
+		u.handleError(w, r, err, "Signup", map[string]string{"email": credentials.Email})
 		return
 	}
 
-	sessionID, err := u.sessionManager.CreateSession(userID)
+	sessionID, err := u.sessionManager.CreateSession(r.Context(), userID)
 	if err != nil {
-		u.sendError(w, http.StatusInternalServerError, err, "error creating session", map[string]string{"userID": userID.String()})
+		u.sendError(w, r, http.StatusInternalServerError, err, "error creating session", map[string]string{"userID": userID.String()})
 		return
 	}
 	logger.Info("session created", zap.String("sessionID", sessionID), zap.String("userID", userID.String()))
@@ -134,10 +133,11 @@ func (u *UserEndpoint) Signup(w http.ResponseWriter, r *http.Request) {
 	cookie, err := u.sessionManager.SetSession(sessionID)
 	if err != nil {
 		logger.Error("error setting session cookie", zap.Error(err))
-		u.sendError(w, http.StatusInternalServerError, err, "error setting session cookie", nil)
+		u.sendError(w, r, http.StatusInternalServerError, err, "error setting session cookie", nil)
 		return
 	}
 	http.SetCookie(w, cookie)
+	u.guestCart.MergeInto(w, r, userID)
 
 	logger.Info("signup successful", zap.String("sessionID", sessionID))
 	w.Header().Set("X-authenticated", "true")
@@ -146,7 +146,8 @@ func (u *UserEndpoint) Signup(w http.ResponseWriter, r *http.Request) {
 
 // Login
 // @Summary User login
-// @Description Allows a user to log into the system
+// @Description Allows a user to log into the system. The guest cart, if any, is merged into the user's cart:
+// @Description adverts already in the cart and adverts that cannot be bought are skipped
 // @Tags Users
 // @Accept json
 // @Produce json
@@ -164,30 +165,31 @@ func (u *UserEndpoint) Login(w http.ResponseWriter, r *http.Request) {
 
 	var credentials dto.Login
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
-		u.sendError(w, http.StatusBadRequest, err, "error decoding login request", nil)
+		u.sendError(w, r, http.StatusBadRequest, err, "error decoding login request", nil)
 		return
 	}
 	utils.SanitizeRequestLogin(&credentials, u.policy)
 
-	userID, err := u.userUC.Login(&credentials)
+	userID, err := u.userUC.Login(r.Context(), &credentials)
 	if err != nil {
-		u.handleError(w, err, "Login", map[string]string{"email": credentials.Email})
+		u.handleError(w, r, err, "Login", map[string]string{"email": credentials.Email})
 		return
 	}
 
-	sessionID, err := u.sessionManager.CreateSession(userID)
+	sessionID, err := u.sessionManager.CreateSession(r.Context(), userID)
 	if err != nil {
-		u.sendError(w, http.StatusInternalServerError, err, "error creating session", map[string]string{"userID": userID.String()})
+		u.sendError(w, r, http.StatusInternalServerError, err, "error creating session", map[string]string{"userID": userID.String()})
 		return
 	}
 	logger.Info("session created", zap.String("sessionID", sessionID), zap.String("userID", userID.String()))
 
 	cookie, err := u.sessionManager.SetSession(sessionID)
 	if err != nil {
-		u.sendError(w, http.StatusInternalServerError, err, "error setting session cookie", nil)
+		u.sendError(w, r, http.StatusInternalServerError, err, "error setting session cookie", nil)
 		return
 	}
 	http.SetCookie(w, cookie)
+	u.guestCart.MergeInto(w, r, userID)
 	logger.Info("login successful", zap.String("sessionID", sessionID))
 	w.Header().Set("X-authenticated", "true")
 	utils.SendJSONResponse(w, http.StatusOK, sessionID)
@@ -213,19 +215,19 @@ func (u *UserEndpoint) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 	var updatePassword dto.UpdatePassword
 	if err := json.NewDecoder(r.Body).Decode(&updatePassword); err != nil {
-		u.sendError(w, http.StatusBadRequest, err, "error decoding change password request", nil)
+		u.sendError(w, r, http.StatusBadRequest, err, "error decoding change password request", nil)
 		return
 	}
 	utils.SanitizeRequestChangePassword(&updatePassword, u.policy)
 
 	userID, err := u.sessionManager.GetUserID(r)
 	if err != nil {
-		u.sendError(w, http.StatusUnauthorized, err, "unauthorized request", nil)
+		u.sendError(w, r, http.StatusUnauthorized, err, "unauthorized request", nil)
 		return
 	}
-	err = u.userUC.ChangePassword(userID, &updatePassword)
+	err = u.userUC.ChangePassword(r.Context(), userID, &updatePassword)
 	if err != nil {
-		u.handleError(w, err, "ChangePassword", map[string]string{"userID": userID.String()})
+		u.handleError(w, r, err, "ChangePassword", map[string]string{"userID": userID.String()})
 		return
 	}
 
@@ -253,19 +255,19 @@ func (u *UserEndpoint) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 
 	var user dto.UserUpdate
 	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-		u.sendError(w, http.StatusBadRequest, err, "error decoding update profile request", nil)
+		u.sendError(w, r, http.StatusBadRequest, err, "error decoding update profile request", nil)
 		return
 	}
 	utils.SanitizeRequestUserUpdate(&user, u.policy)
 	userID, err := u.sessionManager.GetUserID(r)
 	if err != nil {
-		u.handleError(w, err, "UpdateProfile", nil)
+		u.handleError(w, r, err, "UpdateProfile", nil)
 		return
 	}
 
-	err = u.userUC.UpdateInfo(&user)
+	err = u.userUC.UpdateInfo(r.Context(), &user)
 	if err != nil {
-		u.handleError(w, err, "UpdateProfile", map[string]string{"userID": userID.String()})
+		u.handleError(w, r, err, "UpdateProfile", map[string]string{"userID": userID.String()})
 		return
 	}
 
@@ -292,15 +294,15 @@ func (u *UserEndpoint) GetProfile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := uuid.Parse(vars["user_id"])
 	if err != nil {
-		u.sendError(w, http.StatusBadRequest, err, "error parsing userID", nil)
+		u.sendError(w, r, http.StatusBadRequest, err, "error parsing userID", nil)
 		return
 	}
-	user, err := u.userUC.Get(userID)
+	user, err := u.userUC.Get(r.Context(), userID)
 	switch {
 	case errors.Is(err, usecase.ErrUserNotFound):
-		u.sendError(w, http.StatusNotFound, err, "user not found", nil)
+		u.sendError(w, r, http.StatusNotFound, err, "user not found", nil)
 	case err != nil:
-		u.handleError(w, err, "GetProfile", map[string]string{"userID": userID.String()})
+		u.handleError(w, r, err, "GetProfile", map[string]string{"userID": userID.String()})
 	}
 
 	logger.Info("get profile successful", zap.String("userID", userID.String()))
@@ -325,12 +327,12 @@ func (u *UserEndpoint) GetMe(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := u.sessionManager.GetUserID(r)
 	if err != nil {
-		u.sendError(w, http.StatusUnauthorized, err, "unauthorized request", nil)
+		u.sendError(w, r, http.StatusUnauthorized, err, "unauthorized request", nil)
 		return
 	}
-	user, err := u.userUC.Get(userID)
+	user, err := u.userUC.Get(r.Context(), userID)
 	if err != nil {
-		u.handleError(w, err, "GetMe", map[string]string{"userID": userID.String()})
+		u.handleError(w, r, err, "GetMe", map[string]string{"userID": userID.String()})
 		return
 	}
 
@@ -344,9 +346,13 @@ func (u *UserEndpoint) GetMe(w http.ResponseWriter, r *http.Request) {
 // @Description Upload an image associated with an advert by its ID
 // @Tags adverts
 // @Param user_id path string true "User ID"
-// @Param image formData file true "Image file to upload"
+// @Param image formData file false "Image file to upload"
+// @Param upload_id query string false "ID of a completed resumable upload to use instead of the image field"
 // @Success 200 {string} string "Image uploaded"
-// @Failure 400 {object} utils.ErrResponse "Invalid user ID or file not attached"
+// @Failure 400 {object} utils.ErrResponse "Invalid user ID, file not attached or not an image"
+// @Failure 409 {object} utils.ErrResponse "Resumable upload is incomplete"
+// @Failure 413 {object} utils.ErrResponse "File size exceeds limit"
+// @Failure 422 {object} utils.ErrResponse "Image was rejected by moderation"
 // @Failure 500 {object} utils.ErrResponse "Failed to upload image"
 // @Router /api/v1/user/{user_id}/image [put]
 func (u *UserEndpoint) UploadImage(writer http.ResponseWriter, r *http.Request) {
@@ -357,46 +363,25 @@ func (u *UserEndpoint) UploadImage(writer http.ResponseWriter, r *http.Request)
 	userIDStr := mux.Vars(r)["user_id"]
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		u.sendError(writer, http.StatusBadRequest, err, "invalid advert ID", nil)
-		return
-	}
-
-	fileHeader, _, err := r.FormFile("image")
-	if err != nil {
-		u.sendError(writer, http.StatusBadRequest, err, "file not attached", nil)
+		u.sendError(writer, r, http.StatusBadRequest, err, "invalid advert ID", nil)
 		return
 	}
 
-	data, err := io.ReadAll(fileHeader)
+	sessionUserID, err := u.sessionManager.GetUserID(r)
 	if err != nil {
-		u.sendError(writer, http.StatusInternalServerError, err, "failed to read file", nil)
-		return
-	}
-
-	if err = fileHeader.Close(); err != nil {
-		u.sendError(writer, http.StatusInternalServerError, err, "failed to close file", nil)
+		u.sendError(writer, r, http.StatusUnauthorized, ErrInvalidCredentials, "user not found", nil)
 		return
 	}
 
-	imageId, err := u.staticGrpcClient.UploadStatic(bytes.NewReader(data))
+	imageId, err := receiveFile(u.staticGrpcClient, r, "image", sessionUserID, entity.UploadPurposeAvatar)
 	if err != nil {
-		if status, ok := status.FromError(err); ok {
-			switch status.Code() {
-			case codes.DeadlineExceeded:
-				u.sendError(writer, http.StatusGatewayTimeout, ErrTimeout, "upload image timeout deadline exceeded", nil)
-			case codes.ResourceExhausted:
-				u.sendError(writer, http.StatusRequestEntityTooLarge, ErrTooLargeFile, "file size exceeds limit", nil)
-			default:
-				u.sendError(writer, http.StatusInternalServerError, ErrFailedToUploadFile, "failed to upload image", nil)
-			}
-		} else {
-			u.sendError(writer, http.StatusInternalServerError, ErrFailedToUploadFile, "failed to upload image", nil)
-		}
+		statusCode, respErr := uploadErrorStatus(err)
+		u.sendError(writer, r, statusCode, respErr, "failed to upload image", nil)
 		return
 	}
 
-	if err := u.userUC.UploadImage(userID, imageId); err != nil {
-		u.sendError(writer, http.StatusInternalServerError, err, "failed to upload image", nil)
+	if err := u.userUC.UploadImage(r.Context(), userID, imageId); err != nil {
+		u.sendError(writer, r, http.StatusInternalServerError, err, "failed to upload image", nil)
 		return
 	}
 
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var ErrImportMissingTitleColumn = errors.New("csv header must contain a title column")
+
+var advertCSVHeader = []string{"id", "external_sku", "title", "description", "price", "location", "has_delivery", "category", "status"}
+
+// DecodeAdvertImport читает строки импорта в формате CSV (с заголовком) или JSON (массив объектов).
+// Ошибки разбора отдельных полей CSV сохраняются в ParseError строки, а не прерывают чтение файла.
+func DecodeAdvertImport(r io.Reader, format dto.AdvertFileFormat) ([]dto.AdvertImportRow, error) {
+	switch format {
+	case dto.AdvertFileFormatJSON:
+		var rows []dto.AdvertImportRow
+		if err := json.NewDecoder(r).Decode(&rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	case dto.AdvertFileFormatCSV:
+		return decodeAdvertCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func decodeAdvertCSV(r io.Reader) ([]dto.AdvertImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, ErrImportMissingTitleColumn
+	}
+
+	var rows []dto.AdvertImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := dto.AdvertImportRow{
+			ExternalSKU: field("external_sku"),
+			Title:       field("title"),
+			Description: field("description"),
+			Location:    field("location"),
+			Category:    field("category"),
+			Status:      dto.AdvertStatus(field("status")),
+		}
+		if value := field("price"); value != "" {
+			if row.Price, err = strconv.Atoi(value); err != nil {
+				row.ParseError = fmt.Sprintf("invalid price %q", value)
+			}
+		}
+		if value := field("has_delivery"); value != "" {
+			if row.HasDelivery, err = strconv.ParseBool(value); err != nil {
+				row.ParseError = fmt.Sprintf("invalid has_delivery %q", value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// EncodeAdvertExport записывает строки экспорта в формате CSV или JSON
+func EncodeAdvertExport(w io.Writer, rows []dto.AdvertImportRow, format dto.AdvertFileFormat) error {
+	switch format {
+	case dto.AdvertFileFormatJSON:
+		return json.NewEncoder(w).Encode(rows)
+	case dto.AdvertFileFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(advertCSVHeader); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			id := ""
+			if row.ID != uuid.Nil {
+				id = row.ID.String()
+			}
+			if err := writer.Write([]string{
+				id,
+				row.ExternalSKU,
+				row.Title,
+				row.Description,
+				strconv.Itoa(row.Price),
+				row.Location,
+				strconv.FormatBool(row.HasDelivery),
+				row.Category,
+				string(row.Status),
+			}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func SanitizeImportRows(rows []dto.AdvertImportRow, policy *bluemonday.Policy) {
+	for i := range rows {
+		rows[i].Title = policy.Sanitize(rows[i].Title)
+		rows[i].Description = policy.Sanitize(rows[i].Description)
+		rows[i].Location = policy.Sanitize(rows[i].Location)
+		rows[i].ExternalSKU = policy.Sanitize(rows[i].ExternalSKU)
+	}
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		size     int64
+		expected *ByteRange
+		err      error
+	}{
+		{"no header", "", 100, nil, nil},
+		{"closed range", "bytes=0-9", 100, &ByteRange{Start: 0, Length: 10}, nil},
+		{"open range", "bytes=90-", 100, &ByteRange{Start: 90, Length: 10}, nil},
+		{"suffix", "bytes=-20", 100, &ByteRange{Start: 80, Length: 20}, nil},
+		{"suffix longer than file", "bytes=-200", 100, &ByteRange{Start: 0, Length: 100}, nil},
+		{"end past file", "bytes=50-500", 100, &ByteRange{Start: 50, Length: 50}, nil},
+		{"start past file", "bytes=100-", 100, nil, ErrRangeNotSatisfiable},
+		{"empty suffix", "bytes=-0", 100, nil, ErrRangeNotSatisfiable},
+		{"multiple ranges", "bytes=0-1,5-6", 100, nil, nil},
+		{"unknown unit", "items=0-1", 100, nil, nil},
+		{"reversed", "bytes=9-0", 100, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			byteRange, err := ParseRange(tt.header, tt.size)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, byteRange)
+		})
+	}
+
+	assert.Equal(t, "bytes 80-99/100", ByteRange{Start: 80, Length: 20}.ContentRange(100))
+}
+
+func TestNotModified(t *testing.T) {
+	etag := FormatETag("abc")
+	modTime := time.Date(2024, 12, 1, 10, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected bool
+	}{
+		{"no conditions", nil, false},
+		{"matching etag", map[string]string{"If-None-Match": `"xyz", "abc"`}, true},
+		{"weak etag", map[string]string{"If-None-Match": `W/"abc"`}, true},
+		{"any etag", map[string]string{"If-None-Match": "*"}, true},
+		{"other etag wins over date", map[string]string{
+			"If-None-Match":     `"xyz"`,
+			"If-Modified-Since": modTime.Format(http.TimeFormat),
+		}, false},
+		{"not modified since", map[string]string{"If-Modified-Since": modTime.Format(http.TimeFormat)}, true},
+		{"modified since", map[string]string{"If-Modified-Since": modTime.Add(-time.Hour).Format(http.TimeFormat)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.expected, NotModified(r, etag, modTime))
+		})
+	}
+}
+
+func TestRangeApplies(t *testing.T) {
+	etag := FormatETag("abc")
+	modTime := time.Date(2024, 12, 1, 10, 0, 0, 0, time.UTC)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, RangeApplies(r, etag, modTime))
+
+	r.Header.Set("If-Range", etag)
+	assert.True(t, RangeApplies(r, etag, modTime))
+
+	r.Header.Set("If-Range", `"old"`)
+	assert.False(t, RangeApplies(r, etag, modTime))
+
+	r.Header.Set("If-Range", modTime.Format(http.TimeFormat))
+	assert.True(t, RangeApplies(r, etag, modTime))
+}
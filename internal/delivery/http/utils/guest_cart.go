@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const guestCartCookieName = "guest_cart"
+
+// GuestCartCookie выдает посетителям без входа идентификатор гостевой корзины. Значение cookie -
+// идентификатор и его подпись HMAC-SHA256, поэтому чужую корзину нельзя получить подбором
+type GuestCartCookie struct {
+	secret   []byte
+	lifetime time.Duration
+	secure   bool
+}
+
+func NewGuestCartCookie(secret string, lifetime time.Duration, secure bool) *GuestCartCookie {
+	return &GuestCartCookie{
+		secret:   []byte(secret),
+		lifetime: lifetime,
+		secure:   secure,
+	}
+}
+
+func (c *GuestCartCookie) sign(guestID uuid.UUID) string {
+	hash := hmac.New(sha256.New, c.secret)
+	hash.Write([]byte(guestID.String()))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// GuestID возвращает идентификатор корзины из cookie. Если cookie нет или подпись неверна,
+// возвращает false
+func (c *GuestCartCookie) GuestID(r *http.Request) (uuid.UUID, bool) {
+	cookie, err := r.Cookie(guestCartCookieName)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	rawID, signature, found := strings.Cut(cookie.Value, ".")
+	if !found {
+		return uuid.Nil, false
+	}
+	guestID, err := uuid.Parse(rawID)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(c.sign(guestID))) {
+		return uuid.Nil, false
+	}
+	return guestID, true
+}
+
+// Issue возвращает идентификатор корзины из cookie и выдает новый, если его нет.
+// Срок жизни cookie продлевается вместе с корзиной
+func (c *GuestCartCookie) Issue(w http.ResponseWriter, r *http.Request) uuid.UUID {
+	guestID, ok := c.GuestID(r)
+	if !ok {
+		guestID = uuid.New()
+	}
+	value := guestID.String() + "." + c.sign(guestID)
+	NewCookie(guestCartCookieName, value, time.Now().Add(c.lifetime), true, c.secure).SetCookie(w)
+	return guestID
+}
+
+// Clear удаляет cookie после переноса корзины
+func (c *GuestCartCookie) Clear(w http.ResponseWriter) {
+	NewCookie(guestCartCookieName, "", time.Unix(0, 0), true, c.secure).SetCookie(w)
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuestCartCookie(t *testing.T) {
+	cookies := NewGuestCartCookie("secret", time.Hour, false)
+
+	_, ok := cookies.GuestID(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, ok)
+
+	w := httptest.NewRecorder()
+	guestID := cookies.Issue(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	issued := w.Result().Cookies()
+	assert.Len(t, issued, 1)
+	assert.True(t, issued[0].HttpOnly)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(issued[0])
+	got, ok := cookies.GuestID(r)
+	assert.True(t, ok)
+	assert.Equal(t, guestID, got)
+
+	w = httptest.NewRecorder()
+	assert.Equal(t, guestID, cookies.Issue(w, r), "existing cart keeps its id")
+
+	_, ok = NewGuestCartCookie("other secret", time.Hour, false).GuestID(r)
+	assert.False(t, ok, "signature of another secret")
+
+	_, signature, _ := strings.Cut(issued[0].Value, ".")
+	forged := httptest.NewRequest(http.MethodGet, "/", nil)
+	forged.AddCookie(&http.Cookie{Name: guestCartCookieName, Value: uuid.NewString() + "." + signature})
+	_, ok = cookies.GuestID(forged)
+	assert.False(t, ok, "signature of another cart")
+}
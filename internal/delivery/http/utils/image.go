@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
+
+// acceptQuality возвращает вес q, с которым заголовок Accept допускает MIME-тип contentType.
+// Точное совпадение важнее image/* и */*
+func acceptQuality(accept, contentType string) float64 {
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		level := -1
+		switch {
+		case mediaType == contentType:
+			level = 2
+		case mediaType == "image/*":
+			level = 1
+		case mediaType == "*/*":
+			level = 0
+		}
+		if level <= specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		quality, specificity = q, level
+	}
+	return quality
+}
+
+// NegotiateImageFormat выбирает формат изображения по заголовку Accept. Шаблоны image/* и */*
+// не повод отдавать современный формат: его получают только клиенты, явно назвавшие его.
+// Если ни один формат не назван явно, отдается последний, наиболее совместимый
+func NegotiateImageFormat(accept string, formats []entity.ImageFormat) entity.ImageFormat {
+	fallback := formats[len(formats)-1]
+
+	best, bestQuality := fallback, 0.0
+	for _, format := range formats {
+		if !strings.Contains(strings.ToLower(accept), format.ContentType()) {
+			continue
+		}
+		if quality := acceptQuality(accept, format.ContentType()); quality > bestQuality {
+			best, bestQuality = format, quality
+		}
+	}
+	return best
+}
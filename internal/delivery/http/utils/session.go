@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -30,8 +31,8 @@ func NewSessionManager(grpcClient *auth.GrpcClient, sessionAliveTime int, secure
 	}
 }
 
-func (s *SessionManager) CreateSession(userID uuid.UUID) (string, error) {
-	return s.GrpcClient.CreateSession(userID)
+func (s *SessionManager) CreateSession(ctx context.Context, userID uuid.UUID) (string, error) {
+	return s.GrpcClient.CreateSession(ctx, userID)
 }
 
 func (s *SessionManager) SetSession(value string) (*http.Cookie, error) {
@@ -55,16 +56,16 @@ func (s *SessionManager) GetUserID(r *http.Request) (uuid.UUID, error) {
 		return uuid.Nil, err
 	}
 
-	userID, err := s.GrpcClient.GetUserIDBySession(cookie.Value)
+	userID, err := s.GrpcClient.GetUserIDBySession(r.Context(), cookie.Value)
 	if err != nil {
 		s.Logger.Error("session expired or not found", zap.String("sessionID", cookie.Value))
-		s.DeleteSession(cookie.Value)
+		s.DeleteSession(r.Context(), cookie.Value)
 		return uuid.Nil, ErrSessionExpired
 	}
 
 	return uuid.MustParse(userID), nil
 }
 
-func (s *SessionManager) DeleteSession(sessionID string) error {
-	return s.GrpcClient.DeleteSession(sessionID)
+func (s *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
+	return s.GrpcClient.DeleteSession(ctx, sessionID)
 }
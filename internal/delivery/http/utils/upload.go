@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Заголовки протокола загрузки по частям, совместимые с tus
+const (
+	UploadLengthHeader   = "Upload-Length"
+	UploadOffsetHeader   = "Upload-Offset"
+	UploadMetadataHeader = "Upload-Metadata"
+	UploadExpiresHeader  = "Upload-Expires"
+
+	// UploadChunkContentType - тип тела запроса с частью загрузки
+	UploadChunkContentType = "application/offset+octet-stream"
+)
+
+var (
+	ErrInvalidUploadLength   = errors.New("Upload-Length must be a positive integer")
+	ErrInvalidUploadOffset   = errors.New("Upload-Offset must be a non-negative integer")
+	ErrInvalidUploadMetadata = errors.New("Upload-Metadata must be a list of keys with base64 values")
+)
+
+// ParseUploadLength разбирает заголовок Upload-Length
+func ParseUploadLength(header string) (int64, error) {
+	length, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || length <= 0 {
+		return 0, ErrInvalidUploadLength
+	}
+	return length, nil
+}
+
+// ParseUploadOffset разбирает заголовок Upload-Offset
+func ParseUploadOffset(header string) (int64, error) {
+	offset, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidUploadOffset
+	}
+	return offset, nil
+}
+
+// ParseUploadMetadata разбирает заголовок Upload-Metadata вида "key base64value,key2 base64value2".
+// Значение может отсутствовать, тогда ключу соответствует пустая строка
+func ParseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, ErrInvalidUploadMetadata
+		}
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, ErrInvalidUploadMetadata
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	metadata, err := ParseUploadMetadata("filename cGhvdG8uanBn, filetype aW1hZ2UvanBlZw==,purpose YXZhdGFy,empty")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"filename": "photo.jpg",
+		"filetype": "image/jpeg",
+		"purpose":  "avatar",
+		"empty":    "",
+	}, metadata)
+
+	metadata, err = ParseUploadMetadata("")
+	assert.NoError(t, err)
+	assert.Empty(t, metadata)
+
+	_, err = ParseUploadMetadata("filename not-base64!")
+	assert.ErrorIs(t, err, ErrInvalidUploadMetadata)
+
+	_, err = ParseUploadMetadata(" ,purpose YXZhdGFy")
+	assert.ErrorIs(t, err, ErrInvalidUploadMetadata)
+}
+
+func TestParseUploadLengthAndOffset(t *testing.T) {
+	length, err := ParseUploadLength("1024")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), length)
+
+	for _, header := range []string{"", "0", "-1", "abc"} {
+		_, err = ParseUploadLength(header)
+		assert.ErrorIs(t, err, ErrInvalidUploadLength, header)
+	}
+
+	offset, err := ParseUploadOffset("0")
+	assert.NoError(t, err)
+	assert.Zero(t, offset)
+
+	for _, header := range []string{"", "-1", "1.5"} {
+		_, err = ParseUploadOffset(header)
+		assert.ErrorIs(t, err, ErrInvalidUploadOffset, header)
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	visitorCookieName     = "visitor_id"
+	visitorCookieLifetime = 365 * 24 * time.Hour
+)
+
+// VisitorID возвращает идентификатор анонимного посетителя из cookie и выдает новый, если его нет
+func VisitorID(w http.ResponseWriter, r *http.Request, secure bool) string {
+	if cookie, err := r.Cookie(visitorCookieName); err == nil {
+		if _, err := uuid.Parse(cookie.Value); err == nil {
+			return cookie.Value
+		}
+	}
+
+	visitorID := uuid.NewString()
+	NewCookie(visitorCookieName, visitorID, time.Now().Add(visitorCookieLifetime), true, secure).SetCookie(w)
+	return visitorID
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxUserAddresses - сколько адресов пользователь может сохранить в адресной книге
+const MaxUserAddresses = 10
+
+// AddressFields - структурированный адрес доставки и получатель
+type AddressFields struct {
+	RecipientName string `db:"recipient_name"`
+	Phone         string `db:"phone"`
+	City          string `db:"city"`
+	Street        string `db:"street"`
+	Building      string `db:"building"`
+	Apartment     string `db:"apartment"`
+	Postcode      string `db:"postcode"`
+}
+
+// Text собирает адрес в строку, которая сохраняется в покупке: индекс, город, улица, дом, квартира
+func (a AddressFields) Text() string {
+	parts := make([]string, 0, 5)
+	if a.Postcode != "" {
+		parts = append(parts, a.Postcode)
+	}
+	parts = append(parts, a.City, a.Street, "д. "+a.Building)
+	if a.Apartment != "" {
+		parts = append(parts, "кв. "+a.Apartment)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Address - адрес из адресной книги пользователя
+type Address struct {
+	ID     uuid.UUID `db:"id"`
+	UserID uuid.UUID `db:"user_id"`
+	AddressFields
+	IsDefault bool      `db:"is_default"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// PurchaseAddress - снимок адреса на момент покупки. AddressID - адрес из книги,
+// по которому оформлена покупка, nil для адреса, введенного при оформлении
+type PurchaseAddress struct {
+	PurchaseID uuid.UUID  `db:"purchase_id"`
+	AddressID  *uuid.UUID `db:"address_id"`
+	AddressFields
+}
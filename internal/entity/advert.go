@@ -14,6 +14,8 @@ var (
 	ErrLocationLength    = errors.New("location length exceeds 150 characters")
 	ErrStatusLength      = errors.New("status length exceeds 100 characters")
 	ErrPriceNegative     = errors.New("price cannot be negative")
+	ErrAdvertIncomplete  = errors.New("advert must have a title and a category to be published")
+	ErrExternalSKULength = errors.New("external sku length exceeds 100 characters")
 )
 
 type Advert struct {
@@ -31,6 +33,10 @@ type Advert struct {
 	ViewsNumber uint         
 	CreatedAt   time.Time     `db:"created_at"`
 	UpdatedAt   time.Time     `db:"updated_at"`
+	ExpiresAt   time.Time     `db:"expires_at"`
+	BumpedAt    time.Time     `db:"bumped_at"`
+	PublishAt   *time.Time    `db:"publish_at"`
+	ExternalSKU string        `db:"external_sku"`
 	IsSaved     bool          `db:"is_saved"`
 	IsViewed    bool          `db:"is_viewed"`
 }
@@ -38,11 +44,27 @@ type Advert struct {
 type AdvertStatus string
 
 const (
-	AdvertStatusActive   AdvertStatus = "active"
-	AdvertStatusInactive AdvertStatus = "inactive"
-	AdvertStatusReserved AdvertStatus = "reserved"
+	AdvertStatusActive    AdvertStatus = "active"
+	AdvertStatusInactive  AdvertStatus = "inactive"
+	AdvertStatusReserved  AdvertStatus = "reserved"
+	// Черновик виден только продавцу и может быть незаполненным
+	AdvertStatusDraft AdvertStatus = "draft"
+	// Объявление будет опубликовано автоматически в момент PublishAt
+	AdvertStatusScheduled AdvertStatus = "scheduled"
 )
 
+// IsPublic сообщает, виден ли статус объявления в публичных лентах и поиске
+func (s AdvertStatus) IsPublic() bool {
+	return s != AdvertStatusDraft && s != AdvertStatusScheduled
+}
+
+// AdvertSaleState - то, от чего зависит, можно ли купить объявление: его статус и пользователь продавца
+type AdvertSaleState struct {
+	AdvertID     uuid.UUID    `db:"id"`
+	Status       AdvertStatus `db:"status"`
+	SellerUserID uuid.UUID    `db:"user_id"`
+}
+
 func ValidateAdvert(title, description, location, status string, price int) error {
 	if len(strings.TrimSpace(title)) > 255 {
 		return ErrTitleLength
@@ -61,3 +83,11 @@ func ValidateAdvert(title, description, location, status string, price int) erro
 	}
 	return nil
 }
+
+// ValidateAdvertPublishable проверяет, что объявление заполнено достаточно для публикации
+func ValidateAdvertPublishable(title string, categoryId uuid.UUID) error {
+	if strings.TrimSpace(title) == "" || categoryId == uuid.Nil {
+		return ErrAdvertIncomplete
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatsCounters - счетчики событий объявления за период
+type StatsCounters struct {
+	Views         uint `db:"views"`
+	UniqueViewers uint `db:"unique_viewers"`
+	Saves         uint `db:"saves"`
+	CartAdditions uint `db:"cart_additions"`
+	Purchases     uint `db:"purchases"`
+}
+
+func (c *StatsCounters) Add(other StatsCounters) {
+	c.Views += other.Views
+	c.UniqueViewers += other.UniqueViewers
+	c.Saves += other.Saves
+	c.CartAdditions += other.CartAdditions
+	c.Purchases += other.Purchases
+}
+
+type AdvertDailyStats struct {
+	AdvertId uuid.UUID `db:"advert_id"`
+	Day      time.Time `db:"day"`
+	StatsCounters
+}
+
+type CategoryDailyStats struct {
+	CategoryId uuid.UUID `db:"category_id"`
+	Day        time.Time `db:"day"`
+	Adverts    uint      `db:"adverts"`
+	StatsCounters
+}
+
+type AdvertStatsTotal struct {
+	AdvertId uuid.UUID `db:"advert_id"`
+	Title    string    `db:"title"`
+	StatsCounters
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// BlobInfo - метаданные объекта в хранилище файлов
+type BlobInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+	// ETag - хэш содержимого, меняется вместе с ним
+	ETag string
+}
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DealStatus - этап безопасной сделки. Пока сделка не завершена, покупка остается
+// в in_progress; завершенная сделка завершает покупку, возврат средств - отменяет
+type DealStatus string
+
+const (
+	// DealStatusAwaitingPayment - покупатель еще не подтвердил платеж
+	DealStatusAwaitingPayment DealStatus = "awaiting_payment"
+	// DealStatusHeld - средства заблокированы на карте покупателя, продавец отправляет товар
+	DealStatusHeld DealStatus = "held"
+	// DealStatusShipped - товар отправлен и ждет подтверждения получения покупателем
+	DealStatusShipped DealStatus = "shipped"
+	// DealStatusDisputed - открыт спор, средства заморожены до решения администратора
+	DealStatusDisputed DealStatus = "disputed"
+	// DealStatusReleasing - средства списываются в пользу продавца, спор открыть уже нельзя
+	DealStatusReleasing DealStatus = "releasing"
+	// DealStatusRefunding - блокировка средств снимается, спор открыть уже нельзя
+	DealStatusRefunding DealStatus = "refunding"
+	// DealStatusCompleted - средства списаны в пользу продавца
+	DealStatusCompleted DealStatus = "completed"
+	// DealStatusRefunded - блокировка снята, средства остались у покупателя
+	DealStatusRefunded DealStatus = "refunded"
+	// DealStatusCanceled - покупатель не оплатил сделку
+	DealStatusCanceled DealStatus = "canceled"
+)
+
+// Active сообщает, заблокированы ли средства по сделке
+func (s DealStatus) Active() bool {
+	switch s {
+	case DealStatusHeld, DealStatusShipped, DealStatusDisputed, DealStatusReleasing, DealStatusRefunding:
+		return true
+	}
+	return false
+}
+
+// DealResolution - решение администратора по спору
+type DealResolution string
+
+const (
+	// DealResolutionRelease - списать средства в пользу продавца
+	DealResolutionRelease DealResolution = "release"
+	// DealResolutionRefund - разблокировать средства покупателя
+	DealResolutionRefund DealResolution = "refund"
+)
+
+// SafeDeal - безопасная сделка по покупке: средства покупателя блокируются при оплате
+// и списываются только после подтверждения получения. SellerID - продавец объявлений
+// корзины, SellerUserID - его пользователь
+type SafeDeal struct {
+	ID            uuid.UUID  `db:"id"`
+	PurchaseID    uuid.UUID  `db:"purchase_id"`
+	BuyerID       uuid.UUID  `db:"buyer_id"`
+	SellerID      uuid.UUID  `db:"seller_id"`
+	SellerUserID  uuid.UUID  `db:"seller_user_id"`
+	Status        DealStatus `db:"status"`
+	HeldAt        *time.Time `db:"held_at"`
+	ShippedAt     *time.Time `db:"shipped_at"`
+	DisputedBy    *uuid.UUID `db:"disputed_by"`
+	DisputeReason string     `db:"dispute_reason"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryOptionType - способ доставки, который продавец предлагает для объявления
+type DeliveryOptionType string
+
+const (
+	DeliveryOptionCourier      DeliveryOptionType = "courier"
+	DeliveryOptionParcelLocker DeliveryOptionType = "parcel_locker"
+	DeliveryOptionPost         DeliveryOptionType = "post"
+)
+
+// DeliveryOptionTypes - способы доставки в порядке показа покупателю
+var DeliveryOptionTypes = []DeliveryOptionType{DeliveryOptionCourier, DeliveryOptionParcelLocker, DeliveryOptionPost}
+
+func (t DeliveryOptionType) Valid() bool {
+	switch t {
+	case DeliveryOptionCourier, DeliveryOptionParcelLocker, DeliveryOptionPost:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// MaxDeliveryDays - максимальный срок доставки, который может указать продавец
+	MaxDeliveryDays = 60
+	// MaxDeliveryPrice - максимальная стоимость доставки в рублях
+	MaxDeliveryPrice = 100000
+)
+
+// DeliveryOption - способ доставки объявления. Price - стоимость в рублях,
+// MinDays и MaxDays - срок доставки в днях
+type DeliveryOption struct {
+	AdvertID uuid.UUID          `db:"advert_id"`
+	Type     DeliveryOptionType `db:"type"`
+	Price    uint               `db:"price"`
+	MinDays  uint               `db:"min_days"`
+	MaxDays  uint               `db:"max_days"`
+}
+
+// ShipmentStatus - этап доставки отправления
+type ShipmentStatus string
+
+const (
+	// ShipmentStatusAwaitingShipment - продавец еще не передал отправление перевозчику
+	ShipmentStatusAwaitingShipment ShipmentStatus = "awaiting_shipment"
+	// ShipmentStatusInTransit - отправление у перевозчика
+	ShipmentStatusInTransit ShipmentStatus = "in_transit"
+	// ShipmentStatusReadyForPickup - отправление ждет покупателя в постамате или отделении
+	ShipmentStatusReadyForPickup ShipmentStatus = "ready_for_pickup"
+	// ShipmentStatusDelivered - отправление вручено покупателю
+	ShipmentStatusDelivered ShipmentStatus = "delivered"
+	// ShipmentStatusReturned - отправление вернулось продавцу
+	ShipmentStatusReturned ShipmentStatus = "returned"
+)
+
+// CanChangeTo сообщает, может ли отправление перейти в статус next. Данные перевозчика
+// запрашиваются повторно, поэтому переход назад или в тот же статус пропускается
+func (s ShipmentStatus) CanChangeTo(next ShipmentStatus) bool {
+	switch s {
+	case ShipmentStatusAwaitingShipment:
+		return next == ShipmentStatusInTransit
+	case ShipmentStatusInTransit:
+		return next == ShipmentStatusReadyForPickup || next == ShipmentStatusDelivered || next == ShipmentStatusReturned
+	case ShipmentStatusReadyForPickup:
+		return next == ShipmentStatusDelivered || next == ShipmentStatusReturned
+	default:
+		return false
+	}
+}
+
+// Shipment - отправление продавца по покупке: объявления одного продавца доставляются
+// одной посылкой. Cost - стоимость доставки в рублях, SellerUserID - пользователь продавца,
+// Carrier - перевозчик, который отслеживает TrackingNumber
+type Shipment struct {
+	ID             uuid.UUID          `db:"id"`
+	PurchaseID     uuid.UUID          `db:"purchase_id"`
+	SellerID       uuid.UUID          `db:"seller_id"`
+	SellerUserID   uuid.UUID          `db:"seller_user_id"`
+	BuyerID        uuid.UUID          `db:"buyer_id"`
+	Type           DeliveryOptionType `db:"type"`
+	Cost           uint               `db:"cost"`
+	MinDays        uint               `db:"min_days"`
+	MaxDays        uint               `db:"max_days"`
+	Carrier        string             `db:"carrier"`
+	TrackingNumber string             `db:"tracking_number"`
+	Status         ShipmentStatus     `db:"status"`
+	ShippedAt      *time.Time         `db:"shipped_at"`
+	DeliveredAt    *time.Time         `db:"delivered_at"`
+	CreatedAt      time.Time          `db:"created_at"`
+	UpdatedAt      time.Time          `db:"updated_at"`
+}
+
+// ShipmentEvent - запись истории отправления от перевозчика
+type ShipmentEvent struct {
+	ShipmentID  uuid.UUID      `db:"shipment_id"`
+	Status      ShipmentStatus `db:"status"`
+	Description string         `db:"description"`
+	Location    string         `db:"location"`
+	OccurredAt  time.Time      `db:"occurred_at"`
+}
+
+// TrackingInfo - состояние отправления у перевозчика и его история от старых событий к новым
+type TrackingInfo struct {
+	Status ShipmentStatus
+	Events []ShipmentEvent
+}
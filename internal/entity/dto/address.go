@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// AddressDetails - структурированный адрес доставки. Phone - номер из 10-15 цифр, можно с +,
+// Postcode - шесть цифр, Apartment и Postcode необязательны
+type AddressDetails struct {
+	RecipientName string `json:"recipient_name"`
+	Phone         string `json:"phone"`
+	City          string `json:"city"`
+	Street        string `json:"street"`
+	Building      string `json:"building"`
+	Apartment     string `json:"apartment,omitempty"`
+	Postcode      string `json:"postcode,omitempty"`
+}
+
+// AddressRequest - адрес для адресной книги. IsDefault делает его адресом по умолчанию,
+// false признак не снимает
+type AddressRequest struct {
+	AddressDetails
+	IsDefault bool `json:"is_default"`
+}
+
+type AddressResponse struct {
+	ID uuid.UUID `json:"id"`
+	AddressDetails
+	IsDefault bool `json:"is_default"`
+}
+
+// PurchaseAddress - адрес, по которому оформлена покупка. AddressID - адрес из адресной книги,
+// если покупка оформлена по нему
+type PurchaseAddress struct {
+	AddressID *uuid.UUID `json:"address_id,omitempty"`
+	AddressDetails
+}
@@ -14,6 +14,11 @@ type AdvertRequest struct {
 	Status      AdvertStatus `json:"status"`
 	HasDelivery bool         `json:"has_delivery"`
 	Location    string       `json:"location"`
+	PublishAt   *time.Time   `json:"publish_at,omitempty"`
+}
+
+type PublishAdvertRequest struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
 }
 
 type PreviewAdvert struct {
@@ -38,6 +43,9 @@ type MyPreviewAdvertCard struct {
 	Preview     PreviewAdvert `json:"preview"`
 	ViewsNumber uint          `json:"views_number"`
 	SavesNumber uint          `json:"saves_number"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	BumpedAt    time.Time     `json:"bumped_at"`
+	PublishAt   *time.Time    `json:"publish_at,omitempty"`
 }
 
 type Advert struct {
@@ -55,6 +63,9 @@ type Advert struct {
 	ViewsNumber uint          `json:"views_number"`
 	CreatedAt   time.Time    `json:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	BumpedAt    time.Time    `json:"bumped_at"`
+	PublishAt   *time.Time   `json:"publish_at,omitempty"`
 }
 
 type AdvertCard struct {
@@ -66,7 +77,16 @@ type AdvertCard struct {
 type AdvertStatus string
 
 const (
-	AdvertStatusActive   AdvertStatus = "active"
-	AdvertStatusInactive AdvertStatus = "inactive"
-	AdvertStatusReserved AdvertStatus = "reserved"
+	AdvertStatusActive    AdvertStatus = "active"
+	AdvertStatusInactive  AdvertStatus = "inactive"
+	AdvertStatusReserved  AdvertStatus = "reserved"
+	AdvertStatusDraft     AdvertStatus = "draft"
+	AdvertStatusScheduled AdvertStatus = "scheduled"
 )
+
+// AdvertVideo - видео объявления. Файл отдается по частям через /api/v1/files/stream/{id},
+// а кадр-обложка - как вариант изображения через /api/v1/files/{id}
+type AdvertVideo struct {
+	ID       uuid.UUID `json:"id"`
+	Position int       `json:"position"`
+}
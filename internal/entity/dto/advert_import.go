@@ -0,0 +1,56 @@
+package dto
+
+import "github.com/google/uuid"
+
+type AdvertFileFormat string
+
+const (
+	AdvertFileFormatCSV  AdvertFileFormat = "csv"
+	AdvertFileFormatJSON AdvertFileFormat = "json"
+)
+
+// AdvertImportRow - одна строка файла импорта или экспорта объявлений.
+// Category может содержать как идентификатор, так и название категории.
+type AdvertImportRow struct {
+	ID          uuid.UUID    `json:"id,omitempty"`
+	ExternalSKU string       `json:"external_sku"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Price       int          `json:"price"`
+	Location    string       `json:"location"`
+	HasDelivery bool         `json:"has_delivery"`
+	Category    string       `json:"category"`
+	Status      AdvertStatus `json:"status"`
+	// ParseError заполняется при разборе строки, если ее поля не удалось прочитать
+	ParseError string `json:"-"`
+}
+
+type AdvertImportAction string
+
+const (
+	AdvertImportActionCreated AdvertImportAction = "created"
+	AdvertImportActionUpdated AdvertImportAction = "updated"
+	AdvertImportActionValid   AdvertImportAction = "valid"
+	AdvertImportActionFailed  AdvertImportAction = "failed"
+	// AdvertImportActionSkipped - строка экспорта с зарезервированным или запланированным объявлением.
+	// Такими статусами управляют покупки и отложенная публикация, поэтому импорт их не меняет
+	AdvertImportActionSkipped AdvertImportAction = "skipped"
+)
+
+type AdvertImportRowResult struct {
+	Row         int                `json:"row"`
+	ExternalSKU string             `json:"external_sku,omitempty"`
+	AdvertId    uuid.UUID          `json:"advert_id,omitempty"`
+	Action      AdvertImportAction `json:"action"`
+	Error       string             `json:"error,omitempty"`
+}
+
+type AdvertImportReport struct {
+	DryRun  bool                    `json:"dry_run"`
+	Total   int                     `json:"total"`
+	Created int                     `json:"created"`
+	Updated int                     `json:"updated"`
+	Failed  int                     `json:"failed"`
+	Skipped int                     `json:"skipped"`
+	Rows    []AdvertImportRowResult `json:"rows"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import "github.com/google/uuid"
+
+type AnalyticsCounters struct {
+	Views         uint `json:"views"`
+	UniqueViewers uint `json:"unique_viewers"`
+	Saves         uint `json:"saves"`
+	CartAdditions uint `json:"cart_additions"`
+	Purchases     uint `json:"purchases"`
+}
+
+type AnalyticsDay struct {
+	Day string `json:"day"`
+	AnalyticsCounters
+}
+
+// AnalyticsFunnel - доли перехода между шагами воронки просмотр -> корзина -> покупка
+type AnalyticsFunnel struct {
+	ViewToSave     float64 `json:"view_to_save"`
+	ViewToCart     float64 `json:"view_to_cart"`
+	CartToPurchase float64 `json:"cart_to_purchase"`
+	ViewToPurchase float64 `json:"view_to_purchase"`
+}
+
+// CategoryAverages - среднее значение счетчиков на одно активное объявление категории за период
+type CategoryAverages struct {
+	CategoryId    uuid.UUID `json:"category_id"`
+	Views         float64   `json:"views"`
+	UniqueViewers float64   `json:"unique_viewers"`
+	Saves         float64   `json:"saves"`
+	CartAdditions float64   `json:"cart_additions"`
+	Purchases     float64   `json:"purchases"`
+}
+
+type AdvertAnalytics struct {
+	AdvertId        uuid.UUID         `json:"advert_id"`
+	From            string            `json:"from"`
+	To              string            `json:"to"`
+	Totals          AnalyticsCounters `json:"totals"`
+	Funnel          AnalyticsFunnel   `json:"funnel"`
+	Daily           []AnalyticsDay    `json:"daily"`
+	CategoryAverage *CategoryAverages `json:"category_average,omitempty"`
+}
+
+type AdvertAnalyticsSummary struct {
+	AdvertId uuid.UUID         `json:"advert_id"`
+	Title    string            `json:"title"`
+	Totals   AnalyticsCounters `json:"totals"`
+	Funnel   AnalyticsFunnel   `json:"funnel"`
+}
+
+type SellerAnalytics struct {
+	From    string                   `json:"from"`
+	To      string                   `json:"to"`
+	Totals  AnalyticsCounters        `json:"totals"`
+	Funnel  AnalyticsFunnel          `json:"funnel"`
+	Adverts []AdvertAnalyticsSummary `json:"adverts"`
+}
@@ -25,3 +25,50 @@ type Cart struct {
 type CartResponse struct {
 	Cart Cart `json:"cart"`
 }
+
+// UnavailableReason - почему объявление корзины нельзя купить
+type UnavailableReason string
+
+const (
+	// UnavailableOwnAdvert - объявление самого покупателя
+	UnavailableOwnAdvert UnavailableReason = "own_advert"
+	// UnavailableReserved - объявление уже купил другой пользователь
+	UnavailableReserved UnavailableReason = "reserved"
+	// UnavailableInactive - объявление снято с публикации
+	UnavailableInactive UnavailableReason = "inactive"
+	// UnavailableNotFound - объявление удалено
+	UnavailableNotFound UnavailableReason = "not_found"
+)
+
+// UnavailableAdvert - объявление корзины, которое нельзя купить, и его текущий статус.
+// У удаленного объявления статуса нет
+type UnavailableAdvert struct {
+	AdvertID uuid.UUID           `json:"advert_id"`
+	Status   entity.AdvertStatus `json:"status,omitempty"`
+	Reason   UnavailableReason   `json:"reason"`
+}
+
+// UnavailableAdvertsResponse - ответ на оформление корзины, в которой есть объявления, которые нельзя купить
+type UnavailableAdvertsResponse struct {
+	Code    int                 `json:"code"`
+	Status  string              `json:"status"`
+	Adverts []UnavailableAdvert `json:"adverts"`
+}
+
+// CartMergeResult - итог переноса гостевой корзины в корзину пользователя: Added - перенесенные
+// объявления, Duplicates - уже лежавшие в корзине, Unavailable - те, что купить нельзя
+type CartMergeResult struct {
+	CartID      uuid.UUID           `json:"cart_id"`
+	Added       []uuid.UUID         `json:"added"`
+	Duplicates  []uuid.UUID         `json:"duplicates"`
+	Unavailable []UnavailableAdvert `json:"unavailable"`
+}
+
+// GuestCart - корзина посетителя без входа. Удаленные объявления в ней не показываются
+type GuestCart struct {
+	Adverts []PreviewAdvertCard `json:"adverts"`
+}
+
+type AddAdvertToGuestCartRequest struct {
+	AdvertID uuid.UUID `json:"advert_id"`
+}
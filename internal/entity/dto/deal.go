@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DealResponse - безопасная сделка по покупке. Deadline - срок, до которого продавец должен
+// отправить товар или покупатель подтвердить получение; после него средства возвращаются
+// покупателю или списываются в пользу продавца
+type DealResponse struct {
+	ID            uuid.UUID        `json:"id"`
+	PurchaseID    uuid.UUID        `json:"purchase_id"`
+	BuyerID       uuid.UUID        `json:"buyer_id"`
+	SellerID      uuid.UUID        `json:"seller_id"`
+	Status        string           `json:"status"`
+	Deadline      *time.Time       `json:"deadline,omitempty"`
+	DisputedBy    *uuid.UUID       `json:"disputed_by,omitempty"`
+	DisputeReason string           `json:"dispute_reason,omitempty"`
+	Payment       *PaymentResponse `json:"payment,omitempty"`
+}
+
+type DealDisputeRequest struct {
+	Reason string `json:"reason"`
+}
+
+type DealResolveRequest struct {
+	Resolution string `json:"resolution"`
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryOption - способ доставки объявления: courier, parcel_locker или post.
+// Price - стоимость в рублях, MinDays и MaxDays - срок доставки в днях
+type DeliveryOption struct {
+	Type    string `json:"type"`
+	Price   uint   `json:"price"`
+	MinDays uint   `json:"min_days"`
+	MaxDays uint   `json:"max_days"`
+}
+
+type DeliveryOptionsRequest struct {
+	Options []DeliveryOption `json:"options"`
+}
+
+// ShipmentQuote - доставка объявлений одного продавца одной посылкой
+type ShipmentQuote struct {
+	SellerID uuid.UUID `json:"seller_id"`
+	Cost     uint      `json:"cost"`
+	MinDays  uint      `json:"min_days"`
+	MaxDays  uint      `json:"max_days"`
+}
+
+// DeliveryQuote - стоимость доставки корзины выбранным способом: Cost - сумма по всем
+// продавцам в рублях, срок - по самой долгой посылке
+type DeliveryQuote struct {
+	Type      string          `json:"type"`
+	Cost      uint            `json:"cost"`
+	MinDays   uint            `json:"min_days"`
+	MaxDays   uint            `json:"max_days"`
+	Shipments []ShipmentQuote `json:"shipments"`
+}
+
+type ShipmentEvent struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location,omitempty"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// ShipmentResponse - отправление продавца по покупке вместе с историей от перевозчика
+type ShipmentResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	PurchaseID     uuid.UUID       `json:"purchase_id"`
+	SellerID       uuid.UUID       `json:"seller_id"`
+	Type           string          `json:"type"`
+	Cost           uint            `json:"cost"`
+	MinDays        uint            `json:"min_days"`
+	MaxDays        uint            `json:"max_days"`
+	Carrier        string          `json:"carrier,omitempty"`
+	TrackingNumber string          `json:"tracking_number,omitempty"`
+	Status         string          `json:"status"`
+	ShippedAt      *time.Time      `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	Events         []ShipmentEvent `json:"events,omitempty"`
+}
+
+type ShipmentsRequest struct {
+	Type string `json:"type"`
+}
+
+type TrackingRequest struct {
+	TrackingNumber string `json:"tracking_number"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/google/uuid"
+
+// PaymentResponse - платеж за покупку. Amount - сумма в копейках; по ConfirmationURL
+// покупатель подтверждает оплату на стороне платежного провайдера
+type PaymentResponse struct {
+	ID              uuid.UUID `json:"id"`
+	PurchaseID      uuid.UUID `json:"purchase_id"`
+	Provider        string    `json:"provider"`
+	ExternalID      string    `json:"external_id"`
+	Status          string    `json:"status"`
+	Amount          uint      `json:"amount"`
+	Currency        string    `json:"currency"`
+	ConfirmationURL string    `json:"confirmation_url,omitempty"`
+}
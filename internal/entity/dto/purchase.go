@@ -10,6 +10,19 @@ type PurchaseRequest struct {
 	PaymentMethod  PaymentMethod `json:"payment_method"`
 	DeliveryMethod DeliveryMethod `json:"delivery_method"`
 	UserID         uuid.UUID   	`json:"user_id"`
+	// SafeDeal - оплатить картой через безопасную сделку: средства списываются после
+	// подтверждения получения
+	SafeDeal bool `json:"safe_deal"`
+	// DeliveryOption - способ доставки courier, parcel_locker или post для delivery_method = delivery.
+	// Стоимость доставки добавляется к сумме платежа
+	DeliveryOption string `json:"delivery_option,omitempty"`
+	// AddressID - адрес из адресной книги покупателя. Вместо него можно передать AddressDetails,
+	// тогда Address заполняется из выбранного адреса
+	AddressID *uuid.UUID `json:"address_id,omitempty"`
+	// AddressDetails - структурированный адрес, введенный при оформлении
+	AddressDetails *AddressDetails `json:"address_details,omitempty"`
+	// PurchaseAddress - проверенный адрес покупки, снимок которого сохраняется вместе с ней
+	PurchaseAddress *PurchaseAddress `json:"-"`
 }
 
 type PurchaseStatus string
@@ -40,4 +53,17 @@ type PurchaseResponse struct {
 	Status PurchaseStatus `json:"status"`
 	PaymentMethod PaymentMethod `json:"payment_method"`
 	DeliveryMethod DeliveryMethod `json:"delivery_method"`
+	// SafeDeal - покупка оплачивается через безопасную сделку
+	SafeDeal bool `json:"safe_deal"`
+	// Payment - платеж за покупку картой, который покупателю нужно подтвердить
+	Payment *PaymentResponse `json:"payment,omitempty"`
+	// Deal - безопасная сделка, если покупка оплачивается через нее
+	Deal *DealResponse `json:"deal,omitempty"`
+	// Shipments - отправления продавцов, если выбран способ доставки
+	Shipments []*ShipmentResponse `json:"shipments,omitempty"`
+	// ShippingCost - стоимость доставки в рублях
+	ShippingCost uint `json:"shipping_cost,omitempty"`
+	// AddressDetails - снимок адреса на момент покупки, если он был выбран из адресной книги
+	// или введен по полям
+	AddressDetails *PurchaseAddress `json:"address_details,omitempty"`
 }
@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResumableUpload - состояние загрузки по частям
+type ResumableUpload struct {
+	ID        uuid.UUID `json:"id"`
+	Purpose   string    `json:"purpose"`
+	Offset    int64     `json:"offset"`
+	Size      int64     `json:"size"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -14,8 +14,18 @@ func PSQLWrap(errs ...error) error {
 	return errors.Join(ErrPSQL, errors.Join(errs...))
 }
 
+func BlobWrap(errs ...error) error {
+	return errors.Join(ErrBlob, errors.Join(errs...))
+}
+
+func GatewayWrap(errs ...error) error {
+	return errors.Join(ErrGateway, errors.Join(errs...))
+}
+
 var (
 	ErrInternal = errors.New("internal error")
 	ErrRedis    = errors.New("redis error")
 	ErrPSQL     = errors.New("psql error")
+	ErrBlob     = errors.New("blob store error")
+	ErrGateway  = errors.New("payment gateway error")
 )
@@ -0,0 +1,12 @@
+package entity
+
+// IdempotencyRecord - небезопасный запрос с заголовком Idempotency-Key и ответ на него.
+// Fingerprint - хэш метода, пути и тела запроса; пока запрос обрабатывается, Completed = false
+// и ответа в записи нет
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
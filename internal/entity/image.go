@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+)
+
+type ImageFit string
+
+const (
+	// ImageFitCover заполняет рамку целиком, обрезая края по центру
+	ImageFitCover ImageFit = "cover"
+	// ImageFitContain вписывает изображение в рамку, сохраняя пропорции
+	ImageFitContain ImageFit = "contain"
+)
+
+type ImageFormat string
+
+const (
+	ImageFormatAVIF ImageFormat = "avif"
+	ImageFormatWebP ImageFormat = "webp"
+	ImageFormatJPEG ImageFormat = "jpeg"
+)
+
+// ContentType возвращает MIME-тип формата
+func (f ImageFormat) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Extension возвращает расширение файла для формата
+func (f ImageFormat) Extension() string {
+	if f == ImageFormatJPEG {
+		return "jpg"
+	}
+	return string(f)
+}
+
+// ImageFormatsByPreference - форматы, которые умеет кодировать сервис статики,
+// в порядке предпочтения при согласовании по заголовку Accept. Последний формат
+// отдается клиентам, не указавшим ни один из поддерживаемых
+var ImageFormatsByPreference = []ImageFormat{ImageFormatAVIF, ImageFormatWebP, ImageFormatJPEG}
+
+const MaxImageVariantSide = 2048
+
+// imageVariantSteps - допустимые размеры сторон вариантов. Запрошенный размер округляется
+// вверх до ближайшего шага, чтобы произвольные w и h не порождали бесконечное число копий в кэше
+var imageVariantSteps = []int{64, 128, 200, 320, 480, 640, 800, 1024, 1280, 1600, MaxImageVariantSide}
+
+var (
+	ErrInvalidImageVariant = errors.New("invalid image variant")
+	ErrUnknownImagePreset  = errors.New("unknown image preset")
+)
+
+// ImageVariant описывает производную копию изображения. Нулевая ширина или высота
+// означает, что сторона не ограничена и вычисляется из пропорций оригинала
+type ImageVariant struct {
+	Width  int
+	Height int
+	Fit    ImageFit
+	Format ImageFormat
+}
+
+// Предустановленные варианты, которые создаются сразу при загрузке
+var ImageVariantPresets = map[string]ImageVariant{
+	"thumb":    {Width: 200, Height: 200, Fit: ImageFitCover},
+	"card":     {Width: 640, Height: 480, Fit: ImageFitCover},
+	"full":     {Width: 1600, Height: 1600, Fit: ImageFitContain},
+	"original": {Width: MaxImageVariantSide, Height: MaxImageVariantSide, Fit: ImageFitContain},
+}
+
+// EagerImagePresets - пресеты, которые генерируются при загрузке, остальные - при первом запросе
+var EagerImagePresets = []string{"thumb", "card"}
+
+// ImagePreset возвращает вариант пресета name в формате format
+func ImagePreset(name string, format ImageFormat) (ImageVariant, error) {
+	variant, ok := ImageVariantPresets[name]
+	if !ok {
+		return ImageVariant{}, ErrUnknownImagePreset
+	}
+	variant.Format = format
+	return variant, nil
+}
+
+func roundImageSide(side int) int {
+	if side <= 0 {
+		return 0
+	}
+	for _, step := range imageVariantSteps {
+		if side <= step {
+			return step
+		}
+	}
+	return MaxImageVariantSide
+}
+
+// Normalize проверяет вариант и приводит размеры к допустимым шагам
+func (v ImageVariant) Normalize() (ImageVariant, error) {
+	if v.Width < 0 || v.Height < 0 {
+		return v, ErrInvalidImageVariant
+	}
+	switch v.Fit {
+	case "":
+		v.Fit = ImageFitContain
+		if v.Width > 0 && v.Height > 0 {
+			v.Fit = ImageFitCover
+		}
+	case ImageFitCover, ImageFitContain:
+	default:
+		return v, ErrInvalidImageVariant
+	}
+	switch v.Format {
+	case "":
+		v.Format = ImageFormatWebP
+	case ImageFormatAVIF, ImageFormatWebP, ImageFormatJPEG:
+	default:
+		return v, ErrInvalidImageVariant
+	}
+
+	v.Width, v.Height = roundImageSide(v.Width), roundImageSide(v.Height)
+	if v.Width == 0 && v.Height == 0 {
+		v.Width, v.Height = MaxImageVariantSide, MaxImageVariantSide
+	}
+	// Обрезать можно только в рамку с обеими сторонами
+	if v.Fit == ImageFitCover && (v.Width == 0 || v.Height == 0) {
+		v.Fit = ImageFitContain
+	}
+	return v, nil
+}
+
+// ImageVariantsPrefix возвращает каталог вариантов файла source - имени файла без расширения.
+// Одинаковые файлы хранятся один раз, поэтому и варианты у них общие
+func ImageVariantsPrefix(source string) string {
+	return "variants/" + source
+}
+
+// Key возвращает ключ, под которым вариант файла source хранится в хранилище
+func (v ImageVariant) Key(source string) string {
+	return fmt.Sprintf("%s/%dx%d-%s.%s", ImageVariantsPrefix(source), v.Width, v.Height, v.Fit, v.Format.Extension())
+}
+
+type ImageVerdict string
+
+const (
+	ImageVerdictAllow ImageVerdict = "allow"
+	// ImageVerdictFlag - изображение сохраняется, но помечается для ручной проверки
+	ImageVerdictFlag ImageVerdict = "flag"
+	// ImageVerdictReject - изображение не сохраняется
+	ImageVerdictReject ImageVerdict = "reject"
+)
+
+// ImageInspection - результат проверки изображения перед сохранением. Reason объясняет пометку или отказ
+type ImageInspection struct {
+	Verdict ImageVerdict
+	Reason  string
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentStatus string
+
+const (
+	// PaymentStatusPending - платеж создан и ждет подтверждения покупателем
+	PaymentStatusPending PaymentStatus = "pending"
+	// PaymentStatusWaitingForCapture - средства заблокированы на карте и ждут списания
+	PaymentStatusWaitingForCapture PaymentStatus = "waiting_for_capture"
+	PaymentStatusSucceeded         PaymentStatus = "succeeded"
+	PaymentStatusCanceled          PaymentStatus = "canceled"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+)
+
+// CanChangeTo сообщает, может ли платеж перейти в статус next. Уведомления провайдера
+// приходят повторно и не по порядку, поэтому переход назад или в тот же статус пропускается
+func (s PaymentStatus) CanChangeTo(next PaymentStatus) bool {
+	switch s {
+	case PaymentStatusPending:
+		return next == PaymentStatusWaitingForCapture || next == PaymentStatusSucceeded || next == PaymentStatusCanceled
+	case PaymentStatusWaitingForCapture:
+		return next == PaymentStatusSucceeded || next == PaymentStatusCanceled
+	case PaymentStatusSucceeded:
+		return next == PaymentStatusRefunded
+	default:
+		return false
+	}
+}
+
+// Payment - оплата покупки через платежный шлюз. Amount - сумма в копейках,
+// ExternalID - идентификатор платежа у провайдера, UserID - покупатель
+type Payment struct {
+	ID              uuid.UUID     `db:"id"`
+	PurchaseID      uuid.UUID     `db:"purchase_id"`
+	UserID          uuid.UUID     `db:"user_id"`
+	Provider        string        `db:"provider"`
+	ExternalID      string        `db:"external_id"`
+	Amount          uint          `db:"amount"`
+	Currency        string        `db:"currency"`
+	Status          PaymentStatus `db:"status"`
+	ConfirmationURL string        `db:"confirmation_url"`
+	CreatedAt       time.Time     `db:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at"`
+}
+
+// GatewayPaymentRequest - запрос на создание платежа у провайдера. IdempotenceKey защищает
+// от двойного списания при повторе запроса
+type GatewayPaymentRequest struct {
+	PurchaseID     uuid.UUID
+	Amount         uint
+	Currency       string
+	Description    string
+	ReturnURL      string
+	Capture        bool
+	IdempotenceKey string
+}
+
+// GatewayPayment - состояние платежа у провайдера
+type GatewayPayment struct {
+	ExternalID      string
+	Status          PaymentStatus
+	ConfirmationURL string
+}
+
+// PaymentEvent - уведомление провайдера об изменении статуса платежа
+type PaymentEvent struct {
+	ExternalID string
+	Status     PaymentStatus
+}
@@ -9,6 +9,8 @@ type Purchase struct {
 	Status         PurchaseStatus `db:"status"`
 	PaymentMethod  PaymentMethod `db:"payment_method"`
 	DeliveryMethod DeliveryMethod `db:"delivery_method"` 
+	// SafeDeal - покупка оплачивается через безопасную сделку, поэтому платеж за нее только удерживает средства
+	SafeDeal bool `db:"safe_deal"`
 }
 
 type PurchaseStatus string
@@ -19,7 +21,10 @@ const (
 	StatusPending PurchaseStatus = "pending"
 	StatusCompleted PurchaseStatus = "completed"
 	StatusFailed PurchaseStatus = "in_progress"
-	StatusCanceled PurchaseStatus = "canceled"
+	// StatusInProgress - покупка оплачена и передана продавцу
+	StatusInProgress PurchaseStatus = "in_progress"
+	// StatusCanceled совпадает со значением перечисления purchase_status в БД
+	StatusCanceled PurchaseStatus = "cancelled"
 )
 
 const (
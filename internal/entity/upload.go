@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadPurpose - назначение загружаемого файла, от него зависят ограничения загрузки
+type UploadPurpose string
+
+const (
+	UploadPurposeAvatar UploadPurpose = "avatar"
+	UploadPurposeAdvert UploadPurpose = "advert"
+	// UploadPurposeAdvertVideo - короткое видео к объявлению
+	UploadPurposeAdvertVideo UploadPurpose = "advert_video"
+)
+
+// UploadMetadata - сведения о загружаемом файле, которые клиент передает до его содержимого.
+// Size и ContentType заявлены клиентом и проверяются по фактическому содержимому
+type UploadMetadata struct {
+	Filename    string
+	ContentType string
+	Purpose     UploadPurpose
+	Size        int64
+}
+
+// ResumableUpload - загрузка, которую клиент передает частями и может продолжить после обрыва связи.
+// Offset - сколько байт уже получено, Metadata.Size - полный размер файла
+type ResumableUpload struct {
+	ID        uuid.UUID
+	OwnerID   uuid.UUID
+	Metadata  UploadMetadata
+	Offset    int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Complete сообщает, получен ли файл целиком
+func (u ResumableUpload) Complete() bool {
+	return u.Offset == u.Metadata.Size
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"mime"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VideoFormat string
+
+const (
+	VideoFormatMP4  VideoFormat = "mp4"
+	VideoFormatWebM VideoFormat = "webm"
+)
+
+// ContentType возвращает MIME-тип формата
+func (f VideoFormat) ContentType() string {
+	return "video/" + string(f)
+}
+
+// VideoFormats - принимаемые форматы видео по MIME-типу содержимого
+var VideoFormats = map[string]VideoFormat{
+	VideoFormatMP4.ContentType():  VideoFormatMP4,
+	VideoFormatWebM.ContentType(): VideoFormatWebM,
+}
+
+func init() {
+	// Тип файла в хранилище определяется по расширению, а встроенная таблица Go не знает видеоформатов
+	for _, format := range VideoFormats {
+		_ = mime.AddExtensionType("."+string(format), format.ContentType())
+	}
+}
+
+// VideoInfo - сведения о видео, прочитанные из файла
+type VideoInfo struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+}
+
+// MaxAdvertVideos - сколько видео можно прикрепить к одному объявлению
+const MaxAdvertVideos = 3
+
+// AdvertVideo - видео, прикрепленное к объявлению. Кадр-обложка отдается
+// как вариант изображения той же статики
+type AdvertVideo struct {
+	AdvertID  uuid.UUID
+	VideoID   uuid.UUID
+	Position  int
+	CreatedAt time.Time
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AdvertView - просмотр объявления авторизованным пользователем или анонимным посетителем
+type AdvertView struct {
+	AdvertId  uuid.UUID `json:"advert_id"`
+	UserId    uuid.UUID `json:"user_id"`
+	VisitorId string    `json:"visitor_id"`
+	ViewedAt  time.Time `json:"viewed_at"`
+}
+
+// ViewerKey идентифицирует зрителя: пользователя, если он авторизован, иначе анонимного посетителя
+func (v AdvertView) ViewerKey() string {
+	if v.UserId != uuid.Nil {
+		return "u:" + v.UserId.String()
+	}
+	return "v:" + v.VisitorId
+}
+
+// PendingViews - просмотры, накопленные в Redis и еще не перенесенные в Postgres.
+// BatchId не меняется, пока перенос пачки не подтвержден
+type PendingViews struct {
+	BatchId  uuid.UUID
+	Counters map[uuid.UUID]uint
+	Views    []AdvertView
+}
+
+func (p *PendingViews) Empty() bool {
+	return len(p.Counters) == 0 && len(p.Views) == 0
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type AddressRepository interface {
+	// BeginTransaction начинает транзакцию
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
+
+	// Add сохраняет адрес в адресную книгу. Первый адрес пользователя становится адресом по умолчанию
+	// Возможные ошибки:
+	// ErrAddressLimit - у пользователя уже limit адресов
+	Add(ctx context.Context, tx pgx.Tx, address *entity.Address, limit int) (*entity.Address, error)
+
+	// GetById возвращает адрес пользователя
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	GetById(ctx context.Context, addressID, userID uuid.UUID) (*entity.Address, error)
+
+	// GetByUserId возвращает адреса пользователя, начиная с адреса по умолчанию
+	GetByUserId(ctx context.Context, userID uuid.UUID) ([]*entity.Address, error)
+
+	// Update изменяет адрес пользователя. Адрес становится адресом по умолчанию, если IsDefault,
+	// иначе признак не меняется
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	Update(ctx context.Context, tx pgx.Tx, address *entity.Address) (*entity.Address, error)
+
+	// Delete удаляет адрес пользователя и сообщает, был ли он адресом по умолчанию
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	Delete(ctx context.Context, tx pgx.Tx, addressID, userID uuid.UUID) (bool, error)
+
+	// ClearDefault снимает признак адреса по умолчанию со всех адресов пользователя
+	ClearDefault(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error
+
+	// SetDefault делает адрес адресом по умолчанию. Прежний адрес по умолчанию нужно сначала
+	// сбросить через ClearDefault
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	SetDefault(ctx context.Context, tx pgx.Tx, addressID, userID uuid.UUID) (*entity.Address, error)
+
+	// SetNewestDefault делает адресом по умолчанию последний добавленный адрес пользователя, если он есть
+	SetNewestDefault(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error
+
+	// GetPurchaseSnapshots возвращает адреса переданных покупок. Покупки с адресом
+	// в виде текста в ответ не попадают
+	GetPurchaseSnapshots(ctx context.Context, purchaseIDs []uuid.UUID) ([]*entity.PurchaseAddress, error)
+}
+
+var (
+	ErrAddressNotFound = errors.New("адрес не найден")
+	ErrAddressLimit    = errors.New("превышено количество адресов")
+)
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/google/uuid"
@@ -10,77 +12,142 @@ import (
 
 type AdvertRepository interface {
 	// Get возвращает массив объявлений в соответствии с offset и limit
-	Get(limit, offset int, userId uuid.UUID) ([]*entity.Advert, error)
+	Get(ctx context.Context, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// GetBySellerId возвращает массив объявлений в соответствии с sellerId
-	GetBySellerId(sellerId, userId uuid.UUID) ([]*entity.Advert, error)
+	GetBySellerId(ctx context.Context, sellerId, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// GetByUserId возвращает массив объявлений в соответствии с userId
-	GetByUserId(sellerId, userId uuid.UUID) ([]*entity.Advert, error)
+	GetByUserId(ctx context.Context, sellerId, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// GetByCartId возвращает массив объявлений, которые находятся в корзине
-	GetByCartId(cartId uuid.UUID, userId uuid.UUID) ([]*entity.Advert, error)
+	GetByCartId(ctx context.Context, cartId uuid.UUID, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// GetByCategoryId возвращает массив объявлений по categoryId
-	GetByCategoryId(categoryId, userId uuid.UUID) ([]*entity.Advert, error)
+	GetByCategoryId(ctx context.Context, categoryId, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// GetById возвращает объявление по его идентификатору
 	// Если объявление не найдено, возвращает ErrAdvertNotFound
-	GetById(advertId, userId uuid.UUID) (*entity.Advert, error)
+	GetById(ctx context.Context, advertId, userId uuid.UUID) (*entity.Advert, error)
 
 	// GetSavedByUserId возвращает массив объявлений, которые находятся в сохраненных
-	GetSavedByUserId(userId uuid.UUID) ([]*entity.Advert, error)
+	GetSavedByUserId(ctx context.Context, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// Add добавляет объявление
 	// Возможные ошибки:
 	// ErrAdvertBadRequest - некорректные данные для создания объявления
 	// ErrAdvertAlreadyExists - объявление уже существует
-	Add(advert *entity.Advert) (*entity.Advert, error)
+	Add(ctx context.Context, advert *entity.Advert) (*entity.Advert, error)
 
 	// AddToSaved добавляет объявление в сохраненные
-	AddToSaved(advertId, userId uuid.UUID) error
+	AddToSaved(ctx context.Context, advertId, userId uuid.UUID) error
 
 	// DeleteFromSaved удаляет объявление из сохраненных
-	DeleteFromSaved(userId, advertId uuid.UUID) error
+	DeleteFromSaved(ctx context.Context, userId, advertId uuid.UUID) error
 
-	// Update обновляет объявление
+	// Update обновляет объявление, если его статус все еще from
 	// Возможные ошибки:
 	// ErrAdvertBadRequest - некорректные данные для создания объявления
-	// ErrAdvertNotFound - объявление не найдено
-	Update(advert *entity.Advert) error
+	// ErrAdvertNotFound - объявление не найдено или его статус уже не from
+	Update(ctx context.Context, advert *entity.Advert, from entity.AdvertStatus) error
 
 	// DeleteById удаляет объявление по Id
 	// Возможные ошибки:
 	// ErrAdvertNotFound - объявление не найдено
-	DeleteById(advertId uuid.UUID) error
+	DeleteById(ctx context.Context, advertId uuid.UUID) error
 
 	// UpdateStatus обновляет статус объявления
 	// Возможные ошибки:
 	// ErrAdvertBadRequest - некорректные данные для создания объявления
 	// ErrAdvertNotFound - объявление не найдено
-	UpdateStatus(tx pgx.Tx, advertId uuid.UUID, status entity.AdvertStatus) error
+	UpdateStatus(ctx context.Context, tx pgx.Tx, advertId uuid.UUID, status entity.AdvertStatus) error
 
 	// UploadImage загружает изображение в объявление
-	UploadImage(advertId uuid.UUID, imageId uuid.UUID) error
+	// Возможные ошибки:
+	// ErrAdvertNotFound - объявление не найдено
+	// ErrAdvertImageFlagged - изображение ждет ручной проверки модератором
+	UploadImage(ctx context.Context, advertId uuid.UUID, imageId uuid.UUID) error
 
-	// AddViewed добавляет просмотренное объявление
-	AddViewed(userId, advertId uuid.UUID) error
+	// FlushViews сохраняет накопленные просмотры и увеличивает счетчики просмотров объявлений.
+	// Уже перенесенная пачка batchId пропускается
+	FlushViews(ctx context.Context, batchId uuid.UUID, views []entity.AdvertView, counters map[uuid.UUID]uint) error
 
 	// BeginTransaction начинает транзакцию
-	BeginTransaction() (pgx.Tx, error)
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
 
 	// CheckIfExists проверяет, существует ли объявление
-	CheckIfExists(advertId uuid.UUID) (bool, error)
+	CheckIfExists(ctx context.Context, advertId uuid.UUID) (bool, error)
 
 	// Search ищет объявления по запросу
-	Search(query string, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error)
+	Search(ctx context.Context, query string, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error)
 
 	// Count возвращает количество объявлений
-	Count() (int, error)
+	Count(ctx context.Context) (int, error)
+
+	// Renew продлевает срок публикации активного или снятого объявления на время жизни
+	// его категории и делает его снова активным
+	// Возможные ошибки:
+	// ErrAdvertNotRenewable - объявление зарезервировано покупкой, не опубликовано или не найдено
+	Renew(ctx context.Context, advertId uuid.UUID) error
+
+	// Bump поднимает активное объявление в ленте, если с прошлого поднятия прошло не меньше cooldown
+	// Возможные ошибки:
+	// ErrAdvertBumpCooldown - объявление неактивно или поднималось слишком недавно
+	Bump(ctx context.Context, advertId uuid.UUID, cooldown time.Duration) error
+
+	// ExpireOutdated переводит в inactive активные объявления с истекшим сроком
+	// и возвращает их количество
+	ExpireOutdated(ctx context.Context) (int64, error)
+
+	// GetExpiringUnreminded возвращает активные объявления, срок которых истекает
+	// в течение before и о которых продавцу еще не напоминали
+	GetExpiringUnreminded(ctx context.Context, before time.Duration) ([]*entity.Advert, error)
+
+	// MarkReminded отмечает, что продавцу напомнили об истечении срока объявления.
+	// Возвращает false, если отметка уже стоит
+	MarkReminded(ctx context.Context, advertId uuid.UUID) (bool, error)
+
+	// Publish немедленно публикует черновик или запланированное объявление
+	// Возможные ошибки:
+	// ErrAdvertNotDraft - объявление не является черновиком
+	Publish(ctx context.Context, advertId uuid.UUID) error
+
+	// Schedule планирует публикацию черновика на момент publishAt
+	// Возможные ошибки:
+	// ErrAdvertNotDraft - объявление не является черновиком
+	Schedule(ctx context.Context, advertId uuid.UUID, publishAt time.Time) error
+
+	// PublishScheduled публикует объявления, время публикации которых наступило,
+	// и возвращает их количество
+	PublishScheduled(ctx context.Context) (int64, error)
+
+	// UpsertBySKU создает объявление или обновляет объявление продавца с тем же артикулом.
+	// Снятое объявление, которое снова становится активным, получает новый срок публикации.
+	// Возвращает объявление и признак того, что оно было создано
+	// Возможные ошибки:
+	// ErrAdvertSKULocked - объявление с артикулом зарезервировано покупкой или ждет публикации
+	UpsertBySKU(ctx context.Context, advert *entity.Advert) (*entity.Advert, bool, error)
+
+	// GetForExport возвращает все объявления продавца вместе с артикулами
+	GetForExport(ctx context.Context, sellerId uuid.UUID) ([]*entity.Advert, error)
+
+	// GetSaleState возвращает статус объявления и пользователя его продавца
+	// Возможные ошибки:
+	// ErrAdvertNotFound - объявление не найдено
+	GetSaleState(ctx context.Context, advertId uuid.UUID) (*entity.AdvertSaleState, error)
+
+	// LockByCartId блокирует объявления корзины до конца транзакции, чтобы их не купили
+	// параллельно, и возвращает их статусы и пользователей продавцов
+	LockByCartId(ctx context.Context, tx pgx.Tx, cartId uuid.UUID) ([]*entity.AdvertSaleState, error)
 }
 
 var (
 	ErrAdvertNotFound      = errors.New("объявление не найдено")
 	ErrAdvertBadRequest    = errors.New("некорректные данные для создания объявления")
 	ErrAdvertAlreadyExists = errors.New("объявление уже существует")
+	ErrAdvertBumpCooldown  = errors.New("объявление нельзя поднять сейчас")
+	ErrAdvertNotDraft      = errors.New("объявление не является черновиком")
+	ErrAdvertNotRenewable  = errors.New("объявление нельзя продлить")
+	ErrAdvertSKULocked     = errors.New("объявление с этим артикулом зарезервировано или ждет публикации")
+	ErrAdvertImageFlagged  = errors.New("изображение ждет проверки модератором")
 )
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+)
+
+type AdvertVideoRepository interface {
+	// Add прикрепляет видео к объявлению последним, если у объявления меньше limit видео
+	// Возможные ошибки:
+	// ErrAdvertVideoLimit - у объявления уже limit видео
+	Add(ctx context.Context, advertID, videoID uuid.UUID, limit int) (*entity.AdvertVideo, error)
+
+	// GetByAdvertID возвращает видео объявления в порядке показа
+	GetByAdvertID(ctx context.Context, advertID uuid.UUID) ([]*entity.AdvertVideo, error)
+
+	// Delete открепляет видео от объявления
+	// Возможные ошибки:
+	// ErrAdvertVideoNotFound - видео не прикреплено к объявлению
+	Delete(ctx context.Context, advertID, videoID uuid.UUID) error
+}
+
+var (
+	ErrAdvertVideoLimit    = errors.New("к объявлению прикреплено максимальное количество видео")
+	ErrAdvertVideoNotFound = errors.New("видео объявления не найдено")
+)
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+)
+
+type AnalyticsRepository interface {
+	// RollupDaily пересчитывает дневные агрегаты объявлений и категорий начиная с дня since
+	RollupDaily(ctx context.Context, since time.Time) error
+
+	// GetAdvertDailyStats возвращает дневную статистику объявления за период [from, to]
+	GetAdvertDailyStats(ctx context.Context, advertId uuid.UUID, from, to time.Time) ([]*entity.AdvertDailyStats, error)
+
+	// GetCategoryDailyStats возвращает дневную статистику категории за период [from, to]
+	GetCategoryDailyStats(ctx context.Context, categoryId uuid.UUID, from, to time.Time) ([]*entity.CategoryDailyStats, error)
+
+	// GetSellerTotals возвращает суммарную статистику каждого объявления продавца за период [from, to]
+	GetSellerTotals(ctx context.Context, sellerId uuid.UUID, from, to time.Time) ([]*entity.AdvertStatsTotal, error)
+}
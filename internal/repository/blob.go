@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
+
+// BlobStore - хранилище файлов статики. Ключ объекта - относительный путь вида "images/<name>"
+type BlobStore interface {
+	// Put сохраняет объект под ключом key, перезаписывая существующий
+	Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error
+
+	// Get открывает объект для чтения
+	// Возможные ошибки:
+	// ErrBlobNotFound - объект не найден
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, error)
+
+	// Stat возвращает метаданные объекта
+	// Возможные ошибки:
+	// ErrBlobNotFound - объект не найден
+	Stat(ctx context.Context, key string) (*entity.BlobInfo, error)
+
+	// Delete удаляет объект, отсутствие объекта ошибкой не считается
+	Delete(ctx context.Context, key string) error
+
+	// DeletePrefix удаляет все объекты с ключами вида "<prefix>/..."
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+var (
+	ErrBlobNotFound   = errors.New("объект не найден в хранилище")
+	ErrBlobInvalidKey = errors.New("некорректный ключ объекта")
+)
@@ -0,0 +1,26 @@
+package blobstore
+
+import (
+	"path"
+	"strings"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+)
+
+// cleanKey приводит ключ к каноничному виду и не дает выйти за пределы хранилища
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", repository.ErrBlobInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." {
+			return "", repository.ErrBlobInvalidKey
+		}
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned == "." {
+		return "", repository.ErrBlobInvalidKey
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,197 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"go.uber.org/zap"
+)
+
+// LocalStore хранит объекты в файловой системе под корневым каталогом root
+type LocalStore struct {
+	root   string
+	logger *zap.Logger
+	// etags кэширует хэши содержимого, чтобы не перечитывать файл на каждый Stat
+	etags sync.Map
+}
+
+type localETag struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+func NewLocalStore(root string, logger *zap.Logger) (*LocalStore, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{
+		root:   root,
+		logger: logger,
+	}, nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put записывает объект во временный файл и переименовывает его, чтобы читатели не видели недописанный файл
+func (s *LocalStore) Put(_ context.Context, key string, data io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		s.logger.Error("error creating blob directory", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		s.logger.Error("error creating temporary blob file", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		s.logger.Error("error writing blob", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	if err := tmp.Close(); err != nil {
+		s.logger.Error("error closing blob file", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return entity.BlobWrap(err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		s.logger.Error("error moving blob into place", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+
+	return nil
+}
+
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, repository.ErrBlobNotFound
+	}
+	if err != nil {
+		s.logger.Error("error opening blob", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+	return file, nil
+}
+
+func (s *LocalStore) Stat(_ context.Context, key string) (*entity.BlobInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+		return nil, repository.ErrBlobNotFound
+	}
+	if err != nil {
+		s.logger.Error("error getting blob info", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+
+	etag, err := s.etag(path, info)
+	if err != nil {
+		s.logger.Error("error hashing blob", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+
+	return &entity.BlobInfo{
+		Key:         key,
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+		ModTime:     info.ModTime(),
+		ETag:        etag,
+	}, nil
+}
+
+// etag возвращает SHA-256 содержимого файла. Put заменяет файл целиком, поэтому
+// неизменные размер и время изменения означают неизменное содержимое
+func (s *LocalStore) etag(path string, info fs.FileInfo) (string, error) {
+	if cached, ok := s.etags.Load(path); ok {
+		cached := cached.(localETag)
+		if cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+			return cached.etag, nil
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	etag := hex.EncodeToString(hash.Sum(nil))
+
+	s.etags.Store(path, localETag{size: info.Size(), modTime: info.ModTime(), etag: etag})
+	return etag, nil
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Error("error deleting blob", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	s.etags.Delete(path)
+	return nil
+}
+
+func (s *LocalStore) DeletePrefix(_ context.Context, prefix string) error {
+	path, err := s.path(prefix)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		s.logger.Error("error deleting blob directory", zap.String("prefix", prefix), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	s.etags.Range(func(cached, _ any) bool {
+		if strings.HasPrefix(cached.(string), path+string(filepath.Separator)) {
+			s.etags.Delete(cached)
+		}
+		return true
+	})
+	return nil
+}
@@ -0,0 +1,82 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), zap.NewNop())
+	assert.NoError(t, err)
+
+	testBlobStore(t, store)
+}
+
+// testBlobStore проверяет общий контракт repository.BlobStore для всех реализаций
+func testBlobStore(t *testing.T, store repository.BlobStore) {
+	err := store.Put(context.Background(), "images/file.webp", strings.NewReader("content"), 7, "image/webp")
+	assert.NoError(t, err)
+
+	info, err := store.Stat(context.Background(), "images/file.webp")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), info.Size)
+	assert.Equal(t, "image/webp", info.ContentType)
+	// ETag - SHA-256 содержимого во всех хранилищах, чтобы он не менялся при переезде между ними
+	sum := sha256.Sum256([]byte("content"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), info.ETag)
+
+	err = store.Put(context.Background(), "images/other.webp", strings.NewReader("another"), 7, "image/webp")
+	assert.NoError(t, err)
+	other, err := store.Stat(context.Background(), "images/other.webp")
+	assert.NoError(t, err)
+	assert.NotEqual(t, info.ETag, other.ETag)
+	assert.NoError(t, store.Delete(context.Background(), "images/other.webp"))
+
+	again, err := store.Stat(context.Background(), "images/file.webp")
+	assert.NoError(t, err)
+	assert.Equal(t, info.ETag, again.ETag)
+
+	reader, err := store.Get(context.Background(), "images/file.webp")
+	assert.NoError(t, err)
+	_, err = reader.Seek(3, io.SeekStart)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "tent", string(content))
+	assert.NoError(t, reader.Close())
+
+	_, err = store.Get(context.Background(), "images/missing.webp")
+	assert.ErrorIs(t, err, repository.ErrBlobNotFound)
+	_, err = store.Stat(context.Background(), "images/missing.webp")
+	assert.ErrorIs(t, err, repository.ErrBlobNotFound)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "images/../../secret"} {
+		_, err = store.Get(context.Background(), key)
+		assert.ErrorIs(t, err, repository.ErrBlobInvalidKey, key)
+	}
+
+	assert.NoError(t, store.Delete(context.Background(), "images/file.webp"))
+	assert.NoError(t, store.Delete(context.Background(), "images/file.webp"))
+	_, err = store.Get(context.Background(), "images/file.webp")
+	assert.ErrorIs(t, err, repository.ErrBlobNotFound)
+
+	for _, key := range []string{"variants/a/1.webp", "variants/a/2.webp", "variants/ab/1.webp"} {
+		assert.NoError(t, store.Put(context.Background(), key, strings.NewReader("v"), 1, "image/webp"))
+	}
+	assert.NoError(t, store.DeletePrefix(context.Background(), "variants/a"))
+	_, err = store.Stat(context.Background(), "variants/a/1.webp")
+	assert.ErrorIs(t, err, repository.ErrBlobNotFound)
+	_, err = store.Stat(context.Background(), "variants/a/2.webp")
+	assert.ErrorIs(t, err, repository.ErrBlobNotFound)
+	_, err = store.Stat(context.Background(), "variants/ab/1.webp")
+	assert.NoError(t, err)
+	assert.NoError(t, store.DeletePrefix(context.Background(), "variants/missing"))
+}
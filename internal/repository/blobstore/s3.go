@@ -0,0 +1,239 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+const (
+	s3NoSuchKey = "NoSuchKey"
+	// s3ContentHashMeta - пользовательские метаданные с SHA-256 содержимого. ETag самого S3
+	// не является хэшем содержимого для составных загрузок и при шифровании на стороне хранилища
+	s3ContentHashMeta = "Content-Sha256"
+)
+
+// S3Store хранит объекты в бакете S3-совместимого хранилища (AWS S3, MinIO, Ceph и т.п.)
+type S3Store struct {
+	client  *minio.Client
+	bucket  string
+	timeout time.Duration
+	logger  *zap.Logger
+	// etags кэширует хэши объектов, загруженных без метаданных с хэшем
+	etags sync.Map
+}
+
+type s3ETag struct {
+	s3ETag string
+	etag   string
+}
+
+func NewS3Store(ctx context.Context, client *minio.Client, bucket string, timeout time.Duration, logger *zap.Logger) (*S3Store, error) {
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	return &S3Store{
+		client:  client,
+		bucket:  bucket,
+		timeout: timeout,
+		logger:  logger,
+	}, nil
+}
+
+func isNoSuchKey(err error) bool {
+	return minio.ToErrorResponse(err).Code == s3NoSuchKey
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	data, hash, err := contentHash(data)
+	if err != nil {
+		s.logger.Error("error hashing object", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, key, data, size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: map[string]string{s3ContentHashMeta: hash},
+	})
+	if err != nil {
+		s.logger.Error("error putting object", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	return nil
+}
+
+// Get возвращает объект, который догружает данные диапазонами при чтении и перемещении по нему,
+// поэтому таймаут на само чтение не накладывается
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		s.logger.Error("error getting object", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+
+	// GetObject не обращается к хранилищу, отсутствие объекта выясняется только запросом метаданных
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if isNoSuchKey(err) {
+			return nil, repository.ErrBlobNotFound
+		}
+		s.logger.Error("error getting object info", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+	return object, nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (*entity.BlobInfo, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, repository.ErrBlobNotFound
+		}
+		s.logger.Error("error getting object info", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+
+	etag, err := s.etag(ctx, key, info)
+	if err != nil {
+		s.logger.Error("error hashing object", zap.String("key", key), zap.Error(err))
+		return nil, entity.BlobWrap(err)
+	}
+
+	return &entity.BlobInfo{
+		Key:         key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		ModTime:     info.LastModified,
+		ETag:        etag,
+	}, nil
+}
+
+// etag возвращает SHA-256 содержимого, как и LocalStore. Хэш берется из метаданных объекта,
+// а для объектов, сохраненных до их появления, считается по содержимому один раз
+func (s *S3Store) etag(ctx context.Context, key string, info minio.ObjectInfo) (string, error) {
+	if hash := info.UserMetadata[s3ContentHashMeta]; hash != "" {
+		return hash, nil
+	}
+
+	if cached, ok := s.etags.Load(key); ok {
+		cached := cached.(s3ETag)
+		if cached.s3ETag == info.ETag {
+			return cached.etag, nil
+		}
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer object.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, object); err != nil {
+		return "", err
+	}
+	etag := hex.EncodeToString(hash.Sum(nil))
+
+	s.etags.Store(key, s3ETag{s3ETag: info.ETag, etag: etag})
+	return etag, nil
+}
+
+// contentHash считает SHA-256 данных до отправки, так как метаданные передаются раньше тела.
+// Данные с возможностью перемещения перематываются назад, остальные читаются в память
+func contentHash(data io.Reader) (io.Reader, string, error) {
+	hash := sha256.New()
+	if seeker, ok := data.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(hash, seeker); err != nil {
+			return nil, "", err
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, "", err
+		}
+		return seeker, hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	content, err := io.ReadAll(data)
+	if err != nil {
+		return nil, "", err
+	}
+	hash.Write(content)
+	return bytes.NewReader(content), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil && !isNoSuchKey(err) {
+		s.logger.Error("error deleting object", zap.String("key", key), zap.Error(err))
+		return entity.BlobWrap(err)
+	}
+	return nil
+}
+
+func (s *S3Store) DeletePrefix(ctx context.Context, prefix string) error {
+	prefix, err := cleanKey(prefix)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix + "/", Recursive: true}) {
+		if object.Err != nil {
+			s.logger.Error("error listing objects", zap.String("prefix", prefix), zap.Error(object.Err))
+			return entity.BlobWrap(object.Err)
+		}
+		if err := s.client.RemoveObject(ctx, s.bucket, object.Key, minio.RemoveObjectOptions{}); err != nil && !isNoSuchKey(err) {
+			s.logger.Error("error deleting object", zap.String("key", object.Key), zap.Error(err))
+			return entity.BlobWrap(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package blobstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/connector"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+	meta        http.Header
+}
+
+// fakeS3 - хранилище в памяти, понимающее подмножество протокола S3, которое использует S3Store
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]bool
+	objects map[string]fakeS3Object
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		buckets: make(map[string]bool),
+		objects: make(map[string]fakeS3Object),
+	}
+}
+
+// listObjects отвечает на ListObjectsV2 одной страницей
+func (f *fakeS3) listObjects(w http.ResponseWriter, r *http.Request, bucket string) {
+	if r.URL.Query().Has("location") {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<LocationConstraint>us-east-1</LocationConstraint>`)
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	var keys []string
+	for name := range f.objects {
+		if key, ok := strings.CutPrefix(name, bucket+"/"); ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<ListBucketResult><Name>%s</Name><Prefix>%s</Prefix><KeyCount>%d</KeyCount><IsTruncated>false</IsTruncated>`,
+		bucket, prefix, len(keys))
+	for _, key := range keys {
+		object := f.objects[bucket+"/"+key]
+		fmt.Fprintf(w, `<Contents><Key>%s</Key><Size>%d</Size><LastModified>%s</LastModified></Contents>`,
+			key, len(object.data), object.modTime.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprint(w, `</ListBucketResult>`)
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if key == "" {
+		switch r.Method {
+		case http.MethodHead:
+			if !f.buckets[bucket] {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			f.buckets[bucket] = true
+		case http.MethodGet:
+			f.listObjects(w, r, bucket)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := readS3Payload(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		meta := make(http.Header)
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, "X-Amz-Meta-") {
+				meta[name] = values
+			}
+		}
+		f.objects[bucket+"/"+key] = fakeS3Object{data: body, contentType: r.Header.Get("Content-Type"), modTime: time.Now(), meta: meta}
+		sum := md5.Sum(body)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	case http.MethodGet, http.MethodHead:
+		object, ok := f.objects[bucket+"/"+key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				fmt.Fprintf(w, `<Error><Code>NoSuchKey</Code><Key>%s</Key></Error>`, key)
+			}
+			return
+		}
+		sum := md5.Sum(object.data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Content-Type", object.contentType)
+		for name, values := range object.meta {
+			w.Header()[name] = values
+		}
+		http.ServeContent(w, r, key, object.modTime, bytes.NewReader(object.data))
+	case http.MethodDelete:
+		delete(f.objects, bucket+"/"+key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+// readS3Payload читает тело запроса, снимая кодирование aws-chunked, которое клиент использует без TLS
+func readS3Payload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var payload bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return payload.Bytes(), nil
+		}
+		if _, err := io.CopyN(&payload, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := connector.GetS3Connector(strings.TrimPrefix(server.URL, "http://"), "access", "secret", "us-east-1", false)
+	assert.NoError(t, err)
+
+	store, err := NewS3Store(context.Background(), client, "static", 5*time.Second, zap.NewNop())
+	assert.NoError(t, err)
+	assert.True(t, fake.buckets["static"])
+
+	testBlobStore(t, store)
+
+	// объект, сохраненный без метаданных с хэшем, получает тот же ETag по содержимому
+	fake.objects["static/legacy/file.webp"] = fakeS3Object{data: []byte("content"), contentType: "image/webp", modTime: time.Now()}
+	info, err := store.Stat(context.Background(), "legacy/file.webp")
+	assert.NoError(t, err)
+	sum := sha256.Sum256([]byte("content"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), info.ETag)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
+
+// CarrierProvider - служба доставки, у которой отслеживаются отправления по трек-номеру
+type CarrierProvider interface {
+	// Name возвращает имя перевозчика, под которым отправления хранятся в БД
+	Name() string
+
+	// Register проверяет трек-номер и ставит отправление на отслеживание
+	// Возможные ошибки:
+	// ErrCarrierInvalidTracking - перевозчик не принимает такой трек-номер
+	Register(ctx context.Context, trackingNumber string) error
+
+	// Track возвращает текущий статус отправления и его историю
+	// Возможные ошибки:
+	// ErrCarrierTrackingNotFound - перевозчик не знает отправление
+	Track(ctx context.Context, trackingNumber string) (*entity.TrackingInfo, error)
+}
+
+var (
+	ErrCarrierInvalidTracking  = errors.New("некорректный трек-номер")
+	ErrCarrierTrackingNotFound = errors.New("отправление не найдено у перевозчика")
+)
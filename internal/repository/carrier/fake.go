@@ -0,0 +1,114 @@
+package carrier
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+)
+
+const FakeProviderName = "fake"
+
+// fakeReturnPrefix - отправления с таким трек-номером не забирают и они возвращаются продавцу
+const fakeReturnPrefix = "RET"
+
+var trackingNumberPattern = regexp.MustCompile(`^[A-Z0-9]{8,30}$`)
+
+// fakeStage - этап, до которого отправление доходит через step после предыдущего
+type fakeStage struct {
+	status      entity.ShipmentStatus
+	description string
+	location    string
+}
+
+var (
+	fakeDeliveredStages = []fakeStage{
+		{entity.ShipmentStatusInTransit, "Принято перевозчиком", "Сортировочный центр"},
+		{entity.ShipmentStatusReadyForPickup, "Прибыло в пункт выдачи", "Пункт выдачи"},
+		{entity.ShipmentStatusDelivered, "Вручено получателю", "Пункт выдачи"},
+	}
+	fakeReturnedStages = []fakeStage{
+		{entity.ShipmentStatusInTransit, "Принято перевозчиком", "Сортировочный центр"},
+		{entity.ShipmentStatusReadyForPickup, "Прибыло в пункт выдачи", "Пункт выдачи"},
+		{entity.ShipmentStatusReturned, "Срок хранения истек, возвращено отправителю", "Сортировочный центр"},
+	}
+)
+
+// FakeCarrier - перевозчик в памяти процесса для разработки и тестов. Отправление проходит
+// этапы доставки через каждые step после регистрации; трек-номера с префиксом RET
+// возвращаются продавцу. Неизвестный трек-номер, например после перезапуска, регистрируется
+// при первом запросе
+type FakeCarrier struct {
+	mu         sync.Mutex
+	registered map[string]time.Time
+	step       time.Duration
+	now        func() time.Time
+}
+
+func NewFakeCarrier(step time.Duration) *FakeCarrier {
+	return &FakeCarrier{
+		registered: make(map[string]time.Time),
+		step:       step,
+		now:        time.Now,
+	}
+}
+
+func (c *FakeCarrier) Name() string {
+	return FakeProviderName
+}
+
+func (c *FakeCarrier) Register(_ context.Context, trackingNumber string) error {
+	if !trackingNumberPattern.MatchString(trackingNumber) {
+		return repository.ErrCarrierInvalidTracking
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.register(trackingNumber)
+	return nil
+}
+
+func (c *FakeCarrier) register(trackingNumber string) time.Time {
+	registeredAt, ok := c.registered[trackingNumber]
+	if !ok {
+		registeredAt = c.now()
+		c.registered[trackingNumber] = registeredAt
+	}
+	return registeredAt
+}
+
+func (c *FakeCarrier) Track(_ context.Context, trackingNumber string) (*entity.TrackingInfo, error) {
+	if !trackingNumberPattern.MatchString(trackingNumber) {
+		return nil, repository.ErrCarrierTrackingNotFound
+	}
+
+	c.mu.Lock()
+	registeredAt := c.register(trackingNumber)
+	elapsed := c.now().Sub(registeredAt)
+	c.mu.Unlock()
+
+	stages := fakeDeliveredStages
+	if strings.HasPrefix(trackingNumber, fakeReturnPrefix) {
+		stages = fakeReturnedStages
+	}
+
+	info := &entity.TrackingInfo{}
+	for i, stage := range stages {
+		offset := time.Duration(i) * c.step
+		if i > 0 && elapsed < offset {
+			break
+		}
+		info.Status = stage.status
+		info.Events = append(info.Events, entity.ShipmentEvent{
+			Status:      stage.status,
+			Description: stage.description,
+			Location:    stage.location,
+			OccurredAt:  registeredAt.Add(offset),
+		})
+	}
+	return info, nil
+}
@@ -0,0 +1,69 @@
+package carrier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCarrier() (*FakeCarrier, *time.Time) {
+	now := time.Date(2024, 12, 28, 12, 0, 0, 0, time.UTC)
+	carrier := NewFakeCarrier(time.Hour)
+	carrier.now = func() time.Time { return now }
+	return carrier, &now
+}
+
+func TestFakeCarrier(t *testing.T) {
+	t.Run("Delivered", func(t *testing.T) {
+		carrier, now := newTestCarrier()
+		require.NoError(t, carrier.Register(context.Background(), "AB123456789RU"))
+
+		info, err := carrier.Track(context.Background(), "AB123456789RU")
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShipmentStatusInTransit, info.Status)
+		assert.Len(t, info.Events, 1)
+
+		*now = now.Add(time.Hour)
+		info, err = carrier.Track(context.Background(), "AB123456789RU")
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShipmentStatusReadyForPickup, info.Status)
+
+		*now = now.Add(time.Hour)
+		info, err = carrier.Track(context.Background(), "AB123456789RU")
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShipmentStatusDelivered, info.Status)
+		require.Len(t, info.Events, 3)
+		assert.Equal(t, info.Events[0].OccurredAt.Add(2*time.Hour), info.Events[2].OccurredAt)
+	})
+
+	t.Run("Returned", func(t *testing.T) {
+		carrier, now := newTestCarrier()
+		require.NoError(t, carrier.Register(context.Background(), "RET12345678"))
+
+		*now = now.Add(3 * time.Hour)
+		info, err := carrier.Track(context.Background(), "RET12345678")
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShipmentStatusReturned, info.Status)
+	})
+
+	t.Run("UnknownTrackingIsRegistered", func(t *testing.T) {
+		carrier, _ := newTestCarrier()
+
+		info, err := carrier.Track(context.Background(), "AB123456789RU")
+		require.NoError(t, err)
+		assert.Equal(t, entity.ShipmentStatusInTransit, info.Status)
+	})
+
+	t.Run("InvalidTracking", func(t *testing.T) {
+		carrier, _ := newTestCarrier()
+
+		assert.ErrorIs(t, carrier.Register(context.Background(), "ab-1"), repository.ErrCarrierInvalidTracking)
+		_, err := carrier.Track(context.Background(), "ab-1")
+		assert.ErrorIs(t, err, repository.ErrCarrierTrackingNotFound)
+	})
+}
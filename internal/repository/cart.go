@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -9,13 +10,13 @@ import (
 )
 
 type Cart interface {
-	GetAdvertsByCartId(cartID uuid.UUID) ([]entity.Advert, error)
-	AddAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error
-	DeleteAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error
-	UpdateStatus(tx pgx.Tx, cartID uuid.UUID, status entity.CartStatus) error
-	GetByUserId(userID uuid.UUID) (entity.Cart, error)
-	Create(userID uuid.UUID) (uuid.UUID, error)
-	GetById(cartID uuid.UUID) (entity.Cart, error)
+	GetAdvertsByCartId(ctx context.Context, cartID uuid.UUID) ([]entity.Advert, error)
+	AddAdvert(ctx context.Context, cartID uuid.UUID, AdvertID uuid.UUID) error
+	DeleteAdvert(ctx context.Context, cartID uuid.UUID, AdvertID uuid.UUID) error
+	UpdateStatus(ctx context.Context, tx pgx.Tx, cartID uuid.UUID, status entity.CartStatus) error
+	GetByUserId(ctx context.Context, userID uuid.UUID) (entity.Cart, error)
+	Create(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	GetById(ctx context.Context, cartID uuid.UUID) (entity.Cart, error)
 }
 
 var (
@@ -1,8 +1,12 @@
 package repository
 
-import "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
 
 type CategoryRepository interface {
 	// GetCategories возвращает все категории
-	Get() ([]*entity.Category, error)
+	Get(ctx context.Context) ([]*entity.Category, error)
 }
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type SafeDealRepository interface {
+	// BeginTransaction начинает транзакцию
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
+
+	// Add создает сделку по покупке в статусе awaiting_payment
+	// Возможные ошибки:
+	// ErrDealAlreadyExists - сделка по покупке уже создана
+	Add(ctx context.Context, deal *entity.SafeDeal) (*entity.SafeDeal, error)
+
+	// GetById возвращает сделку вместе с пользователем продавца
+	// Возможные ошибки:
+	// ErrDealNotFound - сделка не найдена
+	GetById(ctx context.Context, dealID uuid.UUID) (*entity.SafeDeal, error)
+
+	// GetByPurchaseId возвращает сделку по покупке
+	// Возможные ошибки:
+	// ErrDealNotFound - покупка оформлена без безопасной сделки
+	GetByPurchaseId(ctx context.Context, purchaseID uuid.UUID) (*entity.SafeDeal, error)
+
+	// UpdateStatus переводит сделку из статуса from в статус to и запоминает время блокировки
+	// средств и отправки товара. Возвращает false, если статус уже изменил параллельный запрос
+	UpdateStatus(ctx context.Context, tx pgx.Tx, dealID uuid.UUID, from, to entity.DealStatus) (bool, error)
+
+	// OpenDispute переводит сделку из статуса from в disputed и сохраняет, кто и почему открыл спор.
+	// Возвращает false, если статус уже изменил параллельный запрос
+	OpenDispute(ctx context.Context, dealID uuid.UUID, from entity.DealStatus, userID uuid.UUID, reason string) (bool, error)
+
+	// GetExpired возвращает не больше limit сделок, где товар не отправлен за shipTimeout после
+	// блокировки средств, получение не подтверждено за confirmTimeout после отправки или расчет
+	// не завершился за settleTimeout
+	GetExpired(ctx context.Context, shipTimeout, confirmTimeout, settleTimeout time.Duration, limit int) ([]*entity.SafeDeal, error)
+
+	// GetDisputed возвращает открытые споры, начиная с самых старых
+	GetDisputed(ctx context.Context, limit, offset int) ([]*entity.SafeDeal, error)
+}
+
+var (
+	ErrDealNotFound      = errors.New("сделка не найдена")
+	ErrDealAlreadyExists = errors.New("сделка по покупке уже создана")
+)
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DeliveryRepository interface {
+	// BeginTransaction начинает транзакцию
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
+
+	// GetOptions возвращает способы доставки объявления
+	GetOptions(ctx context.Context, advertID uuid.UUID) ([]*entity.DeliveryOption, error)
+
+	// GetOptionsByAdverts возвращает способы доставки всех переданных объявлений
+	GetOptionsByAdverts(ctx context.Context, advertIDs []uuid.UUID) ([]*entity.DeliveryOption, error)
+
+	// ReplaceOptions заменяет способы доставки объявления и отмечает, есть ли у него доставка
+	ReplaceOptions(ctx context.Context, tx pgx.Tx, advertID uuid.UUID, options []*entity.DeliveryOption) error
+
+	// AddShipments создает отправления по покупке. Отправления продавцов, для которых
+	// они уже созданы, пропускаются
+	AddShipments(ctx context.Context, tx pgx.Tx, shipments []*entity.Shipment) error
+
+	// GetShipmentById возвращает отправление вместе с пользователем продавца
+	// Возможные ошибки:
+	// ErrShipmentNotFound - отправление не найдено
+	GetShipmentById(ctx context.Context, shipmentID uuid.UUID) (*entity.Shipment, error)
+
+	// GetShipmentsByPurchaseId возвращает отправления по покупке
+	GetShipmentsByPurchaseId(ctx context.Context, purchaseID uuid.UUID) ([]*entity.Shipment, error)
+
+	// SetTracking сохраняет трек-номер отправления, ожидающего отправки, и переводит его в in_transit.
+	// Возвращает false, если отправление уже передано перевозчику
+	SetTracking(ctx context.Context, shipmentID uuid.UUID, carrier, trackingNumber string) (bool, error)
+
+	// GetTrackable возвращает не больше limit отправлений у перевозчика, начиная с давно не проверенных
+	GetTrackable(ctx context.Context, limit int) ([]*entity.Shipment, error)
+
+	// UpdateTracking переводит отправление из статуса from в статус to и запоминает время проверки.
+	// Возвращает false, если статус уже изменил параллельный запрос
+	UpdateTracking(ctx context.Context, tx pgx.Tx, shipmentID uuid.UUID, from, to entity.ShipmentStatus) (bool, error)
+
+	// AddEvents сохраняет историю отправления, уже сохраненные события пропускаются
+	AddEvents(ctx context.Context, tx pgx.Tx, events []entity.ShipmentEvent) error
+
+	// GetEvents возвращает историю отправления от старых событий к новым
+	GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]entity.ShipmentEvent, error)
+}
+
+var (
+	ErrShipmentNotFound = errors.New("отправление не найдено")
+)
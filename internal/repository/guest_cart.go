@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type GuestCart interface {
+	// AddAdvert добавляет объявление в гостевую корзину и продлевает ее жизнь. Повторное
+	// добавление не меняет корзину. Если в корзине уже limit объявлений, возвращает ErrGuestCartFull
+	AddAdvert(ctx context.Context, guestID, advertID uuid.UUID, limit int) error
+	// GetAdverts возвращает объявления гостевой корзины в порядке добавления
+	GetAdverts(ctx context.Context, guestID uuid.UUID) ([]uuid.UUID, error)
+	// DeleteAdvert удаляет объявление из гостевой корзины
+	DeleteAdvert(ctx context.Context, guestID, advertID uuid.UUID) error
+	// Delete удаляет гостевую корзину
+	Delete(ctx context.Context, guestID uuid.UUID) error
+}
+
+var (
+	ErrGuestCartFull           = errors.New("guest cart is full")
+	ErrGuestCartAdvertNotFound = errors.New("advert not found in guest cart")
+	ErrGuestCartFailed         = errors.New("failed to access guest cart")
+)
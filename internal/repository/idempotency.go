@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+)
+
+// IdempotencyRepository хранит ответы на запросы с заголовком Idempotency-Key.
+// Ключи разных пользователей не пересекаются
+type IdempotencyRepository interface {
+	// Reserve занимает ключ key пользователя userID под запрос с отпечатком fingerprint.
+	// Если ключ уже занят, возвращает сохраненную запись и false
+	Reserve(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*entity.IdempotencyRecord, bool, error)
+	// Complete сохраняет ответ на запрос, занявший ключ через Reserve
+	Complete(ctx context.Context, userID uuid.UUID, key string, record entity.IdempotencyRecord) error
+	// Release освобождает ключ, чтобы запрос, который не удалось обработать, можно было повторить
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+}
+
+var (
+	ErrIdempotencyReserveFailed  = errors.New("failed to reserve idempotency key")
+	ErrIdempotencyCompleteFailed = errors.New("failed to save idempotent response")
+	ErrIdempotencyReleaseFailed  = errors.New("failed to release idempotency key")
+)
@@ -0,0 +1,186 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/address.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// MockAddressRepository is a mock of AddressRepository interface.
+type MockAddressRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressRepositoryMockRecorder
+}
+
+// MockAddressRepositoryMockRecorder is the mock recorder for MockAddressRepository.
+type MockAddressRepositoryMockRecorder struct {
+	mock *MockAddressRepository
+}
+
+// NewMockAddressRepository creates a new mock instance.
+func NewMockAddressRepository(ctrl *gomock.Controller) *MockAddressRepository {
+	mock := &MockAddressRepository{ctrl: ctrl}
+	mock.recorder = &MockAddressRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressRepository) EXPECT() *MockAddressRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockAddressRepository) Add(ctx context.Context, tx pgx.Tx, address *entity.Address, limit int) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, tx, address, limit)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockAddressRepositoryMockRecorder) Add(ctx, tx, address, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAddressRepository)(nil).Add), ctx, tx, address, limit)
+}
+
+// BeginTransaction mocks base method.
+func (m *MockAddressRepository) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockAddressRepositoryMockRecorder) BeginTransaction(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockAddressRepository)(nil).BeginTransaction), ctx)
+}
+
+// ClearDefault mocks base method.
+func (m *MockAddressRepository) ClearDefault(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearDefault", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearDefault indicates an expected call of ClearDefault.
+func (mr *MockAddressRepositoryMockRecorder) ClearDefault(ctx, tx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearDefault", reflect.TypeOf((*MockAddressRepository)(nil).ClearDefault), ctx, tx, userID)
+}
+
+// Delete mocks base method.
+func (m *MockAddressRepository) Delete(ctx context.Context, tx pgx.Tx, addressID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, tx, addressID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAddressRepositoryMockRecorder) Delete(ctx, tx, addressID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAddressRepository)(nil).Delete), ctx, tx, addressID, userID)
+}
+
+// GetById mocks base method.
+func (m *MockAddressRepository) GetById(ctx context.Context, addressID, userID uuid.UUID) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, addressID, userID)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockAddressRepositoryMockRecorder) GetById(ctx, addressID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAddressRepository)(nil).GetById), ctx, addressID, userID)
+}
+
+// GetByUserId mocks base method.
+func (m *MockAddressRepository) GetByUserId(ctx context.Context, userID uuid.UUID) ([]*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userID)
+	ret0, _ := ret[0].([]*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserId indicates an expected call of GetByUserId.
+func (mr *MockAddressRepositoryMockRecorder) GetByUserId(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockAddressRepository)(nil).GetByUserId), ctx, userID)
+}
+
+// GetPurchaseSnapshots mocks base method.
+func (m *MockAddressRepository) GetPurchaseSnapshots(ctx context.Context, purchaseIDs []uuid.UUID) ([]*entity.PurchaseAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseSnapshots", ctx, purchaseIDs)
+	ret0, _ := ret[0].([]*entity.PurchaseAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseSnapshots indicates an expected call of GetPurchaseSnapshots.
+func (mr *MockAddressRepositoryMockRecorder) GetPurchaseSnapshots(ctx, purchaseIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseSnapshots", reflect.TypeOf((*MockAddressRepository)(nil).GetPurchaseSnapshots), ctx, purchaseIDs)
+}
+
+// SetDefault mocks base method.
+func (m *MockAddressRepository) SetDefault(ctx context.Context, tx pgx.Tx, addressID, userID uuid.UUID) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefault", ctx, tx, addressID, userID)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDefault indicates an expected call of SetDefault.
+func (mr *MockAddressRepositoryMockRecorder) SetDefault(ctx, tx, addressID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefault", reflect.TypeOf((*MockAddressRepository)(nil).SetDefault), ctx, tx, addressID, userID)
+}
+
+// SetNewestDefault mocks base method.
+func (m *MockAddressRepository) SetNewestDefault(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNewestDefault", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNewestDefault indicates an expected call of SetNewestDefault.
+func (mr *MockAddressRepositoryMockRecorder) SetNewestDefault(ctx, tx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNewestDefault", reflect.TypeOf((*MockAddressRepository)(nil).SetNewestDefault), ctx, tx, userID)
+}
+
+// Update mocks base method.
+func (m *MockAddressRepository) Update(ctx context.Context, tx pgx.Tx, address *entity.Address) (*entity.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, tx, address)
+	ret0, _ := ret[0].(*entity.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAddressRepositoryMockRecorder) Update(ctx, tx, address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAddressRepository)(nil).Update), ctx, tx, address)
+}
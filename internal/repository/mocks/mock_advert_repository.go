@@ -5,7 +5,9 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	gomock "github.com/golang/mock/gomock"
@@ -37,279 +39,456 @@ func (m *MockAdvertRepository) EXPECT() *MockAdvertRepositoryMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockAdvertRepository) Add(advert *entity.Advert) (*entity.Advert, error) {
+func (m *MockAdvertRepository) Add(ctx context.Context, advert *entity.Advert) (*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", advert)
+	ret := m.ctrl.Call(m, "Add", ctx, advert)
 	ret0, _ := ret[0].(*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockAdvertRepositoryMockRecorder) Add(advert interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) Add(ctx, advert interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAdvertRepository)(nil).Add), advert)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAdvertRepository)(nil).Add), ctx, advert)
 }
 
 // AddToSaved mocks base method.
-func (m *MockAdvertRepository) AddToSaved(advertId, userId uuid.UUID) error {
+func (m *MockAdvertRepository) AddToSaved(ctx context.Context, advertId, userId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddToSaved", advertId, userId)
+	ret := m.ctrl.Call(m, "AddToSaved", ctx, advertId, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddToSaved indicates an expected call of AddToSaved.
-func (mr *MockAdvertRepositoryMockRecorder) AddToSaved(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) AddToSaved(ctx, advertId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToSaved", reflect.TypeOf((*MockAdvertRepository)(nil).AddToSaved), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToSaved", reflect.TypeOf((*MockAdvertRepository)(nil).AddToSaved), ctx, advertId, userId)
 }
 
-// AddViewed mocks base method.
-func (m *MockAdvertRepository) AddViewed(userId, advertId uuid.UUID) error {
+// BeginTransaction mocks base method.
+func (m *MockAdvertRepository) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddViewed", userId, advertId)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "BeginTransaction", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// AddViewed indicates an expected call of AddViewed.
-func (mr *MockAdvertRepositoryMockRecorder) AddViewed(userId, advertId interface{}) *gomock.Call {
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockAdvertRepositoryMockRecorder) BeginTransaction(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViewed", reflect.TypeOf((*MockAdvertRepository)(nil).AddViewed), userId, advertId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockAdvertRepository)(nil).BeginTransaction), ctx)
 }
 
-// BeginTransaction mocks base method.
-func (m *MockAdvertRepository) BeginTransaction() (pgx.Tx, error) {
+// Bump mocks base method.
+func (m *MockAdvertRepository) Bump(ctx context.Context, advertId uuid.UUID, cooldown time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTransaction")
-	ret0, _ := ret[0].(pgx.Tx)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Bump", ctx, advertId, cooldown)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// BeginTransaction indicates an expected call of BeginTransaction.
-func (mr *MockAdvertRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+// Bump indicates an expected call of Bump.
+func (mr *MockAdvertRepositoryMockRecorder) Bump(ctx, advertId, cooldown interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockAdvertRepository)(nil).BeginTransaction))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bump", reflect.TypeOf((*MockAdvertRepository)(nil).Bump), ctx, advertId, cooldown)
 }
 
 // CheckIfExists mocks base method.
-func (m *MockAdvertRepository) CheckIfExists(advertId uuid.UUID) (bool, error) {
+func (m *MockAdvertRepository) CheckIfExists(ctx context.Context, advertId uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckIfExists", advertId)
+	ret := m.ctrl.Call(m, "CheckIfExists", ctx, advertId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckIfExists indicates an expected call of CheckIfExists.
-func (mr *MockAdvertRepositoryMockRecorder) CheckIfExists(advertId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) CheckIfExists(ctx, advertId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfExists", reflect.TypeOf((*MockAdvertRepository)(nil).CheckIfExists), advertId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfExists", reflect.TypeOf((*MockAdvertRepository)(nil).CheckIfExists), ctx, advertId)
 }
 
 // Count mocks base method.
-func (m *MockAdvertRepository) Count() (int, error) {
+func (m *MockAdvertRepository) Count(ctx context.Context) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count")
+	ret := m.ctrl.Call(m, "Count", ctx)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockAdvertRepositoryMockRecorder) Count() *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) Count(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockAdvertRepository)(nil).Count))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockAdvertRepository)(nil).Count), ctx)
 }
 
 // DeleteById mocks base method.
-func (m *MockAdvertRepository) DeleteById(advertId uuid.UUID) error {
+func (m *MockAdvertRepository) DeleteById(ctx context.Context, advertId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteById", advertId)
+	ret := m.ctrl.Call(m, "DeleteById", ctx, advertId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteById indicates an expected call of DeleteById.
-func (mr *MockAdvertRepositoryMockRecorder) DeleteById(advertId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) DeleteById(ctx, advertId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockAdvertRepository)(nil).DeleteById), advertId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockAdvertRepository)(nil).DeleteById), ctx, advertId)
 }
 
 // DeleteFromSaved mocks base method.
-func (m *MockAdvertRepository) DeleteFromSaved(userId, advertId uuid.UUID) error {
+func (m *MockAdvertRepository) DeleteFromSaved(ctx context.Context, userId, advertId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteFromSaved", userId, advertId)
+	ret := m.ctrl.Call(m, "DeleteFromSaved", ctx, userId, advertId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteFromSaved indicates an expected call of DeleteFromSaved.
-func (mr *MockAdvertRepositoryMockRecorder) DeleteFromSaved(userId, advertId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) DeleteFromSaved(ctx, userId, advertId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFromSaved", reflect.TypeOf((*MockAdvertRepository)(nil).DeleteFromSaved), ctx, userId, advertId)
+}
+
+// ExpireOutdated mocks base method.
+func (m *MockAdvertRepository) ExpireOutdated(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireOutdated", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireOutdated indicates an expected call of ExpireOutdated.
+func (mr *MockAdvertRepositoryMockRecorder) ExpireOutdated(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireOutdated", reflect.TypeOf((*MockAdvertRepository)(nil).ExpireOutdated), ctx)
+}
+
+// FlushViews mocks base method.
+func (m *MockAdvertRepository) FlushViews(ctx context.Context, batchId uuid.UUID, views []entity.AdvertView, counters map[uuid.UUID]uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushViews", ctx, batchId, views, counters)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushViews indicates an expected call of FlushViews.
+func (mr *MockAdvertRepositoryMockRecorder) FlushViews(ctx, batchId, views, counters interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFromSaved", reflect.TypeOf((*MockAdvertRepository)(nil).DeleteFromSaved), userId, advertId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushViews", reflect.TypeOf((*MockAdvertRepository)(nil).FlushViews), ctx, batchId, views, counters)
 }
 
 // Get mocks base method.
-func (m *MockAdvertRepository) Get(limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) Get(ctx context.Context, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", limit, offset, userId)
+	ret := m.ctrl.Call(m, "Get", ctx, limit, offset, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockAdvertRepositoryMockRecorder) Get(limit, offset, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) Get(ctx, limit, offset, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAdvertRepository)(nil).Get), limit, offset, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAdvertRepository)(nil).Get), ctx, limit, offset, userId)
 }
 
 // GetByCartId mocks base method.
-func (m *MockAdvertRepository) GetByCartId(cartId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) GetByCartId(ctx context.Context, cartId, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCartId", cartId, userId)
+	ret := m.ctrl.Call(m, "GetByCartId", ctx, cartId, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCartId indicates an expected call of GetByCartId.
-func (mr *MockAdvertRepositoryMockRecorder) GetByCartId(cartId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) GetByCartId(ctx, cartId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCartId", reflect.TypeOf((*MockAdvertRepository)(nil).GetByCartId), cartId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCartId", reflect.TypeOf((*MockAdvertRepository)(nil).GetByCartId), ctx, cartId, userId)
 }
 
 // GetByCategoryId mocks base method.
-func (m *MockAdvertRepository) GetByCategoryId(categoryId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) GetByCategoryId(ctx context.Context, categoryId, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCategoryId", categoryId, userId)
+	ret := m.ctrl.Call(m, "GetByCategoryId", ctx, categoryId, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCategoryId indicates an expected call of GetByCategoryId.
-func (mr *MockAdvertRepositoryMockRecorder) GetByCategoryId(categoryId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) GetByCategoryId(ctx, categoryId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategoryId", reflect.TypeOf((*MockAdvertRepository)(nil).GetByCategoryId), categoryId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategoryId", reflect.TypeOf((*MockAdvertRepository)(nil).GetByCategoryId), ctx, categoryId, userId)
 }
 
 // GetById mocks base method.
-func (m *MockAdvertRepository) GetById(advertId, userId uuid.UUID) (*entity.Advert, error) {
+func (m *MockAdvertRepository) GetById(ctx context.Context, advertId, userId uuid.UUID) (*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", advertId, userId)
+	ret := m.ctrl.Call(m, "GetById", ctx, advertId, userId)
 	ret0, _ := ret[0].(*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockAdvertRepositoryMockRecorder) GetById(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) GetById(ctx, advertId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAdvertRepository)(nil).GetById), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAdvertRepository)(nil).GetById), ctx, advertId, userId)
 }
 
 // GetBySellerId mocks base method.
-func (m *MockAdvertRepository) GetBySellerId(sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) GetBySellerId(ctx context.Context, sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBySellerId", sellerId, userId)
+	ret := m.ctrl.Call(m, "GetBySellerId", ctx, sellerId, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBySellerId indicates an expected call of GetBySellerId.
-func (mr *MockAdvertRepositoryMockRecorder) GetBySellerId(sellerId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) GetBySellerId(ctx, sellerId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySellerId", reflect.TypeOf((*MockAdvertRepository)(nil).GetBySellerId), sellerId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySellerId", reflect.TypeOf((*MockAdvertRepository)(nil).GetBySellerId), ctx, sellerId, userId)
 }
 
 // GetByUserId mocks base method.
-func (m *MockAdvertRepository) GetByUserId(sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) GetByUserId(ctx context.Context, sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", sellerId, userId)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, sellerId, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockAdvertRepositoryMockRecorder) GetByUserId(sellerId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) GetByUserId(ctx, sellerId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockAdvertRepository)(nil).GetByUserId), ctx, sellerId, userId)
+}
+
+// GetExpiringUnreminded mocks base method.
+func (m *MockAdvertRepository) GetExpiringUnreminded(ctx context.Context, before time.Duration) ([]*entity.Advert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiringUnreminded", ctx, before)
+	ret0, _ := ret[0].([]*entity.Advert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiringUnreminded indicates an expected call of GetExpiringUnreminded.
+func (mr *MockAdvertRepositoryMockRecorder) GetExpiringUnreminded(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiringUnreminded", reflect.TypeOf((*MockAdvertRepository)(nil).GetExpiringUnreminded), ctx, before)
+}
+
+// GetForExport mocks base method.
+func (m *MockAdvertRepository) GetForExport(ctx context.Context, sellerId uuid.UUID) ([]*entity.Advert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForExport", ctx, sellerId)
+	ret0, _ := ret[0].([]*entity.Advert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForExport indicates an expected call of GetForExport.
+func (mr *MockAdvertRepositoryMockRecorder) GetForExport(ctx, sellerId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForExport", reflect.TypeOf((*MockAdvertRepository)(nil).GetForExport), ctx, sellerId)
+}
+
+// GetSaleState mocks base method.
+func (m *MockAdvertRepository) GetSaleState(ctx context.Context, advertId uuid.UUID) (*entity.AdvertSaleState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSaleState", ctx, advertId)
+	ret0, _ := ret[0].(*entity.AdvertSaleState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSaleState indicates an expected call of GetSaleState.
+func (mr *MockAdvertRepositoryMockRecorder) GetSaleState(ctx, advertId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockAdvertRepository)(nil).GetByUserId), sellerId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSaleState", reflect.TypeOf((*MockAdvertRepository)(nil).GetSaleState), ctx, advertId)
 }
 
 // GetSavedByUserId mocks base method.
-func (m *MockAdvertRepository) GetSavedByUserId(userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) GetSavedByUserId(ctx context.Context, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSavedByUserId", userId)
+	ret := m.ctrl.Call(m, "GetSavedByUserId", ctx, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSavedByUserId indicates an expected call of GetSavedByUserId.
-func (mr *MockAdvertRepositoryMockRecorder) GetSavedByUserId(userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) GetSavedByUserId(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedByUserId", reflect.TypeOf((*MockAdvertRepository)(nil).GetSavedByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedByUserId", reflect.TypeOf((*MockAdvertRepository)(nil).GetSavedByUserId), ctx, userId)
+}
+
+// LockByCartId mocks base method.
+func (m *MockAdvertRepository) LockByCartId(ctx context.Context, tx pgx.Tx, cartId uuid.UUID) ([]*entity.AdvertSaleState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockByCartId", ctx, tx, cartId)
+	ret0, _ := ret[0].([]*entity.AdvertSaleState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockByCartId indicates an expected call of LockByCartId.
+func (mr *MockAdvertRepositoryMockRecorder) LockByCartId(ctx, tx, cartId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockByCartId", reflect.TypeOf((*MockAdvertRepository)(nil).LockByCartId), ctx, tx, cartId)
+}
+
+// MarkReminded mocks base method.
+func (m *MockAdvertRepository) MarkReminded(ctx context.Context, advertId uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkReminded", ctx, advertId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkReminded indicates an expected call of MarkReminded.
+func (mr *MockAdvertRepositoryMockRecorder) MarkReminded(ctx, advertId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReminded", reflect.TypeOf((*MockAdvertRepository)(nil).MarkReminded), ctx, advertId)
+}
+
+// Publish mocks base method.
+func (m *MockAdvertRepository) Publish(ctx context.Context, advertId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, advertId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockAdvertRepositoryMockRecorder) Publish(ctx, advertId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockAdvertRepository)(nil).Publish), ctx, advertId)
+}
+
+// PublishScheduled mocks base method.
+func (m *MockAdvertRepository) PublishScheduled(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockAdvertRepositoryMockRecorder) PublishScheduled(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockAdvertRepository)(nil).PublishScheduled), ctx)
+}
+
+// Renew mocks base method.
+func (m *MockAdvertRepository) Renew(ctx context.Context, advertId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, advertId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockAdvertRepositoryMockRecorder) Renew(ctx, advertId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockAdvertRepository)(nil).Renew), ctx, advertId)
+}
+
+// Schedule mocks base method.
+func (m *MockAdvertRepository) Schedule(ctx context.Context, advertId uuid.UUID, publishAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Schedule", ctx, advertId, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockAdvertRepositoryMockRecorder) Schedule(ctx, advertId, publishAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockAdvertRepository)(nil).Schedule), ctx, advertId, publishAt)
 }
 
 // Search mocks base method.
-func (m *MockAdvertRepository) Search(query string, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
+func (m *MockAdvertRepository) Search(ctx context.Context, query string, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", query, limit, offset, userId)
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit, offset, userId)
 	ret0, _ := ret[0].([]*entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockAdvertRepositoryMockRecorder) Search(query, limit, offset, userId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) Search(ctx, query, limit, offset, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAdvertRepository)(nil).Search), query, limit, offset, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAdvertRepository)(nil).Search), ctx, query, limit, offset, userId)
 }
 
 // Update mocks base method.
-func (m *MockAdvertRepository) Update(advert *entity.Advert) error {
+func (m *MockAdvertRepository) Update(ctx context.Context, advert *entity.Advert, from entity.AdvertStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", advert)
+	ret := m.ctrl.Call(m, "Update", ctx, advert, from)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAdvertRepositoryMockRecorder) Update(advert interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) Update(ctx, advert, from interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdvertRepository)(nil).Update), advert)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdvertRepository)(nil).Update), ctx, advert, from)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockAdvertRepository) UpdateStatus(tx pgx.Tx, advertId uuid.UUID, status entity.AdvertStatus) error {
+func (m *MockAdvertRepository) UpdateStatus(ctx context.Context, tx pgx.Tx, advertId uuid.UUID, status entity.AdvertStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", tx, advertId, status)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, tx, advertId, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockAdvertRepositoryMockRecorder) UpdateStatus(tx, advertId, status interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) UpdateStatus(ctx, tx, advertId, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAdvertRepository)(nil).UpdateStatus), tx, advertId, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAdvertRepository)(nil).UpdateStatus), ctx, tx, advertId, status)
 }
 
 // UploadImage mocks base method.
-func (m *MockAdvertRepository) UploadImage(advertId, imageId uuid.UUID) error {
+func (m *MockAdvertRepository) UploadImage(ctx context.Context, advertId, imageId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadImage", advertId, imageId)
+	ret := m.ctrl.Call(m, "UploadImage", ctx, advertId, imageId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UploadImage indicates an expected call of UploadImage.
-func (mr *MockAdvertRepositoryMockRecorder) UploadImage(advertId, imageId interface{}) *gomock.Call {
+func (mr *MockAdvertRepositoryMockRecorder) UploadImage(ctx, advertId, imageId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockAdvertRepository)(nil).UploadImage), ctx, advertId, imageId)
+}
+
+// UpsertBySKU mocks base method.
+func (m *MockAdvertRepository) UpsertBySKU(ctx context.Context, advert *entity.Advert) (*entity.Advert, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertBySKU", ctx, advert)
+	ret0, _ := ret[0].(*entity.Advert)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpsertBySKU indicates an expected call of UpsertBySKU.
+func (mr *MockAdvertRepositoryMockRecorder) UpsertBySKU(ctx, advert interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockAdvertRepository)(nil).UploadImage), advertId, imageId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBySKU", reflect.TypeOf((*MockAdvertRepository)(nil).UpsertBySKU), ctx, advert)
 }
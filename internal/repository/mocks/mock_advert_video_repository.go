@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/advert_video.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAdvertVideoRepository is a mock of AdvertVideoRepository interface.
+type MockAdvertVideoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdvertVideoRepositoryMockRecorder
+}
+
+// MockAdvertVideoRepositoryMockRecorder is the mock recorder for MockAdvertVideoRepository.
+type MockAdvertVideoRepositoryMockRecorder struct {
+	mock *MockAdvertVideoRepository
+}
+
+// NewMockAdvertVideoRepository creates a new mock instance.
+func NewMockAdvertVideoRepository(ctrl *gomock.Controller) *MockAdvertVideoRepository {
+	mock := &MockAdvertVideoRepository{ctrl: ctrl}
+	mock.recorder = &MockAdvertVideoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdvertVideoRepository) EXPECT() *MockAdvertVideoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockAdvertVideoRepository) Add(ctx context.Context, advertID, videoID uuid.UUID, limit int) (*entity.AdvertVideo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, advertID, videoID, limit)
+	ret0, _ := ret[0].(*entity.AdvertVideo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockAdvertVideoRepositoryMockRecorder) Add(ctx, advertID, videoID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAdvertVideoRepository)(nil).Add), ctx, advertID, videoID, limit)
+}
+
+// Delete mocks base method.
+func (m *MockAdvertVideoRepository) Delete(ctx context.Context, advertID, videoID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, advertID, videoID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAdvertVideoRepositoryMockRecorder) Delete(ctx, advertID, videoID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAdvertVideoRepository)(nil).Delete), ctx, advertID, videoID)
+}
+
+// GetByAdvertID mocks base method.
+func (m *MockAdvertVideoRepository) GetByAdvertID(ctx context.Context, advertID uuid.UUID) ([]*entity.AdvertVideo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAdvertID", ctx, advertID)
+	ret0, _ := ret[0].([]*entity.AdvertVideo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAdvertID indicates an expected call of GetByAdvertID.
+func (mr *MockAdvertVideoRepositoryMockRecorder) GetByAdvertID(ctx, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAdvertID", reflect.TypeOf((*MockAdvertVideoRepository)(nil).GetByAdvertID), ctx, advertID)
+}
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/analytics.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAnalyticsRepository is a mock of AnalyticsRepository interface.
+type MockAnalyticsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsRepositoryMockRecorder
+}
+
+// MockAnalyticsRepositoryMockRecorder is the mock recorder for MockAnalyticsRepository.
+type MockAnalyticsRepositoryMockRecorder struct {
+	mock *MockAnalyticsRepository
+}
+
+// NewMockAnalyticsRepository creates a new mock instance.
+func NewMockAnalyticsRepository(ctrl *gomock.Controller) *MockAnalyticsRepository {
+	mock := &MockAnalyticsRepository{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsRepository) EXPECT() *MockAnalyticsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAdvertDailyStats mocks base method.
+func (m *MockAnalyticsRepository) GetAdvertDailyStats(ctx context.Context, advertId uuid.UUID, from, to time.Time) ([]*entity.AdvertDailyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdvertDailyStats", ctx, advertId, from, to)
+	ret0, _ := ret[0].([]*entity.AdvertDailyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdvertDailyStats indicates an expected call of GetAdvertDailyStats.
+func (mr *MockAnalyticsRepositoryMockRecorder) GetAdvertDailyStats(ctx, advertId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertDailyStats", reflect.TypeOf((*MockAnalyticsRepository)(nil).GetAdvertDailyStats), ctx, advertId, from, to)
+}
+
+// GetCategoryDailyStats mocks base method.
+func (m *MockAnalyticsRepository) GetCategoryDailyStats(ctx context.Context, categoryId uuid.UUID, from, to time.Time) ([]*entity.CategoryDailyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryDailyStats", ctx, categoryId, from, to)
+	ret0, _ := ret[0].([]*entity.CategoryDailyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryDailyStats indicates an expected call of GetCategoryDailyStats.
+func (mr *MockAnalyticsRepositoryMockRecorder) GetCategoryDailyStats(ctx, categoryId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryDailyStats", reflect.TypeOf((*MockAnalyticsRepository)(nil).GetCategoryDailyStats), ctx, categoryId, from, to)
+}
+
+// GetSellerTotals mocks base method.
+func (m *MockAnalyticsRepository) GetSellerTotals(ctx context.Context, sellerId uuid.UUID, from, to time.Time) ([]*entity.AdvertStatsTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerTotals", ctx, sellerId, from, to)
+	ret0, _ := ret[0].([]*entity.AdvertStatsTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerTotals indicates an expected call of GetSellerTotals.
+func (mr *MockAnalyticsRepositoryMockRecorder) GetSellerTotals(ctx, sellerId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerTotals", reflect.TypeOf((*MockAnalyticsRepository)(nil).GetSellerTotals), ctx, sellerId, from, to)
+}
+
+// RollupDaily mocks base method.
+func (m *MockAnalyticsRepository) RollupDaily(ctx context.Context, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollupDaily", ctx, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollupDaily indicates an expected call of RollupDaily.
+func (mr *MockAnalyticsRepositoryMockRecorder) RollupDaily(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollupDaily", reflect.TypeOf((*MockAnalyticsRepository)(nil).RollupDaily), ctx, since)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/carrier.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCarrierProvider is a mock of CarrierProvider interface.
+type MockCarrierProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockCarrierProviderMockRecorder
+}
+
+// MockCarrierProviderMockRecorder is the mock recorder for MockCarrierProvider.
+type MockCarrierProviderMockRecorder struct {
+	mock *MockCarrierProvider
+}
+
+// NewMockCarrierProvider creates a new mock instance.
+func NewMockCarrierProvider(ctrl *gomock.Controller) *MockCarrierProvider {
+	mock := &MockCarrierProvider{ctrl: ctrl}
+	mock.recorder = &MockCarrierProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCarrierProvider) EXPECT() *MockCarrierProviderMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockCarrierProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockCarrierProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockCarrierProvider)(nil).Name))
+}
+
+// Register mocks base method.
+func (m *MockCarrierProvider) Register(ctx context.Context, trackingNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, trackingNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockCarrierProviderMockRecorder) Register(ctx, trackingNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockCarrierProvider)(nil).Register), ctx, trackingNumber)
+}
+
+// Track mocks base method.
+func (m *MockCarrierProvider) Track(ctx context.Context, trackingNumber string) (*entity.TrackingInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Track", ctx, trackingNumber)
+	ret0, _ := ret[0].(*entity.TrackingInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockCarrierProviderMockRecorder) Track(ctx, trackingNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockCarrierProvider)(nil).Track), ctx, trackingNumber)
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -37,103 +38,103 @@ func (m *MockCart) EXPECT() *MockCartMockRecorder {
 }
 
 // AddAdvert mocks base method.
-func (m *MockCart) AddAdvert(cartID, AdvertID uuid.UUID) error {
+func (m *MockCart) AddAdvert(ctx context.Context, cartID, AdvertID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddAdvert", cartID, AdvertID)
+	ret := m.ctrl.Call(m, "AddAdvert", ctx, cartID, AdvertID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddAdvert indicates an expected call of AddAdvert.
-func (mr *MockCartMockRecorder) AddAdvert(cartID, AdvertID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) AddAdvert(ctx, cartID, AdvertID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdvert", reflect.TypeOf((*MockCart)(nil).AddAdvert), cartID, AdvertID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdvert", reflect.TypeOf((*MockCart)(nil).AddAdvert), ctx, cartID, AdvertID)
 }
 
 // Create mocks base method.
-func (m *MockCart) Create(userID uuid.UUID) (uuid.UUID, error) {
+func (m *MockCart) Create(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID)
+	ret := m.ctrl.Call(m, "Create", ctx, userID)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockCartMockRecorder) Create(userID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) Create(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCart)(nil).Create), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCart)(nil).Create), ctx, userID)
 }
 
 // DeleteAdvert mocks base method.
-func (m *MockCart) DeleteAdvert(cartID, AdvertID uuid.UUID) error {
+func (m *MockCart) DeleteAdvert(ctx context.Context, cartID, AdvertID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAdvert", cartID, AdvertID)
+	ret := m.ctrl.Call(m, "DeleteAdvert", ctx, cartID, AdvertID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAdvert indicates an expected call of DeleteAdvert.
-func (mr *MockCartMockRecorder) DeleteAdvert(cartID, AdvertID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) DeleteAdvert(ctx, cartID, AdvertID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdvert", reflect.TypeOf((*MockCart)(nil).DeleteAdvert), cartID, AdvertID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdvert", reflect.TypeOf((*MockCart)(nil).DeleteAdvert), ctx, cartID, AdvertID)
 }
 
 // GetAdvertsByCartId mocks base method.
-func (m *MockCart) GetAdvertsByCartId(cartID uuid.UUID) ([]entity.Advert, error) {
+func (m *MockCart) GetAdvertsByCartId(ctx context.Context, cartID uuid.UUID) ([]entity.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAdvertsByCartId", cartID)
+	ret := m.ctrl.Call(m, "GetAdvertsByCartId", ctx, cartID)
 	ret0, _ := ret[0].([]entity.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAdvertsByCartId indicates an expected call of GetAdvertsByCartId.
-func (mr *MockCartMockRecorder) GetAdvertsByCartId(cartID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) GetAdvertsByCartId(ctx, cartID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertsByCartId", reflect.TypeOf((*MockCart)(nil).GetAdvertsByCartId), cartID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertsByCartId", reflect.TypeOf((*MockCart)(nil).GetAdvertsByCartId), ctx, cartID)
 }
 
 // GetById mocks base method.
-func (m *MockCart) GetById(cartID uuid.UUID) (entity.Cart, error) {
+func (m *MockCart) GetById(ctx context.Context, cartID uuid.UUID) (entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", cartID)
+	ret := m.ctrl.Call(m, "GetById", ctx, cartID)
 	ret0, _ := ret[0].(entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockCartMockRecorder) GetById(cartID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) GetById(ctx, cartID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCart)(nil).GetById), cartID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCart)(nil).GetById), ctx, cartID)
 }
 
 // GetByUserId mocks base method.
-func (m *MockCart) GetByUserId(userID uuid.UUID) (entity.Cart, error) {
+func (m *MockCart) GetByUserId(ctx context.Context, userID uuid.UUID) (entity.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", userID)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userID)
 	ret0, _ := ret[0].(entity.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockCartMockRecorder) GetByUserId(userID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) GetByUserId(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockCart)(nil).GetByUserId), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockCart)(nil).GetByUserId), ctx, userID)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockCart) UpdateStatus(tx pgx.Tx, cartID uuid.UUID, status entity.CartStatus) error {
+func (m *MockCart) UpdateStatus(ctx context.Context, tx pgx.Tx, cartID uuid.UUID, status entity.CartStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", tx, cartID, status)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, tx, cartID, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockCartMockRecorder) UpdateStatus(tx, cartID, status interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) UpdateStatus(ctx, tx, cartID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockCart)(nil).UpdateStatus), tx, cartID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockCart)(nil).UpdateStatus), ctx, tx, cartID, status)
 }
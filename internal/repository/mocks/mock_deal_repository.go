@@ -0,0 +1,159 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/deal.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// MockSafeDealRepository is a mock of SafeDealRepository interface.
+type MockSafeDealRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSafeDealRepositoryMockRecorder
+}
+
+// MockSafeDealRepositoryMockRecorder is the mock recorder for MockSafeDealRepository.
+type MockSafeDealRepositoryMockRecorder struct {
+	mock *MockSafeDealRepository
+}
+
+// NewMockSafeDealRepository creates a new mock instance.
+func NewMockSafeDealRepository(ctrl *gomock.Controller) *MockSafeDealRepository {
+	mock := &MockSafeDealRepository{ctrl: ctrl}
+	mock.recorder = &MockSafeDealRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSafeDealRepository) EXPECT() *MockSafeDealRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockSafeDealRepository) Add(ctx context.Context, deal *entity.SafeDeal) (*entity.SafeDeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, deal)
+	ret0, _ := ret[0].(*entity.SafeDeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockSafeDealRepositoryMockRecorder) Add(ctx, deal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSafeDealRepository)(nil).Add), ctx, deal)
+}
+
+// BeginTransaction mocks base method.
+func (m *MockSafeDealRepository) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockSafeDealRepositoryMockRecorder) BeginTransaction(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockSafeDealRepository)(nil).BeginTransaction), ctx)
+}
+
+// GetById mocks base method.
+func (m *MockSafeDealRepository) GetById(ctx context.Context, dealID uuid.UUID) (*entity.SafeDeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, dealID)
+	ret0, _ := ret[0].(*entity.SafeDeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockSafeDealRepositoryMockRecorder) GetById(ctx, dealID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockSafeDealRepository)(nil).GetById), ctx, dealID)
+}
+
+// GetByPurchaseId mocks base method.
+func (m *MockSafeDealRepository) GetByPurchaseId(ctx context.Context, purchaseID uuid.UUID) (*entity.SafeDeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPurchaseId", ctx, purchaseID)
+	ret0, _ := ret[0].(*entity.SafeDeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPurchaseId indicates an expected call of GetByPurchaseId.
+func (mr *MockSafeDealRepositoryMockRecorder) GetByPurchaseId(ctx, purchaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPurchaseId", reflect.TypeOf((*MockSafeDealRepository)(nil).GetByPurchaseId), ctx, purchaseID)
+}
+
+// GetDisputed mocks base method.
+func (m *MockSafeDealRepository) GetDisputed(ctx context.Context, limit, offset int) ([]*entity.SafeDeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputed", ctx, limit, offset)
+	ret0, _ := ret[0].([]*entity.SafeDeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputed indicates an expected call of GetDisputed.
+func (mr *MockSafeDealRepositoryMockRecorder) GetDisputed(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputed", reflect.TypeOf((*MockSafeDealRepository)(nil).GetDisputed), ctx, limit, offset)
+}
+
+// GetExpired mocks base method.
+func (m *MockSafeDealRepository) GetExpired(ctx context.Context, shipTimeout, confirmTimeout, settleTimeout time.Duration, limit int) ([]*entity.SafeDeal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpired", ctx, shipTimeout, confirmTimeout, settleTimeout, limit)
+	ret0, _ := ret[0].([]*entity.SafeDeal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpired indicates an expected call of GetExpired.
+func (mr *MockSafeDealRepositoryMockRecorder) GetExpired(ctx, shipTimeout, confirmTimeout, settleTimeout, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpired", reflect.TypeOf((*MockSafeDealRepository)(nil).GetExpired), ctx, shipTimeout, confirmTimeout, settleTimeout, limit)
+}
+
+// OpenDispute mocks base method.
+func (m *MockSafeDealRepository) OpenDispute(ctx context.Context, dealID uuid.UUID, from entity.DealStatus, userID uuid.UUID, reason string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDispute", ctx, dealID, from, userID, reason)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenDispute indicates an expected call of OpenDispute.
+func (mr *MockSafeDealRepositoryMockRecorder) OpenDispute(ctx, dealID, from, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDispute", reflect.TypeOf((*MockSafeDealRepository)(nil).OpenDispute), ctx, dealID, from, userID, reason)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockSafeDealRepository) UpdateStatus(ctx context.Context, tx pgx.Tx, dealID uuid.UUID, from, to entity.DealStatus) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, tx, dealID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockSafeDealRepositoryMockRecorder) UpdateStatus(ctx, tx, dealID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockSafeDealRepository)(nil).UpdateStatus), ctx, tx, dealID, from, to)
+}
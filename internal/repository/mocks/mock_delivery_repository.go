@@ -0,0 +1,215 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/delivery.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// MockDeliveryRepository is a mock of DeliveryRepository interface.
+type MockDeliveryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryRepositoryMockRecorder
+}
+
+// MockDeliveryRepositoryMockRecorder is the mock recorder for MockDeliveryRepository.
+type MockDeliveryRepositoryMockRecorder struct {
+	mock *MockDeliveryRepository
+}
+
+// NewMockDeliveryRepository creates a new mock instance.
+func NewMockDeliveryRepository(ctrl *gomock.Controller) *MockDeliveryRepository {
+	mock := &MockDeliveryRepository{ctrl: ctrl}
+	mock.recorder = &MockDeliveryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryRepository) EXPECT() *MockDeliveryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddEvents mocks base method.
+func (m *MockDeliveryRepository) AddEvents(ctx context.Context, tx pgx.Tx, events []entity.ShipmentEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvents", ctx, tx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvents indicates an expected call of AddEvents.
+func (mr *MockDeliveryRepositoryMockRecorder) AddEvents(ctx, tx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvents", reflect.TypeOf((*MockDeliveryRepository)(nil).AddEvents), ctx, tx, events)
+}
+
+// AddShipments mocks base method.
+func (m *MockDeliveryRepository) AddShipments(ctx context.Context, tx pgx.Tx, shipments []*entity.Shipment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddShipments", ctx, tx, shipments)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddShipments indicates an expected call of AddShipments.
+func (mr *MockDeliveryRepositoryMockRecorder) AddShipments(ctx, tx, shipments interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddShipments", reflect.TypeOf((*MockDeliveryRepository)(nil).AddShipments), ctx, tx, shipments)
+}
+
+// BeginTransaction mocks base method.
+func (m *MockDeliveryRepository) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction", ctx)
+	ret0, _ := ret[0].(pgx.Tx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockDeliveryRepositoryMockRecorder) BeginTransaction(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockDeliveryRepository)(nil).BeginTransaction), ctx)
+}
+
+// GetEvents mocks base method.
+func (m *MockDeliveryRepository) GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]entity.ShipmentEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", ctx, shipmentID)
+	ret0, _ := ret[0].([]entity.ShipmentEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockDeliveryRepositoryMockRecorder) GetEvents(ctx, shipmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockDeliveryRepository)(nil).GetEvents), ctx, shipmentID)
+}
+
+// GetOptions mocks base method.
+func (m *MockDeliveryRepository) GetOptions(ctx context.Context, advertID uuid.UUID) ([]*entity.DeliveryOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOptions", ctx, advertID)
+	ret0, _ := ret[0].([]*entity.DeliveryOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOptions indicates an expected call of GetOptions.
+func (mr *MockDeliveryRepositoryMockRecorder) GetOptions(ctx, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOptions", reflect.TypeOf((*MockDeliveryRepository)(nil).GetOptions), ctx, advertID)
+}
+
+// GetOptionsByAdverts mocks base method.
+func (m *MockDeliveryRepository) GetOptionsByAdverts(ctx context.Context, advertIDs []uuid.UUID) ([]*entity.DeliveryOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOptionsByAdverts", ctx, advertIDs)
+	ret0, _ := ret[0].([]*entity.DeliveryOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOptionsByAdverts indicates an expected call of GetOptionsByAdverts.
+func (mr *MockDeliveryRepositoryMockRecorder) GetOptionsByAdverts(ctx, advertIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOptionsByAdverts", reflect.TypeOf((*MockDeliveryRepository)(nil).GetOptionsByAdverts), ctx, advertIDs)
+}
+
+// GetShipmentById mocks base method.
+func (m *MockDeliveryRepository) GetShipmentById(ctx context.Context, shipmentID uuid.UUID) (*entity.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipmentById", ctx, shipmentID)
+	ret0, _ := ret[0].(*entity.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipmentById indicates an expected call of GetShipmentById.
+func (mr *MockDeliveryRepositoryMockRecorder) GetShipmentById(ctx, shipmentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentById", reflect.TypeOf((*MockDeliveryRepository)(nil).GetShipmentById), ctx, shipmentID)
+}
+
+// GetShipmentsByPurchaseId mocks base method.
+func (m *MockDeliveryRepository) GetShipmentsByPurchaseId(ctx context.Context, purchaseID uuid.UUID) ([]*entity.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipmentsByPurchaseId", ctx, purchaseID)
+	ret0, _ := ret[0].([]*entity.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipmentsByPurchaseId indicates an expected call of GetShipmentsByPurchaseId.
+func (mr *MockDeliveryRepositoryMockRecorder) GetShipmentsByPurchaseId(ctx, purchaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentsByPurchaseId", reflect.TypeOf((*MockDeliveryRepository)(nil).GetShipmentsByPurchaseId), ctx, purchaseID)
+}
+
+// GetTrackable mocks base method.
+func (m *MockDeliveryRepository) GetTrackable(ctx context.Context, limit int) ([]*entity.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrackable", ctx, limit)
+	ret0, _ := ret[0].([]*entity.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrackable indicates an expected call of GetTrackable.
+func (mr *MockDeliveryRepositoryMockRecorder) GetTrackable(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrackable", reflect.TypeOf((*MockDeliveryRepository)(nil).GetTrackable), ctx, limit)
+}
+
+// ReplaceOptions mocks base method.
+func (m *MockDeliveryRepository) ReplaceOptions(ctx context.Context, tx pgx.Tx, advertID uuid.UUID, options []*entity.DeliveryOption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceOptions", ctx, tx, advertID, options)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceOptions indicates an expected call of ReplaceOptions.
+func (mr *MockDeliveryRepositoryMockRecorder) ReplaceOptions(ctx, tx, advertID, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceOptions", reflect.TypeOf((*MockDeliveryRepository)(nil).ReplaceOptions), ctx, tx, advertID, options)
+}
+
+// SetTracking mocks base method.
+func (m *MockDeliveryRepository) SetTracking(ctx context.Context, shipmentID uuid.UUID, carrier, trackingNumber string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTracking", ctx, shipmentID, carrier, trackingNumber)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTracking indicates an expected call of SetTracking.
+func (mr *MockDeliveryRepositoryMockRecorder) SetTracking(ctx, shipmentID, carrier, trackingNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTracking", reflect.TypeOf((*MockDeliveryRepository)(nil).SetTracking), ctx, shipmentID, carrier, trackingNumber)
+}
+
+// UpdateTracking mocks base method.
+func (m *MockDeliveryRepository) UpdateTracking(ctx context.Context, tx pgx.Tx, shipmentID uuid.UUID, from, to entity.ShipmentStatus) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTracking", ctx, tx, shipmentID, from, to)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTracking indicates an expected call of UpdateTracking.
+func (mr *MockDeliveryRepositoryMockRecorder) UpdateTracking(ctx, tx, shipmentID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTracking", reflect.TypeOf((*MockDeliveryRepository)(nil).UpdateTracking), ctx, tx, shipmentID, from, to)
+}
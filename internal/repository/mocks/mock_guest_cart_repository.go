@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/guest_cart.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockGuestCart is a mock of GuestCart interface.
+type MockGuestCart struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestCartMockRecorder
+}
+
+// MockGuestCartMockRecorder is the mock recorder for MockGuestCart.
+type MockGuestCartMockRecorder struct {
+	mock *MockGuestCart
+}
+
+// NewMockGuestCart creates a new mock instance.
+func NewMockGuestCart(ctrl *gomock.Controller) *MockGuestCart {
+	mock := &MockGuestCart{ctrl: ctrl}
+	mock.recorder = &MockGuestCartMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuestCart) EXPECT() *MockGuestCartMockRecorder {
+	return m.recorder
+}
+
+// AddAdvert mocks base method.
+func (m *MockGuestCart) AddAdvert(ctx context.Context, guestID, advertID uuid.UUID, limit int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdvert", ctx, guestID, advertID, limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAdvert indicates an expected call of AddAdvert.
+func (mr *MockGuestCartMockRecorder) AddAdvert(ctx, guestID, advertID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdvert", reflect.TypeOf((*MockGuestCart)(nil).AddAdvert), ctx, guestID, advertID, limit)
+}
+
+// Delete mocks base method.
+func (m *MockGuestCart) Delete(ctx context.Context, guestID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, guestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockGuestCartMockRecorder) Delete(ctx, guestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGuestCart)(nil).Delete), ctx, guestID)
+}
+
+// DeleteAdvert mocks base method.
+func (m *MockGuestCart) DeleteAdvert(ctx context.Context, guestID, advertID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAdvert", ctx, guestID, advertID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAdvert indicates an expected call of DeleteAdvert.
+func (mr *MockGuestCartMockRecorder) DeleteAdvert(ctx, guestID, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdvert", reflect.TypeOf((*MockGuestCart)(nil).DeleteAdvert), ctx, guestID, advertID)
+}
+
+// GetAdverts mocks base method.
+func (m *MockGuestCart) GetAdverts(ctx context.Context, guestID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdverts", ctx, guestID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdverts indicates an expected call of GetAdverts.
+func (mr *MockGuestCartMockRecorder) GetAdverts(ctx, guestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdverts", reflect.TypeOf((*MockGuestCart)(nil).GetAdverts), ctx, guestID)
+}
@@ -0,0 +1,177 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/payment_gateway.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentGateway is a mock of PaymentGateway interface.
+type MockPaymentGateway struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentGatewayMockRecorder
+}
+
+// MockPaymentGatewayMockRecorder is the mock recorder for MockPaymentGateway.
+type MockPaymentGatewayMockRecorder struct {
+	mock *MockPaymentGateway
+}
+
+// NewMockPaymentGateway creates a new mock instance.
+func NewMockPaymentGateway(ctrl *gomock.Controller) *MockPaymentGateway {
+	mock := &MockPaymentGateway{ctrl: ctrl}
+	mock.recorder = &MockPaymentGatewayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentGateway) EXPECT() *MockPaymentGatewayMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockPaymentGateway) Cancel(ctx context.Context, externalID string) (*entity.GatewayPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, externalID)
+	ret0, _ := ret[0].(*entity.GatewayPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockPaymentGatewayMockRecorder) Cancel(ctx, externalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockPaymentGateway)(nil).Cancel), ctx, externalID)
+}
+
+// Capture mocks base method.
+func (m *MockPaymentGateway) Capture(ctx context.Context, externalID string, amount uint, currency string) (*entity.GatewayPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, externalID, amount, currency)
+	ret0, _ := ret[0].(*entity.GatewayPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockPaymentGatewayMockRecorder) Capture(ctx, externalID, amount, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockPaymentGateway)(nil).Capture), ctx, externalID, amount, currency)
+}
+
+// CreatePayment mocks base method.
+func (m *MockPaymentGateway) CreatePayment(ctx context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePayment", ctx, req)
+	ret0, _ := ret[0].(*entity.GatewayPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockPaymentGatewayMockRecorder) CreatePayment(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPaymentGateway)(nil).CreatePayment), ctx, req)
+}
+
+// Name mocks base method.
+func (m *MockPaymentGateway) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockPaymentGatewayMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockPaymentGateway)(nil).Name))
+}
+
+// ParseWebhook mocks base method.
+func (m *MockPaymentGateway) ParseWebhook(payload []byte, signature string) (*entity.PaymentEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ParseWebhook", payload, signature)
+	ret0, _ := ret[0].(*entity.PaymentEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ParseWebhook indicates an expected call of ParseWebhook.
+func (mr *MockPaymentGatewayMockRecorder) ParseWebhook(payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseWebhook", reflect.TypeOf((*MockPaymentGateway)(nil).ParseWebhook), payload, signature)
+}
+
+// Refund mocks base method.
+func (m *MockPaymentGateway) Refund(ctx context.Context, externalID string, amount uint, currency string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, externalID, amount, currency)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentGatewayMockRecorder) Refund(ctx, externalID, amount, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentGateway)(nil).Refund), ctx, externalID, amount, currency)
+}
+
+// MockPaymentHold is a mock of PaymentHold interface.
+type MockPaymentHold struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentHoldMockRecorder
+}
+
+// MockPaymentHoldMockRecorder is the mock recorder for MockPaymentHold.
+type MockPaymentHoldMockRecorder struct {
+	mock *MockPaymentHold
+}
+
+// NewMockPaymentHold creates a new mock instance.
+func NewMockPaymentHold(ctrl *gomock.Controller) *MockPaymentHold {
+	mock := &MockPaymentHold{ctrl: ctrl}
+	mock.recorder = &MockPaymentHoldMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentHold) EXPECT() *MockPaymentHoldMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockPaymentHold) Cancel(ctx context.Context, externalID string) (*entity.GatewayPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, externalID)
+	ret0, _ := ret[0].(*entity.GatewayPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockPaymentHoldMockRecorder) Cancel(ctx, externalID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockPaymentHold)(nil).Cancel), ctx, externalID)
+}
+
+// Capture mocks base method.
+func (m *MockPaymentHold) Capture(ctx context.Context, externalID string, amount uint, currency string) (*entity.GatewayPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, externalID, amount, currency)
+	ret0, _ := ret[0].(*entity.GatewayPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockPaymentHoldMockRecorder) Capture(ctx, externalID, amount, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockPaymentHold)(nil).Capture), ctx, externalID, amount, currency)
+}
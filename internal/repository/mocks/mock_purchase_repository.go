@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -37,46 +38,89 @@ func (m *MockPurchaseRepository) EXPECT() *MockPurchaseRepositoryMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockPurchaseRepository) Add(tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase, error) {
+func (m *MockPurchaseRepository) Add(ctx context.Context, tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", tx, purchase)
+	ret := m.ctrl.Call(m, "Add", ctx, tx, purchase)
 	ret0, _ := ret[0].(*entity.Purchase)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockPurchaseRepositoryMockRecorder) Add(tx, purchase interface{}) *gomock.Call {
+func (mr *MockPurchaseRepositoryMockRecorder) Add(ctx, tx, purchase interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockPurchaseRepository)(nil).Add), tx, purchase)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockPurchaseRepository)(nil).Add), ctx, tx, purchase)
+}
+
+// AddAddress mocks base method.
+func (m *MockPurchaseRepository) AddAddress(ctx context.Context, tx pgx.Tx, snapshot *entity.PurchaseAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAddress", ctx, tx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAddress indicates an expected call of AddAddress.
+func (mr *MockPurchaseRepositoryMockRecorder) AddAddress(ctx, tx, snapshot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAddress", reflect.TypeOf((*MockPurchaseRepository)(nil).AddAddress), ctx, tx, snapshot)
 }
 
 // BeginTransaction mocks base method.
-func (m *MockPurchaseRepository) BeginTransaction() (pgx.Tx, error) {
+func (m *MockPurchaseRepository) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret := m.ctrl.Call(m, "BeginTransaction", ctx)
 	ret0, _ := ret[0].(pgx.Tx)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // BeginTransaction indicates an expected call of BeginTransaction.
-func (mr *MockPurchaseRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+func (mr *MockPurchaseRepositoryMockRecorder) BeginTransaction(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockPurchaseRepository)(nil).BeginTransaction))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockPurchaseRepository)(nil).BeginTransaction), ctx)
+}
+
+// GetById mocks base method.
+func (m *MockPurchaseRepository) GetById(ctx context.Context, purchaseID uuid.UUID) (*entity.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, purchaseID)
+	ret0, _ := ret[0].(*entity.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockPurchaseRepositoryMockRecorder) GetById(ctx, purchaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockPurchaseRepository)(nil).GetById), ctx, purchaseID)
 }
 
 // GetByUserId mocks base method.
-func (m *MockPurchaseRepository) GetByUserId(userID uuid.UUID) ([]*entity.Purchase, error) {
+func (m *MockPurchaseRepository) GetByUserId(ctx context.Context, userID uuid.UUID) ([]*entity.Purchase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", userID)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userID)
 	ret0, _ := ret[0].([]*entity.Purchase)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockPurchaseRepositoryMockRecorder) GetByUserId(userID interface{}) *gomock.Call {
+func (mr *MockPurchaseRepositoryMockRecorder) GetByUserId(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockPurchaseRepository)(nil).GetByUserId), ctx, userID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockPurchaseRepository) UpdateStatus(ctx context.Context, tx pgx.Tx, purchaseID uuid.UUID, status entity.PurchaseStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, tx, purchaseID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockPurchaseRepositoryMockRecorder) UpdateStatus(ctx, tx, purchaseID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockPurchaseRepository)(nil).GetByUserId), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockPurchaseRepository)(nil).UpdateStatus), ctx, tx, purchaseID, status)
 }
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -37,46 +38,46 @@ func (m *MockSeller) EXPECT() *MockSellerMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockSeller) Add(tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error) {
+func (m *MockSeller) Add(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", tx, userID)
+	ret := m.ctrl.Call(m, "Add", ctx, tx, userID)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockSellerMockRecorder) Add(tx, userID interface{}) *gomock.Call {
+func (mr *MockSellerMockRecorder) Add(ctx, tx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSeller)(nil).Add), tx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSeller)(nil).Add), ctx, tx, userID)
 }
 
 // GetById mocks base method.
-func (m *MockSeller) GetById(sellerID uuid.UUID) (*entity.Seller, error) {
+func (m *MockSeller) GetById(ctx context.Context, sellerID uuid.UUID) (*entity.Seller, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", sellerID)
+	ret := m.ctrl.Call(m, "GetById", ctx, sellerID)
 	ret0, _ := ret[0].(*entity.Seller)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockSellerMockRecorder) GetById(sellerID interface{}) *gomock.Call {
+func (mr *MockSellerMockRecorder) GetById(ctx, sellerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockSeller)(nil).GetById), sellerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockSeller)(nil).GetById), ctx, sellerID)
 }
 
 // GetByUserId mocks base method.
-func (m *MockSeller) GetByUserId(userID uuid.UUID) (*entity.Seller, error) {
+func (m *MockSeller) GetByUserId(ctx context.Context, userID uuid.UUID) (*entity.Seller, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", userID)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userID)
 	ret0, _ := ret[0].(*entity.Seller)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockSellerMockRecorder) GetByUserId(userID interface{}) *gomock.Call {
+func (mr *MockSellerMockRecorder) GetByUserId(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockSeller)(nil).GetByUserId), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockSeller)(nil).GetByUserId), ctx, userID)
 }
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	"github.com/golang/mock/gomock"
@@ -8,8 +9,8 @@ import (
 )
 
 type SessionRepository interface {
-	Create(userID uuid.UUID) (string, error)
-	Delete(sessionID string) error
+	Create(ctx context.Context, userID uuid.UUID) (string, error)
+	Delete(ctx context.Context, sessionID string) error
 }
 
 // MockSession is a mock of Session interface
@@ -32,39 +33,39 @@ func NewMockSession(ctrl *gomock.Controller) *MockSession {
 	return mock
 }
 
-func (m *MockSession) Delete(sessionID string) error {
+func (m *MockSession) Delete(ctx context.Context, sessionID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", sessionID)
+	ret := m.ctrl.Call(m, "Delete", ctx, sessionID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
-func (mr *MockSessionMockRecorder) Delete(sessionID interface{}) *gomock.Call {
+func (mr *MockSessionMockRecorder) Delete(ctx, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSession)(nil).Delete), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSession)(nil).Delete), ctx, sessionID)
 }
 
-func (m *MockSession) Create(userID uuid.UUID) (string, error) {
+func (m *MockSession) Create(ctx context.Context, userID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", userID)
+	ret := m.ctrl.Call(m, "Create", ctx, userID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (mr *MockSessionMockRecorder) Create(userID interface{}) *gomock.Call {
+func (mr *MockSessionMockRecorder) Create(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSession)(nil).Create), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSession)(nil).Create), ctx, userID)
 }
 
-func (m *MockSession) Get(sessionID string) (uuid.UUID, error) {
+func (m *MockSession) Get(ctx context.Context, sessionID string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", sessionID)
+	ret := m.ctrl.Call(m, "Get", ctx, sessionID)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (mr *MockSessionMockRecorder) Get(sessionID interface{}) *gomock.Call {
+func (mr *MockSessionMockRecorder) Get(ctx, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSession)(nil).Get), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSession)(nil).Get), ctx, sessionID)
 }
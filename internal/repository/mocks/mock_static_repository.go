@@ -5,7 +5,10 @@
 package mocks
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -34,19 +37,48 @@ func (m *MockStaticRepository) EXPECT() *MockStaticRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CollectGarbage mocks base method.
+func (m *MockStaticRepository) CollectGarbage(ctx context.Context, olderThan time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectGarbage", ctx, olderThan, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectGarbage indicates an expected call of CollectGarbage.
+func (mr *MockStaticRepositoryMockRecorder) CollectGarbage(ctx, olderThan, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGarbage", reflect.TypeOf((*MockStaticRepository)(nil).CollectGarbage), ctx, olderThan, limit)
+}
+
+// Flag mocks base method.
+func (m *MockStaticRepository) Flag(ctx context.Context, staticID uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flag", ctx, staticID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flag indicates an expected call of Flag.
+func (mr *MockStaticRepositoryMockRecorder) Flag(ctx, staticID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flag", reflect.TypeOf((*MockStaticRepository)(nil).Flag), ctx, staticID, reason)
+}
+
 // Get mocks base method.
-func (m *MockStaticRepository) Get(staticID uuid.UUID) (string, error) {
+func (m *MockStaticRepository) Get(ctx context.Context, staticID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", staticID)
+	ret := m.ctrl.Call(m, "Get", ctx, staticID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockStaticRepositoryMockRecorder) Get(staticID interface{}) *gomock.Call {
+func (mr *MockStaticRepositoryMockRecorder) Get(ctx, staticID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStaticRepository)(nil).Get), staticID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStaticRepository)(nil).Get), ctx, staticID)
 }
 
 // GetMaxSize mocks base method.
@@ -63,17 +95,32 @@ func (mr *MockStaticRepositoryMockRecorder) GetMaxSize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMaxSize", reflect.TypeOf((*MockStaticRepository)(nil).GetMaxSize))
 }
 
+// IsFlagged mocks base method.
+func (m *MockStaticRepository) IsFlagged(ctx context.Context, source string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFlagged", ctx, source)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFlagged indicates an expected call of IsFlagged.
+func (mr *MockStaticRepositoryMockRecorder) IsFlagged(ctx, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFlagged", reflect.TypeOf((*MockStaticRepository)(nil).IsFlagged), ctx, source)
+}
+
 // Upload mocks base method.
-func (m *MockStaticRepository) Upload(path, filename string, data []byte) (uuid.UUID, error) {
+func (m *MockStaticRepository) Upload(ctx context.Context, path, filename string, data io.Reader, size int64) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upload", path, filename, data)
+	ret := m.ctrl.Call(m, "Upload", ctx, path, filename, data, size)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Upload indicates an expected call of Upload.
-func (mr *MockStaticRepositoryMockRecorder) Upload(path, filename, data interface{}) *gomock.Call {
+func (mr *MockStaticRepositoryMockRecorder) Upload(ctx, path, filename, data, size interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStaticRepository)(nil).Upload), path, filename, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockStaticRepository)(nil).Upload), ctx, path, filename, data, size)
 }
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/static_upload.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockStaticUploadRepository is a mock of StaticUploadRepository interface.
+type MockStaticUploadRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStaticUploadRepositoryMockRecorder
+}
+
+// MockStaticUploadRepositoryMockRecorder is the mock recorder for MockStaticUploadRepository.
+type MockStaticUploadRepositoryMockRecorder struct {
+	mock *MockStaticUploadRepository
+}
+
+// NewMockStaticUploadRepository creates a new mock instance.
+func NewMockStaticUploadRepository(ctrl *gomock.Controller) *MockStaticUploadRepository {
+	mock := &MockStaticUploadRepository{ctrl: ctrl}
+	mock.recorder = &MockStaticUploadRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStaticUploadRepository) EXPECT() *MockStaticUploadRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AppendChunk mocks base method.
+func (m *MockStaticUploadRepository) AppendChunk(ctx context.Context, uploadID uuid.UUID, offset int64, data []byte, expiresAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendChunk", ctx, uploadID, offset, data, expiresAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendChunk indicates an expected call of AppendChunk.
+func (mr *MockStaticUploadRepositoryMockRecorder) AppendChunk(ctx, uploadID, offset, data, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendChunk", reflect.TypeOf((*MockStaticUploadRepository)(nil).AppendChunk), ctx, uploadID, offset, data, expiresAt)
+}
+
+// Create mocks base method.
+func (m *MockStaticUploadRepository) Create(ctx context.Context, upload *entity.ResumableUpload) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, upload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockStaticUploadRepositoryMockRecorder) Create(ctx, upload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockStaticUploadRepository)(nil).Create), ctx, upload)
+}
+
+// Delete mocks base method.
+func (m *MockStaticUploadRepository) Delete(ctx context.Context, uploadID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uploadID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockStaticUploadRepositoryMockRecorder) Delete(ctx, uploadID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStaticUploadRepository)(nil).Delete), ctx, uploadID)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockStaticUploadRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockStaticUploadRepositoryMockRecorder) DeleteExpired(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockStaticUploadRepository)(nil).DeleteExpired), ctx, now, limit)
+}
+
+// Get mocks base method.
+func (m *MockStaticUploadRepository) Get(ctx context.Context, uploadID uuid.UUID) (*entity.ResumableUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, uploadID)
+	ret0, _ := ret[0].(*entity.ResumableUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockStaticUploadRepositoryMockRecorder) Get(ctx, uploadID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStaticUploadRepository)(nil).Get), ctx, uploadID)
+}
+
+// Open mocks base method.
+func (m *MockStaticUploadRepository) Open(ctx context.Context, uploadID uuid.UUID) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, uploadID)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockStaticUploadRepositoryMockRecorder) Open(ctx, uploadID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockStaticUploadRepository)(nil).Open), ctx, uploadID)
+}
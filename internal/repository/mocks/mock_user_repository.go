@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -37,118 +38,118 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockUser) Add(tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, error) {
+func (m *MockUser) Add(ctx context.Context, tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", tx, email, hash, salt)
+	ret := m.ctrl.Call(m, "Add", ctx, tx, email, hash, salt)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockUserMockRecorder) Add(tx, email, hash, salt interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Add(ctx, tx, email, hash, salt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockUser)(nil).Add), tx, email, hash, salt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockUser)(nil).Add), ctx, tx, email, hash, salt)
 }
 
 // BeginTransaction mocks base method.
-func (m *MockUser) BeginTransaction() (pgx.Tx, error) {
+func (m *MockUser) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret := m.ctrl.Call(m, "BeginTransaction", ctx)
 	ret0, _ := ret[0].(pgx.Tx)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // BeginTransaction indicates an expected call of BeginTransaction.
-func (mr *MockUserMockRecorder) BeginTransaction() *gomock.Call {
+func (mr *MockUserMockRecorder) BeginTransaction(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockUser)(nil).BeginTransaction))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockUser)(nil).BeginTransaction), ctx)
 }
 
 // CheckIfExists mocks base method.
-func (m *MockUser) CheckIfExists(userId uuid.UUID) (bool, error) {
+func (m *MockUser) CheckIfExists(ctx context.Context, userId uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckIfExists", userId)
+	ret := m.ctrl.Call(m, "CheckIfExists", ctx, userId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckIfExists indicates an expected call of CheckIfExists.
-func (mr *MockUserMockRecorder) CheckIfExists(userId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) CheckIfExists(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfExists", reflect.TypeOf((*MockUser)(nil).CheckIfExists), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIfExists", reflect.TypeOf((*MockUser)(nil).CheckIfExists), ctx, userId)
 }
 
 // Delete mocks base method.
-func (m *MockUser) Delete(userID uuid.UUID) error {
+func (m *MockUser) Delete(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userID)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUserMockRecorder) Delete(userID interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Delete(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUser)(nil).Delete), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUser)(nil).Delete), ctx, userID)
 }
 
 // GetByEmail mocks base method.
-func (m *MockUser) GetByEmail(email string) (*entity.User, error) {
+func (m *MockUser) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByEmail", email)
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
 	ret0, _ := ret[0].(*entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByEmail indicates an expected call of GetByEmail.
-func (mr *MockUserMockRecorder) GetByEmail(email interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUser)(nil).GetByEmail), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUser)(nil).GetByEmail), ctx, email)
 }
 
 // GetById mocks base method.
-func (m *MockUser) GetById(userId uuid.UUID) (*entity.User, error) {
+func (m *MockUser) GetById(ctx context.Context, userId uuid.UUID) (*entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", userId)
+	ret := m.ctrl.Call(m, "GetById", ctx, userId)
 	ret0, _ := ret[0].(*entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockUserMockRecorder) GetById(userId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) GetById(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockUser)(nil).GetById), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockUser)(nil).GetById), ctx, userId)
 }
 
 // Update mocks base method.
-func (m *MockUser) Update(user *entity.User) error {
+func (m *MockUser) Update(ctx context.Context, user *entity.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", user)
+	ret := m.ctrl.Call(m, "Update", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserMockRecorder) Update(user interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Update(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUser)(nil).Update), ctx, user)
 }
 
 // UploadImage mocks base method.
-func (m *MockUser) UploadImage(userID, imageId uuid.UUID) error {
+func (m *MockUser) UploadImage(ctx context.Context, userID, imageId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadImage", userID, imageId)
+	ret := m.ctrl.Call(m, "UploadImage", ctx, userID, imageId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UploadImage indicates an expected call of UploadImage.
-func (mr *MockUserMockRecorder) UploadImage(userID, imageId interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) UploadImage(ctx, userID, imageId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockUser)(nil).UploadImage), userID, imageId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockUser)(nil).UploadImage), ctx, userID, imageId)
 }
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/views.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockViewCounter is a mock of ViewCounter interface.
+type MockViewCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockViewCounterMockRecorder
+}
+
+// MockViewCounterMockRecorder is the mock recorder for MockViewCounter.
+type MockViewCounterMockRecorder struct {
+	mock *MockViewCounter
+}
+
+// NewMockViewCounter creates a new mock instance.
+func NewMockViewCounter(ctrl *gomock.Controller) *MockViewCounter {
+	mock := &MockViewCounter{ctrl: ctrl}
+	mock.recorder = &MockViewCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockViewCounter) EXPECT() *MockViewCounterMockRecorder {
+	return m.recorder
+}
+
+// AckPending mocks base method.
+func (m *MockViewCounter) AckPending(ctx context.Context, batchId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AckPending", ctx, batchId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AckPending indicates an expected call of AckPending.
+func (mr *MockViewCounterMockRecorder) AckPending(ctx, batchId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckPending", reflect.TypeOf((*MockViewCounter)(nil).AckPending), ctx, batchId)
+}
+
+// RegisterView mocks base method.
+func (m *MockViewCounter) RegisterView(ctx context.Context, view entity.AdvertView) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterView", ctx, view)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterView indicates an expected call of RegisterView.
+func (mr *MockViewCounterMockRecorder) RegisterView(ctx, view interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterView", reflect.TypeOf((*MockViewCounter)(nil).RegisterView), ctx, view)
+}
+
+// TakePending mocks base method.
+func (m *MockViewCounter) TakePending(ctx context.Context) (*entity.PendingViews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakePending", ctx)
+	ret0, _ := ret[0].(*entity.PendingViews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakePending indicates an expected call of TakePending.
+func (mr *MockViewCounterMockRecorder) TakePending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakePending", reflect.TypeOf((*MockViewCounter)(nil).TakePending), ctx)
+}
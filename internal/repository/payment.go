@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type PaymentRepository interface {
+	// BeginTransaction начинает транзакцию
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
+
+	// Add сохраняет платеж, созданный у провайдера
+	Add(ctx context.Context, payment *entity.Payment) (*entity.Payment, error)
+
+	// GetById возвращает платеж вместе с покупателем
+	// Возможные ошибки:
+	// ErrPaymentNotFound - платеж не найден
+	GetById(ctx context.Context, paymentID uuid.UUID) (*entity.Payment, error)
+
+	// GetByPurchaseId возвращает последний платеж за покупку
+	// Возможные ошибки:
+	// ErrPaymentNotFound - за покупку не создано ни одного платежа
+	GetByPurchaseId(ctx context.Context, purchaseID uuid.UUID) (*entity.Payment, error)
+
+	// GetByExternalId возвращает платеж по идентификатору у провайдера
+	// Возможные ошибки:
+	// ErrPaymentNotFound - платеж не найден
+	GetByExternalId(ctx context.Context, provider, externalID string) (*entity.Payment, error)
+
+	// UpdateStatus переводит платеж из статуса from в статус to. Возвращает false, если статус
+	// платежа уже изменил параллельный запрос
+	UpdateStatus(ctx context.Context, tx pgx.Tx, paymentID uuid.UUID, from, to entity.PaymentStatus) (bool, error)
+}
+
+var (
+	ErrPaymentNotFound = errors.New("платеж не найден")
+)
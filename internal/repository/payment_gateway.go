@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
+
+// PaymentGateway - платежный провайдер, принимающий оплату картой. Суммы передаются в копейках
+type PaymentGateway interface {
+	// Name возвращает имя провайдера, под которым платежи хранятся в БД
+	Name() string
+
+	// CreatePayment создает платеж и возвращает ссылку, по которой покупатель его подтверждает.
+	// Повтор с тем же IdempotenceKey возвращает уже созданный платеж
+	CreatePayment(ctx context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error)
+
+	PaymentHold
+
+	// Refund возвращает покупателю списанные средства
+	// Возможные ошибки:
+	// ErrGatewayPaymentNotFound - платеж не найден у провайдера
+	// ErrGatewayPaymentState - платеж не списан или сумма больше списанной
+	Refund(ctx context.Context, externalID string, amount uint, currency string) error
+
+	// ParseWebhook проверяет подпись уведомления провайдера и разбирает его
+	// Возможные ошибки:
+	// ErrGatewayInvalidSignature - подпись не совпала
+	// ErrGatewayInvalidWebhook - уведомление не удалось разобрать
+	ParseWebhook(payload []byte, signature string) (*entity.PaymentEvent, error)
+}
+
+// PaymentHold - заморозка средств покупателя: платеж, созданный без списания, остается
+// в waiting_for_capture, пока средства не спишут или не разблокируют
+type PaymentHold interface {
+	// Capture списывает заблокированные на карте средства
+	// Возможные ошибки:
+	// ErrGatewayPaymentNotFound - платеж не найден у провайдера
+	// ErrGatewayPaymentState - средства не заблокированы или сумма больше заблокированной
+	Capture(ctx context.Context, externalID string, amount uint, currency string) (*entity.GatewayPayment, error)
+
+	// Cancel разблокирует средства и отменяет платеж
+	// Возможные ошибки:
+	// ErrGatewayPaymentNotFound - платеж не найден у провайдера
+	// ErrGatewayPaymentState - средства не заблокированы
+	Cancel(ctx context.Context, externalID string) (*entity.GatewayPayment, error)
+}
+
+var (
+	ErrGatewayPaymentNotFound  = errors.New("платеж не найден у провайдера")
+	ErrGatewayPaymentState     = errors.New("платеж в неподходящем для операции статусе")
+	ErrGatewayInvalidSignature = errors.New("неверная подпись уведомления о платеже")
+	ErrGatewayInvalidWebhook   = errors.New("некорректное уведомление о платеже")
+)
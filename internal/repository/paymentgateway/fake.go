@@ -0,0 +1,161 @@
+package paymentgateway
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+)
+
+const FakeProviderName = "fake"
+
+type fakePayment struct {
+	status   entity.PaymentStatus
+	amount   uint
+	refunded uint
+	capture  bool
+}
+
+// FakeGateway - платежный шлюз в памяти процесса для разработки и тестов. Платежи не уходят
+// наружу: покупатель "оплачивает" их через Confirm, отказывается через Decline, а шлюз
+// возвращает уведомление, подписанное тем же секретом, что и у настоящего провайдера
+type FakeGateway struct {
+	mu            sync.Mutex
+	payments      map[string]*fakePayment
+	idempotence   map[string]string
+	webhookSecret string
+}
+
+func NewFakeGateway(webhookSecret string) *FakeGateway {
+	return &FakeGateway{
+		payments:      make(map[string]*fakePayment),
+		idempotence:   make(map[string]string),
+		webhookSecret: webhookSecret,
+	}
+}
+
+func (g *FakeGateway) Name() string {
+	return FakeProviderName
+}
+
+func (g *FakeGateway) CreatePayment(_ context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if externalID, ok := g.idempotence[req.IdempotenceKey]; ok && req.IdempotenceKey != "" {
+		return g.gatewayPayment(externalID, req.ReturnURL), nil
+	}
+
+	externalID := "fake-" + uuid.NewString()
+	g.payments[externalID] = &fakePayment{
+		status:  entity.PaymentStatusPending,
+		amount:  req.Amount,
+		capture: req.Capture,
+	}
+	if req.IdempotenceKey != "" {
+		g.idempotence[req.IdempotenceKey] = externalID
+	}
+	return g.gatewayPayment(externalID, req.ReturnURL), nil
+}
+
+func (g *FakeGateway) gatewayPayment(externalID, returnURL string) *entity.GatewayPayment {
+	payment := &entity.GatewayPayment{
+		ExternalID: externalID,
+		Status:     g.payments[externalID].status,
+	}
+	if returnURL != "" {
+		payment.ConfirmationURL = returnURL + "?payment_id=" + url.QueryEscape(externalID)
+	}
+	return payment
+}
+
+func (g *FakeGateway) Capture(_ context.Context, externalID string, amount uint, _ string) (*entity.GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[externalID]
+	if !ok {
+		return nil, repository.ErrGatewayPaymentNotFound
+	}
+	if payment.status != entity.PaymentStatusWaitingForCapture || amount > payment.amount {
+		return nil, repository.ErrGatewayPaymentState
+	}
+	payment.amount = amount
+	payment.status = entity.PaymentStatusSucceeded
+	return &entity.GatewayPayment{ExternalID: externalID, Status: payment.status}, nil
+}
+
+func (g *FakeGateway) Cancel(_ context.Context, externalID string) (*entity.GatewayPayment, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[externalID]
+	if !ok {
+		return nil, repository.ErrGatewayPaymentNotFound
+	}
+	if payment.status != entity.PaymentStatusWaitingForCapture {
+		return nil, repository.ErrGatewayPaymentState
+	}
+	payment.status = entity.PaymentStatusCanceled
+	return &entity.GatewayPayment{ExternalID: externalID, Status: payment.status}, nil
+}
+
+func (g *FakeGateway) Refund(_ context.Context, externalID string, amount uint, _ string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[externalID]
+	if !ok {
+		return repository.ErrGatewayPaymentNotFound
+	}
+	if payment.status != entity.PaymentStatusSucceeded || payment.refunded+amount > payment.amount {
+		return repository.ErrGatewayPaymentState
+	}
+	payment.refunded += amount
+	if payment.refunded == payment.amount {
+		payment.status = entity.PaymentStatusRefunded
+	}
+	return nil
+}
+
+func (g *FakeGateway) ParseWebhook(payload []byte, signature string) (*entity.PaymentEvent, error) {
+	return parseNotification(g.webhookSecret, payload, signature)
+}
+
+// Confirm имитирует оплату покупателем: платеж с отложенным списанием переходит
+// в waiting_for_capture, остальные - в succeeded. Возвращает уведомление и его подпись
+func (g *FakeGateway) Confirm(externalID string) ([]byte, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[externalID]
+	if !ok {
+		return nil, "", repository.ErrGatewayPaymentNotFound
+	}
+	if payment.status == entity.PaymentStatusPending {
+		payment.status = entity.PaymentStatusWaitingForCapture
+		if payment.capture {
+			payment.status = entity.PaymentStatusSucceeded
+		}
+	}
+	return buildNotification(g.webhookSecret, externalID, payment.status)
+}
+
+// Decline имитирует отказ в оплате и возвращает уведомление об отмене платежа
+func (g *FakeGateway) Decline(externalID string) ([]byte, string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	payment, ok := g.payments[externalID]
+	if !ok {
+		return nil, "", repository.ErrGatewayPaymentNotFound
+	}
+	if !payment.status.CanChangeTo(entity.PaymentStatusCanceled) {
+		return nil, "", repository.ErrGatewayPaymentState
+	}
+	payment.status = entity.PaymentStatusCanceled
+	return buildNotification(g.webhookSecret, externalID, payment.status)
+}
@@ -0,0 +1,170 @@
+package paymentgateway
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+func fakePaymentRequest(capture bool) entity.GatewayPaymentRequest {
+	purchaseID := uuid.New()
+	return entity.GatewayPaymentRequest{
+		PurchaseID:     purchaseID,
+		Amount:         150000,
+		Currency:       "RUB",
+		ReturnURL:      "http://localhost:8008/purchases",
+		Capture:        capture,
+		IdempotenceKey: purchaseID.String(),
+	}
+}
+
+func TestFakeGateway(t *testing.T) {
+	t.Run("ConfirmWithCapture", func(t *testing.T) {
+		gateway := NewFakeGateway(testWebhookSecret)
+
+		payment, err := gateway.CreatePayment(context.Background(), fakePaymentRequest(true))
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentStatusPending, payment.Status)
+		assert.True(t, strings.HasPrefix(payment.ConfirmationURL, "http://localhost:8008/purchases?payment_id="))
+
+		payload, signature, err := gateway.Confirm(payment.ExternalID)
+		require.NoError(t, err)
+		event, err := gateway.ParseWebhook(payload, signature)
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentEvent{ExternalID: payment.ExternalID, Status: entity.PaymentStatusSucceeded}, *event)
+
+		require.NoError(t, gateway.Refund(context.Background(), payment.ExternalID, 150000, "RUB"))
+		assert.ErrorIs(t, gateway.Refund(context.Background(), payment.ExternalID, 1, "RUB"), repository.ErrGatewayPaymentState)
+	})
+
+	t.Run("ConfirmThenCapture", func(t *testing.T) {
+		gateway := NewFakeGateway(testWebhookSecret)
+
+		payment, err := gateway.CreatePayment(context.Background(), fakePaymentRequest(false))
+		require.NoError(t, err)
+
+		_, err = gateway.Capture(context.Background(), payment.ExternalID, 150000, "RUB")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentState)
+
+		payload, signature, err := gateway.Confirm(payment.ExternalID)
+		require.NoError(t, err)
+		event, err := gateway.ParseWebhook(payload, signature)
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentStatusWaitingForCapture, event.Status)
+
+		captured, err := gateway.Capture(context.Background(), payment.ExternalID, 150000, "RUB")
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentStatusSucceeded, captured.Status)
+	})
+
+	t.Run("ConfirmThenCancel", func(t *testing.T) {
+		gateway := NewFakeGateway(testWebhookSecret)
+
+		payment, err := gateway.CreatePayment(context.Background(), fakePaymentRequest(false))
+		require.NoError(t, err)
+
+		_, err = gateway.Cancel(context.Background(), payment.ExternalID)
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentState)
+
+		_, _, err = gateway.Confirm(payment.ExternalID)
+		require.NoError(t, err)
+		canceled, err := gateway.Cancel(context.Background(), payment.ExternalID)
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentStatusCanceled, canceled.Status)
+
+		_, err = gateway.Capture(context.Background(), payment.ExternalID, 150000, "RUB")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentState)
+	})
+
+	t.Run("Decline", func(t *testing.T) {
+		gateway := NewFakeGateway(testWebhookSecret)
+
+		payment, err := gateway.CreatePayment(context.Background(), fakePaymentRequest(true))
+		require.NoError(t, err)
+
+		payload, signature, err := gateway.Decline(payment.ExternalID)
+		require.NoError(t, err)
+		event, err := gateway.ParseWebhook(payload, signature)
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentStatusCanceled, event.Status)
+
+		_, _, err = gateway.Decline(payment.ExternalID)
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentState)
+	})
+
+	t.Run("Idempotence", func(t *testing.T) {
+		gateway := NewFakeGateway(testWebhookSecret)
+		req := fakePaymentRequest(true)
+
+		first, err := gateway.CreatePayment(context.Background(), req)
+		require.NoError(t, err)
+		second, err := gateway.CreatePayment(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, first.ExternalID, second.ExternalID)
+
+		other, err := gateway.CreatePayment(context.Background(), fakePaymentRequest(true))
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ExternalID, other.ExternalID)
+	})
+
+	t.Run("UnknownPayment", func(t *testing.T) {
+		gateway := NewFakeGateway(testWebhookSecret)
+
+		_, _, err := gateway.Confirm("missing")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentNotFound)
+		_, err = gateway.Capture(context.Background(), "missing", 1, "RUB")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentNotFound)
+		assert.ErrorIs(t, gateway.Refund(context.Background(), "missing", 1, "RUB"), repository.ErrGatewayPaymentNotFound)
+		_, err = gateway.Cancel(context.Background(), "missing")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentNotFound)
+	})
+}
+
+func TestParseNotification(t *testing.T) {
+	payload := []byte(`{"type":"notification","event":"payment.succeeded","object":{"id":"pay-1","status":"succeeded"}}`)
+
+	t.Run("Valid", func(t *testing.T) {
+		event, err := parseNotification(testWebhookSecret, payload, Sign(testWebhookSecret, payload))
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentEvent{ExternalID: "pay-1", Status: entity.PaymentStatusSucceeded}, *event)
+	})
+
+	t.Run("Refund", func(t *testing.T) {
+		refund := []byte(`{"type":"notification","event":"refund.succeeded","object":{"id":"ref-1","payment_id":"pay-1","status":"succeeded"}}`)
+		event, err := parseNotification(testWebhookSecret, refund, Sign(testWebhookSecret, refund))
+		require.NoError(t, err)
+		assert.Equal(t, entity.PaymentEvent{ExternalID: "pay-1", Status: entity.PaymentStatusRefunded}, *event)
+	})
+
+	t.Run("WrongSignature", func(t *testing.T) {
+		for _, signature := range []string{"", "not-hex", Sign("other-secret", payload)} {
+			_, err := parseNotification(testWebhookSecret, payload, signature)
+			assert.ErrorIs(t, err, repository.ErrGatewayInvalidSignature, signature)
+		}
+	})
+
+	t.Run("EmptySecret", func(t *testing.T) {
+		_, err := parseNotification("", payload, Sign("", payload))
+		assert.ErrorIs(t, err, repository.ErrGatewayInvalidSignature)
+	})
+
+	t.Run("UnknownEvent", func(t *testing.T) {
+		unknown := []byte(`{"type":"notification","event":"deal.closed","object":{"id":"pay-1"}}`)
+		_, err := parseNotification(testWebhookSecret, unknown, Sign(testWebhookSecret, unknown))
+		assert.ErrorIs(t, err, repository.ErrGatewayInvalidWebhook)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		malformed := []byte(`{"event":`)
+		_, err := parseNotification(testWebhookSecret, malformed, Sign(testWebhookSecret, malformed))
+		assert.ErrorIs(t, err, repository.ErrGatewayInvalidWebhook)
+	})
+}
@@ -0,0 +1,97 @@
+package paymentgateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+)
+
+const (
+	eventPaymentWaitingForCapture = "payment.waiting_for_capture"
+	eventPaymentSucceeded         = "payment.succeeded"
+	eventPaymentCanceled          = "payment.canceled"
+	eventRefundSucceeded          = "refund.succeeded"
+)
+
+// notification - уведомление в формате ЮKassa. У возврата идентификатор платежа лежит в payment_id
+type notification struct {
+	Type   string             `json:"type"`
+	Event  string             `json:"event"`
+	Object notificationObject `json:"object"`
+}
+
+type notificationObject struct {
+	ID        string `json:"id"`
+	PaymentID string `json:"payment_id,omitempty"`
+	Status    string `json:"status"`
+}
+
+func mac(secret string, payload []byte) []byte {
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write(payload)
+	return hash.Sum(nil)
+}
+
+// Sign возвращает подпись уведомления: HMAC-SHA256 тела в шестнадцатеричной записи
+func Sign(secret string, payload []byte) string {
+	return hex.EncodeToString(mac(secret, payload))
+}
+
+func parseNotification(secret string, payload []byte, signature string) (*entity.PaymentEvent, error) {
+	got, err := hex.DecodeString(signature)
+	if err != nil || secret == "" || !hmac.Equal(got, mac(secret, payload)) {
+		return nil, repository.ErrGatewayInvalidSignature
+	}
+
+	var n notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return nil, repository.ErrGatewayInvalidWebhook
+	}
+
+	event := &entity.PaymentEvent{ExternalID: n.Object.ID}
+	switch n.Event {
+	case eventPaymentWaitingForCapture:
+		event.Status = entity.PaymentStatusWaitingForCapture
+	case eventPaymentSucceeded:
+		event.Status = entity.PaymentStatusSucceeded
+	case eventPaymentCanceled:
+		event.Status = entity.PaymentStatusCanceled
+	case eventRefundSucceeded:
+		event.ExternalID = n.Object.PaymentID
+		event.Status = entity.PaymentStatusRefunded
+	default:
+		return nil, repository.ErrGatewayInvalidWebhook
+	}
+	if event.ExternalID == "" {
+		return nil, repository.ErrGatewayInvalidWebhook
+	}
+	return event, nil
+}
+
+// buildNotification собирает подписанное уведомление о смене статуса платежа
+func buildNotification(secret, externalID string, status entity.PaymentStatus) ([]byte, string, error) {
+	n := notification{Type: "notification", Object: notificationObject{ID: externalID, Status: string(status)}}
+	switch status {
+	case entity.PaymentStatusWaitingForCapture:
+		n.Event = eventPaymentWaitingForCapture
+	case entity.PaymentStatusSucceeded:
+		n.Event = eventPaymentSucceeded
+	case entity.PaymentStatusCanceled:
+		n.Event = eventPaymentCanceled
+	case entity.PaymentStatusRefunded:
+		n.Event = eventRefundSucceeded
+		n.Object = notificationObject{ID: externalID + "-refund", PaymentID: externalID, Status: string(entity.PaymentStatusSucceeded)}
+	default:
+		return nil, "", repository.ErrGatewayInvalidWebhook
+	}
+
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, Sign(secret, payload), nil
+}
@@ -0,0 +1,200 @@
+package paymentgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.uber.org/zap"
+)
+
+const YooKassaProviderName = "yookassa"
+
+// YooKassaGateway принимает платежи через API ЮKassa v3. Запросы подписываются
+// идентификатором магазина и секретным ключом, каждый изменяющий запрос несет
+// заголовок Idempotence-Key
+type YooKassaGateway struct {
+	client        *http.Client
+	apiURL        string
+	shopID        string
+	secretKey     string
+	webhookSecret string
+	logger        *zap.Logger
+}
+
+func NewYooKassaGateway(apiURL, shopID, secretKey, webhookSecret string, timeout time.Duration, logger *zap.Logger) *YooKassaGateway {
+	return &YooKassaGateway{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		apiURL:        strings.TrimRight(apiURL, "/"),
+		shopID:        shopID,
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		logger:        logger,
+	}
+}
+
+type yooKassaAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+type yooKassaConfirmation struct {
+	Type            string `json:"type"`
+	ReturnURL       string `json:"return_url,omitempty"`
+	ConfirmationURL string `json:"confirmation_url,omitempty"`
+}
+
+type yooKassaPaymentRequest struct {
+	Amount       yooKassaAmount       `json:"amount"`
+	Capture      bool                 `json:"capture"`
+	Confirmation yooKassaConfirmation `json:"confirmation"`
+	Description  string               `json:"description,omitempty"`
+	Metadata     map[string]string    `json:"metadata,omitempty"`
+}
+
+type yooKassaCaptureRequest struct {
+	Amount yooKassaAmount `json:"amount"`
+}
+
+type yooKassaRefundRequest struct {
+	PaymentID string         `json:"payment_id"`
+	Amount    yooKassaAmount `json:"amount"`
+}
+
+type yooKassaPayment struct {
+	ID           string                `json:"id"`
+	Status       string                `json:"status"`
+	Confirmation *yooKassaConfirmation `json:"confirmation,omitempty"`
+}
+
+type yooKassaError struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// formatAmount переводит сумму в копейках в запись ЮKassa вида "1234.50"
+func formatAmount(amount uint, currency string) yooKassaAmount {
+	return yooKassaAmount{
+		Value:    fmt.Sprintf("%d.%02d", amount/100, amount%100),
+		Currency: currency,
+	}
+}
+
+func (g *YooKassaGateway) Name() string {
+	return YooKassaProviderName
+}
+
+func (g *YooKassaGateway) CreatePayment(ctx context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error) {
+	body := yooKassaPaymentRequest{
+		Amount:  formatAmount(req.Amount, req.Currency),
+		Capture: req.Capture,
+		Confirmation: yooKassaConfirmation{
+			Type:      "redirect",
+			ReturnURL: req.ReturnURL,
+		},
+		Description: req.Description,
+		Metadata:    map[string]string{"purchase_id": req.PurchaseID.String()},
+	}
+
+	var payment yooKassaPayment
+	if err := g.do(ctx, "/payments", req.IdempotenceKey, body, &payment); err != nil {
+		return nil, err
+	}
+	return payment.toEntity(), nil
+}
+
+func (g *YooKassaGateway) Capture(ctx context.Context, externalID string, amount uint, currency string) (*entity.GatewayPayment, error) {
+	var payment yooKassaPayment
+	err := g.do(ctx, "/payments/"+externalID+"/capture", externalID+"-capture",
+		yooKassaCaptureRequest{Amount: formatAmount(amount, currency)}, &payment)
+	if err != nil {
+		return nil, err
+	}
+	return payment.toEntity(), nil
+}
+
+func (g *YooKassaGateway) Cancel(ctx context.Context, externalID string) (*entity.GatewayPayment, error) {
+	var payment yooKassaPayment
+	if err := g.do(ctx, "/payments/"+externalID+"/cancel", externalID+"-cancel", struct{}{}, &payment); err != nil {
+		return nil, err
+	}
+	return payment.toEntity(), nil
+}
+
+func (g *YooKassaGateway) Refund(ctx context.Context, externalID string, amount uint, currency string) error {
+	return g.do(ctx, "/refunds", externalID+"-refund", yooKassaRefundRequest{
+		PaymentID: externalID,
+		Amount:    formatAmount(amount, currency),
+	}, nil)
+}
+
+func (g *YooKassaGateway) ParseWebhook(payload []byte, signature string) (*entity.PaymentEvent, error) {
+	return parseNotification(g.webhookSecret, payload, signature)
+}
+
+func (p *yooKassaPayment) toEntity() *entity.GatewayPayment {
+	payment := &entity.GatewayPayment{
+		ExternalID: p.ID,
+		Status:     entity.PaymentStatus(p.Status),
+	}
+	if p.Confirmation != nil {
+		payment.ConfirmationURL = p.Confirmation.ConfirmationURL
+	}
+	return payment
+}
+
+// do отправляет POST-запрос к API и разбирает ответ в out, если он передан
+func (g *YooKassaGateway) do(ctx context.Context, path, idempotenceKey string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return entity.GatewayWrap(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.apiURL+path, bytes.NewReader(body))
+	if err != nil {
+		return entity.GatewayWrap(err)
+	}
+	req.SetBasicAuth(g.shopID, g.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotence-Key", idempotenceKey)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		g.logger.Error("yookassa request failed", zap.String("path", path), zap.Error(err))
+		return entity.GatewayWrap(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr yooKassaError
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		g.logger.Error("yookassa returned an error", zap.String("path", path), zap.Int("status", resp.StatusCode),
+			zap.String("code", apiErr.Code), zap.String("description", apiErr.Description))
+		if resp.StatusCode == http.StatusNotFound {
+			return repository.ErrGatewayPaymentNotFound
+		}
+		if resp.StatusCode == http.StatusBadRequest && apiErr.Code == "invalid_request" {
+			return entity.GatewayWrap(repository.ErrGatewayPaymentState,
+				fmt.Errorf("yookassa: %s", apiErr.Description))
+		}
+		return entity.GatewayWrap(fmt.Errorf("yookassa: status %d: %s %s", resp.StatusCode, apiErr.Code, apiErr.Description))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return entity.GatewayWrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package paymentgateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// yooKassaServer отвечает как API ЮKassa и передает разобранные запросы в handle
+func yooKassaServer(t *testing.T, handle func(w http.ResponseWriter, r *http.Request, body map[string]interface{})) *YooKassaGateway {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shopID, secretKey, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "shop", shopID)
+		assert.Equal(t, "secret", secretKey)
+		assert.NotEmpty(t, r.Header.Get("Idempotence-Key"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		handle(w, r, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewYooKassaGateway(server.URL+"/v3/", "shop", "secret", testWebhookSecret, time.Second, zap.NewNop())
+}
+
+func TestYooKassaGateway_CreatePayment(t *testing.T) {
+	gateway := yooKassaServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		assert.Equal(t, "/v3/payments", r.URL.Path)
+		assert.Equal(t, "purchase-1", r.Header.Get("Idempotence-Key"))
+		assert.Equal(t, map[string]interface{}{"value": "1234.50", "currency": "RUB"}, body["amount"])
+		assert.Equal(t, false, body["capture"])
+		assert.Equal(t, "http://localhost/return", body["confirmation"].(map[string]interface{})["return_url"])
+
+		_, _ = w.Write([]byte(`{"id":"pay-1","status":"pending",
+			"confirmation":{"type":"redirect","confirmation_url":"https://yoomoney.ru/checkout/pay-1"}}`))
+	})
+
+	payment, err := gateway.CreatePayment(context.Background(), entity.GatewayPaymentRequest{
+		Amount:         123450,
+		Currency:       "RUB",
+		ReturnURL:      "http://localhost/return",
+		IdempotenceKey: "purchase-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entity.GatewayPayment{
+		ExternalID:      "pay-1",
+		Status:          entity.PaymentStatusPending,
+		ConfirmationURL: "https://yoomoney.ru/checkout/pay-1",
+	}, *payment)
+}
+
+func TestYooKassaGateway_Capture(t *testing.T) {
+	gateway := yooKassaServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		assert.Equal(t, "/v3/payments/pay-1/capture", r.URL.Path)
+		assert.Equal(t, map[string]interface{}{"value": "5.07", "currency": "RUB"}, body["amount"])
+		_, _ = w.Write([]byte(`{"id":"pay-1","status":"succeeded"}`))
+	})
+
+	payment, err := gateway.Capture(context.Background(), "pay-1", 507, "RUB")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusSucceeded, payment.Status)
+}
+
+func TestYooKassaGateway_Cancel(t *testing.T) {
+	gateway := yooKassaServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		assert.Equal(t, "/v3/payments/pay-1/cancel", r.URL.Path)
+		assert.Equal(t, "pay-1-cancel", r.Header.Get("Idempotence-Key"))
+		_, _ = w.Write([]byte(`{"id":"pay-1","status":"canceled"}`))
+	})
+
+	payment, err := gateway.Cancel(context.Background(), "pay-1")
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusCanceled, payment.Status)
+}
+
+func TestYooKassaGateway_Refund(t *testing.T) {
+	gateway := yooKassaServer(t, func(w http.ResponseWriter, r *http.Request, body map[string]interface{}) {
+		assert.Equal(t, "/v3/refunds", r.URL.Path)
+		assert.Equal(t, "pay-1", body["payment_id"])
+		_, _ = w.Write([]byte(`{"id":"ref-1","status":"succeeded","payment_id":"pay-1"}`))
+	})
+
+	assert.NoError(t, gateway.Refund(context.Background(), "pay-1", 100, "RUB"))
+}
+
+func TestYooKassaGateway_Errors(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		gateway := yooKassaServer(t, func(w http.ResponseWriter, _ *http.Request, _ map[string]interface{}) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"error","code":"not_found","description":"Payment doesn't exist"}`))
+		})
+
+		_, err := gateway.Capture(context.Background(), "missing", 100, "RUB")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentNotFound)
+	})
+
+	t.Run("InvalidState", func(t *testing.T) {
+		gateway := yooKassaServer(t, func(w http.ResponseWriter, _ *http.Request, _ map[string]interface{}) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"type":"error","code":"invalid_request","description":"Payment is not waiting for capture"}`))
+		})
+
+		_, err := gateway.Capture(context.Background(), "pay-1", 100, "RUB")
+		assert.ErrorIs(t, err, repository.ErrGatewayPaymentState)
+		assert.ErrorIs(t, err, entity.ErrGateway)
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		gateway := yooKassaServer(t, func(w http.ResponseWriter, _ *http.Request, _ map[string]interface{}) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		err := gateway.Refund(context.Background(), "pay-1", 100, "RUB")
+		assert.ErrorIs(t, err, entity.ErrGateway)
+	})
+}
+
+func TestYooKassaGateway_ParseWebhook(t *testing.T) {
+	gateway := NewYooKassaGateway("http://localhost", "shop", "secret", testWebhookSecret, time.Second, zap.NewNop())
+	payload := []byte(`{"type":"notification","event":"payment.waiting_for_capture","object":{"id":"pay-1","status":"waiting_for_capture"}}`)
+
+	event, err := gateway.ParseWebhook(payload, Sign(testWebhookSecret, payload))
+	require.NoError(t, err)
+	assert.Equal(t, entity.PaymentEvent{ExternalID: "pay-1", Status: entity.PaymentStatusWaitingForCapture}, *event)
+
+	_, err = gateway.ParseWebhook(payload, Sign("other", payload))
+	assert.ErrorIs(t, err, repository.ErrGatewayInvalidSignature)
+}
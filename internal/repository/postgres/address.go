@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	addressColumns = `id, user_id, recipient_name, phone, city, street, building, apartment, postcode,
+		is_default, created_at, updated_at`
+
+	// Адрес не добавляется, если у пользователя уже $10 адресов
+	insertAddressQuery = `
+		INSERT INTO user_address (user_id, recipient_name, phone, city, street, building, apartment, postcode, is_default)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8,
+			$9 OR NOT EXISTS (SELECT 1 FROM user_address WHERE user_id = $1)
+		WHERE (SELECT COUNT(*) FROM user_address WHERE user_id = $1) < $10
+		RETURNING ` + addressColumns
+
+	selectAddressByIDQuery = `
+		SELECT ` + addressColumns + `
+		FROM user_address
+		WHERE id = $1 AND user_id = $2`
+
+	selectAddressesByUserIDQuery = `
+		SELECT ` + addressColumns + `
+		FROM user_address
+		WHERE user_id = $1
+		ORDER BY is_default DESC, created_at DESC, id`
+
+	updateAddressQuery = `
+		UPDATE user_address
+		SET recipient_name = $3, phone = $4, city = $5, street = $6, building = $7, apartment = $8, postcode = $9,
+			is_default = is_default OR $10
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + addressColumns
+
+	deleteAddressQuery = `
+		DELETE FROM user_address
+		WHERE id = $1 AND user_id = $2
+		RETURNING is_default`
+
+	clearDefaultAddressQuery = `
+		UPDATE user_address
+		SET is_default = FALSE
+		WHERE user_id = $1 AND is_default`
+
+	setDefaultAddressQuery = `
+		UPDATE user_address
+		SET is_default = TRUE
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + addressColumns
+
+	setNewestDefaultAddressQuery = `
+		UPDATE user_address
+		SET is_default = TRUE
+		WHERE id = (
+			SELECT id FROM user_address
+			WHERE user_id = $1
+			ORDER BY created_at DESC, id
+			LIMIT 1
+		)`
+
+	selectPurchaseAddressesQuery = `
+		SELECT purchase_id, address_id, recipient_name, phone, city, street, building, apartment, postcode
+		FROM purchase_address
+		WHERE purchase_id = ANY($1)`
+)
+
+type AddressDB struct {
+	DB      DBExecutor
+	timeout time.Duration
+}
+
+func NewAddressRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Duration) (repository.AddressRepository, error) {
+	if err := db.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &AddressDB{
+		DB:      db,
+		timeout: timeout,
+	}, nil
+}
+
+func (r *AddressDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return tx, nil
+}
+
+func scanAddress(row pgx.Row) (*entity.Address, error) {
+	var address entity.Address
+	err := row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.RecipientName,
+		&address.Phone,
+		&address.City,
+		&address.Street,
+		&address.Building,
+		&address.Apartment,
+		&address.Postcode,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *AddressDB) Add(ctx context.Context, tx pgx.Tx, address *entity.Address, limit int) (*entity.Address, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("adding address to db", zap.String("user_id", address.UserID.String()))
+
+	added, err := scanAddress(tx.QueryRow(ctx, insertAddressQuery, address.UserID, address.RecipientName, address.Phone,
+		address.City, address.Street, address.Building, address.Apartment, address.Postcode, address.IsDefault, limit))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrAddressLimit
+	case err != nil:
+		logger.Error("failed to add address", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return added, nil
+}
+
+func (r *AddressDB) GetById(ctx context.Context, addressID, userID uuid.UUID) (*entity.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting address by id from db", zap.String("address_id", addressID.String()))
+
+	address, err := scanAddress(r.DB.QueryRow(ctx, selectAddressByIDQuery, addressID, userID))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrAddressNotFound
+	case err != nil:
+		logger.Error("failed to get address", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return address, nil
+}
+
+func (r *AddressDB) GetByUserId(ctx context.Context, userID uuid.UUID) ([]*entity.Address, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting addresses by user id from db", zap.String("user_id", userID.String()))
+
+	rows, err := r.DB.Query(ctx, selectAddressesByUserIDQuery, userID)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var addresses []*entity.Address
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return addresses, nil
+}
+
+func (r *AddressDB) Update(ctx context.Context, tx pgx.Tx, address *entity.Address) (*entity.Address, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("updating address in db", zap.String("address_id", address.ID.String()))
+
+	updated, err := scanAddress(tx.QueryRow(ctx, updateAddressQuery, address.ID, address.UserID, address.RecipientName,
+		address.Phone, address.City, address.Street, address.Building, address.Apartment, address.Postcode, address.IsDefault))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrAddressNotFound
+	case err != nil:
+		logger.Error("failed to update address", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return updated, nil
+}
+
+func (r *AddressDB) Delete(ctx context.Context, tx pgx.Tx, addressID, userID uuid.UUID) (bool, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("deleting address from db", zap.String("address_id", addressID.String()))
+
+	var wasDefault bool
+	err := tx.QueryRow(ctx, deleteAddressQuery, addressID, userID).Scan(&wasDefault)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return false, repository.ErrAddressNotFound
+	case err != nil:
+		logger.Error("failed to delete address", zap.Error(err))
+		return false, entity.PSQLWrap(err)
+	}
+	return wasDefault, nil
+}
+
+func (r *AddressDB) ClearDefault(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("clearing default address in db", zap.String("user_id", userID.String()))
+
+	if _, err := tx.Exec(ctx, clearDefaultAddressQuery, userID); err != nil {
+		logger.Error("failed to clear default address", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+	return nil
+}
+
+func (r *AddressDB) SetDefault(ctx context.Context, tx pgx.Tx, addressID, userID uuid.UUID) (*entity.Address, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("setting default address in db", zap.String("address_id", addressID.String()))
+
+	address, err := scanAddress(tx.QueryRow(ctx, setDefaultAddressQuery, addressID, userID))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrAddressNotFound
+	case err != nil:
+		logger.Error("failed to set default address", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return address, nil
+}
+
+func (r *AddressDB) SetNewestDefault(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("setting newest address as default in db", zap.String("user_id", userID.String()))
+
+	if _, err := tx.Exec(ctx, setNewestDefaultAddressQuery, userID); err != nil {
+		logger.Error("failed to set newest default address", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+	return nil
+}
+
+func (r *AddressDB) GetPurchaseSnapshots(ctx context.Context, purchaseIDs []uuid.UUID) ([]*entity.PurchaseAddress, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting purchase addresses from db", zap.Int("purchases", len(purchaseIDs)))
+
+	rows, err := r.DB.Query(ctx, selectPurchaseAddressesQuery, purchaseIDs)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var snapshots []*entity.PurchaseAddress
+	for rows.Next() {
+		var snapshot entity.PurchaseAddress
+		if err := rows.Scan(&snapshot.PurchaseID, &snapshot.AddressID, &snapshot.RecipientName, &snapshot.Phone,
+			&snapshot.City, &snapshot.Street, &snapshot.Building, &snapshot.Apartment, &snapshot.Postcode); err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		snapshots = append(snapshots, &snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return snapshots, nil
+}
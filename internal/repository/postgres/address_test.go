@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAddressTest(t *testing.T) (pgxmock.PgxPoolIface, *AddressDB) {
+	mockPool, adapter := setupMockDB(t)
+	t.Cleanup(func() {
+		mockPool.Close()
+	})
+	return mockPool, &AddressDB{DB: adapter, timeout: 10 * time.Second}
+}
+
+var addressRowColumns = []string{"id", "user_id", "recipient_name", "phone", "city", "street", "building", "apartment",
+	"postcode", "is_default", "created_at", "updated_at"}
+
+func addressRow(mockPool pgxmock.PgxPoolIface, address *entity.Address) *pgxmock.Rows {
+	now := time.Now()
+	return mockPool.NewRows(addressRowColumns).AddRow(address.ID, address.UserID, address.RecipientName, address.Phone,
+		address.City, address.Street, address.Building, address.Apartment, address.Postcode, address.IsDefault, now, now)
+}
+
+func testAddress() *entity.Address {
+	return &entity.Address{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		AddressFields: entity.AddressFields{
+			RecipientName: "Иван Иванов",
+			Phone:         "+79991234567",
+			City:          "Москва",
+			Street:        "Тверская ул.",
+			Building:      "1",
+			Apartment:     "5",
+			Postcode:      "125009",
+		},
+	}
+}
+
+func TestAddressDB_Add(t *testing.T) {
+	mockPool, repo := setupAddressTest(t)
+	address := testAddress()
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		added := *address
+		added.IsDefault = true
+		mockPool.ExpectQuery("INSERT INTO user_address").
+			WithArgs(address.UserID, address.RecipientName, address.Phone, address.City, address.Street,
+				address.Building, address.Apartment, address.Postcode, false, entity.MaxUserAddresses).
+			WillReturnRows(addressRow(mockPool, &added))
+
+		result, err := repo.Add(context.Background(), tx, address, entity.MaxUserAddresses)
+		require.NoError(t, err)
+		assert.Equal(t, address.ID, result.ID)
+		assert.True(t, result.IsDefault)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		mockPool.ExpectQuery("INSERT INTO user_address").
+			WithArgs(address.UserID, address.RecipientName, address.Phone, address.City, address.Street,
+				address.Building, address.Apartment, address.Postcode, false, entity.MaxUserAddresses).
+			WillReturnRows(mockPool.NewRows(addressRowColumns))
+
+		_, err := repo.Add(context.Background(), tx, address, entity.MaxUserAddresses)
+		assert.ErrorIs(t, err, repository.ErrAddressLimit)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockPool.ExpectQuery("INSERT INTO user_address").
+			WithArgs(address.UserID, address.RecipientName, address.Phone, address.City, address.Street,
+				address.Building, address.Apartment, address.Postcode, false, entity.MaxUserAddresses).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.Add(context.Background(), tx, address, entity.MaxUserAddresses)
+		assert.ErrorIs(t, err, entity.ErrPSQL)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAddressDB_Get(t *testing.T) {
+	mockPool, repo := setupAddressTest(t)
+	address := testAddress()
+
+	t.Run("ById", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM user_address WHERE id = \\$1 AND user_id = \\$2").
+			WithArgs(address.ID, address.UserID).
+			WillReturnRows(addressRow(mockPool, address))
+
+		result, err := repo.GetById(context.Background(), address.ID, address.UserID)
+		require.NoError(t, err)
+		assert.Equal(t, address.AddressFields, result.AddressFields)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM user_address WHERE id = \\$1").
+			WithArgs(address.ID, address.UserID).
+			WillReturnRows(mockPool.NewRows(addressRowColumns))
+
+		_, err := repo.GetById(context.Background(), address.ID, address.UserID)
+		assert.ErrorIs(t, err, repository.ErrAddressNotFound)
+	})
+
+	t.Run("ByUserId", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM user_address WHERE user_id = \\$1 ORDER BY is_default DESC").
+			WithArgs(address.UserID).
+			WillReturnRows(addressRow(mockPool, address))
+
+		addresses, err := repo.GetByUserId(context.Background(), address.UserID)
+		require.NoError(t, err)
+		require.Len(t, addresses, 1)
+		assert.Equal(t, address.ID, addresses[0].ID)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAddressDB_UpdateAndDelete(t *testing.T) {
+	mockPool, repo := setupAddressTest(t)
+	address := testAddress()
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	t.Run("Update", func(t *testing.T) {
+		mockPool.ExpectQuery("UPDATE user_address").
+			WithArgs(address.ID, address.UserID, address.RecipientName, address.Phone, address.City, address.Street,
+				address.Building, address.Apartment, address.Postcode, false).
+			WillReturnRows(addressRow(mockPool, address))
+
+		result, err := repo.Update(context.Background(), tx, address)
+		require.NoError(t, err)
+		assert.Equal(t, address.Street, result.Street)
+	})
+
+	t.Run("UpdateNotFound", func(t *testing.T) {
+		mockPool.ExpectQuery("UPDATE user_address").
+			WithArgs(address.ID, address.UserID, address.RecipientName, address.Phone, address.City, address.Street,
+				address.Building, address.Apartment, address.Postcode, false).
+			WillReturnRows(mockPool.NewRows(addressRowColumns))
+
+		_, err := repo.Update(context.Background(), tx, address)
+		assert.ErrorIs(t, err, repository.ErrAddressNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		mockPool.ExpectQuery("DELETE FROM user_address").
+			WithArgs(address.ID, address.UserID).
+			WillReturnRows(mockPool.NewRows([]string{"is_default"}).AddRow(true))
+
+		wasDefault, err := repo.Delete(context.Background(), tx, address.ID, address.UserID)
+		require.NoError(t, err)
+		assert.True(t, wasDefault)
+	})
+
+	t.Run("DeleteNotFound", func(t *testing.T) {
+		mockPool.ExpectQuery("DELETE FROM user_address").
+			WithArgs(address.ID, address.UserID).
+			WillReturnRows(mockPool.NewRows([]string{"is_default"}))
+
+		_, err := repo.Delete(context.Background(), tx, address.ID, address.UserID)
+		assert.ErrorIs(t, err, repository.ErrAddressNotFound)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAddressDB_Default(t *testing.T) {
+	mockPool, repo := setupAddressTest(t)
+	address := testAddress()
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	mockPool.ExpectExec("UPDATE user_address SET is_default = FALSE").
+		WithArgs(address.UserID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	require.NoError(t, repo.ClearDefault(context.Background(), tx, address.UserID))
+
+	address.IsDefault = true
+	mockPool.ExpectQuery("UPDATE user_address SET is_default = TRUE WHERE id = \\$1 AND user_id = \\$2").
+		WithArgs(address.ID, address.UserID).
+		WillReturnRows(addressRow(mockPool, address))
+	result, err := repo.SetDefault(context.Background(), tx, address.ID, address.UserID)
+	require.NoError(t, err)
+	assert.True(t, result.IsDefault)
+
+	mockPool.ExpectExec("ORDER BY created_at DESC").
+		WithArgs(address.UserID).
+		WillReturnError(errors.New("db error"))
+	assert.ErrorIs(t, repo.SetNewestDefault(context.Background(), tx, address.UserID), entity.ErrPSQL)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAddressDB_PurchaseSnapshots(t *testing.T) {
+	mockPool, repo := setupAddressTest(t)
+	address := testAddress()
+	snapshot := &entity.PurchaseAddress{PurchaseID: uuid.New(), AddressID: &address.ID, AddressFields: address.AddressFields}
+
+	purchaseIDs := []uuid.UUID{snapshot.PurchaseID}
+	mockPool.ExpectQuery("FROM purchase_address WHERE purchase_id = ANY\\(\\$1\\)").
+		WithArgs(purchaseIDs).
+		WillReturnRows(mockPool.NewRows([]string{"purchase_id", "address_id", "recipient_name", "phone", "city",
+			"street", "building", "apartment", "postcode"}).
+			AddRow(snapshot.PurchaseID, (*uuid.UUID)(nil), snapshot.RecipientName, snapshot.Phone, snapshot.City,
+				snapshot.Street, snapshot.Building, snapshot.Apartment, snapshot.Postcode))
+
+	snapshots, err := repo.GetPurchaseSnapshots(context.Background(), purchaseIDs)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Nil(t, snapshots[0].AddressID)
+	assert.Equal(t, snapshot.City, snapshots[0].City)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
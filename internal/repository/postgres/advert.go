@@ -16,49 +16,50 @@ import (
 
 type AdvertDB struct {
 	DB      DBExecutor
-	ctx     context.Context
 	timeout time.Duration
 }
 
 const (
 	insertAdvertQuery = `
-		INSERT INTO advert (title, description, price, location, has_delivery, category_id, seller_id, status) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
-		RETURNING id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status`
+		INSERT INTO advert (title, description, price, location, has_delivery, category_id, seller_id, status, publish_at, expires_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
+			COALESCE($9, CURRENT_TIMESTAMP) + COALESCE((SELECT advert_lifetime FROM category WHERE id = $6), INTERVAL '30 days'))
+		RETURNING id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status,
+			created_at, updated_at, expires_at, bumped_at, publish_at, views_count`
 
 	selectAdvertsQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
-		WHERE status != 'inactive'
-		ORDER BY created_at DESC
+		WHERE status NOT IN ('inactive', 'draft', 'scheduled')
+		ORDER BY bumped_at DESC
 		LIMIT $1 OFFSET $2`
 
 	selectSavedAdvertsByUserIdQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
 		WHERE id IN (SELECT advert_id FROM saved_advert WHERE user_id = $1)
 		ORDER BY created_at DESC`
 
 	selectAdvertsBySellerIdQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
-		WHERE seller_id = $1 AND status != 'inactive'
+		WHERE seller_id = $1 AND status NOT IN ('inactive', 'draft', 'scheduled')
 		ORDER BY created_at DESC`
 
 	selectAdvertsByUserIdQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
 		WHERE seller_id = $1
 		ORDER BY created_at DESC`
 
 	selectAdvertsByCartIdQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
 		WHERE id IN (SELECT advert_id FROM cart_advert WHERE cart_id = $1)
 		ORDER BY created_at DESC`
 
 	selectAdvertByIdQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
 		WHERE id = $1
 		ORDER BY created_at DESC`
@@ -66,8 +67,8 @@ const (
 	updateAdvertQuery = `
 		UPDATE advert
 		SET title = $1, description = $2, price = $3, location = $4, has_delivery = $5,
-				category_id = $6, status = $7
-		WHERE id = $8`
+				category_id = $6, status = $7, publish_at = $8
+		WHERE id = $9 AND status = $10`
 
 	deleteAdvertByIdQuery = `DELETE FROM advert WHERE id = $1`
 
@@ -77,15 +78,19 @@ const (
 		WHERE id = $2`
 
 	selectAdvertsByCategoryIdQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
-		WHERE category_id = $1 AND status != 'inactive'
-		ORDER BY created_at DESC`
+		WHERE category_id = $1 AND status NOT IN ('inactive', 'draft', 'scheduled')
+		ORDER BY bumped_at DESC`
 
 	uploadImageQuery = `
 		UPDATE advert
 		SET image_id = $1
-		WHERE id = $2`
+		WHERE id = $2
+			AND NOT EXISTS (SELECT 1 FROM static s WHERE s.id = $1 AND s.moderation_status = 'flagged')`
+
+	selectAdvertImageFlaggedQuery = `
+		SELECT EXISTS (SELECT 1 FROM static WHERE id = $1 AND moderation_status = 'flagged')`
 
 	insertSavedAdvertQuery = `
 		INSERT INTO saved_advert (user_id, advert_id)
@@ -100,26 +105,118 @@ const (
 		SELECT COUNT(*), EXISTS(SELECT 1 FROM saved_advert WHERE advert_id = $1 AND user_id = $2) 
 		FROM saved_advert WHERE advert_id = $1`
 
-	insertViewedAdvertQuery = `
-		INSERT INTO viewed_advert (advert_id, user_id)
-		VALUES ($1, $2)
-		RETURNING id, user_id, advert_id, created_at`
+	insertViewedAdvertsQuery = `
+		INSERT INTO viewed_advert (advert_id, user_id, visitor_id, created_at)
+		SELECT v.advert_id, NULLIF(v.user_id, '')::uuid, NULLIF(v.visitor_id, ''), v.created_at
+		FROM unnest($1::uuid[], $2::text[], $3::text[], $4::timestamp[]) AS v(advert_id, user_id, visitor_id, created_at)
+		JOIN advert a ON a.id = v.advert_id
+		WHERE v.user_id = '' OR EXISTS(SELECT 1 FROM "user" u WHERE u.id = NULLIF(v.user_id, '')::uuid)`
+
+	insertViewBatchQuery = `
+		INSERT INTO advert_view_batch (id) VALUES ($1)
+		ON CONFLICT (id) DO NOTHING`
+
+	incrementViewsCountQuery = `
+		UPDATE advert a
+		SET views_count = a.views_count + v.views
+		FROM unnest($1::uuid[], $2::int[]) AS v(advert_id, views)
+		WHERE a.id = v.advert_id`
 
-	selectViewedCountAndIsViewedQuery = `
-		SELECT COUNT(*), EXISTS(SELECT 1 FROM viewed_advert WHERE advert_id = $1 AND user_id = $2) 
-		FROM viewed_advert WHERE advert_id = $1`
+	selectIsViewedQuery = `
+		SELECT EXISTS(SELECT 1 FROM viewed_advert WHERE advert_id = $1 AND user_id = $2)`
 
 	checkIfExistsQuery = `
 		SELECT EXISTS(SELECT 1 FROM advert WHERE id = $1)`
 
 	searchAdvertsQuery = `
-		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at
+		SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count
 		FROM advert
 		WHERE to_tsvector('russian', title || ' ' || description) @@ plainto_tsquery('russian', $1)
+			AND status NOT IN ('draft', 'scheduled')
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3`
 
-	countAdvertsQuery = `SELECT COUNT(*) FROM advert`
+	countAdvertsQuery = `SELECT COUNT(*) FROM advert WHERE status NOT IN ('draft', 'scheduled')`
+
+	renewAdvertQuery = `
+		UPDATE advert
+		SET status = 'active', expiry_reminded_at = NULL,
+			expires_at = CURRENT_TIMESTAMP + COALESCE((SELECT c.advert_lifetime FROM category c WHERE c.id = advert.category_id), INTERVAL '30 days')
+		WHERE id = $1 AND status IN ('active', 'inactive')`
+
+	bumpAdvertQuery = `
+		UPDATE advert
+		SET bumped_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'active' AND bumped_at <= CURRENT_TIMESTAMP - $2::interval`
+
+	expireAdvertsQuery = `
+		UPDATE advert
+		SET status = 'inactive'
+		WHERE status = 'active' AND expires_at <= CURRENT_TIMESTAMP`
+
+	selectExpiringAdvertsQuery = `
+		SELECT id, title, seller_id, expires_at
+		FROM advert
+		WHERE status = 'active' AND expiry_reminded_at IS NULL
+			AND expires_at <= CURRENT_TIMESTAMP + $1::interval`
+
+	markAdvertRemindedQuery = `
+		UPDATE advert
+		SET expiry_reminded_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND expiry_reminded_at IS NULL`
+
+	publishAdvertQuery = `
+		UPDATE advert
+		SET status = 'active', publish_at = NULL, bumped_at = CURRENT_TIMESTAMP, expiry_reminded_at = NULL,
+			expires_at = CURRENT_TIMESTAMP + COALESCE((SELECT c.advert_lifetime FROM category c WHERE c.id = advert.category_id), INTERVAL '30 days')
+		WHERE id = $1 AND status IN ('draft', 'scheduled')`
+
+	scheduleAdvertQuery = `
+		UPDATE advert
+		SET status = 'scheduled', publish_at = $2
+		WHERE id = $1 AND status IN ('draft', 'scheduled')`
+
+	publishScheduledAdvertsQuery = `
+		UPDATE advert
+		SET status = 'active', bumped_at = publish_at, publish_at = NULL, expiry_reminded_at = NULL,
+			expires_at = publish_at + COALESCE((SELECT c.advert_lifetime FROM category c WHERE c.id = advert.category_id), INTERVAL '30 days')
+		WHERE status = 'scheduled' AND publish_at <= CURRENT_TIMESTAMP`
+
+	upsertAdvertBySKUQuery = `
+		INSERT INTO advert (title, description, price, location, has_delivery, category_id, seller_id, status, external_sku, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9,
+			CURRENT_TIMESTAMP + COALESCE((SELECT advert_lifetime FROM category WHERE id = $6), INTERVAL '30 days'))
+		ON CONFLICT (seller_id, external_sku) WHERE external_sku IS NOT NULL
+		DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description, price = EXCLUDED.price,
+			location = EXCLUDED.location, has_delivery = EXCLUDED.has_delivery, category_id = EXCLUDED.category_id,
+			status = EXCLUDED.status, updated_at = CURRENT_TIMESTAMP,
+			expires_at = CASE WHEN advert.status <> 'active' AND EXCLUDED.status = 'active'
+				THEN EXCLUDED.expires_at ELSE advert.expires_at END,
+			expiry_reminded_at = CASE WHEN advert.status <> 'active' AND EXCLUDED.status = 'active'
+				THEN NULL ELSE advert.expiry_reminded_at END
+		WHERE advert.status NOT IN ('reserved', 'scheduled')
+		RETURNING id, status, created_at, updated_at, expires_at, bumped_at, (xmax = 0) AS inserted`
+
+	selectAdvertsForExportQuery = `
+		SELECT id, COALESCE(external_sku, ''), title, description, price, location, has_delivery, category_id, status
+		FROM advert
+		WHERE seller_id = $1
+		ORDER BY created_at`
+
+	selectAdvertSaleStateQuery = `
+		SELECT a.id, a.status, s.user_id
+		FROM advert a
+		JOIN seller s ON s.id = a.seller_id
+		WHERE a.id = $1`
+
+	// Строки блокируются в порядке id, чтобы встречные покупки не взаимоблокировались
+	lockAdvertsByCartIdQuery = `
+		SELECT a.id, a.status, s.user_id
+		FROM advert a
+		JOIN seller s ON s.id = a.seller_id
+		WHERE a.id IN (SELECT advert_id FROM cart_advert WHERE cart_id = $1)
+		ORDER BY a.id
+		FOR UPDATE OF a`
 )
 
 type AdvertRepoModel struct {
@@ -135,6 +232,10 @@ type AdvertRepoModel struct {
 	Location    string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	ExpiresAt   time.Time
+	BumpedAt    time.Time
+	PublishAt   *time.Time
+	ViewsCount  uint
 }
 
 type SavedAdvertRepoModel struct {
@@ -150,19 +251,18 @@ func NewAdvertRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Dur
 	}
 	return &AdvertDB{
 		DB:      db,
-		ctx:     ctx,
 		timeout: timeout,
 	}, nil
 }
 
-func (r *AdvertDB) getSavedCount(advertId uuid.UUID, userId uuid.UUID) (int, bool, error) {
+func (r *AdvertDB) getSavedCount(ctx context.Context, advertId uuid.UUID, userId uuid.UUID) (int, bool, error) {
 	var savedCount int
 	isSaved := false
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting saved count from db", zap.String("advert_id", advertId.String()), zap.String("user_id", userId.String()))
 	err := r.DB.QueryRow(ctx, selectSavedCountAndIsSavedQuery, advertId, userId).Scan(&savedCount, &isSaved)
 	if err != nil {
@@ -173,38 +273,40 @@ func (r *AdvertDB) getSavedCount(advertId uuid.UUID, userId uuid.UUID) (int, boo
 	return savedCount, isSaved, nil
 }
 
-func (r *AdvertDB) getViewedCount(advertId uuid.UUID, userId uuid.UUID) (int, bool, error) {
-	var viewedCount int
+// isViewed проверяет, просматривал ли пользователь объявление. Для анонимных посетителей запрос не выполняется
+func (r *AdvertDB) isViewed(ctx context.Context, advertId uuid.UUID, userId uuid.UUID) (bool, error) {
 	isViewed := false
+	if userId == uuid.Nil {
+		return isViewed, nil
+	}
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
-	logger.Info("getting viewed count from db", zap.String("advert_id", advertId.String()), zap.String("user_id", userId.String()))
+	logger := middleware.GetLogger(ctx)
+	logger.Info("checking if advert is viewed in db", zap.String("advert_id", advertId.String()), zap.String("user_id", userId.String()))
 
-	err := r.DB.QueryRow(ctx, selectViewedCountAndIsViewedQuery, advertId, userId).Scan(&viewedCount, &isViewed)
+	err := r.DB.QueryRow(ctx, selectIsViewedQuery, advertId, userId).Scan(&isViewed)
 	if err != nil {
 		logger.Error("failed to execute query", zap.Error(err), zap.String("advert_id", advertId.String()), zap.String("user_id", userId.String()))
-		return 0, false, err
+		return false, err
 	}
 
-	return viewedCount, isViewed, nil
+	return isViewed, nil
 }
 
-func (r *AdvertDB) convertToEntityAdvert(dbAdvert AdvertRepoModel, userId uuid.UUID) *entity.Advert {
-	savedCount, isSaved, err := r.getSavedCount(dbAdvert.ID, userId)
-	logger := middleware.GetLogger(r.ctx)
+func (r *AdvertDB) convertToEntityAdvert(ctx context.Context, dbAdvert AdvertRepoModel, userId uuid.UUID) *entity.Advert {
+	savedCount, isSaved, err := r.getSavedCount(ctx, dbAdvert.ID, userId)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting saved count", zap.String("advert_id", dbAdvert.ID.String()), zap.String("user_id", userId.String()))
 	if err != nil {
 		logger.Error("failed to get saved count", zap.Error(err), zap.String("advert_id", dbAdvert.ID.String()), zap.String("user_id", userId.String()))
 		return nil
 	}
 
-	logger.Info("getting viewed count", zap.String("advert_id", dbAdvert.ID.String()), zap.String("user_id", userId.String()))
-	viewedCount, isViewed, err := r.getViewedCount(dbAdvert.ID, userId)
+	isViewed, err := r.isViewed(ctx, dbAdvert.ID, userId)
 	if err != nil {
-		logger.Error("failed to get viewed count", zap.Error(err), zap.String("advert_id", dbAdvert.ID.String()), zap.String("user_id", userId.String()))
+		logger.Error("failed to check if advert is viewed", zap.Error(err), zap.String("advert_id", dbAdvert.ID.String()), zap.String("user_id", userId.String()))
 		return nil
 	}
 
@@ -221,20 +323,23 @@ func (r *AdvertDB) convertToEntityAdvert(dbAdvert AdvertRepoModel, userId uuid.U
 		Status:      entity.AdvertStatus(dbAdvert.Status),
 		CreatedAt:   dbAdvert.CreatedAt,
 		UpdatedAt:   dbAdvert.UpdatedAt,
+		ExpiresAt:   dbAdvert.ExpiresAt,
+		BumpedAt:    dbAdvert.BumpedAt,
+		PublishAt:   dbAdvert.PublishAt,
 		IsSaved:     isSaved,
 		IsViewed:    isViewed,
-		ViewsNumber: uint(viewedCount),
+		ViewsNumber: dbAdvert.ViewsCount,
 		SavesNumber: uint(savedCount),
 	}
 }
 
-func (r *AdvertDB) Add(a *entity.Advert) (*entity.Advert, error) {
+func (r *AdvertDB) Add(ctx context.Context, a *entity.Advert) (*entity.Advert, error) {
 	var dbAdvert AdvertRepoModel
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("adding advert to db", zap.Any("advert", a))
 
 	err := r.DB.QueryRow(ctx, insertAdvertQuery,
@@ -243,9 +348,10 @@ func (r *AdvertDB) Add(a *entity.Advert) (*entity.Advert, error) {
 		a.Price,
 		a.Location,
 		a.HasDelivery,
-		a.CategoryId,
+		nullableUUID(a.CategoryId),
 		a.SellerId,
-		string(a.Status)).Scan(
+		string(a.Status),
+		a.PublishAt).Scan(
 		&dbAdvert.ID,
 		&dbAdvert.Title,
 		&dbAdvert.Description,
@@ -256,6 +362,12 @@ func (r *AdvertDB) Add(a *entity.Advert) (*entity.Advert, error) {
 		&dbAdvert.SellerId,
 		&dbAdvert.ImageId,
 		&dbAdvert.Status,
+		&dbAdvert.CreatedAt,
+		&dbAdvert.UpdatedAt,
+		&dbAdvert.ExpiresAt,
+		&dbAdvert.BumpedAt,
+		&dbAdvert.PublishAt,
+		&dbAdvert.ViewsCount,
 	)
 
 	if err != nil {
@@ -276,16 +388,21 @@ func (r *AdvertDB) Add(a *entity.Advert) (*entity.Advert, error) {
 		SellerId:    dbAdvert.SellerId,
 		ImageId:     dbAdvert.ImageId,
 		Status:      entity.AdvertStatus(dbAdvert.Status),
+		CreatedAt:   dbAdvert.CreatedAt,
+		UpdatedAt:   dbAdvert.UpdatedAt,
+		ExpiresAt:   dbAdvert.ExpiresAt,
+		BumpedAt:    dbAdvert.BumpedAt,
+		PublishAt:   dbAdvert.PublishAt,
 	}, nil
 }
 
-func (r *AdvertDB) Get(limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) Get(ctx context.Context, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting adverts from db", zap.Int("limit", limit), zap.Int("offset", offset))
 
 	rows, err := r.DB.Query(ctx, selectAdvertsQuery, limit, offset)
@@ -309,11 +426,15 @@ func (r *AdvertDB) Get(limit, offset int, userId uuid.UUID) ([]*entity.Advert, e
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -324,13 +445,13 @@ func (r *AdvertDB) Get(limit, offset int, userId uuid.UUID) ([]*entity.Advert, e
 	return adverts, nil
 }
 
-func (r *AdvertDB) GetByCategoryId(categoryId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) GetByCategoryId(ctx context.Context, categoryId, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting adverts by category id from db", zap.String("category_id", categoryId.String()))
 
 	rows, err := r.DB.Query(ctx, selectAdvertsByCategoryIdQuery, categoryId)
@@ -354,11 +475,15 @@ func (r *AdvertDB) GetByCategoryId(categoryId, userId uuid.UUID) ([]*entity.Adve
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err), zap.String("category_id", categoryId.String()))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -369,13 +494,13 @@ func (r *AdvertDB) GetByCategoryId(categoryId, userId uuid.UUID) ([]*entity.Adve
 	return adverts, nil
 }
 
-func (r *AdvertDB) GetBySellerId(sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) GetBySellerId(ctx context.Context, sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting adverts by seller id from db", zap.String("seller_id", sellerId.String()))
 
 	rows, err := r.DB.Query(ctx, selectAdvertsBySellerIdQuery, sellerId)
@@ -400,11 +525,15 @@ func (r *AdvertDB) GetBySellerId(sellerId, userId uuid.UUID) ([]*entity.Advert,
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err), zap.String("seller_id", sellerId.String()))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -415,13 +544,13 @@ func (r *AdvertDB) GetBySellerId(sellerId, userId uuid.UUID) ([]*entity.Advert,
 	return adverts, nil
 }
 
-func (r *AdvertDB) GetByCartId(cartId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) GetByCartId(ctx context.Context, cartId, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting adverts by cart id from db", zap.String("cart_id", cartId.String()))
 
 	rows, err := r.DB.Query(ctx, selectAdvertsByCartIdQuery, cartId)
@@ -446,11 +575,15 @@ func (r *AdvertDB) GetByCartId(cartId, userId uuid.UUID) ([]*entity.Advert, erro
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err), zap.String("cart_id", cartId.String()))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -461,11 +594,11 @@ func (r *AdvertDB) GetByCartId(cartId, userId uuid.UUID) ([]*entity.Advert, erro
 	return adverts, nil
 }
 
-func (r *AdvertDB) BeginTransaction() (pgx.Tx, error) {
-	logger := middleware.GetLogger(r.ctx)
+func (r *AdvertDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("beginning transaction")
 
-	tx, err := r.DB.Begin(r.ctx)
+	tx, err := r.DB.Begin(ctx)
 	if err != nil {
 		logger.Error("failed to begin transaction", zap.Error(err))
 		return nil, err
@@ -473,13 +606,13 @@ func (r *AdvertDB) BeginTransaction() (pgx.Tx, error) {
 	return tx, nil
 }
 
-func (r *AdvertDB) GetById(advertId, userId uuid.UUID) (*entity.Advert, error) {
+func (r *AdvertDB) GetById(ctx context.Context, advertId, userId uuid.UUID) (*entity.Advert, error) {
 	var dbAdvert AdvertRepoModel
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting advert by id from db", zap.String("advert_id", advertId.String()))
 
 	err := r.DB.QueryRow(ctx, selectAdvertByIdQuery, advertId).Scan(
@@ -495,6 +628,10 @@ func (r *AdvertDB) GetById(advertId, userId uuid.UUID) (*entity.Advert, error) {
 		&dbAdvert.Status,
 		&dbAdvert.CreatedAt,
 		&dbAdvert.UpdatedAt,
+		&dbAdvert.ExpiresAt,
+		&dbAdvert.BumpedAt,
+		&dbAdvert.PublishAt,
+		&dbAdvert.ViewsCount,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -505,14 +642,14 @@ func (r *AdvertDB) GetById(advertId, userId uuid.UUID) (*entity.Advert, error) {
 		return nil, entity.PSQLWrap(err)
 	}
 
-	return r.convertToEntityAdvert(dbAdvert, userId), nil
+	return r.convertToEntityAdvert(ctx, dbAdvert, userId), nil
 }
 
-func (r *AdvertDB) Update(advert *entity.Advert) error {
-	ctx, cancel := context.WithTimeout(r.ctx, 5*time.Minute)
+func (r *AdvertDB) Update(ctx context.Context, advert *entity.Advert, from entity.AdvertStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("updating advert in db", zap.String("advert_id", advert.ID.String()))
 
 	result, err := r.DB.Exec(ctx, updateAdvertQuery,
@@ -521,9 +658,11 @@ func (r *AdvertDB) Update(advert *entity.Advert) error {
 		advert.Price,
 		advert.Location,
 		advert.HasDelivery,
-		advert.CategoryId,
+		nullableUUID(advert.CategoryId),
 		advert.Status,
+		advert.PublishAt,
 		advert.ID,
+		from,
 	)
 	if err != nil {
 		logger.Error("failed to update advert", zap.Error(err), zap.String("advert_id", advert.ID.String()))
@@ -539,11 +678,11 @@ func (r *AdvertDB) Update(advert *entity.Advert) error {
 	return nil
 }
 
-func (r *AdvertDB) DeleteById(advertId uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) DeleteById(ctx context.Context, advertId uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("deleting advert from db", zap.String("advert_id", advertId.String()))
 
 	result, err := r.DB.Exec(ctx, deleteAdvertByIdQuery, advertId)
@@ -561,11 +700,11 @@ func (r *AdvertDB) DeleteById(advertId uuid.UUID) error {
 	return nil
 }
 
-func (r *AdvertDB) UpdateStatus(tx pgx.Tx, advertId uuid.UUID, status entity.AdvertStatus) error {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) UpdateStatus(ctx context.Context, tx pgx.Tx, advertId uuid.UUID, status entity.AdvertStatus) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("updating advert status in db", zap.String("advert_id", advertId.String()))
 
 	result, err := tx.Exec(ctx, updateAdvertStatusQuery, status, advertId)
@@ -583,11 +722,11 @@ func (r *AdvertDB) UpdateStatus(tx pgx.Tx, advertId uuid.UUID, status entity.Adv
 	return nil
 }
 
-func (r *AdvertDB) UploadImage(advertId uuid.UUID, imageId uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) UploadImage(ctx context.Context, advertId uuid.UUID, imageId uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("uploading image to db", zap.String("advert_id", advertId.String()))
 
 	result, err := r.DB.Exec(ctx, uploadImageQuery, imageId, advertId)
@@ -598,6 +737,16 @@ func (r *AdvertDB) UploadImage(advertId uuid.UUID, imageId uuid.UUID) error {
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
+		var flagged bool
+		if err := r.DB.QueryRow(ctx, selectAdvertImageFlaggedQuery, imageId).Scan(&flagged); err != nil {
+			logger.Error("failed to check image moderation", zap.Error(err), zap.String("image_id", imageId.String()))
+			return entity.PSQLWrap(err)
+		}
+		if flagged {
+			logger.Warn("image is held for moderation", zap.String("advert_id", advertId.String()),
+				zap.String("image_id", imageId.String()))
+			return entity.PSQLWrap(repository.ErrAdvertImageFlagged)
+		}
 		logger.Error("advert not found", zap.String("advert_id", advertId.String()))
 		return entity.PSQLWrap(repository.ErrAdvertNotFound)
 	}
@@ -605,13 +754,13 @@ func (r *AdvertDB) UploadImage(advertId uuid.UUID, imageId uuid.UUID) error {
 	return nil
 }
 
-func (r *AdvertDB) AddToSaved(advertId, userId uuid.UUID) error {
+func (r *AdvertDB) AddToSaved(ctx context.Context, advertId, userId uuid.UUID) error {
 	var savedAdvert SavedAdvertRepoModel
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("adding advert to saved in db", zap.String("advert_id", advertId.String()))
 
 	err := r.DB.QueryRow(ctx, insertSavedAdvertQuery, userId, advertId).Scan(
@@ -631,11 +780,11 @@ func (r *AdvertDB) AddToSaved(advertId, userId uuid.UUID) error {
 	return nil
 }
 
-func (r *AdvertDB) DeleteFromSaved(userId uuid.UUID, advertId uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) DeleteFromSaved(ctx context.Context, userId uuid.UUID, advertId uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("deleting advert from saved in db", zap.String("advert_id", advertId.String()))
 
 	result, err := r.DB.Exec(ctx, deleteSavedAdvertQuery, advertId, userId)
@@ -653,34 +802,76 @@ func (r *AdvertDB) DeleteFromSaved(userId uuid.UUID, advertId uuid.UUID) error {
 	return nil
 }
 
-func (r *AdvertDB) AddViewed(userId, advertId uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) FlushViews(ctx context.Context, batchId uuid.UUID, views []entity.AdvertView, counters map[uuid.UUID]uint) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
-	logger.Info("adding viewed advert to db", zap.String("advert_id", advertId.String()))
+	logger := middleware.GetLogger(ctx).With(zap.String("batch_id", batchId.String()))
+	logger.Info("flushing advert views to db", zap.Int("views", len(views)), zap.Int("adverts", len(counters)))
+
+	advertIds := make([]uuid.UUID, 0, len(views))
+	userIds := make([]string, 0, len(views))
+	visitorIds := make([]string, 0, len(views))
+	viewedAt := make([]time.Time, 0, len(views))
+	for _, view := range views {
+		userId := ""
+		if view.UserId != uuid.Nil {
+			userId = view.UserId.String()
+		}
+		advertIds = append(advertIds, view.AdvertId)
+		userIds = append(userIds, userId)
+		visitorIds = append(visitorIds, view.VisitorId)
+		viewedAt = append(viewedAt, view.ViewedAt)
+	}
 
-	var userIdToInsert interface{}
-	if userId == uuid.Nil {
-		userIdToInsert = nil
-	} else {
-		userIdToInsert = userId
+	countedIds := make([]uuid.UUID, 0, len(counters))
+	counts := make([]int, 0, len(counters))
+	for advertId, count := range counters {
+		countedIds = append(countedIds, advertId)
+		counts = append(counts, int(count))
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("failed to begin transaction", zap.Error(err))
+		return entity.PSQLWrap(err)
 	}
+	defer tx.Rollback(ctx)
 
-	_, err := r.DB.Exec(ctx, insertViewedAdvertQuery, advertId, userIdToInsert)
+	// пачка, подтверждение которой в Redis не прошло, приходит повторно и уже учтена
+	batch, err := tx.Exec(ctx, insertViewBatchQuery, batchId)
 	if err != nil {
-		logger.Error("failed to add viewed advert", zap.Error(err), zap.String("advert_id", advertId.String()))
+		logger.Error("failed to insert views batch", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+	if batch.RowsAffected() == 0 {
+		logger.Info("views batch already flushed")
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, insertViewedAdvertsQuery, advertIds, userIds, visitorIds, viewedAt); err != nil {
+		logger.Error("failed to insert viewed adverts", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+
+	if _, err := tx.Exec(ctx, incrementViewsCountQuery, countedIds, counts); err != nil {
+		logger.Error("failed to increment views count", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("failed to commit transaction", zap.Error(err))
 		return entity.PSQLWrap(err)
 	}
 
 	return nil
 }
 
-func (r *AdvertDB) CheckIfExists(advertId uuid.UUID) (bool, error) {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) CheckIfExists(ctx context.Context, advertId uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("checking if advert exists in db", zap.String("advert_id", advertId.String()))
 
 	var exists bool
@@ -692,13 +883,13 @@ func (r *AdvertDB) CheckIfExists(advertId uuid.UUID) (bool, error) {
 	return exists, nil
 }
 
-func (r *AdvertDB) GetSavedByUserId(userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) GetSavedByUserId(ctx context.Context, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting saved adverts by user id from db", zap.String("user_id", userId.String()))
 
 	rows, err := r.DB.Query(ctx, selectSavedAdvertsByUserIdQuery, userId)
@@ -723,11 +914,15 @@ func (r *AdvertDB) GetSavedByUserId(userId uuid.UUID) ([]*entity.Advert, error)
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err), zap.String("user_id", userId.String()))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -738,13 +933,13 @@ func (r *AdvertDB) GetSavedByUserId(userId uuid.UUID) ([]*entity.Advert, error)
 	return adverts, nil
 }
 
-func (r *AdvertDB) Search(query string, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) Search(ctx context.Context, query string, limit, offset int, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("searching adverts in db", zap.String("query", query))
 
 	rows, err := r.DB.Query(ctx, searchAdvertsQuery, query, limit, offset)
@@ -769,11 +964,15 @@ func (r *AdvertDB) Search(query string, limit, offset int, userId uuid.UUID) ([]
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err), zap.String("query", query))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -784,11 +983,11 @@ func (r *AdvertDB) Search(query string, limit, offset int, userId uuid.UUID) ([]
 	return adverts, nil
 }
 
-func (r *AdvertDB) Count() (int, error) {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *AdvertDB) Count(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("counting adverts in db")
 
 	var count int
@@ -800,13 +999,13 @@ func (r *AdvertDB) Count() (int, error) {
 	return count, nil
 }
 
-func (r *AdvertDB) GetByUserId(sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
+func (r *AdvertDB) GetByUserId(ctx context.Context, sellerId, userId uuid.UUID) ([]*entity.Advert, error) {
 	var adverts []*entity.Advert
 
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
 
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting adverts by user id from db", zap.String("seller_id", sellerId.String()))
 
 	rows, err := r.DB.Query(ctx, selectAdvertsByUserIdQuery, sellerId)
@@ -831,11 +1030,15 @@ func (r *AdvertDB) GetByUserId(sellerId, userId uuid.UUID) ([]*entity.Advert, er
 			&dbAdvert.Status,
 			&dbAdvert.CreatedAt,
 			&dbAdvert.UpdatedAt,
+			&dbAdvert.ExpiresAt,
+			&dbAdvert.BumpedAt,
+			&dbAdvert.PublishAt,
+			&dbAdvert.ViewsCount,
 		); err != nil {
 			logger.Error("failed to scan row", zap.Error(err), zap.String("seller_id", sellerId.String()))
 			return nil, entity.PSQLWrap(err)
 		}
-		adverts = append(adverts, r.convertToEntityAdvert(dbAdvert, userId))
+		adverts = append(adverts, r.convertToEntityAdvert(ctx, dbAdvert, userId))
 	}
 
 	if err := rows.Err(); err != nil {
@@ -845,3 +1048,311 @@ func (r *AdvertDB) GetByUserId(sellerId, userId uuid.UUID) ([]*entity.Advert, er
 
 	return adverts, nil
 }
+
+func (r *AdvertDB) Renew(ctx context.Context, advertId uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("renewing advert in db", zap.String("advert_id", advertId.String()))
+
+	result, err := r.DB.Exec(ctx, renewAdvertQuery, advertId)
+	if err != nil {
+		logger.Error("failed to renew advert", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(err)
+	}
+
+	if result.RowsAffected() == 0 {
+		logger.Error("advert cannot be renewed", zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(repository.ErrAdvertNotRenewable)
+	}
+
+	return nil
+}
+
+func (r *AdvertDB) Bump(ctx context.Context, advertId uuid.UUID, cooldown time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("bumping advert in db", zap.String("advert_id", advertId.String()))
+
+	result, err := r.DB.Exec(ctx, bumpAdvertQuery, advertId, cooldown)
+	if err != nil {
+		logger.Error("failed to bump advert", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(err)
+	}
+
+	if result.RowsAffected() == 0 {
+		logger.Error("advert cannot be bumped yet", zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(repository.ErrAdvertBumpCooldown)
+	}
+
+	return nil
+}
+
+func (r *AdvertDB) ExpireOutdated(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("expiring outdated adverts in db")
+
+	result, err := r.DB.Exec(ctx, expireAdvertsQuery)
+	if err != nil {
+		logger.Error("failed to expire adverts", zap.Error(err))
+		return 0, entity.PSQLWrap(err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (r *AdvertDB) GetExpiringUnreminded(ctx context.Context, before time.Duration) ([]*entity.Advert, error) {
+	var adverts []*entity.Advert
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting expiring adverts from db", zap.Duration("before", before))
+
+	rows, err := r.DB.Query(ctx, selectExpiringAdvertsQuery, before)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var advert entity.Advert
+		if err := rows.Scan(&advert.ID, &advert.Title, &advert.SellerId, &advert.ExpiresAt); err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		advert.Status = entity.AdvertStatusActive
+		adverts = append(adverts, &advert)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return adverts, nil
+}
+
+func (r *AdvertDB) MarkReminded(ctx context.Context, advertId uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+
+	result, err := r.DB.Exec(ctx, markAdvertRemindedQuery, advertId)
+	if err != nil {
+		logger.Error("failed to mark advert as reminded", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return false, entity.PSQLWrap(err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *AdvertDB) Publish(ctx context.Context, advertId uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("publishing advert in db", zap.String("advert_id", advertId.String()))
+
+	result, err := r.DB.Exec(ctx, publishAdvertQuery, advertId)
+	if err != nil {
+		logger.Error("failed to publish advert", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(err)
+	}
+
+	if result.RowsAffected() == 0 {
+		logger.Error("advert is not a draft", zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(repository.ErrAdvertNotDraft)
+	}
+
+	return nil
+}
+
+func (r *AdvertDB) Schedule(ctx context.Context, advertId uuid.UUID, publishAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("scheduling advert publication in db", zap.String("advert_id", advertId.String()), zap.Time("publish_at", publishAt))
+
+	result, err := r.DB.Exec(ctx, scheduleAdvertQuery, advertId, publishAt)
+	if err != nil {
+		logger.Error("failed to schedule advert", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(err)
+	}
+
+	if result.RowsAffected() == 0 {
+		logger.Error("advert is not a draft", zap.String("advert_id", advertId.String()))
+		return entity.PSQLWrap(repository.ErrAdvertNotDraft)
+	}
+
+	return nil
+}
+
+func (r *AdvertDB) PublishScheduled(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("publishing scheduled adverts in db")
+
+	result, err := r.DB.Exec(ctx, publishScheduledAdvertsQuery)
+	if err != nil {
+		logger.Error("failed to publish scheduled adverts", zap.Error(err))
+		return 0, entity.PSQLWrap(err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// nullableUUID превращает пустой идентификатор в NULL, чтобы черновик мог не иметь категории
+func nullableUUID(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+func (r *AdvertDB) UpsertBySKU(ctx context.Context, a *entity.Advert) (*entity.Advert, bool, error) {
+	var inserted bool
+	var status string
+	advert := *a
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("upserting advert by sku in db", zap.String("seller_id", a.SellerId.String()), zap.String("external_sku", a.ExternalSKU))
+
+	err := r.DB.QueryRow(ctx, upsertAdvertBySKUQuery,
+		a.Title,
+		a.Description,
+		a.Price,
+		a.Location,
+		a.HasDelivery,
+		nullableUUID(a.CategoryId),
+		a.SellerId,
+		string(a.Status),
+		a.ExternalSKU).Scan(
+		&advert.ID,
+		&status,
+		&advert.CreatedAt,
+		&advert.UpdatedAt,
+		&advert.ExpiresAt,
+		&advert.BumpedAt,
+		&inserted,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Error("advert with sku is reserved or scheduled", zap.String("external_sku", a.ExternalSKU))
+		return nil, false, entity.PSQLWrap(repository.ErrAdvertSKULocked)
+	}
+	if err != nil {
+		logger.Error("failed to upsert advert", zap.Error(err), zap.String("external_sku", a.ExternalSKU))
+		return nil, false, entity.PSQLWrap(err)
+	}
+	advert.Status = entity.AdvertStatus(status)
+
+	return &advert, inserted, nil
+}
+
+func (r *AdvertDB) GetForExport(ctx context.Context, sellerId uuid.UUID) ([]*entity.Advert, error) {
+	var adverts []*entity.Advert
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting adverts for export from db", zap.String("seller_id", sellerId.String()))
+
+	rows, err := r.DB.Query(ctx, selectAdvertsForExportQuery, sellerId)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err), zap.String("seller_id", sellerId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status string
+		advert := entity.Advert{SellerId: sellerId}
+		if err := rows.Scan(
+			&advert.ID,
+			&advert.ExternalSKU,
+			&advert.Title,
+			&advert.Description,
+			&advert.Price,
+			&advert.Location,
+			&advert.HasDelivery,
+			&advert.CategoryId,
+			&status,
+		); err != nil {
+			logger.Error("failed to scan row", zap.Error(err), zap.String("seller_id", sellerId.String()))
+			return nil, entity.PSQLWrap(err)
+		}
+		advert.Status = entity.AdvertStatus(status)
+		adverts = append(adverts, &advert)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err), zap.String("seller_id", sellerId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return adverts, nil
+}
+
+func (r *AdvertDB) GetSaleState(ctx context.Context, advertId uuid.UUID) (*entity.AdvertSaleState, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting advert sale state from db", zap.String("advert_id", advertId.String()))
+
+	var state entity.AdvertSaleState
+	err := r.DB.QueryRow(ctx, selectAdvertSaleStateQuery, advertId).Scan(&state.AdvertID, &state.Status, &state.SellerUserID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrAdvertNotFound
+	case err != nil:
+		logger.Error("failed to get advert sale state", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return &state, nil
+}
+
+func (r *AdvertDB) LockByCartId(ctx context.Context, tx pgx.Tx, cartId uuid.UUID) ([]*entity.AdvertSaleState, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("locking cart adverts in db", zap.String("cart_id", cartId.String()))
+
+	rows, err := tx.Query(ctx, lockAdvertsByCartIdQuery, cartId)
+	if err != nil {
+		logger.Error("failed to lock cart adverts", zap.Error(err), zap.String("cart_id", cartId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var states []*entity.AdvertSaleState
+	for rows.Next() {
+		var state entity.AdvertSaleState
+		if err := rows.Scan(&state.AdvertID, &state.Status, &state.SellerUserID); err != nil {
+			logger.Error("failed to scan row", zap.Error(err), zap.String("cart_id", cartId.String()))
+			return nil, entity.PSQLWrap(err)
+		}
+		states = append(states, &state)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err), zap.String("cart_id", cartId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return states, nil
+}
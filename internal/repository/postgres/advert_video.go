@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	// Строка объявления блокируется, чтобы параллельные запросы не превысили лимит видео
+	lockAdvertForVideoQuery = `
+        SELECT id
+        FROM advert
+        WHERE id = $1
+        FOR UPDATE
+    `
+
+	countAdvertVideosQuery = `
+        SELECT COUNT(*), COALESCE(MAX(position) + 1, 0)
+        FROM advert_video
+        WHERE advert_id = $1
+    `
+
+	insertAdvertVideoQuery = `
+        INSERT INTO advert_video (advert_id, static_id, position)
+        VALUES ($1, $2, $3)
+        RETURNING created_at
+    `
+
+	selectAdvertVideosQuery = `
+        SELECT static_id, position, created_at
+        FROM advert_video
+        WHERE advert_id = $1
+        ORDER BY position
+    `
+
+	deleteAdvertVideoQuery = `
+        DELETE FROM advert_video
+        WHERE advert_id = $1 AND static_id = $2
+    `
+)
+
+type AdvertVideoDB struct {
+	DB      DBExecutor
+	timeout time.Duration
+}
+
+func NewAdvertVideoRepository(ctx context.Context, dbpool *pgxpool.Pool, logger *zap.Logger, timeout time.Duration) (repository.AdvertVideoRepository, error) {
+	if err := dbpool.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &AdvertVideoDB{
+		DB:      dbpool,
+		timeout: timeout,
+	}, nil
+}
+
+func (s AdvertVideoDB) Add(ctx context.Context, advertID, videoID uuid.UUID, limit int) (*entity.AdvertVideo, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("adding advert video", zap.String("advert_id", advertID.String()), zap.String("video_id", videoID.String()))
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return nil, entity.PSQLWrap(err, errors.New("error beginning transaction AddAdvertVideo"))
+	}
+	defer tx.Rollback(ctx)
+
+	var lockedID uuid.UUID
+	if err := tx.QueryRow(ctx, lockAdvertForVideoQuery, advertID).Scan(&lockedID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.PSQLWrap(repository.ErrAdvertNotFound)
+		}
+		logger.Error("error locking advert", zap.String("advert_id", advertID.String()), zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query LockAdvertForVideo"))
+	}
+
+	var count, position int
+	if err := tx.QueryRow(ctx, countAdvertVideosQuery, advertID).Scan(&count, &position); err != nil {
+		logger.Error("error counting advert videos", zap.String("advert_id", advertID.String()), zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query CountAdvertVideos"))
+	}
+	if count >= limit {
+		return nil, entity.PSQLWrap(repository.ErrAdvertVideoLimit)
+	}
+
+	video := &entity.AdvertVideo{AdvertID: advertID, VideoID: videoID, Position: position}
+	if err := tx.QueryRow(ctx, insertAdvertVideoQuery, advertID, videoID, position).Scan(&video.CreatedAt); err != nil {
+		logger.Error("error inserting advert video", zap.String("advert_id", advertID.String()), zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query InsertAdvertVideo"))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.PSQLWrap(err, errors.New("error committing transaction AddAdvertVideo"))
+	}
+	return video, nil
+}
+
+func (s AdvertVideoDB) GetByAdvertID(ctx context.Context, advertID uuid.UUID) ([]*entity.AdvertVideo, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting advert videos", zap.String("advert_id", advertID.String()))
+
+	rows, err := s.DB.Query(ctx, selectAdvertVideosQuery, advertID)
+	if err != nil {
+		logger.Error("error getting advert videos", zap.String("advert_id", advertID.String()), zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query GetAdvertVideos"))
+	}
+	defer rows.Close()
+
+	videos := make([]*entity.AdvertVideo, 0)
+	for rows.Next() {
+		video := &entity.AdvertVideo{AdvertID: advertID}
+		if err := rows.Scan(&video.VideoID, &video.Position, &video.CreatedAt); err != nil {
+			return nil, entity.PSQLWrap(err, errors.New("error scanning advert video"))
+		}
+		videos = append(videos, video)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, entity.PSQLWrap(err, errors.New("error iterating advert videos"))
+	}
+	return videos, nil
+}
+
+func (s AdvertVideoDB) Delete(ctx context.Context, advertID, videoID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("deleting advert video", zap.String("advert_id", advertID.String()), zap.String("video_id", videoID.String()))
+
+	tag, err := s.DB.Exec(ctx, deleteAdvertVideoQuery, advertID, videoID)
+	if err != nil {
+		logger.Error("error deleting advert video", zap.String("advert_id", advertID.String()), zap.Error(err))
+		return entity.PSQLWrap(err, errors.New("error executing SQL query DeleteAdvertVideo"))
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.PSQLWrap(repository.ErrAdvertVideoNotFound)
+	}
+	return nil
+}
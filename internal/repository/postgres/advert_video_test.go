@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdvertVideoTest(t *testing.T) (pgxmock.PgxPoolIface, *AdvertVideoDB) {
+	mockPool, adapter := setupMockDB(t)
+	t.Cleanup(func() {
+		mockPool.Close()
+	})
+	return mockPool, &AdvertVideoDB{DB: adapter, timeout: 10 * time.Second}
+}
+
+func TestAdvertVideoDB_Add(t *testing.T) {
+	mockPool, repo := setupAdvertVideoTest(t)
+	advertID, videoID := uuid.New(), uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		mockPool.ExpectBegin()
+		mockPool.ExpectQuery("SELECT id FROM advert").
+			WithArgs(advertID).
+			WillReturnRows(mockPool.NewRows([]string{"id"}).AddRow(advertID))
+		mockPool.ExpectQuery("SELECT COUNT").
+			WithArgs(advertID).
+			WillReturnRows(mockPool.NewRows([]string{"count", "position"}).AddRow(1, 3))
+		mockPool.ExpectQuery("INSERT INTO advert_video").
+			WithArgs(advertID, videoID, 3).
+			WillReturnRows(mockPool.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		mockPool.ExpectCommit()
+		mockPool.ExpectRollback()
+
+		video, err := repo.Add(context.Background(), advertID, videoID, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, videoID, video.VideoID)
+		assert.Equal(t, 3, video.Position)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		mockPool.ExpectBegin()
+		mockPool.ExpectQuery("SELECT id FROM advert").
+			WithArgs(advertID).
+			WillReturnRows(mockPool.NewRows([]string{"id"}).AddRow(advertID))
+		mockPool.ExpectQuery("SELECT COUNT").
+			WithArgs(advertID).
+			WillReturnRows(mockPool.NewRows([]string{"count", "position"}).AddRow(3, 3))
+		mockPool.ExpectRollback()
+
+		_, err := repo.Add(context.Background(), advertID, videoID, 3)
+		assert.ErrorIs(t, err, repository.ErrAdvertVideoLimit)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAdvertVideoDB_Delete(t *testing.T) {
+	mockPool, repo := setupAdvertVideoTest(t)
+	advertID, videoID := uuid.New(), uuid.New()
+
+	mockPool.ExpectExec("DELETE FROM advert_video").
+		WithArgs(advertID, videoID).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), advertID, videoID), repository.ErrAdvertVideoNotFound)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
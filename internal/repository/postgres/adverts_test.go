@@ -2,12 +2,15 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 )
@@ -29,13 +32,20 @@ func TestUpdateAdvert(t *testing.T) {
 		Status:      "inactive",
 	}
 
-	mockPool.ExpectExec(`UPDATE advert SET title = \$1, description = \$2, price = \$3, location = \$4, has_delivery = \$5, category_id = \$6, status = \$7 WHERE id = \$8`).
-		WithArgs(updatedAdvert.Title, updatedAdvert.Description, updatedAdvert.Price, updatedAdvert.Location, updatedAdvert.HasDelivery, updatedAdvert.CategoryId, updatedAdvert.Status, updatedAdvert.ID).
+	mockPool.ExpectExec(`UPDATE advert SET title = \$1, description = \$2, price = \$3, location = \$4, has_delivery = \$5, category_id = \$6, status = \$7, publish_at = \$8 WHERE id = \$9 AND status = \$10`).
+		WithArgs(updatedAdvert.Title, updatedAdvert.Description, updatedAdvert.Price, updatedAdvert.Location, updatedAdvert.HasDelivery, &updatedAdvert.CategoryId, updatedAdvert.Status, updatedAdvert.PublishAt, updatedAdvert.ID, entity.AdvertStatusInactive).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-	err := repo.Update(updatedAdvert)
+	err := repo.Update(context.Background(), updatedAdvert, entity.AdvertStatusInactive)
 	assert.NoError(t, err)
 
+	mockPool.ExpectExec(`UPDATE advert SET`).
+		WithArgs(updatedAdvert.Title, updatedAdvert.Description, updatedAdvert.Price, updatedAdvert.Location, updatedAdvert.HasDelivery, &updatedAdvert.CategoryId, updatedAdvert.Status, updatedAdvert.PublishAt, updatedAdvert.ID, entity.AdvertStatusInactive).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	err = repo.Update(context.Background(), updatedAdvert, entity.AdvertStatusInactive)
+	assert.ErrorIs(t, err, repository.ErrAdvertNotFound)
+
 	err = mockPool.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
@@ -50,7 +60,7 @@ func TestDeleteAdvert(t *testing.T) {
 		WithArgs(advertID).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
-	err := repo.DeleteById(advertID)
+	err := repo.DeleteById(context.Background(), advertID)
 	assert.NoError(t, err)
 
 	err = mockPool.ExpectationsWereMet()
@@ -64,16 +74,16 @@ func TestGetAdvertById(t *testing.T) {
 	advertID := uuid.New()
 
 	rows := pgxmock.NewRows([]string{
-		"id", "title", "description", "price", "location", "has_delivery", "category_id", "seller_id", "image_id", "status", "created_at", "updated_at",
+		"id", "title", "description", "price", "location", "has_delivery", "category_id", "seller_id", "image_id", "status", "created_at", "updated_at", "expires_at", "bumped_at", "publish_at", "views_count",
 	}).AddRow(
-		advertID, "Test Advert", "Test Description", uint(100), "Test Location", true, uuid.New(), uuid.New(), uuid.Nil, "active", time.Now(), time.Now(),
+		advertID, "Test Advert", "Test Description", uint(100), "Test Location", true, uuid.New(), uuid.New(), uuid.Nil, "active", time.Now(), time.Now(), time.Now(), time.Now(), nil, uint(0),
 	)
 
-	mockPool.ExpectQuery(`SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at FROM advert WHERE id = \$1`).
+	mockPool.ExpectQuery(`SELECT id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status, created_at, updated_at, expires_at, bumped_at, publish_at, views_count FROM advert WHERE id = \$1`).
 		WithArgs(advertID).
 		WillReturnRows(rows)
 
-	advert, err := repo.GetById(advertID, uuid.Nil)
+	advert, err := repo.GetById(context.Background(), advertID, uuid.Nil)
 
 	assert.NoError(t, err)
 	assert.Nil(t, advert)
@@ -94,11 +104,13 @@ func TestAddAdvert(t *testing.T) {
 		Status:      entity.AdvertStatusActive,
 	}
 
-	mockPool.ExpectQuery(`INSERT INTO advert \(title, description, price, location, has_delivery, category_id, seller_id, status\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8\) RETURNING id, title, description, price, location, has_delivery, category_id, seller_id, image_id, status`).
-		WithArgs(newAdvert.Title, newAdvert.Description, newAdvert.Price, newAdvert.Location, newAdvert.HasDelivery, newAdvert.CategoryId, newAdvert.SellerId, "active").
-		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "description", "price", "location", "has_delivery", "category_id", "seller_id", "image_id", "status"}).AddRow(uuid.New(), newAdvert.Title, newAdvert.Description, newAdvert.Price, newAdvert.Location, newAdvert.HasDelivery, newAdvert.CategoryId, newAdvert.SellerId, uuid.Nil, "active"))
+	now := time.Now()
+	mockPool.ExpectQuery(`INSERT INTO advert \(title, description, price, location, has_delivery, category_id, seller_id, status, publish_at, expires_at\)`).
+		WithArgs(newAdvert.Title, newAdvert.Description, newAdvert.Price, newAdvert.Location, newAdvert.HasDelivery, &newAdvert.CategoryId, newAdvert.SellerId, "active", newAdvert.PublishAt).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "title", "description", "price", "location", "has_delivery", "category_id", "seller_id", "image_id", "status", "created_at", "updated_at", "expires_at", "bumped_at", "publish_at", "views_count"}).
+			AddRow(uuid.New(), newAdvert.Title, newAdvert.Description, newAdvert.Price, newAdvert.Location, newAdvert.HasDelivery, newAdvert.CategoryId, newAdvert.SellerId, uuid.Nil, "active", now, now, now.Add(30*24*time.Hour), now, nil, uint(0)))
 
-	result, err := repo.Add(newAdvert)
+	result, err := repo.Add(context.Background(), newAdvert)
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, newAdvert.Title, result.Title)
@@ -121,23 +133,261 @@ func TestUpdateAdvertStatus(t *testing.T) {
 		WithArgs(newStatus, advertID).
 		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-	err = repo.UpdateStatus(tx, advertID, newStatus) // Добавьте tx как аргумент
+	err = repo.UpdateStatus(context.Background(), tx, advertID, newStatus) // Добавьте tx как аргумент
 	assert.NoError(t, err)
 
 	err = mockPool.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
 
+func TestBumpAdvert(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advertID := uuid.New()
+	cooldown := 24 * time.Hour
+
+	mockPool.ExpectExec(`UPDATE advert SET bumped_at = CURRENT_TIMESTAMP WHERE id = \$1`).
+		WithArgs(advertID, cooldown).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	assert.NoError(t, repo.Bump(context.Background(), advertID, cooldown))
+
+	mockPool.ExpectExec(`UPDATE advert SET bumped_at = CURRENT_TIMESTAMP WHERE id = \$1`).
+		WithArgs(advertID, cooldown).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	err := repo.Bump(context.Background(), advertID, cooldown)
+	assert.ErrorIs(t, err, repository.ErrAdvertBumpCooldown)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestExpireOutdatedAdverts(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	mockPool.ExpectExec(`UPDATE advert SET status = 'inactive' WHERE status = 'active' AND expires_at <= CURRENT_TIMESTAMP`).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 3))
+
+	expired, err := repo.ExpireOutdated(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), expired)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPublishAdvert(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advertID := uuid.New()
+
+	mockPool.ExpectExec(`UPDATE advert SET status = 'active', publish_at = NULL`).
+		WithArgs(advertID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	assert.NoError(t, repo.Publish(context.Background(), advertID))
+
+	mockPool.ExpectExec(`UPDATE advert SET status = 'active', publish_at = NULL`).
+		WithArgs(advertID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	err := repo.Publish(context.Background(), advertID)
+	assert.ErrorIs(t, err, repository.ErrAdvertNotDraft)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPublishScheduledAdverts(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	mockPool.ExpectExec(`UPDATE advert SET status = 'active', bumped_at = publish_at`).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	published, err := repo.PublishScheduled(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), published)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestUpsertAdvertBySKU(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advert := &entity.Advert{
+		Title:       "Imported Advert",
+		Description: "Imported Description",
+		Price:       300,
+		Location:    "Moscow",
+		CategoryId:  uuid.New(),
+		SellerId:    uuid.New(),
+		Status:      entity.AdvertStatusActive,
+		ExternalSKU: "SKU-42",
+	}
+	advertID := uuid.New()
+	now := time.Now()
+
+	mockPool.ExpectQuery(`INSERT INTO advert .* ON CONFLICT \(seller_id, external_sku\)`).
+		WithArgs(advert.Title, advert.Description, advert.Price, advert.Location, advert.HasDelivery, &advert.CategoryId, advert.SellerId, "active", advert.ExternalSKU).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "created_at", "updated_at", "expires_at", "bumped_at", "inserted"}).
+			AddRow(advertID, "active", now, now, now, now, false))
+
+	saved, created, err := repo.UpsertBySKU(context.Background(), advert)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, advertID, saved.ID)
+	assert.Equal(t, "SKU-42", saved.ExternalSKU)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestUpsertAdvertBySKU_Reserved(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advert := &entity.Advert{
+		Title:       "Imported Advert",
+		Price:       300,
+		CategoryId:  uuid.New(),
+		SellerId:    uuid.New(),
+		Status:      entity.AdvertStatusActive,
+		ExternalSKU: "SKU-42",
+	}
+
+	mockPool.ExpectQuery(`ON CONFLICT \(seller_id, external_sku\).*WHERE advert.status NOT IN \('reserved', 'scheduled'\)`).
+		WithArgs(advert.Title, advert.Description, advert.Price, advert.Location, advert.HasDelivery, &advert.CategoryId, advert.SellerId, "active", advert.ExternalSKU).
+		WillReturnError(pgx.ErrNoRows)
+
+	_, _, err := repo.UpsertBySKU(context.Background(), advert)
+	assert.ErrorIs(t, err, repository.ErrAdvertSKULocked)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestUploadAdvertImage_Flagged(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advertID := uuid.New()
+	imageID := uuid.New()
+
+	mockPool.ExpectExec(`UPDATE advert SET image_id = \$1`).
+		WithArgs(imageID, advertID).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mockPool.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM static WHERE id = \$1 AND moderation_status = 'flagged'\)`).
+		WithArgs(imageID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err := repo.UploadImage(context.Background(), advertID, imageID)
+	assert.ErrorIs(t, err, repository.ErrAdvertImageFlagged)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
 func setupAdvertTest(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter, *AdvertDB, func()) {
 	mockPool, err := pgxmock.NewPool()
 	assert.NoError(t, err)
 	adapter := mocks.NewPgxMockAdapter(mockPool)
 	repo := &AdvertDB{
 		DB:      adapter,
-		ctx:     context.Background(),
 		timeout: 5 * time.Second,
 	}
 	return mockPool, adapter, repo, func() {
 		mockPool.Close()
 	}
 }
+
+func TestFlushAdvertViews(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advertID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+	views := []entity.AdvertView{
+		{AdvertId: advertID, UserId: userID, ViewedAt: now},
+		{AdvertId: advertID, VisitorId: "visitor", ViewedAt: now},
+	}
+
+	batchID := uuid.New()
+	mockPool.ExpectBegin()
+	mockPool.ExpectExec(`INSERT INTO advert_view_batch \(id\) VALUES \(\$1\)`).
+		WithArgs(batchID).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mockPool.ExpectExec(`INSERT INTO viewed_advert \(advert_id, user_id, visitor_id, created_at\)`).
+		WithArgs([]uuid.UUID{advertID, advertID}, []string{userID.String(), ""}, []string{"", "visitor"}, []time.Time{now, now}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+	mockPool.ExpectExec(`UPDATE advert a SET views_count = a.views_count \+ v.views`).
+		WithArgs([]uuid.UUID{advertID}, []int{2}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockPool.ExpectCommit()
+	mockPool.ExpectRollback()
+
+	err := repo.FlushViews(context.Background(), batchID, views, map[uuid.UUID]uint{advertID: 2})
+	assert.NoError(t, err)
+
+	// повтор уже перенесенной пачки не увеличивает счетчики
+	mockPool.ExpectBegin()
+	mockPool.ExpectExec(`INSERT INTO advert_view_batch \(id\) VALUES \(\$1\)`).
+		WithArgs(batchID).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mockPool.ExpectRollback()
+
+	err = repo.FlushViews(context.Background(), batchID, views, map[uuid.UUID]uint{advertID: 2})
+	assert.NoError(t, err)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAdvertDB_GetSaleState(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	advertID, sellerUserID := uuid.New(), uuid.New()
+	mockPool.ExpectQuery(`SELECT a.id, a.status, s.user_id FROM advert a JOIN seller s ON s.id = a.seller_id WHERE a.id = \$1`).
+		WithArgs(advertID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "user_id"}).
+			AddRow(advertID, entity.AdvertStatusReserved, sellerUserID))
+
+	state, err := repo.GetSaleState(context.Background(), advertID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.AdvertStatusReserved, state.Status)
+	assert.Equal(t, sellerUserID, state.SellerUserID)
+
+	mockPool.ExpectQuery(`WHERE a.id = \$1`).
+		WithArgs(advertID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "user_id"}))
+
+	_, err = repo.GetSaleState(context.Background(), advertID)
+	assert.ErrorIs(t, err, repository.ErrAdvertNotFound)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestAdvertDB_LockByCartId(t *testing.T) {
+	mockPool, _, repo, teardown := setupAdvertTest(t)
+	defer teardown()
+
+	cartID, advertID, sellerUserID := uuid.New(), uuid.New(), uuid.New()
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	assert.NoError(t, err)
+
+	mockPool.ExpectQuery(`WHERE a.id IN \(SELECT advert_id FROM cart_advert WHERE cart_id = \$1\) ORDER BY a.id FOR UPDATE OF a`).
+		WithArgs(cartID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "status", "user_id"}).
+			AddRow(advertID, entity.AdvertStatusActive, sellerUserID))
+
+	states, err := repo.LockByCartId(context.Background(), tx, cartID)
+	assert.NoError(t, err)
+	assert.Len(t, states, 1)
+	assert.Equal(t, advertID, states[0].AdvertID)
+
+	mockPool.ExpectQuery(`FOR UPDATE OF a`).
+		WithArgs(cartID).
+		WillReturnError(errors.New("lock timeout"))
+
+	_, err = repo.LockByCartId(context.Background(), tx, cartID)
+	assert.ErrorIs(t, err, entity.ErrPSQL)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
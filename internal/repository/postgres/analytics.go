@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type AnalyticsDB struct {
+	DB      DBExecutor
+	timeout time.Duration
+}
+
+const (
+	rollupAdvertDailyStatsQuery = `
+		INSERT INTO advert_daily_stats (advert_id, day, views, unique_viewers, saves, cart_additions, purchases)
+		SELECT e.advert_id, e.day, SUM(e.views), SUM(e.unique_viewers), SUM(e.saves), SUM(e.cart_additions), SUM(e.purchases)
+		FROM (
+			SELECT advert_id, created_at::date AS day, COUNT(*) AS views, COUNT(DISTINCT COALESCE(user_id::text, visitor_id)) AS unique_viewers,
+				0 AS saves, 0 AS cart_additions, 0 AS purchases
+			FROM viewed_advert
+			WHERE created_at >= $1
+			GROUP BY advert_id, created_at::date
+			UNION ALL
+			SELECT advert_id, created_at::date, 0, 0, COUNT(*), 0, 0
+			FROM saved_advert
+			WHERE created_at >= $1
+			GROUP BY advert_id, created_at::date
+			UNION ALL
+			SELECT advert_id, created_at::date, 0, 0, 0, COUNT(*), 0
+			FROM cart_advert
+			WHERE created_at >= $1
+			GROUP BY advert_id, created_at::date
+			UNION ALL
+			SELECT ca.advert_id, p.created_at::date, 0, 0, 0, 0, COUNT(*)
+			FROM purchase p
+			JOIN cart_advert ca ON ca.cart_id = p.cart_id
+			WHERE p.created_at >= $1 AND p.status != 'cancelled'
+			GROUP BY ca.advert_id, p.created_at::date
+		) e
+		GROUP BY e.advert_id, e.day
+		ON CONFLICT (advert_id, day) DO UPDATE
+		SET views = EXCLUDED.views, unique_viewers = EXCLUDED.unique_viewers, saves = EXCLUDED.saves,
+			cart_additions = EXCLUDED.cart_additions, purchases = EXCLUDED.purchases`
+
+	rollupCategoryDailyStatsQuery = `
+		INSERT INTO category_daily_stats (category_id, day, adverts, views, unique_viewers, saves, cart_additions, purchases)
+		SELECT a.category_id, s.day,
+			(SELECT COUNT(*) FROM advert c WHERE c.category_id = a.category_id AND c.status = 'active'),
+			SUM(s.views), SUM(s.unique_viewers), SUM(s.saves), SUM(s.cart_additions), SUM(s.purchases)
+		FROM advert_daily_stats s
+		JOIN advert a ON a.id = s.advert_id
+		WHERE s.day >= $1 AND a.category_id IS NOT NULL
+		GROUP BY a.category_id, s.day
+		ON CONFLICT (category_id, day) DO UPDATE
+		SET adverts = EXCLUDED.adverts, views = EXCLUDED.views, unique_viewers = EXCLUDED.unique_viewers,
+			saves = EXCLUDED.saves, cart_additions = EXCLUDED.cart_additions, purchases = EXCLUDED.purchases`
+
+	selectAdvertDailyStatsQuery = `
+		SELECT advert_id, day, views, unique_viewers, saves, cart_additions, purchases
+		FROM advert_daily_stats
+		WHERE advert_id = $1 AND day BETWEEN $2 AND $3
+		ORDER BY day`
+
+	selectCategoryDailyStatsQuery = `
+		SELECT category_id, day, adverts, views, unique_viewers, saves, cart_additions, purchases
+		FROM category_daily_stats
+		WHERE category_id = $1 AND day BETWEEN $2 AND $3
+		ORDER BY day`
+
+	selectSellerTotalsQuery = `
+		SELECT a.id, a.title,
+			COALESCE(SUM(s.views), 0), COALESCE(SUM(s.unique_viewers), 0), COALESCE(SUM(s.saves), 0),
+			COALESCE(SUM(s.cart_additions), 0), COALESCE(SUM(s.purchases), 0)
+		FROM advert a
+		LEFT JOIN advert_daily_stats s ON s.advert_id = a.id AND s.day BETWEEN $2 AND $3
+		WHERE a.seller_id = $1
+		GROUP BY a.id, a.title
+		ORDER BY COALESCE(SUM(s.views), 0) DESC`
+)
+
+func NewAnalyticsRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Duration) (repository.AnalyticsRepository, error) {
+	if err := db.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &AnalyticsDB{
+		DB:      db,
+		timeout: timeout,
+	}, nil
+}
+
+func (r *AnalyticsDB) RollupDaily(ctx context.Context, since time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("rolling up daily advert stats", zap.Time("since", since))
+
+	if _, err := r.DB.Exec(ctx, rollupAdvertDailyStatsQuery, since); err != nil {
+		logger.Error("failed to roll up advert stats", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+
+	if _, err := r.DB.Exec(ctx, rollupCategoryDailyStatsQuery, since); err != nil {
+		logger.Error("failed to roll up category stats", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+
+	return nil
+}
+
+func (r *AnalyticsDB) GetAdvertDailyStats(ctx context.Context, advertId uuid.UUID, from, to time.Time) ([]*entity.AdvertDailyStats, error) {
+	var stats []*entity.AdvertDailyStats
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting advert daily stats from db", zap.String("advert_id", advertId.String()))
+
+	rows, err := r.DB.Query(ctx, selectAdvertDailyStatsQuery, advertId, from, to)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day entity.AdvertDailyStats
+		if err := rows.Scan(
+			&day.AdvertId,
+			&day.Day,
+			&day.Views,
+			&day.UniqueViewers,
+			&day.Saves,
+			&day.CartAdditions,
+			&day.Purchases,
+		); err != nil {
+			logger.Error("failed to scan row", zap.Error(err), zap.String("advert_id", advertId.String()))
+			return nil, entity.PSQLWrap(err)
+		}
+		stats = append(stats, &day)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err), zap.String("advert_id", advertId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return stats, nil
+}
+
+func (r *AnalyticsDB) GetCategoryDailyStats(ctx context.Context, categoryId uuid.UUID, from, to time.Time) ([]*entity.CategoryDailyStats, error) {
+	var stats []*entity.CategoryDailyStats
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting category daily stats from db", zap.String("category_id", categoryId.String()))
+
+	rows, err := r.DB.Query(ctx, selectCategoryDailyStatsQuery, categoryId, from, to)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err), zap.String("category_id", categoryId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day entity.CategoryDailyStats
+		if err := rows.Scan(
+			&day.CategoryId,
+			&day.Day,
+			&day.Adverts,
+			&day.Views,
+			&day.UniqueViewers,
+			&day.Saves,
+			&day.CartAdditions,
+			&day.Purchases,
+		); err != nil {
+			logger.Error("failed to scan row", zap.Error(err), zap.String("category_id", categoryId.String()))
+			return nil, entity.PSQLWrap(err)
+		}
+		stats = append(stats, &day)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err), zap.String("category_id", categoryId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return stats, nil
+}
+
+func (r *AnalyticsDB) GetSellerTotals(ctx context.Context, sellerId uuid.UUID, from, to time.Time) ([]*entity.AdvertStatsTotal, error) {
+	var totals []*entity.AdvertStatsTotal
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting seller stats totals from db", zap.String("seller_id", sellerId.String()))
+
+	rows, err := r.DB.Query(ctx, selectSellerTotalsQuery, sellerId, from, to)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err), zap.String("seller_id", sellerId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total entity.AdvertStatsTotal
+		if err := rows.Scan(
+			&total.AdvertId,
+			&total.Title,
+			&total.Views,
+			&total.UniqueViewers,
+			&total.Saves,
+			&total.CartAdditions,
+			&total.Purchases,
+		); err != nil {
+			logger.Error("failed to scan row", zap.Error(err), zap.String("seller_id", sellerId.String()))
+			return nil, entity.PSQLWrap(err)
+		}
+		totals = append(totals, &total)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err), zap.String("seller_id", sellerId.String()))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return totals, nil
+}
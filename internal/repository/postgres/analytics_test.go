@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAnalyticsTest(t *testing.T) (pgxmock.PgxPoolIface, *AnalyticsDB, func()) {
+	mockPool, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	repo := &AnalyticsDB{
+		DB:      mocks.NewPgxMockAdapter(mockPool),
+		timeout: 5 * time.Second,
+	}
+	return mockPool, repo, func() {
+		mockPool.Close()
+	}
+}
+
+func TestRollupDailyStats(t *testing.T) {
+	mockPool, repo, teardown := setupAnalyticsTest(t)
+	defer teardown()
+
+	since := time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)
+
+	mockPool.ExpectExec(`INSERT INTO advert_daily_stats`).
+		WithArgs(since).
+		WillReturnResult(pgxmock.NewResult("INSERT", 5))
+	mockPool.ExpectExec(`INSERT INTO category_daily_stats`).
+		WithArgs(since).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	assert.NoError(t, repo.RollupDaily(context.Background(), since))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestGetAdvertDailyStats(t *testing.T) {
+	mockPool, repo, teardown := setupAnalyticsTest(t)
+	defer teardown()
+
+	advertID := uuid.New()
+	from := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 6)
+
+	mockPool.ExpectQuery(`SELECT advert_id, day, views, unique_viewers, saves, cart_additions, purchases FROM advert_daily_stats`).
+		WithArgs(advertID, from, to).
+		WillReturnRows(pgxmock.NewRows([]string{"advert_id", "day", "views", "unique_viewers", "saves", "cart_additions", "purchases"}).
+			AddRow(advertID, from, uint(10), uint(7), uint(2), uint(1), uint(0)).
+			AddRow(advertID, to, uint(4), uint(4), uint(0), uint(1), uint(1)))
+
+	stats, err := repo.GetAdvertDailyStats(context.Background(), advertID, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, uint(10), stats[0].Views)
+	assert.Equal(t, uint(7), stats[0].UniqueViewers)
+	assert.Equal(t, uint(1), stats[1].Purchases)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -41,8 +41,7 @@ var (
 )
 
 type CartDB struct {
-	DB  DBExecutor
-	ctx context.Context
+	DB DBExecutor
 }
 
 func NewCartRepository(db *pgxpool.Pool, ctx context.Context) (repository.Cart, error) {
@@ -50,17 +49,16 @@ func NewCartRepository(db *pgxpool.Pool, ctx context.Context) (repository.Cart,
 		return nil, err
 	}
 	return &CartDB{
-		DB:  db,
-		ctx: ctx,
+		DB: db,
 	}, nil
 }
 
-func (c *CartDB) GetByUserId(userID uuid.UUID) (entity.Cart, error) {
+func (c *CartDB) GetByUserId(ctx context.Context, userID uuid.UUID) (entity.Cart, error) {
 	var cart entity.Cart
-	logger := middleware.GetLogger(c.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting cart by user id from db", zap.String("user_id", userID.String()))
 
-	err := c.DB.QueryRow(c.ctx, queryGetCart, userID).Scan(&cart.ID, &cart.UserID, &cart.Status)
+	err := c.DB.QueryRow(ctx, queryGetCart, userID).Scan(&cart.ID, &cart.UserID, &cart.Status)
 
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
@@ -74,12 +72,12 @@ func (c *CartDB) GetByUserId(userID uuid.UUID) (entity.Cart, error) {
 	return cart, nil
 }
 
-func (c *CartDB) Create(userID uuid.UUID) (uuid.UUID, error) {
+func (c *CartDB) Create(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
 	var cartID uuid.UUID
-	logger := middleware.GetLogger(c.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("creating cart in db", zap.String("user_id", userID.String()))
 
-	err := c.DB.QueryRow(c.ctx, queryCreateCart, userID).Scan(&cartID)
+	err := c.DB.QueryRow(ctx, queryCreateCart, userID).Scan(&cartID)
 
 	switch {
 	case err != nil:
@@ -89,11 +87,11 @@ func (c *CartDB) Create(userID uuid.UUID) (uuid.UUID, error) {
 	return cartID, nil
 }
 
-func (c *CartDB) AddAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error {
-	logger := middleware.GetLogger(c.ctx)
+func (c *CartDB) AddAdvert(ctx context.Context, cartID uuid.UUID, AdvertID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("adding advert to cart in db", zap.String("cart_id", cartID.String()), zap.String("advert_id", AdvertID.String()))
 
-	_, err := c.DB.Exec(c.ctx, queryAddAdvertToCart, cartID, AdvertID)
+	_, err := c.DB.Exec(ctx, queryAddAdvertToCart, cartID, AdvertID)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
 		return repository.ErrCartNotFound
@@ -104,11 +102,11 @@ func (c *CartDB) AddAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error {
 	return nil
 }
 
-func (c *CartDB) DeleteAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error {
-	logger := middleware.GetLogger(c.ctx)
+func (c *CartDB) DeleteAdvert(ctx context.Context, cartID uuid.UUID, AdvertID uuid.UUID) error {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("deleting advert from cart in db", zap.String("cart_id", cartID.String()), zap.String("Advert_id", AdvertID.String()))
 
-	cmdTag, err := c.DB.Exec(c.ctx, queryDeleteAdvertFromCart, cartID, AdvertID)
+	cmdTag, err := c.DB.Exec(ctx, queryDeleteAdvertFromCart, cartID, AdvertID)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
 		return repository.ErrCartOrAdvertNotFound
@@ -123,11 +121,11 @@ func (c *CartDB) DeleteAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error {
 	return nil
 }
 
-func (c *CartDB) GetAdvertsByCartId(cartID uuid.UUID) ([]entity.Advert, error) {
-	logger := middleware.GetLogger(c.ctx)
+func (c *CartDB) GetAdvertsByCartId(ctx context.Context, cartID uuid.UUID) ([]entity.Advert, error) {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting adverts by cart id from db", zap.String("cart_id", cartID.String()))
 
-	rows, err := c.DB.Query(c.ctx, queryGetAdvertsByCartID, cartID)
+	rows, err := c.DB.Query(ctx, queryGetAdvertsByCartID, cartID)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
 		return nil, repository.ErrCartNotFound
@@ -150,11 +148,11 @@ func (c *CartDB) GetAdvertsByCartId(cartID uuid.UUID) ([]entity.Advert, error) {
 	return Adverts, nil
 }
 
-func (c *CartDB) UpdateStatus(tx pgx.Tx, cartID uuid.UUID, status entity.CartStatus) error {
-	logger := middleware.GetLogger(c.ctx)
+func (c *CartDB) UpdateStatus(ctx context.Context, tx pgx.Tx, cartID uuid.UUID, status entity.CartStatus) error {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("updating cart status in db", zap.String("cart_id", cartID.String()), zap.String("status", string(status)))
 
-	_, err := tx.Exec(c.ctx, queryUpdateCartStatus, cartID, status)
+	_, err := tx.Exec(ctx, queryUpdateCartStatus, cartID, status)
 	switch {
 	case err != nil:
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -167,12 +165,12 @@ func (c *CartDB) UpdateStatus(tx pgx.Tx, cartID uuid.UUID, status entity.CartSta
 	return nil
 }
 
-func (c *CartDB) GetById(cartID uuid.UUID) (entity.Cart, error) {
+func (c *CartDB) GetById(ctx context.Context, cartID uuid.UUID) (entity.Cart, error) {
 	var cart entity.Cart
-	logger := middleware.GetLogger(c.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting cart by id from db", zap.String("cart_id", cartID.String()))
 
-	err := c.DB.QueryRow(c.ctx, queryGetCartByID, cartID).Scan(&cart.ID, &cart.UserID, &cart.Status)
+	err := c.DB.QueryRow(ctx, queryGetCartByID, cartID).Scan(&cart.ID, &cart.UserID, &cart.Status)
 	switch {
 	case errors.Is(err, pgx.ErrNoRows):
 		return entity.Cart{}, repository.ErrCartNotFound
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
@@ -24,15 +23,11 @@ func setupCartMockDB(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter)
 
 func setupCartTest(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter, *CartDB, func()) {
 	mockPool, adapter := setupCartMockDB(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
 	repo := &CartDB{
-		DB:  adapter,
-		ctx: ctx,
+		DB: adapter,
 	}
 
 	return mockPool, adapter, repo, func() {
-		cancel()
 		mockPool.Close()
 	}
 }
@@ -49,7 +44,7 @@ func TestCartDB_GetCartByUserID(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "status"}).
 			AddRow(cartID, userID, entity.CartStatusActive))
 
-	cart, err := repo.GetByUserId(userID)
+	cart, err := repo.GetByUserId(context.Background(), userID)
 	assert.NoError(t, err)
 	assert.Equal(t, cartID, cart.ID)
 	assert.Equal(t, entity.CartStatusActive, cart.Status)
@@ -58,7 +53,7 @@ func TestCartDB_GetCartByUserID(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(pgx.ErrNoRows)
 
-	cart, err = repo.GetByUserId(userID)
+	cart, err = repo.GetByUserId(context.Background(), userID)
 	assert.Error(t, err)
 	assert.Equal(t, repository.ErrCartNotFound, err)
 	assert.Equal(t, uuid.Nil, cart.ID)
@@ -67,7 +62,7 @@ func TestCartDB_GetCartByUserID(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(errors.New("error getting cart by user id"))
 
-	cart, err = repo.GetByUserId(userID)
+	cart, err = repo.GetByUserId(context.Background(), userID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error getting cart by user id")
 	assert.Equal(t, uuid.Nil, cart.ID)
@@ -94,7 +89,7 @@ func TestCartDB_DeleteAdvertFromCart(t *testing.T) {
 	mockTx, err := mockPool.Begin(context.Background())
 	assert.NoError(t, err)
 
-	err = repo.DeleteAdvert(cartID, advertID)
+	err = repo.DeleteAdvert(context.Background(), cartID, advertID)
 	assert.NoError(t, err)
 
 	err = mockTx.Commit(context.Background())
@@ -111,7 +106,7 @@ func TestCartDB_DeleteAdvertFromCart(t *testing.T) {
 	mockTx2, err := mockPool.Begin(context.Background())
 	assert.NoError(t, err)
 
-	err = repo.DeleteAdvert(cartID, advertID)
+	err = repo.DeleteAdvert(context.Background(), cartID, advertID)
 	assert.Error(t, err)
 	assert.Equal(t, repository.ErrCartOrAdvertNotFound, err)
 
@@ -129,7 +124,7 @@ func TestCartDB_DeleteAdvertFromCart(t *testing.T) {
 	mockTx3, err := mockPool.Begin(context.Background())
 	assert.NoError(t, err)
 
-	err = repo.DeleteAdvert(cartID, advertID)
+	err = repo.DeleteAdvert(context.Background(), cartID, advertID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error deleting Advert from cart")
 
@@ -161,7 +156,7 @@ func TestCartDB_GetAdvertsByCartID(t *testing.T) {
 				entity.AdvertStatusActive,
 			))
 
-	adverts, err := repo.GetAdvertsByCartId(cartID)
+	adverts, err := repo.GetAdvertsByCartId(context.Background(), cartID)
 	assert.NoError(t, err)
 	assert.Len(t, adverts, 1)
 	assert.Equal(t, "Test Advert", adverts[0].Title)
@@ -170,7 +165,7 @@ func TestCartDB_GetAdvertsByCartID(t *testing.T) {
 		WithArgs(cartID).
 		WillReturnError(pgx.ErrNoRows)
 
-	adverts, err = repo.GetAdvertsByCartId(cartID)
+	adverts, err = repo.GetAdvertsByCartId(context.Background(), cartID)
 	assert.Error(t, err)
 	assert.Equal(t, repository.ErrCartNotFound, err)
 	assert.Nil(t, adverts)
@@ -179,7 +174,7 @@ func TestCartDB_GetAdvertsByCartID(t *testing.T) {
 		WithArgs(cartID).
 		WillReturnError(errors.New("query error"))
 
-	adverts, err = repo.GetAdvertsByCartId(cartID)
+	adverts, err = repo.GetAdvertsByCartId(context.Background(), cartID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error getting adverts by cart id")
 	assert.Nil(t, adverts)
@@ -13,7 +13,6 @@ import (
 
 type CategoryDB struct {
 	DB      DBExecutor
-	ctx     context.Context
 	timeout time.Duration
 }
 
@@ -28,17 +27,16 @@ func NewCategoryRepository(db *pgxpool.Pool, logger *zap.Logger, ctx context.Con
 	}
 	return &CategoryDB{
 		DB:      db,
-		ctx:     ctx,
 		timeout: timeout,
 	}, nil
 }
 
-func (c *CategoryDB) Get() ([]*entity.Category, error) {
+func (c *CategoryDB) Get(ctx context.Context) ([]*entity.Category, error) {
 	var categories []*entity.Category
 
-	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(c.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting categories from db")
 
 	rows, err := c.DB.Query(ctx, getCategoryQuery)
@@ -21,16 +21,12 @@ func setupCategoryMockDB(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdap
 
 func setupCategoryTest(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter, *CategoryDB, func()) {
 	mockPool, adapter := setupCategoryMockDB(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
 	repo := &CategoryDB{
 		DB:      adapter,
-		ctx:     ctx,
 		timeout: 10 * time.Second,
 	}
 
 	return mockPool, adapter, repo, func() {
-		cancel()
 		mockPool.Close()
 	}
 }
@@ -44,7 +40,7 @@ func TestCategoryDB_GetCategories(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "title"}).
 			AddRow(uuid.New(), "Test Category"))
 
-	categories, err := repo.Get()
+	categories, err := repo.Get(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, categories, 1)
 
@@ -52,7 +48,7 @@ func TestCategoryDB_GetCategories(t *testing.T) {
 	mockPool.ExpectQuery(getCategoryQuery).
 		WillReturnError(errors.New("query error"))
 
-	categories, err = repo.Get()
+	categories, err = repo.Get(context.Background())
 	assert.Error(t, err)
 	assert.Nil(t, categories)
 
@@ -0,0 +1,222 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	insertDealQuery = `
+		INSERT INTO safe_deal (purchase_id, buyer_id, seller_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (purchase_id) DO NOTHING
+		RETURNING id, status, created_at, updated_at`
+
+	selectDealQuery = `
+		SELECT d.id, d.purchase_id, d.buyer_id, d.seller_id, s.user_id, d.status, d.held_at, d.shipped_at,
+			d.disputed_by, COALESCE(d.dispute_reason, ''), d.created_at, d.updated_at
+		FROM safe_deal d
+		JOIN seller s ON s.id = d.seller_id`
+
+	selectDealByIDQuery = selectDealQuery + `
+		WHERE d.id = $1`
+
+	selectDealByPurchaseIDQuery = selectDealQuery + `
+		WHERE d.purchase_id = $1`
+
+	// Время блокировки и отправки фиксируется при переходе, от него отсчитываются сроки сделки
+	updateDealStatusQuery = `
+		UPDATE safe_deal
+		SET status = $3,
+			held_at = CASE WHEN $3 = 'held' THEN CURRENT_TIMESTAMP ELSE held_at END,
+			shipped_at = CASE WHEN $3 = 'shipped' THEN CURRENT_TIMESTAMP ELSE shipped_at END
+		WHERE id = $1 AND status = $2`
+
+	openDealDisputeQuery = `
+		UPDATE safe_deal
+		SET status = 'disputed', disputed_by = $3, dispute_reason = $4
+		WHERE id = $1 AND status = $2`
+
+	// Сделка остается в releasing или refunding, если процесс упал во время расчета
+	// или не смог вернуть ее на прежний этап
+	selectExpiredDealsQuery = selectDealQuery + `
+		WHERE (d.status = 'held' AND d.held_at <= CURRENT_TIMESTAMP - $1::interval)
+			OR (d.status = 'shipped' AND d.shipped_at <= CURRENT_TIMESTAMP - $2::interval)
+			OR (d.status IN ('releasing', 'refunding') AND d.updated_at <= CURRENT_TIMESTAMP - $3::interval)
+		ORDER BY d.updated_at
+		LIMIT $4`
+
+	selectDisputedDealsQuery = selectDealQuery + `
+		WHERE d.status = 'disputed'
+		ORDER BY d.updated_at
+		LIMIT $1 OFFSET $2`
+)
+
+type SafeDealDB struct {
+	DB      DBExecutor
+	timeout time.Duration
+}
+
+func NewSafeDealRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Duration) (repository.SafeDealRepository, error) {
+	if err := db.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &SafeDealDB{
+		DB:      db,
+		timeout: timeout,
+	}, nil
+}
+
+func (r *SafeDealDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return tx, nil
+}
+
+func (r *SafeDealDB) Add(ctx context.Context, deal *entity.SafeDeal) (*entity.SafeDeal, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("adding safe deal to db", zap.String("purchase_id", deal.PurchaseID.String()))
+
+	created := *deal
+	err := r.DB.QueryRow(ctx, insertDealQuery, deal.PurchaseID, deal.BuyerID, deal.SellerID).
+		Scan(&created.ID, &created.Status, &created.CreatedAt, &created.UpdatedAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrDealAlreadyExists
+	case err != nil:
+		logger.Error("failed to add safe deal", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return &created, nil
+}
+
+func scanDeal(row pgx.Row) (*entity.SafeDeal, error) {
+	var deal entity.SafeDeal
+	err := row.Scan(
+		&deal.ID,
+		&deal.PurchaseID,
+		&deal.BuyerID,
+		&deal.SellerID,
+		&deal.SellerUserID,
+		&deal.Status,
+		&deal.HeldAt,
+		&deal.ShippedAt,
+		&deal.DisputedBy,
+		&deal.DisputeReason,
+		&deal.CreatedAt,
+		&deal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &deal, nil
+}
+
+func (r *SafeDealDB) get(ctx context.Context, query string, arg interface{}) (*entity.SafeDeal, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+
+	deal, err := scanDeal(r.DB.QueryRow(ctx, query, arg))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrDealNotFound
+	case err != nil:
+		logger.Error("failed to get safe deal", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return deal, nil
+}
+
+func (r *SafeDealDB) GetById(ctx context.Context, dealID uuid.UUID) (*entity.SafeDeal, error) {
+	middleware.GetLogger(ctx).Info("getting safe deal by id from db", zap.String("deal_id", dealID.String()))
+	return r.get(ctx, selectDealByIDQuery, dealID)
+}
+
+func (r *SafeDealDB) GetByPurchaseId(ctx context.Context, purchaseID uuid.UUID) (*entity.SafeDeal, error) {
+	middleware.GetLogger(ctx).Info("getting safe deal by purchase id from db", zap.String("purchase_id", purchaseID.String()))
+	return r.get(ctx, selectDealByPurchaseIDQuery, purchaseID)
+}
+
+func (r *SafeDealDB) UpdateStatus(ctx context.Context, tx pgx.Tx, dealID uuid.UUID, from, to entity.DealStatus) (bool, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("updating safe deal status in db", zap.String("deal_id", dealID.String()),
+		zap.String("from", string(from)), zap.String("to", string(to)))
+
+	tag, err := tx.Exec(ctx, updateDealStatusQuery, dealID, from, to)
+	if err != nil {
+		logger.Error("failed to update safe deal status", zap.Error(err))
+		return false, entity.PSQLWrap(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *SafeDealDB) OpenDispute(ctx context.Context, dealID uuid.UUID, from entity.DealStatus, userID uuid.UUID, reason string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("opening safe deal dispute in db", zap.String("deal_id", dealID.String()),
+		zap.String("user_id", userID.String()))
+
+	tag, err := r.DB.Exec(ctx, openDealDisputeQuery, dealID, from, userID, reason)
+	if err != nil {
+		logger.Error("failed to open safe deal dispute", zap.Error(err))
+		return false, entity.PSQLWrap(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *SafeDealDB) list(ctx context.Context, query string, args ...interface{}) ([]*entity.SafeDeal, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var deals []*entity.SafeDeal
+	for rows.Next() {
+		deal, err := scanDeal(rows)
+		if err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		deals = append(deals, deal)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return deals, nil
+}
+
+func (r *SafeDealDB) GetExpired(ctx context.Context, shipTimeout, confirmTimeout, settleTimeout time.Duration, limit int) ([]*entity.SafeDeal, error) {
+	middleware.GetLogger(ctx).Info("getting expired safe deals from db", zap.Duration("ship_timeout", shipTimeout),
+		zap.Duration("confirm_timeout", confirmTimeout), zap.Duration("settle_timeout", settleTimeout))
+	return r.list(ctx, selectExpiredDealsQuery, shipTimeout, confirmTimeout, settleTimeout, limit)
+}
+
+func (r *SafeDealDB) GetDisputed(ctx context.Context, limit, offset int) ([]*entity.SafeDeal, error) {
+	middleware.GetLogger(ctx).Info("getting disputed safe deals from db", zap.Int("limit", limit), zap.Int("offset", offset))
+	return r.list(ctx, selectDisputedDealsQuery, limit, offset)
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSafeDealTest(t *testing.T) (pgxmock.PgxPoolIface, *SafeDealDB) {
+	mockPool, adapter := setupMockDB(t)
+	t.Cleanup(func() {
+		mockPool.Close()
+	})
+	return mockPool, &SafeDealDB{DB: adapter, timeout: 10 * time.Second}
+}
+
+var dealColumns = []string{"id", "purchase_id", "buyer_id", "seller_id", "user_id", "status", "held_at", "shipped_at",
+	"disputed_by", "dispute_reason", "created_at", "updated_at"}
+
+func TestSafeDealDB_Add(t *testing.T) {
+	mockPool, repo := setupSafeDealTest(t)
+	deal := &entity.SafeDeal{PurchaseID: uuid.New(), BuyerID: uuid.New(), SellerID: uuid.New()}
+
+	t.Run("Success", func(t *testing.T) {
+		id, now := uuid.New(), time.Now()
+		mockPool.ExpectQuery("INSERT INTO safe_deal").
+			WithArgs(deal.PurchaseID, deal.BuyerID, deal.SellerID).
+			WillReturnRows(mockPool.NewRows([]string{"id", "status", "created_at", "updated_at"}).
+				AddRow(id, entity.DealStatusAwaitingPayment, now, now))
+
+		created, err := repo.Add(context.Background(), deal)
+		require.NoError(t, err)
+		assert.Equal(t, id, created.ID)
+		assert.Equal(t, entity.DealStatusAwaitingPayment, created.Status)
+		assert.Equal(t, deal.SellerID, created.SellerID)
+	})
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		mockPool.ExpectQuery("INSERT INTO safe_deal").
+			WithArgs(deal.PurchaseID, deal.BuyerID, deal.SellerID).
+			WillReturnRows(mockPool.NewRows([]string{"id", "status", "created_at", "updated_at"}))
+
+		_, err := repo.Add(context.Background(), deal)
+		assert.ErrorIs(t, err, repository.ErrDealAlreadyExists)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockPool.ExpectQuery("INSERT INTO safe_deal").
+			WithArgs(deal.PurchaseID, deal.BuyerID, deal.SellerID).
+			WillReturnError(errors.New("db error"))
+
+		_, err := repo.Add(context.Background(), deal)
+		assert.ErrorIs(t, err, entity.ErrPSQL)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestSafeDealDB_Get(t *testing.T) {
+	mockPool, repo := setupSafeDealTest(t)
+	dealID, purchaseID, sellerUserID := uuid.New(), uuid.New(), uuid.New()
+	heldAt := time.Now()
+
+	t.Run("ById", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM safe_deal d .* WHERE d.id = \\$1").
+			WithArgs(dealID).
+			WillReturnRows(mockPool.NewRows(dealColumns).AddRow(dealID, purchaseID, uuid.New(), uuid.New(), sellerUserID,
+				entity.DealStatusHeld, &heldAt, nil, nil, "", heldAt, heldAt))
+
+		deal, err := repo.GetById(context.Background(), dealID)
+		require.NoError(t, err)
+		assert.Equal(t, sellerUserID, deal.SellerUserID)
+		assert.Equal(t, entity.DealStatusHeld, deal.Status)
+		require.NotNil(t, deal.HeldAt)
+		assert.Nil(t, deal.ShippedAt)
+	})
+
+	t.Run("ByPurchaseIdNotFound", func(t *testing.T) {
+		mockPool.ExpectQuery("WHERE d.purchase_id = \\$1").
+			WithArgs(purchaseID).
+			WillReturnRows(mockPool.NewRows(dealColumns))
+
+		_, err := repo.GetByPurchaseId(context.Background(), purchaseID)
+		assert.ErrorIs(t, err, repository.ErrDealNotFound)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestSafeDealDB_UpdateStatus(t *testing.T) {
+	mockPool, repo := setupSafeDealTest(t)
+	dealID := uuid.New()
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	mockPool.ExpectExec("UPDATE safe_deal").
+		WithArgs(dealID, entity.DealStatusHeld, entity.DealStatusShipped).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	changed, err := repo.UpdateStatus(context.Background(), tx, dealID, entity.DealStatusHeld, entity.DealStatusShipped)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	mockPool.ExpectExec("UPDATE safe_deal").
+		WithArgs(dealID, entity.DealStatusHeld, entity.DealStatusShipped).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	changed, err = repo.UpdateStatus(context.Background(), tx, dealID, entity.DealStatusHeld, entity.DealStatusShipped)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestSafeDealDB_OpenDispute(t *testing.T) {
+	mockPool, repo := setupSafeDealTest(t)
+	dealID, userID := uuid.New(), uuid.New()
+
+	mockPool.ExpectExec("SET status = 'disputed'").
+		WithArgs(dealID, entity.DealStatusShipped, userID, "не пришел").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	opened, err := repo.OpenDispute(context.Background(), dealID, entity.DealStatusShipped, userID, "не пришел")
+	require.NoError(t, err)
+	assert.True(t, opened)
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestSafeDealDB_GetExpired(t *testing.T) {
+	mockPool, repo := setupSafeDealTest(t)
+	now := time.Now()
+
+	mockPool.ExpectQuery("d.status = 'held' AND d.held_at <= CURRENT_TIMESTAMP - \\$1::interval").
+		WithArgs(72*time.Hour, 168*time.Hour, 10*time.Minute, 100).
+		WillReturnRows(mockPool.NewRows(dealColumns).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), entity.DealStatusHeld, &now, nil, nil, "", now, now).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), entity.DealStatusShipped, &now, &now, nil, "", now, now).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), entity.DealStatusReleasing, &now, &now, nil, "", now, now))
+
+	deals, err := repo.GetExpired(context.Background(), 72*time.Hour, 168*time.Hour, 10*time.Minute, 100)
+	require.NoError(t, err)
+	require.Len(t, deals, 3)
+	assert.Equal(t, entity.DealStatusShipped, deals[1].Status)
+	assert.Equal(t, entity.DealStatusReleasing, deals[2].Status)
+
+	mockPool.ExpectQuery("WHERE d.status = 'disputed'").
+		WithArgs(20, 0).
+		WillReturnError(errors.New("db error"))
+	_, err = repo.GetDisputed(context.Background(), 20, 0)
+	assert.ErrorIs(t, err, entity.ErrPSQL)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -0,0 +1,344 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	selectDeliveryOptionsQuery = `
+		SELECT advert_id, type, price, min_days, max_days
+		FROM advert_delivery_option
+		WHERE advert_id = $1
+		ORDER BY type`
+
+	selectDeliveryOptionsByAdvertsQuery = `
+		SELECT advert_id, type, price, min_days, max_days
+		FROM advert_delivery_option
+		WHERE advert_id = ANY($1)
+		ORDER BY advert_id, type`
+
+	deleteDeliveryOptionsQuery = `
+		DELETE FROM advert_delivery_option
+		WHERE advert_id = $1`
+
+	insertDeliveryOptionQuery = `
+		INSERT INTO advert_delivery_option (advert_id, type, price, min_days, max_days)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	updateAdvertHasDeliveryQuery = `
+		UPDATE advert
+		SET has_delivery = $2
+		WHERE id = $1`
+
+	insertShipmentQuery = `
+		INSERT INTO shipment (purchase_id, seller_id, buyer_id, type, cost, min_days, max_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (purchase_id, seller_id) DO NOTHING`
+
+	selectShipmentQuery = `
+		SELECT sh.id, sh.purchase_id, sh.seller_id, s.user_id, sh.buyer_id, sh.type, sh.cost, sh.min_days, sh.max_days,
+			COALESCE(sh.carrier, ''), COALESCE(sh.tracking_number, ''), sh.status, sh.shipped_at, sh.delivered_at,
+			sh.created_at, sh.updated_at
+		FROM shipment sh
+		JOIN seller s ON s.id = sh.seller_id`
+
+	selectShipmentByIDQuery = selectShipmentQuery + `
+		WHERE sh.id = $1`
+
+	selectShipmentsByPurchaseIDQuery = selectShipmentQuery + `
+		WHERE sh.purchase_id = $1
+		ORDER BY sh.created_at, sh.id`
+
+	setShipmentTrackingQuery = `
+		UPDATE shipment
+		SET carrier = $2, tracking_number = $3, status = 'in_transit', shipped_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'awaiting_shipment'`
+
+	selectTrackableShipmentsQuery = selectShipmentQuery + `
+		WHERE sh.status IN ('in_transit', 'ready_for_pickup')
+		ORDER BY sh.tracked_at NULLS FIRST, sh.shipped_at
+		LIMIT $1`
+
+	// Время проверки обновляется и без смены статуса, чтобы следующая пачка брала другие отправления
+	updateShipmentTrackingQuery = `
+		UPDATE shipment
+		SET status = $3,
+			tracked_at = CURRENT_TIMESTAMP,
+			delivered_at = CASE WHEN $3 = 'delivered' THEN CURRENT_TIMESTAMP ELSE delivered_at END
+		WHERE id = $1 AND status = $2`
+
+	insertShipmentEventQuery = `
+		INSERT INTO shipment_event (shipment_id, status, description, location, occurred_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		ON CONFLICT ON CONSTRAINT shipment_event_unique DO NOTHING`
+
+	selectShipmentEventsQuery = `
+		SELECT shipment_id, status, description, COALESCE(location, ''), occurred_at
+		FROM shipment_event
+		WHERE shipment_id = $1
+		ORDER BY occurred_at`
+)
+
+type DeliveryDB struct {
+	DB      DBExecutor
+	timeout time.Duration
+}
+
+func NewDeliveryRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Duration) (repository.DeliveryRepository, error) {
+	if err := db.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &DeliveryDB{
+		DB:      db,
+		timeout: timeout,
+	}, nil
+}
+
+func (r *DeliveryDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return tx, nil
+}
+
+func (r *DeliveryDB) listOptions(ctx context.Context, query string, arg interface{}) ([]*entity.DeliveryOption, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+
+	rows, err := r.DB.Query(ctx, query, arg)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var options []*entity.DeliveryOption
+	for rows.Next() {
+		var option entity.DeliveryOption
+		if err := rows.Scan(&option.AdvertID, &option.Type, &option.Price, &option.MinDays, &option.MaxDays); err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		options = append(options, &option)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return options, nil
+}
+
+func (r *DeliveryDB) GetOptions(ctx context.Context, advertID uuid.UUID) ([]*entity.DeliveryOption, error) {
+	middleware.GetLogger(ctx).Info("getting delivery options from db", zap.String("advert_id", advertID.String()))
+	return r.listOptions(ctx, selectDeliveryOptionsQuery, advertID)
+}
+
+func (r *DeliveryDB) GetOptionsByAdverts(ctx context.Context, advertIDs []uuid.UUID) ([]*entity.DeliveryOption, error) {
+	middleware.GetLogger(ctx).Info("getting delivery options of adverts from db", zap.Int("adverts", len(advertIDs)))
+	return r.listOptions(ctx, selectDeliveryOptionsByAdvertsQuery, advertIDs)
+}
+
+func (r *DeliveryDB) ReplaceOptions(ctx context.Context, tx pgx.Tx, advertID uuid.UUID, options []*entity.DeliveryOption) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("replacing delivery options in db", zap.String("advert_id", advertID.String()),
+		zap.Int("options", len(options)))
+
+	if _, err := tx.Exec(ctx, deleteDeliveryOptionsQuery, advertID); err != nil {
+		logger.Error("failed to delete delivery options", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+	for _, option := range options {
+		if _, err := tx.Exec(ctx, insertDeliveryOptionQuery, advertID, option.Type, option.Price,
+			option.MinDays, option.MaxDays); err != nil {
+			logger.Error("failed to insert delivery option", zap.Error(err))
+			return entity.PSQLWrap(err)
+		}
+	}
+	if _, err := tx.Exec(ctx, updateAdvertHasDeliveryQuery, advertID, len(options) > 0); err != nil {
+		logger.Error("failed to update advert delivery flag", zap.Error(err))
+		return entity.PSQLWrap(err)
+	}
+	return nil
+}
+
+func (r *DeliveryDB) AddShipments(ctx context.Context, tx pgx.Tx, shipments []*entity.Shipment) error {
+	logger := middleware.GetLogger(ctx)
+
+	for _, shipment := range shipments {
+		logger.Info("adding shipment to db", zap.String("purchase_id", shipment.PurchaseID.String()),
+			zap.String("seller_id", shipment.SellerID.String()))
+		if _, err := tx.Exec(ctx, insertShipmentQuery, shipment.PurchaseID, shipment.SellerID, shipment.BuyerID,
+			shipment.Type, shipment.Cost, shipment.MinDays, shipment.MaxDays); err != nil {
+			logger.Error("failed to add shipment", zap.Error(err))
+			return entity.PSQLWrap(err)
+		}
+	}
+	return nil
+}
+
+func scanShipment(row pgx.Row) (*entity.Shipment, error) {
+	var shipment entity.Shipment
+	err := row.Scan(
+		&shipment.ID,
+		&shipment.PurchaseID,
+		&shipment.SellerID,
+		&shipment.SellerUserID,
+		&shipment.BuyerID,
+		&shipment.Type,
+		&shipment.Cost,
+		&shipment.MinDays,
+		&shipment.MaxDays,
+		&shipment.Carrier,
+		&shipment.TrackingNumber,
+		&shipment.Status,
+		&shipment.ShippedAt,
+		&shipment.DeliveredAt,
+		&shipment.CreatedAt,
+		&shipment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+func (r *DeliveryDB) GetShipmentById(ctx context.Context, shipmentID uuid.UUID) (*entity.Shipment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting shipment by id from db", zap.String("shipment_id", shipmentID.String()))
+
+	shipment, err := scanShipment(r.DB.QueryRow(ctx, selectShipmentByIDQuery, shipmentID))
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrShipmentNotFound
+	case err != nil:
+		logger.Error("failed to get shipment", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return shipment, nil
+}
+
+func (r *DeliveryDB) listShipments(ctx context.Context, query string, arg interface{}) ([]*entity.Shipment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+
+	rows, err := r.DB.Query(ctx, query, arg)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var shipments []*entity.Shipment
+	for rows.Next() {
+		shipment, err := scanShipment(rows)
+		if err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		shipments = append(shipments, shipment)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return shipments, nil
+}
+
+func (r *DeliveryDB) GetShipmentsByPurchaseId(ctx context.Context, purchaseID uuid.UUID) ([]*entity.Shipment, error) {
+	middleware.GetLogger(ctx).Info("getting shipments by purchase id from db", zap.String("purchase_id", purchaseID.String()))
+	return r.listShipments(ctx, selectShipmentsByPurchaseIDQuery, purchaseID)
+}
+
+func (r *DeliveryDB) SetTracking(ctx context.Context, shipmentID uuid.UUID, carrier, trackingNumber string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("setting shipment tracking number in db", zap.String("shipment_id", shipmentID.String()),
+		zap.String("carrier", carrier))
+
+	tag, err := r.DB.Exec(ctx, setShipmentTrackingQuery, shipmentID, carrier, trackingNumber)
+	if err != nil {
+		logger.Error("failed to set shipment tracking number", zap.Error(err))
+		return false, entity.PSQLWrap(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *DeliveryDB) GetTrackable(ctx context.Context, limit int) ([]*entity.Shipment, error) {
+	middleware.GetLogger(ctx).Info("getting trackable shipments from db", zap.Int("limit", limit))
+	return r.listShipments(ctx, selectTrackableShipmentsQuery, limit)
+}
+
+func (r *DeliveryDB) UpdateTracking(ctx context.Context, tx pgx.Tx, shipmentID uuid.UUID, from, to entity.ShipmentStatus) (bool, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("updating shipment tracking in db", zap.String("shipment_id", shipmentID.String()),
+		zap.String("from", string(from)), zap.String("to", string(to)))
+
+	tag, err := tx.Exec(ctx, updateShipmentTrackingQuery, shipmentID, from, to)
+	if err != nil {
+		logger.Error("failed to update shipment tracking", zap.Error(err))
+		return false, entity.PSQLWrap(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *DeliveryDB) AddEvents(ctx context.Context, tx pgx.Tx, events []entity.ShipmentEvent) error {
+	logger := middleware.GetLogger(ctx)
+
+	for _, event := range events {
+		if _, err := tx.Exec(ctx, insertShipmentEventQuery, event.ShipmentID, event.Status, event.Description,
+			event.Location, event.OccurredAt); err != nil {
+			logger.Error("failed to add shipment event", zap.Error(err))
+			return entity.PSQLWrap(err)
+		}
+	}
+	return nil
+}
+
+func (r *DeliveryDB) GetEvents(ctx context.Context, shipmentID uuid.UUID) ([]entity.ShipmentEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting shipment events from db", zap.String("shipment_id", shipmentID.String()))
+
+	rows, err := r.DB.Query(ctx, selectShipmentEventsQuery, shipmentID)
+	if err != nil {
+		logger.Error("failed to execute query", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer rows.Close()
+
+	var events []entity.ShipmentEvent
+	for rows.Next() {
+		var event entity.ShipmentEvent
+		if err := rows.Scan(&event.ShipmentID, &event.Status, &event.Description, &event.Location,
+			&event.OccurredAt); err != nil {
+			logger.Error("failed to scan row", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over rows", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return events, nil
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDeliveryTest(t *testing.T) (pgxmock.PgxPoolIface, *DeliveryDB) {
+	mockPool, adapter := setupMockDB(t)
+	t.Cleanup(func() {
+		mockPool.Close()
+	})
+	return mockPool, &DeliveryDB{DB: adapter, timeout: 10 * time.Second}
+}
+
+var shipmentColumns = []string{"id", "purchase_id", "seller_id", "user_id", "buyer_id", "type", "cost", "min_days",
+	"max_days", "carrier", "tracking_number", "status", "shipped_at", "delivered_at", "created_at", "updated_at"}
+
+func TestDeliveryDB_GetOptions(t *testing.T) {
+	mockPool, repo := setupDeliveryTest(t)
+	advertID := uuid.New()
+
+	mockPool.ExpectQuery("FROM advert_delivery_option").
+		WithArgs(advertID).
+		WillReturnRows(mockPool.NewRows([]string{"advert_id", "type", "price", "min_days", "max_days"}).
+			AddRow(advertID, entity.DeliveryOptionCourier, uint(500), uint(1), uint(2)).
+			AddRow(advertID, entity.DeliveryOptionPost, uint(300), uint(5), uint(10)))
+
+	options, err := repo.GetOptions(context.Background(), advertID)
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	assert.Equal(t, entity.DeliveryOptionPost, options[1].Type)
+	assert.Equal(t, uint(300), options[1].Price)
+
+	advertIDs := []uuid.UUID{advertID}
+	mockPool.ExpectQuery("WHERE advert_id = ANY\\(\\$1\\)").
+		WithArgs(advertIDs).
+		WillReturnError(errors.New("db error"))
+	_, err = repo.GetOptionsByAdverts(context.Background(), advertIDs)
+	assert.ErrorIs(t, err, entity.ErrPSQL)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestDeliveryDB_ReplaceOptions(t *testing.T) {
+	mockPool, repo := setupDeliveryTest(t)
+	advertID := uuid.New()
+	options := []*entity.DeliveryOption{{Type: entity.DeliveryOptionParcelLocker, Price: 250, MinDays: 2, MaxDays: 4}}
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		mockPool.ExpectExec("DELETE FROM advert_delivery_option").
+			WithArgs(advertID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mockPool.ExpectExec("INSERT INTO advert_delivery_option").
+			WithArgs(advertID, entity.DeliveryOptionParcelLocker, uint(250), uint(2), uint(4)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mockPool.ExpectExec("UPDATE advert").
+			WithArgs(advertID, true).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		require.NoError(t, repo.ReplaceOptions(context.Background(), tx, advertID, options))
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		mockPool.ExpectExec("DELETE FROM advert_delivery_option").
+			WithArgs(advertID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mockPool.ExpectExec("UPDATE advert").
+			WithArgs(advertID, false).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		require.NoError(t, repo.ReplaceOptions(context.Background(), tx, advertID, nil))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockPool.ExpectExec("DELETE FROM advert_delivery_option").
+			WithArgs(advertID).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.ReplaceOptions(context.Background(), tx, advertID, options)
+		assert.ErrorIs(t, err, entity.ErrPSQL)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestDeliveryDB_AddShipments(t *testing.T) {
+	mockPool, repo := setupDeliveryTest(t)
+	shipment := &entity.Shipment{PurchaseID: uuid.New(), SellerID: uuid.New(), BuyerID: uuid.New(),
+		Type: entity.DeliveryOptionCourier, Cost: 500, MinDays: 1, MaxDays: 2}
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	mockPool.ExpectExec("INSERT INTO shipment").
+		WithArgs(shipment.PurchaseID, shipment.SellerID, shipment.BuyerID, shipment.Type, shipment.Cost,
+			shipment.MinDays, shipment.MaxDays).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	require.NoError(t, repo.AddShipments(context.Background(), tx, []*entity.Shipment{shipment}))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestDeliveryDB_GetShipment(t *testing.T) {
+	mockPool, repo := setupDeliveryTest(t)
+	shipmentID, purchaseID, sellerUserID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	t.Run("ById", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM shipment sh .* WHERE sh.id = \\$1").
+			WithArgs(shipmentID).
+			WillReturnRows(mockPool.NewRows(shipmentColumns).AddRow(shipmentID, purchaseID, uuid.New(), sellerUserID,
+				uuid.New(), entity.DeliveryOptionCourier, uint(500), uint(1), uint(2), "fake", "AB123456789RU",
+				entity.ShipmentStatusInTransit, &now, nil, now, now))
+
+		shipment, err := repo.GetShipmentById(context.Background(), shipmentID)
+		require.NoError(t, err)
+		assert.Equal(t, sellerUserID, shipment.SellerUserID)
+		assert.Equal(t, "AB123456789RU", shipment.TrackingNumber)
+		assert.Nil(t, shipment.DeliveredAt)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockPool.ExpectQuery("WHERE sh.id = \\$1").
+			WithArgs(shipmentID).
+			WillReturnRows(mockPool.NewRows(shipmentColumns))
+
+		_, err := repo.GetShipmentById(context.Background(), shipmentID)
+		assert.ErrorIs(t, err, repository.ErrShipmentNotFound)
+	})
+
+	t.Run("Trackable", func(t *testing.T) {
+		mockPool.ExpectQuery("WHERE sh.status IN \\('in_transit', 'ready_for_pickup'\\)").
+			WithArgs(100).
+			WillReturnRows(mockPool.NewRows(shipmentColumns).AddRow(shipmentID, purchaseID, uuid.New(), sellerUserID,
+				uuid.New(), entity.DeliveryOptionPost, uint(300), uint(5), uint(10), "fake", "AB123456789RU",
+				entity.ShipmentStatusReadyForPickup, &now, nil, now, now))
+
+		shipments, err := repo.GetTrackable(context.Background(), 100)
+		require.NoError(t, err)
+		require.Len(t, shipments, 1)
+		assert.Equal(t, entity.ShipmentStatusReadyForPickup, shipments[0].Status)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestDeliveryDB_Tracking(t *testing.T) {
+	mockPool, repo := setupDeliveryTest(t)
+	shipmentID := uuid.New()
+	occurredAt := time.Now()
+
+	mockPool.ExpectExec("SET carrier = \\$2, tracking_number = \\$3, status = 'in_transit'").
+		WithArgs(shipmentID, "fake", "AB123456789RU").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	set, err := repo.SetTracking(context.Background(), shipmentID, "fake", "AB123456789RU")
+	require.NoError(t, err)
+	assert.False(t, set)
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	mockPool.ExpectExec("INSERT INTO shipment_event").
+		WithArgs(shipmentID, entity.ShipmentStatusDelivered, "Вручено", "", occurredAt).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	require.NoError(t, repo.AddEvents(context.Background(), tx, []entity.ShipmentEvent{{
+		ShipmentID: shipmentID, Status: entity.ShipmentStatusDelivered, Description: "Вручено", OccurredAt: occurredAt,
+	}}))
+
+	mockPool.ExpectExec("UPDATE shipment").
+		WithArgs(shipmentID, entity.ShipmentStatusInTransit, entity.ShipmentStatusDelivered).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	changed, err := repo.UpdateTracking(context.Background(), tx, shipmentID, entity.ShipmentStatusInTransit,
+		entity.ShipmentStatusDelivered)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	mockPool.ExpectQuery("FROM shipment_event").
+		WithArgs(shipmentID).
+		WillReturnRows(mockPool.NewRows([]string{"shipment_id", "status", "description", "location", "occurred_at"}).
+			AddRow(shipmentID, entity.ShipmentStatusDelivered, "Вручено", "Пункт выдачи", occurredAt))
+	events, err := repo.GetEvents(context.Background(), shipmentID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "Пункт выдачи", events[0].Location)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
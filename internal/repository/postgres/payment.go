@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	insertPaymentQuery = `
+		INSERT INTO payment (purchase_id, provider, external_id, amount, currency, status, confirmation_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at`
+
+	// Покупатель платежа - владелец корзины, из которой оформлена покупка
+	selectPaymentQuery = `
+		SELECT pm.id, pm.purchase_id, c.user_id, pm.provider, pm.external_id, pm.amount, pm.currency,
+			pm.status, COALESCE(pm.confirmation_url, ''), pm.created_at, pm.updated_at
+		FROM payment pm
+		JOIN purchase p ON p.id = pm.purchase_id
+		JOIN cart c ON c.id = p.cart_id`
+
+	selectPaymentByIDQuery = selectPaymentQuery + `
+		WHERE pm.id = $1`
+
+	selectPaymentByPurchaseIDQuery = selectPaymentQuery + `
+		WHERE pm.purchase_id = $1
+		ORDER BY pm.created_at DESC
+		LIMIT 1`
+
+	selectPaymentByExternalIDQuery = selectPaymentQuery + `
+		WHERE pm.provider = $1 AND pm.external_id = $2`
+
+	updatePaymentStatusQuery = `
+		UPDATE payment
+		SET status = $3
+		WHERE id = $1 AND status = $2`
+)
+
+type PaymentDB struct {
+	DB      DBExecutor
+	timeout time.Duration
+}
+
+func NewPaymentRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Duration) (repository.PaymentRepository, error) {
+	if err := db.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &PaymentDB{
+		DB:      db,
+		timeout: timeout,
+	}, nil
+}
+
+func (r *PaymentDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return tx, nil
+}
+
+func (r *PaymentDB) Add(ctx context.Context, payment *entity.Payment) (*entity.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("adding payment to db", zap.String("purchase_id", payment.PurchaseID.String()),
+		zap.String("external_id", payment.ExternalID))
+
+	created := *payment
+	err := r.DB.QueryRow(ctx, insertPaymentQuery, payment.PurchaseID, payment.Provider, payment.ExternalID,
+		int64(payment.Amount), payment.Currency, payment.Status, payment.ConfirmationURL).
+		Scan(&created.ID, &created.CreatedAt, &created.UpdatedAt)
+	if err != nil {
+		logger.Error("failed to add payment", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	return &created, nil
+}
+
+func (r *PaymentDB) get(ctx context.Context, query string, args ...interface{}) (*entity.Payment, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+
+	var payment entity.Payment
+	var amount int64
+	err := r.DB.QueryRow(ctx, query, args...).Scan(
+		&payment.ID,
+		&payment.PurchaseID,
+		&payment.UserID,
+		&payment.Provider,
+		&payment.ExternalID,
+		&amount,
+		&payment.Currency,
+		&payment.Status,
+		&payment.ConfirmationURL,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrPaymentNotFound
+	case err != nil:
+		logger.Error("failed to get payment", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	payment.Amount = uint(amount)
+	return &payment, nil
+}
+
+func (r *PaymentDB) GetById(ctx context.Context, paymentID uuid.UUID) (*entity.Payment, error) {
+	middleware.GetLogger(ctx).Info("getting payment by id from db", zap.String("payment_id", paymentID.String()))
+	return r.get(ctx, selectPaymentByIDQuery, paymentID)
+}
+
+func (r *PaymentDB) GetByPurchaseId(ctx context.Context, purchaseID uuid.UUID) (*entity.Payment, error) {
+	middleware.GetLogger(ctx).Info("getting payment by purchase id from db", zap.String("purchase_id", purchaseID.String()))
+	return r.get(ctx, selectPaymentByPurchaseIDQuery, purchaseID)
+}
+
+func (r *PaymentDB) GetByExternalId(ctx context.Context, provider, externalID string) (*entity.Payment, error) {
+	middleware.GetLogger(ctx).Info("getting payment by external id from db", zap.String("provider", provider),
+		zap.String("external_id", externalID))
+	return r.get(ctx, selectPaymentByExternalIDQuery, provider, externalID)
+}
+
+func (r *PaymentDB) UpdateStatus(ctx context.Context, tx pgx.Tx, paymentID uuid.UUID, from, to entity.PaymentStatus) (bool, error) {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("updating payment status in db", zap.String("payment_id", paymentID.String()),
+		zap.String("from", string(from)), zap.String("to", string(to)))
+
+	tag, err := tx.Exec(ctx, updatePaymentStatusQuery, paymentID, from, to)
+	if err != nil {
+		logger.Error("failed to update payment status", zap.Error(err))
+		return false, entity.PSQLWrap(err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
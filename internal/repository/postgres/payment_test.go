@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPaymentTest(t *testing.T) (pgxmock.PgxPoolIface, *PaymentDB) {
+	mockPool, adapter := setupMockDB(t)
+	t.Cleanup(func() {
+		mockPool.Close()
+	})
+	return mockPool, &PaymentDB{DB: adapter, timeout: 10 * time.Second}
+}
+
+var paymentColumns = []string{"id", "purchase_id", "user_id", "provider", "external_id", "amount", "currency",
+	"status", "confirmation_url", "created_at", "updated_at"}
+
+func TestPaymentDB_Add(t *testing.T) {
+	mockPool, repo := setupPaymentTest(t)
+	payment := &entity.Payment{
+		PurchaseID:      uuid.New(),
+		UserID:          uuid.New(),
+		Provider:        "fake",
+		ExternalID:      "fake-1",
+		Amount:          150000,
+		Currency:        "RUB",
+		Status:          entity.PaymentStatusPending,
+		ConfirmationURL: "http://pay/1",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		id, now := uuid.New(), time.Now()
+		mockPool.ExpectQuery("INSERT INTO payment").
+			WithArgs(payment.PurchaseID, "fake", "fake-1", int64(150000), "RUB", entity.PaymentStatusPending, "http://pay/1").
+			WillReturnRows(mockPool.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(id, now, now))
+
+		created, err := repo.Add(context.Background(), payment)
+		require.NoError(t, err)
+		assert.Equal(t, id, created.ID)
+		assert.Equal(t, payment.UserID, created.UserID)
+		assert.Equal(t, uint(150000), created.Amount)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockPool.ExpectQuery("INSERT INTO payment").
+			WithArgs(payment.PurchaseID, "fake", "fake-1", int64(150000), "RUB", entity.PaymentStatusPending, "http://pay/1").
+			WillReturnError(errors.New("duplicate key"))
+
+		_, err := repo.Add(context.Background(), payment)
+		assert.ErrorIs(t, err, entity.ErrPSQL)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPaymentDB_Get(t *testing.T) {
+	mockPool, repo := setupPaymentTest(t)
+	paymentID, purchaseID, userID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	row := func() *pgxmock.Rows {
+		return mockPool.NewRows(paymentColumns).AddRow(paymentID, purchaseID, userID, "fake", "fake-1", int64(2500),
+			"RUB", entity.PaymentStatusSucceeded, "", now, now)
+	}
+
+	t.Run("ById", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM payment pm .* WHERE pm.id = \\$1").
+			WithArgs(paymentID).
+			WillReturnRows(row())
+
+		payment, err := repo.GetById(context.Background(), paymentID)
+		require.NoError(t, err)
+		assert.Equal(t, userID, payment.UserID)
+		assert.Equal(t, uint(2500), payment.Amount)
+		assert.Equal(t, entity.PaymentStatusSucceeded, payment.Status)
+	})
+
+	t.Run("ByPurchaseId", func(t *testing.T) {
+		mockPool.ExpectQuery("WHERE pm.purchase_id = \\$1 ORDER BY pm.created_at DESC LIMIT 1").
+			WithArgs(purchaseID).
+			WillReturnRows(row())
+
+		payment, err := repo.GetByPurchaseId(context.Background(), purchaseID)
+		require.NoError(t, err)
+		assert.Equal(t, paymentID, payment.ID)
+	})
+
+	t.Run("ByExternalId", func(t *testing.T) {
+		mockPool.ExpectQuery("WHERE pm.provider = \\$1 AND pm.external_id = \\$2").
+			WithArgs("fake", "fake-1").
+			WillReturnRows(row())
+
+		payment, err := repo.GetByExternalId(context.Background(), "fake", "fake-1")
+		require.NoError(t, err)
+		assert.Equal(t, "fake-1", payment.ExternalID)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockPool.ExpectQuery("FROM payment pm").
+			WithArgs(paymentID).
+			WillReturnRows(mockPool.NewRows(paymentColumns))
+
+		_, err := repo.GetById(context.Background(), paymentID)
+		assert.ErrorIs(t, err, repository.ErrPaymentNotFound)
+	})
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPaymentDB_UpdateStatus(t *testing.T) {
+	mockPool, repo := setupPaymentTest(t)
+	paymentID := uuid.New()
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	require.NoError(t, err)
+
+	mockPool.ExpectExec("UPDATE payment").
+		WithArgs(paymentID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	changed, err := repo.UpdateStatus(context.Background(), tx, paymentID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	mockPool.ExpectExec("UPDATE payment").
+		WithArgs(paymentID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	changed, err = repo.UpdateStatus(context.Background(), tx, paymentID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
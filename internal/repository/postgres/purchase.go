@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
@@ -15,28 +16,42 @@ import (
 
 type PurchaseDB struct {
 	db      DBExecutor
-	ctx     context.Context
 	timeout time.Duration
 }
 
 const (
 	addPurchaseQuery = `
-		INSERT INTO purchase (cart_id, adress, status, payment_method, delivery_method) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id, cart_id, adress, status, payment_method, delivery_method`
+		INSERT INTO purchase (cart_id, address, status, payment_method, delivery_method, safe_deal) 
+		VALUES ($1, $2, $3, $4, $5, $6) 
+		RETURNING id, cart_id, address, status, payment_method, delivery_method, safe_deal`
 
 	getPurchasesByUserIDQuery = `
 		SELECT 
 			p.id, 
 			p.cart_id, 
-			p.adress, 
+			p.address, 
 			p.status, 
 			p.payment_method, 
-			p.delivery_method
+			p.delivery_method,
+			p.safe_deal
 		FROM purchase p
 		INNER JOIN cart c ON p.cart_id = c.id
 		WHERE c.user_id = $1 
 		ORDER BY p.created_at DESC`
+
+	getPurchaseByIDQuery = `
+		SELECT id, cart_id, address, status, payment_method, delivery_method, safe_deal
+		FROM purchase
+		WHERE id = $1`
+
+	addPurchaseAddressQuery = `
+		INSERT INTO purchase_address (purchase_id, address_id, recipient_name, phone, city, street, building, apartment, postcode)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	updatePurchaseStatusQuery = `
+		UPDATE purchase
+		SET status = $2
+		WHERE id = $1`
 )
 
 func NewPurchaseRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Duration) (repository.PurchaseRepository, error) {
@@ -45,16 +60,15 @@ func NewPurchaseRepository(db *pgxpool.Pool, ctx context.Context, timeout time.D
 	}
 	return &PurchaseDB{
 		db:      db,
-		ctx:     ctx,
 		timeout: timeout,
 	}, nil
 }
 
-func (c *PurchaseDB) BeginTransaction() (pgx.Tx, error) {
-	logger := middleware.GetLogger(c.ctx)
+func (c *PurchaseDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("beginning transaction")
 
-	tx, err := c.db.Begin(c.ctx)
+	tx, err := c.db.Begin(ctx)
 	if err != nil {
 		logger.Error("failed to begin transaction", zap.Error(err))
 		return nil, err
@@ -62,13 +76,13 @@ func (c *PurchaseDB) BeginTransaction() (pgx.Tx, error) {
 	return tx, nil
 }
 
-func (r *PurchaseDB) Add(tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase, error) {
+func (r *PurchaseDB) Add(ctx context.Context, tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase, error) {
 	var entityPurchase entity.Purchase
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("adding purchase to db", zap.String("cart_id", purchase.CartID.String()))
 
-	err := tx.QueryRow(r.ctx, addPurchaseQuery, purchase.CartID, purchase.Address, purchase.Status, purchase.PaymentMethod, purchase.DeliveryMethod).
-		Scan(&entityPurchase.ID, &entityPurchase.CartID, &entityPurchase.Address, &entityPurchase.Status, &entityPurchase.PaymentMethod, &entityPurchase.DeliveryMethod)
+	err := tx.QueryRow(ctx, addPurchaseQuery, purchase.CartID, purchase.Address, purchase.Status, purchase.PaymentMethod, purchase.DeliveryMethod, purchase.SafeDeal).
+		Scan(&entityPurchase.ID, &entityPurchase.CartID, &entityPurchase.Address, &entityPurchase.Status, &entityPurchase.PaymentMethod, &entityPurchase.DeliveryMethod, &entityPurchase.SafeDeal)
 	if err != nil {
 		logger.Error("failed to create purchase", zap.Error(err))
 		return nil, entity.PSQLWrap(err, err)
@@ -77,10 +91,10 @@ func (r *PurchaseDB) Add(tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase
 	return &entityPurchase, nil
 }
 
-func (r *PurchaseDB) GetByUserId(userID uuid.UUID) ([]*entity.Purchase, error) {
-	ctx, cancel := context.WithTimeout(r.ctx, r.timeout)
+func (r *PurchaseDB) GetByUserId(ctx context.Context, userID uuid.UUID) ([]*entity.Purchase, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(r.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting purchases by user id from db", zap.String("user_id", userID.String()))
 
 	rows, err := r.db.Query(ctx, getPurchasesByUserIDQuery, userID)
@@ -101,6 +115,7 @@ func (r *PurchaseDB) GetByUserId(userID uuid.UUID) ([]*entity.Purchase, error) {
 			&purchase.Status,
 			&purchase.PaymentMethod,
 			&purchase.DeliveryMethod,
+			&purchase.SafeDeal,
 		)
 		if err != nil {
 			logger.Error("failed to scan purchase row", zap.Error(err))
@@ -117,3 +132,59 @@ func (r *PurchaseDB) GetByUserId(userID uuid.UUID) ([]*entity.Purchase, error) {
 
 	return purchases, nil
 }
+
+func (r *PurchaseDB) GetById(ctx context.Context, purchaseID uuid.UUID) (*entity.Purchase, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting purchase by id from db", zap.String("purchase_id", purchaseID.String()))
+
+	var purchase entity.Purchase
+	err := r.db.QueryRow(ctx, getPurchaseByIDQuery, purchaseID).Scan(
+		&purchase.ID,
+		&purchase.CartID,
+		&purchase.Address,
+		&purchase.Status,
+		&purchase.PaymentMethod,
+		&purchase.DeliveryMethod,
+		&purchase.SafeDeal,
+	)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, repository.ErrPurchaseNotFound
+	case err != nil:
+		logger.Error("failed to get purchase", zap.Error(err))
+		return nil, entity.PSQLWrap(err, err)
+	}
+
+	return &purchase, nil
+}
+
+func (r *PurchaseDB) AddAddress(ctx context.Context, tx pgx.Tx, snapshot *entity.PurchaseAddress) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("adding purchase address to db", zap.String("purchase_id", snapshot.PurchaseID.String()))
+
+	if _, err := tx.Exec(ctx, addPurchaseAddressQuery, snapshot.PurchaseID, snapshot.AddressID,
+		snapshot.RecipientName, snapshot.Phone, snapshot.City, snapshot.Street, snapshot.Building,
+		snapshot.Apartment, snapshot.Postcode); err != nil {
+		logger.Error("failed to add purchase address", zap.Error(err))
+		return entity.PSQLWrap(err, err)
+	}
+	return nil
+}
+
+func (r *PurchaseDB) UpdateStatus(ctx context.Context, tx pgx.Tx, purchaseID uuid.UUID, status entity.PurchaseStatus) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("updating purchase status in db", zap.String("purchase_id", purchaseID.String()), zap.String("status", string(status)))
+
+	tag, err := tx.Exec(ctx, updatePurchaseStatusQuery, purchaseID, status)
+	if err != nil {
+		logger.Error("failed to update purchase status", zap.Error(err))
+		return entity.PSQLWrap(err, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrPurchaseNotFound
+	}
+
+	return nil
+}
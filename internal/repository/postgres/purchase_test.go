@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
 	"github.com/google/uuid"
 	"github.com/pashagolub/pgxmock/v4"
@@ -22,16 +23,12 @@ func setupPurchaseMockDB(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdap
 
 func setupPurchaseTest(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter, *PurchaseDB, func()) {
 	mockPool, adapter := setupPurchaseMockDB(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
 	repo := &PurchaseDB{
 		db:      adapter,
-		ctx:     ctx,
 		timeout: 10 * time.Second,
 	}
 
 	return mockPool, adapter, repo, func() {
-		cancel()
 		mockPool.Close()
 	}
 }
@@ -51,20 +48,22 @@ func TestPurchaseDB_AddPurchase(t *testing.T) {
 		Status:         "pending",
 		PaymentMethod:  "credit_card",
 		DeliveryMethod: "standard",
+		SafeDeal:       true,
 	}
 
-	mockPool.ExpectQuery(`INSERT INTO purchase \(cart_id, adress, status, payment_method, delivery_method\) VALUES \(\$1, \$2, \$3, \$4, \$5\) RETURNING id, cart_id, adress, status, payment_method, delivery_method`).
+	mockPool.ExpectQuery(`INSERT INTO purchase \(cart_id, address, status, payment_method, delivery_method, safe_deal\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) RETURNING id, cart_id, address, status, payment_method, delivery_method, safe_deal`).
 		WithArgs(
 			purchase.CartID,
 			purchase.Address,
 			purchase.Status,
 			purchase.PaymentMethod,
 			purchase.DeliveryMethod,
+			purchase.SafeDeal,
 		).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "cart_id", "adress", "status", "payment_method", "delivery_method"}).
-			AddRow(uuid.New(), purchase.CartID, purchase.Address, purchase.Status, purchase.PaymentMethod, purchase.DeliveryMethod))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "cart_id", "address", "status", "payment_method", "delivery_method", "safe_deal"}).
+			AddRow(uuid.New(), purchase.CartID, purchase.Address, purchase.Status, purchase.PaymentMethod, purchase.DeliveryMethod, purchase.SafeDeal))
 
-	result, err := repo.Add(tx, purchase)
+	result, err := repo.Add(context.Background(), tx, purchase)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -74,18 +73,20 @@ func TestPurchaseDB_AddPurchase(t *testing.T) {
 	assert.Equal(t, purchase.Status, result.Status)
 	assert.Equal(t, purchase.PaymentMethod, result.PaymentMethod)
 	assert.Equal(t, purchase.DeliveryMethod, result.DeliveryMethod)
+	assert.True(t, result.SafeDeal)
 
-	mockPool.ExpectQuery(`INSERT INTO purchase \(cart_id, adress, status, payment_method, delivery_method\)`).
+	mockPool.ExpectQuery(`INSERT INTO purchase \(cart_id, address, status, payment_method, delivery_method, safe_deal\)`).
 		WithArgs(
 			purchase.CartID,
 			purchase.Address,
 			purchase.Status,
 			purchase.PaymentMethod,
 			purchase.DeliveryMethod,
+			purchase.SafeDeal,
 		).
 		WillReturnError(errors.New("insert error"))
 
-	_, err = repo.Add(tx, purchase)
+	_, err = repo.Add(context.Background(), tx, purchase)
 	assert.Error(t, err)
 
 	mockPool.ExpectRollback()
@@ -100,10 +101,112 @@ func TestPurchaseDB_BeginTransaction(t *testing.T) {
 	defer teardown()
 
 	mockPool.ExpectBegin()
-	tx, err := repo.BeginTransaction()
+	tx, err := repo.BeginTransaction(context.Background())
 	assert.NoError(t, err)
 	assert.NotNil(t, tx)
 
 	err = mockPool.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
+
+func TestPurchaseDB_GetById(t *testing.T) {
+	mockPool, _, repo, teardown := setupPurchaseTest(t)
+	defer teardown()
+
+	purchaseID, cartID := uuid.New(), uuid.New()
+	mockPool.ExpectQuery(`SELECT id, cart_id, address, status, payment_method, delivery_method, safe_deal FROM purchase WHERE id = \$1`).
+		WithArgs(purchaseID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "cart_id", "address", "status", "payment_method", "delivery_method", "safe_deal"}).
+			AddRow(purchaseID, cartID, "Test Address", entity.StatusPending, entity.PaymentMethodCard, entity.DeliveryMethodPickup, false))
+
+	purchase, err := repo.GetById(context.Background(), purchaseID)
+	assert.NoError(t, err)
+	assert.Equal(t, cartID, purchase.CartID)
+	assert.Equal(t, entity.PaymentMethodCard, purchase.PaymentMethod)
+
+	mockPool.ExpectQuery(`FROM purchase WHERE id = \$1`).
+		WithArgs(purchaseID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "cart_id", "address", "status", "payment_method", "delivery_method", "safe_deal"}))
+
+	_, err = repo.GetById(context.Background(), purchaseID)
+	assert.ErrorIs(t, err, repository.ErrPurchaseNotFound)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPurchaseDB_GetByUserId(t *testing.T) {
+	mockPool, _, repo, teardown := setupPurchaseTest(t)
+	defer teardown()
+
+	userID, purchaseID, cartID := uuid.New(), uuid.New(), uuid.New()
+	mockPool.ExpectQuery(`FROM purchase p INNER JOIN cart c ON p.cart_id = c.id WHERE c.user_id = \$1`).
+		WithArgs(userID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "cart_id", "address", "status", "payment_method", "delivery_method", "safe_deal"}).
+			AddRow(purchaseID, cartID, "Test Address", entity.StatusPending, entity.PaymentMethodCard, entity.DeliveryMethodPickup, true))
+
+	purchases, err := repo.GetByUserId(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Len(t, purchases, 1)
+	assert.Equal(t, purchaseID, purchases[0].ID)
+	assert.True(t, purchases[0].SafeDeal)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPurchaseDB_AddAddress(t *testing.T) {
+	mockPool, _, repo, teardown := setupPurchaseTest(t)
+	defer teardown()
+
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	assert.NoError(t, err)
+
+	addressID := uuid.New()
+	snapshot := &entity.PurchaseAddress{
+		PurchaseID: uuid.New(),
+		AddressID:  &addressID,
+		AddressFields: entity.AddressFields{
+			RecipientName: "Иван Иванов",
+			Phone:         "+79991234567",
+			City:          "Москва",
+			Street:        "Тверская ул.",
+			Building:      "1",
+		},
+	}
+	mockPool.ExpectExec("INSERT INTO purchase_address").
+		WithArgs(snapshot.PurchaseID, snapshot.AddressID, snapshot.RecipientName, snapshot.Phone, snapshot.City,
+			snapshot.Street, snapshot.Building, snapshot.Apartment, snapshot.Postcode).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	assert.NoError(t, repo.AddAddress(context.Background(), tx, snapshot))
+
+	mockPool.ExpectExec("INSERT INTO purchase_address").
+		WithArgs(snapshot.PurchaseID, snapshot.AddressID, snapshot.RecipientName, snapshot.Phone, snapshot.City,
+			snapshot.Street, snapshot.Building, snapshot.Apartment, snapshot.Postcode).
+		WillReturnError(errors.New("insert error"))
+	assert.ErrorIs(t, repo.AddAddress(context.Background(), tx, snapshot), entity.ErrPSQL)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestPurchaseDB_UpdateStatus(t *testing.T) {
+	mockPool, _, repo, teardown := setupPurchaseTest(t)
+	defer teardown()
+
+	purchaseID := uuid.New()
+	mockPool.ExpectBegin()
+	tx, err := mockPool.Begin(context.Background())
+	assert.NoError(t, err)
+
+	mockPool.ExpectExec(`UPDATE purchase SET status = \$2 WHERE id = \$1`).
+		WithArgs(purchaseID, entity.StatusInProgress).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	assert.NoError(t, repo.UpdateStatus(context.Background(), tx, purchaseID, entity.StatusInProgress))
+
+	mockPool.ExpectExec(`UPDATE purchase`).
+		WithArgs(purchaseID, entity.StatusCanceled).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	err = repo.UpdateStatus(context.Background(), tx, purchaseID, entity.StatusCanceled)
+	assert.ErrorIs(t, err, repository.ErrPurchaseNotFound)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
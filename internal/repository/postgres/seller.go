@@ -36,8 +36,7 @@ const (
 )
 
 type SellerDB struct {
-	DB  DBExecutor
-	ctx context.Context
+	DB DBExecutor
 }
 
 type DBSeller struct {
@@ -53,8 +52,7 @@ func NewSellerRepository(db *pgxpool.Pool, ctx context.Context) (repository.Sell
 		return nil, err
 	}
 	return &SellerDB{
-		DB:  db,
-		ctx: ctx,
+		DB: db,
 	}, nil
 }
 
@@ -66,12 +64,12 @@ func (dbSeller *DBSeller) GetEntity() entity.Seller {
 	}
 }
 
-func (s *SellerDB) Add(tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error) {
+func (s *SellerDB) Add(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error) {
 	var dbSeller DBSeller
-	logger := middleware.GetLogger(s.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("adding seller to db", zap.String("user_id", userID.String()))
 
-	err := tx.QueryRow(s.ctx, queryAddSeller, userID).Scan(
+	err := tx.QueryRow(ctx, queryAddSeller, userID).Scan(
 		&dbSeller.ID,
 		&dbSeller.UserID,
 		&dbSeller.Description,
@@ -91,12 +89,12 @@ func (s *SellerDB) Add(tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error) {
 	return dbSeller.ID, nil
 }
 
-func (s *SellerDB) GetById(sellerID uuid.UUID) (*entity.Seller, error) {
+func (s *SellerDB) GetById(ctx context.Context, sellerID uuid.UUID) (*entity.Seller, error) {
 	var dbSeller DBSeller
-	logger := middleware.GetLogger(s.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting seller by id from db", zap.String("seller_id", sellerID.String()))
 
-	err := s.DB.QueryRow(s.ctx, queryGetSellerByID, sellerID).Scan(
+	err := s.DB.QueryRow(ctx, queryGetSellerByID, sellerID).Scan(
 		&dbSeller.ID,
 		&dbSeller.UserID,
 		&dbSeller.Description,
@@ -118,12 +116,12 @@ func (s *SellerDB) GetById(sellerID uuid.UUID) (*entity.Seller, error) {
 	return &seller, nil
 }
 
-func (s *SellerDB) GetByUserId(userID uuid.UUID) (*entity.Seller, error) {
+func (s *SellerDB) GetByUserId(ctx context.Context, userID uuid.UUID) (*entity.Seller, error) {
 	var dbSeller DBSeller
-	logger := middleware.GetLogger(s.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting seller by user id from db", zap.String("user_id", userID.String()))
 
-	err := s.DB.QueryRow(s.ctx, queryGetSellerByUserID, userID).Scan(
+	err := s.DB.QueryRow(ctx, queryGetSellerByUserID, userID).Scan(
 		&dbSeller.ID,
 		&dbSeller.UserID,
 		&dbSeller.Description,
@@ -24,15 +24,11 @@ func setupSellerMockDB(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapte
 
 func setupSellerTest(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter, *SellerDB, func()) {
 	mockPool, adapter := setupSellerMockDB(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
 	repo := &SellerDB{
-		DB:  adapter,
-		ctx: ctx,
+		DB: adapter,
 	}
 
 	return mockPool, adapter, repo, func() {
-		cancel()
 		mockPool.Close()
 	}
 }
@@ -54,7 +50,7 @@ func TestSellerDB_AddSeller(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "user_id", "description", "created_at", "updated_at"}).
 			AddRow(sellerID, userID, sql.NullString{Valid: false}, time.Now(), time.Now()))
 
-	id, err := repo.Add(tx, userID)
+	id, err := repo.Add(context.Background(), tx, userID)
 	assert.NoError(t, err)
 	assert.Equal(t, sellerID, id)
 
@@ -71,7 +67,7 @@ func TestSellerDB_AddSeller(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(pgx.ErrNoRows)
 
-	_, err = repo.Add(tx2, userID)
+	_, err = repo.Add(context.Background(), tx2, userID)
 	assert.Error(t, err)
 	assert.Equal(t, repository.ErrSellerAlreadyExists, err)
 
@@ -87,7 +83,7 @@ func TestSellerDB_AddSeller(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(errors.New("insertion error"))
 
-	_, err = repo.Add(tx3, userID)
+	_, err = repo.Add(context.Background(), tx3, userID)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error adding seller")
 
@@ -118,17 +114,17 @@ func TestSellerDB_GetSellerByID(t *testing.T) {
 		WithArgs(sellerID).
 		WillReturnError(errors.New("query error"))
 
-	seller, err := repo.GetById(sellerID)
+	seller, err := repo.GetById(context.Background(), sellerID)
 	assert.NoError(t, err)
 	assert.NotNil(t, seller)
 	assert.Equal(t, "A great seller", seller.Description)
 
-	seller, err = repo.GetById(sellerID)
+	seller, err = repo.GetById(context.Background(), sellerID)
 	assert.Error(t, err)
 	assert.Nil(t, seller)
 	assert.Equal(t, repository.ErrSellerNotFound, err)
 
-	seller, err = repo.GetById(sellerID)
+	seller, err = repo.GetById(context.Background(), sellerID)
 	assert.Error(t, err)
 	assert.Nil(t, seller)
 	assert.Contains(t, err.Error(), "error getting seller by ID")
@@ -156,17 +152,17 @@ func TestSellerDB_GetSellerByUserID(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(errors.New("query error"))
 
-	seller, err := repo.GetByUserId(userID)
+	seller, err := repo.GetByUserId(context.Background(), userID)
 	assert.NoError(t, err)
 	assert.NotNil(t, seller)
 	assert.Equal(t, "Another great seller", seller.Description)
 
-	seller, err = repo.GetByUserId(userID)
+	seller, err = repo.GetByUserId(context.Background(), userID)
 	assert.Error(t, err)
 	assert.Nil(t, seller)
 	assert.Equal(t, repository.ErrSellerNotFound, err)
 
-	seller, err = repo.GetByUserId(userID)
+	seller, err = repo.GetByUserId(context.Background(), userID)
 	assert.Error(t, err)
 	assert.Nil(t, seller)
 	assert.Contains(t, err.Error(), "error getting seller by user_id")
@@ -4,7 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"io"
+	"mime"
 	"path/filepath"
 	"strings"
 	"time"
@@ -19,24 +20,88 @@ import (
 )
 
 const (
+	// Помеченная статика не отдается, пока ее не проверит модератор
 	getStaticQuery = `
         SELECT path, name
         FROM static
-        WHERE id = $1
+        WHERE id = $1 AND moderation_status <> 'flagged'
+    `
+
+	acquireStaticBlobQuery = `
+        INSERT INTO static_blob (key, ref_count)
+        VALUES ($1, 1)
+        ON CONFLICT (key) DO UPDATE SET ref_count = static_blob.ref_count + 1
+        RETURNING ref_count
     `
 
 	uploadStaticQuery = `
-        INSERT INTO static (path, name)
-        VALUES ($1, $2)
+        INSERT INTO static (path, name, blob_key)
+        VALUES ($1, $2, $3)
         RETURNING id
     `
+
+	flagStaticQuery = `
+        UPDATE static
+        SET moderation_status = 'flagged', moderation_reason = $2
+        WHERE id = $1
+    `
+
+	isStaticFlaggedQuery = `
+        SELECT COALESCE(bool_and(moderation_status = 'flagged'), FALSE)
+        FROM static
+        WHERE split_part(name, '.', 1) = $1
+    `
+
+	// Изображения по умолчанию подставляются триггерами и никогда не удаляются
+	deleteOrphanStaticQuery = `
+        WITH orphans AS (
+            SELECT s.id
+            FROM static s
+            WHERE s.created_at < $1
+                AND s.name NOT IN ('default.jpg', 'default_advert.jpg')
+                AND NOT EXISTS (SELECT 1 FROM "user" u WHERE u.image_id = s.id)
+                AND NOT EXISTS (SELECT 1 FROM advert a WHERE a.image_id = s.id)
+                AND NOT EXISTS (SELECT 1 FROM advert_video v WHERE v.static_id = s.id)
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        ), deleted AS (
+            DELETE FROM static s
+            USING orphans o
+            WHERE s.id = o.id
+            RETURNING s.blob_key
+        )
+        UPDATE static_blob b
+        SET ref_count = b.ref_count - d.refs
+        FROM (
+            SELECT blob_key, COUNT(*) AS refs
+            FROM deleted
+            WHERE blob_key IS NOT NULL
+            GROUP BY blob_key
+        ) d
+        WHERE b.key = d.blob_key
+    `
+
+	selectUnreferencedBlobsQuery = `
+        SELECT key
+        FROM static_blob
+        WHERE ref_count = 0
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    `
+
+	deleteStaticBlobsQuery = `
+        DELETE FROM static_blob
+        WHERE key = ANY($1)
+    `
 )
 
+// StaticDB хранит сведения о статике в Postgres, а сами файлы - в Store.
+// BasicPath - префикс, с которым пути файлов отдаются клиентам
 type StaticDB struct {
 	DB        DBExecutor
+	Store     repository.BlobStore
 	BasicPath string
 	MaxSize   int
-	Ctx       context.Context
 	timeout   time.Duration
 }
 
@@ -44,25 +109,25 @@ func (s StaticDB) GetMaxSize() int {
 	return s.MaxSize
 }
 
-func NewStaticRepository(ctx context.Context, dbpool *pgxpool.Pool, basicPath string, maxSize int, logger *zap.Logger, timeout time.Duration) (repository.StaticRepository, error) {
+func NewStaticRepository(ctx context.Context, dbpool *pgxpool.Pool, store repository.BlobStore, basicPath string, maxSize int, logger *zap.Logger, timeout time.Duration) (repository.StaticRepository, error) {
 	if err := dbpool.Ping(ctx); err != nil {
 		return nil, err
 	}
 	return &StaticDB{
 		DB:        dbpool,
+		Store:     store,
 		BasicPath: basicPath,
 		MaxSize:   maxSize,
-		Ctx:       ctx,
 		timeout:   timeout,
 	}, nil
 }
 
-func (s StaticDB) Get(staticID uuid.UUID) (string, error) {
+func (s StaticDB) Get(ctx context.Context, staticID uuid.UUID) (string, error) {
 	var path, name string
 
-	ctx, cancel := context.WithTimeout(s.Ctx, s.timeout)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(s.Ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting static by id from db", zap.String("static_id", staticID.String()))
 
 	err := s.DB.QueryRow(ctx, getStaticQuery, staticID).Scan(&path, &name)
@@ -78,62 +143,189 @@ func (s StaticDB) Get(staticID uuid.UUID) (string, error) {
 	return fmt.Sprintf("%s/%s", path, name), nil
 }
 
-func (s StaticDB) Upload(path, filename string, data []byte) (uuid.UUID, error) {
-	ctx, cancel := context.WithTimeout(s.Ctx, s.timeout)
+func (s StaticDB) Upload(ctx context.Context, path, filename string, data io.Reader, size int64) (uuid.UUID, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(s.Ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("uploading static to db", zap.String("path", path), zap.String("filename", filename))
 
-	if len(data) > s.MaxSize {
-		logger.Error("postgres: static too large", zap.Int("size", len(data)), zap.Int("max_size", s.MaxSize))
+	if size > int64(s.MaxSize) {
+		logger.Error("postgres: static too large", zap.Int64("size", size), zap.Int("max_size", s.MaxSize))
 		return uuid.UUID{}, entity.PSQLWrap(repository.ErrStaticTooLarge)
 	}
 
 	if !strings.HasSuffix(path, "/") {
 		path += "/"
 	}
+	key := path + filename
 
-	dir := filepath.Dir(fmt.Sprintf("%s/%s/", s.BasicPath, path))
-	err := os.MkdirAll(dir, 0755)
+	tx, err := s.DB.Begin(ctx)
 	if err != nil {
-		logger.Error("error creating static directory", zap.String("path", dir), zap.Error(err))
-		return uuid.UUID{}, entity.PSQLWrap(
-			err,
-			errors.New("failed to create a directory for storing static files"),
-		)
+		logger.Error("error starting transaction", zap.Error(err))
+		return uuid.UUID{}, entity.PSQLWrap(err)
 	}
+	defer tx.Rollback(ctx)
 
-	dst, err := os.Create(fmt.Sprintf("%s/%s/%s", s.BasicPath, path, filename))
-	if err != nil {
-		logger.Error("error creating static file", zap.String("path", fmt.Sprintf("%s/%s/%s", s.BasicPath, path, filename)), zap.Error(err))
-		return uuid.UUID{}, entity.PSQLWrap(
-			err,
-			errors.New("failed to create file"),
-		)
+	// Строка static_blob остается заблокированной до конца транзакции, поэтому
+	// сборщик мусора не удалит файл, пока новая ссылка на него не сохранена
+	var refCount int
+	if err := tx.QueryRow(ctx, acquireStaticBlobQuery, key).Scan(&refCount); err != nil {
+		logger.Error("error acquiring static blob", zap.String("key", key), zap.Error(err))
+		return uuid.UUID{}, entity.PSQLWrap(err, errors.New("error executing SQL query AcquireStaticBlob"))
 	}
 
-	if _, err = dst.Write(data); err != nil {
-		logger.Error("error writing data to static file", zap.String("path", fmt.Sprintf("%s/%s/%s", s.BasicPath, path, filename)), zap.Error(err))
-		return uuid.UUID{}, entity.PSQLWrap(
-			err,
-			errors.New("failed to write data to file"),
-		)
+	created, err := s.storeBlob(ctx, key, filename, data, size, refCount == 1)
+	if err != nil {
+		return uuid.UUID{}, err
 	}
-	if err = dst.Close(); err != nil {
-		logger.Error("error closing static file", zap.String("path", fmt.Sprintf("%s/%s/%s", s.BasicPath, path, filename)), zap.Error(err))
-		return uuid.UUID{}, entity.PSQLWrap(
-			err,
-			errors.New("failed to close file"),
-		)
+	// Файл удаляется при ошибке, только если на него еще никто не ссылался
+	cleanup := func() {
+		if !created {
+			return
+		}
+		if deleteErr := s.Store.Delete(ctx, key); deleteErr != nil {
+			logger.Error("error deleting orphaned static file", zap.String("key", key), zap.Error(deleteErr))
+		}
 	}
 
-	logger.Info("static uploaded", zap.String("path", fmt.Sprintf("%s/%s/%s", s.BasicPath, path, filename)))
 	var id uuid.UUID
-	if err = s.DB.QueryRow(ctx, uploadStaticQuery, s.BasicPath+path, filename).Scan(&id); err != nil {
-		logger.Error("error uploading static", zap.String("path", fmt.Sprintf("%s/%s/%s", s.BasicPath, path, filename)), zap.Error(err))
+	if err := tx.QueryRow(ctx, uploadStaticQuery, s.BasicPath+path, filename, key).Scan(&id); err != nil {
+		logger.Error("error uploading static", zap.String("key", key), zap.Error(err))
+		cleanup()
 		return uuid.UUID{}, entity.PSQLWrap(err, errors.New("error executing SQL query UploadStatic"))
 	}
 
-	logger.Info("Static file uploaded to DB", zap.String("id", id.String()))
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("error committing static upload", zap.String("key", key), zap.Error(err))
+		cleanup()
+		return uuid.UUID{}, entity.PSQLWrap(err)
+	}
+
+	logger.Info("Static file uploaded to DB", zap.String("id", id.String()), zap.Int("ref_count", refCount))
 	return id, nil
 }
+
+// storeBlob сохраняет файл, если он новый или пропал из хранилища.
+// Возвращает true, если файл создан и до этого на него никто не ссылался
+func (s StaticDB) storeBlob(ctx context.Context, key, filename string, data io.Reader, size int64, isNew bool) (bool, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if !isNew {
+		_, err := s.Store.Stat(ctx, key)
+		if err == nil {
+			logger.Info("static already stored, reusing", zap.String("key", key))
+			return false, nil
+		}
+		if !errors.Is(err, repository.ErrBlobNotFound) {
+			logger.Error("error checking static file", zap.String("key", key), zap.Error(err))
+			return false, err
+		}
+		logger.Warn("referenced static file is missing, storing again", zap.String("key", key))
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if err := s.Store.Put(ctx, key, data, size, contentType); err != nil {
+		logger.Error("error storing static file", zap.String("key", key), zap.Error(err))
+		return false, err
+	}
+	logger.Info("static stored", zap.String("key", key))
+	return isNew, nil
+}
+
+func (s StaticDB) Flag(ctx context.Context, staticID uuid.UUID, reason string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("flagging static for moderation", zap.String("static_id", staticID.String()), zap.String("reason", reason))
+
+	tag, err := s.DB.Exec(ctx, flagStaticQuery, staticID, reason)
+	if err != nil {
+		logger.Error("postgres: error flagging static", zap.String("static_id", staticID.String()), zap.Error(err))
+		return entity.PSQLWrap(err, errors.New("error executing SQL query FlagStatic"))
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.PSQLWrap(repository.ErrStaticNotFound)
+	}
+	return nil
+}
+
+func (s StaticDB) IsFlagged(ctx context.Context, source string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+
+	var flagged bool
+	if err := s.DB.QueryRow(ctx, isStaticFlaggedQuery, source).Scan(&flagged); err != nil {
+		logger.Error("postgres: error checking static moderation", zap.String("source", source), zap.Error(err))
+		return false, entity.PSQLWrap(err, errors.New("error executing SQL query IsStaticFlagged"))
+	}
+	return flagged, nil
+}
+
+func (s StaticDB) CollectGarbage(ctx context.Context, olderThan time.Time, limit int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("collecting unreferenced static", zap.Time("older_than", olderThan), zap.Int("limit", limit))
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("error starting transaction", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, deleteOrphanStaticQuery, olderThan, limit)
+	if err != nil {
+		logger.Error("error deleting orphaned static", zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query DeleteOrphanStatic"))
+	}
+	logger.Info("orphaned static deleted", zap.Int64("blobs_released", tag.RowsAffected()))
+
+	rows, err := tx.Query(ctx, selectUnreferencedBlobsQuery, limit)
+	if err != nil {
+		logger.Error("error selecting unreferenced blobs", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+	var candidates []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			logger.Error("error scanning unreferenced blob", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		candidates = append(candidates, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over unreferenced blobs", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	// Строки static_blob заблокированы, поэтому загрузка того же файла дождется конца транзакции
+	// и сохранит его заново. Файлы, которые не удалось удалить, останутся до следующего запуска
+	deleted := make([]string, 0, len(candidates))
+	for _, key := range candidates {
+		if err := s.Store.Delete(ctx, key); err != nil {
+			logger.Error("error deleting unreferenced static file", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+
+	if len(deleted) > 0 {
+		if _, err := tx.Exec(ctx, deleteStaticBlobsQuery, deleted); err != nil {
+			logger.Error("error deleting static blobs", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("error committing static garbage collection", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	logger.Info("static garbage collected", zap.Int("blobs_deleted", len(deleted)))
+	return deleted, nil
+}
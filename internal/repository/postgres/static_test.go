@@ -3,18 +3,23 @@ package postgres
 import (
 	"context"
 	_ "context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/blobstore"
 	postgres2 "github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
 )
 
 func setupMockDB(t *testing.T) (pgxmock.PgxPoolIface, *postgres2.PgxMockAdapter) {
@@ -32,19 +37,21 @@ func setupTest(t *testing.T) (pgxmock.PgxPoolIface, *postgres2.PgxMockAdapter, s
 	}
 
 	mockPool, adapter := setupMockDB(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	store, err := blobstore.NewLocalStore(tempDir, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create blob store: %v", err)
+	}
 
 	repo := &StaticDB{
 		DB:        adapter,
+		Store:     store,
 		BasicPath: tempDir,
 		MaxSize:   10 * 1024 * 1024,
-		Ctx:       ctx,
 		timeout:   10 * time.Second,
 	}
 
-	return mockPool, adapter, tempDir, ctx, repo, func() {
+	return mockPool, adapter, tempDir, context.Background(), repo, func() {
 		os.RemoveAll(tempDir)
-		cancel()
 		mockPool.Close()
 	}
 }
@@ -89,7 +96,7 @@ func TestStaticDB_GetStatic(t *testing.T) {
 					WillReturnError(pgx.ErrNoRows)
 			}
 
-			path, err := repo.Get(tt.staticID)
+			path, err := repo.Get(context.Background(), tt.staticID)
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError, "expected error in GetStatic")
 			} else {
@@ -103,3 +110,123 @@ func TestStaticDB_GetStatic(t *testing.T) {
 		})
 	}
 }
+
+func TestStaticDB_Upload(t *testing.T) {
+	mockPool, _, tempDir, _, repo, teardown := setupTest(t)
+	defer teardown()
+
+	staticID := uuid.New()
+	mockPool.ExpectBegin()
+	mockPool.ExpectQuery("INSERT INTO static_blob \\(key, ref_count\\)").
+		WithArgs("images/file.webp").
+		WillReturnRows(mockPool.NewRows([]string{"ref_count"}).AddRow(1))
+	mockPool.ExpectQuery("INSERT INTO static \\(path, name, blob_key\\)").
+		WithArgs(tempDir+"images/", "file.webp", "images/file.webp").
+		WillReturnRows(mockPool.NewRows([]string{"id"}).AddRow(staticID))
+	mockPool.ExpectCommit()
+	mockPool.ExpectRollback()
+
+	id, err := repo.Upload(context.Background(), "images", "file.webp", strings.NewReader("content"), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, staticID, id)
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "images", "file.webp"))
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStaticDB_Upload_Deduplicated(t *testing.T) {
+	mockPool, _, tempDir, _, repo, teardown := setupTest(t)
+	defer teardown()
+
+	assert.NoError(t, repo.Store.Put(context.Background(), "images/same.webp", strings.NewReader("stored"), 6, "image/webp"))
+
+	mockPool.ExpectBegin()
+	mockPool.ExpectQuery("INSERT INTO static_blob \\(key, ref_count\\)").
+		WithArgs("images/same.webp").
+		WillReturnRows(mockPool.NewRows([]string{"ref_count"}).AddRow(2))
+	mockPool.ExpectQuery("INSERT INTO static \\(path, name, blob_key\\)").
+		WithArgs(tempDir+"images/", "same.webp", "images/same.webp").
+		WillReturnError(errors.New("insert failed"))
+	mockPool.ExpectRollback()
+
+	_, err := repo.Upload(context.Background(), "images", "same.webp", strings.NewReader("content"), 7)
+	assert.Error(t, err)
+
+	// Файл, на который уже есть ссылки, не перезаписывается и не удаляется при ошибке
+	content, err := os.ReadFile(filepath.Join(tempDir, "images", "same.webp"))
+	assert.NoError(t, err)
+	assert.Equal(t, "stored", string(content))
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStaticDB_Flag(t *testing.T) {
+	mockPool, _, _, _, repo, teardown := setupTest(t)
+	defer teardown()
+
+	staticID := uuid.New()
+	mockPool.ExpectExec("UPDATE static").
+		WithArgs(staticID, "nsfw score 0.70").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	assert.NoError(t, repo.Flag(context.Background(), staticID, "nsfw score 0.70"))
+
+	mockPool.ExpectExec("UPDATE static").
+		WithArgs(staticID, "nsfw score 0.70").
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	assert.ErrorIs(t, repo.Flag(context.Background(), staticID, "nsfw score 0.70"), repository.ErrStaticNotFound)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStaticDB_IsFlagged(t *testing.T) {
+	mockPool, _, _, _, repo, teardown := setupTest(t)
+	defer teardown()
+
+	mockPool.ExpectQuery(`SELECT COALESCE\(bool_and\(moderation_status = 'flagged'\), FALSE\)`).
+		WithArgs("abc").
+		WillReturnRows(pgxmock.NewRows([]string{"flagged"}).AddRow(true))
+	flagged, err := repo.IsFlagged(context.Background(), "abc")
+	assert.NoError(t, err)
+	assert.True(t, flagged)
+
+	mockPool.ExpectQuery(`SELECT COALESCE\(bool_and\(moderation_status = 'flagged'\), FALSE\)`).
+		WithArgs("abc").
+		WillReturnError(errors.New("db down"))
+	_, err = repo.IsFlagged(context.Background(), "abc")
+	assert.ErrorIs(t, err, entity.ErrPSQL)
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStaticDB_CollectGarbage(t *testing.T) {
+	mockPool, _, tempDir, _, repo, teardown := setupTest(t)
+	defer teardown()
+
+	assert.NoError(t, repo.Store.Put(context.Background(), "images/orphan.webp", strings.NewReader("orphan"), 6, "image/webp"))
+
+	olderThan := time.Now().Add(-24 * time.Hour)
+	mockPool.ExpectBegin()
+	mockPool.ExpectExec("WITH orphans AS").
+		WithArgs(olderThan, 100).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockPool.ExpectQuery("SELECT key FROM static_blob WHERE ref_count = 0").
+		WithArgs(100).
+		WillReturnRows(mockPool.NewRows([]string{"key"}).AddRow("images/orphan.webp"))
+	mockPool.ExpectExec("DELETE FROM static_blob WHERE key = ANY\\(\\$1\\)").
+		WithArgs([]string{"images/orphan.webp"}).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mockPool.ExpectCommit()
+	mockPool.ExpectRollback()
+
+	deleted, err := repo.CollectGarbage(context.Background(), olderThan, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"images/orphan.webp"}, deleted)
+
+	_, err = os.Stat(filepath.Join(tempDir, "images", "orphan.webp"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
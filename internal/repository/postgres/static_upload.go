@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	createStaticUploadQuery = `
+        INSERT INTO static_upload (id, owner_id, purpose, filename, content_type, size, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING created_at
+    `
+
+	getStaticUploadQuery = `
+        SELECT owner_id, purpose, filename, content_type, size, received, created_at, expires_at
+        FROM static_upload
+        WHERE id = $1 AND expires_at > NOW()
+    `
+
+	lockStaticUploadQuery = `
+        SELECT size, received
+        FROM static_upload
+        WHERE id = $1 AND expires_at > NOW()
+        FOR UPDATE
+    `
+
+	insertStaticUploadPartQuery = `
+        INSERT INTO static_upload_part (upload_id, start, size)
+        VALUES ($1, $2, $3)
+    `
+
+	advanceStaticUploadQuery = `
+        UPDATE static_upload
+        SET received = received + $2, expires_at = $3
+        WHERE id = $1
+        RETURNING received
+    `
+
+	selectStaticUploadPartsQuery = `
+        SELECT start
+        FROM static_upload_part
+        WHERE upload_id = $1
+        ORDER BY start
+    `
+
+	deleteStaticUploadQuery = `
+        DELETE FROM static_upload
+        WHERE id = $1
+    `
+
+	deleteExpiredStaticUploadsQuery = `
+        DELETE FROM static_upload
+        WHERE id IN (
+            SELECT id
+            FROM static_upload
+            WHERE expires_at <= $1
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id
+    `
+)
+
+// StaticUploadDB хранит состояние загрузок по частям в Postgres, а сами части - в Store
+type StaticUploadDB struct {
+	DB      DBExecutor
+	Store   repository.BlobStore
+	timeout time.Duration
+}
+
+func NewStaticUploadRepository(ctx context.Context, dbpool *pgxpool.Pool, store repository.BlobStore, logger *zap.Logger, timeout time.Duration) (repository.StaticUploadRepository, error) {
+	if err := dbpool.Ping(ctx); err != nil {
+		return nil, err
+	}
+	return &StaticUploadDB{
+		DB:      dbpool,
+		Store:   store,
+		timeout: timeout,
+	}, nil
+}
+
+func uploadPrefix(uploadID uuid.UUID) string {
+	return "uploads/" + uploadID.String()
+}
+
+func uploadPartKey(uploadID uuid.UUID, start int64) string {
+	return fmt.Sprintf("%s/%d", uploadPrefix(uploadID), start)
+}
+
+func (s StaticUploadDB) Create(ctx context.Context, upload *entity.ResumableUpload) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("creating static upload", zap.String("upload_id", upload.ID.String()),
+		zap.String("purpose", string(upload.Metadata.Purpose)), zap.Int64("size", upload.Metadata.Size))
+
+	err := s.DB.QueryRow(ctx, createStaticUploadQuery,
+		upload.ID,
+		upload.OwnerID,
+		upload.Metadata.Purpose,
+		upload.Metadata.Filename,
+		upload.Metadata.ContentType,
+		upload.Metadata.Size,
+		upload.ExpiresAt,
+	).Scan(&upload.CreatedAt)
+	if err != nil {
+		logger.Error("error creating static upload", zap.String("upload_id", upload.ID.String()), zap.Error(err))
+		return entity.PSQLWrap(err, errors.New("error executing SQL query CreateStaticUpload"))
+	}
+	return nil
+}
+
+func (s StaticUploadDB) Get(ctx context.Context, uploadID uuid.UUID) (*entity.ResumableUpload, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("getting static upload", zap.String("upload_id", uploadID.String()))
+
+	upload := entity.ResumableUpload{ID: uploadID}
+	err := s.DB.QueryRow(ctx, getStaticUploadQuery, uploadID).Scan(
+		&upload.OwnerID,
+		&upload.Metadata.Purpose,
+		&upload.Metadata.Filename,
+		&upload.Metadata.ContentType,
+		&upload.Metadata.Size,
+		&upload.Offset,
+		&upload.CreatedAt,
+		&upload.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.PSQLWrap(repository.ErrStaticUploadNotFound)
+		}
+		logger.Error("error getting static upload", zap.String("upload_id", uploadID.String()), zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query GetStaticUpload"))
+	}
+	return &upload, nil
+}
+
+func (s StaticUploadDB) AppendChunk(ctx context.Context, uploadID uuid.UUID, offset int64, data []byte, expiresAt time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("appending static upload chunk", zap.String("upload_id", uploadID.String()),
+		zap.Int64("offset", offset), zap.Int("size", len(data)))
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("error starting transaction", zap.Error(err))
+		return 0, entity.PSQLWrap(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Строка загрузки заблокирована до конца транзакции, поэтому одновременные запросы
+	// с одним смещением не запишут две части на одно место
+	var size, received int64
+	if err := tx.QueryRow(ctx, lockStaticUploadQuery, uploadID).Scan(&size, &received); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, entity.PSQLWrap(repository.ErrStaticUploadNotFound)
+		}
+		logger.Error("error locking static upload", zap.String("upload_id", uploadID.String()), zap.Error(err))
+		return 0, entity.PSQLWrap(err, errors.New("error executing SQL query LockStaticUpload"))
+	}
+	if offset != received {
+		return received, entity.PSQLWrap(repository.ErrStaticUploadOffset)
+	}
+	if offset+int64(len(data)) > size {
+		return received, entity.PSQLWrap(repository.ErrStaticUploadOverflow)
+	}
+	if len(data) == 0 {
+		return received, nil
+	}
+
+	key := uploadPartKey(uploadID, offset)
+	if err := s.Store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/octet-stream"); err != nil {
+		logger.Error("error storing static upload chunk", zap.String("key", key), zap.Error(err))
+		return received, err
+	}
+	cleanup := func() {
+		if deleteErr := s.Store.Delete(ctx, key); deleteErr != nil {
+			logger.Error("error deleting static upload chunk", zap.String("key", key), zap.Error(deleteErr))
+		}
+	}
+
+	if _, err := tx.Exec(ctx, insertStaticUploadPartQuery, uploadID, offset, len(data)); err != nil {
+		logger.Error("error inserting static upload part", zap.String("key", key), zap.Error(err))
+		cleanup()
+		return received, entity.PSQLWrap(err, errors.New("error executing SQL query InsertStaticUploadPart"))
+	}
+	if err := tx.QueryRow(ctx, advanceStaticUploadQuery, uploadID, len(data), expiresAt).Scan(&received); err != nil {
+		logger.Error("error advancing static upload", zap.String("upload_id", uploadID.String()), zap.Error(err))
+		cleanup()
+		return offset, entity.PSQLWrap(err, errors.New("error executing SQL query AdvanceStaticUpload"))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("error committing static upload chunk", zap.String("key", key), zap.Error(err))
+		cleanup()
+		return offset, entity.PSQLWrap(err)
+	}
+
+	logger.Info("static upload chunk stored", zap.String("key", key), zap.Int64("received", received))
+	return received, nil
+}
+
+func (s StaticUploadDB) Open(ctx context.Context, uploadID uuid.UUID) (io.ReadCloser, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("opening static upload", zap.String("upload_id", uploadID.String()))
+
+	rows, err := s.DB.Query(queryCtx, selectStaticUploadPartsQuery, uploadID)
+	if err != nil {
+		logger.Error("error selecting static upload parts", zap.String("upload_id", uploadID.String()), zap.Error(err))
+		return nil, entity.PSQLWrap(err, errors.New("error executing SQL query SelectStaticUploadParts"))
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var start int64
+		if err := rows.Scan(&start); err != nil {
+			logger.Error("error scanning static upload part", zap.Error(err))
+			return nil, entity.PSQLWrap(err)
+		}
+		keys = append(keys, uploadPartKey(uploadID, start))
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over static upload parts", zap.Error(err))
+		return nil, entity.PSQLWrap(err)
+	}
+
+	return &uploadPartsReader{ctx: ctx, store: s.Store, keys: keys}, nil
+}
+
+func (s StaticUploadDB) Delete(ctx context.Context, uploadID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("deleting static upload", zap.String("upload_id", uploadID.String()))
+
+	// Сначала удаляются части: если хранилище недоступно, строка останется
+	// и загрузка будет удалена повторно по истечении срока жизни
+	if err := s.Store.DeletePrefix(ctx, uploadPrefix(uploadID)); err != nil {
+		logger.Error("error deleting static upload parts", zap.String("upload_id", uploadID.String()), zap.Error(err))
+		return err
+	}
+	if _, err := s.DB.Exec(ctx, deleteStaticUploadQuery, uploadID); err != nil {
+		logger.Error("error deleting static upload", zap.String("upload_id", uploadID.String()), zap.Error(err))
+		return entity.PSQLWrap(err, errors.New("error executing SQL query DeleteStaticUpload"))
+	}
+	return nil
+}
+
+func (s StaticUploadDB) DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	logger := middleware.GetLogger(ctx)
+	logger.Info("deleting expired static uploads", zap.Time("now", now), zap.Int("limit", limit))
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		logger.Error("error starting transaction", zap.Error(err))
+		return 0, entity.PSQLWrap(err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, deleteExpiredStaticUploadsQuery, now, limit)
+	if err != nil {
+		logger.Error("error deleting expired static uploads", zap.Error(err))
+		return 0, entity.PSQLWrap(err, errors.New("error executing SQL query DeleteExpiredStaticUploads"))
+	}
+	var expired []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			logger.Error("error scanning expired static upload", zap.Error(err))
+			return 0, entity.PSQLWrap(err)
+		}
+		expired = append(expired, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.Error("error iterating over expired static uploads", zap.Error(err))
+		return 0, entity.PSQLWrap(err)
+	}
+
+	// Если части удалить не удалось, транзакция откатывается и загрузка удаляется при следующем запуске
+	for _, id := range expired {
+		if err := s.Store.DeletePrefix(ctx, uploadPrefix(id)); err != nil {
+			logger.Error("error deleting expired static upload parts", zap.String("upload_id", id.String()), zap.Error(err))
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("error committing expired static uploads deletion", zap.Error(err))
+		return 0, entity.PSQLWrap(err)
+	}
+
+	logger.Info("expired static uploads deleted", zap.Int("deleted", len(expired)))
+	return len(expired), nil
+}
+
+// uploadPartsReader читает части загрузки подряд, открывая каждую только когда до нее дошла очередь.
+// Части открываются в контексте запроса, в котором была открыта загрузка
+type uploadPartsReader struct {
+	ctx     context.Context
+	store   repository.BlobStore
+	keys    []string
+	current io.ReadCloser
+}
+
+func (r *uploadPartsReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			part, err := r.store.Get(r.ctx, r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current, r.keys = part, r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			closeErr := r.current.Close()
+			r.current = nil
+			if closeErr != nil {
+				return n, closeErr
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *uploadPartsReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/blobstore"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func setupStaticUploadTest(t *testing.T) (pgxmock.PgxPoolIface, string, *StaticUploadDB) {
+	tempDir := t.TempDir()
+	mockPool, adapter := setupMockDB(t)
+	t.Cleanup(func() {
+		mockPool.Close()
+	})
+
+	store, err := blobstore.NewLocalStore(tempDir, zap.NewNop())
+	assert.NoError(t, err)
+
+	return mockPool, tempDir, &StaticUploadDB{
+		DB:      adapter,
+		Store:   store,
+		timeout: 10 * time.Second,
+	}
+}
+
+func TestStaticUploadDB_AppendChunkAndOpen(t *testing.T) {
+	mockPool, _, repo := setupStaticUploadTest(t)
+
+	uploadID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	for _, chunk := range []struct {
+		offset int64
+		data   string
+	}{{0, "hello, "}, {7, "world"}} {
+		mockPool.ExpectBegin()
+		mockPool.ExpectQuery("SELECT size, received FROM static_upload").
+			WithArgs(uploadID).
+			WillReturnRows(mockPool.NewRows([]string{"size", "received"}).AddRow(int64(12), chunk.offset))
+		mockPool.ExpectExec("INSERT INTO static_upload_part").
+			WithArgs(uploadID, chunk.offset, len(chunk.data)).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mockPool.ExpectQuery("UPDATE static_upload").
+			WithArgs(uploadID, len(chunk.data), expiresAt).
+			WillReturnRows(mockPool.NewRows([]string{"received"}).AddRow(chunk.offset + int64(len(chunk.data))))
+		mockPool.ExpectCommit()
+		mockPool.ExpectRollback()
+
+		received, err := repo.AppendChunk(context.Background(), uploadID, chunk.offset, []byte(chunk.data), expiresAt)
+		assert.NoError(t, err)
+		assert.Equal(t, chunk.offset+int64(len(chunk.data)), received)
+	}
+
+	mockPool.ExpectQuery("SELECT start FROM static_upload_part").
+		WithArgs(uploadID).
+		WillReturnRows(mockPool.NewRows([]string{"start"}).AddRow(int64(0)).AddRow(int64(7)))
+
+	content, err := repo.Open(context.Background(), uploadID)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(content)
+	assert.NoError(t, err)
+	assert.NoError(t, content.Close())
+	assert.Equal(t, "hello, world", string(data))
+
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStaticUploadDB_AppendChunk_Rejected(t *testing.T) {
+	mockPool, tempDir, repo := setupStaticUploadTest(t)
+
+	uploadID := uuid.New()
+	tests := []struct {
+		name     string
+		offset   int64
+		data     string
+		received int64
+		err      error
+	}{
+		{name: "OffsetMismatch", offset: 0, data: "again", received: 5, err: repository.ErrStaticUploadOffset},
+		{name: "Overflow", offset: 5, data: "too long", received: 5, err: repository.ErrStaticUploadOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPool.ExpectBegin()
+			mockPool.ExpectQuery("SELECT size, received FROM static_upload").
+				WithArgs(uploadID).
+				WillReturnRows(mockPool.NewRows([]string{"size", "received"}).AddRow(int64(10), tt.received))
+			mockPool.ExpectRollback()
+
+			received, err := repo.AppendChunk(context.Background(), uploadID, tt.offset, []byte(tt.data), time.Now())
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.received, received)
+		})
+	}
+
+	_, err := os.Stat(filepath.Join(tempDir, "uploads", uploadID.String()))
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
+
+func TestStaticUploadDB_DeleteExpired(t *testing.T) {
+	mockPool, tempDir, repo := setupStaticUploadTest(t)
+
+	expiredID := uuid.New()
+	assert.NoError(t, repo.Store.Put(context.Background(), uploadPartKey(expiredID, 0), strings.NewReader("part"), 4, "application/octet-stream"))
+
+	now := time.Now()
+	mockPool.ExpectBegin()
+	mockPool.ExpectQuery("DELETE FROM static_upload").
+		WithArgs(now, 100).
+		WillReturnRows(mockPool.NewRows([]string{"id"}).AddRow(expiredID))
+	mockPool.ExpectCommit()
+	mockPool.ExpectRollback()
+
+	deleted, err := repo.DeleteExpired(context.Background(), now, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = os.Stat(filepath.Join(tempDir, "uploads", expiredID.String()))
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, mockPool.ExpectationsWereMet())
+}
@@ -53,7 +53,6 @@ const (
 
 type UserDB struct {
 	DB      DBExecutor
-	ctx     context.Context
 	timeout time.Duration
 }
 
@@ -77,7 +76,6 @@ func NewUserRepository(db *pgxpool.Pool, ctx context.Context, timeout time.Durat
 
 	return &UserDB{
 		DB:      db,
-		ctx:     ctx,
 		timeout: timeout,
 	}, nil
 }
@@ -97,11 +95,11 @@ func (us *DBUser) GetEntity() entity.User {
 	}
 }
 
-func (us *UserDB) BeginTransaction() (pgx.Tx, error) {
-	logger := middleware.GetLogger(us.ctx)
+func (us *UserDB) BeginTransaction(ctx context.Context) (pgx.Tx, error) {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("beginning transaction")
 
-	tx, err := us.DB.Begin(us.ctx)
+	tx, err := us.DB.Begin(ctx)
 	if err != nil {
 		logger.Error("failed to begin transaction", zap.Error(err))
 		return nil, err
@@ -109,12 +107,12 @@ func (us *UserDB) BeginTransaction() (pgx.Tx, error) {
 	return tx, nil
 }
 
-func (us *UserDB) Add(tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, error) {
+func (us *UserDB) Add(ctx context.Context, tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, error) {
 	var dbUser DBUser
 
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("adding user to db", zap.String("email", email))
 
 	err := tx.QueryRow(ctx, queryAddUser, email, hash, salt).Scan(
@@ -140,12 +138,12 @@ func (us *UserDB) Add(tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, er
 	return dbUser.ID, nil
 }
 
-func (us *UserDB) GetByEmail(email string) (*entity.User, error) {
+func (us *UserDB) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var dbUser DBUser
 
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting user by email from db", zap.String("email", email))
 
 	err := us.DB.QueryRow(ctx, queryGetUserByEmail, email).Scan(
@@ -174,12 +172,12 @@ func (us *UserDB) GetByEmail(email string) (*entity.User, error) {
 	return &user, nil
 }
 
-func (us *UserDB) GetById(id uuid.UUID) (*entity.User, error) {
+func (us *UserDB) GetById(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	var dbUser DBUser
 
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("getting user by id from db", zap.String("id", id.String()))
 
 	err := us.DB.QueryRow(ctx, queryGetUserById, id).Scan(
@@ -208,10 +206,10 @@ func (us *UserDB) GetById(id uuid.UUID) (*entity.User, error) {
 	return &user, nil
 }
 
-func (us *UserDB) Update(user *entity.User) error {
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+func (us *UserDB) Update(ctx context.Context, user *entity.User) error {
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("updating user in db", zap.String("id", user.ID.String()))
 
 	ctag, err := us.DB.Exec(ctx, queryUpdateUser, user.Username, user.Phone, user.ID)
@@ -234,10 +232,10 @@ func (us *UserDB) Update(user *entity.User) error {
 	return nil
 }
 
-func (us *UserDB) Delete(userID uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+func (us *UserDB) Delete(ctx context.Context, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("deleting user from db", zap.String("id", userID.String()))
 
 	ctag, err := us.DB.Exec(ctx, queryDeleteUser, userID)
@@ -260,10 +258,10 @@ func (us *UserDB) Delete(userID uuid.UUID) error {
 	return nil
 }
 
-func (us *UserDB) UploadImage(userID uuid.UUID, imageId uuid.UUID) error {
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+func (us *UserDB) UploadImage(ctx context.Context, userID uuid.UUID, imageId uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("uploading image to db", zap.String("user_id", userID.String()))
 
 	result, err := us.DB.Exec(ctx, uploadAvatarQuery, imageId, userID)
@@ -281,10 +279,10 @@ func (us *UserDB) UploadImage(userID uuid.UUID, imageId uuid.UUID) error {
 	return nil
 }
 
-func (us *UserDB) CheckIfExists(userId uuid.UUID) (bool, error) {
-	ctx, cancel := context.WithTimeout(us.ctx, us.timeout)
+func (us *UserDB) CheckIfExists(ctx context.Context, userId uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
-	logger := middleware.GetLogger(us.ctx)
+	logger := middleware.GetLogger(ctx)
 	logger.Info("checking if user exists in db", zap.String("user_id", userId.String()))
 
 	var exists bool
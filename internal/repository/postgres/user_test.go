@@ -24,16 +24,12 @@ func setupUserMockDB(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter)
 
 func setupUserTest(t *testing.T) (pgxmock.PgxPoolIface, *mocks.PgxMockAdapter, *UserDB, func()) {
 	mockPool, adapter := setupUserMockDB(t)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
 	repo := &UserDB{
 		DB:      adapter,
-		ctx:     ctx,
 		timeout: 10 * time.Second,
 	}
 
 	return mockPool, adapter, repo, func() {
-		cancel()
 		mockPool.Close()
 	}
 }
@@ -44,7 +40,7 @@ func TestUserDB_AddUser(t *testing.T) {
 
 	mockPool.ExpectBegin()
 
-	tx, err := repo.BeginTransaction()
+	tx, err := repo.BeginTransaction(context.Background())
 	assert.NoError(t, err)
 
 	email := "test@example.com"
@@ -56,7 +52,7 @@ func TestUserDB_AddUser(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "password_hash", "password_salt", "username", "phone_number", "image_id", "status"}).
 			AddRow(uuid.New(), email, hash, salt, sql.NullString{Valid: false}, sql.NullString{Valid: false}, uuid.Nil, "active"))
 
-	id, err := repo.Add(tx, email, hash, salt)
+	id, err := repo.Add(context.Background(), tx, email, hash, salt)
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, id)
 
@@ -64,7 +60,7 @@ func TestUserDB_AddUser(t *testing.T) {
 		WithArgs(email, hash, salt).
 		WillReturnError(errors.New("insert error"))
 
-	_, err = repo.Add(tx, email, hash, salt)
+	_, err = repo.Add(context.Background(), tx, email, hash, salt)
 	assert.Error(t, err)
 
 	mockPool.ExpectRollback()
@@ -85,7 +81,7 @@ func TestUserDB_GetUserById(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "password_hash", "password_salt", "username", "phone_number", "image_id", "status", "created_at", "updated_at"}).
 			AddRow(userId, "test@example.com", []byte("hash"), []byte("salt"), sql.NullString{String: "Test User", Valid: true}, sql.NullString{String: "1234567890", Valid: true}, uuid.Nil, sql.NullString{String: "active", Valid: true}, time.Now(), time.Now()))
 
-	user, err := repo.GetById(userId)
+	user, err := repo.GetById(context.Background(), userId)
 	assert.NoError(t, err)
 	assert.Equal(t, "Test User", user.Username)
 
@@ -93,7 +89,7 @@ func TestUserDB_GetUserById(t *testing.T) {
 		WithArgs(userId).
 		WillReturnError(pgx.ErrNoRows)
 
-	user, err = repo.GetById(userId)
+	user, err = repo.GetById(context.Background(), userId)
 	assert.Error(t, err)
 	assert.Nil(t, user)
 
@@ -112,7 +108,7 @@ func TestUserDB_DeleteUser(t *testing.T) {
 		WithArgs(userId).
 		WillReturnResult(pgxmock.NewResult("DELETE", 1))
 
-	err := repo.Delete(userId)
+	err := repo.Delete(context.Background(), userId)
 	assert.NoError(t, err)
 
 	// Ожидаем, что строка не будет найдена
@@ -120,7 +116,7 @@ func TestUserDB_DeleteUser(t *testing.T) {
 		WithArgs(userId).
 		WillReturnResult(pgxmock.NewResult("DELETE", 0))
 
-	err = repo.Delete(userId)
+	err = repo.Delete(context.Background(), userId)
 	assert.Error(t, err)
 	assert.Equal(t, repository.ErrUserNotFound, err)
 
@@ -129,7 +125,7 @@ func TestUserDB_DeleteUser(t *testing.T) {
 		WithArgs(userId).
 		WillReturnError(errors.New("delete error"))
 
-	err = repo.Delete(userId)
+	err = repo.Delete(context.Background(), userId)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error deleting user")
 
@@ -149,7 +145,7 @@ func TestUserDB_GetByEmail(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"id", "email", "password_hash", "password_salt", "username", "phone_number", "image_id", "status", "created_at", "updated_at"}).
 			AddRow(uuid.New(), email, []byte("hash"), []byte("salt"), sql.NullString{String: "Test User", Valid: true}, sql.NullString{String: "1234567890", Valid: true}, uuid.Nil, sql.NullString{String: "active", Valid: true}, time.Now(), time.Now()))
 
-	user, err := repo.GetByEmail(email)
+	user, err := repo.GetByEmail(context.Background(), email)
 	assert.NoError(t, err)
 	assert.Equal(t, "Test User", user.Username)
 
@@ -158,7 +154,7 @@ func TestUserDB_GetByEmail(t *testing.T) {
 		WithArgs(email).
 		WillReturnError(pgx.ErrNoRows)
 
-	user, err = repo.GetByEmail(email)
+	user, err = repo.GetByEmail(context.Background(), email)
 	assert.Error(t, err)
 	assert.Nil(t, user)
 
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"errors"
+
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/jackc/pgx/v5"
 	"github.com/google/uuid"
@@ -9,11 +12,28 @@ import (
 
 type PurchaseRepository interface {
 	// BeginTransaction начинает транзакцию
-	BeginTransaction() (pgx.Tx, error)
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
 
 	// AddPurchase создает запись о покупке
-	Add(tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase, error)
+	Add(ctx context.Context, tx pgx.Tx, purchase *entity.Purchase) (*entity.Purchase, error)
 
 	// GetPurchasesByUserID получает покупки по UserID
-	GetByUserId(userID uuid.UUID) ([]*entity.Purchase, error)
+	GetByUserId(ctx context.Context, userID uuid.UUID) ([]*entity.Purchase, error)
+
+	// GetById получает покупку по ID
+	// Возможные ошибки:
+	// ErrPurchaseNotFound - покупка не найдена
+	GetById(ctx context.Context, purchaseID uuid.UUID) (*entity.Purchase, error)
+
+	// AddAddress сохраняет снимок адреса покупки в транзакции, в которой создана покупка
+	AddAddress(ctx context.Context, tx pgx.Tx, snapshot *entity.PurchaseAddress) error
+
+	// UpdateStatus обновляет статус покупки
+	// Возможные ошибки:
+	// ErrPurchaseNotFound - покупка не найдена
+	UpdateStatus(ctx context.Context, tx pgx.Tx, purchaseID uuid.UUID, status entity.PurchaseStatus) error
 }
+
+var (
+	ErrPurchaseNotFound = errors.New("purchase not found")
+)
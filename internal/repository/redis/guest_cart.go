@@ -0,0 +1,105 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const guestCartPlaceholder = "guest_cart:"
+
+// addGuestCartAdvert добавляет объявление в отсортированное по времени добавления множество,
+// если его там нет и корзина не заполнена. Возвращает 0 для повторного добавления и -1,
+// если корзина заполнена
+var addGuestCartAdvert = redis.NewScript(`
+if redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+	return 0
+end
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+	return -1
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return 1
+`)
+
+type GuestCartDB struct {
+	rdb    *redis.Client
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+func NewGuestCartRepository(rdb *redis.Client, ttl time.Duration, ctx context.Context, logger *zap.Logger) (*GuestCartDB, error) {
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return &GuestCartDB{
+		rdb:    rdb,
+		ttl:    ttl,
+		logger: logger,
+	}, nil
+}
+
+func guestCartKey(guestID uuid.UUID) string {
+	return guestCartPlaceholder + guestID.String()
+}
+
+func (g *GuestCartDB) AddAdvert(ctx context.Context, guestID, advertID uuid.UUID, limit int) error {
+	added, err := addGuestCartAdvert.Run(ctx, g.rdb, []string{guestCartKey(guestID)},
+		advertID.String(), time.Now().UnixNano(), limit, g.ttl.Milliseconds()).Int()
+	if err != nil {
+		g.logger.Error("error adding advert to guest cart", zap.String("guest_id", guestID.String()),
+			zap.String("advert_id", advertID.String()), zap.Error(err))
+		return entity.RedisWrap(repository.ErrGuestCartFailed, err)
+	}
+	if added < 0 {
+		return repository.ErrGuestCartFull
+	}
+	return nil
+}
+
+func (g *GuestCartDB) GetAdverts(ctx context.Context, guestID uuid.UUID) ([]uuid.UUID, error) {
+	members, err := g.rdb.ZRange(ctx, guestCartKey(guestID), 0, -1).Result()
+	if err != nil {
+		g.logger.Error("error getting guest cart", zap.String("guest_id", guestID.String()), zap.Error(err))
+		return nil, entity.RedisWrap(repository.ErrGuestCartFailed, err)
+	}
+
+	advertIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		advertID, err := uuid.Parse(member)
+		if err != nil {
+			g.logger.Error("skipping invalid guest cart advert", zap.String("guest_id", guestID.String()), zap.String("advert_id", member))
+			continue
+		}
+		advertIDs = append(advertIDs, advertID)
+	}
+	return advertIDs, nil
+}
+
+func (g *GuestCartDB) DeleteAdvert(ctx context.Context, guestID, advertID uuid.UUID) error {
+	removed, err := g.rdb.ZRem(ctx, guestCartKey(guestID), advertID.String()).Result()
+	if err != nil {
+		g.logger.Error("error deleting advert from guest cart", zap.String("guest_id", guestID.String()),
+			zap.String("advert_id", advertID.String()), zap.Error(err))
+		return entity.RedisWrap(repository.ErrGuestCartFailed, err)
+	}
+	if removed == 0 {
+		return repository.ErrGuestCartAdvertNotFound
+	}
+	return nil
+}
+
+func (g *GuestCartDB) Delete(ctx context.Context, guestID uuid.UUID) error {
+	if err := g.rdb.Del(ctx, guestCartKey(guestID)).Err(); err != nil {
+		g.logger.Error("error deleting guest cart", zap.String("guest_id", guestID.String()), zap.Error(err))
+		return entity.RedisWrap(repository.ErrGuestCartFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const idempotencyPlaceholder = "idempotency:"
+
+type IdempotencyDB struct {
+	rdb *redis.Client
+	// ttl - сколько хранится ответ на запрос, lockTTL - сколько ключ остается занятым
+	// запросом, который так и не завершился, например из-за падения сервера
+	ttl     time.Duration
+	lockTTL time.Duration
+	logger  *zap.Logger
+}
+
+func NewIdempotencyRepository(rdb *redis.Client, ttl, lockTTL time.Duration, ctx context.Context, logger *zap.Logger) (*IdempotencyDB, error) {
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return &IdempotencyDB{
+		rdb:     rdb,
+		ttl:     ttl,
+		lockTTL: lockTTL,
+		logger:  logger,
+	}, nil
+}
+
+func idempotencyKey(userID uuid.UUID, key string) string {
+	return idempotencyPlaceholder + userID.String() + ":" + key
+}
+
+// Reserve занимает ключ атомарно через SET NX, поэтому из одновременных запросов с одним
+// ключом обрабатывается только первый
+func (i *IdempotencyDB) Reserve(ctx context.Context, userID uuid.UUID, key, fingerprint string) (*entity.IdempotencyRecord, bool, error) {
+	payload, err := json.Marshal(entity.IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, entity.RedisWrap(repository.ErrIdempotencyReserveFailed, err)
+	}
+
+	redisKey := idempotencyKey(userID, key)
+	reserved, err := i.rdb.SetNX(ctx, redisKey, payload, i.lockTTL).Result()
+	if err != nil {
+		i.logger.Error("error reserving idempotency key", zap.String("key", redisKey), zap.Error(err))
+		return nil, false, entity.RedisWrap(repository.ErrIdempotencyReserveFailed, err)
+	}
+	if reserved {
+		return nil, true, nil
+	}
+
+	stored, err := i.rdb.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// ключ истек или был освобожден между SET NX и GET
+		return i.Reserve(ctx, userID, key, fingerprint)
+	}
+	if err != nil {
+		i.logger.Error("error getting idempotency record", zap.String("key", redisKey), zap.Error(err))
+		return nil, false, entity.RedisWrap(repository.ErrIdempotencyReserveFailed, err)
+	}
+
+	var record entity.IdempotencyRecord
+	if err := json.Unmarshal(stored, &record); err != nil {
+		return nil, false, entity.RedisWrap(repository.ErrIdempotencyReserveFailed, err)
+	}
+	return &record, false, nil
+}
+
+func (i *IdempotencyDB) Complete(ctx context.Context, userID uuid.UUID, key string, record entity.IdempotencyRecord) error {
+	record.Completed = true
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return entity.RedisWrap(repository.ErrIdempotencyCompleteFailed, err)
+	}
+
+	redisKey := idempotencyKey(userID, key)
+	if err := i.rdb.Set(ctx, redisKey, payload, i.ttl).Err(); err != nil {
+		i.logger.Error("error saving idempotent response", zap.String("key", redisKey), zap.Error(err))
+		return entity.RedisWrap(repository.ErrIdempotencyCompleteFailed, err)
+	}
+	return nil
+}
+
+func (i *IdempotencyDB) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	redisKey := idempotencyKey(userID, key)
+	if err := i.rdb.Del(ctx, redisKey).Err(); err != nil {
+		i.logger.Error("error releasing idempotency key", zap.String("key", redisKey), zap.Error(err))
+		return entity.RedisWrap(repository.ErrIdempotencyReleaseFailed, err)
+	}
+	return nil
+}
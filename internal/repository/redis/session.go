@@ -17,7 +17,6 @@ const userSessionPlaceholder = "user_sessions:"
 type SessionDB struct {
 	rdb              *redis.Client
 	sessionAliveTime int
-	ctx              context.Context
 	logger           *zap.Logger
 }
 
@@ -28,29 +27,28 @@ func NewSessionRepository(rdb *redis.Client, sessionAliveTime int, ctx context.C
 	return &SessionDB{
 		rdb:              rdb,
 		sessionAliveTime: sessionAliveTime,
-		ctx:              ctx,
 		logger:           logger,
 	}, nil
 }
 
-func (s *SessionDB) Create(userID uuid.UUID) (string, error) {
+func (s *SessionDB) Create(ctx context.Context, userID uuid.UUID) (string, error) {
 	sessionID := uuid.NewString()
 
 	for {
-		_, err := s.rdb.Get(s.ctx, sessionID).Result()
+		_, err := s.rdb.Get(ctx, sessionID).Result()
 		if errors.Is(err, redis.Nil) {
 			break
 		}
 		sessionID = uuid.NewString()
 	}
 
-	err := s.rdb.Set(s.ctx, sessionID, userID.String(), time.Duration(s.sessionAliveTime)*time.Second).Err()
+	err := s.rdb.Set(ctx, sessionID, userID.String(), time.Duration(s.sessionAliveTime)*time.Second).Err()
 	if err != nil {
 		s.logger.Error("error creating session", zap.String("sessionID", sessionID), zap.String("userID", userID.String()), zap.Error(err))
 		return "", entity.RedisWrap(repository.ErrSessionCreationFailed, err)
 	}
 
-	err = s.rdb.SAdd(s.ctx, userSessionPlaceholder+userID.String(), sessionID).Err()
+	err = s.rdb.SAdd(ctx, userSessionPlaceholder+userID.String(), sessionID).Err()
 	if err != nil {
 		s.logger.Error("error adding session to user", zap.String("sessionID", sessionID), zap.String("userID", userID.String()), zap.Error(err))
 		return "", entity.RedisWrap(repository.ErrSessionCreationFailed, err)
@@ -58,8 +56,8 @@ func (s *SessionDB) Create(userID uuid.UUID) (string, error) {
 	return sessionID, nil
 }
 
-func (s *SessionDB) Get(sessionID string) (uuid.UUID, error) {
-	userID, err := s.rdb.Get(s.ctx, sessionID).Result()
+func (s *SessionDB) Get(ctx context.Context, sessionID string) (uuid.UUID, error) {
+	userID, err := s.rdb.Get(ctx, sessionID).Result()
 	if errors.Is(err, redis.Nil) {
 		s.logger.Error("session not found", zap.String("sessionID", sessionID))
 		return uuid.Nil, entity.RedisWrap(repository.ErrSessionNotFound, err)
@@ -77,8 +75,8 @@ func (s *SessionDB) Get(sessionID string) (uuid.UUID, error) {
 	return id, nil
 }
 
-func (s *SessionDB) Delete(sessionID string) error {
-	userID, err := s.rdb.Get(s.ctx, sessionID).Result()
+func (s *SessionDB) Delete(ctx context.Context, sessionID string) error {
+	userID, err := s.rdb.Get(ctx, sessionID).Result()
 	if errors.Is(err, redis.Nil) {
 		s.logger.Info("session not found", zap.String("sessionID", sessionID))
 		return nil
@@ -88,7 +86,7 @@ func (s *SessionDB) Delete(sessionID string) error {
 		return entity.RedisWrap(repository.ErrSessionDeleteFailed, err)
 	}
 
-	err = s.rdb.Del(s.ctx, sessionID).Err()
+	err = s.rdb.Del(ctx, sessionID).Err()
 	if err != nil {
 		s.logger.Error("error deleting session", zap.String("sessionID", sessionID), zap.Error(err))
 		return entity.RedisWrap(repository.ErrSessionDeleteFailed, err)
@@ -99,7 +97,7 @@ func (s *SessionDB) Delete(sessionID string) error {
 		return entity.RedisWrap(repository.ErrSessionDeleteFailed, errors.New("userID is empty"))
 	}
 
-	err = s.rdb.SRem(s.ctx, userSessionPlaceholder+userID, sessionID).Err()
+	err = s.rdb.SRem(ctx, userSessionPlaceholder+userID, sessionID).Err()
 	if err != nil {
 		s.logger.Error("error deleting session from user", zap.String("sessionID", sessionID), zap.String("userID", userID), zap.Error(err))
 		return entity.RedisWrap(repository.ErrSessionDeleteFailed, err)
@@ -0,0 +1,203 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	advertViewPlaceholder    = "advert_view:"
+	advertViewersPlaceholder = "advert_viewers:"
+	pendingViewsCountersKey  = "advert_views:pending"
+	pendingViewsEventsKey    = "advert_views:events"
+	flushingCountersKey      = "advert_views:flushing"
+	flushingEventsKey        = "advert_views:flushing_events"
+	flushingBatchKey         = "advert_views:flushing_batch"
+	viewsFlushLockKey        = "advert_views:flush_lock"
+	viewsFlushLockTTL        = time.Minute
+)
+
+// claimViews возвращает идентификатор пачки в ключах обработки. Если неподтвержденной пачки нет,
+// накопленные счетчики и просмотры переносятся в ключи обработки вместе, под новым идентификатором
+// ARGV[1]. Пустое накопление пачки не создает
+var claimViews = redis.NewScript(`
+local batch = redis.call('GET', KEYS[5])
+if batch then
+	return batch
+end
+if redis.call('EXISTS', KEYS[3], KEYS[4]) == 0 then
+	if redis.call('EXISTS', KEYS[1]) == 1 then
+		redis.call('RENAME', KEYS[1], KEYS[3])
+	end
+	if redis.call('EXISTS', KEYS[2]) == 1 then
+		redis.call('RENAME', KEYS[2], KEYS[4])
+	end
+end
+if redis.call('EXISTS', KEYS[3], KEYS[4]) == 0 then
+	return false
+end
+redis.call('SET', KEYS[5], ARGV[1])
+return ARGV[1]
+`)
+
+// ackViews удаляет пачку ARGV[1] и снимает блокировку. Если в ключах обработки уже другая
+// пачка, блокировка истекла и пачку забрал другой экземпляр, поэтому ничего не удаляется
+var ackViews = redis.NewScript(`
+local batch = redis.call('GET', KEYS[3])
+if batch and batch ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1], KEYS[2], KEYS[3], KEYS[4])
+return 1
+`)
+
+type ViewCounterDB struct {
+	rdb         *redis.Client
+	dedupWindow time.Duration
+	logger      *zap.Logger
+}
+
+func NewViewCounterRepository(rdb *redis.Client, dedupWindow time.Duration, ctx context.Context, logger *zap.Logger) (*ViewCounterDB, error) {
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return &ViewCounterDB{
+		rdb:         rdb,
+		dedupWindow: dedupWindow,
+		logger:      logger,
+	}, nil
+}
+
+// RegisterView засчитывает просмотр, если ключ зрителя в окне дедупликации еще не создан.
+// Окна фиксированные, поэтому просмотры на границе двух окон могут засчитаться дважды.
+// HyperLogLog окна хранит приблизительное число зрителей только для статистики: его ответ
+// не подходит для дедупликации, потому что новый зритель может не изменить его регистры
+func (v *ViewCounterDB) RegisterView(ctx context.Context, view entity.AdvertView) (bool, error) {
+	window := int64(v.dedupWindow.Seconds())
+	if window <= 0 {
+		window = 1
+	}
+	bucket := view.ViewedAt.Unix() / window
+	viewKey := fmt.Sprintf("%s%s:%s:%d", advertViewPlaceholder, view.AdvertId, view.ViewerKey(), bucket)
+	viewersKey := fmt.Sprintf("%s%s:%d", advertViewersPlaceholder, view.AdvertId, bucket)
+
+	pipe := v.rdb.TxPipeline()
+	added := pipe.SetNX(ctx, viewKey, 1, time.Duration(window)*time.Second)
+	pipe.PFAdd(ctx, viewersKey, view.ViewerKey())
+	pipe.Expire(ctx, viewersKey, 2*v.dedupWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		v.logger.Error("error adding viewer", zap.String("advert_id", view.AdvertId.String()), zap.Error(err))
+		return false, entity.RedisWrap(repository.ErrViewRegisterFailed, err)
+	}
+	if !added.Val() {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(view)
+	if err != nil {
+		return false, entity.RedisWrap(repository.ErrViewRegisterFailed, err)
+	}
+
+	pipe = v.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, pendingViewsCountersKey, view.AdvertId.String(), 1)
+	pipe.RPush(ctx, pendingViewsEventsKey, payload)
+	if _, err := pipe.Exec(ctx); err != nil {
+		v.logger.Error("error counting view", zap.String("advert_id", view.AdvertId.String()), zap.Error(err))
+		return false, entity.RedisWrap(repository.ErrViewRegisterFailed, err)
+	}
+
+	return true, nil
+}
+
+// claim возвращает идентификатор пачки в ключах обработки или uuid.Nil, если переносить нечего
+func (v *ViewCounterDB) claim(ctx context.Context) (uuid.UUID, error) {
+	keys := []string{pendingViewsCountersKey, pendingViewsEventsKey, flushingCountersKey, flushingEventsKey, flushingBatchKey}
+	batch, err := claimViews.Run(ctx, v.rdb, keys, uuid.New().String()).Text()
+	if errors.Is(err, redis.Nil) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(batch)
+}
+
+func (v *ViewCounterDB) TakePending(ctx context.Context) (*entity.PendingViews, error) {
+	locked, err := v.rdb.SetNX(ctx, viewsFlushLockKey, 1, viewsFlushLockTTL).Result()
+	if err != nil {
+		v.logger.Error("error locking views flush", zap.Error(err))
+		return nil, entity.RedisWrap(repository.ErrViewFlushFailed, err)
+	}
+	if !locked {
+		return nil, repository.ErrViewFlushLocked
+	}
+
+	batchId, err := v.claim(ctx)
+	if err != nil {
+		v.logger.Error("error claiming pending views", zap.Error(err))
+		return nil, entity.RedisWrap(repository.ErrViewFlushFailed, err)
+	}
+
+	rawCounters, err := v.rdb.HGetAll(ctx, flushingCountersKey).Result()
+	if err != nil {
+		v.logger.Error("error getting pending counters", zap.Error(err))
+		return nil, entity.RedisWrap(repository.ErrViewFlushFailed, err)
+	}
+	rawViews, err := v.rdb.LRange(ctx, flushingEventsKey, 0, -1).Result()
+	if err != nil {
+		v.logger.Error("error getting pending views", zap.Error(err))
+		return nil, entity.RedisWrap(repository.ErrViewFlushFailed, err)
+	}
+
+	pending := &entity.PendingViews{
+		BatchId:  batchId,
+		Counters: make(map[uuid.UUID]uint, len(rawCounters)),
+		Views:    make([]entity.AdvertView, 0, len(rawViews)),
+	}
+	for rawId, rawCount := range rawCounters {
+		advertId, err := uuid.Parse(rawId)
+		if err != nil {
+			v.logger.Error("skipping counter with invalid advert id", zap.String("advert_id", rawId))
+			continue
+		}
+		count, err := strconv.ParseUint(rawCount, 10, 64)
+		if err != nil {
+			v.logger.Error("skipping invalid counter", zap.String("advert_id", rawId), zap.String("count", rawCount))
+			continue
+		}
+		pending.Counters[advertId] = uint(count)
+	}
+	for _, raw := range rawViews {
+		var view entity.AdvertView
+		if err := json.Unmarshal([]byte(raw), &view); err != nil {
+			v.logger.Error("skipping invalid view", zap.String("view", raw), zap.Error(err))
+			continue
+		}
+		pending.Views = append(pending.Views, view)
+	}
+
+	return pending, nil
+}
+
+func (v *ViewCounterDB) AckPending(ctx context.Context, batchId uuid.UUID) error {
+	keys := []string{flushingCountersKey, flushingEventsKey, flushingBatchKey, viewsFlushLockKey}
+	acked, err := ackViews.Run(ctx, v.rdb, keys, batchId.String()).Int()
+	if err != nil {
+		v.logger.Error("error acknowledging views flush", zap.Error(err))
+		return entity.RedisWrap(repository.ErrViewFlushFailed, err)
+	}
+	if acked == 0 {
+		v.logger.Warn("views batch was taken by another flush", zap.String("batch_id", batchId.String()))
+	}
+	return nil
+}
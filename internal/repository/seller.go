@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -10,13 +11,13 @@ import (
 
 type Seller interface {
 	// Add добавляет нового продавца в бд в рамках транзакции
-	Add(tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error)
+	Add(ctx context.Context, tx pgx.Tx, userID uuid.UUID) (uuid.UUID, error)
 
 	// GetById возвращает продавца по его ID
-	GetById(sellerID uuid.UUID) (*entity.Seller, error)
+	GetById(ctx context.Context, sellerID uuid.UUID) (*entity.Seller, error)
 
 	// GetByUserId возвращает продавца по ID пользователя
-	GetByUserId(userID uuid.UUID) (*entity.Seller, error)
+	GetByUserId(ctx context.Context, userID uuid.UUID) (*entity.Seller, error)
 }
 
 var (
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/google/uuid"
@@ -8,11 +9,11 @@ import (
 
 type Session interface {
 	// Create создает сессию для пользователя
-	Create(userID uuid.UUID) (string, error)
+	Create(ctx context.Context, userID uuid.UUID) (string, error)
 	// Get возвращает id пользователя по sessionID
-	Get(sessionID string) (uuid.UUID, error)
+	Get(ctx context.Context, sessionID string) (uuid.UUID, error)
 	// Delete удаляет сессию
-	Delete(sessionID string) error
+	Delete(ctx context.Context, sessionID string) error
 }
 
 var (
@@ -1,16 +1,36 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"io"
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type StaticRepository interface {
-	// Get возвращает путь к статическому файлу по его ID
-	Get(staticID uuid.UUID) (string, error)
+	// Get возвращает путь к статическому файлу по его ID. Статика, помеченная для ручной
+	// проверки, считается ненайденной, пока модератор ее не одобрит
+	Get(ctx context.Context, staticID uuid.UUID) (string, error)
+
+	// Upload загружает статический файл размером size из data и возвращает его ID. Файлы с одинаковым
+	// именем хранятся один раз: новая строка статики увеличивает счетчик ссылок на уже сохраненный файл
+	Upload(ctx context.Context, path, filename string, data io.Reader, size int64) (uuid.UUID, error)
+
+	// Flag помечает статику для ручной проверки модератором с указанием причины.
+	// До проверки такая статика не отдается и не привязывается к объявлениям
+	Flag(ctx context.Context, staticID uuid.UUID, reason string) error
+
+	// IsFlagged сообщает, что файлы source - сам файл статики, его копию и варианты - отдавать нельзя:
+	// все строки статики с таким именем файла без расширения помечены для ручной проверки.
+	// Файлы, на которые не ссылается ни одна строка статики, помеченными не считаются
+	IsFlagged(ctx context.Context, source string) (bool, error)
 
-	// Upload загружает статический файл и возвращает его ID
-	Upload(path, filename string, data []byte) (uuid.UUID, error)
+	// CollectGarbage удаляет не более limit строк статики старше olderThan, на которые не ссылаются
+	// пользователи и объявления, и файлы, на которые больше не ссылается ни одна строка.
+	// Возвращает ключи удаленных файлов
+	CollectGarbage(ctx context.Context, olderThan time.Time, limit int) ([]string, error)
 
 	// GetMaxSize возвращает максимальный размер файла
 	GetMaxSize() int
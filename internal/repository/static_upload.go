@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+)
+
+type StaticUploadRepository interface {
+	// Create сохраняет новую загрузку по частям
+	Create(ctx context.Context, upload *entity.ResumableUpload) error
+
+	// Get возвращает загрузку по ID
+	// Возможные ошибки:
+	// ErrStaticUploadNotFound - загрузка не найдена или срок ее жизни истек
+	Get(ctx context.Context, uploadID uuid.UUID) (*entity.ResumableUpload, error)
+
+	// AppendChunk сохраняет часть data, начинающуюся с offset, продлевает загрузку до expiresAt
+	// и возвращает новое смещение
+	// Возможные ошибки:
+	// ErrStaticUploadNotFound - загрузка не найдена или срок ее жизни истек
+	// ErrStaticUploadOffset - offset не совпадает с числом уже полученных байт
+	// ErrStaticUploadOverflow - часть выходит за заявленный размер файла
+	AppendChunk(ctx context.Context, uploadID uuid.UUID, offset int64, data []byte, expiresAt time.Time) (int64, error)
+
+	// Open возвращает полученное содержимое загрузки, части читаются из хранилища по очереди
+	Open(ctx context.Context, uploadID uuid.UUID) (io.ReadCloser, error)
+
+	// Delete удаляет загрузку и ее части, отсутствие загрузки ошибкой не считается
+	Delete(ctx context.Context, uploadID uuid.UUID) error
+
+	// DeleteExpired удаляет не более limit загрузок, срок жизни которых истек к now,
+	// и возвращает число удаленных
+	DeleteExpired(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+var (
+	ErrStaticUploadNotFound = errors.New("загрузка не найдена")
+	ErrStaticUploadOffset   = errors.New("смещение части не совпадает с полученным размером загрузки")
+	ErrStaticUploadOverflow = errors.New("часть выходит за размер загрузки")
+)
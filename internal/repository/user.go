@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/google/uuid"
@@ -10,21 +11,21 @@ import (
 )
 
 type User interface {
-	BeginTransaction() (pgx.Tx, error)
+	BeginTransaction(ctx context.Context) (pgx.Tx, error)
 	// Add добавляет пользователя в базу в рамках транзакции
-	Add(tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, error)
+	Add(ctx context.Context, tx pgx.Tx, email string, hash, salt []byte) (uuid.UUID, error)
 	// GetByEmail возвращает пользователя по его емейлу
-	GetByEmail(email string) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	// GetById возвращает пользователя по его id
-	GetById(userId uuid.UUID) (*entity.User, error)
+	GetById(ctx context.Context, userId uuid.UUID) (*entity.User, error)
 	// Update обновляет данные пользователя в рамках транзакции
-	Update(user *entity.User) error
+	Update(ctx context.Context, user *entity.User) error
 	// Delete удаляет пользователя
-	Delete(userID uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID) error
 	// CheckIfExists проверяет, существует ли пользователь
-	CheckIfExists(userId uuid.UUID) (bool, error)
+	CheckIfExists(ctx context.Context, userId uuid.UUID) (bool, error)
 	// UploadImage обновляет аватар пользователя
-	UploadImage(userID uuid.UUID, imageId uuid.UUID) error
+	UploadImage(ctx context.Context, userID uuid.UUID, imageId uuid.UUID) error
 }
 
 var (
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+)
+
+type ViewCounter interface {
+	// RegisterView учитывает просмотр, если зритель не просматривал объявление в текущем окне дедупликации.
+	// Возвращает true, если просмотр засчитан
+	RegisterView(ctx context.Context, view entity.AdvertView) (bool, error)
+	// TakePending забирает накопленные просмотры для переноса в Postgres.
+	// Пока перенос не подтвержден через AckPending, повторный вызов вернет те же просмотры с тем же BatchId
+	TakePending(ctx context.Context) (*entity.PendingViews, error)
+	// AckPending подтверждает перенос пачки batchId, полученной через TakePending.
+	// Подтверждение устаревшей пачки ничего не удаляет
+	AckPending(ctx context.Context, batchId uuid.UUID) error
+}
+
+var (
+	ErrViewRegisterFailed = errors.New("failed to register view")
+	ErrViewFlushLocked    = errors.New("views are being flushed by another instance")
+	ErrViewFlushFailed    = errors.New("failed to take pending views")
+)
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type AddressUseCase interface {
+	// GetAddresses возвращает адресную книгу пользователя, начиная с адреса по умолчанию
+	GetAddresses(ctx context.Context, userID uuid.UUID) ([]*dto.AddressResponse, error)
+
+	// AddAddress сохраняет адрес в адресную книгу. Первый адрес становится адресом по умолчанию
+	// Возможные ошибки:
+	// ErrAddressBadRequest - не заполнены обязательные поля или некорректны телефон и индекс
+	// ErrAddressLimit - в адресной книге уже entity.MaxUserAddresses адресов
+	AddAddress(ctx context.Context, userID uuid.UUID, req dto.AddressRequest) (*dto.AddressResponse, error)
+
+	// UpdateAddress изменяет адрес пользователя
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	// ErrAddressBadRequest - не заполнены обязательные поля или некорректны телефон и индекс
+	UpdateAddress(ctx context.Context, addressID, userID uuid.UUID, req dto.AddressRequest) (*dto.AddressResponse, error)
+
+	// DeleteAddress удаляет адрес пользователя. Если он был адресом по умолчанию,
+	// им становится последний добавленный адрес
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	DeleteAddress(ctx context.Context, addressID, userID uuid.UUID) error
+
+	// SetDefault делает адрес адресом по умолчанию
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	SetDefault(ctx context.Context, addressID, userID uuid.UUID) (*dto.AddressResponse, error)
+
+	// ResolveCheckout возвращает адрес покупки: сохраненный адрес addressID или проверенный адрес details.
+	// Если не передано ни то, ни другое, возвращает nil
+	// Возможные ошибки:
+	// ErrAddressNotFound - адрес не найден или принадлежит другому пользователю
+	// ErrAddressBadRequest - переданы оба адреса, не заполнены обязательные поля или некорректны телефон и индекс
+	ResolveCheckout(ctx context.Context, userID uuid.UUID, addressID *uuid.UUID, details *dto.AddressDetails) (*dto.PurchaseAddress, error)
+
+	// GetPurchaseAddresses возвращает снимки адресов переданных покупок по идентификатору покупки
+	GetPurchaseAddresses(ctx context.Context, purchaseIDs []uuid.UUID) (map[uuid.UUID]*dto.PurchaseAddress, error)
+}
+
+var (
+	ErrAddressBadRequest = errors.New("invalid address")
+)
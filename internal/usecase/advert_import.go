@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type AdvertImportUseCase interface {
+	// Import создает или обновляет объявления продавца по строкам файла.
+	// Ошибки отдельных строк попадают в отчет и не прерывают импорт.
+	// Строки со статусами reserved и scheduled из экспорта пропускаются.
+	// При dryRun строки только проверяются, изменения не сохраняются
+	// Возможные ошибки:
+	// ErrImportTooManyRows - в файле слишком много строк
+	Import(ctx context.Context, userId uuid.UUID, rows []dto.AdvertImportRow, dryRun bool) (*dto.AdvertImportReport, error)
+
+	// Export возвращает все объявления продавца в виде строк импорта
+	Export(ctx context.Context, userId uuid.UUID) ([]dto.AdvertImportRow, error)
+}
+
+var (
+	ErrImportTooManyRows     = errors.New("too many rows in import file")
+	ErrImportUnknownCategory = errors.New("unknown category")
+	ErrImportDuplicateSKU    = errors.New("duplicate external sku in import file")
+	ErrImportInvalidStatus   = errors.New("invalid advert status for import")
+	ErrImportStatusManaged   = errors.New("reserved and scheduled adverts are not changed by import, row skipped")
+	ErrImportAdvertLocked    = errors.New("advert with this external sku is reserved by a purchase or scheduled for publication")
+)
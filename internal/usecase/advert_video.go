@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type AdvertVideoUseCase interface {
+	// AddVideo прикрепляет загруженное видео videoID к объявлению продавца-пользователя userID
+	AddVideo(ctx context.Context, advertID, videoID, userID uuid.UUID) (*dto.AdvertVideo, error)
+
+	// GetVideos возвращает видео объявления в порядке показа
+	GetVideos(ctx context.Context, advertID uuid.UUID) ([]*dto.AdvertVideo, error)
+
+	// DeleteVideo открепляет видео от объявления продавца-пользователя userID
+	DeleteVideo(ctx context.Context, advertID, videoID, userID uuid.UUID) error
+}
+
+var (
+	ErrAdvertVideoAdvertNotFound = errors.New("advert not found")
+	ErrAdvertVideoForbidden      = errors.New("advert belongs to another seller")
+	ErrAdvertVideoLimit          = errors.New("advert already has the maximum number of videos")
+	ErrAdvertVideoNotFound       = errors.New("advert video not found")
+)
@@ -1,74 +1,135 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/google/uuid"
 )
 
 type AdvertUseCase interface {
 	// Get возвращает массив объявлений в соответствии с offset и limit
-	Get(limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+	Get(ctx context.Context, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
 
 	// GetByUserId возвращает массив объявлений в соответствии с userId
-	GetByUserId(userId uuid.UUID) ([]*dto.MyPreviewAdvertCard, error)
+	GetByUserId(ctx context.Context, userId uuid.UUID) ([]*dto.MyPreviewAdvertCard, error)
 
 	// GetByCartId возвращает массив объявлений, которые находятся в корзине
-	GetByCartId(cartId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+	GetByCartId(ctx context.Context, cartId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
 
 	// GetById возвращает объявление по его идентификатору
 	// Если объявление не найдено, возвращает ErrAdvertNotFound
-	GetById(advertId, userId uuid.UUID) (*dto.AdvertCard, error)
+	GetById(ctx context.Context, advertId, userId uuid.UUID) (*dto.AdvertCard, error)
 
 	// GetSavedByUserId возвращает массив объявлений, которые находятся в сохраненных
-	GetSavedByUserId(userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+	GetSavedByUserId(ctx context.Context, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
 
 	// Add добавляет объявление
 	// Возможные ошибки:
 	// ErrAdvertBadRequest - некорректные данные для создания объявления
 	// ErrAdvertAlreadyExists - объявление уже существует
-	Add(advert *dto.AdvertRequest, userId uuid.UUID) (*dto.Advert, error)
+	Add(ctx context.Context, advert *dto.AdvertRequest, userId uuid.UUID) (*dto.Advert, error)
 
 	// Update обновляет объявление
 	// Возможные ошибки:
 	// ErrAdvertBadRequest - некорректные данные для обновления объявления
 	// ErrAdvertNotFound - объявление не найдено
 	// ErrForbidden - нет прав на обновление объявления
-	Update(advert *dto.AdvertRequest, userId, advertId uuid.UUID) error
+	// ErrAdvertReserved - объявление зарезервировано покупкой
+	// ErrAdvertNotPublished - черновик публикуется только через Publish
+	// ErrAdvertStatusChanged - статус объявления изменился во время обновления
+	// Опубликованное объявление сохраняет статус, черновик можно только запланировать или вернуть в черновики
+	Update(ctx context.Context, advert *dto.AdvertRequest, userId, advertId uuid.UUID) error
 
 	// UpdateStatus обновляет статус объявления
 	// Возможные ошибки:
 	// ErrAdvertBadRequest - некорректные данные для обновления статуса объявления
 	// ErrAdvertNotFound - объявление не найдено
 	// ErrForbidden - нет прав на обновление статуса объявления
-	UpdateStatus(advertId, userId uuid.UUID, status dto.AdvertStatus) error
+	// ErrAdvertNotPublished - черновик нужно сначала опубликовать
+	UpdateStatus(ctx context.Context, advertId, userId uuid.UUID, status dto.AdvertStatus) error
 
 	// DeleteById удаляет объявление по Id
 	// Возможные ошибки:
 	// ErrAdvertNotFound - объявление не найдено
 	// ErrForbidden - нет прав на удаление объявления
-	DeleteById(advertId uuid.UUID, userId uuid.UUID) error
+	DeleteById(ctx context.Context, advertId uuid.UUID, userId uuid.UUID) error
 
 	// GetByCategoryId возвращает массив объявлений по categoryId
-	GetByCategoryId(categoryId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+	GetByCategoryId(ctx context.Context, categoryId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
 
 	// UploadImage загружает изображение в объявление
 	// Возможные ошибки:
 	// ErrAdvertNotFound - объявление не найдено
 	// ErrForbidden - нет прав на загрузку изображения
-	UploadImage(advertId uuid.UUID, imageId uuid.UUID, userId uuid.UUID) error
+	// ErrAdvertImageOnModeration - изображение отправлено на ручную проверку и пока не может быть показано
+	UploadImage(ctx context.Context, advertId uuid.UUID, imageId uuid.UUID, userId uuid.UUID) error
 
 	// AddToSaved добавляет объявление в сохраненные
-	AddToSaved(advertId, userId uuid.UUID) error
+	AddToSaved(ctx context.Context, advertId, userId uuid.UUID) error
 
-	// AddViewed добавляет просмотренное объявление
-	AddViewed(advertId, userId uuid.UUID) error
+	// AddViewed учитывает просмотр объявления пользователем или анонимным посетителем visitorId.
+	// Повторные просмотры одного зрителя в пределах окна дедупликации не засчитываются
+	AddViewed(ctx context.Context, advertId, userId uuid.UUID, visitorId string) error
 
 	// RemoveFromSaved удаляет объявление из сохраненных
-	RemoveFromSaved(advertId, userId uuid.UUID) error
+	RemoveFromSaved(ctx context.Context, advertId, userId uuid.UUID) error
 
 	// GetBySellerId возвращает массив объявлений по sellerId
-	GetBySellerId(userId, sellerId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+	GetBySellerId(ctx context.Context, userId, sellerId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
 
 	// Search ищет объявления по запросу
-	Search(query string, batchSize, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+	Search(ctx context.Context, query string, batchSize, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error)
+
+	// Renew продлевает срок публикации объявления и возвращает его в активные
+	// Возможные ошибки:
+	// ErrAdvertNotFound - объявление не найдено
+	// ErrForbidden - нет прав на продление объявления
+	// ErrAdvertNotRenewable - объявление зарезервировано покупкой или еще не опубликовано
+	Renew(ctx context.Context, advertId, userId uuid.UUID) error
+
+	// Bump поднимает объявление в начало ленты
+	// Возможные ошибки:
+	// ErrAdvertNotFound - объявление не найдено
+	// ErrForbidden - нет прав на поднятие объявления
+	// ErrAdvertBumpCooldown - объявление неактивно или поднималось слишком недавно
+	Bump(ctx context.Context, advertId, userId uuid.UUID) error
+
+	// ExpireOutdated снимает с публикации объявления с истекшим сроком
+	ExpireOutdated(ctx context.Context) error
+
+	// RemindExpiring напоминает продавцам об объявлениях, срок которых скоро истечет
+	RemindExpiring(ctx context.Context) error
+
+	// Publish публикует черновик сразу или планирует публикацию на момент publishAt
+	// Возможные ошибки:
+	// ErrAdvertNotFound - объявление не найдено
+	// ErrForbidden - нет прав на публикацию объявления
+	// ErrAdvertNotDraft - объявление уже опубликовано
+	// ErrAdvertIncomplete - объявление заполнено недостаточно для публикации
+	// ErrAdvertBadRequest - время публикации уже прошло
+	Publish(ctx context.Context, advertId, userId uuid.UUID, publishAt *time.Time) error
+
+	// PublishScheduled публикует объявления, время публикации которых наступило
+	PublishScheduled(ctx context.Context) error
+
+	// FlushViews переносит накопленные просмотры в Postgres
+	FlushViews(ctx context.Context) error
 }
+
+// AdvertExpiryNotifier доставляет продавцу напоминание о скором истечении срока объявления
+type AdvertExpiryNotifier interface {
+	NotifyExpiring(ctx context.Context, sellerId uuid.UUID, advertId uuid.UUID, title string, expiresAt time.Time) error
+}
+
+var (
+	ErrAdvertBumpCooldown      = errors.New("advert cannot be bumped yet")
+	ErrAdvertNotDraft          = errors.New("advert is already published")
+	ErrAdvertNotPublished      = errors.New("advert is a draft, publish it first")
+	ErrAdvertNotRenewable      = errors.New("only active or inactive adverts can be renewed")
+	ErrAdvertImageOnModeration = errors.New("image is held for manual moderation")
+	ErrAdvertReserved          = errors.New("advert is reserved by a purchase and cannot be edited")
+	ErrAdvertStatusChanged     = errors.New("advert status has changed, reload it and try again")
+)
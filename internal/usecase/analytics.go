@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type AnalyticsUseCase interface {
+	// GetAdvertAnalytics возвращает дневную статистику, воронку и сравнение с категорией
+	// для объявления продавца за период [from, to]
+	// Возможные ошибки:
+	// ErrAnalyticsInvalidRange - некорректный или слишком длинный период
+	// ErrAdvertNotFound - объявление не найдено
+	// ErrAnalyticsForbidden - объявление принадлежит другому продавцу
+	GetAdvertAnalytics(ctx context.Context, advertId, userId uuid.UUID, from, to time.Time) (*dto.AdvertAnalytics, error)
+
+	// GetSellerAnalytics возвращает суммарную статистику по всем объявлениям продавца за период [from, to]
+	// Возможные ошибки:
+	// ErrAnalyticsInvalidRange - некорректный или слишком длинный период
+	GetSellerAnalytics(ctx context.Context, userId uuid.UUID, from, to time.Time) (*dto.SellerAnalytics, error)
+
+	// Rollup пересчитывает дневные агрегаты за последние дни
+	Rollup(ctx context.Context) error
+}
+
+var (
+	ErrAnalyticsInvalidRange = errors.New("invalid analytics date range")
+	ErrAnalyticsForbidden    = errors.New("analytics of another seller's advert is forbidden")
+)
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 
 	"github.com/google/uuid"
@@ -8,9 +9,9 @@ import (
 
 type Auth interface {
 	// Logout удаляет сессию
-	Logout(session string) error
-	CreateSession(userId uuid.UUID) (string, error)
-	GetUserIdBySession(session string) (uuid.UUID, error)
+	Logout(ctx context.Context, session string) error
+	CreateSession(ctx context.Context, userId uuid.UUID) (string, error)
+	GetUserIdBySession(ctx context.Context, session string) (uuid.UUID, error)
 }
 
 var (
@@ -1,19 +1,49 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/google/uuid"
 )
 
 type Cart interface {
 	// AddAdvert добавляет товар в корзину юзера по его ID
-	AddAdvert(userID uuid.UUID, AdvertID uuid.UUID) error
+	// Возможные ошибки:
+	// ErrCartOwnAdvert - объявление самого пользователя
+	// ErrCartAdvertUnavailable - объявление не активно или уже зарезервировано
+	AddAdvert(ctx context.Context, userID uuid.UUID, AdvertID uuid.UUID) error
 	// GetByID возвращает корзину по ID корзины
-	GetById(cartID uuid.UUID) (dto.Cart, error)
+	GetById(ctx context.Context, cartID uuid.UUID) (dto.Cart, error)
 	// GetByUserID возвращает корзину по ID юзера
-	GetByUserId(userID uuid.UUID) (dto.Cart, error)
+	GetByUserId(ctx context.Context, userID uuid.UUID) (dto.Cart, error)
 	// DeleteAdvert удаляет товар из корзины по ID корзины и ID товара
-	DeleteAdvert(cartID uuid.UUID, AdvertID uuid.UUID) error
+	DeleteAdvert(ctx context.Context, cartID uuid.UUID, AdvertID uuid.UUID) error
 	// CheckExists проверяет, существует ли корзина для пользователя
-	CheckExists(userID uuid.UUID) (uuid.UUID, error)
+	CheckExists(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	// MergeAdverts переносит объявления гостевой корзины в корзину пользователя и создает ее,
+	// если корзины нет. Объявления, которые уже лежат в корзине или которые нельзя купить,
+	// пропускаются и перечисляются в результате
+	MergeAdverts(ctx context.Context, userID uuid.UUID, advertIDs []uuid.UUID) (dto.CartMergeResult, error)
+}
+
+var (
+	ErrCartOwnAdvert         = errors.New("own advert cannot be added to the cart")
+	ErrCartAdvertUnavailable = errors.New("advert is not available for purchase")
+)
+
+// UnavailableAdvertsError - корзину нельзя оформить: часть объявлений стала недоступна
+// после добавления. Для errors.Is совпадает с ErrCartAdvertUnavailable
+type UnavailableAdvertsError struct {
+	Adverts []dto.UnavailableAdvert
+}
+
+func (e *UnavailableAdvertsError) Error() string {
+	return fmt.Sprintf("%d adverts of the cart are not available for purchase", len(e.Adverts))
+}
+
+func (e *UnavailableAdvertsError) Is(target error) bool {
+	return target == ErrCartAdvertUnavailable
 }
@@ -1,10 +1,12 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 )
 
 type CategoryUseCase interface {
 	// Get возвращает все категории
-	Get() ([]*entity.Category, error)
+	Get(ctx context.Context) ([]*entity.Category, error)
 }
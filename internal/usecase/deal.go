@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type SafeDealUseCase interface {
+	// Create оформляет покупку безопасной сделкой и создает платеж, который только блокирует
+	// средства покупателя. Повторный вызов возвращает уже созданную сделку
+	// Возможные ошибки:
+	// ErrPurchaseNotFound - покупка не найдена
+	// ErrDealForbidden - покупка оформлена другим пользователем
+	// ErrDealNotAllowed - покупка оформлена без безопасной сделки, оплачивается не картой
+	// или уже не ждет оплаты
+	// ErrDealMultipleSellers - в корзине объявления разных продавцов
+	Create(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.DealResponse, error)
+
+	// GetByPurchaseId возвращает сделку покупателю, продавцу или администратору
+	// Возможные ошибки:
+	// ErrDealNotFound - покупка оформлена без безопасной сделки
+	// ErrDealForbidden - пользователь не участвует в сделке
+	GetByPurchaseId(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.DealResponse, error)
+
+	// Ship отмечает отправку товара продавцом и запускает срок подтверждения получения
+	// Возможные ошибки:
+	// ErrDealForbidden - пользователь не продавец по сделке
+	// ErrDealInvalidState - средства не заблокированы или товар уже отправлен
+	Ship(ctx context.Context, dealID, userID uuid.UUID) (*dto.DealResponse, error)
+
+	// Confirm подтверждает получение товара покупателем и списывает средства в пользу продавца
+	// Возможные ошибки:
+	// ErrDealForbidden - пользователь не покупатель по сделке
+	// ErrDealInvalidState - средства не заблокированы или по сделке открыт спор
+	Confirm(ctx context.Context, dealID, userID uuid.UUID) (*dto.DealResponse, error)
+
+	// OpenDispute открывает спор по сделке. Средства остаются заблокированными, сроки сделки
+	// останавливаются до решения администратора
+	// Возможные ошибки:
+	// ErrDealForbidden - пользователь не участвует в сделке
+	// ErrDealInvalidState - средства не заблокированы или спор уже открыт
+	// ErrDealBadRequest - причина спора пустая или слишком длинная
+	OpenDispute(ctx context.Context, dealID, userID uuid.UUID, reason string) (*dto.DealResponse, error)
+
+	// GetDisputes возвращает открытые споры администратору
+	// Возможные ошибки:
+	// ErrDealForbidden - пользователь не администратор
+	GetDisputes(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*dto.DealResponse, error)
+
+	// Resolve закрывает спор: release списывает средства в пользу продавца, refund возвращает их покупателю
+	// Возможные ошибки:
+	// ErrDealForbidden - пользователь не администратор
+	// ErrDealInvalidState - по сделке нет открытого спора
+	// ErrDealBadRequest - неизвестное решение
+	Resolve(ctx context.Context, dealID, userID uuid.UUID, resolution entity.DealResolution) (*dto.DealResponse, error)
+
+	// ProcessExpired возвращает средства по сделкам, где продавец не отправил товар в срок,
+	// и списывает их по сделкам, где покупатель не подтвердил получение в срок
+	ProcessExpired(ctx context.Context) error
+}
+
+var (
+	ErrDealForbidden       = errors.New("user is not a party to the safe deal")
+	ErrDealNotAllowed      = errors.New("purchase cannot be paid with a safe deal")
+	ErrDealMultipleSellers = errors.New("safe deal is available only for adverts of one seller")
+	ErrDealInvalidState    = errors.New("operation is not allowed in the current safe deal status")
+	ErrDealBadRequest      = errors.New("invalid safe deal request")
+)
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type DeliveryUseCase interface {
+	// GetOptions возвращает способы доставки объявления
+	// Возможные ошибки:
+	// ErrDeliveryAdvertNotFound - объявление не найдено
+	GetOptions(ctx context.Context, advertID uuid.UUID) ([]*dto.DeliveryOption, error)
+
+	// SetOptions заменяет способы доставки объявления продавца-пользователя userID.
+	// Пустой список отключает доставку
+	// Возможные ошибки:
+	// ErrDeliveryAdvertNotFound - объявление не найдено
+	// ErrDeliveryForbidden - объявление другого продавца
+	// ErrDeliveryBadRequest - неизвестный или повторяющийся способ, некорректный срок
+	SetOptions(ctx context.Context, advertID, userID uuid.UUID, options []*dto.DeliveryOption) ([]*dto.DeliveryOption, error)
+
+	// GetQuotes возвращает стоимость доставки корзины каждым способом, который есть у всех ее объявлений
+	// Возможные ошибки:
+	// ErrDeliveryForbidden - корзина другого пользователя
+	GetQuotes(ctx context.Context, cartID, userID uuid.UUID) ([]*dto.DeliveryQuote, error)
+
+	// Quote возвращает стоимость доставки корзины способом optionType
+	// Возможные ошибки:
+	// ErrDeliveryForbidden - корзина другого пользователя
+	// ErrDeliveryBadRequest - неизвестный способ доставки или пустая корзина
+	// ErrDeliveryOptionUnavailable - способ доставки есть не у всех объявлений корзины
+	Quote(ctx context.Context, cartID, userID uuid.UUID, optionType entity.DeliveryOptionType) (*dto.DeliveryQuote, error)
+
+	// CreateShipments создает по отправлению на каждого продавца покупки со стоимостью доставки
+	// способом optionType. Повторный вызов возвращает уже созданные отправления
+	// Возможные ошибки:
+	// ErrPurchaseNotFound - покупка не найдена
+	// ErrDeliveryForbidden - покупка оформлена другим пользователем
+	// ErrDeliveryBadRequest - покупка оформлена с самовывозом или способ доставки неизвестен
+	// ErrDeliveryOptionUnavailable - способ доставки есть не у всех объявлений покупки
+	CreateShipments(ctx context.Context, purchaseID, userID uuid.UUID, optionType entity.DeliveryOptionType) ([]*dto.ShipmentResponse, error)
+
+	// GetShipments возвращает отправления покупки с историей: покупателю - все, продавцу - его отправления
+	// Возможные ошибки:
+	// ErrPurchaseNotFound - покупка не найдена
+	// ErrDeliveryForbidden - пользователь не участвует в покупке
+	GetShipments(ctx context.Context, purchaseID, userID uuid.UUID) ([]*dto.ShipmentResponse, error)
+
+	// SetTracking сохраняет трек-номер отправления продавца-пользователя userID и передает
+	// отправление перевозчику на отслеживание
+	// Возможные ошибки:
+	// ErrShipmentNotFound - отправление не найдено
+	// ErrDeliveryForbidden - отправление другого продавца
+	// ErrDeliveryBadRequest - перевозчик не принимает трек-номер
+	// ErrShipmentInvalidState - трек-номер уже указан
+	SetTracking(ctx context.Context, shipmentID, userID uuid.UUID, trackingNumber string) (*dto.ShipmentResponse, error)
+
+	// RefreshTracking запрашивает у перевозчика статусы отправлений в пути и сохраняет их историю
+	RefreshTracking(ctx context.Context) error
+}
+
+var (
+	ErrDeliveryAdvertNotFound    = errors.New("advert not found")
+	ErrDeliveryForbidden         = errors.New("user is not allowed to manage the delivery")
+	ErrDeliveryBadRequest        = errors.New("invalid delivery request")
+	ErrDeliveryOptionUnavailable = errors.New("delivery option is not available for every advert")
+	ErrShipmentInvalidState      = errors.New("operation is not allowed in the current shipment status")
+)
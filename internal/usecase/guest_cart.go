@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type GuestCart interface {
+	// AddAdvert добавляет объявление в корзину посетителя без входа
+	// Возможные ошибки:
+	// ErrAdvertNotFound - объявление не найдено
+	// ErrCartAdvertUnavailable - объявление не активно или уже зарезервировано
+	// ErrGuestCartFull - в корзине уже максимальное число объявлений
+	AddAdvert(ctx context.Context, guestID, advertID uuid.UUID) error
+
+	// GetCart возвращает объявления гостевой корзины
+	GetCart(ctx context.Context, guestID uuid.UUID) (dto.GuestCart, error)
+
+	// DeleteAdvert удаляет объявление из гостевой корзины
+	// Возможные ошибки:
+	// ErrGuestCartAdvertNotFound - объявления нет в корзине
+	DeleteAdvert(ctx context.Context, guestID, advertID uuid.UUID) error
+
+	// GetAdvertIDs возвращает объявления гостевой корзины для переноса в корзину пользователя
+	GetAdvertIDs(ctx context.Context, guestID uuid.UUID) ([]uuid.UUID, error)
+
+	// Clear удаляет гостевую корзину после переноса
+	Clear(ctx context.Context, guestID uuid.UUID) error
+}
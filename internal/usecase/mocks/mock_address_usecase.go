@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/address.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAddressUseCase is a mock of AddressUseCase interface.
+type MockAddressUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAddressUseCaseMockRecorder
+}
+
+// MockAddressUseCaseMockRecorder is the mock recorder for MockAddressUseCase.
+type MockAddressUseCaseMockRecorder struct {
+	mock *MockAddressUseCase
+}
+
+// NewMockAddressUseCase creates a new mock instance.
+func NewMockAddressUseCase(ctrl *gomock.Controller) *MockAddressUseCase {
+	mock := &MockAddressUseCase{ctrl: ctrl}
+	mock.recorder = &MockAddressUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAddressUseCase) EXPECT() *MockAddressUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AddAddress mocks base method.
+func (m *MockAddressUseCase) AddAddress(ctx context.Context, userID uuid.UUID, req dto.AddressRequest) (*dto.AddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAddress", ctx, userID, req)
+	ret0, _ := ret[0].(*dto.AddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAddress indicates an expected call of AddAddress.
+func (mr *MockAddressUseCaseMockRecorder) AddAddress(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAddress", reflect.TypeOf((*MockAddressUseCase)(nil).AddAddress), ctx, userID, req)
+}
+
+// DeleteAddress mocks base method.
+func (m *MockAddressUseCase) DeleteAddress(ctx context.Context, addressID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAddress", ctx, addressID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAddress indicates an expected call of DeleteAddress.
+func (mr *MockAddressUseCaseMockRecorder) DeleteAddress(ctx, addressID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAddress", reflect.TypeOf((*MockAddressUseCase)(nil).DeleteAddress), ctx, addressID, userID)
+}
+
+// GetAddresses mocks base method.
+func (m *MockAddressUseCase) GetAddresses(ctx context.Context, userID uuid.UUID) ([]*dto.AddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAddresses", ctx, userID)
+	ret0, _ := ret[0].([]*dto.AddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAddresses indicates an expected call of GetAddresses.
+func (mr *MockAddressUseCaseMockRecorder) GetAddresses(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAddresses", reflect.TypeOf((*MockAddressUseCase)(nil).GetAddresses), ctx, userID)
+}
+
+// GetPurchaseAddresses mocks base method.
+func (m *MockAddressUseCase) GetPurchaseAddresses(ctx context.Context, purchaseIDs []uuid.UUID) (map[uuid.UUID]*dto.PurchaseAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseAddresses", ctx, purchaseIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]*dto.PurchaseAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseAddresses indicates an expected call of GetPurchaseAddresses.
+func (mr *MockAddressUseCaseMockRecorder) GetPurchaseAddresses(ctx, purchaseIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseAddresses", reflect.TypeOf((*MockAddressUseCase)(nil).GetPurchaseAddresses), ctx, purchaseIDs)
+}
+
+// ResolveCheckout mocks base method.
+func (m *MockAddressUseCase) ResolveCheckout(ctx context.Context, userID uuid.UUID, addressID *uuid.UUID, details *dto.AddressDetails) (*dto.PurchaseAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveCheckout", ctx, userID, addressID, details)
+	ret0, _ := ret[0].(*dto.PurchaseAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveCheckout indicates an expected call of ResolveCheckout.
+func (mr *MockAddressUseCaseMockRecorder) ResolveCheckout(ctx, userID, addressID, details interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCheckout", reflect.TypeOf((*MockAddressUseCase)(nil).ResolveCheckout), ctx, userID, addressID, details)
+}
+
+// SetDefault mocks base method.
+func (m *MockAddressUseCase) SetDefault(ctx context.Context, addressID, userID uuid.UUID) (*dto.AddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDefault", ctx, addressID, userID)
+	ret0, _ := ret[0].(*dto.AddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDefault indicates an expected call of SetDefault.
+func (mr *MockAddressUseCaseMockRecorder) SetDefault(ctx, addressID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDefault", reflect.TypeOf((*MockAddressUseCase)(nil).SetDefault), ctx, addressID, userID)
+}
+
+// UpdateAddress mocks base method.
+func (m *MockAddressUseCase) UpdateAddress(ctx context.Context, addressID, userID uuid.UUID, req dto.AddressRequest) (*dto.AddressResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddress", ctx, addressID, userID, req)
+	ret0, _ := ret[0].(*dto.AddressResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddress indicates an expected call of UpdateAddress.
+func (mr *MockAddressUseCaseMockRecorder) UpdateAddress(ctx, addressID, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddress", reflect.TypeOf((*MockAddressUseCase)(nil).UpdateAddress), ctx, addressID, userID, req)
+}
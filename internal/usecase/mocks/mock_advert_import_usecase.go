@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/advert_import.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAdvertImportUseCase is a mock of AdvertImportUseCase interface.
+type MockAdvertImportUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdvertImportUseCaseMockRecorder
+}
+
+// MockAdvertImportUseCaseMockRecorder is the mock recorder for MockAdvertImportUseCase.
+type MockAdvertImportUseCaseMockRecorder struct {
+	mock *MockAdvertImportUseCase
+}
+
+// NewMockAdvertImportUseCase creates a new mock instance.
+func NewMockAdvertImportUseCase(ctrl *gomock.Controller) *MockAdvertImportUseCase {
+	mock := &MockAdvertImportUseCase{ctrl: ctrl}
+	mock.recorder = &MockAdvertImportUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdvertImportUseCase) EXPECT() *MockAdvertImportUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockAdvertImportUseCase) Export(ctx context.Context, userId uuid.UUID) ([]dto.AdvertImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, userId)
+	ret0, _ := ret[0].([]dto.AdvertImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockAdvertImportUseCaseMockRecorder) Export(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockAdvertImportUseCase)(nil).Export), ctx, userId)
+}
+
+// Import mocks base method.
+func (m *MockAdvertImportUseCase) Import(ctx context.Context, userId uuid.UUID, rows []dto.AdvertImportRow, dryRun bool) (*dto.AdvertImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, userId, rows, dryRun)
+	ret0, _ := ret[0].(*dto.AdvertImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockAdvertImportUseCaseMockRecorder) Import(ctx, userId, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockAdvertImportUseCase)(nil).Import), ctx, userId, rows, dryRun)
+}
@@ -5,7 +5,9 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	gomock "github.com/golang/mock/gomock"
@@ -36,234 +38,369 @@ func (m *MockAdvertUseCase) EXPECT() *MockAdvertUseCaseMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockAdvertUseCase) Add(advert *dto.AdvertRequest, userId uuid.UUID) (*dto.Advert, error) {
+func (m *MockAdvertUseCase) Add(ctx context.Context, advert *dto.AdvertRequest, userId uuid.UUID) (*dto.Advert, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", advert, userId)
+	ret := m.ctrl.Call(m, "Add", ctx, advert, userId)
 	ret0, _ := ret[0].(*dto.Advert)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockAdvertUseCaseMockRecorder) Add(advert, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) Add(ctx, advert, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAdvertUseCase)(nil).Add), advert, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockAdvertUseCase)(nil).Add), ctx, advert, userId)
 }
 
 // AddToSaved mocks base method.
-func (m *MockAdvertUseCase) AddToSaved(advertId, userId uuid.UUID) error {
+func (m *MockAdvertUseCase) AddToSaved(ctx context.Context, advertId, userId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddToSaved", advertId, userId)
+	ret := m.ctrl.Call(m, "AddToSaved", ctx, advertId, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddToSaved indicates an expected call of AddToSaved.
-func (mr *MockAdvertUseCaseMockRecorder) AddToSaved(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) AddToSaved(ctx, advertId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToSaved", reflect.TypeOf((*MockAdvertUseCase)(nil).AddToSaved), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToSaved", reflect.TypeOf((*MockAdvertUseCase)(nil).AddToSaved), ctx, advertId, userId)
 }
 
 // AddViewed mocks base method.
-func (m *MockAdvertUseCase) AddViewed(advertId, userId uuid.UUID) error {
+func (m *MockAdvertUseCase) AddViewed(ctx context.Context, advertId, userId uuid.UUID, visitorId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddViewed", advertId, userId)
+	ret := m.ctrl.Call(m, "AddViewed", ctx, advertId, userId, visitorId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddViewed indicates an expected call of AddViewed.
-func (mr *MockAdvertUseCaseMockRecorder) AddViewed(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) AddViewed(ctx, advertId, userId, visitorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViewed", reflect.TypeOf((*MockAdvertUseCase)(nil).AddViewed), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddViewed", reflect.TypeOf((*MockAdvertUseCase)(nil).AddViewed), ctx, advertId, userId, visitorId)
+}
+
+// Bump mocks base method.
+func (m *MockAdvertUseCase) Bump(ctx context.Context, advertId, userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Bump", ctx, advertId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Bump indicates an expected call of Bump.
+func (mr *MockAdvertUseCaseMockRecorder) Bump(ctx, advertId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bump", reflect.TypeOf((*MockAdvertUseCase)(nil).Bump), ctx, advertId, userId)
 }
 
 // DeleteById mocks base method.
-func (m *MockAdvertUseCase) DeleteById(advertId, userId uuid.UUID) error {
+func (m *MockAdvertUseCase) DeleteById(ctx context.Context, advertId, userId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteById", advertId, userId)
+	ret := m.ctrl.Call(m, "DeleteById", ctx, advertId, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteById indicates an expected call of DeleteById.
-func (mr *MockAdvertUseCaseMockRecorder) DeleteById(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) DeleteById(ctx, advertId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockAdvertUseCase)(nil).DeleteById), ctx, advertId, userId)
+}
+
+// ExpireOutdated mocks base method.
+func (m *MockAdvertUseCase) ExpireOutdated(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireOutdated", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireOutdated indicates an expected call of ExpireOutdated.
+func (mr *MockAdvertUseCaseMockRecorder) ExpireOutdated(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireOutdated", reflect.TypeOf((*MockAdvertUseCase)(nil).ExpireOutdated), ctx)
+}
+
+// FlushViews mocks base method.
+func (m *MockAdvertUseCase) FlushViews(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushViews", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushViews indicates an expected call of FlushViews.
+func (mr *MockAdvertUseCaseMockRecorder) FlushViews(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteById", reflect.TypeOf((*MockAdvertUseCase)(nil).DeleteById), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushViews", reflect.TypeOf((*MockAdvertUseCase)(nil).FlushViews), ctx)
 }
 
 // Get mocks base method.
-func (m *MockAdvertUseCase) Get(limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) Get(ctx context.Context, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", limit, offset, userId)
+	ret := m.ctrl.Call(m, "Get", ctx, limit, offset, userId)
 	ret0, _ := ret[0].([]*dto.PreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockAdvertUseCaseMockRecorder) Get(limit, offset, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) Get(ctx, limit, offset, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAdvertUseCase)(nil).Get), limit, offset, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAdvertUseCase)(nil).Get), ctx, limit, offset, userId)
 }
 
 // GetByCartId mocks base method.
-func (m *MockAdvertUseCase) GetByCartId(cartId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) GetByCartId(ctx context.Context, cartId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCartId", cartId, userId)
+	ret := m.ctrl.Call(m, "GetByCartId", ctx, cartId, userId)
 	ret0, _ := ret[0].([]*dto.PreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCartId indicates an expected call of GetByCartId.
-func (mr *MockAdvertUseCaseMockRecorder) GetByCartId(cartId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) GetByCartId(ctx, cartId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCartId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetByCartId), cartId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCartId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetByCartId), ctx, cartId, userId)
 }
 
 // GetByCategoryId mocks base method.
-func (m *MockAdvertUseCase) GetByCategoryId(categoryId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) GetByCategoryId(ctx context.Context, categoryId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCategoryId", categoryId, userId)
+	ret := m.ctrl.Call(m, "GetByCategoryId", ctx, categoryId, userId)
 	ret0, _ := ret[0].([]*dto.PreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByCategoryId indicates an expected call of GetByCategoryId.
-func (mr *MockAdvertUseCaseMockRecorder) GetByCategoryId(categoryId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) GetByCategoryId(ctx, categoryId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategoryId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetByCategoryId), categoryId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategoryId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetByCategoryId), ctx, categoryId, userId)
 }
 
 // GetById mocks base method.
-func (m *MockAdvertUseCase) GetById(advertId, userId uuid.UUID) (*dto.AdvertCard, error) {
+func (m *MockAdvertUseCase) GetById(ctx context.Context, advertId, userId uuid.UUID) (*dto.AdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", advertId, userId)
+	ret := m.ctrl.Call(m, "GetById", ctx, advertId, userId)
 	ret0, _ := ret[0].(*dto.AdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockAdvertUseCaseMockRecorder) GetById(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) GetById(ctx, advertId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAdvertUseCase)(nil).GetById), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockAdvertUseCase)(nil).GetById), ctx, advertId, userId)
 }
 
 // GetBySellerId mocks base method.
-func (m *MockAdvertUseCase) GetBySellerId(userId, sellerId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) GetBySellerId(ctx context.Context, userId, sellerId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBySellerId", userId, sellerId)
+	ret := m.ctrl.Call(m, "GetBySellerId", ctx, userId, sellerId)
 	ret0, _ := ret[0].([]*dto.PreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBySellerId indicates an expected call of GetBySellerId.
-func (mr *MockAdvertUseCaseMockRecorder) GetBySellerId(userId, sellerId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) GetBySellerId(ctx, userId, sellerId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySellerId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetBySellerId), userId, sellerId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySellerId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetBySellerId), ctx, userId, sellerId)
 }
 
 // GetByUserId mocks base method.
-func (m *MockAdvertUseCase) GetByUserId(userId uuid.UUID) ([]*dto.MyPreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) GetByUserId(ctx context.Context, userId uuid.UUID) ([]*dto.MyPreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", userId)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userId)
 	ret0, _ := ret[0].([]*dto.MyPreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockAdvertUseCaseMockRecorder) GetByUserId(userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) GetByUserId(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetByUserId), ctx, userId)
 }
 
 // GetSavedByUserId mocks base method.
-func (m *MockAdvertUseCase) GetSavedByUserId(userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) GetSavedByUserId(ctx context.Context, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSavedByUserId", userId)
+	ret := m.ctrl.Call(m, "GetSavedByUserId", ctx, userId)
 	ret0, _ := ret[0].([]*dto.PreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSavedByUserId indicates an expected call of GetSavedByUserId.
-func (mr *MockAdvertUseCaseMockRecorder) GetSavedByUserId(userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) GetSavedByUserId(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedByUserId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetSavedByUserId), ctx, userId)
+}
+
+// Publish mocks base method.
+func (m *MockAdvertUseCase) Publish(ctx context.Context, advertId, userId uuid.UUID, publishAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, advertId, userId, publishAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockAdvertUseCaseMockRecorder) Publish(ctx, advertId, userId, publishAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockAdvertUseCase)(nil).Publish), ctx, advertId, userId, publishAt)
+}
+
+// PublishScheduled mocks base method.
+func (m *MockAdvertUseCase) PublishScheduled(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishScheduled", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishScheduled indicates an expected call of PublishScheduled.
+func (mr *MockAdvertUseCaseMockRecorder) PublishScheduled(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishScheduled", reflect.TypeOf((*MockAdvertUseCase)(nil).PublishScheduled), ctx)
+}
+
+// RemindExpiring mocks base method.
+func (m *MockAdvertUseCase) RemindExpiring(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemindExpiring", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemindExpiring indicates an expected call of RemindExpiring.
+func (mr *MockAdvertUseCaseMockRecorder) RemindExpiring(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedByUserId", reflect.TypeOf((*MockAdvertUseCase)(nil).GetSavedByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemindExpiring", reflect.TypeOf((*MockAdvertUseCase)(nil).RemindExpiring), ctx)
 }
 
 // RemoveFromSaved mocks base method.
-func (m *MockAdvertUseCase) RemoveFromSaved(advertId, userId uuid.UUID) error {
+func (m *MockAdvertUseCase) RemoveFromSaved(ctx context.Context, advertId, userId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveFromSaved", advertId, userId)
+	ret := m.ctrl.Call(m, "RemoveFromSaved", ctx, advertId, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveFromSaved indicates an expected call of RemoveFromSaved.
-func (mr *MockAdvertUseCaseMockRecorder) RemoveFromSaved(advertId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) RemoveFromSaved(ctx, advertId, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromSaved", reflect.TypeOf((*MockAdvertUseCase)(nil).RemoveFromSaved), advertId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromSaved", reflect.TypeOf((*MockAdvertUseCase)(nil).RemoveFromSaved), ctx, advertId, userId)
+}
+
+// Renew mocks base method.
+func (m *MockAdvertUseCase) Renew(ctx context.Context, advertId, userId uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, advertId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockAdvertUseCaseMockRecorder) Renew(ctx, advertId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockAdvertUseCase)(nil).Renew), ctx, advertId, userId)
 }
 
 // Search mocks base method.
-func (m *MockAdvertUseCase) Search(query string, batchSize, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+func (m *MockAdvertUseCase) Search(ctx context.Context, query string, batchSize, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Search", query, batchSize, limit, offset, userId)
+	ret := m.ctrl.Call(m, "Search", ctx, query, batchSize, limit, offset, userId)
 	ret0, _ := ret[0].([]*dto.PreviewAdvertCard)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Search indicates an expected call of Search.
-func (mr *MockAdvertUseCaseMockRecorder) Search(query, batchSize, limit, offset, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) Search(ctx, query, batchSize, limit, offset, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAdvertUseCase)(nil).Search), query, batchSize, limit, offset, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockAdvertUseCase)(nil).Search), ctx, query, batchSize, limit, offset, userId)
 }
 
 // Update mocks base method.
-func (m *MockAdvertUseCase) Update(advert *dto.AdvertRequest, userId, advertId uuid.UUID) error {
+func (m *MockAdvertUseCase) Update(ctx context.Context, advert *dto.AdvertRequest, userId, advertId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", advert, userId, advertId)
+	ret := m.ctrl.Call(m, "Update", ctx, advert, userId, advertId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockAdvertUseCaseMockRecorder) Update(advert, userId, advertId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) Update(ctx, advert, userId, advertId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdvertUseCase)(nil).Update), advert, userId, advertId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAdvertUseCase)(nil).Update), ctx, advert, userId, advertId)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockAdvertUseCase) UpdateStatus(advertId, userId uuid.UUID, status dto.AdvertStatus) error {
+func (m *MockAdvertUseCase) UpdateStatus(ctx context.Context, advertId, userId uuid.UUID, status dto.AdvertStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", advertId, userId, status)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, advertId, userId, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockAdvertUseCaseMockRecorder) UpdateStatus(advertId, userId, status interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) UpdateStatus(ctx, advertId, userId, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAdvertUseCase)(nil).UpdateStatus), advertId, userId, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockAdvertUseCase)(nil).UpdateStatus), ctx, advertId, userId, status)
 }
 
 // UploadImage mocks base method.
-func (m *MockAdvertUseCase) UploadImage(advertId, imageId, userId uuid.UUID) error {
+func (m *MockAdvertUseCase) UploadImage(ctx context.Context, advertId, imageId, userId uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadImage", advertId, imageId, userId)
+	ret := m.ctrl.Call(m, "UploadImage", ctx, advertId, imageId, userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UploadImage indicates an expected call of UploadImage.
-func (mr *MockAdvertUseCaseMockRecorder) UploadImage(advertId, imageId, userId interface{}) *gomock.Call {
+func (mr *MockAdvertUseCaseMockRecorder) UploadImage(ctx, advertId, imageId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockAdvertUseCase)(nil).UploadImage), ctx, advertId, imageId, userId)
+}
+
+// MockAdvertExpiryNotifier is a mock of AdvertExpiryNotifier interface.
+type MockAdvertExpiryNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdvertExpiryNotifierMockRecorder
+}
+
+// MockAdvertExpiryNotifierMockRecorder is the mock recorder for MockAdvertExpiryNotifier.
+type MockAdvertExpiryNotifierMockRecorder struct {
+	mock *MockAdvertExpiryNotifier
+}
+
+// NewMockAdvertExpiryNotifier creates a new mock instance.
+func NewMockAdvertExpiryNotifier(ctrl *gomock.Controller) *MockAdvertExpiryNotifier {
+	mock := &MockAdvertExpiryNotifier{ctrl: ctrl}
+	mock.recorder = &MockAdvertExpiryNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdvertExpiryNotifier) EXPECT() *MockAdvertExpiryNotifierMockRecorder {
+	return m.recorder
+}
+
+// NotifyExpiring mocks base method.
+func (m *MockAdvertExpiryNotifier) NotifyExpiring(ctx context.Context, sellerId, advertId uuid.UUID, title string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyExpiring", ctx, sellerId, advertId, title, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyExpiring indicates an expected call of NotifyExpiring.
+func (mr *MockAdvertExpiryNotifierMockRecorder) NotifyExpiring(ctx, sellerId, advertId, title, expiresAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockAdvertUseCase)(nil).UploadImage), advertId, imageId, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyExpiring", reflect.TypeOf((*MockAdvertExpiryNotifier)(nil).NotifyExpiring), ctx, sellerId, advertId, title, expiresAt)
 }
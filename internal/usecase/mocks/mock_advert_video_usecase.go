@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/advert_video.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAdvertVideoUseCase is a mock of AdvertVideoUseCase interface.
+type MockAdvertVideoUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdvertVideoUseCaseMockRecorder
+}
+
+// MockAdvertVideoUseCaseMockRecorder is the mock recorder for MockAdvertVideoUseCase.
+type MockAdvertVideoUseCaseMockRecorder struct {
+	mock *MockAdvertVideoUseCase
+}
+
+// NewMockAdvertVideoUseCase creates a new mock instance.
+func NewMockAdvertVideoUseCase(ctrl *gomock.Controller) *MockAdvertVideoUseCase {
+	mock := &MockAdvertVideoUseCase{ctrl: ctrl}
+	mock.recorder = &MockAdvertVideoUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdvertVideoUseCase) EXPECT() *MockAdvertVideoUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AddVideo mocks base method.
+func (m *MockAdvertVideoUseCase) AddVideo(ctx context.Context, advertID, videoID, userID uuid.UUID) (*dto.AdvertVideo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddVideo", ctx, advertID, videoID, userID)
+	ret0, _ := ret[0].(*dto.AdvertVideo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddVideo indicates an expected call of AddVideo.
+func (mr *MockAdvertVideoUseCaseMockRecorder) AddVideo(ctx, advertID, videoID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVideo", reflect.TypeOf((*MockAdvertVideoUseCase)(nil).AddVideo), ctx, advertID, videoID, userID)
+}
+
+// DeleteVideo mocks base method.
+func (m *MockAdvertVideoUseCase) DeleteVideo(ctx context.Context, advertID, videoID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVideo", ctx, advertID, videoID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVideo indicates an expected call of DeleteVideo.
+func (mr *MockAdvertVideoUseCaseMockRecorder) DeleteVideo(ctx, advertID, videoID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVideo", reflect.TypeOf((*MockAdvertVideoUseCase)(nil).DeleteVideo), ctx, advertID, videoID, userID)
+}
+
+// GetVideos mocks base method.
+func (m *MockAdvertVideoUseCase) GetVideos(ctx context.Context, advertID uuid.UUID) ([]*dto.AdvertVideo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVideos", ctx, advertID)
+	ret0, _ := ret[0].([]*dto.AdvertVideo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVideos indicates an expected call of GetVideos.
+func (mr *MockAdvertVideoUseCaseMockRecorder) GetVideos(ctx, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVideos", reflect.TypeOf((*MockAdvertVideoUseCase)(nil).GetVideos), ctx, advertID)
+}
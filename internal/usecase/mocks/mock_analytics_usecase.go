@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/analytics.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAnalyticsUseCase is a mock of AnalyticsUseCase interface.
+type MockAnalyticsUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsUseCaseMockRecorder
+}
+
+// MockAnalyticsUseCaseMockRecorder is the mock recorder for MockAnalyticsUseCase.
+type MockAnalyticsUseCaseMockRecorder struct {
+	mock *MockAnalyticsUseCase
+}
+
+// NewMockAnalyticsUseCase creates a new mock instance.
+func NewMockAnalyticsUseCase(ctrl *gomock.Controller) *MockAnalyticsUseCase {
+	mock := &MockAnalyticsUseCase{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsUseCase) EXPECT() *MockAnalyticsUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetAdvertAnalytics mocks base method.
+func (m *MockAnalyticsUseCase) GetAdvertAnalytics(ctx context.Context, advertId, userId uuid.UUID, from, to time.Time) (*dto.AdvertAnalytics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdvertAnalytics", ctx, advertId, userId, from, to)
+	ret0, _ := ret[0].(*dto.AdvertAnalytics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdvertAnalytics indicates an expected call of GetAdvertAnalytics.
+func (mr *MockAnalyticsUseCaseMockRecorder) GetAdvertAnalytics(ctx, advertId, userId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertAnalytics", reflect.TypeOf((*MockAnalyticsUseCase)(nil).GetAdvertAnalytics), ctx, advertId, userId, from, to)
+}
+
+// GetSellerAnalytics mocks base method.
+func (m *MockAnalyticsUseCase) GetSellerAnalytics(ctx context.Context, userId uuid.UUID, from, to time.Time) (*dto.SellerAnalytics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSellerAnalytics", ctx, userId, from, to)
+	ret0, _ := ret[0].(*dto.SellerAnalytics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSellerAnalytics indicates an expected call of GetSellerAnalytics.
+func (mr *MockAnalyticsUseCaseMockRecorder) GetSellerAnalytics(ctx, userId, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSellerAnalytics", reflect.TypeOf((*MockAnalyticsUseCase)(nil).GetSellerAnalytics), ctx, userId, from, to)
+}
+
+// Rollup mocks base method.
+func (m *MockAnalyticsUseCase) Rollup(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollup", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollup indicates an expected call of Rollup.
+func (mr *MockAnalyticsUseCaseMockRecorder) Rollup(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollup", reflect.TypeOf((*MockAnalyticsUseCase)(nil).Rollup), ctx)
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,45 +36,45 @@ func (m *MockAuth) EXPECT() *MockAuthMockRecorder {
 }
 
 // CreateSession mocks base method.
-func (m *MockAuth) CreateSession(userId uuid.UUID) (string, error) {
+func (m *MockAuth) CreateSession(ctx context.Context, userId uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", userId)
+	ret := m.ctrl.Call(m, "CreateSession", ctx, userId)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSession indicates an expected call of CreateSession.
-func (mr *MockAuthMockRecorder) CreateSession(userId interface{}) *gomock.Call {
+func (mr *MockAuthMockRecorder) CreateSession(ctx, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockAuth)(nil).CreateSession), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockAuth)(nil).CreateSession), ctx, userId)
 }
 
 // GetUserIdBySession mocks base method.
-func (m *MockAuth) GetUserIdBySession(session string) (uuid.UUID, error) {
+func (m *MockAuth) GetUserIdBySession(ctx context.Context, session string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserIdBySession", session)
+	ret := m.ctrl.Call(m, "GetUserIdBySession", ctx, session)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserIdBySession indicates an expected call of GetUserIdBySession.
-func (mr *MockAuthMockRecorder) GetUserIdBySession(session interface{}) *gomock.Call {
+func (mr *MockAuthMockRecorder) GetUserIdBySession(ctx, session interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdBySession", reflect.TypeOf((*MockAuth)(nil).GetUserIdBySession), session)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdBySession", reflect.TypeOf((*MockAuth)(nil).GetUserIdBySession), ctx, session)
 }
 
 // Logout mocks base method.
-func (m *MockAuth) Logout(session string) error {
+func (m *MockAuth) Logout(ctx context.Context, session string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Logout", session)
+	ret := m.ctrl.Call(m, "Logout", ctx, session)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Logout indicates an expected call of Logout.
-func (mr *MockAuthMockRecorder) Logout(session interface{}) *gomock.Call {
+func (mr *MockAuthMockRecorder) Logout(ctx, session interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuth)(nil).Logout), session)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuth)(nil).Logout), ctx, session)
 }
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
@@ -36,74 +37,89 @@ func (m *MockCart) EXPECT() *MockCartMockRecorder {
 }
 
 // AddAdvert mocks base method.
-func (m *MockCart) AddAdvert(userID, AdvertID uuid.UUID) error {
+func (m *MockCart) AddAdvert(ctx context.Context, userID, AdvertID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddAdvert", userID, AdvertID)
+	ret := m.ctrl.Call(m, "AddAdvert", ctx, userID, AdvertID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddAdvert indicates an expected call of AddAdvert.
-func (mr *MockCartMockRecorder) AddAdvert(userID, AdvertID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) AddAdvert(ctx, userID, AdvertID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdvert", reflect.TypeOf((*MockCart)(nil).AddAdvert), userID, AdvertID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdvert", reflect.TypeOf((*MockCart)(nil).AddAdvert), ctx, userID, AdvertID)
 }
 
 // CheckExists mocks base method.
-func (m *MockCart) CheckExists(userID uuid.UUID) (uuid.UUID, error) {
+func (m *MockCart) CheckExists(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckExists", userID)
+	ret := m.ctrl.Call(m, "CheckExists", ctx, userID)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckExists indicates an expected call of CheckExists.
-func (mr *MockCartMockRecorder) CheckExists(userID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) CheckExists(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckExists", reflect.TypeOf((*MockCart)(nil).CheckExists), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckExists", reflect.TypeOf((*MockCart)(nil).CheckExists), ctx, userID)
 }
 
 // DeleteAdvert mocks base method.
-func (m *MockCart) DeleteAdvert(cartID, AdvertID uuid.UUID) error {
+func (m *MockCart) DeleteAdvert(ctx context.Context, cartID, AdvertID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAdvert", cartID, AdvertID)
+	ret := m.ctrl.Call(m, "DeleteAdvert", ctx, cartID, AdvertID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAdvert indicates an expected call of DeleteAdvert.
-func (mr *MockCartMockRecorder) DeleteAdvert(cartID, AdvertID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) DeleteAdvert(ctx, cartID, AdvertID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdvert", reflect.TypeOf((*MockCart)(nil).DeleteAdvert), cartID, AdvertID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdvert", reflect.TypeOf((*MockCart)(nil).DeleteAdvert), ctx, cartID, AdvertID)
 }
 
 // GetById mocks base method.
-func (m *MockCart) GetById(cartID uuid.UUID) (dto.Cart, error) {
+func (m *MockCart) GetById(ctx context.Context, cartID uuid.UUID) (dto.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetById", cartID)
+	ret := m.ctrl.Call(m, "GetById", ctx, cartID)
 	ret0, _ := ret[0].(dto.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetById indicates an expected call of GetById.
-func (mr *MockCartMockRecorder) GetById(cartID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) GetById(ctx, cartID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCart)(nil).GetById), cartID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockCart)(nil).GetById), ctx, cartID)
 }
 
 // GetByUserId mocks base method.
-func (m *MockCart) GetByUserId(userID uuid.UUID) (dto.Cart, error) {
+func (m *MockCart) GetByUserId(ctx context.Context, userID uuid.UUID) (dto.Cart, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", userID)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userID)
 	ret0, _ := ret[0].(dto.Cart)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockCartMockRecorder) GetByUserId(userID interface{}) *gomock.Call {
+func (mr *MockCartMockRecorder) GetByUserId(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockCart)(nil).GetByUserId), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockCart)(nil).GetByUserId), ctx, userID)
+}
+
+// MergeAdverts mocks base method.
+func (m *MockCart) MergeAdverts(ctx context.Context, userID uuid.UUID, advertIDs []uuid.UUID) (dto.CartMergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeAdverts", ctx, userID, advertIDs)
+	ret0, _ := ret[0].(dto.CartMergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeAdverts indicates an expected call of MergeAdverts.
+func (mr *MockCartMockRecorder) MergeAdverts(ctx, userID, advertIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeAdverts", reflect.TypeOf((*MockCart)(nil).MergeAdverts), ctx, userID, advertIDs)
 }
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -35,16 +36,16 @@ func (m *MockCategoryUseCase) EXPECT() *MockCategoryUseCaseMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockCategoryUseCase) Get() ([]*entity.Category, error) {
+func (m *MockCategoryUseCase) Get(ctx context.Context) ([]*entity.Category, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get")
+	ret := m.ctrl.Call(m, "Get", ctx)
 	ret0, _ := ret[0].([]*entity.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockCategoryUseCaseMockRecorder) Get() *gomock.Call {
+func (mr *MockCategoryUseCaseMockRecorder) Get(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCategoryUseCase)(nil).Get))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCategoryUseCase)(nil).Get), ctx)
 }
@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/deal.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockSafeDealUseCase is a mock of SafeDealUseCase interface.
+type MockSafeDealUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockSafeDealUseCaseMockRecorder
+}
+
+// MockSafeDealUseCaseMockRecorder is the mock recorder for MockSafeDealUseCase.
+type MockSafeDealUseCaseMockRecorder struct {
+	mock *MockSafeDealUseCase
+}
+
+// NewMockSafeDealUseCase creates a new mock instance.
+func NewMockSafeDealUseCase(ctrl *gomock.Controller) *MockSafeDealUseCase {
+	mock := &MockSafeDealUseCase{ctrl: ctrl}
+	mock.recorder = &MockSafeDealUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSafeDealUseCase) EXPECT() *MockSafeDealUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockSafeDealUseCase) Confirm(ctx context.Context, dealID, userID uuid.UUID) (*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, dealID, userID)
+	ret0, _ := ret[0].(*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockSafeDealUseCaseMockRecorder) Confirm(ctx, dealID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockSafeDealUseCase)(nil).Confirm), ctx, dealID, userID)
+}
+
+// Create mocks base method.
+func (m *MockSafeDealUseCase) Create(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, purchaseID, userID)
+	ret0, _ := ret[0].(*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSafeDealUseCaseMockRecorder) Create(ctx, purchaseID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSafeDealUseCase)(nil).Create), ctx, purchaseID, userID)
+}
+
+// GetByPurchaseId mocks base method.
+func (m *MockSafeDealUseCase) GetByPurchaseId(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPurchaseId", ctx, purchaseID, userID)
+	ret0, _ := ret[0].(*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPurchaseId indicates an expected call of GetByPurchaseId.
+func (mr *MockSafeDealUseCaseMockRecorder) GetByPurchaseId(ctx, purchaseID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPurchaseId", reflect.TypeOf((*MockSafeDealUseCase)(nil).GetByPurchaseId), ctx, purchaseID, userID)
+}
+
+// GetDisputes mocks base method.
+func (m *MockSafeDealUseCase) GetDisputes(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputes", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputes indicates an expected call of GetDisputes.
+func (mr *MockSafeDealUseCaseMockRecorder) GetDisputes(ctx, userID, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputes", reflect.TypeOf((*MockSafeDealUseCase)(nil).GetDisputes), ctx, userID, limit, offset)
+}
+
+// OpenDispute mocks base method.
+func (m *MockSafeDealUseCase) OpenDispute(ctx context.Context, dealID, userID uuid.UUID, reason string) (*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDispute", ctx, dealID, userID, reason)
+	ret0, _ := ret[0].(*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenDispute indicates an expected call of OpenDispute.
+func (mr *MockSafeDealUseCaseMockRecorder) OpenDispute(ctx, dealID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDispute", reflect.TypeOf((*MockSafeDealUseCase)(nil).OpenDispute), ctx, dealID, userID, reason)
+}
+
+// ProcessExpired mocks base method.
+func (m *MockSafeDealUseCase) ProcessExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessExpired indicates an expected call of ProcessExpired.
+func (mr *MockSafeDealUseCaseMockRecorder) ProcessExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessExpired", reflect.TypeOf((*MockSafeDealUseCase)(nil).ProcessExpired), ctx)
+}
+
+// Resolve mocks base method.
+func (m *MockSafeDealUseCase) Resolve(ctx context.Context, dealID, userID uuid.UUID, resolution entity.DealResolution) (*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, dealID, userID, resolution)
+	ret0, _ := ret[0].(*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockSafeDealUseCaseMockRecorder) Resolve(ctx, dealID, userID, resolution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockSafeDealUseCase)(nil).Resolve), ctx, dealID, userID, resolution)
+}
+
+// Ship mocks base method.
+func (m *MockSafeDealUseCase) Ship(ctx context.Context, dealID, userID uuid.UUID) (*dto.DealResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ship", ctx, dealID, userID)
+	ret0, _ := ret[0].(*dto.DealResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ship indicates an expected call of Ship.
+func (mr *MockSafeDealUseCaseMockRecorder) Ship(ctx, dealID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ship", reflect.TypeOf((*MockSafeDealUseCase)(nil).Ship), ctx, dealID, userID)
+}
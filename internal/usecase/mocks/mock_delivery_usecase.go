@@ -0,0 +1,157 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/delivery.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDeliveryUseCase is a mock of DeliveryUseCase interface.
+type MockDeliveryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryUseCaseMockRecorder
+}
+
+// MockDeliveryUseCaseMockRecorder is the mock recorder for MockDeliveryUseCase.
+type MockDeliveryUseCaseMockRecorder struct {
+	mock *MockDeliveryUseCase
+}
+
+// NewMockDeliveryUseCase creates a new mock instance.
+func NewMockDeliveryUseCase(ctrl *gomock.Controller) *MockDeliveryUseCase {
+	mock := &MockDeliveryUseCase{ctrl: ctrl}
+	mock.recorder = &MockDeliveryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryUseCase) EXPECT() *MockDeliveryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateShipments mocks base method.
+func (m *MockDeliveryUseCase) CreateShipments(ctx context.Context, purchaseID, userID uuid.UUID, optionType entity.DeliveryOptionType) ([]*dto.ShipmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShipments", ctx, purchaseID, userID, optionType)
+	ret0, _ := ret[0].([]*dto.ShipmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateShipments indicates an expected call of CreateShipments.
+func (mr *MockDeliveryUseCaseMockRecorder) CreateShipments(ctx, purchaseID, userID, optionType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShipments", reflect.TypeOf((*MockDeliveryUseCase)(nil).CreateShipments), ctx, purchaseID, userID, optionType)
+}
+
+// GetOptions mocks base method.
+func (m *MockDeliveryUseCase) GetOptions(ctx context.Context, advertID uuid.UUID) ([]*dto.DeliveryOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOptions", ctx, advertID)
+	ret0, _ := ret[0].([]*dto.DeliveryOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOptions indicates an expected call of GetOptions.
+func (mr *MockDeliveryUseCaseMockRecorder) GetOptions(ctx, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOptions", reflect.TypeOf((*MockDeliveryUseCase)(nil).GetOptions), ctx, advertID)
+}
+
+// GetQuotes mocks base method.
+func (m *MockDeliveryUseCase) GetQuotes(ctx context.Context, cartID, userID uuid.UUID) ([]*dto.DeliveryQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotes", ctx, cartID, userID)
+	ret0, _ := ret[0].([]*dto.DeliveryQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotes indicates an expected call of GetQuotes.
+func (mr *MockDeliveryUseCaseMockRecorder) GetQuotes(ctx, cartID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotes", reflect.TypeOf((*MockDeliveryUseCase)(nil).GetQuotes), ctx, cartID, userID)
+}
+
+// GetShipments mocks base method.
+func (m *MockDeliveryUseCase) GetShipments(ctx context.Context, purchaseID, userID uuid.UUID) ([]*dto.ShipmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipments", ctx, purchaseID, userID)
+	ret0, _ := ret[0].([]*dto.ShipmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipments indicates an expected call of GetShipments.
+func (mr *MockDeliveryUseCaseMockRecorder) GetShipments(ctx, purchaseID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipments", reflect.TypeOf((*MockDeliveryUseCase)(nil).GetShipments), ctx, purchaseID, userID)
+}
+
+// Quote mocks base method.
+func (m *MockDeliveryUseCase) Quote(ctx context.Context, cartID, userID uuid.UUID, optionType entity.DeliveryOptionType) (*dto.DeliveryQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, cartID, userID, optionType)
+	ret0, _ := ret[0].(*dto.DeliveryQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockDeliveryUseCaseMockRecorder) Quote(ctx, cartID, userID, optionType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockDeliveryUseCase)(nil).Quote), ctx, cartID, userID, optionType)
+}
+
+// RefreshTracking mocks base method.
+func (m *MockDeliveryUseCase) RefreshTracking(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTracking", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshTracking indicates an expected call of RefreshTracking.
+func (mr *MockDeliveryUseCaseMockRecorder) RefreshTracking(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTracking", reflect.TypeOf((*MockDeliveryUseCase)(nil).RefreshTracking), ctx)
+}
+
+// SetOptions mocks base method.
+func (m *MockDeliveryUseCase) SetOptions(ctx context.Context, advertID, userID uuid.UUID, options []*dto.DeliveryOption) ([]*dto.DeliveryOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOptions", ctx, advertID, userID, options)
+	ret0, _ := ret[0].([]*dto.DeliveryOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetOptions indicates an expected call of SetOptions.
+func (mr *MockDeliveryUseCaseMockRecorder) SetOptions(ctx, advertID, userID, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOptions", reflect.TypeOf((*MockDeliveryUseCase)(nil).SetOptions), ctx, advertID, userID, options)
+}
+
+// SetTracking mocks base method.
+func (m *MockDeliveryUseCase) SetTracking(ctx context.Context, shipmentID, userID uuid.UUID, trackingNumber string) (*dto.ShipmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTracking", ctx, shipmentID, userID, trackingNumber)
+	ret0, _ := ret[0].(*dto.ShipmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTracking indicates an expected call of SetTracking.
+func (mr *MockDeliveryUseCaseMockRecorder) SetTracking(ctx, shipmentID, userID, trackingNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTracking", reflect.TypeOf((*MockDeliveryUseCase)(nil).SetTracking), ctx, shipmentID, userID, trackingNumber)
+}
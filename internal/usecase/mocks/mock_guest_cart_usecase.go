@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/guest_cart.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockGuestCart is a mock of GuestCart interface.
+type MockGuestCart struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestCartMockRecorder
+}
+
+// MockGuestCartMockRecorder is the mock recorder for MockGuestCart.
+type MockGuestCartMockRecorder struct {
+	mock *MockGuestCart
+}
+
+// NewMockGuestCart creates a new mock instance.
+func NewMockGuestCart(ctrl *gomock.Controller) *MockGuestCart {
+	mock := &MockGuestCart{ctrl: ctrl}
+	mock.recorder = &MockGuestCartMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuestCart) EXPECT() *MockGuestCartMockRecorder {
+	return m.recorder
+}
+
+// AddAdvert mocks base method.
+func (m *MockGuestCart) AddAdvert(ctx context.Context, guestID, advertID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAdvert", ctx, guestID, advertID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAdvert indicates an expected call of AddAdvert.
+func (mr *MockGuestCartMockRecorder) AddAdvert(ctx, guestID, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAdvert", reflect.TypeOf((*MockGuestCart)(nil).AddAdvert), ctx, guestID, advertID)
+}
+
+// Clear mocks base method.
+func (m *MockGuestCart) Clear(ctx context.Context, guestID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, guestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockGuestCartMockRecorder) Clear(ctx, guestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockGuestCart)(nil).Clear), ctx, guestID)
+}
+
+// DeleteAdvert mocks base method.
+func (m *MockGuestCart) DeleteAdvert(ctx context.Context, guestID, advertID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAdvert", ctx, guestID, advertID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAdvert indicates an expected call of DeleteAdvert.
+func (mr *MockGuestCartMockRecorder) DeleteAdvert(ctx, guestID, advertID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAdvert", reflect.TypeOf((*MockGuestCart)(nil).DeleteAdvert), ctx, guestID, advertID)
+}
+
+// GetAdvertIDs mocks base method.
+func (m *MockGuestCart) GetAdvertIDs(ctx context.Context, guestID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdvertIDs", ctx, guestID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdvertIDs indicates an expected call of GetAdvertIDs.
+func (mr *MockGuestCartMockRecorder) GetAdvertIDs(ctx, guestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdvertIDs", reflect.TypeOf((*MockGuestCart)(nil).GetAdvertIDs), ctx, guestID)
+}
+
+// GetCart mocks base method.
+func (m *MockGuestCart) GetCart(ctx context.Context, guestID uuid.UUID) (dto.GuestCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", ctx, guestID)
+	ret0, _ := ret[0].(dto.GuestCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockGuestCartMockRecorder) GetCart(ctx, guestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockGuestCart)(nil).GetCart), ctx, guestID)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/payment.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPaymentUseCase is a mock of PaymentUseCase interface.
+type MockPaymentUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentUseCaseMockRecorder
+}
+
+// MockPaymentUseCaseMockRecorder is the mock recorder for MockPaymentUseCase.
+type MockPaymentUseCaseMockRecorder struct {
+	mock *MockPaymentUseCase
+}
+
+// NewMockPaymentUseCase creates a new mock instance.
+func NewMockPaymentUseCase(ctrl *gomock.Controller) *MockPaymentUseCase {
+	mock := &MockPaymentUseCase{ctrl: ctrl}
+	mock.recorder = &MockPaymentUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentUseCase) EXPECT() *MockPaymentUseCaseMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockPaymentUseCase) Cancel(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, paymentID)
+	ret0, _ := ret[0].(*dto.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockPaymentUseCaseMockRecorder) Cancel(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockPaymentUseCase)(nil).Cancel), ctx, paymentID)
+}
+
+// Capture mocks base method.
+func (m *MockPaymentUseCase) Capture(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, paymentID)
+	ret0, _ := ret[0].(*dto.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockPaymentUseCaseMockRecorder) Capture(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockPaymentUseCase)(nil).Capture), ctx, paymentID)
+}
+
+// Create mocks base method.
+func (m *MockPaymentUseCase) Create(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, purchaseID, userID)
+	ret0, _ := ret[0].(*dto.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentUseCaseMockRecorder) Create(ctx, purchaseID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentUseCase)(nil).Create), ctx, purchaseID, userID)
+}
+
+// GetByPurchaseId mocks base method.
+func (m *MockPaymentUseCase) GetByPurchaseId(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPurchaseId", ctx, purchaseID, userID)
+	ret0, _ := ret[0].(*dto.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPurchaseId indicates an expected call of GetByPurchaseId.
+func (mr *MockPaymentUseCaseMockRecorder) GetByPurchaseId(ctx, purchaseID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPurchaseId", reflect.TypeOf((*MockPaymentUseCase)(nil).GetByPurchaseId), ctx, purchaseID, userID)
+}
+
+// HandleWebhook mocks base method.
+func (m *MockPaymentUseCase) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleWebhook", ctx, payload, signature)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleWebhook indicates an expected call of HandleWebhook.
+func (mr *MockPaymentUseCaseMockRecorder) HandleWebhook(ctx, payload, signature interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleWebhook", reflect.TypeOf((*MockPaymentUseCase)(nil).HandleWebhook), ctx, payload, signature)
+}
+
+// Refund mocks base method.
+func (m *MockPaymentUseCase) Refund(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, paymentID)
+	ret0, _ := ret[0].(*dto.PaymentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPaymentUseCaseMockRecorder) Refund(ctx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPaymentUseCase)(nil).Refund), ctx, paymentID)
+}
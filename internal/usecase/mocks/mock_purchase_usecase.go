@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
@@ -36,31 +37,31 @@ func (m *MockPurchase) EXPECT() *MockPurchaseMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockPurchase) Add(purchaseRequest dto.PurchaseRequest, userId uuid.UUID) (*dto.PurchaseResponse, error) {
+func (m *MockPurchase) Add(ctx context.Context, purchaseRequest dto.PurchaseRequest, userId uuid.UUID) (*dto.PurchaseResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", purchaseRequest, userId)
+	ret := m.ctrl.Call(m, "Add", ctx, purchaseRequest, userId)
 	ret0, _ := ret[0].(*dto.PurchaseResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockPurchaseMockRecorder) Add(purchaseRequest, userId interface{}) *gomock.Call {
+func (mr *MockPurchaseMockRecorder) Add(ctx, purchaseRequest, userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockPurchase)(nil).Add), purchaseRequest, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockPurchase)(nil).Add), ctx, purchaseRequest, userId)
 }
 
 // GetByUserId mocks base method.
-func (m *MockPurchase) GetByUserId(userID uuid.UUID) ([]*dto.PurchaseResponse, error) {
+func (m *MockPurchase) GetByUserId(ctx context.Context, userID uuid.UUID) ([]*dto.PurchaseResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserId", userID)
+	ret := m.ctrl.Call(m, "GetByUserId", ctx, userID)
 	ret0, _ := ret[0].([]*dto.PurchaseResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByUserId indicates an expected call of GetByUserId.
-func (mr *MockPurchaseMockRecorder) GetByUserId(userID interface{}) *gomock.Call {
+func (mr *MockPurchaseMockRecorder) GetByUserId(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockPurchase)(nil).GetByUserId), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserId", reflect.TypeOf((*MockPurchase)(nil).GetByUserId), ctx, userID)
 }
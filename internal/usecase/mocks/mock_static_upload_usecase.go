@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/static_upload.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockStaticUpload is a mock of StaticUpload interface.
+type MockStaticUpload struct {
+	ctrl     *gomock.Controller
+	recorder *MockStaticUploadMockRecorder
+}
+
+// MockStaticUploadMockRecorder is the mock recorder for MockStaticUpload.
+type MockStaticUploadMockRecorder struct {
+	mock *MockStaticUpload
+}
+
+// NewMockStaticUpload creates a new mock instance.
+func NewMockStaticUpload(ctrl *gomock.Controller) *MockStaticUpload {
+	mock := &MockStaticUpload{ctrl: ctrl}
+	mock.recorder = &MockStaticUploadMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStaticUpload) EXPECT() *MockStaticUploadMockRecorder {
+	return m.recorder
+}
+
+// AppendUpload mocks base method.
+func (m *MockStaticUpload) AppendUpload(ctx context.Context, uploadID, ownerID uuid.UUID, offset int64, data io.Reader) (*entity.ResumableUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendUpload", ctx, uploadID, ownerID, offset, data)
+	ret0, _ := ret[0].(*entity.ResumableUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendUpload indicates an expected call of AppendUpload.
+func (mr *MockStaticUploadMockRecorder) AppendUpload(ctx, uploadID, ownerID, offset, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUpload", reflect.TypeOf((*MockStaticUpload)(nil).AppendUpload), ctx, uploadID, ownerID, offset, data)
+}
+
+// CancelUpload mocks base method.
+func (m *MockStaticUpload) CancelUpload(ctx context.Context, uploadID, ownerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelUpload", ctx, uploadID, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelUpload indicates an expected call of CancelUpload.
+func (mr *MockStaticUploadMockRecorder) CancelUpload(ctx, uploadID, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUpload", reflect.TypeOf((*MockStaticUpload)(nil).CancelUpload), ctx, uploadID, ownerID)
+}
+
+// CreateUpload mocks base method.
+func (m *MockStaticUpload) CreateUpload(ctx context.Context, ownerID uuid.UUID, metadata entity.UploadMetadata) (*entity.ResumableUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUpload", ctx, ownerID, metadata)
+	ret0, _ := ret[0].(*entity.ResumableUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUpload indicates an expected call of CreateUpload.
+func (mr *MockStaticUploadMockRecorder) CreateUpload(ctx, ownerID, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUpload", reflect.TypeOf((*MockStaticUpload)(nil).CreateUpload), ctx, ownerID, metadata)
+}
+
+// ExpireUploads mocks base method.
+func (m *MockStaticUpload) ExpireUploads(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireUploads", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireUploads indicates an expected call of ExpireUploads.
+func (mr *MockStaticUploadMockRecorder) ExpireUploads(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireUploads", reflect.TypeOf((*MockStaticUpload)(nil).ExpireUploads), ctx)
+}
+
+// FinishUpload mocks base method.
+func (m *MockStaticUpload) FinishUpload(ctx context.Context, uploadID, ownerID uuid.UUID, purpose entity.UploadPurpose) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishUpload", ctx, uploadID, ownerID, purpose)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishUpload indicates an expected call of FinishUpload.
+func (mr *MockStaticUploadMockRecorder) FinishUpload(ctx, uploadID, ownerID, purpose interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishUpload", reflect.TypeOf((*MockStaticUpload)(nil).FinishUpload), ctx, uploadID, ownerID, purpose)
+}
+
+// GetUpload mocks base method.
+func (m *MockStaticUpload) GetUpload(ctx context.Context, uploadID, ownerID uuid.UUID) (*entity.ResumableUpload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpload", ctx, uploadID, ownerID)
+	ret0, _ := ret[0].(*entity.ResumableUpload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpload indicates an expected call of GetUpload.
+func (mr *MockStaticUploadMockRecorder) GetUpload(ctx, uploadID, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpload", reflect.TypeOf((*MockStaticUpload)(nil).GetUpload), ctx, uploadID, ownerID)
+}
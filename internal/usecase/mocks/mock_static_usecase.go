@@ -5,9 +5,13 @@
 package mocks
 
 import (
+	context "context"
+	image "image"
 	io "io"
 	reflect "reflect"
+	time "time"
 
+	entity "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -35,62 +39,235 @@ func (m *MockStaticUseCase) EXPECT() *MockStaticUseCaseMockRecorder {
 	return m.recorder
 }
 
+// CollectGarbage mocks base method.
+func (m *MockStaticUseCase) CollectGarbage(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectGarbage", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CollectGarbage indicates an expected call of CollectGarbage.
+func (mr *MockStaticUseCaseMockRecorder) CollectGarbage(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectGarbage", reflect.TypeOf((*MockStaticUseCase)(nil).CollectGarbage), ctx)
+}
+
 // GetAvatar mocks base method.
-func (m *MockStaticUseCase) GetAvatar(staticID uuid.UUID) (string, error) {
+func (m *MockStaticUseCase) GetAvatar(ctx context.Context, staticID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAvatar", staticID)
+	ret := m.ctrl.Call(m, "GetAvatar", ctx, staticID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAvatar indicates an expected call of GetAvatar.
-func (mr *MockStaticUseCaseMockRecorder) GetAvatar(staticID interface{}) *gomock.Call {
+func (mr *MockStaticUseCaseMockRecorder) GetAvatar(ctx, staticID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvatar", reflect.TypeOf((*MockStaticUseCase)(nil).GetAvatar), staticID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvatar", reflect.TypeOf((*MockStaticUseCase)(nil).GetAvatar), ctx, staticID)
 }
 
 // GetStatic mocks base method.
-func (m *MockStaticUseCase) GetStatic(id uuid.UUID) (string, error) {
+func (m *MockStaticUseCase) GetStatic(ctx context.Context, id uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStatic", id)
+	ret := m.ctrl.Call(m, "GetStatic", ctx, id)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetStatic indicates an expected call of GetStatic.
-func (mr *MockStaticUseCaseMockRecorder) GetStatic(id interface{}) *gomock.Call {
+func (mr *MockStaticUseCaseMockRecorder) GetStatic(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatic", reflect.TypeOf((*MockStaticUseCase)(nil).GetStatic), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatic", reflect.TypeOf((*MockStaticUseCase)(nil).GetStatic), ctx, id)
 }
 
 // GetStaticFile mocks base method.
-func (m *MockStaticUseCase) GetStaticFile(uri string) (io.ReadSeeker, error) {
+func (m *MockStaticUseCase) GetStaticFile(ctx context.Context, uri string) (io.ReadSeeker, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetStaticFile", uri)
+	ret := m.ctrl.Call(m, "GetStaticFile", ctx, uri)
 	ret0, _ := ret[0].(io.ReadSeeker)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetStaticFile indicates an expected call of GetStaticFile.
-func (mr *MockStaticUseCaseMockRecorder) GetStaticFile(uri interface{}) *gomock.Call {
+func (mr *MockStaticUseCaseMockRecorder) GetStaticFile(ctx, uri interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaticFile", reflect.TypeOf((*MockStaticUseCase)(nil).GetStaticFile), ctx, uri)
+}
+
+// GetStaticFileInfo mocks base method.
+func (m *MockStaticUseCase) GetStaticFileInfo(ctx context.Context, uri string) (*entity.BlobInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaticFileInfo", ctx, uri)
+	ret0, _ := ret[0].(*entity.BlobInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaticFileInfo indicates an expected call of GetStaticFileInfo.
+func (mr *MockStaticUseCaseMockRecorder) GetStaticFileInfo(ctx, uri interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaticFileInfo", reflect.TypeOf((*MockStaticUseCase)(nil).GetStaticFileInfo), ctx, uri)
+}
+
+// GetStaticVariant mocks base method.
+func (m *MockStaticUseCase) GetStaticVariant(ctx context.Context, id uuid.UUID, variant entity.ImageVariant) (io.ReadSeeker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaticVariant", ctx, id, variant)
+	ret0, _ := ret[0].(io.ReadSeeker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaticVariant indicates an expected call of GetStaticVariant.
+func (mr *MockStaticUseCaseMockRecorder) GetStaticVariant(ctx, id, variant interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaticFile", reflect.TypeOf((*MockStaticUseCase)(nil).GetStaticFile), uri)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaticVariant", reflect.TypeOf((*MockStaticUseCase)(nil).GetStaticVariant), ctx, id, variant)
 }
 
 // UploadStatic mocks base method.
-func (m *MockStaticUseCase) UploadStatic(data io.ReadSeeker) (uuid.UUID, error) {
+func (m *MockStaticUseCase) UploadStatic(ctx context.Context, data io.Reader, metadata entity.UploadMetadata) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadStatic", data)
+	ret := m.ctrl.Call(m, "UploadStatic", ctx, data, metadata)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UploadStatic indicates an expected call of UploadStatic.
-func (mr *MockStaticUseCaseMockRecorder) UploadStatic(data interface{}) *gomock.Call {
+func (mr *MockStaticUseCaseMockRecorder) UploadStatic(ctx, data, metadata interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadStatic", reflect.TypeOf((*MockStaticUseCase)(nil).UploadStatic), ctx, data, metadata)
+}
+
+// MockImageInspector is a mock of ImageInspector interface.
+type MockImageInspector struct {
+	ctrl     *gomock.Controller
+	recorder *MockImageInspectorMockRecorder
+}
+
+// MockImageInspectorMockRecorder is the mock recorder for MockImageInspector.
+type MockImageInspectorMockRecorder struct {
+	mock *MockImageInspector
+}
+
+// NewMockImageInspector creates a new mock instance.
+func NewMockImageInspector(ctrl *gomock.Controller) *MockImageInspector {
+	mock := &MockImageInspector{ctrl: ctrl}
+	mock.recorder = &MockImageInspectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImageInspector) EXPECT() *MockImageInspectorMockRecorder {
+	return m.recorder
+}
+
+// Inspect mocks base method.
+func (m *MockImageInspector) Inspect(ctx context.Context, img image.Image) (entity.ImageInspection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inspect", ctx, img)
+	ret0, _ := ret[0].(entity.ImageInspection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inspect indicates an expected call of Inspect.
+func (mr *MockImageInspectorMockRecorder) Inspect(ctx, img interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inspect", reflect.TypeOf((*MockImageInspector)(nil).Inspect), ctx, img)
+}
+
+// MockNSFWScorer is a mock of NSFWScorer interface.
+type MockNSFWScorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockNSFWScorerMockRecorder
+}
+
+// MockNSFWScorerMockRecorder is the mock recorder for MockNSFWScorer.
+type MockNSFWScorerMockRecorder struct {
+	mock *MockNSFWScorer
+}
+
+// NewMockNSFWScorer creates a new mock instance.
+func NewMockNSFWScorer(ctrl *gomock.Controller) *MockNSFWScorer {
+	mock := &MockNSFWScorer{ctrl: ctrl}
+	mock.recorder = &MockNSFWScorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNSFWScorer) EXPECT() *MockNSFWScorerMockRecorder {
+	return m.recorder
+}
+
+// Score mocks base method.
+func (m *MockNSFWScorer) Score(ctx context.Context, img image.Image) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Score", ctx, img)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Score indicates an expected call of Score.
+func (mr *MockNSFWScorerMockRecorder) Score(ctx, img interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Score", reflect.TypeOf((*MockNSFWScorer)(nil).Score), ctx, img)
+}
+
+// MockVideoProcessor is a mock of VideoProcessor interface.
+type MockVideoProcessor struct {
+	ctrl     *gomock.Controller
+	recorder *MockVideoProcessorMockRecorder
+}
+
+// MockVideoProcessorMockRecorder is the mock recorder for MockVideoProcessor.
+type MockVideoProcessorMockRecorder struct {
+	mock *MockVideoProcessor
+}
+
+// NewMockVideoProcessor creates a new mock instance.
+func NewMockVideoProcessor(ctrl *gomock.Controller) *MockVideoProcessor {
+	mock := &MockVideoProcessor{ctrl: ctrl}
+	mock.recorder = &MockVideoProcessorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVideoProcessor) EXPECT() *MockVideoProcessorMockRecorder {
+	return m.recorder
+}
+
+// Frame mocks base method.
+func (m *MockVideoProcessor) Frame(ctx context.Context, path string, at time.Duration) (image.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Frame", ctx, path, at)
+	ret0, _ := ret[0].(image.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Frame indicates an expected call of Frame.
+func (mr *MockVideoProcessorMockRecorder) Frame(ctx, path, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Frame", reflect.TypeOf((*MockVideoProcessor)(nil).Frame), ctx, path, at)
+}
+
+// Probe mocks base method.
+func (m *MockVideoProcessor) Probe(ctx context.Context, path string) (entity.VideoInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Probe", ctx, path)
+	ret0, _ := ret[0].(entity.VideoInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Probe indicates an expected call of Probe.
+func (mr *MockVideoProcessorMockRecorder) Probe(ctx, path interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadStatic", reflect.TypeOf((*MockStaticUseCase)(nil).UploadStatic), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Probe", reflect.TypeOf((*MockVideoProcessor)(nil).Probe), ctx, path)
 }
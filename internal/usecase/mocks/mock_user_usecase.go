@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	dto "github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
@@ -36,88 +37,88 @@ func (m *MockUser) EXPECT() *MockUserMockRecorder {
 }
 
 // ChangePassword mocks base method.
-func (m *MockUser) ChangePassword(userID uuid.UUID, password *dto.UpdatePassword) error {
+func (m *MockUser) ChangePassword(ctx context.Context, userID uuid.UUID, password *dto.UpdatePassword) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ChangePassword", userID, password)
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, password)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ChangePassword indicates an expected call of ChangePassword.
-func (mr *MockUserMockRecorder) ChangePassword(userID, password interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) ChangePassword(ctx, userID, password interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUser)(nil).ChangePassword), userID, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUser)(nil).ChangePassword), ctx, userID, password)
 }
 
 // Get mocks base method.
-func (m *MockUser) Get(userID uuid.UUID) (*dto.User, error) {
+func (m *MockUser) Get(ctx context.Context, userID uuid.UUID) (*dto.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", userID)
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
 	ret0, _ := ret[0].(*dto.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockUserMockRecorder) Get(userID interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Get(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUser)(nil).Get), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUser)(nil).Get), ctx, userID)
 }
 
 // Login mocks base method.
-func (m *MockUser) Login(arg0 *dto.Login) (uuid.UUID, error) {
+func (m *MockUser) Login(ctx context.Context, login *dto.Login) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", arg0)
+	ret := m.ctrl.Call(m, "Login", ctx, login)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserMockRecorder) Login(arg0 interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Login(ctx, login interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUser)(nil).Login), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUser)(nil).Login), ctx, login)
 }
 
 // Signup mocks base method.
-func (m *MockUser) Signup(arg0 *dto.Signup) (uuid.UUID, error) {
+func (m *MockUser) Signup(ctx context.Context, signup *dto.Signup) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Signup", arg0)
+	ret := m.ctrl.Call(m, "Signup", ctx, signup)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Signup indicates an expected call of Signup.
-func (mr *MockUserMockRecorder) Signup(arg0 interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) Signup(ctx, signup interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockUser)(nil).Signup), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockUser)(nil).Signup), ctx, signup)
 }
 
 // UpdateInfo mocks base method.
-func (m *MockUser) UpdateInfo(arg0 *dto.UserUpdate) error {
+func (m *MockUser) UpdateInfo(ctx context.Context, user *dto.UserUpdate) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateInfo", arg0)
+	ret := m.ctrl.Call(m, "UpdateInfo", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateInfo indicates an expected call of UpdateInfo.
-func (mr *MockUserMockRecorder) UpdateInfo(arg0 interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) UpdateInfo(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInfo", reflect.TypeOf((*MockUser)(nil).UpdateInfo), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInfo", reflect.TypeOf((*MockUser)(nil).UpdateInfo), ctx, user)
 }
 
 // UploadImage mocks base method.
-func (m *MockUser) UploadImage(userID, imageID uuid.UUID) error {
+func (m *MockUser) UploadImage(ctx context.Context, userID, imageID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UploadImage", userID, imageID)
+	ret := m.ctrl.Call(m, "UploadImage", ctx, userID, imageID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UploadImage indicates an expected call of UploadImage.
-func (mr *MockUserMockRecorder) UploadImage(userID, imageID interface{}) *gomock.Call {
+func (mr *MockUserMockRecorder) UploadImage(ctx, userID, imageID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockUser)(nil).UploadImage), userID, imageID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockUser)(nil).UploadImage), ctx, userID, imageID)
 }
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/google/uuid"
+)
+
+type PaymentUseCase interface {
+	// Create создает у платежного провайдера платеж за покупку картой на сумму объявлений
+	// корзины. Если платеж за покупку уже создан, возвращает его
+	// Возможные ошибки:
+	// ErrPurchaseNotFound - покупка не найдена
+	// ErrPaymentForbidden - покупка оформлена другим пользователем
+	// ErrPaymentNotRequired - покупка оплачивается не картой или уже не ждет оплаты
+	// ErrPaymentDealRequired - покупка оформлена безопасной сделкой, но сделка еще не создана
+	Create(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.PaymentResponse, error)
+
+	// GetByPurchaseId возвращает платеж за покупку пользователя userID
+	// Возможные ошибки:
+	// ErrPaymentNotFound - платеж за покупку не создан
+	// ErrPaymentForbidden - покупка оформлена другим пользователем
+	GetByPurchaseId(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.PaymentResponse, error)
+
+	// Capture списывает заблокированные на карте покупателя средства
+	// Возможные ошибки:
+	// ErrPaymentNotFound - платеж не найден
+	// ErrPaymentInvalidState - средства по платежу не заблокированы
+	Capture(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error)
+
+	// Cancel разблокирует средства покупателя и отменяет покупку
+	// Возможные ошибки:
+	// ErrPaymentNotFound - платеж не найден
+	// ErrPaymentInvalidState - средства по платежу не заблокированы
+	Cancel(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error)
+
+	// Refund возвращает покупателю списанные средства и отменяет покупку
+	// Возможные ошибки:
+	// ErrPaymentNotFound - платеж не найден
+	// ErrPaymentInvalidState - средства по платежу не списаны
+	Refund(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error)
+
+	// HandleWebhook проверяет подпись уведомления провайдера и переводит платеж и покупку
+	// в новый статус. Повторные и устаревшие уведомления пропускаются
+	// Возможные ошибки:
+	// ErrGatewayInvalidSignature - подпись не совпала
+	// ErrGatewayInvalidWebhook - уведомление не удалось разобрать
+	// ErrPaymentNotFound - платеж из уведомления не найден
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+var (
+	ErrPaymentForbidden    = errors.New("payment of another user's purchase is forbidden")
+	ErrPaymentNotRequired  = errors.New("purchase does not require card payment")
+	ErrPaymentInvalidState = errors.New("operation is not allowed in the current payment status")
+	ErrPaymentDealRequired = errors.New("purchase is paid with a safe deal that is not created yet")
+)
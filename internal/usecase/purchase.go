@@ -1,14 +1,26 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/google/uuid"
 )
 
 type Purchase interface {
-	// Add добавляет покупку в базу данных
-	Add(purchaseRequest dto.PurchaseRequest, userId uuid.UUID) (*dto.PurchaseResponse, error)
+	// Add добавляет покупку в базу данных вместе со снимком адреса и резервирует объявления корзины
+	// Возможные ошибки:
+	// *UnavailableAdvertsError - часть объявлений корзины нельзя купить
+	// ErrPurchaseEmptyCart - в корзине нет объявлений
+	// ErrPurchaseAddressRequired - не передан адрес покупки
+	Add(ctx context.Context, purchaseRequest dto.PurchaseRequest, userId uuid.UUID) (*dto.PurchaseResponse, error)
 
 	// GetByUserId получает покупки по UserID
-	GetByUserId(userID uuid.UUID) ([]*dto.PurchaseResponse, error)
+	GetByUserId(ctx context.Context, userID uuid.UUID) ([]*dto.PurchaseResponse, error)
 }
+
+var (
+	ErrPurchaseEmptyCart       = errors.New("cart has no adverts to purchase")
+	ErrPurchaseAddressRequired = errors.New("purchase address is required")
+)
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	maxRecipientNameLength = 100
+	maxCityLength          = 100
+	maxStreetLength        = 150
+	maxBuildingLength      = 20
+	maxApartmentLength     = 20
+)
+
+var (
+	addressPhoneRegexp    = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+	addressPostcodeRegexp = regexp.MustCompile(`^[0-9]{6}$`)
+	// из телефона убираются разделители, с которыми его обычно вводят
+	addressPhoneReplacer = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "")
+)
+
+// AddressService ведет адресную книгу пользователя и выбирает адрес при оформлении покупки
+type AddressService struct {
+	addressRepo repository.AddressRepository
+}
+
+func NewAddressService(addressRepo repository.AddressRepository) *AddressService {
+	return &AddressService{
+		addressRepo: addressRepo,
+	}
+}
+
+func addressFieldsToDTO(fields entity.AddressFields) dto.AddressDetails {
+	return dto.AddressDetails{
+		RecipientName: fields.RecipientName,
+		Phone:         fields.Phone,
+		City:          fields.City,
+		Street:        fields.Street,
+		Building:      fields.Building,
+		Apartment:     fields.Apartment,
+		Postcode:      fields.Postcode,
+	}
+}
+
+func addressEntityToDTO(address *entity.Address) *dto.AddressResponse {
+	return &dto.AddressResponse{
+		ID:             address.ID,
+		AddressDetails: addressFieldsToDTO(address.AddressFields),
+		IsDefault:      address.IsDefault,
+	}
+}
+
+// normalizeAddress убирает лишние пробелы и разделители телефона и проверяет адрес
+func normalizeAddress(details dto.AddressDetails) (entity.AddressFields, error) {
+	fields := entity.AddressFields{
+		RecipientName: strings.TrimSpace(details.RecipientName),
+		Phone:         addressPhoneReplacer.Replace(strings.TrimSpace(details.Phone)),
+		City:          strings.TrimSpace(details.City),
+		Street:        strings.TrimSpace(details.Street),
+		Building:      strings.TrimSpace(details.Building),
+		Apartment:     strings.TrimSpace(details.Apartment),
+		Postcode:      strings.TrimSpace(details.Postcode),
+	}
+
+	limits := []struct {
+		value    string
+		required bool
+		max      int
+	}{
+		{fields.RecipientName, true, maxRecipientNameLength},
+		{fields.City, true, maxCityLength},
+		{fields.Street, true, maxStreetLength},
+		{fields.Building, true, maxBuildingLength},
+		{fields.Apartment, false, maxApartmentLength},
+	}
+	for _, limit := range limits {
+		if (limit.required && limit.value == "") || utf8.RuneCountInString(limit.value) > limit.max {
+			return entity.AddressFields{}, usecase.ErrAddressBadRequest
+		}
+	}
+	if !addressPhoneRegexp.MatchString(fields.Phone) {
+		return entity.AddressFields{}, usecase.ErrAddressBadRequest
+	}
+	if fields.Postcode != "" && !addressPostcodeRegexp.MatchString(fields.Postcode) {
+		return entity.AddressFields{}, usecase.ErrAddressBadRequest
+	}
+	return fields, nil
+}
+
+func (s *AddressService) GetAddresses(ctx context.Context, userID uuid.UUID) ([]*dto.AddressResponse, error) {
+	addresses, err := s.addressRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting addresses"))
+	}
+	result := make([]*dto.AddressResponse, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, addressEntityToDTO(address))
+	}
+	return result, nil
+}
+
+func (s *AddressService) AddAddress(ctx context.Context, userID uuid.UUID, req dto.AddressRequest) (*dto.AddressResponse, error) {
+	fields, err := normalizeAddress(req.AddressDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.addressRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	if req.IsDefault {
+		if err := s.addressRepo.ClearDefault(ctx, tx, userID); err != nil {
+			_ = tx.Rollback(ctx)
+			return nil, entity.UsecaseWrap(err, errors.New("error clearing default address"))
+		}
+	}
+	address, err := s.addressRepo.Add(ctx, tx, &entity.Address{
+		UserID:        userID,
+		AddressFields: fields,
+		IsDefault:     req.IsDefault,
+	}, entity.MaxUserAddresses)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, entity.UsecaseWrap(err, errors.New("error adding address"))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	middleware.GetLogger(ctx).Info("address added", zap.String("address_id", address.ID.String()),
+		zap.String("user_id", userID.String()))
+	return addressEntityToDTO(address), nil
+}
+
+func (s *AddressService) UpdateAddress(ctx context.Context, addressID, userID uuid.UUID, req dto.AddressRequest) (*dto.AddressResponse, error) {
+	fields, err := normalizeAddress(req.AddressDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.addressRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	if req.IsDefault {
+		if err := s.addressRepo.ClearDefault(ctx, tx, userID); err != nil {
+			_ = tx.Rollback(ctx)
+			return nil, entity.UsecaseWrap(err, errors.New("error clearing default address"))
+		}
+	}
+	address, err := s.addressRepo.Update(ctx, tx, &entity.Address{
+		ID:            addressID,
+		UserID:        userID,
+		AddressFields: fields,
+		IsDefault:     req.IsDefault,
+	})
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, entity.UsecaseWrap(err, errors.New("error updating address"))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	middleware.GetLogger(ctx).Info("address updated", zap.String("address_id", addressID.String()))
+	return addressEntityToDTO(address), nil
+}
+
+func (s *AddressService) DeleteAddress(ctx context.Context, addressID, userID uuid.UUID) error {
+	tx, err := s.addressRepo.BeginTransaction(ctx)
+	if err != nil {
+		return entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	wasDefault, err := s.addressRepo.Delete(ctx, tx, addressID, userID)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return entity.UsecaseWrap(err, errors.New("error deleting address"))
+	}
+	if wasDefault {
+		if err := s.addressRepo.SetNewestDefault(ctx, tx, userID); err != nil {
+			_ = tx.Rollback(ctx)
+			return entity.UsecaseWrap(err, errors.New("error setting default address"))
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	middleware.GetLogger(ctx).Info("address deleted", zap.String("address_id", addressID.String()))
+	return nil
+}
+
+func (s *AddressService) SetDefault(ctx context.Context, addressID, userID uuid.UUID) (*dto.AddressResponse, error) {
+	tx, err := s.addressRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	if err := s.addressRepo.ClearDefault(ctx, tx, userID); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, entity.UsecaseWrap(err, errors.New("error clearing default address"))
+	}
+	address, err := s.addressRepo.SetDefault(ctx, tx, addressID, userID)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, entity.UsecaseWrap(err, errors.New("error setting default address"))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	middleware.GetLogger(ctx).Info("default address changed", zap.String("address_id", addressID.String()),
+		zap.String("user_id", userID.String()))
+	return addressEntityToDTO(address), nil
+}
+
+func (s *AddressService) ResolveCheckout(ctx context.Context, userID uuid.UUID, addressID *uuid.UUID,
+	details *dto.AddressDetails) (*dto.PurchaseAddress, error) {
+	switch {
+	case addressID != nil && details != nil:
+		return nil, usecase.ErrAddressBadRequest
+	case addressID != nil:
+		address, err := s.addressRepo.GetById(ctx, *addressID, userID)
+		if err != nil {
+			return nil, entity.UsecaseWrap(err, errors.New("error getting address"))
+		}
+		return &dto.PurchaseAddress{
+			AddressID:      &address.ID,
+			AddressDetails: addressFieldsToDTO(address.AddressFields),
+		}, nil
+	case details != nil:
+		fields, err := normalizeAddress(*details)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.PurchaseAddress{AddressDetails: addressFieldsToDTO(fields)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *AddressService) GetPurchaseAddresses(ctx context.Context, purchaseIDs []uuid.UUID) (map[uuid.UUID]*dto.PurchaseAddress, error) {
+	result := make(map[uuid.UUID]*dto.PurchaseAddress, len(purchaseIDs))
+	if len(purchaseIDs) == 0 {
+		return result, nil
+	}
+
+	snapshots, err := s.addressRepo.GetPurchaseSnapshots(ctx, purchaseIDs)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting purchase addresses"))
+	}
+	for _, snapshot := range snapshots {
+		result[snapshot.PurchaseID] = &dto.PurchaseAddress{
+			AddressID:      snapshot.AddressID,
+			AddressDetails: addressFieldsToDTO(snapshot.AddressFields),
+		}
+	}
+	return result, nil
+}
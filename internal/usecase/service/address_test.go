@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type addressTestDeps struct {
+	addressRepo *mocks.MockAddressRepository
+	pools       []pgxmock.PgxPoolIface
+}
+
+func setupAddressService(t *testing.T) (*AddressService, *addressTestDeps) {
+	ctrl := gomock.NewController(t)
+	deps := &addressTestDeps{
+		addressRepo: mocks.NewMockAddressRepository(ctrl),
+	}
+	return NewAddressService(deps.addressRepo), deps
+}
+
+// expectTx ожидает очередную транзакцию, которая завершится commit или rollback
+func (d *addressTestDeps) expectTx(t *testing.T, commit bool) pgx.Tx {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	d.pools = append(d.pools, pool)
+
+	pool.ExpectBegin()
+	tx, err := pool.Begin(context.Background())
+	require.NoError(t, err)
+	if commit {
+		pool.ExpectCommit()
+	} else {
+		pool.ExpectRollback()
+	}
+	d.addressRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	return tx
+}
+
+func (d *addressTestDeps) assertTxDone(t *testing.T) {
+	for _, pool := range d.pools {
+		assert.NoError(t, pool.ExpectationsWereMet())
+	}
+}
+
+func validAddressDetails() dto.AddressDetails {
+	return dto.AddressDetails{
+		RecipientName: " Иван Иванов ",
+		Phone:         "+7 (999) 123-45-67",
+		City:          "Москва",
+		Street:        "Тверская ул.",
+		Building:      "1",
+		Apartment:     "5",
+		Postcode:      "125009",
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	fields, err := normalizeAddress(validAddressDetails())
+	require.NoError(t, err)
+	assert.Equal(t, "Иван Иванов", fields.RecipientName)
+	assert.Equal(t, "+79991234567", fields.Phone)
+	assert.Equal(t, "125009, Москва, Тверская ул., д. 1, кв. 5", fields.Text())
+
+	invalid := map[string]func(d *dto.AddressDetails){
+		"NoCity":        func(d *dto.AddressDetails) { d.City = "  " },
+		"NoBuilding":    func(d *dto.AddressDetails) { d.Building = "" },
+		"ShortPhone":    func(d *dto.AddressDetails) { d.Phone = "12345" },
+		"LetterPhone":   func(d *dto.AddressDetails) { d.Phone = "+7999abc4567" },
+		"BadPostcode":   func(d *dto.AddressDetails) { d.Postcode = "12345" },
+		"LongApartment": func(d *dto.AddressDetails) { d.Apartment = "123456789012345678901" },
+	}
+	for name, modify := range invalid {
+		t.Run(name, func(t *testing.T) {
+			details := validAddressDetails()
+			modify(&details)
+			_, err := normalizeAddress(details)
+			assert.ErrorIs(t, err, usecase.ErrAddressBadRequest)
+		})
+	}
+}
+
+func TestAddressService_AddAddress(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("Default", func(t *testing.T) {
+		service, deps := setupAddressService(t)
+		tx := deps.expectTx(t, true)
+		deps.addressRepo.EXPECT().ClearDefault(gomock.Any(), tx, userID).Return(nil)
+		deps.addressRepo.EXPECT().Add(gomock.Any(), tx, gomock.Any(), entity.MaxUserAddresses).
+			DoAndReturn(func(_ context.Context, _ pgx.Tx, address *entity.Address, _ int) (*entity.Address, error) {
+				assert.Equal(t, "+79991234567", address.Phone)
+				address.ID = uuid.New()
+				return address, nil
+			})
+
+		response, err := service.AddAddress(context.Background(), userID,
+			dto.AddressRequest{AddressDetails: validAddressDetails(), IsDefault: true})
+		require.NoError(t, err)
+		assert.True(t, response.IsDefault)
+		assert.Equal(t, "Иван Иванов", response.RecipientName)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		service, deps := setupAddressService(t)
+		tx := deps.expectTx(t, false)
+		deps.addressRepo.EXPECT().Add(gomock.Any(), tx, gomock.Any(), entity.MaxUserAddresses).
+			Return(nil, repository.ErrAddressLimit)
+
+		_, err := service.AddAddress(context.Background(), userID, dto.AddressRequest{AddressDetails: validAddressDetails()})
+		assert.ErrorIs(t, err, repository.ErrAddressLimit)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		service, _ := setupAddressService(t)
+		details := validAddressDetails()
+		details.Phone = ""
+
+		_, err := service.AddAddress(context.Background(), userID, dto.AddressRequest{AddressDetails: details})
+		assert.ErrorIs(t, err, usecase.ErrAddressBadRequest)
+	})
+}
+
+func TestAddressService_DeleteAddress(t *testing.T) {
+	addressID, userID := uuid.New(), uuid.New()
+
+	t.Run("DefaultPromotesNewest", func(t *testing.T) {
+		service, deps := setupAddressService(t)
+		tx := deps.expectTx(t, true)
+		deps.addressRepo.EXPECT().Delete(gomock.Any(), tx, addressID, userID).Return(true, nil)
+		deps.addressRepo.EXPECT().SetNewestDefault(gomock.Any(), tx, userID).Return(nil)
+
+		require.NoError(t, service.DeleteAddress(context.Background(), addressID, userID))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		service, deps := setupAddressService(t)
+		tx := deps.expectTx(t, false)
+		deps.addressRepo.EXPECT().Delete(gomock.Any(), tx, addressID, userID).Return(false, repository.ErrAddressNotFound)
+
+		err := service.DeleteAddress(context.Background(), addressID, userID)
+		assert.ErrorIs(t, err, repository.ErrAddressNotFound)
+		deps.assertTxDone(t)
+	})
+}
+
+func TestAddressService_SetDefault(t *testing.T) {
+	addressID, userID := uuid.New(), uuid.New()
+	service, deps := setupAddressService(t)
+	tx := deps.expectTx(t, true)
+	gomock.InOrder(
+		deps.addressRepo.EXPECT().ClearDefault(gomock.Any(), tx, userID).Return(nil),
+		deps.addressRepo.EXPECT().SetDefault(gomock.Any(), tx, addressID, userID).
+			Return(&entity.Address{ID: addressID, UserID: userID, IsDefault: true}, nil),
+	)
+
+	response, err := service.SetDefault(context.Background(), addressID, userID)
+	require.NoError(t, err)
+	assert.True(t, response.IsDefault)
+	deps.assertTxDone(t)
+}
+
+func TestAddressService_ResolveCheckout(t *testing.T) {
+	addressID, userID := uuid.New(), uuid.New()
+
+	t.Run("Saved", func(t *testing.T) {
+		service, deps := setupAddressService(t)
+		deps.addressRepo.EXPECT().GetById(gomock.Any(), addressID, userID).
+			Return(&entity.Address{ID: addressID, UserID: userID, AddressFields: entity.AddressFields{City: "Казань"}}, nil)
+
+		address, err := service.ResolveCheckout(context.Background(), userID, &addressID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, addressID, *address.AddressID)
+		assert.Equal(t, "Казань", address.City)
+	})
+
+	t.Run("SavedNotFound", func(t *testing.T) {
+		service, deps := setupAddressService(t)
+		deps.addressRepo.EXPECT().GetById(gomock.Any(), addressID, userID).Return(nil, repository.ErrAddressNotFound)
+
+		_, err := service.ResolveCheckout(context.Background(), userID, &addressID, nil)
+		assert.ErrorIs(t, err, repository.ErrAddressNotFound)
+	})
+
+	t.Run("Details", func(t *testing.T) {
+		service, _ := setupAddressService(t)
+		details := validAddressDetails()
+
+		address, err := service.ResolveCheckout(context.Background(), userID, nil, &details)
+		require.NoError(t, err)
+		assert.Nil(t, address.AddressID)
+		assert.Equal(t, "+79991234567", address.Phone)
+	})
+
+	t.Run("Both", func(t *testing.T) {
+		service, _ := setupAddressService(t)
+		details := validAddressDetails()
+
+		_, err := service.ResolveCheckout(context.Background(), userID, &addressID, &details)
+		assert.ErrorIs(t, err, usecase.ErrAddressBadRequest)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		service, _ := setupAddressService(t)
+
+		address, err := service.ResolveCheckout(context.Background(), userID, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, address)
+	})
+}
+
+func TestAddressService_GetPurchaseAddresses(t *testing.T) {
+	service, deps := setupAddressService(t)
+	purchaseID, otherID := uuid.New(), uuid.New()
+	deps.addressRepo.EXPECT().GetPurchaseSnapshots(gomock.Any(), []uuid.UUID{purchaseID, otherID}).
+		Return([]*entity.PurchaseAddress{{PurchaseID: purchaseID, AddressFields: entity.AddressFields{City: "Казань"}}}, nil)
+
+	addresses, err := service.GetPurchaseAddresses(context.Background(), []uuid.UUID{purchaseID, otherID})
+	require.NoError(t, err)
+	require.Contains(t, addresses, purchaseID)
+	assert.Equal(t, "Казань", addresses[purchaseID].City)
+	assert.NotContains(t, addresses, otherID)
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxImportRows = 1000
+
+type AdvertImportService struct {
+	advertRepo   repository.AdvertRepository
+	sellerRepo   repository.Seller
+	categoryRepo repository.CategoryRepository
+}
+
+func NewAdvertImportService(advertRepo repository.AdvertRepository,
+	sellerRepo repository.Seller,
+	categoryRepo repository.CategoryRepository) *AdvertImportService {
+	return &AdvertImportService{
+		advertRepo:   advertRepo,
+		sellerRepo:   sellerRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// categoryIndex позволяет найти категорию по идентификатору или по названию без учета регистра
+type categoryIndex struct {
+	byId    map[uuid.UUID]*entity.Category
+	byTitle map[string]*entity.Category
+}
+
+func (s *AdvertImportService) loadCategories(ctx context.Context) (*categoryIndex, error) {
+	categories, err := s.categoryRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &categoryIndex{
+		byId:    make(map[uuid.UUID]*entity.Category, len(categories)),
+		byTitle: make(map[string]*entity.Category, len(categories)),
+	}
+	for _, category := range categories {
+		index.byId[category.ID] = category
+		index.byTitle[strings.ToLower(strings.TrimSpace(category.Title))] = category
+	}
+	return index, nil
+}
+
+func (i *categoryIndex) resolve(value string) (uuid.UUID, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return uuid.Nil, nil
+	}
+	if id, err := uuid.Parse(value); err == nil {
+		if _, ok := i.byId[id]; ok {
+			return id, nil
+		}
+		return uuid.Nil, usecase.ErrImportUnknownCategory
+	}
+	if category, ok := i.byTitle[strings.ToLower(value)]; ok {
+		return category.ID, nil
+	}
+	return uuid.Nil, usecase.ErrImportUnknownCategory
+}
+
+func (s *AdvertImportService) Import(ctx context.Context, userId uuid.UUID, rows []dto.AdvertImportRow, dryRun bool) (*dto.AdvertImportReport, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if len(rows) > maxImportRows {
+		return nil, entity.UsecaseWrap(usecase.ErrImportTooManyRows, usecase.ErrImportTooManyRows)
+	}
+
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, repository.ErrSellerNotFound)
+	}
+
+	categories, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, err)
+	}
+
+	report := &dto.AdvertImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]dto.AdvertImportRowResult, 0, len(rows)),
+	}
+	seenSKU := make(map[string]struct{}, len(rows))
+
+	for i, row := range rows {
+		result := dto.AdvertImportRowResult{
+			Row:         i + 1,
+			ExternalSKU: strings.TrimSpace(row.ExternalSKU),
+		}
+
+		if row.ParseError == "" && isManagedImportStatus(row.Status) {
+			result.Action = dto.AdvertImportActionSkipped
+			result.Error = usecase.ErrImportStatusManaged.Error()
+			report.Skipped++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		advert, err := s.prepareRow(row, seller.ID, categories)
+		if err == nil && advert.ExternalSKU != "" {
+			if _, ok := seenSKU[advert.ExternalSKU]; ok {
+				err = usecase.ErrImportDuplicateSKU
+			}
+			seenSKU[advert.ExternalSKU] = struct{}{}
+		}
+		if err != nil {
+			result.Action = dto.AdvertImportActionFailed
+			result.Error = err.Error()
+			report.Failed++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		if dryRun {
+			result.Action = dto.AdvertImportActionValid
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		var saved *entity.Advert
+		created := true
+		if advert.ExternalSKU != "" {
+			saved, created, err = s.advertRepo.UpsertBySKU(ctx, advert)
+		} else {
+			saved, err = s.advertRepo.Add(ctx, advert)
+		}
+		if err != nil {
+			logger.Error("failed to import advert row", zap.Error(err), zap.Int("row", result.Row))
+			result.Action = dto.AdvertImportActionFailed
+			result.Error = repository.ErrAdvertBadRequest.Error()
+			if errors.Is(err, repository.ErrAdvertSKULocked) {
+				result.Error = usecase.ErrImportAdvertLocked.Error()
+			}
+			report.Failed++
+			report.Rows = append(report.Rows, result)
+			continue
+		}
+
+		result.AdvertId = saved.ID
+		if created {
+			result.Action = dto.AdvertImportActionCreated
+			report.Created++
+		} else {
+			result.Action = dto.AdvertImportActionUpdated
+			report.Updated++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	logger.Info("adverts imported", zap.String("seller_id", seller.ID.String()), zap.Bool("dry_run", dryRun),
+		zap.Int("total", report.Total), zap.Int("created", report.Created),
+		zap.Int("updated", report.Updated), zap.Int("failed", report.Failed), zap.Int("skipped", report.Skipped))
+
+	return report, nil
+}
+
+// isManagedImportStatus сообщает, что статусом объявления управляют покупки или отложенная публикация.
+// Такие строки попадают в экспорт, но при обратном импорте пропускаются
+func isManagedImportStatus(status dto.AdvertStatus) bool {
+	switch status {
+	case dto.AdvertStatusReserved, dto.AdvertStatusScheduled:
+		return true
+	}
+	return false
+}
+
+func (s *AdvertImportService) prepareRow(row dto.AdvertImportRow, sellerId uuid.UUID, categories *categoryIndex) (*entity.Advert, error) {
+	if row.ParseError != "" {
+		return nil, errors.New(row.ParseError)
+	}
+
+	if err := entity.ValidateAdvert(row.Title, row.Description, row.Location, string(row.Status), row.Price); err != nil {
+		return nil, err
+	}
+
+	sku := strings.TrimSpace(row.ExternalSKU)
+	if len(sku) > 100 {
+		return nil, entity.ErrExternalSKULength
+	}
+
+	categoryId, err := categories.resolve(row.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	status := entity.AdvertStatus(row.Status)
+	switch status {
+	case "":
+		status = entity.AdvertStatusActive
+	case entity.AdvertStatusActive, entity.AdvertStatusInactive, entity.AdvertStatusDraft:
+	default:
+		return nil, usecase.ErrImportInvalidStatus
+	}
+
+	if status != entity.AdvertStatusDraft {
+		if err := entity.ValidateAdvertPublishable(row.Title, categoryId); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entity.Advert{
+		SellerId:    sellerId,
+		CategoryId:  categoryId,
+		Title:       strings.TrimSpace(row.Title),
+		Description: strings.TrimSpace(row.Description),
+		Price:       uint(row.Price),
+		Location:    strings.TrimSpace(row.Location),
+		HasDelivery: row.HasDelivery,
+		Status:      status,
+		ExternalSKU: sku,
+	}, nil
+}
+
+func (s *AdvertImportService) Export(ctx context.Context, userId uuid.UUID) ([]dto.AdvertImportRow, error) {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, repository.ErrSellerNotFound)
+	}
+
+	categories, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, err)
+	}
+
+	adverts, err := s.advertRepo.GetForExport(ctx, seller.ID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, err)
+	}
+
+	rows := make([]dto.AdvertImportRow, 0, len(adverts))
+	for _, advert := range adverts {
+		category := ""
+		if c, ok := categories.byId[advert.CategoryId]; ok {
+			category = c.Title
+		}
+		rows = append(rows, dto.AdvertImportRow{
+			ID:          advert.ID,
+			ExternalSKU: advert.ExternalSKU,
+			Title:       advert.Title,
+			Description: advert.Description,
+			Price:       int(advert.Price),
+			Location:    advert.Location,
+			HasDelivery: advert.HasDelivery,
+			Category:    category,
+			Status:      dto.AdvertStatus(advert.Status),
+		})
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdvertImportService(t *testing.T) (*AdvertImportService, *mocks.MockAdvertRepository, *mocks.MockSeller, *mocks.MockCategoryRepository, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	advertRepo := mocks.NewMockAdvertRepository(ctrl)
+	sellerRepo := mocks.NewMockSeller(ctrl)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+
+	service := NewAdvertImportService(advertRepo, sellerRepo, categoryRepo)
+
+	return service, advertRepo, sellerRepo, categoryRepo, ctrl
+}
+
+func TestAdvertImportService_Import(t *testing.T) {
+	service, advertRepo, sellerRepo, categoryRepo, ctrl := setupAdvertImportService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+	category := &entity.Category{ID: uuid.New(), Title: "Электроника"}
+
+	rows := []dto.AdvertImportRow{
+		{ExternalSKU: "SKU-1", Title: "Phone", Price: 100, Category: "электроника"},
+		{Title: "Laptop", Price: 200, Category: category.ID.String()},
+		{ExternalSKU: "SKU-1", Title: "Duplicate", Price: 100, Category: "Электроника"},
+		{Title: "Unknown category", Price: 100, Category: "Мебель"},
+		{Title: "Negative", Price: -1, Category: "Электроника"},
+		{Title: "Broken", ParseError: `invalid price "abc"`},
+	}
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+	categoryRepo.EXPECT().Get(gomock.Any()).Return([]*entity.Category{category}, nil)
+	advertRepo.EXPECT().UpsertBySKU(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, advert *entity.Advert) (*entity.Advert, bool, error) {
+		assert.Equal(t, category.ID, advert.CategoryId)
+		assert.Equal(t, seller.ID, advert.SellerId)
+		assert.Equal(t, "SKU-1", advert.ExternalSKU)
+		advert.ID = uuid.New()
+		return advert, false, nil
+	})
+	advertRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, advert *entity.Advert) (*entity.Advert, error) {
+		assert.Equal(t, entity.AdvertStatusActive, advert.Status)
+		advert.ID = uuid.New()
+		return advert, nil
+	})
+
+	report, err := service.Import(context.Background(), userID, rows, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Updated)
+	assert.Equal(t, 4, report.Failed)
+	assert.Equal(t, dto.AdvertImportActionUpdated, report.Rows[0].Action)
+	assert.Equal(t, dto.AdvertImportActionCreated, report.Rows[1].Action)
+	assert.Equal(t, usecase.ErrImportDuplicateSKU.Error(), report.Rows[2].Error)
+	assert.Equal(t, usecase.ErrImportUnknownCategory.Error(), report.Rows[3].Error)
+	assert.Equal(t, entity.ErrPriceNegative.Error(), report.Rows[4].Error)
+	assert.Equal(t, `invalid price "abc"`, report.Rows[5].Error)
+}
+
+func TestAdvertImportService_ImportDryRun(t *testing.T) {
+	service, _, sellerRepo, categoryRepo, ctrl := setupAdvertImportService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	category := &entity.Category{ID: uuid.New(), Title: "Книги"}
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: uuid.New()}, nil)
+	categoryRepo.EXPECT().Get(gomock.Any()).Return([]*entity.Category{category}, nil)
+
+	report, err := service.Import(context.Background(), userID, []dto.AdvertImportRow{
+		{ExternalSKU: "B-1", Title: "Book", Price: 10, Category: "Книги"},
+		{Title: "Draft without category", Status: dto.AdvertStatusDraft},
+	}, true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 0, report.Failed)
+	for _, row := range report.Rows {
+		assert.Equal(t, dto.AdvertImportActionValid, row.Action)
+	}
+}
+
+func TestAdvertImportService_ImportManagedStatuses(t *testing.T) {
+	service, advertRepo, sellerRepo, categoryRepo, ctrl := setupAdvertImportService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	category := &entity.Category{ID: uuid.New(), Title: "Книги"}
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: uuid.New()}, nil)
+	categoryRepo.EXPECT().Get(gomock.Any()).Return([]*entity.Category{category}, nil)
+	advertRepo.EXPECT().UpsertBySKU(gomock.Any(), gomock.Any()).Return(nil, false, entity.PSQLWrap(repository.ErrAdvertSKULocked))
+
+	report, err := service.Import(context.Background(), userID, []dto.AdvertImportRow{
+		{ExternalSKU: "B-1", Title: "Book", Price: 10, Category: "Книги", Status: dto.AdvertStatusReserved},
+		{ExternalSKU: "B-2", Title: "Book", Price: 10, Category: "Книги", Status: dto.AdvertStatusScheduled},
+		{ExternalSKU: "B-3", Title: "Book", Price: 10, Category: "Книги"},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, dto.AdvertImportActionSkipped, report.Rows[0].Action)
+	assert.Equal(t, usecase.ErrImportStatusManaged.Error(), report.Rows[1].Error)
+	assert.Equal(t, usecase.ErrImportAdvertLocked.Error(), report.Rows[2].Error)
+}
+
+func TestAdvertImportService_ImportTooManyRows(t *testing.T) {
+	service, _, _, _, ctrl := setupAdvertImportService(t)
+	defer ctrl.Finish()
+
+	_, err := service.Import(context.Background(), uuid.New(), make([]dto.AdvertImportRow, maxImportRows+1), false)
+	assert.ErrorIs(t, err, usecase.ErrImportTooManyRows)
+}
+
+func TestAdvertImportService_Export(t *testing.T) {
+	service, advertRepo, sellerRepo, categoryRepo, ctrl := setupAdvertImportService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+	category := &entity.Category{ID: uuid.New(), Title: "Одежда"}
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+	categoryRepo.EXPECT().Get(gomock.Any()).Return([]*entity.Category{category}, nil)
+	advertRepo.EXPECT().GetForExport(gomock.Any(), seller.ID).Return([]*entity.Advert{
+		{ID: uuid.New(), ExternalSKU: "C-1", Title: "Coat", Price: 500, CategoryId: category.ID, Status: entity.AdvertStatusActive},
+	}, nil)
+
+	rows, err := service.Export(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, "Одежда", rows[0].Category)
+	assert.Equal(t, "C-1", rows[0].ExternalSKU)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LogExpiryNotifier пишет напоминания об истечении срока объявлений в лог,
+// пока у сервиса нет отдельного канала уведомлений
+type LogExpiryNotifier struct{}
+
+func NewLogExpiryNotifier() *LogExpiryNotifier {
+	return &LogExpiryNotifier{}
+}
+
+func (n *LogExpiryNotifier) NotifyExpiring(ctx context.Context, sellerId, advertId uuid.UUID, title string, expiresAt time.Time) error {
+	logger := middleware.GetLogger(ctx)
+	logger.Info("advert expires soon",
+		zap.String("seller_id", sellerId.String()),
+		zap.String("advert_id", advertId.String()),
+		zap.String("title", title),
+		zap.Time("expires_at", expiresAt))
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type AdvertVideoService struct {
+	videoRepo  repository.AdvertVideoRepository
+	advertRepo repository.AdvertRepository
+	sellerRepo repository.Seller
+}
+
+func NewAdvertVideoService(videoRepo repository.AdvertVideoRepository,
+	advertRepo repository.AdvertRepository,
+	sellerRepo repository.Seller) *AdvertVideoService {
+	return &AdvertVideoService{
+		videoRepo:  videoRepo,
+		advertRepo: advertRepo,
+		sellerRepo: sellerRepo,
+	}
+}
+
+// checkOwner проверяет, что объявление принадлежит продавцу-пользователю userID
+func (s *AdvertVideoService) checkOwner(ctx context.Context, advertID, userID uuid.UUID) error {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSellerNotFound) {
+			return usecase.ErrAdvertVideoForbidden
+		}
+		return entity.UsecaseWrap(err, errors.New("error getting seller"))
+	}
+
+	advert, err := s.advertRepo.GetById(ctx, advertID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdvertNotFound) {
+			return usecase.ErrAdvertVideoAdvertNotFound
+		}
+		return entity.UsecaseWrap(err, errors.New("error getting advert"))
+	}
+	if advert.SellerId != seller.ID {
+		return usecase.ErrAdvertVideoForbidden
+	}
+	return nil
+}
+
+func (s *AdvertVideoService) AddVideo(ctx context.Context, advertID, videoID, userID uuid.UUID) (*dto.AdvertVideo, error) {
+	if err := s.checkOwner(ctx, advertID, userID); err != nil {
+		return nil, err
+	}
+
+	video, err := s.videoRepo.Add(ctx, advertID, videoID, entity.MaxAdvertVideos)
+	switch {
+	case errors.Is(err, repository.ErrAdvertVideoLimit):
+		return nil, usecase.ErrAdvertVideoLimit
+	case errors.Is(err, repository.ErrAdvertNotFound):
+		return nil, usecase.ErrAdvertVideoAdvertNotFound
+	case err != nil:
+		return nil, entity.UsecaseWrap(err, errors.New("error adding advert video"))
+	}
+
+	middleware.GetLogger(ctx).Info("advert video added",
+		zap.String("advert_id", advertID.String()), zap.String("video_id", videoID.String()))
+	return &dto.AdvertVideo{ID: video.VideoID, Position: video.Position}, nil
+}
+
+func (s *AdvertVideoService) GetVideos(ctx context.Context, advertID uuid.UUID) ([]*dto.AdvertVideo, error) {
+	exists, err := s.advertRepo.CheckIfExists(ctx, advertID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error checking advert"))
+	}
+	if !exists {
+		return nil, usecase.ErrAdvertVideoAdvertNotFound
+	}
+
+	videos, err := s.videoRepo.GetByAdvertID(ctx, advertID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting advert videos"))
+	}
+
+	result := make([]*dto.AdvertVideo, 0, len(videos))
+	for _, video := range videos {
+		result = append(result, &dto.AdvertVideo{ID: video.VideoID, Position: video.Position})
+	}
+	return result, nil
+}
+
+func (s *AdvertVideoService) DeleteVideo(ctx context.Context, advertID, videoID, userID uuid.UUID) error {
+	if err := s.checkOwner(ctx, advertID, userID); err != nil {
+		return err
+	}
+
+	err := s.videoRepo.Delete(ctx, advertID, videoID)
+	switch {
+	case errors.Is(err, repository.ErrAdvertVideoNotFound):
+		return usecase.ErrAdvertVideoNotFound
+	case err != nil:
+		return entity.UsecaseWrap(err, errors.New("error deleting advert video"))
+	}
+	return nil
+}
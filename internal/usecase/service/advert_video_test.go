@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+)
+
+type advertVideoTest struct {
+	service    *AdvertVideoService
+	videoRepo  *mocks.MockAdvertVideoRepository
+	advertRepo *mocks.MockAdvertRepository
+	sellerRepo *mocks.MockSeller
+}
+
+func setupAdvertVideoTest(t *testing.T) advertVideoTest {
+	ctrl := gomock.NewController(t)
+	test := advertVideoTest{
+		videoRepo:  mocks.NewMockAdvertVideoRepository(ctrl),
+		advertRepo: mocks.NewMockAdvertRepository(ctrl),
+		sellerRepo: mocks.NewMockSeller(ctrl),
+	}
+	test.service = NewAdvertVideoService(test.videoRepo, test.advertRepo, test.sellerRepo)
+	return test
+}
+
+func (test advertVideoTest) expectAdvert(advertID, userID, sellerID, ownerID uuid.UUID) {
+	test.sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+	test.advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: ownerID}, nil)
+}
+
+func TestAdvertVideoService_AddVideo(t *testing.T) {
+	advertID, videoID, userID, sellerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		test := setupAdvertVideoTest(t)
+		test.expectAdvert(advertID, userID, sellerID, sellerID)
+		test.videoRepo.EXPECT().Add(gomock.Any(), advertID, videoID, entity.MaxAdvertVideos).
+			Return(&entity.AdvertVideo{AdvertID: advertID, VideoID: videoID, Position: 1, CreatedAt: time.Now()}, nil)
+
+		video, err := test.service.AddVideo(context.Background(), advertID, videoID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, videoID, video.ID)
+		assert.Equal(t, 1, video.Position)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		test := setupAdvertVideoTest(t)
+		test.expectAdvert(advertID, userID, sellerID, uuid.New())
+
+		_, err := test.service.AddVideo(context.Background(), advertID, videoID, userID)
+		assert.ErrorIs(t, err, usecase.ErrAdvertVideoForbidden)
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		test := setupAdvertVideoTest(t)
+		test.expectAdvert(advertID, userID, sellerID, sellerID)
+		test.videoRepo.EXPECT().Add(gomock.Any(), advertID, videoID, entity.MaxAdvertVideos).
+			Return(nil, entity.PSQLWrap(repository.ErrAdvertVideoLimit))
+
+		_, err := test.service.AddVideo(context.Background(), advertID, videoID, userID)
+		assert.ErrorIs(t, err, usecase.ErrAdvertVideoLimit)
+	})
+}
+
+func TestAdvertVideoService_GetVideos(t *testing.T) {
+	advertID, videoID := uuid.New(), uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		test := setupAdvertVideoTest(t)
+		test.advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(true, nil)
+		test.videoRepo.EXPECT().GetByAdvertID(gomock.Any(), advertID).
+			Return([]*entity.AdvertVideo{{AdvertID: advertID, VideoID: videoID}}, nil)
+
+		videos, err := test.service.GetVideos(context.Background(), advertID)
+		assert.NoError(t, err)
+		assert.Len(t, videos, 1)
+		assert.Equal(t, videoID, videos[0].ID)
+	})
+
+	t.Run("AdvertNotFound", func(t *testing.T) {
+		test := setupAdvertVideoTest(t)
+		test.advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(false, nil)
+
+		_, err := test.service.GetVideos(context.Background(), advertID)
+		assert.ErrorIs(t, err, usecase.ErrAdvertVideoAdvertNotFound)
+	})
+}
+
+func TestAdvertVideoService_DeleteVideo(t *testing.T) {
+	test := setupAdvertVideoTest(t)
+	advertID, videoID, userID, sellerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	test.expectAdvert(advertID, userID, sellerID, sellerID)
+	test.videoRepo.EXPECT().Delete(gomock.Any(), advertID, videoID).Return(entity.PSQLWrap(repository.ErrAdvertVideoNotFound))
+
+	err := test.service.DeleteVideo(context.Background(), advertID, videoID, userID)
+	assert.ErrorIs(t, err, usecase.ErrAdvertVideoNotFound)
+}
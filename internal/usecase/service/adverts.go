@@ -5,11 +5,13 @@ import (
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -22,23 +24,34 @@ var (
 )
 
 type AdvertService struct {
-	advertRepo repository.AdvertRepository
-	sellerRepo repository.Seller
-	userRepo   repository.User
+	advertRepo     repository.AdvertRepository
+	sellerRepo     repository.Seller
+	userRepo       repository.User
+	viewCounter    repository.ViewCounter
+	notifier       usecase.AdvertExpiryNotifier
+	bumpCooldown   time.Duration
+	expiryReminder time.Duration
 }
 
 func NewAdvertService(advertRepo repository.AdvertRepository,
 	sellerRepo repository.Seller,
-	userRepo repository.User) *AdvertService {
+	userRepo repository.User,
+	viewCounter repository.ViewCounter,
+	notifier usecase.AdvertExpiryNotifier,
+	bumpCooldown, expiryReminder time.Duration) *AdvertService {
 	return &AdvertService{
-		advertRepo: advertRepo,
-		sellerRepo: sellerRepo,
-		userRepo:   userRepo,
+		advertRepo:     advertRepo,
+		sellerRepo:     sellerRepo,
+		userRepo:       userRepo,
+		viewCounter:    viewCounter,
+		notifier:       notifier,
+		bumpCooldown:   bumpCooldown,
+		expiryReminder: expiryReminder,
 	}
 }
 
-func (s *AdvertService) Get(limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
-	adverts, err := s.advertRepo.Get(limit, offset, userId)
+func (s *AdvertService) Get(ctx context.Context, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+	adverts, err := s.advertRepo.Get(ctx, limit, offset, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -66,13 +79,13 @@ func (s *AdvertService) Get(limit, offset int, userId uuid.UUID) ([]*dto.Preview
 	return dtoAdverts, nil
 }
 
-func (s *AdvertService) GetByUserId(userId uuid.UUID) ([]*dto.MyPreviewAdvertCard, error) {
-	seller, err := s.sellerRepo.GetByUserId(userId)
+func (s *AdvertService) GetByUserId(ctx context.Context, userId uuid.UUID) ([]*dto.MyPreviewAdvertCard, error) {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, repository.ErrSellerNotFound)
 	}
 
-	adverts, err := s.advertRepo.GetByUserId(seller.ID, userId)
+	adverts, err := s.advertRepo.GetByUserId(ctx, seller.ID, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -93,14 +106,17 @@ func (s *AdvertService) GetByUserId(userId uuid.UUID) ([]*dto.MyPreviewAdvertCar
 			},
 			ViewsNumber: advert.ViewsNumber,
 			SavesNumber: advert.SavesNumber,
+			ExpiresAt:   advert.ExpiresAt,
+			BumpedAt:    advert.BumpedAt,
+			PublishAt:   advert.PublishAt,
 		}
 		dtoAdverts = append(dtoAdverts, &advertDTO)
 	}
 	return dtoAdverts, nil
 }
 
-func (s *AdvertService) GetByCartId(cartId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
-	adverts, err := s.advertRepo.GetByCartId(cartId, userId)
+func (s *AdvertService) GetByCartId(ctx context.Context, cartId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+	adverts, err := s.advertRepo.GetByCartId(ctx, cartId, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -128,8 +144,8 @@ func (s *AdvertService) GetByCartId(cartId, userId uuid.UUID) ([]*dto.PreviewAdv
 	return dtoAdverts, nil
 }
 
-func (s *AdvertService) GetById(advertId, userId uuid.UUID) (*dto.AdvertCard, error) {
-	advert, err := s.advertRepo.GetById(advertId, userId)
+func (s *AdvertService) GetById(ctx context.Context, advertId, userId uuid.UUID) (*dto.AdvertCard, error) {
+	advert, err := s.advertRepo.GetById(ctx, advertId, userId)
 
 	if err != nil {
 		if errors.Is(err, repository.ErrAdvertNotFound) {
@@ -138,6 +154,10 @@ func (s *AdvertService) GetById(advertId, userId uuid.UUID) (*dto.AdvertCard, er
 		return nil, entity.UsecaseWrap(err, err)
 	}
 
+	if !advert.Status.IsPublic() && !s.isOwner(ctx, advert, userId) {
+		return nil, entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
+	}
+
 	advertDTO := dto.AdvertCard{
 		Advert: dto.Advert{
 			ID:          advert.ID,
@@ -152,6 +172,9 @@ func (s *AdvertService) GetById(advertId, userId uuid.UUID) (*dto.AdvertCard, er
 			Location:    advert.Location,
 			CreatedAt:   advert.CreatedAt,
 			UpdatedAt:   advert.UpdatedAt,
+			ExpiresAt:   advert.ExpiresAt,
+			BumpedAt:    advert.BumpedAt,
+			PublishAt:   advert.PublishAt,
 			ViewsNumber: advert.ViewsNumber,
 			SavesNumber: advert.SavesNumber,
 		},
@@ -161,7 +184,7 @@ func (s *AdvertService) GetById(advertId, userId uuid.UUID) (*dto.AdvertCard, er
 	return &advertDTO, nil
 }
 
-func (s *AdvertService) Add(advert *dto.AdvertRequest, userId uuid.UUID) (*dto.Advert, error) {
+func (s *AdvertService) Add(ctx context.Context, advert *dto.AdvertRequest, userId uuid.UUID) (*dto.Advert, error) {
 	if err := entity.ValidateAdvert(advert.Title,
 		advert.Description,
 		advert.Location,
@@ -170,20 +193,26 @@ func (s *AdvertService) Add(advert *dto.AdvertRequest, userId uuid.UUID) (*dto.A
 		return nil, entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
 	}
 
-	seller, err := s.sellerRepo.GetByUserId(userId)
+	status, publishAt, err := resolvePublication(advert)
+	if err != nil {
+		return nil, err
+	}
+
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, repository.ErrSellerNotFound)
 	}
 
-	entityAdvert, err := s.advertRepo.Add(&entity.Advert{
+	entityAdvert, err := s.advertRepo.Add(ctx, &entity.Advert{
 		SellerId:    seller.ID,
 		CategoryId:  advert.CategoryId,
 		Title:       strings.TrimSpace(advert.Title),
 		Description: strings.TrimSpace(advert.Description),
 		Price:       advert.Price,
-		Status:      entity.AdvertStatus(advert.Status),
+		Status:      status,
 		HasDelivery: advert.HasDelivery,
 		Location:    advert.Location,
+		PublishAt:   publishAt,
 	})
 	if err != nil {
 		return nil, entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
@@ -202,13 +231,16 @@ func (s *AdvertService) Add(advert *dto.AdvertRequest, userId uuid.UUID) (*dto.A
 		ImageId:     entityAdvert.ImageId,
 		CreatedAt:   entityAdvert.CreatedAt,
 		UpdatedAt:   entityAdvert.UpdatedAt,
+		ExpiresAt:   entityAdvert.ExpiresAt,
+		BumpedAt:    entityAdvert.BumpedAt,
+		PublishAt:   entityAdvert.PublishAt,
 		ViewsNumber: entityAdvert.ViewsNumber,
 		SavesNumber: entityAdvert.SavesNumber,
 	}
 	return &advertDTO, nil
 }
 
-func (s *AdvertService) Update(advert *dto.AdvertRequest, userId uuid.UUID, advertId uuid.UUID) error {
+func (s *AdvertService) Update(ctx context.Context, advert *dto.AdvertRequest, userId uuid.UUID, advertId uuid.UUID) error {
 	if err := entity.ValidateAdvert(advert.Title,
 		advert.Description,
 		advert.Location,
@@ -217,12 +249,12 @@ func (s *AdvertService) Update(advert *dto.AdvertRequest, userId uuid.UUID, adve
 		return entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
 	}
 
-	seller, err := s.sellerRepo.GetByUserId(userId)
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrSellerNotFound)
 	}
 
-	existingAdvert, err := s.advertRepo.GetById(advertId, userId)
+	existingAdvert, err := s.advertRepo.GetById(ctx, advertId, userId)
 	if err != nil {
 		if errors.Is(err, repository.ErrAdvertNotFound) {
 			return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
@@ -233,17 +265,27 @@ func (s *AdvertService) Update(advert *dto.AdvertRequest, userId uuid.UUID, adve
 		return entity.UsecaseWrap(ErrForbidden, ErrForbidden)
 	}
 
-	err = s.advertRepo.Update(&entity.Advert{
+	status, publishAt, err := resolveUpdate(existingAdvert, advert)
+	if err != nil {
+		return err
+	}
+
+	err = s.advertRepo.Update(ctx, &entity.Advert{
 		ID:          advertId,
 		SellerId:    seller.ID,
 		CategoryId:  advert.CategoryId,
 		Title:       strings.TrimSpace(advert.Title),
 		Description: strings.TrimSpace(advert.Description),
 		Price:       advert.Price,
-		Status:      entity.AdvertStatus(advert.Status),
+		Status:      status,
 		HasDelivery: advert.HasDelivery,
 		Location:    advert.Location,
-	})
+		PublishAt:   publishAt,
+	}, existingAdvert.Status)
+	if errors.Is(err, repository.ErrAdvertNotFound) {
+		// объявление найдено выше, значит статус успели изменить: например, его зарезервировала покупка
+		return entity.UsecaseWrap(usecase.ErrAdvertStatusChanged, usecase.ErrAdvertStatusChanged)
+	}
 	if err != nil {
 		return entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
 	}
@@ -251,13 +293,13 @@ func (s *AdvertService) Update(advert *dto.AdvertRequest, userId uuid.UUID, adve
 	return nil
 }
 
-func (s *AdvertService) DeleteById(advertId uuid.UUID, userId uuid.UUID) error {
-	seller, err := s.sellerRepo.GetByUserId(userId)
+func (s *AdvertService) DeleteById(ctx context.Context, advertId uuid.UUID, userId uuid.UUID) error {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrSellerNotFound)
 	}
 
-	existingAdvert, err := s.advertRepo.GetById(advertId, userId)
+	existingAdvert, err := s.advertRepo.GetById(ctx, advertId, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrAdvertNotFound)
 	}
@@ -265,7 +307,7 @@ func (s *AdvertService) DeleteById(advertId uuid.UUID, userId uuid.UUID) error {
 		return entity.UsecaseWrap(ErrForbidden, ErrForbidden)
 	}
 
-	if err := s.advertRepo.DeleteById(advertId); err != nil {
+	if err := s.advertRepo.DeleteById(ctx, advertId); err != nil {
 		if errors.Is(err, repository.ErrAdvertNotFound) {
 			return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
 		}
@@ -275,9 +317,8 @@ func (s *AdvertService) DeleteById(advertId uuid.UUID, userId uuid.UUID) error {
 	return nil
 }
 
-func (s *AdvertService) UpdateStatus(advertId, userId uuid.UUID, status dto.AdvertStatus) error {
-	ctx := context.Background()
-	tx, err := s.advertRepo.BeginTransaction()
+func (s *AdvertService) UpdateStatus(ctx context.Context, advertId, userId uuid.UUID, status dto.AdvertStatus) error {
+	tx, err := s.advertRepo.BeginTransaction(ctx)
 	if err != nil {
 		logger := middleware.GetLogger(ctx)
 		logger.Error("failed to begin transaction", zap.Error(err))
@@ -291,20 +332,23 @@ func (s *AdvertService) UpdateStatus(advertId, userId uuid.UUID, status dto.Adve
 		}
 	}()
 
-	seller, err := s.sellerRepo.GetByUserId(userId)
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrSellerNotFound)
 	}
 
-	existingAdvert, err := s.advertRepo.GetById(advertId, userId)
+	existingAdvert, err := s.advertRepo.GetById(ctx, advertId, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrAdvertNotFound)
 	}
 	if existingAdvert.SellerId != seller.ID {
 		return entity.UsecaseWrap(ErrForbidden, ErrForbidden)
 	}
+	if !existingAdvert.Status.IsPublic() {
+		return entity.UsecaseWrap(ErrAdvertBadRequest, usecase.ErrAdvertNotPublished)
+	}
 
-	if err := s.advertRepo.UpdateStatus(tx, advertId, entity.AdvertStatus(status)); err != nil {
+	if err := s.advertRepo.UpdateStatus(ctx, tx, advertId, entity.AdvertStatus(status)); err != nil {
 		if errors.Is(err, repository.ErrAdvertNotFound) {
 			return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
 		}
@@ -314,8 +358,8 @@ func (s *AdvertService) UpdateStatus(advertId, userId uuid.UUID, status dto.Adve
 	return nil
 }
 
-func (s *AdvertService) GetByCategoryId(categoryId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
-	adverts, err := s.advertRepo.GetByCategoryId(categoryId, userId)
+func (s *AdvertService) GetByCategoryId(ctx context.Context, categoryId, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+	adverts, err := s.advertRepo.GetByCategoryId(ctx, categoryId, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -343,13 +387,13 @@ func (s *AdvertService) GetByCategoryId(categoryId, userId uuid.UUID) ([]*dto.Pr
 	return dtoAdverts, nil
 }
 
-func (s *AdvertService) UploadImage(advertId uuid.UUID, imageId uuid.UUID, userId uuid.UUID) error {
-	seller, err := s.sellerRepo.GetByUserId(userId)
+func (s *AdvertService) UploadImage(ctx context.Context, advertId uuid.UUID, imageId uuid.UUID, userId uuid.UUID) error {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrSellerNotFound)
 	}
 
-	existingAdvert, err := s.advertRepo.GetById(advertId, userId)
+	existingAdvert, err := s.advertRepo.GetById(ctx, advertId, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, repository.ErrAdvertNotFound)
 	}
@@ -357,15 +401,18 @@ func (s *AdvertService) UploadImage(advertId uuid.UUID, imageId uuid.UUID, userI
 		return entity.UsecaseWrap(ErrForbidden, ErrForbidden)
 	}
 
-	if err := s.advertRepo.UploadImage(advertId, imageId); err != nil {
+	if err := s.advertRepo.UploadImage(ctx, advertId, imageId); err != nil {
+		if errors.Is(err, repository.ErrAdvertImageFlagged) {
+			return entity.UsecaseWrap(usecase.ErrAdvertImageOnModeration, usecase.ErrAdvertImageOnModeration)
+		}
 		return entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
 	}
 
 	return nil
 }
 
-func (s *AdvertService) GetSavedByUserId(userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
-	adverts, err := s.advertRepo.GetSavedByUserId(userId)
+func (s *AdvertService) GetSavedByUserId(ctx context.Context, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+	adverts, err := s.advertRepo.GetSavedByUserId(ctx, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -393,8 +440,8 @@ func (s *AdvertService) GetSavedByUserId(userId uuid.UUID) ([]*dto.PreviewAdvert
 	return dtoAdverts, nil
 }
 
-func (s *AdvertService) AddToSaved(advertId, userId uuid.UUID) error {
-	exists, err := s.advertRepo.CheckIfExists(advertId)
+func (s *AdvertService) AddToSaved(ctx context.Context, advertId, userId uuid.UUID) error {
+	exists, err := s.advertRepo.CheckIfExists(ctx, advertId)
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -402,7 +449,7 @@ func (s *AdvertService) AddToSaved(advertId, userId uuid.UUID) error {
 		return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
 	}
 
-	exists, err = s.userRepo.CheckIfExists(userId)
+	exists, err = s.userRepo.CheckIfExists(ctx, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -410,7 +457,7 @@ func (s *AdvertService) AddToSaved(advertId, userId uuid.UUID) error {
 		return entity.UsecaseWrap(repository.ErrUserNotFound, repository.ErrUserNotFound)
 	}
 
-	err = s.advertRepo.AddToSaved(advertId, userId)
+	err = s.advertRepo.AddToSaved(ctx, advertId, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -418,8 +465,8 @@ func (s *AdvertService) AddToSaved(advertId, userId uuid.UUID) error {
 	return nil
 }
 
-func (s *AdvertService) RemoveFromSaved(advertId, userId uuid.UUID) error {
-	exists, err := s.advertRepo.CheckIfExists(advertId)
+func (s *AdvertService) RemoveFromSaved(ctx context.Context, advertId, userId uuid.UUID) error {
+	exists, err := s.advertRepo.CheckIfExists(ctx, advertId)
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -427,7 +474,7 @@ func (s *AdvertService) RemoveFromSaved(advertId, userId uuid.UUID) error {
 		return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
 	}
 
-	err = s.advertRepo.DeleteFromSaved(advertId, userId)
+	err = s.advertRepo.DeleteFromSaved(ctx, advertId, userId)
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -435,8 +482,12 @@ func (s *AdvertService) RemoveFromSaved(advertId, userId uuid.UUID) error {
 	return nil
 }
 
-func (s *AdvertService) AddViewed(advertId, userId uuid.UUID) error {
-	exists, err := s.advertRepo.CheckIfExists(advertId)
+func (s *AdvertService) AddViewed(ctx context.Context, advertId, userId uuid.UUID, visitorId string) error {
+	if userId == uuid.Nil && visitorId == "" {
+		return nil
+	}
+
+	exists, err := s.advertRepo.CheckIfExists(ctx, advertId)
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -444,7 +495,12 @@ func (s *AdvertService) AddViewed(advertId, userId uuid.UUID) error {
 		return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
 	}
 
-	err = s.advertRepo.AddViewed(userId, advertId)
+	_, err = s.viewCounter.RegisterView(ctx, entity.AdvertView{
+		AdvertId:  advertId,
+		UserId:    userId,
+		VisitorId: visitorId,
+		ViewedAt:  time.Now(),
+	})
 	if err != nil {
 		return entity.UsecaseWrap(err, err)
 	}
@@ -452,8 +508,8 @@ func (s *AdvertService) AddViewed(advertId, userId uuid.UUID) error {
 	return nil
 }
 
-func (s *AdvertService) GetBySellerId(userId, sellerId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
-	adverts, err := s.advertRepo.GetBySellerId(sellerId, userId)
+func (s *AdvertService) GetBySellerId(ctx context.Context, userId, sellerId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+	adverts, err := s.advertRepo.GetBySellerId(ctx, sellerId, userId)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -481,8 +537,8 @@ func (s *AdvertService) GetBySellerId(userId, sellerId uuid.UUID) ([]*dto.Previe
 	return dtoAdverts, nil
 }
 
-func (s *AdvertService) Search(query string, batchSize, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
-	totalAdverts, err := s.advertRepo.Count()
+func (s *AdvertService) Search(ctx context.Context, query string, batchSize, limit, offset int, userId uuid.UUID) ([]*dto.PreviewAdvertCard, error) {
+	totalAdverts, err := s.advertRepo.Count(ctx)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -504,7 +560,7 @@ func (s *AdvertService) Search(query string, batchSize, limit, offset int, userI
 
 			batchOffset := batchNumber * batchSize
 
-			adverts, err := s.advertRepo.Search(query, batchSize, batchOffset, userId)
+			adverts, err := s.advertRepo.Search(ctx, query, batchSize, batchOffset, userId)
 			if err != nil {
 				return
 			}
@@ -543,3 +599,256 @@ func (s *AdvertService) Search(query string, batchSize, limit, offset int, userI
 
 	return dtoAdverts, nil
 }
+
+func (s *AdvertService) checkOwner(ctx context.Context, advertId, userId uuid.UUID) error {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return entity.UsecaseWrap(err, repository.ErrSellerNotFound)
+	}
+
+	existingAdvert, err := s.advertRepo.GetById(ctx, advertId, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdvertNotFound) {
+			return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
+		}
+		return entity.UsecaseWrap(err, err)
+	}
+	if existingAdvert.SellerId != seller.ID {
+		return entity.UsecaseWrap(ErrForbidden, ErrForbidden)
+	}
+
+	return nil
+}
+
+func (s *AdvertService) Renew(ctx context.Context, advertId, userId uuid.UUID) error {
+	if err := s.checkOwner(ctx, advertId, userId); err != nil {
+		return err
+	}
+
+	if err := s.advertRepo.Renew(ctx, advertId); err != nil {
+		if errors.Is(err, repository.ErrAdvertNotRenewable) {
+			return entity.UsecaseWrap(usecase.ErrAdvertNotRenewable, usecase.ErrAdvertNotRenewable)
+		}
+		return entity.UsecaseWrap(err, err)
+	}
+
+	return nil
+}
+
+func (s *AdvertService) Bump(ctx context.Context, advertId, userId uuid.UUID) error {
+	if err := s.checkOwner(ctx, advertId, userId); err != nil {
+		return err
+	}
+
+	if err := s.advertRepo.Bump(ctx, advertId, s.bumpCooldown); err != nil {
+		if errors.Is(err, repository.ErrAdvertBumpCooldown) {
+			return entity.UsecaseWrap(usecase.ErrAdvertBumpCooldown, usecase.ErrAdvertBumpCooldown)
+		}
+		return entity.UsecaseWrap(err, err)
+	}
+
+	return nil
+}
+
+func (s *AdvertService) ExpireOutdated(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	expired, err := s.advertRepo.ExpireOutdated(ctx)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	if expired > 0 {
+		logger.Info("outdated adverts expired", zap.Int64("count", expired))
+	}
+	return nil
+}
+
+func (s *AdvertService) RemindExpiring(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	adverts, err := s.advertRepo.GetExpiringUnreminded(ctx, s.expiryReminder)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	// отметка ставится после отправки, чтобы неотправленное напоминание повторилось в следующий запуск
+	for _, advert := range adverts {
+		if err := s.notifier.NotifyExpiring(ctx, advert.SellerId, advert.ID, advert.Title, advert.ExpiresAt); err != nil {
+			logger.Error("failed to notify seller about expiring advert", zap.Error(err),
+				zap.String("advert_id", advert.ID.String()))
+			continue
+		}
+		if _, err := s.advertRepo.MarkReminded(ctx, advert.ID); err != nil {
+			logger.Error("failed to mark advert as reminded", zap.Error(err),
+				zap.String("advert_id", advert.ID.String()))
+		}
+	}
+
+	return nil
+}
+
+func (s *AdvertService) isOwner(ctx context.Context, advert *entity.Advert, userId uuid.UUID) bool {
+	if userId == uuid.Nil {
+		return false
+	}
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return false
+	}
+	return seller.ID == advert.SellerId
+}
+
+// resolvePublication определяет статус и время публикации объявления из запроса.
+// Черновик может быть незаполненным, остальные статусы требуют готового к публикации объявления.
+func resolvePublication(advert *dto.AdvertRequest) (entity.AdvertStatus, *time.Time, error) {
+	status := entity.AdvertStatus(advert.Status)
+	if status == "" {
+		status = entity.AdvertStatusActive
+	}
+	if advert.PublishAt != nil && status == entity.AdvertStatusActive && advert.PublishAt.After(time.Now()) {
+		status = entity.AdvertStatusScheduled
+	}
+
+	switch status {
+	case entity.AdvertStatusDraft:
+		return status, nil, nil
+	case entity.AdvertStatusScheduled:
+		if advert.PublishAt == nil || !advert.PublishAt.After(time.Now()) {
+			return "", nil, entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
+		}
+	}
+
+	if err := entity.ValidateAdvertPublishable(advert.Title, advert.CategoryId); err != nil {
+		return "", nil, entity.UsecaseWrap(ErrAdvertBadRequest, err)
+	}
+
+	if status == entity.AdvertStatusScheduled {
+		return status, advert.PublishAt, nil
+	}
+	return status, nil, nil
+}
+
+// resolveUpdate возвращает статус и время публикации объявления после редактирования.
+// Статус опубликованного объявления не меняется: резервирование, истечение срока и публикация
+// управляются отдельными операциями. Неопубликованное объявление можно перевести только
+// между черновиком и запланированной публикацией
+func resolveUpdate(existing *entity.Advert, advert *dto.AdvertRequest) (entity.AdvertStatus, *time.Time, error) {
+	switch existing.Status {
+	case entity.AdvertStatusReserved:
+		return "", nil, entity.UsecaseWrap(usecase.ErrAdvertReserved, usecase.ErrAdvertReserved)
+	case entity.AdvertStatusDraft, entity.AdvertStatusScheduled:
+	default:
+		if err := entity.ValidateAdvertPublishable(advert.Title, advert.CategoryId); err != nil {
+			return "", nil, entity.UsecaseWrap(ErrAdvertBadRequest, err)
+		}
+		return existing.Status, existing.PublishAt, nil
+	}
+
+	status := entity.AdvertStatus(advert.Status)
+	if status == "" {
+		status = existing.Status
+	}
+	switch status {
+	case entity.AdvertStatusDraft:
+		return status, nil, nil
+	case entity.AdvertStatusScheduled:
+		publishAt := advert.PublishAt
+		if publishAt == nil {
+			publishAt = existing.PublishAt
+		}
+		if publishAt == nil || !publishAt.After(time.Now()) {
+			return "", nil, entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
+		}
+		if err := entity.ValidateAdvertPublishable(advert.Title, advert.CategoryId); err != nil {
+			return "", nil, entity.UsecaseWrap(ErrAdvertBadRequest, err)
+		}
+		return status, publishAt, nil
+	case entity.AdvertStatusActive:
+		// публикация сбрасывает срок и время поднятия, поэтому идет только через Publish
+		return "", nil, entity.UsecaseWrap(usecase.ErrAdvertNotPublished, usecase.ErrAdvertNotPublished)
+	default:
+		return "", nil, entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
+	}
+}
+
+func (s *AdvertService) Publish(ctx context.Context, advertId, userId uuid.UUID, publishAt *time.Time) error {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return entity.UsecaseWrap(err, repository.ErrSellerNotFound)
+	}
+
+	advert, err := s.advertRepo.GetById(ctx, advertId, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdvertNotFound) {
+			return entity.UsecaseWrap(ErrAdvertNotFound, ErrAdvertNotFound)
+		}
+		return entity.UsecaseWrap(err, err)
+	}
+	if advert.SellerId != seller.ID {
+		return entity.UsecaseWrap(ErrForbidden, ErrForbidden)
+	}
+
+	if err := entity.ValidateAdvertPublishable(advert.Title, advert.CategoryId); err != nil {
+		return entity.UsecaseWrap(ErrAdvertBadRequest, err)
+	}
+
+	if publishAt != nil && !publishAt.After(time.Now()) {
+		return entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
+	}
+
+	if publishAt != nil {
+		err = s.advertRepo.Schedule(ctx, advertId, *publishAt)
+	} else {
+		err = s.advertRepo.Publish(ctx, advertId)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrAdvertNotDraft) {
+			return entity.UsecaseWrap(usecase.ErrAdvertNotDraft, usecase.ErrAdvertNotDraft)
+		}
+		return entity.UsecaseWrap(err, err)
+	}
+
+	return nil
+}
+
+func (s *AdvertService) PublishScheduled(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	published, err := s.advertRepo.PublishScheduled(ctx)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	if published > 0 {
+		logger.Info("scheduled adverts published", zap.Int64("count", published))
+	}
+	return nil
+}
+
+func (s *AdvertService) FlushViews(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	pending, err := s.viewCounter.TakePending(ctx)
+	if errors.Is(err, repository.ErrViewFlushLocked) {
+		return nil
+	}
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	if !pending.Empty() {
+		if err := s.advertRepo.FlushViews(ctx, pending.BatchId, pending.Views, pending.Counters); err != nil {
+			return entity.UsecaseWrap(err, err)
+		}
+	}
+
+	if err := s.viewCounter.AckPending(ctx, pending.BatchId); err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	if !pending.Empty() {
+		logger.Info("advert views flushed", zap.Int("views", len(pending.Views)), zap.Int("adverts", len(pending.Counters)))
+	}
+	return nil
+}
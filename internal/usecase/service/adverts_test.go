@@ -1,27 +1,46 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	ucMocks "github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase/mocks"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
+const (
+	testBumpCooldown   = 24 * time.Hour
+	testExpiryReminder = 72 * time.Hour
+)
+
 func setupAdvertService(t *testing.T) (*AdvertService, *mocks.MockAdvertRepository, *mocks.MockSeller, *mocks.MockUser, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 	advertRepo := mocks.NewMockAdvertRepository(ctrl)
 	sellerRepo := mocks.NewMockSeller(ctrl)
 	userRepo := mocks.NewMockUser(ctrl)
-	service := NewAdvertService(advertRepo, sellerRepo, userRepo)
+	viewCounter := mocks.NewMockViewCounter(ctrl)
+	service := NewAdvertService(advertRepo, sellerRepo, userRepo, viewCounter, NewLogExpiryNotifier(), testBumpCooldown, testExpiryReminder)
 	return service, advertRepo, sellerRepo, userRepo, ctrl
 }
 
+func setupAdvertViewsService(t *testing.T) (*AdvertService, *mocks.MockAdvertRepository, *mocks.MockViewCounter, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	advertRepo := mocks.NewMockAdvertRepository(ctrl)
+	viewCounter := mocks.NewMockViewCounter(ctrl)
+	service := NewAdvertService(advertRepo, mocks.NewMockSeller(ctrl), mocks.NewMockUser(ctrl), viewCounter,
+		NewLogExpiryNotifier(), testBumpCooldown, testExpiryReminder)
+	return service, advertRepo, viewCounter, ctrl
+}
+
 func TestAdvertService_GetById(t *testing.T) {
 	service, advertRepo, _, _, ctrl := setupAdvertService(t)
 	defer ctrl.Finish()
@@ -47,14 +66,14 @@ func TestAdvertService_GetById(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().GetById(advertID, userID).Return(expectedAdvert, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(expectedAdvert, nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Advert Not Found",
 			setupMocks: func() {
-				advertRepo.EXPECT().GetById(advertID, userID).Return(nil, repository.ErrAdvertNotFound)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(nil, repository.ErrAdvertNotFound)
 			},
 			expectedError: ErrAdvertNotFound,
 		},
@@ -64,7 +83,7 @@ func TestAdvertService_GetById(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			advert, err := service.GetById(advertID, userID)
+			advert, err := service.GetById(context.Background(), advertID, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -84,6 +103,7 @@ func TestAdvertService_Add(t *testing.T) {
 	userID := uuid.New()
 	sellerID := uuid.New()
 	advertRequest := &dto.AdvertRequest{
+		CategoryId:  uuid.New(),
 		Title:       "New Advert",
 		Description: "Description",
 		Price:       200,
@@ -110,23 +130,23 @@ func TestAdvertService_Add(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().Add(gomock.Any()).Return(expectedAdvert, nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(expectedAdvert, nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Seller Not Found",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(nil, repository.ErrSellerNotFound)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(nil, repository.ErrSellerNotFound)
 			},
 			expectedError: repository.ErrSellerNotFound,
 		},
 		{
 			name: "Invalid Advert Data",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().Add(gomock.Any()).Return(nil, ErrAdvertBadRequest)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil, ErrAdvertBadRequest)
 			},
 			expectedError: ErrAdvertBadRequest,
 		},
@@ -136,7 +156,7 @@ func TestAdvertService_Add(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			advert, err := service.Add(advertRequest, userID)
+			advert, err := service.Add(context.Background(), advertRequest, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -157,6 +177,7 @@ func TestAdvertService_Update(t *testing.T) {
 	advertID := uuid.New()
 	sellerID := uuid.New()
 	advertRequest := &dto.AdvertRequest{
+		CategoryId:  uuid.New(),
 		Title:       "Updated Advert",
 		Description: "Updated Description",
 		Price:       300,
@@ -172,25 +193,69 @@ func TestAdvertService_Update(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{SellerId: sellerID}, nil)
-				advertRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+					Return(&entity.Advert{SellerId: sellerID, Status: entity.AdvertStatusActive}, nil)
+				advertRepo.EXPECT().Update(gomock.Any(), gomock.Any(), entity.AdvertStatusActive).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Expired Advert Keeps Status",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+					Return(&entity.Advert{SellerId: sellerID, Status: entity.AdvertStatusInactive}, nil)
+				advertRepo.EXPECT().Update(gomock.Any(), gomock.Any(), entity.AdvertStatusInactive).
+					DoAndReturn(func(_ context.Context, advert *entity.Advert, _ entity.AdvertStatus) error {
+						assert.Equal(t, entity.AdvertStatusInactive, advert.Status)
+						return nil
+					})
 			},
 			expectedError: nil,
 		},
+		{
+			name: "Reserved",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+					Return(&entity.Advert{SellerId: sellerID, Status: entity.AdvertStatusReserved}, nil)
+			},
+			expectedError: usecase.ErrAdvertReserved,
+		},
+		{
+			name: "Draft Published Through Update",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+					Return(&entity.Advert{SellerId: sellerID, Status: entity.AdvertStatusDraft}, nil)
+			},
+			expectedError: usecase.ErrAdvertNotPublished,
+		},
+		{
+			name: "Reserved Concurrently",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+					Return(&entity.Advert{SellerId: sellerID, Status: entity.AdvertStatusActive}, nil)
+				advertRepo.EXPECT().Update(gomock.Any(), gomock.Any(), entity.AdvertStatusActive).
+					Return(entity.PSQLWrap(repository.ErrAdvertNotFound))
+			},
+			expectedError: usecase.ErrAdvertStatusChanged,
+		},
 		{
 			name: "Advert Not Found",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(nil, repository.ErrAdvertNotFound)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(nil, repository.ErrAdvertNotFound)
 			},
 			expectedError: ErrAdvertNotFound,
 		},
 		{
 			name: "Forbidden",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{SellerId: uuid.New()}, nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{SellerId: uuid.New()}, nil)
 			},
 			expectedError: ErrForbidden,
 		},
@@ -200,7 +265,7 @@ func TestAdvertService_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.Update(advertRequest, userID, advertID)
+			err := service.Update(context.Background(), advertRequest, userID, advertID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -212,6 +277,59 @@ func TestAdvertService_Update(t *testing.T) {
 	}
 }
 
+func TestAdvertService_Update_Unpublished(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	userID, advertID, sellerID := uuid.New(), uuid.New(), uuid.New()
+	publishAt := time.Now().Add(time.Hour)
+	request := func(status dto.AdvertStatus, publishAt *time.Time) *dto.AdvertRequest {
+		return &dto.AdvertRequest{CategoryId: uuid.New(), Title: "Черновик", Description: "Описание", Price: 100,
+			Location: "Москва", Status: status, PublishAt: publishAt}
+	}
+	expectDraft := func(status entity.AdvertStatus) {
+		sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+		advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+			Return(&entity.Advert{SellerId: sellerID, Status: status, PublishAt: &publishAt}, nil)
+	}
+
+	t.Run("DraftScheduled", func(t *testing.T) {
+		expectDraft(entity.AdvertStatusDraft)
+		advertRepo.EXPECT().Update(gomock.Any(), gomock.Any(), entity.AdvertStatusDraft).
+			DoAndReturn(func(_ context.Context, advert *entity.Advert, _ entity.AdvertStatus) error {
+				assert.Equal(t, entity.AdvertStatusScheduled, advert.Status)
+				assert.Equal(t, &publishAt, advert.PublishAt)
+				return nil
+			})
+
+		assert.NoError(t, service.Update(context.Background(), request(dto.AdvertStatusScheduled, &publishAt), userID, advertID))
+	})
+
+	t.Run("ScheduledBackToDraft", func(t *testing.T) {
+		expectDraft(entity.AdvertStatusScheduled)
+		advertRepo.EXPECT().Update(gomock.Any(), gomock.Any(), entity.AdvertStatusScheduled).
+			DoAndReturn(func(_ context.Context, advert *entity.Advert, _ entity.AdvertStatus) error {
+				assert.Equal(t, entity.AdvertStatusDraft, advert.Status)
+				assert.Nil(t, advert.PublishAt)
+				return nil
+			})
+
+		assert.NoError(t, service.Update(context.Background(), request(dto.AdvertStatusDraft, nil), userID, advertID))
+	})
+
+	t.Run("ScheduledKeepsStatus", func(t *testing.T) {
+		expectDraft(entity.AdvertStatusScheduled)
+		advertRepo.EXPECT().Update(gomock.Any(), gomock.Any(), entity.AdvertStatusScheduled).
+			DoAndReturn(func(_ context.Context, advert *entity.Advert, _ entity.AdvertStatus) error {
+				assert.Equal(t, entity.AdvertStatusScheduled, advert.Status)
+				assert.Equal(t, &publishAt, advert.PublishAt)
+				return nil
+			})
+
+		assert.NoError(t, service.Update(context.Background(), request("", nil), userID, advertID))
+	})
+}
+
 func TestAdvertService_DeleteById(t *testing.T) {
 	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
 	defer ctrl.Finish()
@@ -228,25 +346,25 @@ func TestAdvertService_DeleteById(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{SellerId: sellerID}, nil)
-				advertRepo.EXPECT().DeleteById(advertID).Return(nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{SellerId: sellerID}, nil)
+				advertRepo.EXPECT().DeleteById(gomock.Any(), advertID).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Advert Not Found",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(nil, repository.ErrAdvertNotFound)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(nil, repository.ErrAdvertNotFound)
 			},
 			expectedError: repository.ErrAdvertNotFound,
 		},
 		{
 			name: "Forbidden",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{SellerId: uuid.New()}, nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{SellerId: uuid.New()}, nil)
 			},
 			expectedError: ErrForbidden,
 		},
@@ -256,7 +374,7 @@ func TestAdvertService_DeleteById(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.DeleteById(advertID, userID)
+			err := service.DeleteById(context.Background(), advertID, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -305,15 +423,15 @@ func TestAdvertService_GetByUserId(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetByUserId(sellerID, userID).Return(expectedAdverts, nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetByUserId(gomock.Any(), sellerID, userID).Return(expectedAdverts, nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Seller Not Found",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(nil, repository.ErrSellerNotFound)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(nil, repository.ErrSellerNotFound)
 			},
 			expectedError: repository.ErrSellerNotFound,
 		},
@@ -323,7 +441,7 @@ func TestAdvertService_GetByUserId(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			adverts, err := service.GetByUserId(userID)
+			adverts, err := service.GetByUserId(context.Background(), userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -372,7 +490,7 @@ func TestAdvertService_Get(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().Get(10, 0, userID).Return(expectedAdverts, nil)
+				advertRepo.EXPECT().Get(gomock.Any(), 10, 0, userID).Return(expectedAdverts, nil)
 			},
 			expectedError: nil,
 		},
@@ -382,7 +500,7 @@ func TestAdvertService_Get(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			adverts, err := service.Get(10, 0, userID)
+			adverts, err := service.Get(context.Background(), 10, 0, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -432,14 +550,14 @@ func TestAdvertService_GetByCartId(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().GetByCartId(cartID, userID).Return(expectedAdverts, nil)
+				advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return(expectedAdverts, nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Cart not found",
 			setupMocks: func() {
-				advertRepo.EXPECT().GetByCartId(cartID, userID).Return(nil, repository.ErrCartNotFound)
+				advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return(nil, repository.ErrCartNotFound)
 			},
 			expectedError: repository.ErrCartNotFound,
 		},
@@ -449,7 +567,7 @@ func TestAdvertService_GetByCartId(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			adverts, err := service.GetByCartId(cartID, userID)
+			adverts, err := service.GetByCartId(context.Background(), cartID, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -479,35 +597,44 @@ func TestAdvertService_UploadImage(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{SellerId: sellerID}, nil)
-				advertRepo.EXPECT().UploadImage(advertID, imageID).Return(nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{SellerId: sellerID}, nil)
+				advertRepo.EXPECT().UploadImage(gomock.Any(), advertID, imageID).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Advert Not Found",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(nil, repository.ErrAdvertNotFound)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(nil, repository.ErrAdvertNotFound)
 			},
 			expectedError: repository.ErrAdvertNotFound,
 		},
 		{
 			name: "Forbidden",
 			setupMocks: func() {
-				sellerRepo.EXPECT().GetByUserId(userID).Return(&entity.Seller{ID: sellerID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{SellerId: uuid.New()}, nil)
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{SellerId: uuid.New()}, nil)
 			},
 			expectedError: ErrForbidden,
 		},
+		{
+			name: "Image Held For Moderation",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{SellerId: sellerID}, nil)
+				advertRepo.EXPECT().UploadImage(gomock.Any(), advertID, imageID).Return(entity.PSQLWrap(repository.ErrAdvertImageFlagged))
+			},
+			expectedError: usecase.ErrAdvertImageOnModeration,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.UploadImage(advertID, imageID, userID)
+			err := service.UploadImage(context.Background(), advertID, imageID, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -534,15 +661,15 @@ func TestAdvertService_DeleteFromSaved(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().CheckIfExists(advertID).Return(true, nil)
-				advertRepo.EXPECT().DeleteFromSaved(advertID, userID).Return(nil)
+				advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(true, nil)
+				advertRepo.EXPECT().DeleteFromSaved(gomock.Any(), advertID, userID).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Advert Not Found",
 			setupMocks: func() {
-				advertRepo.EXPECT().CheckIfExists(advertID).Return(false, nil)
+				advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(false, nil)
 			},
 			expectedError: ErrAdvertNotFound,
 		},
@@ -552,7 +679,7 @@ func TestAdvertService_DeleteFromSaved(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.RemoveFromSaved(advertID, userID)
+			err := service.RemoveFromSaved(context.Background(), advertID, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -600,7 +727,7 @@ func TestAdvertService_GetSavedByUserId(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().GetSavedByUserId(userID).Return(expectedAdverts, nil)
+				advertRepo.EXPECT().GetSavedByUserId(gomock.Any(), userID).Return(expectedAdverts, nil)
 			},
 			expectedError: nil,
 		},
@@ -610,7 +737,7 @@ func TestAdvertService_GetSavedByUserId(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			adverts, err := service.GetSavedByUserId(userID)
+			adverts, err := service.GetSavedByUserId(context.Background(), userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -660,8 +787,8 @@ func TestAdvertService_Search(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().Count().Return(2, nil)
-				advertRepo.EXPECT().Search(query, gomock.Any(), gomock.Any(), userID).Return(expectedAdverts, nil).AnyTimes()
+				advertRepo.EXPECT().Count(gomock.Any()).Return(2, nil)
+				advertRepo.EXPECT().Search(gomock.Any(), query, gomock.Any(), gomock.Any(), userID).Return(expectedAdverts, nil).AnyTimes()
 			},
 			expectedError: nil,
 		},
@@ -671,7 +798,7 @@ func TestAdvertService_Search(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			adverts, err := service.Search(query, 10, 10, 0, userID)
+			adverts, err := service.Search(context.Background(), query, 10, 10, 0, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -711,7 +838,7 @@ func TestAdvertService_GetByCategoryId(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				advertRepo.EXPECT().GetByCategoryId(categoryID, userID).Return(expectedAdverts, nil)
+				advertRepo.EXPECT().GetByCategoryId(gomock.Any(), categoryID, userID).Return(expectedAdverts, nil)
 			},
 			expectedError: nil,
 		},
@@ -721,7 +848,7 @@ func TestAdvertService_GetByCategoryId(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			adverts, err := service.GetByCategoryId(categoryID, userID)
+			adverts, err := service.GetByCategoryId(context.Background(), categoryID, userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -733,3 +860,309 @@ func TestAdvertService_GetByCategoryId(t *testing.T) {
 		})
 	}
 }
+
+func TestAdvertService_Bump(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	advertID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+
+	testCases := []struct {
+		name          string
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: seller.ID}, nil)
+				advertRepo.EXPECT().Bump(gomock.Any(), advertID, testBumpCooldown).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Cooldown",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: seller.ID}, nil)
+				advertRepo.EXPECT().Bump(gomock.Any(), advertID, testBumpCooldown).Return(entity.PSQLWrap(repository.ErrAdvertBumpCooldown))
+			},
+			expectedError: usecase.ErrAdvertBumpCooldown,
+		},
+		{
+			name: "Forbidden",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: uuid.New()}, nil)
+			},
+			expectedError: ErrForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+
+			err := service.Bump(context.Background(), advertID, userID)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected error: %v, got: %v", tc.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAdvertService_Renew(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	advertID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+	advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: seller.ID, Status: entity.AdvertStatusInactive}, nil)
+	advertRepo.EXPECT().Renew(gomock.Any(), advertID).Return(nil)
+
+	err := service.Renew(context.Background(), advertID, userID)
+	assert.NoError(t, err)
+}
+
+func TestAdvertService_Renew_Reserved(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	advertID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+	advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: seller.ID, Status: entity.AdvertStatusReserved}, nil)
+	advertRepo.EXPECT().Renew(gomock.Any(), advertID).Return(entity.PSQLWrap(repository.ErrAdvertNotRenewable))
+
+	err := service.Renew(context.Background(), advertID, userID)
+	assert.ErrorIs(t, err, usecase.ErrAdvertNotRenewable)
+}
+
+func TestAdvertService_RemindExpiring(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	advertRepo := mocks.NewMockAdvertRepository(ctrl)
+	notifier := ucMocks.NewMockAdvertExpiryNotifier(ctrl)
+	service := NewAdvertService(advertRepo, mocks.NewMockSeller(ctrl), mocks.NewMockUser(ctrl), mocks.NewMockViewCounter(ctrl), notifier, testBumpCooldown, testExpiryReminder)
+
+	expiring := []*entity.Advert{
+		{ID: uuid.New(), SellerId: uuid.New(), Title: "Advert 1", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: uuid.New(), SellerId: uuid.New(), Title: "Advert 2", ExpiresAt: time.Now().Add(2 * time.Hour)},
+	}
+
+	advertRepo.EXPECT().GetExpiringUnreminded(gomock.Any(), testExpiryReminder).Return(expiring, nil)
+	notifier.EXPECT().NotifyExpiring(gomock.Any(), expiring[0].SellerId, expiring[0].ID, expiring[0].Title, expiring[0].ExpiresAt).Return(nil)
+	advertRepo.EXPECT().MarkReminded(gomock.Any(), expiring[0].ID).Return(true, nil)
+	// неотправленное напоминание не отмечается и повторяется в следующий запуск
+	notifier.EXPECT().NotifyExpiring(gomock.Any(), expiring[1].SellerId, expiring[1].ID, expiring[1].Title, expiring[1].ExpiresAt).
+		Return(errors.New("smtp unavailable"))
+
+	err := service.RemindExpiring(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestAdvertService_AddDraft(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	sellerID := uuid.New()
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID}, nil)
+	advertRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, advert *entity.Advert) (*entity.Advert, error) {
+		assert.Equal(t, entity.AdvertStatusDraft, advert.Status)
+		assert.Nil(t, advert.PublishAt)
+		advert.ID = uuid.New()
+		return advert, nil
+	})
+
+	advert, err := service.Add(context.Background(), &dto.AdvertRequest{Status: dto.AdvertStatusDraft}, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.AdvertStatusDraft, advert.Status)
+
+	_, err = service.Add(context.Background(), &dto.AdvertRequest{Title: "Incomplete", Status: dto.AdvertStatusActive}, userID)
+	assert.ErrorIs(t, err, entity.ErrAdvertIncomplete)
+}
+
+func TestAdvertService_GetDraftById(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	ownerID := uuid.New()
+	strangerID := uuid.New()
+	sellerID := uuid.New()
+	advertID := uuid.New()
+	draft := &entity.Advert{ID: advertID, SellerId: sellerID, Status: entity.AdvertStatusDraft}
+
+	advertRepo.EXPECT().GetById(gomock.Any(), advertID, ownerID).Return(draft, nil)
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), ownerID).Return(&entity.Seller{ID: sellerID}, nil)
+	advert, err := service.GetById(context.Background(), advertID, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, advertID, advert.Advert.ID)
+
+	advertRepo.EXPECT().GetById(gomock.Any(), advertID, strangerID).Return(draft, nil)
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), strangerID).Return(nil, repository.ErrSellerNotFound)
+	_, err = service.GetById(context.Background(), advertID, strangerID)
+	assert.ErrorIs(t, err, ErrAdvertNotFound)
+}
+
+func TestAdvertService_Publish(t *testing.T) {
+	service, advertRepo, sellerRepo, _, ctrl := setupAdvertService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	advertID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+	draft := &entity.Advert{ID: advertID, SellerId: seller.ID, Title: "Draft", CategoryId: uuid.New(), Status: entity.AdvertStatusDraft}
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name          string
+		publishAt     *time.Time
+		setupMocks    func()
+		expectedError error
+	}{
+		{
+			name: "Publish Now",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(draft, nil)
+				advertRepo.EXPECT().Publish(gomock.Any(), advertID).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "Schedule",
+			publishAt: &future,
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(draft, nil)
+				advertRepo.EXPECT().Schedule(gomock.Any(), advertID, future).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "Publish Time In Past",
+			publishAt: &past,
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(draft, nil)
+			},
+			expectedError: ErrAdvertBadRequest,
+		},
+		{
+			name: "Already Published",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(draft, nil)
+				advertRepo.EXPECT().Publish(gomock.Any(), advertID).Return(entity.PSQLWrap(repository.ErrAdvertNotDraft))
+			},
+			expectedError: usecase.ErrAdvertNotDraft,
+		},
+		{
+			name: "Incomplete Draft",
+			setupMocks: func() {
+				sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+				advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: seller.ID, Status: entity.AdvertStatusDraft}, nil)
+			},
+			expectedError: entity.ErrAdvertIncomplete,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setupMocks()
+
+			err := service.Publish(context.Background(), advertID, userID, tc.publishAt)
+
+			if tc.expectedError != nil {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, tc.expectedError), "expected error: %v, got: %v", tc.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAdvertService_AddViewed(t *testing.T) {
+	service, advertRepo, viewCounter, ctrl := setupAdvertViewsService(t)
+	defer ctrl.Finish()
+
+	advertID := uuid.New()
+	userID := uuid.New()
+
+	advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(true, nil).Times(2)
+	viewCounter.EXPECT().RegisterView(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, view entity.AdvertView) (bool, error) {
+		assert.Equal(t, advertID, view.AdvertId)
+		assert.Equal(t, "u:"+userID.String(), view.ViewerKey())
+		return true, nil
+	})
+	viewCounter.EXPECT().RegisterView(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, view entity.AdvertView) (bool, error) {
+		assert.Equal(t, "v:visitor", view.ViewerKey())
+		return false, nil
+	})
+
+	assert.NoError(t, service.AddViewed(context.Background(), advertID, userID, ""))
+	assert.NoError(t, service.AddViewed(context.Background(), advertID, uuid.Nil, "visitor"))
+	assert.NoError(t, service.AddViewed(context.Background(), advertID, uuid.Nil, ""))
+
+	advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(false, nil)
+	err := service.AddViewed(context.Background(), advertID, userID, "")
+	assert.ErrorIs(t, err, ErrAdvertNotFound)
+}
+
+func TestAdvertService_FlushViews(t *testing.T) {
+	service, advertRepo, viewCounter, ctrl := setupAdvertViewsService(t)
+	defer ctrl.Finish()
+
+	advertID := uuid.New()
+	pending := &entity.PendingViews{
+		BatchId:  uuid.New(),
+		Counters: map[uuid.UUID]uint{advertID: 2},
+		Views: []entity.AdvertView{
+			{AdvertId: advertID, UserId: uuid.New(), ViewedAt: time.Now()},
+			{AdvertId: advertID, VisitorId: "visitor", ViewedAt: time.Now()},
+		},
+	}
+
+	gomock.InOrder(
+		viewCounter.EXPECT().TakePending(gomock.Any()).Return(pending, nil),
+		advertRepo.EXPECT().FlushViews(gomock.Any(), pending.BatchId, pending.Views, pending.Counters).Return(nil),
+		viewCounter.EXPECT().AckPending(gomock.Any(), pending.BatchId).Return(nil),
+	)
+	assert.NoError(t, service.FlushViews(context.Background()))
+
+	// неподтвержденная пачка переносится повторно с тем же идентификатором, и Postgres ее пропускает
+	gomock.InOrder(
+		viewCounter.EXPECT().TakePending(gomock.Any()).Return(pending, nil),
+		advertRepo.EXPECT().FlushViews(gomock.Any(), pending.BatchId, pending.Views, pending.Counters).Return(nil),
+		viewCounter.EXPECT().AckPending(gomock.Any(), pending.BatchId).Return(entity.RedisWrap(repository.ErrViewFlushFailed, errors.New("redis down"))),
+		viewCounter.EXPECT().TakePending(gomock.Any()).Return(pending, nil),
+		advertRepo.EXPECT().FlushViews(gomock.Any(), pending.BatchId, pending.Views, pending.Counters).Return(nil),
+		viewCounter.EXPECT().AckPending(gomock.Any(), pending.BatchId).Return(nil),
+	)
+	assert.Error(t, service.FlushViews(context.Background()))
+	assert.NoError(t, service.FlushViews(context.Background()))
+
+	viewCounter.EXPECT().TakePending(gomock.Any()).Return(pending, nil)
+	advertRepo.EXPECT().FlushViews(gomock.Any(), pending.BatchId, pending.Views, pending.Counters).Return(entity.PSQLWrap(errors.New("db down")))
+	assert.Error(t, service.FlushViews(context.Background()))
+
+	viewCounter.EXPECT().TakePending(gomock.Any()).Return(nil, repository.ErrViewFlushLocked)
+	assert.NoError(t, service.FlushViews(context.Background()))
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+)
+
+const analyticsDayLayout = "2006-01-02"
+
+type AnalyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	advertRepo    repository.AdvertRepository
+	sellerRepo    repository.Seller
+	rollupWindow  time.Duration
+	maxRange      time.Duration
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository,
+	advertRepo repository.AdvertRepository,
+	sellerRepo repository.Seller,
+	rollupWindow, maxRange time.Duration) *AnalyticsService {
+	return &AnalyticsService{
+		analyticsRepo: analyticsRepo,
+		advertRepo:    advertRepo,
+		sellerRepo:    sellerRepo,
+		rollupWindow:  rollupWindow,
+		maxRange:      maxRange,
+	}
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (s *AnalyticsService) checkRange(from, to time.Time) (time.Time, time.Time, error) {
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) || to.Sub(from) > s.maxRange {
+		return from, to, entity.UsecaseWrap(usecase.ErrAnalyticsInvalidRange, usecase.ErrAnalyticsInvalidRange)
+	}
+	return from, to, nil
+}
+
+func toCounters(c entity.StatsCounters) dto.AnalyticsCounters {
+	return dto.AnalyticsCounters{
+		Views:         c.Views,
+		UniqueViewers: c.UniqueViewers,
+		Saves:         c.Saves,
+		CartAdditions: c.CartAdditions,
+		Purchases:     c.Purchases,
+	}
+}
+
+func ratio(part, total uint) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func buildFunnel(c entity.StatsCounters) dto.AnalyticsFunnel {
+	return dto.AnalyticsFunnel{
+		ViewToSave:     ratio(c.Saves, c.Views),
+		ViewToCart:     ratio(c.CartAdditions, c.Views),
+		CartToPurchase: ratio(c.Purchases, c.CartAdditions),
+		ViewToPurchase: ratio(c.Purchases, c.Views),
+	}
+}
+
+func (s *AnalyticsService) GetAdvertAnalytics(ctx context.Context, advertId, userId uuid.UUID, from, to time.Time) (*dto.AdvertAnalytics, error) {
+	from, to, err := s.checkRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, repository.ErrSellerNotFound)
+	}
+
+	advert, err := s.advertRepo.GetById(ctx, advertId, userId)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, err)
+	}
+	if advert.SellerId != seller.ID {
+		return nil, entity.UsecaseWrap(usecase.ErrAnalyticsForbidden, usecase.ErrAnalyticsForbidden)
+	}
+
+	stats, err := s.analyticsRepo.GetAdvertDailyStats(ctx, advertId, from, to)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, err)
+	}
+
+	byDay := make(map[time.Time]entity.StatsCounters, len(stats))
+	var totals entity.StatsCounters
+	for _, day := range stats {
+		byDay[truncateDay(day.Day)] = day.StatsCounters
+		totals.Add(day.StatsCounters)
+	}
+
+	// Дни без событий тоже попадают в ряд, чтобы график был непрерывным
+	daily := make([]dto.AnalyticsDay, 0, int(to.Sub(from).Hours()/24)+1)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		daily = append(daily, dto.AnalyticsDay{
+			Day:               day.Format(analyticsDayLayout),
+			AnalyticsCounters: toCounters(byDay[day]),
+		})
+	}
+
+	result := &dto.AdvertAnalytics{
+		AdvertId: advertId,
+		From:     from.Format(analyticsDayLayout),
+		To:       to.Format(analyticsDayLayout),
+		Totals:   toCounters(totals),
+		Funnel:   buildFunnel(totals),
+		Daily:    daily,
+	}
+
+	if advert.CategoryId != uuid.Nil {
+		categoryStats, err := s.analyticsRepo.GetCategoryDailyStats(ctx, advert.CategoryId, from, to)
+		if err != nil {
+			return nil, entity.UsecaseWrap(err, err)
+		}
+		result.CategoryAverage = categoryAverages(advert.CategoryId, categoryStats)
+	}
+
+	return result, nil
+}
+
+// categoryAverages суммирует по дням средние значения на одно активное объявление категории,
+// что сопоставимо с итогами отдельного объявления за тот же период
+func categoryAverages(categoryId uuid.UUID, stats []*entity.CategoryDailyStats) *dto.CategoryAverages {
+	averages := &dto.CategoryAverages{CategoryId: categoryId}
+	for _, day := range stats {
+		adverts := float64(day.Adverts)
+		if adverts == 0 {
+			adverts = 1
+		}
+		averages.Views += float64(day.Views) / adverts
+		averages.UniqueViewers += float64(day.UniqueViewers) / adverts
+		averages.Saves += float64(day.Saves) / adverts
+		averages.CartAdditions += float64(day.CartAdditions) / adverts
+		averages.Purchases += float64(day.Purchases) / adverts
+	}
+	return averages
+}
+
+func (s *AnalyticsService) GetSellerAnalytics(ctx context.Context, userId uuid.UUID, from, to time.Time) (*dto.SellerAnalytics, error) {
+	from, to, err := s.checkRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	seller, err := s.sellerRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, repository.ErrSellerNotFound)
+	}
+
+	advertTotals, err := s.analyticsRepo.GetSellerTotals(ctx, seller.ID, from, to)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, err)
+	}
+
+	var totals entity.StatsCounters
+	adverts := make([]dto.AdvertAnalyticsSummary, 0, len(advertTotals))
+	for _, advert := range advertTotals {
+		totals.Add(advert.StatsCounters)
+		adverts = append(adverts, dto.AdvertAnalyticsSummary{
+			AdvertId: advert.AdvertId,
+			Title:    advert.Title,
+			Totals:   toCounters(advert.StatsCounters),
+			Funnel:   buildFunnel(advert.StatsCounters),
+		})
+	}
+
+	return &dto.SellerAnalytics{
+		From:    from.Format(analyticsDayLayout),
+		To:      to.Format(analyticsDayLayout),
+		Totals:  toCounters(totals),
+		Funnel:  buildFunnel(totals),
+		Adverts: adverts,
+	}, nil
+}
+
+func (s *AnalyticsService) Rollup(ctx context.Context) error {
+	since := truncateDay(time.Now().Add(-s.rollupWindow))
+	if err := s.analyticsRepo.RollupDaily(ctx, since); err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRollupWindow   = 48 * time.Hour
+	testAnalyticsRange = 92 * 24 * time.Hour
+)
+
+func setupAnalyticsService(t *testing.T) (*AnalyticsService, *mocks.MockAnalyticsRepository, *mocks.MockAdvertRepository, *mocks.MockSeller, *gomock.Controller) {
+	ctrl := gomock.NewController(t)
+	analyticsRepo := mocks.NewMockAnalyticsRepository(ctrl)
+	advertRepo := mocks.NewMockAdvertRepository(ctrl)
+	sellerRepo := mocks.NewMockSeller(ctrl)
+
+	service := NewAnalyticsService(analyticsRepo, advertRepo, sellerRepo, testRollupWindow, testAnalyticsRange)
+
+	return service, analyticsRepo, advertRepo, sellerRepo, ctrl
+}
+
+func TestAnalyticsService_GetAdvertAnalytics(t *testing.T) {
+	service, analyticsRepo, advertRepo, sellerRepo, ctrl := setupAnalyticsService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+	advert := &entity.Advert{ID: uuid.New(), SellerId: seller.ID, CategoryId: uuid.New()}
+	from := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+	advertRepo.EXPECT().GetById(gomock.Any(), advert.ID, userID).Return(advert, nil)
+	analyticsRepo.EXPECT().GetAdvertDailyStats(gomock.Any(), advert.ID, from, to).Return([]*entity.AdvertDailyStats{
+		{AdvertId: advert.ID, Day: from, StatsCounters: entity.StatsCounters{Views: 10, UniqueViewers: 8, Saves: 2, CartAdditions: 4}},
+		{AdvertId: advert.ID, Day: to, StatsCounters: entity.StatsCounters{Views: 10, UniqueViewers: 6, CartAdditions: 1, Purchases: 1}},
+	}, nil)
+	analyticsRepo.EXPECT().GetCategoryDailyStats(gomock.Any(), advert.CategoryId, from, to).Return([]*entity.CategoryDailyStats{
+		{CategoryId: advert.CategoryId, Day: from, Adverts: 4, StatsCounters: entity.StatsCounters{Views: 40, Purchases: 2}},
+	}, nil)
+
+	analytics, err := service.GetAdvertAnalytics(context.Background(), advert.ID, userID, from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-12-01", analytics.From)
+	assert.Equal(t, "2024-12-03", analytics.To)
+	assert.Len(t, analytics.Daily, 3)
+	assert.Equal(t, "2024-12-02", analytics.Daily[1].Day)
+	assert.Zero(t, analytics.Daily[1].Views)
+	assert.Equal(t, uint(20), analytics.Totals.Views)
+	assert.Equal(t, uint(5), analytics.Totals.CartAdditions)
+	assert.Equal(t, 0.25, analytics.Funnel.ViewToCart)
+	assert.Equal(t, 0.2, analytics.Funnel.CartToPurchase)
+	assert.Equal(t, float64(10), analytics.CategoryAverage.Views)
+	assert.Equal(t, 0.5, analytics.CategoryAverage.Purchases)
+}
+
+func TestAnalyticsService_GetAdvertAnalyticsErrors(t *testing.T) {
+	service, _, advertRepo, sellerRepo, ctrl := setupAnalyticsService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	advertID := uuid.New()
+	now := time.Now()
+
+	_, err := service.GetAdvertAnalytics(context.Background(), advertID, userID, now, now.AddDate(0, 0, -1))
+	assert.ErrorIs(t, err, usecase.ErrAnalyticsInvalidRange)
+
+	_, err = service.GetAdvertAnalytics(context.Background(), advertID, userID, now.AddDate(-1, 0, 0), now)
+	assert.ErrorIs(t, err, usecase.ErrAnalyticsInvalidRange)
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: uuid.New()}, nil)
+	advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).Return(&entity.Advert{ID: advertID, SellerId: uuid.New()}, nil)
+
+	_, err = service.GetAdvertAnalytics(context.Background(), advertID, userID, now.AddDate(0, 0, -7), now)
+	assert.ErrorIs(t, err, usecase.ErrAnalyticsForbidden)
+}
+
+func TestAnalyticsService_GetSellerAnalytics(t *testing.T) {
+	service, analyticsRepo, _, sellerRepo, ctrl := setupAnalyticsService(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	seller := &entity.Seller{ID: uuid.New(), UserID: userID}
+	from := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 29)
+
+	sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(seller, nil)
+	analyticsRepo.EXPECT().GetSellerTotals(gomock.Any(), seller.ID, from, to).Return([]*entity.AdvertStatsTotal{
+		{AdvertId: uuid.New(), Title: "Phone", StatsCounters: entity.StatsCounters{Views: 30, Saves: 3, CartAdditions: 2, Purchases: 1}},
+		{AdvertId: uuid.New(), Title: "Laptop", StatsCounters: entity.StatsCounters{Views: 10}},
+	}, nil)
+
+	analytics, err := service.GetSellerAnalytics(context.Background(), userID, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, analytics.Adverts, 2)
+	assert.Equal(t, uint(40), analytics.Totals.Views)
+	assert.Equal(t, 0.5, analytics.Adverts[0].Funnel.CartToPurchase)
+	assert.Zero(t, analytics.Adverts[1].Funnel.ViewToCart)
+	assert.Equal(t, 0.025, analytics.Funnel.ViewToPurchase)
+}
+
+func TestAnalyticsService_Rollup(t *testing.T) {
+	service, analyticsRepo, _, _, ctrl := setupAnalyticsService(t)
+	defer ctrl.Finish()
+
+	analyticsRepo.EXPECT().RollupDaily(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, since time.Time) error {
+		assert.Equal(t, truncateDay(time.Now().Add(-testRollupWindow)), since)
+		return nil
+	})
+
+	assert.NoError(t, service.Rollup(context.Background()))
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -17,8 +18,8 @@ func NewAuthService(authRepo repository.Session) *AuthService {
 	return &AuthService{sessionRepo: authRepo}
 }
 
-func (a *AuthService) Logout(session string) error {
-	err := a.sessionRepo.Delete(session)
+func (a *AuthService) Logout(ctx context.Context, session string) error {
+	err := a.sessionRepo.Delete(ctx, session)
 	switch {
 	case errors.Is(err, repository.ErrSessionNotFound):
 		return usecase.ErrUserNotFound
@@ -29,16 +30,16 @@ func (a *AuthService) Logout(session string) error {
 	return nil
 }
 
-func (a *AuthService) CreateSession(userId uuid.UUID) (string, error) {
-	session, err := a.sessionRepo.Create(userId)
+func (a *AuthService) CreateSession(ctx context.Context, userId uuid.UUID) (string, error) {
+	session, err := a.sessionRepo.Create(ctx, userId)
 	if err != nil {
 		return "", entity.UsecaseWrap(errors.New("error creating session"), err)
 	}
 	return session, nil
 }
 
-func (a *AuthService) GetUserIdBySession(session string) (uuid.UUID, error) {
-	userID, err := a.sessionRepo.Get(session)
+func (a *AuthService) GetUserIdBySession(ctx context.Context, session string) (uuid.UUID, error) {
+	userID, err := a.sessionRepo.Get(ctx, session)
 	switch {
 	case errors.Is(err, repository.ErrSessionNotFound):
 		return uuid.Nil, usecase.ErrUserNotFound
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -26,10 +27,10 @@ func TestAuthService_Logout(t *testing.T) {
 	session := "valid-session-id"
 
 	gomock.InOrder(
-		sessionRepo.EXPECT().Delete(session).Return(nil),
+		sessionRepo.EXPECT().Delete(gomock.Any(), session).Return(nil),
 	)
 
-	err := service.Logout(session)
+	err := service.Logout(context.Background(), session)
 
 	assert.NoError(t, err)
 }
@@ -40,9 +41,9 @@ func TestAuthService_Logout_SessionNotFound(t *testing.T) {
 
 	session := "invalid-session-id"
 
-	sessionRepo.EXPECT().Delete(session).Return(repository.ErrSessionNotFound)
+	sessionRepo.EXPECT().Delete(gomock.Any(), session).Return(repository.ErrSessionNotFound)
 
-	err := service.Logout(session)
+	err := service.Logout(context.Background(), session)
 	assert.ErrorIs(t, err, usecase.ErrUserNotFound)
 }
 
@@ -53,9 +54,9 @@ func TestAuthService_CreateSession(t *testing.T) {
 	userId := uuid.New()
 	expectedSession := "new-session-id"
 
-	sessionRepo.EXPECT().Create(userId).Return(expectedSession, nil)
+	sessionRepo.EXPECT().Create(gomock.Any(), userId).Return(expectedSession, nil)
 
-	session, err := service.CreateSession(userId)
+	session, err := service.CreateSession(context.Background(), userId)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSession, session)
 }
@@ -67,9 +68,9 @@ func TestAuthService_GetUserIdBySession(t *testing.T) {
 	session := "valid-session-id"
 	expectedUserId := uuid.New()
 
-	sessionRepo.EXPECT().Get(session).Return(expectedUserId, nil)
+	sessionRepo.EXPECT().Get(gomock.Any(), session).Return(expectedUserId, nil)
 
-	userId, err := service.GetUserIdBySession(session)
+	userId, err := service.GetUserIdBySession(context.Background(), session)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUserId, userId)
 }
@@ -80,9 +81,9 @@ func TestAuthService_GetUserIdBySession_SessionNotFound(t *testing.T) {
 
 	session := "invalid-session-id"
 
-	sessionRepo.EXPECT().Get(session).Return(uuid.Nil, repository.ErrSessionNotFound)
+	sessionRepo.EXPECT().Get(gomock.Any(), session).Return(uuid.Nil, repository.ErrSessionNotFound)
 
-	userId, err := service.GetUserIdBySession(session)
+	userId, err := service.GetUserIdBySession(context.Background(), session)
 	assert.ErrorIs(t, err, usecase.ErrUserNotFound)
 	assert.Equal(t, uuid.Nil, userId)
 }
@@ -93,9 +94,9 @@ func TestAuthService_CreateSession_FailureInRepository(t *testing.T) {
 
 	userId := uuid.New()
 	expectedError := errors.New("repository error")
-	sessionRepo.EXPECT().Create(userId).Return("", expectedError)
+	sessionRepo.EXPECT().Create(gomock.Any(), userId).Return("", expectedError)
 
-	session, err := service.CreateSession(userId)
+	session, err := service.CreateSession(context.Background(), userId)
 
 	assert.Error(t, err)
 	assert.Equal(t, "", session)
@@ -108,9 +109,9 @@ func TestAuthService_GetUserIdBySession_RepositoryError(t *testing.T) {
 	session := "some-session-id"
 	expectedError := errors.New("database error")
 
-	sessionRepo.EXPECT().Get(session).Return(uuid.Nil, expectedError)
+	sessionRepo.EXPECT().Get(gomock.Any(), session).Return(uuid.Nil, expectedError)
 
-	userId, err := service.GetUserIdBySession(session)
+	userId, err := service.GetUserIdBySession(context.Background(), session)
 
 	assert.Error(t, err)
 	assert.Equal(t, uuid.Nil, userId)
@@ -123,9 +124,9 @@ func TestAuthService_Logout_FailureInRepository(t *testing.T) {
 	session := "some-session-id"
 	expectedError := errors.New("delete session error")
 
-	sessionRepo.EXPECT().Delete(session).Return(expectedError)
+	sessionRepo.EXPECT().Delete(gomock.Any(), session).Return(expectedError)
 
-	err := service.Logout(session)
+	err := service.Logout(context.Background(), session)
 
 	assert.Error(t, err)
 }
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/google/uuid"
 )
 
@@ -37,41 +39,118 @@ func convertAdvertToResponse(advert entity.Advert) dto.PreviewAdvertCard {
 	}
 }
 
-func (c *CartService) AddAdvert(userID, advertID uuid.UUID) error {
-	cart, err := c.cartRepo.GetByUserId(userID)
+// unavailableReason возвращает причину, по которой пользователь userID не может купить объявление,
+// или пустую строку, если купить его можно
+func unavailableReason(state *entity.AdvertSaleState, userID uuid.UUID) dto.UnavailableReason {
+	switch {
+	case state.SellerUserID == userID:
+		return dto.UnavailableOwnAdvert
+	case state.Status == entity.AdvertStatusReserved:
+		return dto.UnavailableReserved
+	case state.Status != entity.AdvertStatusActive:
+		return dto.UnavailableInactive
+	default:
+		return ""
+	}
+}
+
+func (c *CartService) AddAdvert(ctx context.Context, userID, advertID uuid.UUID) error {
+	state, err := c.advertRepo.GetSaleState(ctx, advertID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("error getting advert by id"), err)
+	}
+	switch unavailableReason(state, userID) {
+	case "":
+	case dto.UnavailableOwnAdvert:
+		return usecase.ErrCartOwnAdvert
+	default:
+		return usecase.ErrCartAdvertUnavailable
+	}
+
+	cartID, err := c.activeCartID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return c.cartRepo.AddAdvert(ctx, cartID, advertID)
+}
+
+// activeCartID возвращает активную корзину пользователя и создает ее, если корзины нет
+func (c *CartService) activeCartID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	cart, err := c.cartRepo.GetByUserId(ctx, userID)
 	switch {
 	case errors.Is(err, repository.ErrCartNotFound):
-		cartID, err := c.cartRepo.Create(userID)
+		cartID, err := c.cartRepo.Create(ctx, userID)
 		if err != nil {
-			return entity.PSQLWrap(errors.New("error creating cart"), err)
+			return uuid.Nil, entity.PSQLWrap(errors.New("error creating cart"), err)
 		}
-		advert, err := c.advertRepo.GetById(advertID, userID)
-		if err != nil {
-			return entity.PSQLWrap(errors.New("error getting advert by id"), err)
+		return cartID, nil
+	case err != nil:
+		return uuid.Nil, entity.PSQLWrap(errors.New("error getting cart by user id"), err)
+	}
+	return cart.ID, nil
+}
+
+func (c *CartService) MergeAdverts(ctx context.Context, userID uuid.UUID, advertIDs []uuid.UUID) (dto.CartMergeResult, error) {
+	cartID, err := c.activeCartID(ctx, userID)
+	if err != nil {
+		return dto.CartMergeResult{}, err
+	}
+	inCart, err := c.cartRepo.GetAdvertsByCartId(ctx, cartID)
+	if err != nil {
+		return dto.CartMergeResult{}, entity.PSQLWrap(errors.New("error getting adverts by cart id"), err)
+	}
+	seen := make(map[uuid.UUID]bool, len(inCart)+len(advertIDs))
+	for _, advert := range inCart {
+		seen[advert.ID] = true
+	}
+
+	result := dto.CartMergeResult{
+		CartID:      cartID,
+		Added:       []uuid.UUID{},
+		Duplicates:  []uuid.UUID{},
+		Unavailable: []dto.UnavailableAdvert{},
+	}
+	for _, advertID := range advertIDs {
+		if seen[advertID] {
+			result.Duplicates = append(result.Duplicates, advertID)
+			continue
 		}
-		if advert.Status != entity.AdvertStatusActive {
-			return entity.UsecaseWrap(errors.New("advert is not active"), nil)
+		seen[advertID] = true
+
+		state, err := c.advertRepo.GetSaleState(ctx, advertID)
+		switch {
+		case errors.Is(err, repository.ErrAdvertNotFound):
+			result.Unavailable = append(result.Unavailable, dto.UnavailableAdvert{AdvertID: advertID, Reason: dto.UnavailableNotFound})
+			continue
+		case err != nil:
+			return dto.CartMergeResult{}, entity.UsecaseWrap(errors.New("error getting advert by id"), err)
 		}
-		return c.cartRepo.AddAdvert(cartID, advertID)
-	case err != nil:
-		return entity.PSQLWrap(errors.New("error getting cart by user id"), err)
+		if reason := unavailableReason(state, userID); reason != "" {
+			result.Unavailable = append(result.Unavailable, dto.UnavailableAdvert{AdvertID: advertID, Status: state.Status, Reason: reason})
+			continue
+		}
+
+		if err := c.cartRepo.AddAdvert(ctx, cartID, advertID); err != nil {
+			return dto.CartMergeResult{}, err
+		}
+		result.Added = append(result.Added, advertID)
 	}
-	return c.cartRepo.AddAdvert(cart.ID, advertID)
+	return result, nil
 }
 
-func (c *CartService) DeleteAdvert(cartID uuid.UUID, advertID uuid.UUID) error {
-	return c.cartRepo.DeleteAdvert(cartID, advertID)
+func (c *CartService) DeleteAdvert(ctx context.Context, cartID uuid.UUID, advertID uuid.UUID) error {
+	return c.cartRepo.DeleteAdvert(ctx, cartID, advertID)
 }
 
-func (c *CartService) GetById(cartID uuid.UUID) (dto.Cart, error) {
-	adverts, err := c.cartRepo.GetAdvertsByCartId(cartID)
+func (c *CartService) GetById(ctx context.Context, cartID uuid.UUID) (dto.Cart, error) {
+	adverts, err := c.cartRepo.GetAdvertsByCartId(ctx, cartID)
 	switch {
 	case errors.Is(err, repository.ErrCartNotFound):
 		return dto.Cart{}, entity.UsecaseWrap(errors.New("cart not found"), err)
 	case err != nil:
 		return dto.Cart{}, entity.PSQLWrap(errors.New("error getting adverts by cart id"), err)
 	}
-	cart, err := c.cartRepo.GetById(cartID)
+	cart, err := c.cartRepo.GetById(ctx, cartID)
 	if err != nil {
 		return dto.Cart{}, entity.PSQLWrap(errors.New("error getting cart by id"), err)
 	}
@@ -83,8 +162,8 @@ func (c *CartService) GetById(cartID uuid.UUID) (dto.Cart, error) {
 	return dto.Cart{Adverts: advertsResponse, ID: cart.ID, UserID: cart.UserID, Status: cart.Status}, nil
 }
 
-func (c *CartService) GetByUserId(userID uuid.UUID) (dto.Cart, error) {
-	cart, err := c.cartRepo.GetByUserId(userID)
+func (c *CartService) GetByUserId(ctx context.Context, userID uuid.UUID) (dto.Cart, error) {
+	cart, err := c.cartRepo.GetByUserId(ctx, userID)
 	switch {
 	case errors.Is(err, repository.ErrCartNotFound):
 		return dto.Cart{}, entity.UsecaseWrap(errors.New("cart not found"), err)
@@ -99,8 +178,8 @@ func (c *CartService) GetByUserId(userID uuid.UUID) (dto.Cart, error) {
 	}, nil
 }
 
-func (c *CartService) CheckExists(userID uuid.UUID) (uuid.UUID, error) {
-	cart, err := c.cartRepo.GetByUserId(userID)
+func (c *CartService) CheckExists(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	cart, err := c.cartRepo.GetByUserId(ctx, userID)
 	switch {
 	case errors.Is(err, repository.ErrCartNotFound):
 		return uuid.Nil, nil
@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +30,7 @@ func TestCartService_AddAdvert(t *testing.T) {
 	userID := uuid.New()
 	advertID := uuid.New()
 	cartID := uuid.New()
+	sellerUserID := uuid.New()
 
 	testCases := []struct {
 		name          string
@@ -36,28 +40,54 @@ func TestCartService_AddAdvert(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{ID: cartID}, nil)
-				advertRepo.EXPECT().GetById(advertID, userID).Return(&entity.Advert{Status: entity.AdvertStatusActive}, nil)
-				cartRepo.EXPECT().AddAdvert(cartID, advertID).Return(nil)
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: sellerUserID}, nil)
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{ID: cartID}, nil)
+				cartRepo.EXPECT().AddAdvert(gomock.Any(), cartID, advertID).Return(nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Cart Not Found",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{}, repository.ErrCartNotFound)
-				cartRepo.EXPECT().Create(userID).Return(cartID, nil)
-				cartRepo.EXPECT().AddAdvert(cartID, advertID).Return(nil)
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: sellerUserID}, nil)
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{}, repository.ErrCartNotFound)
+				cartRepo.EXPECT().Create(gomock.Any(), userID).Return(cartID, nil)
+				cartRepo.EXPECT().AddAdvert(gomock.Any(), cartID, advertID).Return(nil)
 			},
 			expectedError: nil,
 		},
+		{
+			name: "Own Advert",
+			setupMocks: func() {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: userID}, nil)
+			},
+			expectedError: usecase.ErrCartOwnAdvert,
+		},
+		{
+			name: "Reserved Advert",
+			setupMocks: func() {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusReserved, SellerUserID: sellerUserID}, nil)
+			},
+			expectedError: usecase.ErrCartAdvertUnavailable,
+		},
+		{
+			name: "Advert Not Found",
+			setupMocks: func() {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).Return(nil, repository.ErrAdvertNotFound)
+			},
+			expectedError: repository.ErrAdvertNotFound,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.AddAdvert(userID, advertID)
+			err := service.AddAdvert(context.Background(), userID, advertID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -69,6 +99,58 @@ func TestCartService_AddAdvert(t *testing.T) {
 	}
 }
 
+func TestCartService_MergeAdverts(t *testing.T) {
+	service, cartRepo, advertRepo, ctrl := setupCartService(t)
+	defer ctrl.Finish()
+
+	userID, cartID, sellerUserID := uuid.New(), uuid.New(), uuid.New()
+	added, inCart, own, reserved, deleted := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{}, repository.ErrCartNotFound)
+	cartRepo.EXPECT().Create(gomock.Any(), userID).Return(cartID, nil)
+	cartRepo.EXPECT().GetAdvertsByCartId(gomock.Any(), cartID).Return([]entity.Advert{{ID: inCart}}, nil)
+	advertRepo.EXPECT().GetSaleState(gomock.Any(), added).
+		Return(&entity.AdvertSaleState{AdvertID: added, Status: entity.AdvertStatusActive, SellerUserID: sellerUserID}, nil)
+	cartRepo.EXPECT().AddAdvert(gomock.Any(), cartID, added).Return(nil)
+	advertRepo.EXPECT().GetSaleState(gomock.Any(), own).
+		Return(&entity.AdvertSaleState{AdvertID: own, Status: entity.AdvertStatusActive, SellerUserID: userID}, nil)
+	advertRepo.EXPECT().GetSaleState(gomock.Any(), reserved).
+		Return(&entity.AdvertSaleState{AdvertID: reserved, Status: entity.AdvertStatusReserved, SellerUserID: sellerUserID}, nil)
+	advertRepo.EXPECT().GetSaleState(gomock.Any(), deleted).Return(nil, repository.ErrAdvertNotFound)
+
+	result, err := service.MergeAdverts(context.Background(), userID, []uuid.UUID{added, inCart, own, added, reserved, deleted})
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.CartMergeResult{
+		CartID:     cartID,
+		Added:      []uuid.UUID{added},
+		Duplicates: []uuid.UUID{inCart, added},
+		Unavailable: []dto.UnavailableAdvert{
+			{AdvertID: own, Status: entity.AdvertStatusActive, Reason: dto.UnavailableOwnAdvert},
+			{AdvertID: reserved, Status: entity.AdvertStatusReserved, Reason: dto.UnavailableReserved},
+			{AdvertID: deleted, Reason: dto.UnavailableNotFound},
+		},
+	}, result)
+}
+
+func TestCartService_MergeAdverts_AddFails(t *testing.T) {
+	service, cartRepo, advertRepo, ctrl := setupCartService(t)
+	defer ctrl.Finish()
+
+	userID, cartID, advertID := uuid.New(), uuid.New(), uuid.New()
+	dbErr := errors.New("db error")
+
+	cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{ID: cartID}, nil)
+	cartRepo.EXPECT().GetAdvertsByCartId(gomock.Any(), cartID).Return(nil, nil)
+	advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+		Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: uuid.New()}, nil)
+	cartRepo.EXPECT().AddAdvert(gomock.Any(), cartID, advertID).Return(dbErr)
+
+	_, err := service.MergeAdverts(context.Background(), userID, []uuid.UUID{advertID})
+
+	assert.ErrorIs(t, err, dbErr)
+}
+
 func TestCartService_DeleteAdvert(t *testing.T) {
 	service, cartRepo, _, ctrl := setupCartService(t)
 	defer ctrl.Finish()
@@ -84,7 +166,7 @@ func TestCartService_DeleteAdvert(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				cartRepo.EXPECT().DeleteAdvert(cartID, advertID).Return(nil)
+				cartRepo.EXPECT().DeleteAdvert(gomock.Any(), cartID, advertID).Return(nil)
 			},
 			expectedError: nil,
 		},
@@ -94,7 +176,7 @@ func TestCartService_DeleteAdvert(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.DeleteAdvert(cartID, advertID)
+			err := service.DeleteAdvert(context.Background(), cartID, advertID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -130,8 +212,8 @@ func TestCartService_GetById(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetAdvertsByCartId(cartID).Return(expectedAdverts, nil)
-				cartRepo.EXPECT().GetById(cartID).Return(entity.Cart{ID: cartID, UserID: uuid.New(), Status: "active"}, nil)
+				cartRepo.EXPECT().GetAdvertsByCartId(gomock.Any(), cartID).Return(expectedAdverts, nil)
+				cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: uuid.New(), Status: "active"}, nil)
 			},
 			expectedError: nil,
 		},
@@ -141,7 +223,7 @@ func TestCartService_GetById(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			cart, err := service.GetById(cartID)
+			cart, err := service.GetById(context.Background(), cartID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -168,7 +250,7 @@ func TestCartService_GetByUserId(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{ID: uuid.New(), UserID: userID, Status: "active"}, nil)
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{ID: uuid.New(), UserID: userID, Status: "active"}, nil)
 			},
 			expectedError: nil,
 		},
@@ -178,7 +260,7 @@ func TestCartService_GetByUserId(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			cart, err := service.GetByUserId(userID)
+			cart, err := service.GetByUserId(context.Background(), userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -206,14 +288,14 @@ func TestCartService_CheckExists(t *testing.T) {
 		{
 			name: "Exists",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{ID: cartID}, nil)
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{ID: cartID}, nil)
 			},
 			expectedError: nil,
 		},
 		{
 			name: "Does Not Exist",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{}, repository.ErrCartNotFound)
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{}, repository.ErrCartNotFound)
 			},
 			expectedError: nil,
 		},
@@ -223,7 +305,7 @@ func TestCartService_CheckExists(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			cartID, err := service.CheckExists(userID)
+			cartID, err := service.CheckExists(context.Background(), userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -302,14 +384,14 @@ func TestCartService_DeleteAdvert_FailureAdvertNotInCart(t *testing.T) {
 		{
 			name: "Advert Not Found in Cart",
 			setupMocks: func() {
-				cartRepo.EXPECT().DeleteAdvert(cartID, advertID).Return(repository.ErrCartOrAdvertNotFound)
+				cartRepo.EXPECT().DeleteAdvert(gomock.Any(), cartID, advertID).Return(repository.ErrCartOrAdvertNotFound)
 			},
 			expectedError: repository.ErrCartOrAdvertNotFound,
 		},
 		{
 			name: "Delete Advert Error",
 			setupMocks: func() {
-				cartRepo.EXPECT().DeleteAdvert(cartID, advertID).Return(errors.New("delete advert error"))
+				cartRepo.EXPECT().DeleteAdvert(gomock.Any(), cartID, advertID).Return(errors.New("delete advert error"))
 			},
 			expectedError: errors.New("delete advert error"),
 		},
@@ -319,7 +401,7 @@ func TestCartService_DeleteAdvert_FailureAdvertNotInCart(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			err := service.DeleteAdvert(cartID, advertID)
+			err := service.DeleteAdvert(context.Background(), cartID, advertID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -397,14 +479,14 @@ func TestCartService_GetByUserId_FailureCartNotFound(t *testing.T) {
 		{
 			name: "Cart Not Found",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{}, repository.ErrCartNotFound)
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{}, repository.ErrCartNotFound)
 			},
 			expectedError: repository.ErrCartNotFound,
 		},
 		{
 			name: "Get Cart Error",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{}, errors.New("get cart error"))
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{}, errors.New("get cart error"))
 			},
 			expectedError: errors.New("get cart error"),
 		},
@@ -414,7 +496,7 @@ func TestCartService_GetByUserId_FailureCartNotFound(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			cart, err := service.GetByUserId(userID)
+			cart, err := service.GetByUserId(context.Background(), userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
@@ -441,7 +523,7 @@ func TestCartService_CheckExists_FailureRepositoryError(t *testing.T) {
 		{
 			name: "Repository Error",
 			setupMocks: func() {
-				cartRepo.EXPECT().GetByUserId(userID).Return(entity.Cart{}, errors.New("repository error"))
+				cartRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(entity.Cart{}, errors.New("repository error"))
 			},
 			expectedError: errors.New("repository error"),
 		},
@@ -451,7 +533,7 @@ func TestCartService_CheckExists_FailureRepositoryError(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
 
-			cartID, err := service.CheckExists(userID)
+			cartID, err := service.CheckExists(context.Background(), userID)
 
 			if tc.expectedError != nil {
 				assert.Error(t, err)
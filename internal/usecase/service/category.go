@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 )
@@ -15,8 +16,8 @@ func NewCategoryService(categoryRepo repository.CategoryRepository) *CategorySer
 	}
 }
 
-func (s *CategoryService) Get() ([]*entity.Category, error) {
-	categories, err := s.categoryRepo.Get()
+func (s *CategoryService) Get(ctx context.Context) ([]*entity.Category, error) {
+	categories, err := s.categoryRepo.Get(ctx)
 	if err != nil {
 		return nil, entity.UsecaseWrap(err, err)
 	}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"testing"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -24,9 +25,9 @@ func TestCategoryService_GetCategories_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	expectedCategories := []*entity.Category{{ID: uuid.New(), Title: "Category1"}}
-	mockRepo.EXPECT().Get().Return(expectedCategories, nil)
+	mockRepo.EXPECT().Get(gomock.Any()).Return(expectedCategories, nil)
 
-	categories, err := service.Get()
+	categories, err := service.Get(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCategories, categories)
@@ -36,9 +37,9 @@ func TestCategoryService_GetCategories_Error(t *testing.T) {
 	service, ctrl, mockRepo := setupCategoryTestService(t)
 	defer ctrl.Finish()
 
-	mockRepo.EXPECT().Get().Return(nil, assert.AnError)
+	mockRepo.EXPECT().Get(gomock.Any()).Return(nil, assert.AnError)
 
-	categories, err := service.Get()
+	categories, err := service.Get(context.Background())
 
 	assert.Error(t, err)
 	assert.Nil(t, categories)
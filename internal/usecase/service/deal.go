@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const maxDisputeReasonLength = 1000
+
+// SafeDealService ведет безопасные сделки. Деньги по сделке двигает PaymentService: списание
+// и отмена платежа переводят сделку и покупку на следующий этап в одной транзакции
+type SafeDealService struct {
+	dealRepo       repository.SafeDealRepository
+	purchaseRepo   repository.PurchaseRepository
+	cartRepo       repository.Cart
+	advertRepo     repository.AdvertRepository
+	paymentUC      usecase.PaymentUseCase
+	shipTimeout    time.Duration
+	confirmTimeout time.Duration
+	settleTimeout  time.Duration
+	batchSize      int
+	admins         map[uuid.UUID]struct{}
+}
+
+func NewSafeDealService(dealRepo repository.SafeDealRepository,
+	purchaseRepo repository.PurchaseRepository,
+	cartRepo repository.Cart,
+	advertRepo repository.AdvertRepository,
+	paymentUC usecase.PaymentUseCase,
+	shipTimeout, confirmTimeout, settleTimeout time.Duration,
+	batchSize int,
+	admins []uuid.UUID) *SafeDealService {
+	adminSet := make(map[uuid.UUID]struct{}, len(admins))
+	for _, admin := range admins {
+		adminSet[admin] = struct{}{}
+	}
+	return &SafeDealService{
+		dealRepo:       dealRepo,
+		purchaseRepo:   purchaseRepo,
+		cartRepo:       cartRepo,
+		advertRepo:     advertRepo,
+		paymentUC:      paymentUC,
+		shipTimeout:    shipTimeout,
+		confirmTimeout: confirmTimeout,
+		settleTimeout:  settleTimeout,
+		batchSize:      batchSize,
+		admins:         adminSet,
+	}
+}
+
+func (s *SafeDealService) dealEntityToDTO(deal *entity.SafeDeal, payment *dto.PaymentResponse) *dto.DealResponse {
+	response := &dto.DealResponse{
+		ID:            deal.ID,
+		PurchaseID:    deal.PurchaseID,
+		BuyerID:       deal.BuyerID,
+		SellerID:      deal.SellerID,
+		Status:        string(deal.Status),
+		DisputedBy:    deal.DisputedBy,
+		DisputeReason: deal.DisputeReason,
+		Payment:       payment,
+	}
+	switch {
+	case deal.Status == entity.DealStatusHeld && deal.HeldAt != nil:
+		deadline := deal.HeldAt.Add(s.shipTimeout)
+		response.Deadline = &deadline
+	case deal.Status == entity.DealStatusShipped && deal.ShippedAt != nil:
+		deadline := deal.ShippedAt.Add(s.confirmTimeout)
+		response.Deadline = &deadline
+	}
+	return response
+}
+
+func (s *SafeDealService) isAdmin(userID uuid.UUID) bool {
+	_, ok := s.admins[userID]
+	return ok
+}
+
+func (s *SafeDealService) Create(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.DealResponse, error) {
+	logger := middleware.GetLogger(ctx).With(zap.String("purchase_id", purchaseID.String()))
+
+	purchase, err := s.purchaseRepo.GetById(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get purchase"), err)
+	}
+	cart, err := s.cartRepo.GetById(ctx, purchase.CartID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get cart"), err)
+	}
+	if cart.UserID != userID {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+
+	deal, err := s.dealRepo.GetByPurchaseId(ctx, purchaseID)
+	switch {
+	case errors.Is(err, repository.ErrDealNotFound):
+		deal, err = s.add(ctx, purchase, userID)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("safe deal created", zap.String("deal_id", deal.ID.String()))
+	case err != nil:
+		return nil, entity.UsecaseWrap(errors.New("failed to get safe deal"), err)
+	}
+
+	// платеж создается и при повторе, если в прошлый раз провайдер был недоступен
+	payment, err := s.paymentUC.Create(ctx, purchaseID, userID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to create safe deal payment"), err)
+	}
+	return s.dealEntityToDTO(deal, payment), nil
+}
+
+func (s *SafeDealService) add(ctx context.Context, purchase *entity.Purchase, userID uuid.UUID) (*entity.SafeDeal, error) {
+	if !purchase.SafeDeal || purchase.PaymentMethod != entity.PaymentMethodCard || purchase.Status != entity.StatusPending {
+		return nil, entity.UsecaseWrap(usecase.ErrDealNotAllowed, usecase.ErrDealNotAllowed)
+	}
+
+	adverts, err := s.advertRepo.GetByCartId(ctx, purchase.CartID, userID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get adverts"), err)
+	}
+	if len(adverts) == 0 {
+		return nil, entity.UsecaseWrap(usecase.ErrDealNotAllowed, usecase.ErrDealNotAllowed)
+	}
+	sellerID := adverts[0].SellerId
+	for _, advert := range adverts[1:] {
+		if advert.SellerId != sellerID {
+			return nil, entity.UsecaseWrap(usecase.ErrDealMultipleSellers, usecase.ErrDealMultipleSellers)
+		}
+	}
+
+	deal, err := s.dealRepo.Add(ctx, &entity.SafeDeal{
+		PurchaseID: purchase.ID,
+		BuyerID:    userID,
+		SellerID:   sellerID,
+	})
+	if errors.Is(err, repository.ErrDealAlreadyExists) {
+		deal, err = s.dealRepo.GetByPurchaseId(ctx, purchase.ID)
+	}
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to add safe deal"), err)
+	}
+	return deal, nil
+}
+
+// payment возвращает платеж по сделке или nil, если он еще не создан
+func (s *SafeDealService) payment(ctx context.Context, deal *entity.SafeDeal) (*dto.PaymentResponse, error) {
+	payment, err := s.paymentUC.GetByPurchaseId(ctx, deal.PurchaseID, deal.BuyerID)
+	switch {
+	case errors.Is(err, repository.ErrPaymentNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, entity.UsecaseWrap(errors.New("failed to get safe deal payment"), err)
+	}
+	return payment, nil
+}
+
+// response перечитывает сделку после изменения и возвращает ее вместе с платежом
+func (s *SafeDealService) response(ctx context.Context, dealID uuid.UUID) (*dto.DealResponse, error) {
+	deal, err := s.dealRepo.GetById(ctx, dealID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get safe deal"), err)
+	}
+	payment, err := s.payment(ctx, deal)
+	if err != nil {
+		return nil, err
+	}
+	return s.dealEntityToDTO(deal, payment), nil
+}
+
+func (s *SafeDealService) GetByPurchaseId(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.DealResponse, error) {
+	deal, err := s.dealRepo.GetByPurchaseId(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get safe deal"), err)
+	}
+	if userID != deal.BuyerID && userID != deal.SellerUserID && !s.isAdmin(userID) {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+	payment, err := s.payment(ctx, deal)
+	if err != nil {
+		return nil, err
+	}
+	return s.dealEntityToDTO(deal, payment), nil
+}
+
+func (s *SafeDealService) getDeal(ctx context.Context, dealID uuid.UUID) (*entity.SafeDeal, error) {
+	deal, err := s.dealRepo.GetById(ctx, dealID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get safe deal"), err)
+	}
+	return deal, nil
+}
+
+func (s *SafeDealService) Ship(ctx context.Context, dealID, userID uuid.UUID) (*dto.DealResponse, error) {
+	deal, err := s.getDeal(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != deal.SellerUserID {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+	if deal.Status != entity.DealStatusHeld {
+		return nil, entity.UsecaseWrap(usecase.ErrDealInvalidState, usecase.ErrDealInvalidState)
+	}
+
+	tx, err := s.dealRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to begin transaction"), err)
+	}
+	changed, err := s.dealRepo.UpdateStatus(ctx, tx, deal.ID, entity.DealStatusHeld, entity.DealStatusShipped)
+	if err != nil || !changed {
+		_ = tx.Rollback(ctx)
+		if err == nil {
+			err = usecase.ErrDealInvalidState
+		}
+		return nil, entity.UsecaseWrap(errors.New("failed to mark safe deal shipped"), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to commit transaction"), err)
+	}
+
+	middleware.GetLogger(ctx).Info("safe deal shipped", zap.String("deal_id", deal.ID.String()))
+	return s.response(ctx, deal.ID)
+}
+
+func (s *SafeDealService) Confirm(ctx context.Context, dealID, userID uuid.UUID) (*dto.DealResponse, error) {
+	deal, err := s.getDeal(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != deal.BuyerID {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+	// покупатель может получить товар из рук раньше, чем продавец отметит отправку
+	if deal.Status != entity.DealStatusHeld && deal.Status != entity.DealStatusShipped {
+		return nil, entity.UsecaseWrap(usecase.ErrDealInvalidState, usecase.ErrDealInvalidState)
+	}
+	if err := s.release(ctx, deal); err != nil {
+		return nil, err
+	}
+	return s.response(ctx, deal.ID)
+}
+
+func (s *SafeDealService) OpenDispute(ctx context.Context, dealID, userID uuid.UUID, reason string) (*dto.DealResponse, error) {
+	deal, err := s.getDeal(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if userID != deal.BuyerID && userID != deal.SellerUserID {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+	if reason == "" || utf8.RuneCountInString(reason) > maxDisputeReasonLength {
+		return nil, entity.UsecaseWrap(usecase.ErrDealBadRequest, usecase.ErrDealBadRequest)
+	}
+	if deal.Status != entity.DealStatusHeld && deal.Status != entity.DealStatusShipped {
+		return nil, entity.UsecaseWrap(usecase.ErrDealInvalidState, usecase.ErrDealInvalidState)
+	}
+
+	opened, err := s.dealRepo.OpenDispute(ctx, deal.ID, deal.Status, userID, reason)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to open dispute"), err)
+	}
+	if !opened {
+		return nil, entity.UsecaseWrap(usecase.ErrDealInvalidState, usecase.ErrDealInvalidState)
+	}
+
+	middleware.GetLogger(ctx).Info("safe deal dispute opened", zap.String("deal_id", deal.ID.String()),
+		zap.String("user_id", userID.String()))
+	return s.response(ctx, deal.ID)
+}
+
+func (s *SafeDealService) GetDisputes(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*dto.DealResponse, error) {
+	if !s.isAdmin(userID) {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+	deals, err := s.dealRepo.GetDisputed(ctx, limit, offset)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get disputes"), err)
+	}
+
+	responses := make([]*dto.DealResponse, 0, len(deals))
+	for _, deal := range deals {
+		payment, err := s.payment(ctx, deal)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, s.dealEntityToDTO(deal, payment))
+	}
+	return responses, nil
+}
+
+func (s *SafeDealService) Resolve(ctx context.Context, dealID, userID uuid.UUID, resolution entity.DealResolution) (*dto.DealResponse, error) {
+	if !s.isAdmin(userID) {
+		return nil, entity.UsecaseWrap(usecase.ErrDealForbidden, usecase.ErrDealForbidden)
+	}
+	deal, err := s.getDeal(ctx, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if deal.Status != entity.DealStatusDisputed {
+		return nil, entity.UsecaseWrap(usecase.ErrDealInvalidState, usecase.ErrDealInvalidState)
+	}
+
+	switch resolution {
+	case entity.DealResolutionRelease:
+		err = s.release(ctx, deal)
+	case entity.DealResolutionRefund:
+		err = s.refund(ctx, deal)
+	default:
+		return nil, entity.UsecaseWrap(usecase.ErrDealBadRequest, usecase.ErrDealBadRequest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	middleware.GetLogger(ctx).Info("safe deal dispute resolved", zap.String("deal_id", deal.ID.String()),
+		zap.String("admin_id", userID.String()), zap.String("resolution", string(resolution)))
+	return s.response(ctx, deal.ID)
+}
+
+func (s *SafeDealService) ProcessExpired(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	deals, err := s.dealRepo.GetExpired(ctx, s.shipTimeout, s.confirmTimeout, s.settleTimeout, s.batchSize)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	var released, refunded int
+	for _, deal := range deals {
+		var err error
+		switch deal.Status {
+		case entity.DealStatusHeld:
+			if err = s.refund(ctx, deal); err == nil {
+				refunded++
+			}
+		case entity.DealStatusShipped:
+			if err = s.release(ctx, deal); err == nil {
+				released++
+			}
+		case entity.DealStatusRefunding:
+			if err = s.resume(ctx, deal, s.paymentUC.Cancel); err == nil {
+				refunded++
+			}
+		case entity.DealStatusReleasing:
+			if err = s.resume(ctx, deal, s.paymentUC.Capture); err == nil {
+				released++
+			}
+		}
+		if err != nil {
+			logger.Error("failed to process expired safe deal", zap.Error(err), zap.String("deal_id", deal.ID.String()))
+		}
+	}
+
+	if released+refunded > 0 {
+		logger.Info("expired safe deals processed", zap.Int("released", released), zap.Int("refunded", refunded))
+	}
+	return nil
+}
+
+// release списывает заблокированные средства в пользу продавца
+func (s *SafeDealService) release(ctx context.Context, deal *entity.SafeDeal) error {
+	return s.settle(ctx, deal, entity.DealStatusReleasing, s.paymentUC.Capture)
+}
+
+// refund разблокирует средства покупателя
+func (s *SafeDealService) refund(ctx context.Context, deal *entity.SafeDeal) error {
+	return s.settle(ctx, deal, entity.DealStatusRefunding, s.paymentUC.Cancel)
+}
+
+// settle переводит сделку в промежуточный статус и только потом обращается к провайдеру, поэтому
+// спор, открытый параллельно, либо не даст начать расчет, либо уже не сможет открыться.
+// Завершает сделку смена статуса платежа; если провайдер отказал, сделка возвращается на прежний этап
+func (s *SafeDealService) settle(ctx context.Context, deal *entity.SafeDeal, settling entity.DealStatus,
+	move func(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error)) error {
+	logger := middleware.GetLogger(ctx).With(zap.String("deal_id", deal.ID.String()),
+		zap.String("from", string(deal.Status)), zap.String("to", string(settling)))
+
+	payment, err := s.paymentUC.GetByPurchaseId(ctx, deal.PurchaseID, deal.BuyerID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to get safe deal payment"), err)
+	}
+
+	if err := s.changeStatus(ctx, deal.ID, deal.Status, settling); err != nil {
+		return err
+	}
+
+	if _, err := move(ctx, payment.ID); err != nil {
+		if revertErr := s.changeStatus(ctx, deal.ID, settling, deal.Status); revertErr != nil {
+			logger.Error("failed to return safe deal to previous status", zap.Error(revertErr))
+		}
+		return entity.UsecaseWrap(errors.New("failed to settle safe deal funds"), err)
+	}
+	return nil
+}
+
+// resume повторяет расчет по сделке, которая осталась в releasing или refunding. Сделка уже
+// не вернется на прежний этап, поэтому провайдеру повторяется тот же запрос: по ключу
+// идемпотентности он не спишет и не разблокирует средства второй раз
+func (s *SafeDealService) resume(ctx context.Context, deal *entity.SafeDeal,
+	move func(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error)) error {
+	payment, err := s.paymentUC.GetByPurchaseId(ctx, deal.PurchaseID, deal.BuyerID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to get safe deal payment"), err)
+	}
+	if _, err := move(ctx, payment.ID); err != nil {
+		return entity.UsecaseWrap(errors.New("failed to settle safe deal funds"), err)
+	}
+	middleware.GetLogger(ctx).Info("stuck safe deal settled", zap.String("deal_id", deal.ID.String()),
+		zap.String("status", string(deal.Status)))
+	return nil
+}
+
+// changeStatus переводит сделку из статуса from в to отдельной транзакцией
+func (s *SafeDealService) changeStatus(ctx context.Context, dealID uuid.UUID, from, to entity.DealStatus) error {
+	tx, err := s.dealRepo.BeginTransaction(ctx)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to begin transaction"), err)
+	}
+	changed, err := s.dealRepo.UpdateStatus(ctx, tx, dealID, from, to)
+	if err != nil || !changed {
+		_ = tx.Rollback(ctx)
+		if err == nil {
+			err = usecase.ErrDealInvalidState
+		}
+		return entity.UsecaseWrap(errors.New("failed to update safe deal status"), err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return entity.UsecaseWrap(errors.New("failed to commit transaction"), err)
+	}
+	return nil
+}
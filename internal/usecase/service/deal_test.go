@@ -0,0 +1,496 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	ucmocks "github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testShipTimeout    = 72 * time.Hour
+	testConfirmTimeout = 168 * time.Hour
+	testSettleTimeout  = 10 * time.Minute
+)
+
+type dealTestDeps struct {
+	dealRepo     *mocks.MockSafeDealRepository
+	purchaseRepo *mocks.MockPurchaseRepository
+	cartRepo     *mocks.MockCart
+	advertRepo   *mocks.MockAdvertRepository
+	paymentUC    *ucmocks.MockPaymentUseCase
+}
+
+func setupSafeDealService(t *testing.T, admins ...uuid.UUID) (*SafeDealService, *dealTestDeps) {
+	ctrl := gomock.NewController(t)
+	deps := &dealTestDeps{
+		dealRepo:     mocks.NewMockSafeDealRepository(ctrl),
+		purchaseRepo: mocks.NewMockPurchaseRepository(ctrl),
+		cartRepo:     mocks.NewMockCart(ctrl),
+		advertRepo:   mocks.NewMockAdvertRepository(ctrl),
+		paymentUC:    ucmocks.NewMockPaymentUseCase(ctrl),
+	}
+	service := NewSafeDealService(deps.dealRepo, deps.purchaseRepo, deps.cartRepo, deps.advertRepo, deps.paymentUC,
+		testShipTimeout, testConfirmTimeout, testSettleTimeout, 10, admins)
+	return service, deps
+}
+
+// expectTransition ожидает перевод сделки из from в to отдельной транзакцией
+func (d *dealTestDeps) expectTransition(t *testing.T, dealID uuid.UUID, from, to entity.DealStatus, changed bool) {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	pool.ExpectBegin()
+	tx, err := pool.Begin(context.Background())
+	require.NoError(t, err)
+	if changed {
+		pool.ExpectCommit()
+	} else {
+		pool.ExpectRollback()
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, pool.ExpectationsWereMet())
+		pool.Close()
+	})
+
+	d.dealRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	d.dealRepo.EXPECT().UpdateStatus(gomock.Any(), tx, dealID, from, to).Return(changed, nil)
+}
+
+func newTestDeal(status entity.DealStatus) *entity.SafeDeal {
+	now := time.Now()
+	return &entity.SafeDeal{
+		ID:           uuid.New(),
+		PurchaseID:   uuid.New(),
+		BuyerID:      uuid.New(),
+		SellerID:     uuid.New(),
+		SellerUserID: uuid.New(),
+		Status:       status,
+		HeldAt:       &now,
+	}
+}
+
+func TestSafeDealService_Create(t *testing.T) {
+	userID, purchaseID, cartID, sellerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	purchase := &entity.Purchase{
+		ID:            purchaseID,
+		CartID:        cartID,
+		Status:        entity.StatusPending,
+		PaymentMethod: entity.PaymentMethodCard,
+		SafeDeal:      true,
+	}
+	payment := &dto.PaymentResponse{ID: uuid.New(), PurchaseID: purchaseID, Status: string(entity.PaymentStatusPending)}
+
+	expectOwnedPurchase := func(deps *dealTestDeps) {
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(purchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		expectOwnedPurchase(deps)
+
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrDealNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).
+			Return([]*entity.Advert{{SellerId: sellerID}, {SellerId: sellerID}}, nil)
+		deps.dealRepo.EXPECT().Add(gomock.Any(), &entity.SafeDeal{PurchaseID: purchaseID, BuyerID: userID, SellerID: sellerID}).
+			DoAndReturn(func(_ context.Context, deal *entity.SafeDeal) (*entity.SafeDeal, error) {
+				created := *deal
+				created.ID = uuid.New()
+				created.Status = entity.DealStatusAwaitingPayment
+				return &created, nil
+			})
+		deps.paymentUC.EXPECT().Create(gomock.Any(), purchaseID, userID).Return(payment, nil)
+
+		deal, err := service.Create(context.Background(), purchaseID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.DealStatusAwaitingPayment), deal.Status)
+		assert.Equal(t, sellerID, deal.SellerID)
+		assert.Equal(t, payment, deal.Payment)
+		assert.Nil(t, deal.Deadline)
+	})
+
+	t.Run("AlreadyCreated", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		expectOwnedPurchase(deps)
+		existing := newTestDeal(entity.DealStatusAwaitingPayment)
+
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(existing, nil)
+		deps.paymentUC.EXPECT().Create(gomock.Any(), purchaseID, userID).Return(payment, nil)
+
+		deal, err := service.Create(context.Background(), purchaseID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, deal.ID)
+	})
+
+	t.Run("MultipleSellers", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		expectOwnedPurchase(deps)
+
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrDealNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).
+			Return([]*entity.Advert{{SellerId: sellerID}, {SellerId: uuid.New()}}, nil)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrDealMultipleSellers)
+	})
+
+	t.Run("Cash", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		cashPurchase := *purchase
+		cashPurchase.PaymentMethod = entity.PaymentMethodCash
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(&cashPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrDealNotFound)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrDealNotAllowed)
+	})
+
+	t.Run("PurchaseWithoutSafeDeal", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		regularPurchase := *purchase
+		regularPurchase.SafeDeal = false
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(&regularPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrDealNotFound)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrDealNotAllowed)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(purchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: uuid.New()}, nil)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrDealForbidden)
+	})
+}
+
+func TestSafeDealService_GetByPurchaseId(t *testing.T) {
+	adminID := uuid.New()
+	deal := newTestDeal(entity.DealStatusHeld)
+
+	for name, userID := range map[string]uuid.UUID{"Buyer": deal.BuyerID, "Seller": deal.SellerUserID, "Admin": adminID} {
+		t.Run(name, func(t *testing.T) {
+			service, deps := setupSafeDealService(t, adminID)
+
+			deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID).Return(deal, nil)
+			deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).
+				Return(nil, repository.ErrPaymentNotFound)
+
+			resp, err := service.GetByPurchaseId(context.Background(), deal.PurchaseID, userID)
+			require.NoError(t, err)
+			require.NotNil(t, resp.Deadline)
+			assert.Equal(t, deal.HeldAt.Add(testShipTimeout), *resp.Deadline)
+			assert.Nil(t, resp.Payment)
+		})
+	}
+
+	t.Run("Stranger", func(t *testing.T) {
+		service, deps := setupSafeDealService(t, adminID)
+
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID).Return(deal, nil)
+
+		_, err := service.GetByPurchaseId(context.Background(), deal.PurchaseID, uuid.New())
+		assert.ErrorIs(t, err, usecase.ErrDealForbidden)
+	})
+}
+
+func TestSafeDealService_Ship(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusHeld)
+		shipped := *deal
+		shipped.Status = entity.DealStatusShipped
+		shippedAt := time.Now()
+		shipped.ShippedAt = &shippedAt
+
+		pool, err := pgxmock.NewPool()
+		require.NoError(t, err)
+		defer pool.Close()
+		pool.ExpectBegin()
+		tx, err := pool.Begin(context.Background())
+		require.NoError(t, err)
+		pool.ExpectCommit()
+
+		gomock.InOrder(
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil),
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(&shipped, nil),
+		)
+		deps.dealRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+		deps.dealRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.ID, entity.DealStatusHeld, entity.DealStatusShipped).
+			Return(true, nil)
+		deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).
+			Return(&dto.PaymentResponse{ID: uuid.New()}, nil)
+
+		resp, err := service.Ship(context.Background(), deal.ID, deal.SellerUserID)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.DealStatusShipped), resp.Status)
+		assert.Equal(t, shippedAt.Add(testConfirmTimeout), *resp.Deadline)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("NotSeller", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusHeld)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.Ship(context.Background(), deal.ID, deal.BuyerID)
+		assert.ErrorIs(t, err, usecase.ErrDealForbidden)
+	})
+
+	t.Run("NotHeld", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusAwaitingPayment)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.Ship(context.Background(), deal.ID, deal.SellerUserID)
+		assert.ErrorIs(t, err, usecase.ErrDealInvalidState)
+	})
+}
+
+func TestSafeDealService_Confirm(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusShipped)
+		completed := *deal
+		completed.Status = entity.DealStatusCompleted
+		payment := &dto.PaymentResponse{ID: uuid.New(), Status: string(entity.PaymentStatusWaitingForCapture)}
+
+		gomock.InOrder(
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil),
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(&completed, nil),
+		)
+		deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).Return(payment, nil).Times(2)
+		deps.expectTransition(t, deal.ID, entity.DealStatusShipped, entity.DealStatusReleasing, true)
+		deps.paymentUC.EXPECT().Capture(gomock.Any(), payment.ID).Return(payment, nil)
+
+		resp, err := service.Confirm(context.Background(), deal.ID, deal.BuyerID)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.DealStatusCompleted), resp.Status)
+		assert.Nil(t, resp.Deadline)
+	})
+
+	t.Run("DisputeOpenedConcurrently", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusShipped)
+		payment := &dto.PaymentResponse{ID: uuid.New()}
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+		deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).Return(payment, nil)
+		deps.expectTransition(t, deal.ID, entity.DealStatusShipped, entity.DealStatusReleasing, false)
+
+		_, err := service.Confirm(context.Background(), deal.ID, deal.BuyerID)
+		assert.ErrorIs(t, err, usecase.ErrDealInvalidState)
+	})
+
+	t.Run("CaptureFailed", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusShipped)
+		payment := &dto.PaymentResponse{ID: uuid.New()}
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+		deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).Return(payment, nil)
+		deps.expectTransition(t, deal.ID, entity.DealStatusShipped, entity.DealStatusReleasing, true)
+		deps.paymentUC.EXPECT().Capture(gomock.Any(), payment.ID).Return(nil, errors.New("gateway is down"))
+		deps.expectTransition(t, deal.ID, entity.DealStatusReleasing, entity.DealStatusShipped, true)
+
+		_, err := service.Confirm(context.Background(), deal.ID, deal.BuyerID)
+		assert.Error(t, err)
+	})
+
+	t.Run("NotBuyer", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusShipped)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.Confirm(context.Background(), deal.ID, deal.SellerUserID)
+		assert.ErrorIs(t, err, usecase.ErrDealForbidden)
+	})
+
+	t.Run("Disputed", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusDisputed)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.Confirm(context.Background(), deal.ID, deal.BuyerID)
+		assert.ErrorIs(t, err, usecase.ErrDealInvalidState)
+	})
+}
+
+func TestSafeDealService_OpenDispute(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusShipped)
+		disputed := *deal
+		disputed.Status = entity.DealStatusDisputed
+
+		gomock.InOrder(
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil),
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(&disputed, nil),
+		)
+		deps.dealRepo.EXPECT().OpenDispute(gomock.Any(), deal.ID, entity.DealStatusShipped, deal.SellerUserID, "не забирает товар").
+			Return(true, nil)
+		deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).
+			Return(nil, repository.ErrPaymentNotFound)
+
+		resp, err := service.OpenDispute(context.Background(), deal.ID, deal.SellerUserID, "не забирает товар")
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.DealStatusDisputed), resp.Status)
+		assert.Nil(t, resp.Deadline)
+	})
+
+	t.Run("EmptyReason", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusHeld)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.OpenDispute(context.Background(), deal.ID, deal.BuyerID, "")
+		assert.ErrorIs(t, err, usecase.ErrDealBadRequest)
+	})
+
+	t.Run("ChangedConcurrently", func(t *testing.T) {
+		service, deps := setupSafeDealService(t)
+		deal := newTestDeal(entity.DealStatusHeld)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+		deps.dealRepo.EXPECT().OpenDispute(gomock.Any(), deal.ID, entity.DealStatusHeld, deal.BuyerID, "не пришел").
+			Return(false, nil)
+
+		_, err := service.OpenDispute(context.Background(), deal.ID, deal.BuyerID, "не пришел")
+		assert.ErrorIs(t, err, usecase.ErrDealInvalidState)
+	})
+}
+
+func TestSafeDealService_Resolve(t *testing.T) {
+	adminID := uuid.New()
+
+	t.Run("Refund", func(t *testing.T) {
+		service, deps := setupSafeDealService(t, adminID)
+		deal := newTestDeal(entity.DealStatusDisputed)
+		refunded := *deal
+		refunded.Status = entity.DealStatusRefunded
+		payment := &dto.PaymentResponse{ID: uuid.New()}
+
+		gomock.InOrder(
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil),
+			deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(&refunded, nil),
+		)
+		deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).Return(payment, nil).Times(2)
+		deps.expectTransition(t, deal.ID, entity.DealStatusDisputed, entity.DealStatusRefunding, true)
+		deps.paymentUC.EXPECT().Cancel(gomock.Any(), payment.ID).Return(payment, nil)
+
+		resp, err := service.Resolve(context.Background(), deal.ID, adminID, entity.DealResolutionRefund)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.DealStatusRefunded), resp.Status)
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		service, _ := setupSafeDealService(t, adminID)
+
+		_, err := service.Resolve(context.Background(), uuid.New(), uuid.New(), entity.DealResolutionRelease)
+		assert.ErrorIs(t, err, usecase.ErrDealForbidden)
+	})
+
+	t.Run("UnknownResolution", func(t *testing.T) {
+		service, deps := setupSafeDealService(t, adminID)
+		deal := newTestDeal(entity.DealStatusDisputed)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.Resolve(context.Background(), deal.ID, adminID, "split")
+		assert.ErrorIs(t, err, usecase.ErrDealBadRequest)
+	})
+
+	t.Run("NotDisputed", func(t *testing.T) {
+		service, deps := setupSafeDealService(t, adminID)
+		deal := newTestDeal(entity.DealStatusShipped)
+
+		deps.dealRepo.EXPECT().GetById(gomock.Any(), deal.ID).Return(deal, nil)
+
+		_, err := service.Resolve(context.Background(), deal.ID, adminID, entity.DealResolutionRelease)
+		assert.ErrorIs(t, err, usecase.ErrDealInvalidState)
+	})
+}
+
+func TestSafeDealService_GetDisputes(t *testing.T) {
+	adminID := uuid.New()
+	service, deps := setupSafeDealService(t, adminID)
+	deal := newTestDeal(entity.DealStatusDisputed)
+
+	deps.dealRepo.EXPECT().GetDisputed(gomock.Any(), 10, 0).Return([]*entity.SafeDeal{deal}, nil)
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID, deal.BuyerID).Return(&dto.PaymentResponse{}, nil)
+
+	disputes, err := service.GetDisputes(context.Background(), adminID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, disputes, 1)
+	assert.Equal(t, deal.ID, disputes[0].ID)
+
+	_, err = service.GetDisputes(context.Background(), deal.BuyerID, 10, 0)
+	assert.ErrorIs(t, err, usecase.ErrDealForbidden)
+}
+
+func TestSafeDealService_ProcessExpired(t *testing.T) {
+	service, deps := setupSafeDealService(t)
+	notShipped := newTestDeal(entity.DealStatusHeld)
+	notConfirmed := newTestDeal(entity.DealStatusShipped)
+	failing := newTestDeal(entity.DealStatusShipped)
+	heldPayment, shippedPayment := &dto.PaymentResponse{ID: uuid.New()}, &dto.PaymentResponse{ID: uuid.New()}
+
+	deps.dealRepo.EXPECT().GetExpired(gomock.Any(), testShipTimeout, testConfirmTimeout, testSettleTimeout, 10).
+		Return([]*entity.SafeDeal{failing, notShipped, notConfirmed}, nil)
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), failing.PurchaseID, failing.BuyerID).
+		Return(nil, errors.New("db is down"))
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), notShipped.PurchaseID, notShipped.BuyerID).Return(heldPayment, nil)
+	deps.expectTransition(t, notShipped.ID, entity.DealStatusHeld, entity.DealStatusRefunding, true)
+	deps.paymentUC.EXPECT().Cancel(gomock.Any(), heldPayment.ID).Return(heldPayment, nil)
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), notConfirmed.PurchaseID, notConfirmed.BuyerID).Return(shippedPayment, nil)
+	deps.expectTransition(t, notConfirmed.ID, entity.DealStatusShipped, entity.DealStatusReleasing, true)
+	deps.paymentUC.EXPECT().Capture(gomock.Any(), shippedPayment.ID).Return(shippedPayment, nil)
+
+	assert.NoError(t, service.ProcessExpired(context.Background()))
+}
+
+func TestSafeDealService_ProcessExpired_StuckSettlement(t *testing.T) {
+	service, deps := setupSafeDealService(t)
+	releasing := newTestDeal(entity.DealStatusReleasing)
+	refunding := newTestDeal(entity.DealStatusRefunding)
+	failing := newTestDeal(entity.DealStatusRefunding)
+	releasingPayment, refundingPayment := &dto.PaymentResponse{ID: uuid.New()}, &dto.PaymentResponse{ID: uuid.New()}
+	failingPayment := &dto.PaymentResponse{ID: uuid.New()}
+
+	deps.dealRepo.EXPECT().GetExpired(gomock.Any(), testShipTimeout, testConfirmTimeout, testSettleTimeout, 10).
+		Return([]*entity.SafeDeal{releasing, refunding, failing}, nil)
+	// сделка уже в промежуточном статусе: расчет повторяется без смены статуса
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), releasing.PurchaseID, releasing.BuyerID).Return(releasingPayment, nil)
+	deps.paymentUC.EXPECT().Capture(gomock.Any(), releasingPayment.ID).Return(releasingPayment, nil)
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), refunding.PurchaseID, refunding.BuyerID).Return(refundingPayment, nil)
+	deps.paymentUC.EXPECT().Cancel(gomock.Any(), refundingPayment.ID).Return(refundingPayment, nil)
+	// неудачный повтор не возвращает сделку на прежний этап, она будет выбрана снова
+	deps.paymentUC.EXPECT().GetByPurchaseId(gomock.Any(), failing.PurchaseID, failing.BuyerID).Return(failingPayment, nil)
+	deps.paymentUC.EXPECT().Cancel(gomock.Any(), failingPayment.ID).Return(nil, errors.New("gateway is down"))
+
+	assert.NoError(t, service.ProcessExpired(context.Background()))
+}
@@ -0,0 +1,495 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeliveryService считает доставку по способам, которые продавцы указали в объявлениях,
+// и отслеживает отправления у перевозчика. Объявления одного продавца уезжают одной
+// посылкой: ее стоимость и срок - максимальные среди объявлений продавца
+type DeliveryService struct {
+	deliveryRepo repository.DeliveryRepository
+	purchaseRepo repository.PurchaseRepository
+	cartRepo     repository.Cart
+	advertRepo   repository.AdvertRepository
+	sellerRepo   repository.Seller
+	carrier      repository.CarrierProvider
+	batchSize    int
+}
+
+func NewDeliveryService(deliveryRepo repository.DeliveryRepository,
+	purchaseRepo repository.PurchaseRepository,
+	cartRepo repository.Cart,
+	advertRepo repository.AdvertRepository,
+	sellerRepo repository.Seller,
+	carrier repository.CarrierProvider,
+	batchSize int) *DeliveryService {
+	return &DeliveryService{
+		deliveryRepo: deliveryRepo,
+		purchaseRepo: purchaseRepo,
+		cartRepo:     cartRepo,
+		advertRepo:   advertRepo,
+		sellerRepo:   sellerRepo,
+		carrier:      carrier,
+		batchSize:    batchSize,
+	}
+}
+
+func deliveryOptionEntityToDTO(option *entity.DeliveryOption) *dto.DeliveryOption {
+	return &dto.DeliveryOption{
+		Type:    string(option.Type),
+		Price:   option.Price,
+		MinDays: option.MinDays,
+		MaxDays: option.MaxDays,
+	}
+}
+
+func shipmentEntityToDTO(shipment *entity.Shipment, events []entity.ShipmentEvent) *dto.ShipmentResponse {
+	response := &dto.ShipmentResponse{
+		ID:             shipment.ID,
+		PurchaseID:     shipment.PurchaseID,
+		SellerID:       shipment.SellerID,
+		Type:           string(shipment.Type),
+		Cost:           shipment.Cost,
+		MinDays:        shipment.MinDays,
+		MaxDays:        shipment.MaxDays,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         string(shipment.Status),
+		ShippedAt:      shipment.ShippedAt,
+		DeliveredAt:    shipment.DeliveredAt,
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, dto.ShipmentEvent{
+			Status:      string(event.Status),
+			Description: event.Description,
+			Location:    event.Location,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+	return response
+}
+
+func (s *DeliveryService) GetOptions(ctx context.Context, advertID uuid.UUID) ([]*dto.DeliveryOption, error) {
+	exists, err := s.advertRepo.CheckIfExists(ctx, advertID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error checking advert"))
+	}
+	if !exists {
+		return nil, usecase.ErrDeliveryAdvertNotFound
+	}
+
+	options, err := s.deliveryRepo.GetOptions(ctx, advertID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting delivery options"))
+	}
+	result := make([]*dto.DeliveryOption, 0, len(options))
+	for _, option := range options {
+		result = append(result, deliveryOptionEntityToDTO(option))
+	}
+	return result, nil
+}
+
+// checkOwner проверяет, что объявление принадлежит продавцу-пользователю userID
+func (s *DeliveryService) checkOwner(ctx context.Context, advertID, userID uuid.UUID) error {
+	seller, err := s.sellerRepo.GetByUserId(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSellerNotFound) {
+			return usecase.ErrDeliveryForbidden
+		}
+		return entity.UsecaseWrap(err, errors.New("error getting seller"))
+	}
+
+	advert, err := s.advertRepo.GetById(ctx, advertID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAdvertNotFound) {
+			return usecase.ErrDeliveryAdvertNotFound
+		}
+		return entity.UsecaseWrap(err, errors.New("error getting advert"))
+	}
+	if advert.SellerId != seller.ID {
+		return usecase.ErrDeliveryForbidden
+	}
+	return nil
+}
+
+func validateDeliveryOptions(advertID uuid.UUID, options []*dto.DeliveryOption) ([]*entity.DeliveryOption, error) {
+	seen := make(map[entity.DeliveryOptionType]struct{}, len(options))
+	result := make([]*entity.DeliveryOption, 0, len(options))
+	for _, option := range options {
+		optionType := entity.DeliveryOptionType(option.Type)
+		if _, ok := seen[optionType]; ok || !optionType.Valid() {
+			return nil, usecase.ErrDeliveryBadRequest
+		}
+		if option.MinDays > option.MaxDays || option.MaxDays > entity.MaxDeliveryDays || option.Price > entity.MaxDeliveryPrice {
+			return nil, usecase.ErrDeliveryBadRequest
+		}
+		seen[optionType] = struct{}{}
+		result = append(result, &entity.DeliveryOption{
+			AdvertID: advertID,
+			Type:     optionType,
+			Price:    option.Price,
+			MinDays:  option.MinDays,
+			MaxDays:  option.MaxDays,
+		})
+	}
+	return result, nil
+}
+
+func (s *DeliveryService) SetOptions(ctx context.Context, advertID, userID uuid.UUID, options []*dto.DeliveryOption) ([]*dto.DeliveryOption, error) {
+	if err := s.checkOwner(ctx, advertID, userID); err != nil {
+		return nil, err
+	}
+	entities, err := validateDeliveryOptions(advertID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.deliveryRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	if err := s.deliveryRepo.ReplaceOptions(ctx, tx, advertID, entities); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, entity.UsecaseWrap(err, errors.New("error replacing delivery options"))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	middleware.GetLogger(ctx).Info("advert delivery options updated",
+		zap.String("advert_id", advertID.String()), zap.Int("options", len(entities)))
+	return s.GetOptions(ctx, advertID)
+}
+
+// cartDelivery возвращает объявления корзины пользователя userID и их способы доставки
+func (s *DeliveryService) cartDelivery(ctx context.Context, cartID, userID uuid.UUID) ([]*entity.Advert,
+	map[uuid.UUID]map[entity.DeliveryOptionType]*entity.DeliveryOption, error) {
+	cart, err := s.cartRepo.GetById(ctx, cartID)
+	if err != nil {
+		return nil, nil, entity.UsecaseWrap(err, errors.New("error getting cart"))
+	}
+	if cart.UserID != userID {
+		return nil, nil, usecase.ErrDeliveryForbidden
+	}
+
+	adverts, err := s.advertRepo.GetByCartId(ctx, cartID, userID)
+	if err != nil {
+		return nil, nil, entity.UsecaseWrap(err, errors.New("error getting adverts"))
+	}
+	advertIDs := make([]uuid.UUID, 0, len(adverts))
+	for _, advert := range adverts {
+		advertIDs = append(advertIDs, advert.ID)
+	}
+	options, err := s.deliveryRepo.GetOptionsByAdverts(ctx, advertIDs)
+	if err != nil {
+		return nil, nil, entity.UsecaseWrap(err, errors.New("error getting delivery options"))
+	}
+
+	byAdvert := make(map[uuid.UUID]map[entity.DeliveryOptionType]*entity.DeliveryOption, len(adverts))
+	for _, option := range options {
+		if byAdvert[option.AdvertID] == nil {
+			byAdvert[option.AdvertID] = make(map[entity.DeliveryOptionType]*entity.DeliveryOption)
+		}
+		byAdvert[option.AdvertID][option.Type] = option
+	}
+	return adverts, byAdvert, nil
+}
+
+// buildQuote считает доставку способом optionType. Возвращает false, если способа нет
+// хотя бы у одного объявления
+func buildQuote(adverts []*entity.Advert, options map[uuid.UUID]map[entity.DeliveryOptionType]*entity.DeliveryOption,
+	optionType entity.DeliveryOptionType) (*dto.DeliveryQuote, bool) {
+	quote := &dto.DeliveryQuote{Type: string(optionType)}
+	sellers := make(map[uuid.UUID]int)
+	for _, advert := range adverts {
+		option, ok := options[advert.ID][optionType]
+		if !ok {
+			return nil, false
+		}
+		i, ok := sellers[advert.SellerId]
+		if !ok {
+			i = len(quote.Shipments)
+			sellers[advert.SellerId] = i
+			quote.Shipments = append(quote.Shipments, dto.ShipmentQuote{SellerID: advert.SellerId})
+		}
+		shipment := &quote.Shipments[i]
+		shipment.Cost = max(shipment.Cost, option.Price)
+		shipment.MinDays = max(shipment.MinDays, option.MinDays)
+		shipment.MaxDays = max(shipment.MaxDays, option.MaxDays)
+	}
+	for _, shipment := range quote.Shipments {
+		quote.Cost += shipment.Cost
+		quote.MinDays = max(quote.MinDays, shipment.MinDays)
+		quote.MaxDays = max(quote.MaxDays, shipment.MaxDays)
+	}
+	return quote, true
+}
+
+func (s *DeliveryService) GetQuotes(ctx context.Context, cartID, userID uuid.UUID) ([]*dto.DeliveryQuote, error) {
+	adverts, options, err := s.cartDelivery(ctx, cartID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]*dto.DeliveryQuote, 0, len(entity.DeliveryOptionTypes))
+	if len(adverts) == 0 {
+		return quotes, nil
+	}
+	for _, optionType := range entity.DeliveryOptionTypes {
+		if quote, ok := buildQuote(adverts, options, optionType); ok {
+			quotes = append(quotes, quote)
+		}
+	}
+	return quotes, nil
+}
+
+func (s *DeliveryService) Quote(ctx context.Context, cartID, userID uuid.UUID, optionType entity.DeliveryOptionType) (*dto.DeliveryQuote, error) {
+	if !optionType.Valid() {
+		return nil, usecase.ErrDeliveryBadRequest
+	}
+	adverts, options, err := s.cartDelivery(ctx, cartID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(adverts) == 0 {
+		return nil, usecase.ErrDeliveryBadRequest
+	}
+
+	quote, ok := buildQuote(adverts, options, optionType)
+	if !ok {
+		return nil, usecase.ErrDeliveryOptionUnavailable
+	}
+	return quote, nil
+}
+
+func (s *DeliveryService) CreateShipments(ctx context.Context, purchaseID, userID uuid.UUID, optionType entity.DeliveryOptionType) ([]*dto.ShipmentResponse, error) {
+	purchase, err := s.purchaseRepo.GetById(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting purchase"))
+	}
+
+	cart, err := s.cartRepo.GetById(ctx, purchase.CartID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting cart"))
+	}
+	if cart.UserID != userID {
+		return nil, usecase.ErrDeliveryForbidden
+	}
+
+	existing, err := s.deliveryRepo.GetShipmentsByPurchaseId(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting shipments"))
+	}
+	if len(existing) > 0 {
+		return s.shipmentsToDTO(ctx, existing)
+	}
+
+	if purchase.DeliveryMethod != entity.DeliveryMethodDelivery {
+		return nil, usecase.ErrDeliveryBadRequest
+	}
+	quote, err := s.Quote(ctx, purchase.CartID, userID, optionType)
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*entity.Shipment, 0, len(quote.Shipments))
+	for _, shipment := range quote.Shipments {
+		shipments = append(shipments, &entity.Shipment{
+			PurchaseID: purchaseID,
+			SellerID:   shipment.SellerID,
+			BuyerID:    userID,
+			Type:       optionType,
+			Cost:       shipment.Cost,
+			MinDays:    shipment.MinDays,
+			MaxDays:    shipment.MaxDays,
+		})
+	}
+
+	tx, err := s.deliveryRepo.BeginTransaction(ctx)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	if err := s.deliveryRepo.AddShipments(ctx, tx, shipments); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, entity.UsecaseWrap(err, errors.New("error adding shipments"))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	middleware.GetLogger(ctx).Info("shipments created", zap.String("purchase_id", purchaseID.String()),
+		zap.String("type", string(optionType)), zap.Uint("cost", quote.Cost))
+
+	// перечитываются и отправления, созданные параллельным запросом
+	created, err := s.deliveryRepo.GetShipmentsByPurchaseId(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting shipments"))
+	}
+	return s.shipmentsToDTO(ctx, created)
+}
+
+func (s *DeliveryService) shipmentsToDTO(ctx context.Context, shipments []*entity.Shipment) ([]*dto.ShipmentResponse, error) {
+	result := make([]*dto.ShipmentResponse, 0, len(shipments))
+	for _, shipment := range shipments {
+		events, err := s.deliveryRepo.GetEvents(ctx, shipment.ID)
+		if err != nil {
+			return nil, entity.UsecaseWrap(err, errors.New("error getting shipment events"))
+		}
+		result = append(result, shipmentEntityToDTO(shipment, events))
+	}
+	return result, nil
+}
+
+func (s *DeliveryService) GetShipments(ctx context.Context, purchaseID, userID uuid.UUID) ([]*dto.ShipmentResponse, error) {
+	purchase, err := s.purchaseRepo.GetById(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting purchase"))
+	}
+	cart, err := s.cartRepo.GetById(ctx, purchase.CartID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting cart"))
+	}
+	shipments, err := s.deliveryRepo.GetShipmentsByPurchaseId(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting shipments"))
+	}
+
+	if cart.UserID != userID {
+		var own []*entity.Shipment
+		for _, shipment := range shipments {
+			if shipment.SellerUserID == userID {
+				own = append(own, shipment)
+			}
+		}
+		if len(own) == 0 {
+			return nil, usecase.ErrDeliveryForbidden
+		}
+		shipments = own
+	}
+	return s.shipmentsToDTO(ctx, shipments)
+}
+
+func (s *DeliveryService) SetTracking(ctx context.Context, shipmentID, userID uuid.UUID, trackingNumber string) (*dto.ShipmentResponse, error) {
+	trackingNumber = strings.ToUpper(strings.TrimSpace(trackingNumber))
+	if trackingNumber == "" {
+		return nil, usecase.ErrDeliveryBadRequest
+	}
+
+	shipment, err := s.deliveryRepo.GetShipmentById(ctx, shipmentID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting shipment"))
+	}
+	if shipment.SellerUserID != userID {
+		return nil, usecase.ErrDeliveryForbidden
+	}
+	if shipment.Status != entity.ShipmentStatusAwaitingShipment {
+		return nil, usecase.ErrShipmentInvalidState
+	}
+
+	if err := s.carrier.Register(ctx, trackingNumber); err != nil {
+		if errors.Is(err, repository.ErrCarrierInvalidTracking) {
+			return nil, entity.UsecaseWrap(usecase.ErrDeliveryBadRequest, err)
+		}
+		return nil, entity.UsecaseWrap(err, errors.New("error registering tracking number"))
+	}
+	set, err := s.deliveryRepo.SetTracking(ctx, shipmentID, s.carrier.Name(), trackingNumber)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error setting tracking number"))
+	}
+	if !set {
+		return nil, usecase.ErrShipmentInvalidState
+	}
+
+	middleware.GetLogger(ctx).Info("shipment tracking number set", zap.String("shipment_id", shipmentID.String()),
+		zap.String("carrier", s.carrier.Name()))
+
+	shipment, err = s.deliveryRepo.GetShipmentById(ctx, shipmentID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error getting shipment"))
+	}
+	return shipmentEntityToDTO(shipment, nil), nil
+}
+
+func (s *DeliveryService) RefreshTracking(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	shipments, err := s.deliveryRepo.GetTrackable(ctx, s.batchSize)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	var changed int
+	for _, shipment := range shipments {
+		updated, err := s.track(ctx, shipment)
+		if err != nil {
+			logger.Error("failed to refresh shipment tracking", zap.Error(err), zap.String("shipment_id", shipment.ID.String()))
+			continue
+		}
+		if updated {
+			changed++
+		}
+	}
+
+	if changed > 0 {
+		logger.Info("shipment tracking refreshed", zap.Int("changed", changed))
+	}
+	return nil
+}
+
+// track сохраняет статус и историю отправления от перевозчика. Время проверки обновляется
+// и при ошибке перевозчика, чтобы отправление не занимало место в каждой пачке
+func (s *DeliveryService) track(ctx context.Context, shipment *entity.Shipment) (bool, error) {
+	next := shipment.Status
+	var events []entity.ShipmentEvent
+	var trackErr error
+
+	switch {
+	case shipment.Carrier != s.carrier.Name():
+		trackErr = errors.New("shipment is tracked by another carrier: " + shipment.Carrier)
+	default:
+		info, err := s.carrier.Track(ctx, shipment.TrackingNumber)
+		if err != nil {
+			trackErr = entity.UsecaseWrap(errors.New("error tracking shipment"), err)
+			break
+		}
+		if shipment.Status.CanChangeTo(info.Status) {
+			next = info.Status
+		}
+		for _, event := range info.Events {
+			event.ShipmentID = shipment.ID
+			events = append(events, event)
+		}
+	}
+
+	tx, err := s.deliveryRepo.BeginTransaction(ctx)
+	if err != nil {
+		return false, entity.UsecaseWrap(err, errors.New("error beginning transaction"))
+	}
+	if err := s.deliveryRepo.AddEvents(ctx, tx, events); err != nil {
+		_ = tx.Rollback(ctx)
+		return false, entity.UsecaseWrap(err, errors.New("error adding shipment events"))
+	}
+	if _, err := s.deliveryRepo.UpdateTracking(ctx, tx, shipment.ID, shipment.Status, next); err != nil {
+		_ = tx.Rollback(ctx)
+		return false, entity.UsecaseWrap(err, errors.New("error updating shipment tracking"))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, entity.UsecaseWrap(err, errors.New("error committing transaction"))
+	}
+
+	if trackErr != nil {
+		return false, trackErr
+	}
+	return next != shipment.Status, nil
+}
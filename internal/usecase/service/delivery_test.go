@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deliveryTestDeps struct {
+	deliveryRepo *mocks.MockDeliveryRepository
+	purchaseRepo *mocks.MockPurchaseRepository
+	cartRepo     *mocks.MockCart
+	advertRepo   *mocks.MockAdvertRepository
+	sellerRepo   *mocks.MockSeller
+	carrier      *mocks.MockCarrierProvider
+	pools        []pgxmock.PgxPoolIface
+}
+
+func setupDeliveryService(t *testing.T) (*DeliveryService, *deliveryTestDeps) {
+	ctrl := gomock.NewController(t)
+	deps := &deliveryTestDeps{
+		deliveryRepo: mocks.NewMockDeliveryRepository(ctrl),
+		purchaseRepo: mocks.NewMockPurchaseRepository(ctrl),
+		cartRepo:     mocks.NewMockCart(ctrl),
+		advertRepo:   mocks.NewMockAdvertRepository(ctrl),
+		sellerRepo:   mocks.NewMockSeller(ctrl),
+		carrier:      mocks.NewMockCarrierProvider(ctrl),
+	}
+	deps.carrier.EXPECT().Name().Return("fake").AnyTimes()
+	service := NewDeliveryService(deps.deliveryRepo, deps.purchaseRepo, deps.cartRepo, deps.advertRepo,
+		deps.sellerRepo, deps.carrier, 10)
+	return service, deps
+}
+
+// expectTx ожидает очередную транзакцию, которая завершится commit или rollback
+func (d *deliveryTestDeps) expectTx(t *testing.T, commit bool) pgx.Tx {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	d.pools = append(d.pools, pool)
+
+	pool.ExpectBegin()
+	tx, err := pool.Begin(context.Background())
+	require.NoError(t, err)
+	if commit {
+		pool.ExpectCommit()
+	} else {
+		pool.ExpectRollback()
+	}
+	d.deliveryRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	return tx
+}
+
+func (d *deliveryTestDeps) assertTxDone(t *testing.T) {
+	for _, pool := range d.pools {
+		assert.NoError(t, pool.ExpectationsWereMet())
+	}
+}
+
+func TestDeliveryService_SetOptions(t *testing.T) {
+	advertID, userID, sellerID := uuid.New(), uuid.New(), uuid.New()
+	options := []*dto.DeliveryOption{
+		{Type: "courier", Price: 500, MinDays: 1, MaxDays: 2},
+		{Type: "post", Price: 300, MinDays: 5, MaxDays: 10},
+	}
+
+	expectOwner := func(deps *deliveryTestDeps, advertSellerID uuid.UUID) {
+		deps.sellerRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(&entity.Seller{ID: sellerID, UserID: userID}, nil)
+		deps.advertRepo.EXPECT().GetById(gomock.Any(), advertID, userID).
+			Return(&entity.Advert{ID: advertID, SellerId: advertSellerID}, nil)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectOwner(deps, sellerID)
+		tx := deps.expectTx(t, true)
+		deps.deliveryRepo.EXPECT().ReplaceOptions(gomock.Any(), tx, advertID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ pgx.Tx, _ uuid.UUID, saved []*entity.DeliveryOption) error {
+				require.Len(t, saved, 2)
+				assert.Equal(t, entity.DeliveryOptionCourier, saved[0].Type)
+				assert.Equal(t, advertID, saved[1].AdvertID)
+				return nil
+			})
+		deps.advertRepo.EXPECT().CheckIfExists(gomock.Any(), advertID).Return(true, nil)
+		deps.deliveryRepo.EXPECT().GetOptions(gomock.Any(), advertID).Return([]*entity.DeliveryOption{
+			{AdvertID: advertID, Type: entity.DeliveryOptionCourier, Price: 500, MinDays: 1, MaxDays: 2},
+		}, nil)
+
+		result, err := service.SetOptions(context.Background(), advertID, userID, options)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "courier", result[0].Type)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("AnotherSeller", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectOwner(deps, uuid.New())
+
+		_, err := service.SetOptions(context.Background(), advertID, userID, options)
+		assert.ErrorIs(t, err, usecase.ErrDeliveryForbidden)
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		invalid := [][]*dto.DeliveryOption{
+			{{Type: "drone", Price: 100, MinDays: 1, MaxDays: 1}},
+			{{Type: "post", Price: 100, MinDays: 5, MaxDays: 1}},
+			{{Type: "post", Price: 100, MinDays: 1, MaxDays: entity.MaxDeliveryDays + 1}},
+			{{Type: "post", Price: 100, MinDays: 1, MaxDays: 2}, {Type: "post", Price: 200, MinDays: 1, MaxDays: 2}},
+		}
+		for _, options := range invalid {
+			service, deps := setupDeliveryService(t)
+			expectOwner(deps, sellerID)
+
+			_, err := service.SetOptions(context.Background(), advertID, userID, options)
+			assert.ErrorIs(t, err, usecase.ErrDeliveryBadRequest)
+		}
+	})
+}
+
+func TestDeliveryService_Quote(t *testing.T) {
+	userID, cartID, sellerA, sellerB := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	adverts := []*entity.Advert{
+		{ID: uuid.New(), SellerId: sellerA},
+		{ID: uuid.New(), SellerId: sellerA},
+		{ID: uuid.New(), SellerId: sellerB},
+	}
+	options := []*entity.DeliveryOption{
+		{AdvertID: adverts[0].ID, Type: entity.DeliveryOptionCourier, Price: 400, MinDays: 1, MaxDays: 2},
+		{AdvertID: adverts[1].ID, Type: entity.DeliveryOptionCourier, Price: 600, MinDays: 2, MaxDays: 3},
+		{AdvertID: adverts[2].ID, Type: entity.DeliveryOptionCourier, Price: 300, MinDays: 1, MaxDays: 5},
+		{AdvertID: adverts[0].ID, Type: entity.DeliveryOptionPost, Price: 200, MinDays: 5, MaxDays: 10},
+		{AdvertID: adverts[1].ID, Type: entity.DeliveryOptionPost, Price: 200, MinDays: 5, MaxDays: 10},
+	}
+
+	expectCart := func(deps *deliveryTestDeps, owner uuid.UUID) {
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: owner}, nil)
+	}
+	expectAdverts := func(deps *deliveryTestDeps) {
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return(adverts, nil)
+		deps.deliveryRepo.EXPECT().GetOptionsByAdverts(gomock.Any(), gomock.Len(3)).Return(options, nil)
+	}
+
+	t.Run("GroupedBySeller", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectCart(deps, userID)
+		expectAdverts(deps)
+
+		quote, err := service.Quote(context.Background(), cartID, userID, entity.DeliveryOptionCourier)
+		require.NoError(t, err)
+		assert.Equal(t, uint(900), quote.Cost)
+		assert.Equal(t, uint(2), quote.MinDays)
+		assert.Equal(t, uint(5), quote.MaxDays)
+		require.Len(t, quote.Shipments, 2)
+		assert.Equal(t, dto.ShipmentQuote{SellerID: sellerA, Cost: 600, MinDays: 2, MaxDays: 3}, quote.Shipments[0])
+		assert.Equal(t, dto.ShipmentQuote{SellerID: sellerB, Cost: 300, MinDays: 1, MaxDays: 5}, quote.Shipments[1])
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectCart(deps, userID)
+		expectAdverts(deps)
+
+		_, err := service.Quote(context.Background(), cartID, userID, entity.DeliveryOptionPost)
+		assert.ErrorIs(t, err, usecase.ErrDeliveryOptionUnavailable)
+	})
+
+	t.Run("AllQuotes", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectCart(deps, userID)
+		expectAdverts(deps)
+
+		quotes, err := service.GetQuotes(context.Background(), cartID, userID)
+		require.NoError(t, err)
+		require.Len(t, quotes, 1)
+		assert.Equal(t, "courier", quotes[0].Type)
+	})
+
+	t.Run("AnotherUser", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectCart(deps, uuid.New())
+
+		_, err := service.Quote(context.Background(), cartID, userID, entity.DeliveryOptionCourier)
+		assert.ErrorIs(t, err, usecase.ErrDeliveryForbidden)
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		service, _ := setupDeliveryService(t)
+
+		_, err := service.Quote(context.Background(), cartID, userID, "drone")
+		assert.ErrorIs(t, err, usecase.ErrDeliveryBadRequest)
+	})
+}
+
+func TestDeliveryService_CreateShipments(t *testing.T) {
+	userID, purchaseID, cartID, sellerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	purchase := &entity.Purchase{ID: purchaseID, CartID: cartID, DeliveryMethod: entity.DeliveryMethodDelivery}
+	advert := &entity.Advert{ID: uuid.New(), SellerId: sellerID}
+	shipment := &entity.Shipment{ID: uuid.New(), PurchaseID: purchaseID, SellerID: sellerID,
+		Type: entity.DeliveryOptionParcelLocker, Cost: 250, Status: entity.ShipmentStatusAwaitingShipment}
+
+	expectOwnedPurchase := func(deps *deliveryTestDeps, purchase *entity.Purchase) {
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(purchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectOwnedPurchase(deps, purchase)
+		gomock.InOrder(
+			deps.deliveryRepo.EXPECT().GetShipmentsByPurchaseId(gomock.Any(), purchaseID).Return(nil, nil),
+			deps.deliveryRepo.EXPECT().GetShipmentsByPurchaseId(gomock.Any(), purchaseID).
+				Return([]*entity.Shipment{shipment}, nil),
+		)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return([]*entity.Advert{advert}, nil)
+		deps.deliveryRepo.EXPECT().GetOptionsByAdverts(gomock.Any(), []uuid.UUID{advert.ID}).
+			Return([]*entity.DeliveryOption{{AdvertID: advert.ID, Type: entity.DeliveryOptionParcelLocker, Price: 250,
+				MinDays: 2, MaxDays: 4}}, nil)
+		tx := deps.expectTx(t, true)
+		deps.deliveryRepo.EXPECT().AddShipments(gomock.Any(), tx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ pgx.Tx, shipments []*entity.Shipment) error {
+				require.Len(t, shipments, 1)
+				assert.Equal(t, userID, shipments[0].BuyerID)
+				assert.Equal(t, uint(250), shipments[0].Cost)
+				assert.Equal(t, uint(4), shipments[0].MaxDays)
+				return nil
+			})
+		deps.deliveryRepo.EXPECT().GetEvents(gomock.Any(), shipment.ID).Return(nil, nil)
+
+		result, err := service.CreateShipments(context.Background(), purchaseID, userID, entity.DeliveryOptionParcelLocker)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "awaiting_shipment", result[0].Status)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("AlreadyCreated", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectOwnedPurchase(deps, purchase)
+		deps.deliveryRepo.EXPECT().GetShipmentsByPurchaseId(gomock.Any(), purchaseID).
+			Return([]*entity.Shipment{shipment}, nil)
+		deps.deliveryRepo.EXPECT().GetEvents(gomock.Any(), shipment.ID).Return(nil, nil)
+
+		result, err := service.CreateShipments(context.Background(), purchaseID, userID, entity.DeliveryOptionCourier)
+		require.NoError(t, err)
+		assert.Equal(t, "parcel_locker", result[0].Type)
+	})
+
+	t.Run("Pickup", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		pickup := *purchase
+		pickup.DeliveryMethod = entity.DeliveryMethodPickup
+		expectOwnedPurchase(deps, &pickup)
+		deps.deliveryRepo.EXPECT().GetShipmentsByPurchaseId(gomock.Any(), purchaseID).Return(nil, nil)
+
+		_, err := service.CreateShipments(context.Background(), purchaseID, userID, entity.DeliveryOptionCourier)
+		assert.ErrorIs(t, err, usecase.ErrDeliveryBadRequest)
+	})
+}
+
+func TestDeliveryService_GetShipments(t *testing.T) {
+	buyerID, sellerUserID, purchaseID, cartID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	own := &entity.Shipment{ID: uuid.New(), SellerUserID: sellerUserID, Status: entity.ShipmentStatusInTransit}
+	other := &entity.Shipment{ID: uuid.New(), SellerUserID: uuid.New(), Status: entity.ShipmentStatusAwaitingShipment}
+
+	expectPurchase := func(deps *deliveryTestDeps) {
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(&entity.Purchase{ID: purchaseID, CartID: cartID}, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: buyerID}, nil)
+		deps.deliveryRepo.EXPECT().GetShipmentsByPurchaseId(gomock.Any(), purchaseID).
+			Return([]*entity.Shipment{own, other}, nil)
+	}
+
+	t.Run("Seller", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectPurchase(deps)
+		deps.deliveryRepo.EXPECT().GetEvents(gomock.Any(), own.ID).Return([]entity.ShipmentEvent{
+			{ShipmentID: own.ID, Status: entity.ShipmentStatusInTransit, Description: "Принято перевозчиком", OccurredAt: time.Now()},
+		}, nil)
+
+		result, err := service.GetShipments(context.Background(), purchaseID, sellerUserID)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, own.ID, result[0].ID)
+		require.Len(t, result[0].Events, 1)
+	})
+
+	t.Run("Buyer", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectPurchase(deps)
+		deps.deliveryRepo.EXPECT().GetEvents(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+		result, err := service.GetShipments(context.Background(), purchaseID, buyerID)
+		require.NoError(t, err)
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("Stranger", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		expectPurchase(deps)
+
+		_, err := service.GetShipments(context.Background(), purchaseID, uuid.New())
+		assert.ErrorIs(t, err, usecase.ErrDeliveryForbidden)
+	})
+}
+
+func TestDeliveryService_SetTracking(t *testing.T) {
+	sellerUserID := uuid.New()
+	newShipment := func(status entity.ShipmentStatus) *entity.Shipment {
+		return &entity.Shipment{ID: uuid.New(), SellerUserID: sellerUserID, Status: status}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := newShipment(entity.ShipmentStatusAwaitingShipment)
+		tracked := *shipment
+		tracked.Status, tracked.Carrier, tracked.TrackingNumber = entity.ShipmentStatusInTransit, "fake", "AB123456789RU"
+
+		gomock.InOrder(
+			deps.deliveryRepo.EXPECT().GetShipmentById(gomock.Any(), shipment.ID).Return(shipment, nil),
+			deps.deliveryRepo.EXPECT().GetShipmentById(gomock.Any(), shipment.ID).Return(&tracked, nil),
+		)
+		deps.carrier.EXPECT().Register(gomock.Any(), "AB123456789RU").Return(nil)
+		deps.deliveryRepo.EXPECT().SetTracking(gomock.Any(), shipment.ID, "fake", "AB123456789RU").Return(true, nil)
+
+		result, err := service.SetTracking(context.Background(), shipment.ID, sellerUserID, " ab123456789ru ")
+		require.NoError(t, err)
+		assert.Equal(t, "in_transit", result.Status)
+		assert.Equal(t, "AB123456789RU", result.TrackingNumber)
+	})
+
+	t.Run("InvalidTrackingNumber", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := newShipment(entity.ShipmentStatusAwaitingShipment)
+
+		deps.deliveryRepo.EXPECT().GetShipmentById(gomock.Any(), shipment.ID).Return(shipment, nil)
+		deps.carrier.EXPECT().Register(gomock.Any(), "12").Return(repository.ErrCarrierInvalidTracking)
+
+		_, err := service.SetTracking(context.Background(), shipment.ID, sellerUserID, "12")
+		assert.ErrorIs(t, err, usecase.ErrDeliveryBadRequest)
+	})
+
+	t.Run("AlreadyShipped", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := newShipment(entity.ShipmentStatusInTransit)
+
+		deps.deliveryRepo.EXPECT().GetShipmentById(gomock.Any(), shipment.ID).Return(shipment, nil)
+
+		_, err := service.SetTracking(context.Background(), shipment.ID, sellerUserID, "AB123456789RU")
+		assert.ErrorIs(t, err, usecase.ErrShipmentInvalidState)
+	})
+
+	t.Run("AnotherSeller", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := newShipment(entity.ShipmentStatusAwaitingShipment)
+
+		deps.deliveryRepo.EXPECT().GetShipmentById(gomock.Any(), shipment.ID).Return(shipment, nil)
+
+		_, err := service.SetTracking(context.Background(), shipment.ID, uuid.New(), "AB123456789RU")
+		assert.ErrorIs(t, err, usecase.ErrDeliveryForbidden)
+	})
+}
+
+func TestDeliveryService_RefreshTracking(t *testing.T) {
+	t.Run("StatusChanged", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := &entity.Shipment{ID: uuid.New(), Carrier: "fake", TrackingNumber: "AB123456789RU",
+			Status: entity.ShipmentStatusInTransit}
+		occurredAt := time.Now()
+
+		deps.deliveryRepo.EXPECT().GetTrackable(gomock.Any(), 10).Return([]*entity.Shipment{shipment}, nil)
+		deps.carrier.EXPECT().Track(gomock.Any(), "AB123456789RU").Return(&entity.TrackingInfo{
+			Status: entity.ShipmentStatusDelivered,
+			Events: []entity.ShipmentEvent{{Status: entity.ShipmentStatusDelivered, Description: "Вручено", OccurredAt: occurredAt}},
+		}, nil)
+		tx := deps.expectTx(t, true)
+		deps.deliveryRepo.EXPECT().AddEvents(gomock.Any(), tx, []entity.ShipmentEvent{{ShipmentID: shipment.ID,
+			Status: entity.ShipmentStatusDelivered, Description: "Вручено", OccurredAt: occurredAt}}).Return(nil)
+		deps.deliveryRepo.EXPECT().UpdateTracking(gomock.Any(), tx, shipment.ID, entity.ShipmentStatusInTransit,
+			entity.ShipmentStatusDelivered).Return(true, nil)
+
+		require.NoError(t, service.RefreshTracking(context.Background()))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("BackwardStatusIgnored", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := &entity.Shipment{ID: uuid.New(), Carrier: "fake", TrackingNumber: "AB123456789RU",
+			Status: entity.ShipmentStatusReadyForPickup}
+
+		deps.deliveryRepo.EXPECT().GetTrackable(gomock.Any(), 10).Return([]*entity.Shipment{shipment}, nil)
+		deps.carrier.EXPECT().Track(gomock.Any(), "AB123456789RU").
+			Return(&entity.TrackingInfo{Status: entity.ShipmentStatusInTransit}, nil)
+		tx := deps.expectTx(t, true)
+		deps.deliveryRepo.EXPECT().AddEvents(gomock.Any(), tx, gomock.Any()).Return(nil)
+		deps.deliveryRepo.EXPECT().UpdateTracking(gomock.Any(), tx, shipment.ID, entity.ShipmentStatusReadyForPickup,
+			entity.ShipmentStatusReadyForPickup).Return(true, nil)
+
+		require.NoError(t, service.RefreshTracking(context.Background()))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("CarrierErrorStillMarksTracked", func(t *testing.T) {
+		service, deps := setupDeliveryService(t)
+		shipment := &entity.Shipment{ID: uuid.New(), Carrier: "fake", TrackingNumber: "AB123456789RU",
+			Status: entity.ShipmentStatusInTransit}
+
+		deps.deliveryRepo.EXPECT().GetTrackable(gomock.Any(), 10).Return([]*entity.Shipment{shipment}, nil)
+		deps.carrier.EXPECT().Track(gomock.Any(), "AB123456789RU").Return(nil, errors.New("carrier unavailable"))
+		tx := deps.expectTx(t, true)
+		deps.deliveryRepo.EXPECT().AddEvents(gomock.Any(), tx, gomock.Nil()).Return(nil)
+		deps.deliveryRepo.EXPECT().UpdateTracking(gomock.Any(), tx, shipment.ID, entity.ShipmentStatusInTransit,
+			entity.ShipmentStatusInTransit).Return(true, nil)
+
+		require.NoError(t, service.RefreshTracking(context.Background()))
+		deps.assertTxDone(t)
+	})
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"io"
+)
+
+const exifOrientationTag = 0x0112
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation возвращает значение тега Orientation из EXIF файла JPEG или WebP: 1 - без поворота,
+// 2-8 - отражения и повороты по спецификации EXIF. Если тега нет или он поврежден, возвращается 1
+func exifOrientation(data []byte) int {
+	return tiffOrientation(exifPayload(data))
+}
+
+// fileExifOrientation - exifOrientation для файла размером size: из файла читаются только
+// заголовки сегментов JPEG или чанков WebP и сам блок EXIF
+func fileExifOrientation(r io.ReaderAt, size int64) int {
+	return tiffOrientation(readExifPayload(r, size))
+}
+
+// tiffOrientation возвращает значение тега Orientation из блока EXIF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	if order.Uint16(tiff[2:4]) != 42 {
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) != exifOrientationTag {
+			continue
+		}
+		// Orientation - одно значение типа SHORT, оно лежит в начале поля значения
+		orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+		return orientation
+	}
+	return 1
+}
+
+// exifPayload находит блок EXIF (заголовок TIFF и каталоги) в JPEG или WebP
+func exifPayload(data []byte) []byte {
+	switch {
+	case len(data) > 2 && data[0] == 0xFF && data[1] == 0xD8:
+		for _, segment := range jpegSegments(data) {
+			if segment.marker == 0xE1 && bytes.HasPrefix(segment.payload, exifHeader) {
+				return segment.payload[len(exifHeader):]
+			}
+		}
+	case isWebP(data):
+		for _, chunk := range webpChunks(data) {
+			if chunk.fourCC == "EXIF" {
+				return bytes.TrimPrefix(chunk.payload, exifHeader)
+			}
+		}
+	}
+	return nil
+}
+
+// maxExifSize - наибольший блок EXIF, который читается из файла: сегмент JPEG не бывает больше
+// 64 КБ, а чанк WebP большего размера считается поврежденным
+const maxExifSize = 1 << 16
+
+// readExifPayload находит блок EXIF в JPEG или WebP так же, как exifPayload, но читает файл
+// по частям
+func readExifPayload(r io.ReaderAt, size int64) []byte {
+	head := make([]byte, min(size, 12))
+	if _, err := r.ReadAt(head, 0); err != nil {
+		return nil
+	}
+	switch {
+	case len(head) > 2 && head[0] == 0xFF && head[1] == 0xD8:
+		header := make([]byte, 4)
+		for pos := int64(2); pos+4 <= size; {
+			if _, err := r.ReadAt(header, pos); err != nil || header[0] != 0xFF {
+				return nil
+			}
+			marker := header[1]
+			if marker == 0xFF {
+				pos++
+				continue
+			}
+			if marker == 0xDA || marker == 0xD9 {
+				return nil
+			}
+			length := int64(binary.BigEndian.Uint16(header[2:4]))
+			end := pos + 2 + length
+			if length < 2 || end > size {
+				return nil
+			}
+			if marker == 0xE1 {
+				payload := make([]byte, length-2)
+				if _, err := r.ReadAt(payload, pos+4); err != nil {
+					return nil
+				}
+				if bytes.HasPrefix(payload, exifHeader) {
+					return payload[len(exifHeader):]
+				}
+			}
+			pos = end
+		}
+	case isWebP(head):
+		header := make([]byte, 8)
+		for pos := int64(12); pos+8 <= size; {
+			if _, err := r.ReadAt(header, pos); err != nil {
+				return nil
+			}
+			chunkSize := int64(binary.LittleEndian.Uint32(header[4:8]))
+			if pos+8+chunkSize > size {
+				return nil
+			}
+			if string(header[:4]) == "EXIF" {
+				if chunkSize > maxExifSize {
+					return nil
+				}
+				payload := make([]byte, chunkSize)
+				if _, err := r.ReadAt(payload, pos+8); err != nil {
+					return nil
+				}
+				return bytes.TrimPrefix(payload, exifHeader)
+			}
+			// Чанки выравниваются по четной границе
+			pos += 8 + chunkSize + chunkSize&1
+		}
+	}
+	return nil
+}
+
+// orientImage поворачивает и отражает img так, чтобы он выглядел как задумано съемкой с ориентацией orientation
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	src, ok := img.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		src = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	}
+	width, height := bounds.Dx(), bounds.Dy()
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2: // отражение по горизонтали
+				srcX, srcY = width-1-x, y
+			case 3: // поворот на 180°
+				srcX, srcY = width-1-x, height-1-y
+			case 4: // отражение по вертикали
+				srcX, srcY = x, height-1-y
+			case 5: // отражение относительно главной диагонали
+				srcX, srcY = y, x
+			case 6: // поворот на 90° по часовой стрелке
+				srcX, srcY = y, height-1-x
+			case 7: // отражение относительно побочной диагонали
+				srcX, srcY = width-1-y, height-1-x
+			case 8: // поворот на 90° против часовой стрелки
+				srcX, srcY = width-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(srcX, srcY):src.PixOffset(srcX, srcY)+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/chai2010/webp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
+
+// exifWithOrientation собирает блок EXIF с единственным тегом Orientation
+func exifWithOrientation(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifOrientationTag)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+	return append(append([]byte{}, exifHeader...), tiff...)
+}
+
+// jpegWithExif кодирует img в JPEG и вставляет сегмент APP1 с exif сразу после SOI
+func jpegWithExif(t *testing.T, img image.Image, exif []byte) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&buf, img, nil))
+	encoded := buf.Bytes()
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(exif)+2))
+	segment = append(segment, exif...)
+
+	return append(append(append([]byte{}, encoded[:2]...), segment...), encoded[2:]...)
+}
+
+// webpWithExif оборачивает WebP в расширенный формат VP8X с чанком EXIF
+func webpWithExif(t *testing.T, img image.Image, exif []byte) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, webp.Encode(&buf, img, &webp.Options{Lossless: true}))
+	simple := buf.Bytes()
+
+	bounds := img.Bounds()
+	vp8x := make([]byte, 18)
+	copy(vp8x, "VP8X")
+	binary.LittleEndian.PutUint32(vp8x[4:], 10)
+	vp8x[8] = webpFlagEXIF
+	putUint24 := func(b []byte, v int) { b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16) }
+	putUint24(vp8x[12:], bounds.Dx()-1)
+	putUint24(vp8x[15:], bounds.Dy()-1)
+
+	exifChunk := make([]byte, 8)
+	copy(exifChunk, "EXIF")
+	binary.LittleEndian.PutUint32(exifChunk[4:], uint32(len(exif)))
+	exifChunk = append(exifChunk, exif...)
+	if len(exif)%2 == 1 {
+		exifChunk = append(exifChunk, 0)
+	}
+
+	out := append([]byte("RIFF\x00\x00\x00\x00WEBP"), vp8x...)
+	out = append(out, simple[12:]...)
+	out = append(out, exifChunk...)
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out
+}
+
+func TestExifOrientation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+
+	assert.Equal(t, 6, exifOrientation(jpegWithExif(t, img, exifWithOrientation(binary.LittleEndian, 6))))
+	assert.Equal(t, 8, exifOrientation(jpegWithExif(t, img, exifWithOrientation(binary.BigEndian, 8))))
+	assert.Equal(t, 3, exifOrientation(webpWithExif(t, img, exifWithOrientation(binary.LittleEndian, 3))))
+
+	// Без EXIF, с поврежденным блоком или недопустимым значением ориентация не меняется
+	var plain bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&plain, img, nil))
+	assert.Equal(t, 1, exifOrientation(plain.Bytes()))
+	assert.Equal(t, 1, exifOrientation(jpegWithExif(t, img, exifHeader)))
+	assert.Equal(t, 1, exifOrientation(jpegWithExif(t, img, exifWithOrientation(binary.LittleEndian, 42))))
+	assert.Equal(t, 1, exifOrientation([]byte("not an image")))
+}
+
+func TestFileExifOrientation(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for _, data := range [][]byte{
+		jpegWithExif(t, img, exifWithOrientation(binary.LittleEndian, 6)),
+		jpegWithExif(t, img, exifWithOrientation(binary.BigEndian, 8)),
+		webpWithExif(t, img, exifWithOrientation(binary.LittleEndian, 3)),
+		jpegWithExif(t, img, exifHeader),
+		[]byte("not an image"),
+		nil,
+	} {
+		assert.Equal(t, exifOrientation(data), fileExifOrientation(bytes.NewReader(data), int64(len(data))))
+	}
+}
+
+func TestOrientImage(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, red)
+	img.Set(1, 0, blue)
+
+	tests := []struct {
+		orientation int
+		width       int
+		height      int
+		first       color.RGBA
+	}{
+		{1, 2, 1, red},
+		{2, 2, 1, blue},
+		{3, 2, 1, blue},
+		{6, 1, 2, red},
+		{8, 1, 2, blue},
+	}
+
+	for _, tt := range tests {
+		oriented := orientImage(img, tt.orientation)
+		assert.Equal(t, tt.width, oriented.Bounds().Dx(), "orientation %d", tt.orientation)
+		assert.Equal(t, tt.height, oriented.Bounds().Dy(), "orientation %d", tt.orientation)
+		assert.Equal(t, tt.first, color.RGBAModel.Convert(oriented.At(0, 0)), "orientation %d", tt.orientation)
+	}
+}
+
+func TestStripMetadata(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	exif := exifWithOrientation(binary.LittleEndian, 6)
+
+	t.Run("JPEG", func(t *testing.T) {
+		stripped := stripMetadata(entity.ImageFormatJPEG, jpegWithExif(t, img, exif))
+		assert.False(t, bytes.Contains(stripped, exifHeader))
+		decoded, err := jpeg.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+		assert.Equal(t, img.Bounds(), decoded.Bounds())
+	})
+
+	t.Run("WebP", func(t *testing.T) {
+		stripped := stripMetadata(entity.ImageFormatWebP, webpWithExif(t, img, exif))
+		assert.False(t, bytes.Contains(stripped, []byte("EXIF")))
+		assert.Zero(t, stripped[20]&webpFlagEXIF)
+		assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:8]))
+		decoded, err := webp.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+		assert.Equal(t, img.Bounds(), decoded.Bounds())
+	})
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+)
+
+type GuestCartService struct {
+	guestCartRepo repository.GuestCart
+	advertRepo    repository.AdvertRepository
+	maxAdverts    int
+}
+
+func NewGuestCartService(guestCartRepo repository.GuestCart, advertRepo repository.AdvertRepository, maxAdverts int) *GuestCartService {
+	return &GuestCartService{
+		guestCartRepo: guestCartRepo,
+		advertRepo:    advertRepo,
+		maxAdverts:    maxAdverts,
+	}
+}
+
+// AddAdvert проверяет только статус объявления: кто гость, неизвестно, поэтому его собственные
+// объявления отсеиваются при переносе в корзину пользователя
+func (g *GuestCartService) AddAdvert(ctx context.Context, guestID, advertID uuid.UUID) error {
+	state, err := g.advertRepo.GetSaleState(ctx, advertID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("error getting advert by id"), err)
+	}
+	if state.Status != entity.AdvertStatusActive {
+		return usecase.ErrCartAdvertUnavailable
+	}
+
+	return g.guestCartRepo.AddAdvert(ctx, guestID, advertID, g.maxAdverts)
+}
+
+func (g *GuestCartService) GetCart(ctx context.Context, guestID uuid.UUID) (dto.GuestCart, error) {
+	advertIDs, err := g.guestCartRepo.GetAdverts(ctx, guestID)
+	if err != nil {
+		return dto.GuestCart{}, err
+	}
+
+	cart := dto.GuestCart{Adverts: make([]dto.PreviewAdvertCard, 0, len(advertIDs))}
+	for _, advertID := range advertIDs {
+		advert, err := g.advertRepo.GetById(ctx, advertID, uuid.Nil)
+		switch {
+		case errors.Is(err, repository.ErrAdvertNotFound):
+			continue
+		case err != nil:
+			return dto.GuestCart{}, entity.UsecaseWrap(errors.New("error getting advert by id"), err)
+		}
+		cart.Adverts = append(cart.Adverts, convertAdvertToResponse(*advert))
+	}
+	return cart, nil
+}
+
+func (g *GuestCartService) DeleteAdvert(ctx context.Context, guestID, advertID uuid.UUID) error {
+	return g.guestCartRepo.DeleteAdvert(ctx, guestID, advertID)
+}
+
+func (g *GuestCartService) GetAdvertIDs(ctx context.Context, guestID uuid.UUID) ([]uuid.UUID, error) {
+	return g.guestCartRepo.GetAdverts(ctx, guestID)
+}
+
+func (g *GuestCartService) Clear(ctx context.Context, guestID uuid.UUID) error {
+	return g.guestCartRepo.Delete(ctx, guestID)
+}
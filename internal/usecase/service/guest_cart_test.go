@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testGuestCartLimit = 50
+
+func setupGuestCartService(t *testing.T) (*GuestCartService, *mocks.MockGuestCart, *mocks.MockAdvertRepository) {
+	ctrl := gomock.NewController(t)
+	guestCartRepo := mocks.NewMockGuestCart(ctrl)
+	advertRepo := mocks.NewMockAdvertRepository(ctrl)
+	return NewGuestCartService(guestCartRepo, advertRepo, testGuestCartLimit), guestCartRepo, advertRepo
+}
+
+func TestGuestCartService_AddAdvert(t *testing.T) {
+	guestID, advertID := uuid.New(), uuid.New()
+
+	testCases := []struct {
+		name          string
+		setupMocks    func(guestCartRepo *mocks.MockGuestCart, advertRepo *mocks.MockAdvertRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(guestCartRepo *mocks.MockGuestCart, advertRepo *mocks.MockAdvertRepository) {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusActive}, nil)
+				guestCartRepo.EXPECT().AddAdvert(gomock.Any(), guestID, advertID, testGuestCartLimit).Return(nil)
+			},
+		},
+		{
+			name: "Reserved Advert",
+			setupMocks: func(_ *mocks.MockGuestCart, advertRepo *mocks.MockAdvertRepository) {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusReserved}, nil)
+			},
+			expectedError: usecase.ErrCartAdvertUnavailable,
+		},
+		{
+			name: "Advert Not Found",
+			setupMocks: func(_ *mocks.MockGuestCart, advertRepo *mocks.MockAdvertRepository) {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).Return(nil, repository.ErrAdvertNotFound)
+			},
+			expectedError: repository.ErrAdvertNotFound,
+		},
+		{
+			name: "Cart Full",
+			setupMocks: func(guestCartRepo *mocks.MockGuestCart, advertRepo *mocks.MockAdvertRepository) {
+				advertRepo.EXPECT().GetSaleState(gomock.Any(), advertID).
+					Return(&entity.AdvertSaleState{AdvertID: advertID, Status: entity.AdvertStatusActive}, nil)
+				guestCartRepo.EXPECT().AddAdvert(gomock.Any(), guestID, advertID, testGuestCartLimit).
+					Return(repository.ErrGuestCartFull)
+			},
+			expectedError: repository.ErrGuestCartFull,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, guestCartRepo, advertRepo := setupGuestCartService(t)
+			tc.setupMocks(guestCartRepo, advertRepo)
+
+			err := service.AddAdvert(context.Background(), guestID, advertID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGuestCartService_GetCart(t *testing.T) {
+	service, guestCartRepo, advertRepo := setupGuestCartService(t)
+	guestID, advertID, deletedID := uuid.New(), uuid.New(), uuid.New()
+
+	guestCartRepo.EXPECT().GetAdverts(gomock.Any(), guestID).Return([]uuid.UUID{deletedID, advertID}, nil)
+	advertRepo.EXPECT().GetById(gomock.Any(), deletedID, uuid.Nil).Return(nil, repository.ErrAdvertNotFound)
+	advertRepo.EXPECT().GetById(gomock.Any(), advertID, uuid.Nil).
+		Return(&entity.Advert{ID: advertID, Title: "Велосипед", Price: 15000, Status: entity.AdvertStatusActive}, nil)
+
+	cart, err := service.GetCart(context.Background(), guestID)
+
+	assert.NoError(t, err)
+	assert.Len(t, cart.Adverts, 1)
+	assert.Equal(t, advertID, cart.Adverts[0].Preview.ID)
+	assert.Equal(t, "Велосипед", cart.Adverts[0].Preview.Title)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"io"
+
+	"github.com/chai2010/webp"
+	"github.com/gen2brain/avif"
+	"golang.org/x/image/draw"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+)
+
+const (
+	uploadQuality   = 60
+	variantQuality  = 75
+	originalQuality = 90
+	// avifSpeed - компромисс между временем кодирования и размером файла, шкала от 0 до 10
+	avifSpeed = 8
+)
+
+type imageEncoder func(w io.Writer, img image.Image, quality int) error
+
+// imageEncoders - кодировщики вариантов для всех форматов из entity.ImageFormatsByPreference
+var imageEncoders = map[entity.ImageFormat]imageEncoder{
+	entity.ImageFormatAVIF: func(w io.Writer, img image.Image, quality int) error {
+		return avif.Encode(w, img, avif.Options{Quality: quality, QualityAlpha: quality, Speed: avifSpeed})
+	},
+	entity.ImageFormatWebP: func(w io.Writer, img image.Image, quality int) error {
+		return webp.Encode(w, img, &webp.Options{Quality: float32(quality)})
+	},
+	entity.ImageFormatJPEG: func(w io.Writer, img image.Image, quality int) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	},
+}
+
+// encodeImage кодирует img в format. Метаданные удаляются из результата независимо от того,
+// что записал кодировщик
+func encodeImage(format entity.ImageFormat, img image.Image, quality int) ([]byte, error) {
+	encode, ok := imageEncoders[format]
+	if !ok {
+		return nil, usecase.ErrStaticUnsupportedFormat
+	}
+	var out bytes.Buffer
+	if err := encode(&out, img, quality); err != nil {
+		return nil, err
+	}
+	return stripMetadata(format, out.Bytes()), nil
+}
+
+// scaleDimensions возвращает размер, в который вписывается изображение width x height
+// при ограничениях maxWidth и maxHeight (0 - без ограничения). Изображение не увеличивается
+func scaleDimensions(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		scale = min(scale, float64(maxHeight)/float64(height))
+	}
+	return max(1, int(float64(width)*scale+0.5)), max(1, int(float64(height)*scale+0.5))
+}
+
+// coverRect возвращает центральную область src с пропорциями width:height
+func coverRect(src image.Rectangle, width, height int) image.Rectangle {
+	srcWidth, srcHeight := src.Dx(), src.Dy()
+	cropWidth, cropHeight := srcWidth, srcWidth*height/width
+	if cropHeight > srcHeight {
+		cropWidth, cropHeight = srcHeight*width/height, srcHeight
+	}
+	x := src.Min.X + (srcWidth-cropWidth)/2
+	y := src.Min.Y + (srcHeight-cropHeight)/2
+	return image.Rect(x, y, x+cropWidth, y+cropHeight)
+}
+
+// resizeImage строит копию img по размерам и режиму варианта
+func resizeImage(img image.Image, variant entity.ImageVariant) image.Image {
+	src := img.Bounds()
+	if variant.Fit == entity.ImageFitCover {
+		src = coverRect(src, variant.Width, variant.Height)
+	}
+	width, height := scaleDimensions(src.Dx(), src.Dy(), variant.Width, variant.Height)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+)
+
+// ImageHash возвращает разностный хэш (dHash) изображения: каждый бит сравнивает яркость соседних
+// точек уменьшенной копии 9x8. Хэш почти не меняется при масштабировании, пересжатии и коррекции цвета
+func ImageHash(img image.Image) uint64 {
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.GrayAt(x, y).Y < small.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// ParseImageHashes разбирает хэши ImageHash в шестнадцатеричной записи
+func ParseImageHashes(hashes []string) ([]uint64, error) {
+	parsed := make([]uint64, 0, len(hashes))
+	for _, hash := range hashes {
+		value, err := strconv.ParseUint(strings.TrimPrefix(hash, "0x"), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image hash %q: %w", hash, err)
+		}
+		parsed = append(parsed, value)
+	}
+	return parsed, nil
+}
+
+// PerceptualHashInspector отклоняет изображения, похожие на запрещенные: расстояние Хэмминга
+// между их хэшами ImageHash не больше maxDistance
+type PerceptualHashInspector struct {
+	banned      []uint64
+	maxDistance int
+}
+
+func NewPerceptualHashInspector(banned []uint64, maxDistance int) *PerceptualHashInspector {
+	return &PerceptualHashInspector{
+		banned:      banned,
+		maxDistance: maxDistance,
+	}
+}
+
+func (i *PerceptualHashInspector) Inspect(ctx context.Context, img image.Image) (entity.ImageInspection, error) {
+	hash := ImageHash(img)
+	for _, banned := range i.banned {
+		if bits.OnesCount64(hash^banned) <= i.maxDistance {
+			return entity.ImageInspection{
+				Verdict: entity.ImageVerdictReject,
+				Reason:  fmt.Sprintf("matches banned image %016x", banned),
+			}, nil
+		}
+	}
+	return entity.ImageInspection{Verdict: entity.ImageVerdictAllow}, nil
+}
+
+// NSFWInspector помечает для ручной проверки изображения с оценкой не ниже flagScore
+// и отклоняет изображения с оценкой не ниже rejectScore
+type NSFWInspector struct {
+	scorer      usecase.NSFWScorer
+	flagScore   float64
+	rejectScore float64
+}
+
+func NewNSFWInspector(scorer usecase.NSFWScorer, flagScore, rejectScore float64) *NSFWInspector {
+	return &NSFWInspector{
+		scorer:      scorer,
+		flagScore:   flagScore,
+		rejectScore: rejectScore,
+	}
+}
+
+func (i *NSFWInspector) Inspect(ctx context.Context, img image.Image) (entity.ImageInspection, error) {
+	score, err := i.scorer.Score(ctx, img)
+	if err != nil {
+		return entity.ImageInspection{}, err
+	}
+
+	reason := fmt.Sprintf("nsfw score %.2f", score)
+	switch {
+	case score >= i.rejectScore:
+		return entity.ImageInspection{Verdict: entity.ImageVerdictReject, Reason: reason}, nil
+	case score >= i.flagScore:
+		return entity.ImageInspection{Verdict: entity.ImageVerdictFlag, Reason: reason}, nil
+	}
+	return entity.ImageInspection{Verdict: entity.ImageVerdictAllow}, nil
+}
+
+// SkinToneScorer - простейшая реализация usecase.NSFWScorer: доля точек телесного цвета
+// в уменьшенной копии изображения. Пропускает многое и часто ошибается на портретах,
+// поэтому годится только для отправки на ручную проверку до подключения настоящей модели
+type SkinToneScorer struct{}
+
+func NewSkinToneScorer() *SkinToneScorer {
+	return &SkinToneScorer{}
+}
+
+func (SkinToneScorer) Score(_ context.Context, img image.Image) (float64, error) {
+	const side = 64
+	small := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	skin := 0
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			c := small.RGBAAt(x, y)
+			_, cb, cr := color.RGBToYCbCr(c.R, c.G, c.B)
+			if cb >= 77 && cb <= 127 && cr >= 133 && cr <= 173 {
+				skin++
+			}
+		}
+	}
+	return float64(skin) / float64(side*side), nil
+}
+
+// inspectImage проверяет изображение всеми проверками и возвращает самый строгий вердикт.
+// Ошибка проверки не отклоняет загрузку, а отправляет изображение на ручную проверку
+func (s *StaticService) inspectImage(ctx context.Context, img image.Image) entity.ImageInspection {
+	result := entity.ImageInspection{Verdict: entity.ImageVerdictAllow}
+	for _, inspector := range s.inspectors {
+		inspection, err := inspector.Inspect(ctx, img)
+		if err != nil {
+			inspection = entity.ImageInspection{Verdict: entity.ImageVerdictFlag, Reason: "inspection failed: " + err.Error()}
+		}
+		switch inspection.Verdict {
+		case entity.ImageVerdictReject:
+			return inspection
+		case entity.ImageVerdictFlag:
+			if result.Verdict != entity.ImageVerdictFlag {
+				result = inspection
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+)
+
+type jpegSegment struct {
+	marker  byte
+	start   int
+	end     int
+	payload []byte
+}
+
+// jpegSegments возвращает сегменты заголовка JPEG до начала сжатых данных (SOS)
+func jpegSegments(data []byte) []jpegSegment {
+	var segments []jpegSegment
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return segments
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return segments
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return segments
+		}
+		segments = append(segments, jpegSegment{marker: marker, start: pos, end: end, payload: data[pos+4 : end]})
+		pos = end
+	}
+	return segments
+}
+
+// jpegMetadataMarkers - сегменты с метаданными: APP1 (EXIF, XMP) и APP13 (IPTC)
+var jpegMetadataMarkers = map[byte]bool{0xE1: true, 0xED: true}
+
+func stripJPEGMetadata(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+	for _, segment := range jpegSegments(data) {
+		out = append(out, data[pos:segment.start]...)
+		if !jpegMetadataMarkers[segment.marker] {
+			out = append(out, data[segment.start:segment.end]...)
+		}
+		pos = segment.end
+	}
+	return append(out, data[pos:]...)
+}
+
+type webpChunk struct {
+	fourCC  string
+	start   int
+	end     int
+	payload []byte
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// webpChunks разбирает контейнер RIFF файла WebP на чанки
+func webpChunks(data []byte) []webpChunk {
+	var chunks []webpChunk
+	for pos := 12; pos+8 <= len(data); {
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		if pos+8+size > len(data) {
+			return chunks
+		}
+		// Чанки выравниваются по четной границе
+		end := min(pos+8+size+size&1, len(data))
+		chunks = append(chunks, webpChunk{fourCC: string(data[pos : pos+4]), start: pos, end: end, payload: data[pos+8 : pos+8+size]})
+		pos = end
+	}
+	return chunks
+}
+
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+func stripWebPMetadata(data []byte) []byte {
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	for _, chunk := range webpChunks(data) {
+		if chunk.fourCC == "EXIF" || chunk.fourCC == "XMP " {
+			continue
+		}
+		part := bytes.Clone(data[chunk.start:chunk.end])
+		if chunk.fourCC == "VP8X" && len(part) > 8 {
+			part[8] &^= webpFlagEXIF | webpFlagXMP
+		}
+		out = append(out, part...)
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out
+}
+
+// stripMetadata удаляет из закодированного изображения EXIF, XMP и IPTC: в них бывают
+// координаты съемки, модель устройства и другие сведения о владельце
+func stripMetadata(format entity.ImageFormat, data []byte) []byte {
+	switch {
+	case format == entity.ImageFormatJPEG && len(data) > 2 && data[0] == 0xFF && data[1] == 0xD8:
+		return stripJPEGMetadata(data)
+	case format == entity.ImageFormatWebP && isWebP(data):
+		return stripWebPMetadata(data)
+	}
+	return data
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// kopecksInRuble - цены объявлений хранятся в рублях, платежи - в копейках
+const kopecksInRuble = 100
+
+type PaymentService struct {
+	paymentRepo  repository.PaymentRepository
+	purchaseRepo repository.PurchaseRepository
+	cartRepo     repository.Cart
+	advertRepo   repository.AdvertRepository
+	dealRepo     repository.SafeDealRepository
+	deliveryRepo repository.DeliveryRepository
+	gateway      repository.PaymentGateway
+	currency     string
+	returnURL    string
+	autoCapture  bool
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository,
+	purchaseRepo repository.PurchaseRepository,
+	cartRepo repository.Cart,
+	advertRepo repository.AdvertRepository,
+	dealRepo repository.SafeDealRepository,
+	deliveryRepo repository.DeliveryRepository,
+	gateway repository.PaymentGateway,
+	currency, returnURL string, autoCapture bool) *PaymentService {
+	return &PaymentService{
+		paymentRepo:  paymentRepo,
+		purchaseRepo: purchaseRepo,
+		cartRepo:     cartRepo,
+		advertRepo:   advertRepo,
+		dealRepo:     dealRepo,
+		deliveryRepo: deliveryRepo,
+		gateway:      gateway,
+		currency:     currency,
+		returnURL:    returnURL,
+		autoCapture:  autoCapture,
+	}
+}
+
+func paymentEntityToDTO(payment *entity.Payment) *dto.PaymentResponse {
+	return &dto.PaymentResponse{
+		ID:              payment.ID,
+		PurchaseID:      payment.PurchaseID,
+		Provider:        payment.Provider,
+		ExternalID:      payment.ExternalID,
+		Status:          string(payment.Status),
+		Amount:          payment.Amount,
+		Currency:        payment.Currency,
+		ConfirmationURL: payment.ConfirmationURL,
+	}
+}
+
+func (s *PaymentService) Create(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.PaymentResponse, error) {
+	logger := middleware.GetLogger(ctx).With(zap.String("purchase_id", purchaseID.String()))
+
+	purchase, err := s.purchaseRepo.GetById(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get purchase"), err)
+	}
+	cart, err := s.cartRepo.GetById(ctx, purchase.CartID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get cart"), err)
+	}
+	if cart.UserID != userID {
+		return nil, entity.UsecaseWrap(usecase.ErrPaymentForbidden, usecase.ErrPaymentForbidden)
+	}
+
+	existing, err := s.paymentRepo.GetByPurchaseId(ctx, purchaseID)
+	switch {
+	case err == nil:
+		return paymentEntityToDTO(existing), nil
+	case !errors.Is(err, repository.ErrPaymentNotFound):
+		return nil, entity.UsecaseWrap(errors.New("failed to get payment"), err)
+	}
+
+	if purchase.PaymentMethod != entity.PaymentMethodCard || purchase.Status != entity.StatusPending {
+		return nil, entity.UsecaseWrap(usecase.ErrPaymentNotRequired, usecase.ErrPaymentNotRequired)
+	}
+
+	adverts, err := s.advertRepo.GetByCartId(ctx, purchase.CartID, userID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get adverts"), err)
+	}
+	var amount uint
+	for _, advert := range adverts {
+		amount += advert.Price * kopecksInRuble
+	}
+	if amount == 0 {
+		return nil, entity.UsecaseWrap(usecase.ErrPaymentNotRequired, usecase.ErrPaymentNotRequired)
+	}
+	// доставка оплачивается вместе с объявлениями, если отправления созданы до платежа
+	shipments, err := s.deliveryRepo.GetShipmentsByPurchaseId(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get shipments"), err)
+	}
+	for _, shipment := range shipments {
+		amount += shipment.Cost * kopecksInRuble
+	}
+
+	// по безопасной сделке средства только блокируются до подтверждения получения
+	deal, err := s.getDeal(ctx, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+	// без сделки платеж списал бы средства сразу, поэтому его создание откладывается до сделки
+	if purchase.SafeDeal && deal == nil {
+		return nil, entity.UsecaseWrap(usecase.ErrPaymentDealRequired, usecase.ErrPaymentDealRequired)
+	}
+
+	// ключ идемпотентности - покупка, поэтому повтор после сбоя не создаст второй платеж у провайдера
+	gatewayPayment, err := s.gateway.CreatePayment(ctx, entity.GatewayPaymentRequest{
+		PurchaseID:     purchaseID,
+		Amount:         amount,
+		Currency:       s.currency,
+		Description:    fmt.Sprintf("Заказ %s", purchaseID),
+		ReturnURL:      s.returnURL,
+		Capture:        s.autoCapture && deal == nil,
+		IdempotenceKey: purchaseID.String(),
+	})
+	if err != nil {
+		logger.Error("failed to create payment in gateway", zap.Error(err))
+		return nil, entity.UsecaseWrap(errors.New("failed to create payment"), err)
+	}
+
+	payment, err := s.paymentRepo.Add(ctx, &entity.Payment{
+		PurchaseID:      purchaseID,
+		UserID:          userID,
+		Provider:        s.gateway.Name(),
+		ExternalID:      gatewayPayment.ExternalID,
+		Amount:          amount,
+		Currency:        s.currency,
+		Status:          gatewayPayment.Status,
+		ConfirmationURL: gatewayPayment.ConfirmationURL,
+	})
+	if err != nil {
+		// параллельный запрос уже сохранил тот же платеж
+		if stored, getErr := s.paymentRepo.GetByExternalId(ctx, s.gateway.Name(), gatewayPayment.ExternalID); getErr == nil {
+			return paymentEntityToDTO(stored), nil
+		}
+		return nil, entity.UsecaseWrap(errors.New("failed to save payment"), err)
+	}
+
+	logger.Info("payment created", zap.String("payment_id", payment.ID.String()), zap.Uint("amount", amount))
+	return paymentEntityToDTO(payment), nil
+}
+
+func (s *PaymentService) GetByPurchaseId(ctx context.Context, purchaseID, userID uuid.UUID) (*dto.PaymentResponse, error) {
+	payment, err := s.paymentRepo.GetByPurchaseId(ctx, purchaseID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get payment"), err)
+	}
+	if payment.UserID != userID {
+		return nil, entity.UsecaseWrap(usecase.ErrPaymentForbidden, usecase.ErrPaymentForbidden)
+	}
+	return paymentEntityToDTO(payment), nil
+}
+
+func (s *PaymentService) Capture(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error) {
+	payment, err := s.paymentRepo.GetById(ctx, paymentID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get payment"), err)
+	}
+	if err := s.capture(ctx, payment); err != nil {
+		return nil, err
+	}
+	return paymentEntityToDTO(payment), nil
+}
+
+func (s *PaymentService) capture(ctx context.Context, payment *entity.Payment) error {
+	if payment.Status != entity.PaymentStatusWaitingForCapture {
+		return entity.UsecaseWrap(usecase.ErrPaymentInvalidState, usecase.ErrPaymentInvalidState)
+	}
+	captured, err := s.gateway.Capture(ctx, payment.ExternalID, payment.Amount, payment.Currency)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to capture payment"), err)
+	}
+	return s.changeStatus(ctx, payment, captured.Status)
+}
+
+func (s *PaymentService) Cancel(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error) {
+	payment, err := s.paymentRepo.GetById(ctx, paymentID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get payment"), err)
+	}
+	if payment.Status != entity.PaymentStatusWaitingForCapture {
+		return nil, entity.UsecaseWrap(usecase.ErrPaymentInvalidState, usecase.ErrPaymentInvalidState)
+	}
+	canceled, err := s.gateway.Cancel(ctx, payment.ExternalID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to cancel payment"), err)
+	}
+	if err := s.changeStatus(ctx, payment, canceled.Status); err != nil {
+		return nil, err
+	}
+	return paymentEntityToDTO(payment), nil
+}
+
+func (s *PaymentService) Refund(ctx context.Context, paymentID uuid.UUID) (*dto.PaymentResponse, error) {
+	payment, err := s.paymentRepo.GetById(ctx, paymentID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to get payment"), err)
+	}
+	if err := s.refund(ctx, payment); err != nil {
+		return nil, err
+	}
+	return paymentEntityToDTO(payment), nil
+}
+
+func (s *PaymentService) refund(ctx context.Context, payment *entity.Payment) error {
+	if payment.Status != entity.PaymentStatusSucceeded {
+		return entity.UsecaseWrap(usecase.ErrPaymentInvalidState, usecase.ErrPaymentInvalidState)
+	}
+	if err := s.gateway.Refund(ctx, payment.ExternalID, payment.Amount, payment.Currency); err != nil {
+		return entity.UsecaseWrap(errors.New("failed to refund payment"), err)
+	}
+	return s.changeStatus(ctx, payment, entity.PaymentStatusRefunded)
+}
+
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	payment, err := s.paymentRepo.GetByExternalId(ctx, s.gateway.Name(), event.ExternalID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to get payment"), err)
+	}
+	return s.changeStatus(ctx, payment, event.Status)
+}
+
+// getDeal возвращает безопасную сделку по покупке или nil, если покупка оформлена без нее
+func (s *PaymentService) getDeal(ctx context.Context, purchaseID uuid.UUID) (*entity.SafeDeal, error) {
+	deal, err := s.dealRepo.GetByPurchaseId(ctx, purchaseID)
+	switch {
+	case errors.Is(err, repository.ErrDealNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, entity.UsecaseWrap(errors.New("failed to get safe deal"), err)
+	}
+	return deal, nil
+}
+
+// dealStatusAfter возвращает этап сделки после перехода ее платежа в статус payment.
+// Спорная сделка завершается только решением администратора через releasing, поэтому
+// списание средств ее не завершает
+func dealStatusAfter(deal entity.DealStatus, payment entity.PaymentStatus) (entity.DealStatus, bool) {
+	switch {
+	case payment == entity.PaymentStatusWaitingForCapture && deal == entity.DealStatusAwaitingPayment:
+		return entity.DealStatusHeld, true
+	case payment == entity.PaymentStatusSucceeded && (deal == entity.DealStatusAwaitingPayment ||
+		(deal.Active() && deal != entity.DealStatusDisputed)):
+		return entity.DealStatusCompleted, true
+	case payment == entity.PaymentStatusCanceled && deal == entity.DealStatusAwaitingPayment:
+		return entity.DealStatusCanceled, true
+	case (payment == entity.PaymentStatusCanceled || payment == entity.PaymentStatusRefunded) && deal.Active():
+		return entity.DealStatusRefunded, true
+	}
+	return deal, false
+}
+
+// changeStatus переводит платеж в статус to и меняет статус покупки: оплаченная покупка
+// передается продавцу, покупка с отмененным или возвращенным платежом отменяется, а ее
+// объявления снова становятся активными. Безопасная сделка по покупке переходит на
+// следующий этап в той же транзакции, а покупка завершается только со списанием средств по ней
+func (s *PaymentService) changeStatus(ctx context.Context, payment *entity.Payment, to entity.PaymentStatus) (err error) {
+	logger := middleware.GetLogger(ctx).With(zap.String("payment_id", payment.ID.String()),
+		zap.String("from", string(payment.Status)), zap.String("to", string(to)))
+
+	if !payment.Status.CanChangeTo(to) {
+		logger.Info("payment status change skipped")
+		return nil
+	}
+
+	purchase, err := s.purchaseRepo.GetById(ctx, payment.PurchaseID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to get purchase"), err)
+	}
+	deal, err := s.getDeal(ctx, payment.PurchaseID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.paymentRepo.BeginTransaction(ctx)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to begin transaction"), err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	changed, err := s.paymentRepo.UpdateStatus(ctx, tx, payment.ID, payment.Status, to)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to update payment status"), err)
+	}
+	if !changed {
+		logger.Info("payment status was changed concurrently")
+		_ = tx.Rollback(ctx)
+		return nil
+	}
+
+	switch to {
+	case entity.PaymentStatusWaitingForCapture, entity.PaymentStatusSucceeded:
+		next := entity.StatusInProgress
+		if deal != nil && to == entity.PaymentStatusSucceeded {
+			next = entity.StatusCompleted
+		}
+		if purchase.Status == entity.StatusPending || (purchase.Status == entity.StatusInProgress && next == entity.StatusCompleted) {
+			if err = s.purchaseRepo.UpdateStatus(ctx, tx, purchase.ID, next); err != nil {
+				return entity.UsecaseWrap(errors.New("failed to update purchase status"), err)
+			}
+		}
+	case entity.PaymentStatusCanceled, entity.PaymentStatusRefunded:
+		if purchase.Status == entity.StatusPending || purchase.Status == entity.StatusInProgress {
+			if err = s.cancelPurchase(ctx, tx, purchase, payment.UserID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if deal != nil {
+		if next, ok := dealStatusAfter(deal.Status, to); ok {
+			var dealChanged bool
+			dealChanged, err = s.dealRepo.UpdateStatus(ctx, tx, deal.ID, deal.Status, next)
+			if err != nil {
+				return entity.UsecaseWrap(errors.New("failed to update safe deal status"), err)
+			}
+			// сделку изменили параллельно: платеж остается в прежнем статусе до повторного уведомления
+			if !dealChanged {
+				err = usecase.ErrDealInvalidState
+				return entity.UsecaseWrap(errors.New("safe deal status was changed concurrently"), err)
+			}
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return entity.UsecaseWrap(errors.New("failed to commit transaction"), err)
+	}
+	payment.Status = to
+	logger.Info("payment status changed")
+
+	switch {
+	case to == entity.PaymentStatusSucceeded && purchase.Status == entity.StatusCanceled:
+		// покупку отменили, пока покупатель платил, - деньги возвращаются
+		return s.refund(ctx, payment)
+	case to == entity.PaymentStatusWaitingForCapture && s.autoCapture && deal == nil && purchase.Status != entity.StatusCanceled:
+		return s.capture(ctx, payment)
+	}
+	return nil
+}
+
+func (s *PaymentService) cancelPurchase(ctx context.Context, tx pgx.Tx, purchase *entity.Purchase, userID uuid.UUID) error {
+	if err := s.purchaseRepo.UpdateStatus(ctx, tx, purchase.ID, entity.StatusCanceled); err != nil {
+		return entity.UsecaseWrap(errors.New("failed to update purchase status"), err)
+	}
+	adverts, err := s.advertRepo.GetByCartId(ctx, purchase.CartID, userID)
+	if err != nil {
+		return entity.UsecaseWrap(errors.New("failed to get adverts"), err)
+	}
+	for _, advert := range adverts {
+		if advert.Status != entity.AdvertStatusReserved {
+			continue
+		}
+		if err := s.advertRepo.UpdateStatus(ctx, tx, advert.ID, entity.AdvertStatusActive); err != nil {
+			return entity.UsecaseWrap(errors.New("failed to update advert status"), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,565 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type paymentTestDeps struct {
+	paymentRepo  *mocks.MockPaymentRepository
+	purchaseRepo *mocks.MockPurchaseRepository
+	cartRepo     *mocks.MockCart
+	advertRepo   *mocks.MockAdvertRepository
+	dealRepo     *mocks.MockSafeDealRepository
+	deliveryRepo *mocks.MockDeliveryRepository
+	gateway      *mocks.MockPaymentGateway
+	pools        []pgxmock.PgxPoolIface
+	// shipments - отправления покупки, стоимость которых добавляется к платежу
+	shipments []*entity.Shipment
+}
+
+func setupPaymentService(t *testing.T, autoCapture bool) (*PaymentService, *paymentTestDeps) {
+	return setupPaymentServiceWithDeal(t, autoCapture, nil)
+}
+
+// setupPaymentServiceWithDeal готовит сервис, в котором покупка оформлена безопасной сделкой deal
+// или, если deal = nil, без нее
+func setupPaymentServiceWithDeal(t *testing.T, autoCapture bool, deal *entity.SafeDeal) (*PaymentService, *paymentTestDeps) {
+	ctrl := gomock.NewController(t)
+	deps := &paymentTestDeps{
+		paymentRepo:  mocks.NewMockPaymentRepository(ctrl),
+		purchaseRepo: mocks.NewMockPurchaseRepository(ctrl),
+		cartRepo:     mocks.NewMockCart(ctrl),
+		advertRepo:   mocks.NewMockAdvertRepository(ctrl),
+		dealRepo:     mocks.NewMockSafeDealRepository(ctrl),
+		deliveryRepo: mocks.NewMockDeliveryRepository(ctrl),
+		gateway:      mocks.NewMockPaymentGateway(ctrl),
+	}
+	deps.gateway.EXPECT().Name().Return("fake").AnyTimes()
+	deps.deliveryRepo.EXPECT().GetShipmentsByPurchaseId(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, uuid.UUID) ([]*entity.Shipment, error) {
+			return deps.shipments, nil
+		}).AnyTimes()
+	if deal == nil {
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), gomock.Any()).Return(nil, repository.ErrDealNotFound).AnyTimes()
+	} else {
+		deps.dealRepo.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID).Return(deal, nil).AnyTimes()
+	}
+
+	service := NewPaymentService(deps.paymentRepo, deps.purchaseRepo, deps.cartRepo, deps.advertRepo, deps.dealRepo,
+		deps.deliveryRepo, deps.gateway, "RUB", "http://localhost/return", autoCapture)
+	return service, deps
+}
+
+// expectTx ожидает очередную транзакцию, которая завершится commit или rollback
+func (d *paymentTestDeps) expectTx(t *testing.T, commit bool) pgx.Tx {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	d.pools = append(d.pools, pool)
+
+	pool.ExpectBegin()
+	tx, err := pool.Begin(context.Background())
+	require.NoError(t, err)
+	if commit {
+		pool.ExpectCommit()
+	} else {
+		pool.ExpectRollback()
+	}
+	d.paymentRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	return tx
+}
+
+func (d *paymentTestDeps) assertTxDone(t *testing.T) {
+	for _, pool := range d.pools {
+		assert.NoError(t, pool.ExpectationsWereMet())
+	}
+}
+
+func TestPaymentService_Create(t *testing.T) {
+	userID, purchaseID, cartID := uuid.New(), uuid.New(), uuid.New()
+	pendingCardPurchase := &entity.Purchase{
+		ID:            purchaseID,
+		CartID:        cartID,
+		Status:        entity.StatusPending,
+		PaymentMethod: entity.PaymentMethodCard,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(pendingCardPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrPaymentNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).
+			Return([]*entity.Advert{{Price: 1500}, {Price: 250}}, nil)
+		deps.gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error) {
+				assert.Equal(t, uint(175000), req.Amount)
+				assert.Equal(t, "RUB", req.Currency)
+				assert.Equal(t, purchaseID.String(), req.IdempotenceKey)
+				assert.True(t, req.Capture)
+				return &entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusPending,
+					ConfirmationURL: "http://pay/fake-1"}, nil
+			})
+		deps.paymentRepo.EXPECT().Add(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, payment *entity.Payment) (*entity.Payment, error) {
+				assert.Equal(t, "fake", payment.Provider)
+				assert.Equal(t, userID, payment.UserID)
+				created := *payment
+				created.ID = uuid.New()
+				return &created, nil
+			})
+
+		payment, err := service.Create(context.Background(), purchaseID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(175000), payment.Amount)
+		assert.Equal(t, "http://pay/fake-1", payment.ConfirmationURL)
+		assert.Equal(t, string(entity.PaymentStatusPending), payment.Status)
+	})
+
+	t.Run("WithShipping", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		deps.shipments = []*entity.Shipment{{Cost: 300}, {Cost: 150}}
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(pendingCardPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrPaymentNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).
+			Return([]*entity.Advert{{Price: 1500}, {Price: 250}}, nil)
+		deps.gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error) {
+				assert.Equal(t, uint(220000), req.Amount)
+				return &entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusPending}, nil
+			})
+		deps.paymentRepo.EXPECT().Add(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, payment *entity.Payment) (*entity.Payment, error) {
+				created := *payment
+				created.ID = uuid.New()
+				return &created, nil
+			})
+
+		payment, err := service.Create(context.Background(), purchaseID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(220000), payment.Amount)
+	})
+
+	t.Run("AlreadyCreated", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		existing := &entity.Payment{ID: uuid.New(), PurchaseID: purchaseID, UserID: userID, Status: entity.PaymentStatusPending}
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(pendingCardPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(existing, nil)
+
+		payment, err := service.Create(context.Background(), purchaseID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, payment.ID)
+	})
+
+	t.Run("SavedConcurrently", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		stored := &entity.Payment{ID: uuid.New(), PurchaseID: purchaseID, ExternalID: "fake-1"}
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(pendingCardPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrPaymentNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return([]*entity.Advert{{Price: 10}}, nil)
+		deps.gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+			Return(&entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusPending}, nil)
+		deps.paymentRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil, errors.New("duplicate key"))
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(stored, nil)
+
+		payment, err := service.Create(context.Background(), purchaseID, userID)
+		require.NoError(t, err)
+		assert.Equal(t, stored.ID, payment.ID)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(pendingCardPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: uuid.New()}, nil)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrPaymentForbidden)
+	})
+
+	t.Run("Cash", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		cashPurchase := *pendingCardPurchase
+		cashPurchase.PaymentMethod = entity.PaymentMethodCash
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(&cashPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrPaymentNotFound)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrPaymentNotRequired)
+	})
+
+	t.Run("SafeDealNotCreated", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		safeDealPurchase := *pendingCardPurchase
+		safeDealPurchase.SafeDeal = true
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(&safeDealPurchase, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(nil, repository.ErrPaymentNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return([]*entity.Advert{{Price: 1500}}, nil)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, usecase.ErrPaymentDealRequired)
+	})
+
+	t.Run("PurchaseNotFound", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), purchaseID).Return(nil, repository.ErrPurchaseNotFound)
+
+		_, err := service.Create(context.Background(), purchaseID, userID)
+		assert.ErrorIs(t, err, repository.ErrPurchaseNotFound)
+	})
+}
+
+func TestPaymentService_GetByPurchaseId(t *testing.T) {
+	service, deps := setupPaymentService(t, true)
+	userID, purchaseID := uuid.New(), uuid.New()
+	payment := &entity.Payment{ID: uuid.New(), PurchaseID: purchaseID, UserID: userID}
+
+	deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), purchaseID).Return(payment, nil).Times(2)
+
+	resp, err := service.GetByPurchaseId(context.Background(), purchaseID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, payment.ID, resp.ID)
+
+	_, err = service.GetByPurchaseId(context.Background(), purchaseID, uuid.New())
+	assert.ErrorIs(t, err, usecase.ErrPaymentForbidden)
+}
+
+func TestPaymentService_HandleWebhook(t *testing.T) {
+	payload, signature := []byte(`{}`), "signature"
+	userID, cartID := uuid.New(), uuid.New()
+	newPayment := func(status entity.PaymentStatus) *entity.Payment {
+		return &entity.Payment{ID: uuid.New(), PurchaseID: uuid.New(), UserID: userID, ExternalID: "fake-1",
+			Amount: 1000, Currency: "RUB", Status: status}
+	}
+	newPurchase := func(payment *entity.Payment, status entity.PurchaseStatus) *entity.Purchase {
+		return &entity.Purchase{ID: payment.PurchaseID, CartID: cartID, Status: status}
+	}
+
+	t.Run("Succeeded", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		payment := newPayment(entity.PaymentStatusPending)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), payment.PurchaseID).Return(newPurchase(payment, entity.StatusPending), nil)
+		tx := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.PurchaseID, entity.StatusInProgress).Return(nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), payload, signature))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("Repeated", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").
+			Return(newPayment(entity.PaymentStatusSucceeded), nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), payload, signature))
+	})
+
+	t.Run("ChangedConcurrently", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		payment := newPayment(entity.PaymentStatusPending)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusCanceled}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), payment.PurchaseID).Return(newPurchase(payment, entity.StatusPending), nil)
+		tx := deps.expectTx(t, false)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusPending, entity.PaymentStatusCanceled).
+			Return(false, nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), payload, signature))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		payment := newPayment(entity.PaymentStatusPending)
+		reserved, active := uuid.New(), uuid.New()
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusCanceled}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), payment.PurchaseID).Return(newPurchase(payment, entity.StatusPending), nil)
+		tx := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusPending, entity.PaymentStatusCanceled).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.PurchaseID, entity.StatusCanceled).Return(nil)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return([]*entity.Advert{
+			{ID: reserved, Status: entity.AdvertStatusReserved},
+			{ID: active, Status: entity.AdvertStatusActive},
+		}, nil)
+		deps.advertRepo.EXPECT().UpdateStatus(gomock.Any(), tx, reserved, entity.AdvertStatusActive).Return(nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), payload, signature))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("WaitingForCaptureAutoCapture", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		payment := newPayment(entity.PaymentStatusPending)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusWaitingForCapture}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		gomock.InOrder(
+			deps.purchaseRepo.EXPECT().GetById(gomock.Any(), payment.PurchaseID).Return(newPurchase(payment, entity.StatusPending), nil),
+			deps.purchaseRepo.EXPECT().GetById(gomock.Any(), payment.PurchaseID).Return(newPurchase(payment, entity.StatusInProgress), nil),
+		)
+		held := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), held, payment.ID, entity.PaymentStatusPending, entity.PaymentStatusWaitingForCapture).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), held, payment.PurchaseID, entity.StatusInProgress).Return(nil)
+		deps.gateway.EXPECT().Capture(gomock.Any(), "fake-1", uint(1000), "RUB").
+			Return(&entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		captured := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), captured, payment.ID, entity.PaymentStatusWaitingForCapture, entity.PaymentStatusSucceeded).
+			Return(true, nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), payload, signature))
+		assert.Equal(t, entity.PaymentStatusSucceeded, payment.Status)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("SucceededForCanceledPurchase", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+		payment := newPayment(entity.PaymentStatusPending)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), payment.PurchaseID).
+			Return(newPurchase(payment, entity.StatusCanceled), nil).Times(2)
+		succeeded := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), succeeded, payment.ID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded).
+			Return(true, nil)
+		deps.gateway.EXPECT().Refund(gomock.Any(), "fake-1", uint(1000), "RUB").Return(nil)
+		refunded := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), refunded, payment.ID, entity.PaymentStatusSucceeded, entity.PaymentStatusRefunded).
+			Return(true, nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), payload, signature))
+		assert.Equal(t, entity.PaymentStatusRefunded, payment.Status)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).Return(nil, repository.ErrGatewayInvalidSignature)
+
+		err := service.HandleWebhook(context.Background(), payload, signature)
+		assert.ErrorIs(t, err, repository.ErrGatewayInvalidSignature)
+	})
+
+	t.Run("UnknownPayment", func(t *testing.T) {
+		service, deps := setupPaymentService(t, true)
+
+		deps.gateway.EXPECT().ParseWebhook(payload, signature).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(nil, repository.ErrPaymentNotFound)
+
+		err := service.HandleWebhook(context.Background(), payload, signature)
+		assert.ErrorIs(t, err, repository.ErrPaymentNotFound)
+	})
+}
+
+func TestPaymentService_CaptureAndRefund_InvalidState(t *testing.T) {
+	service, deps := setupPaymentService(t, false)
+	pending := &entity.Payment{ID: uuid.New(), Status: entity.PaymentStatusPending}
+
+	deps.paymentRepo.EXPECT().GetById(gomock.Any(), pending.ID).Return(pending, nil).Times(2)
+
+	_, err := service.Capture(context.Background(), pending.ID)
+	assert.ErrorIs(t, err, usecase.ErrPaymentInvalidState)
+	_, err = service.Refund(context.Background(), pending.ID)
+	assert.ErrorIs(t, err, usecase.ErrPaymentInvalidState)
+}
+
+func TestPaymentService_SafeDeal(t *testing.T) {
+	userID, cartID := uuid.New(), uuid.New()
+	newDeal := func(status entity.DealStatus) *entity.SafeDeal {
+		return &entity.SafeDeal{ID: uuid.New(), PurchaseID: uuid.New(), BuyerID: userID, Status: status}
+	}
+	newPayment := func(deal *entity.SafeDeal, status entity.PaymentStatus) *entity.Payment {
+		return &entity.Payment{ID: uuid.New(), PurchaseID: deal.PurchaseID, UserID: userID, ExternalID: "fake-1",
+			Amount: 1000, Currency: "RUB", Status: status}
+	}
+
+	t.Run("CreateHoldsFunds", func(t *testing.T) {
+		deal := newDeal(entity.DealStatusAwaitingPayment)
+		service, deps := setupPaymentServiceWithDeal(t, true, deal)
+
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), deal.PurchaseID).Return(&entity.Purchase{ID: deal.PurchaseID,
+			CartID: cartID, Status: entity.StatusPending, PaymentMethod: entity.PaymentMethodCard}, nil)
+		deps.cartRepo.EXPECT().GetById(gomock.Any(), cartID).Return(entity.Cart{ID: cartID, UserID: userID}, nil)
+		deps.paymentRepo.EXPECT().GetByPurchaseId(gomock.Any(), deal.PurchaseID).Return(nil, repository.ErrPaymentNotFound)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).Return([]*entity.Advert{{Price: 10}}, nil)
+		deps.gateway.EXPECT().CreatePayment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req entity.GatewayPaymentRequest) (*entity.GatewayPayment, error) {
+				assert.False(t, req.Capture)
+				return &entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusPending}, nil
+			})
+		deps.paymentRepo.EXPECT().Add(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, payment *entity.Payment) (*entity.Payment, error) {
+				return payment, nil
+			})
+
+		_, err := service.Create(context.Background(), deal.PurchaseID, userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("HeldWithoutAutoCapture", func(t *testing.T) {
+		deal := newDeal(entity.DealStatusAwaitingPayment)
+		service, deps := setupPaymentServiceWithDeal(t, true, deal)
+		payment := newPayment(deal, entity.PaymentStatusPending)
+
+		deps.gateway.EXPECT().ParseWebhook(gomock.Any(), gomock.Any()).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusWaitingForCapture}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), deal.PurchaseID).
+			Return(&entity.Purchase{ID: deal.PurchaseID, Status: entity.StatusPending}, nil)
+		tx := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusPending, entity.PaymentStatusWaitingForCapture).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.PurchaseID, entity.StatusInProgress).Return(nil)
+		deps.dealRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.ID, entity.DealStatusAwaitingPayment, entity.DealStatusHeld).
+			Return(true, nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), []byte(`{}`), "signature"))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("CaptureCompletesPurchase", func(t *testing.T) {
+		deal := newDeal(entity.DealStatusShipped)
+		service, deps := setupPaymentServiceWithDeal(t, false, deal)
+		payment := newPayment(deal, entity.PaymentStatusWaitingForCapture)
+
+		deps.paymentRepo.EXPECT().GetById(gomock.Any(), payment.ID).Return(payment, nil)
+		deps.gateway.EXPECT().Capture(gomock.Any(), "fake-1", uint(1000), "RUB").
+			Return(&entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), deal.PurchaseID).
+			Return(&entity.Purchase{ID: deal.PurchaseID, Status: entity.StatusInProgress}, nil)
+		tx := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusWaitingForCapture, entity.PaymentStatusSucceeded).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.PurchaseID, entity.StatusCompleted).Return(nil)
+		deps.dealRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.ID, entity.DealStatusShipped, entity.DealStatusCompleted).
+			Return(true, nil)
+
+		resp, err := service.Capture(context.Background(), payment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.PaymentStatusSucceeded), resp.Status)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("CaptureKeepsDisputedDeal", func(t *testing.T) {
+		deal := newDeal(entity.DealStatusDisputed)
+		service, deps := setupPaymentServiceWithDeal(t, false, deal)
+		payment := newPayment(deal, entity.PaymentStatusWaitingForCapture)
+
+		deps.gateway.EXPECT().ParseWebhook(gomock.Any(), gomock.Any()).
+			Return(&entity.PaymentEvent{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.paymentRepo.EXPECT().GetByExternalId(gomock.Any(), "fake", "fake-1").Return(payment, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), deal.PurchaseID).
+			Return(&entity.Purchase{ID: deal.PurchaseID, Status: entity.StatusInProgress}, nil)
+		tx := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusWaitingForCapture, entity.PaymentStatusSucceeded).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.PurchaseID, entity.StatusCompleted).Return(nil)
+
+		assert.NoError(t, service.HandleWebhook(context.Background(), []byte(`{}`), "signature"))
+		deps.assertTxDone(t)
+	})
+
+	t.Run("DealChangedConcurrently", func(t *testing.T) {
+		deal := newDeal(entity.DealStatusReleasing)
+		service, deps := setupPaymentServiceWithDeal(t, false, deal)
+		payment := newPayment(deal, entity.PaymentStatusWaitingForCapture)
+
+		deps.paymentRepo.EXPECT().GetById(gomock.Any(), payment.ID).Return(payment, nil)
+		deps.gateway.EXPECT().Capture(gomock.Any(), "fake-1", uint(1000), "RUB").
+			Return(&entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusSucceeded}, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), deal.PurchaseID).
+			Return(&entity.Purchase{ID: deal.PurchaseID, Status: entity.StatusInProgress}, nil)
+		tx := deps.expectTx(t, false)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusWaitingForCapture, entity.PaymentStatusSucceeded).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.PurchaseID, entity.StatusCompleted).Return(nil)
+		deps.dealRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.ID, entity.DealStatusReleasing, entity.DealStatusCompleted).
+			Return(false, nil)
+
+		_, err := service.Capture(context.Background(), payment.ID)
+		assert.ErrorIs(t, err, usecase.ErrDealInvalidState)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("CancelRefundsDeal", func(t *testing.T) {
+		deal := newDeal(entity.DealStatusRefunding)
+		service, deps := setupPaymentServiceWithDeal(t, false, deal)
+		payment := newPayment(deal, entity.PaymentStatusWaitingForCapture)
+		reserved := uuid.New()
+
+		deps.paymentRepo.EXPECT().GetById(gomock.Any(), payment.ID).Return(payment, nil)
+		deps.gateway.EXPECT().Cancel(gomock.Any(), "fake-1").
+			Return(&entity.GatewayPayment{ExternalID: "fake-1", Status: entity.PaymentStatusCanceled}, nil)
+		deps.purchaseRepo.EXPECT().GetById(gomock.Any(), deal.PurchaseID).
+			Return(&entity.Purchase{ID: deal.PurchaseID, CartID: cartID, Status: entity.StatusInProgress}, nil)
+		tx := deps.expectTx(t, true)
+		deps.paymentRepo.EXPECT().UpdateStatus(gomock.Any(), tx, payment.ID, entity.PaymentStatusWaitingForCapture, entity.PaymentStatusCanceled).
+			Return(true, nil)
+		deps.purchaseRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.PurchaseID, entity.StatusCanceled).Return(nil)
+		deps.advertRepo.EXPECT().GetByCartId(gomock.Any(), cartID, userID).
+			Return([]*entity.Advert{{ID: reserved, Status: entity.AdvertStatusReserved}}, nil)
+		deps.advertRepo.EXPECT().UpdateStatus(gomock.Any(), tx, reserved, entity.AdvertStatusActive).Return(nil)
+		deps.dealRepo.EXPECT().UpdateStatus(gomock.Any(), tx, deal.ID, entity.DealStatusRefunding, entity.DealStatusRefunded).
+			Return(true, nil)
+
+		resp, err := service.Cancel(context.Background(), payment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(entity.PaymentStatusCanceled), resp.Status)
+		deps.assertTxDone(t)
+	})
+
+	t.Run("CancelNotHeld", func(t *testing.T) {
+		service, deps := setupPaymentService(t, false)
+		payment := &entity.Payment{ID: uuid.New(), Status: entity.PaymentStatusSucceeded}
+
+		deps.paymentRepo.EXPECT().GetById(gomock.Any(), payment.ID).Return(payment, nil)
+
+		_, err := service.Cancel(context.Background(), payment.ID)
+		assert.ErrorIs(t, err, usecase.ErrPaymentInvalidState)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -30,67 +31,117 @@ func (s *PurchaseService) purchaseEntityToDTO(purchase *entity.Purchase) (*dto.P
 		Status:         dto.PurchaseStatus(purchase.Status),
 		PaymentMethod:  dto.PaymentMethod(purchase.PaymentMethod),
 		DeliveryMethod: dto.DeliveryMethod(purchase.DeliveryMethod),
+		SafeDeal:       purchase.SafeDeal,
 	}, nil
 }
 
-func (s *PurchaseService) Add(purchaseRequest dto.PurchaseRequest, userId uuid.UUID) (*dto.PurchaseResponse, error) {
-	ctx := context.Background()
-	tx, err := s.purchaseRepo.BeginTransaction()
+func (s *PurchaseService) Add(ctx context.Context, purchaseRequest dto.PurchaseRequest, userId uuid.UUID) (response *dto.PurchaseResponse, err error) {
+	// без снимка адреса покупка не оформляется, иначе адрес потеряется при изменении адресной книги
+	address := purchaseRequest.PurchaseAddress
+	if address == nil {
+		return nil, entity.UsecaseWrap(usecase.ErrPurchaseAddressRequired, usecase.ErrPurchaseAddressRequired)
+	}
+
+	tx, err := s.purchaseRepo.BeginTransaction(ctx)
 	if err != nil {
 		logger := middleware.GetLogger(ctx)
 		logger.Error("failed to begin transaction", zap.Error(err), zap.String("userId", userId.String()))
 		return nil, entity.UsecaseWrap(errors.New("failed to begin transaction"), err)
 	}
+	// покупка не возвращается, пока транзакция не зафиксирована: иначе по ней создались бы
+	// отправления и платеж
 	defer func() {
 		if err != nil {
-			tx.Rollback(ctx)
-		} else {
-			tx.Commit(ctx)
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if err = tx.Commit(ctx); err != nil {
+			response, err = nil, entity.UsecaseWrap(errors.New("failed to commit transaction"), err)
 		}
 	}()
 
-	purchase, err := s.purchaseRepo.Add(tx, &entity.Purchase{
+	// объявления блокируются до конца транзакции: параллельная покупка тех же объявлений
+	// дождется ее и увидит их уже зарезервированными
+	states, err := s.advertRepo.LockByCartId(ctx, tx, purchaseRequest.CartID)
+	if err != nil {
+		return nil, entity.UsecaseWrap(errors.New("failed to lock adverts"), err)
+	}
+	// пустая или чужая корзина не блокирует ни одного объявления
+	if len(states) == 0 {
+		return nil, entity.UsecaseWrap(usecase.ErrPurchaseEmptyCart, usecase.ErrPurchaseEmptyCart)
+	}
+	var unavailable []dto.UnavailableAdvert
+	for _, state := range states {
+		if reason := unavailableReason(state, userId); reason != "" {
+			unavailable = append(unavailable, dto.UnavailableAdvert{AdvertID: state.AdvertID, Status: state.Status, Reason: reason})
+		}
+	}
+	if len(unavailable) > 0 {
+		err = &usecase.UnavailableAdvertsError{Adverts: unavailable}
+		middleware.GetLogger(ctx).Warn("cart has unavailable adverts", zap.Error(err),
+			zap.String("cart_id", purchaseRequest.CartID.String()))
+		return nil, err
+	}
+
+	purchase, err := s.purchaseRepo.Add(ctx, tx, &entity.Purchase{
 		CartID:         purchaseRequest.CartID,
 		Address:        purchaseRequest.Address,
 		Status:         entity.StatusPending,
 		PaymentMethod:  entity.PaymentMethod(purchaseRequest.PaymentMethod),
 		DeliveryMethod: entity.DeliveryMethod(purchaseRequest.DeliveryMethod),
+		SafeDeal:       purchaseRequest.SafeDeal,
 	})
 	if err != nil {
 		return nil, entity.UsecaseWrap(errors.New("failed to add purchase"), err)
 	}
 
-	err = s.cartRepo.UpdateStatus(tx, purchase.CartID, entity.CartStatusInactive)
+	err = s.purchaseRepo.AddAddress(ctx, tx, &entity.PurchaseAddress{
+		PurchaseID: purchase.ID,
+		AddressID:  address.AddressID,
+		AddressFields: entity.AddressFields{
+			RecipientName: address.RecipientName,
+			Phone:         address.Phone,
+			City:          address.City,
+			Street:        address.Street,
+			Building:      address.Building,
+			Apartment:     address.Apartment,
+			Postcode:      address.Postcode,
+		},
+	})
 	if err != nil {
-		return nil, entity.UsecaseWrap(errors.New("failed to update cart status"), err)
+		return nil, entity.UsecaseWrap(errors.New("failed to add purchase address"), err)
 	}
 
-	adverts, err := s.advertRepo.GetByCartId(purchase.CartID, userId)
+	err = s.cartRepo.UpdateStatus(ctx, tx, purchase.CartID, entity.CartStatusInactive)
 	if err != nil {
-		return nil, entity.UsecaseWrap(errors.New("failed to get adverts"), err)
+		return nil, entity.UsecaseWrap(errors.New("failed to update cart status"), err)
 	}
 
-	for _, advert := range adverts {
-		err = s.advertRepo.UpdateStatus(tx, advert.ID, entity.AdvertStatusReserved)
+	for _, state := range states {
+		err = s.advertRepo.UpdateStatus(ctx, tx, state.AdvertID, entity.AdvertStatusReserved)
 		if err != nil {
 			return nil, entity.UsecaseWrap(errors.New("failed to update advert status"), err)
 		}
 	}
 
-	return s.purchaseEntityToDTO(purchase)
+	response, err = s.purchaseEntityToDTO(purchase)
+	if err != nil {
+		return nil, err
+	}
+	response.AddressDetails = address
+	return response, nil
 }
 
-func (s *PurchaseService) GetByUserId(userID uuid.UUID) ([]*dto.PurchaseResponse, error) {
-	purchases, err := s.purchaseRepo.GetByUserId(userID)
+func (s *PurchaseService) GetByUserId(ctx context.Context, userID uuid.UUID) ([]*dto.PurchaseResponse, error) {
+	purchases, err := s.purchaseRepo.GetByUserId(ctx, userID)
 	if err != nil {
 		return nil, entity.UsecaseWrap(errors.New("failed to get purchases"), err)
 	}
 
-	return s.purchaseEntitiesToDTO(purchases)
+	return s.purchaseEntitiesToDTO(ctx, purchases)
 }
 
-func (s *PurchaseService) purchaseEntitiesToDTO(purchases []*entity.Purchase) ([]*dto.PurchaseResponse, error) {
-	ctx := context.Background()
+func (s *PurchaseService) purchaseEntitiesToDTO(ctx context.Context, purchases []*entity.Purchase) ([]*dto.PurchaseResponse, error) {
 	var purchaseDTOs []*dto.PurchaseResponse
 
 	for _, purchase := range purchases {
@@ -1,16 +1,21 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 )
 
 func setup(t *testing.T) (*PurchaseService, *mocks.MockPurchaseRepository, *mocks.MockCart, *mocks.MockAdvertRepository, *gomock.Controller) {
@@ -26,16 +31,17 @@ func TestPurchaseService_AddPurchase_FailureInBeginTransaction(t *testing.T) {
 	service, purchaseRepo, _, _, ctrl := setup(t)
 	defer ctrl.Finish()
 
-	purchaseRepo.EXPECT().BeginTransaction().Return(nil, errors.New("begin transaction error"))
+	purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(nil, errors.New("begin transaction error"))
 
 	purchaseRequest := dto.PurchaseRequest{
-		CartID:         uuid.New(),
-		Address:        "123 Street",
-		PaymentMethod:  dto.PaymentMethodCard,
-		DeliveryMethod: dto.DeliveryMethodPickup,
+		CartID:          uuid.New(),
+		Address:         "123 Street",
+		PaymentMethod:   dto.PaymentMethodCard,
+		DeliveryMethod:  dto.DeliveryMethodPickup,
+		PurchaseAddress: testPurchaseAddress(),
 	}
 
-	resp, err := service.Add(purchaseRequest, uuid.New())
+	resp, err := service.Add(context.Background(), purchaseRequest, uuid.New())
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
@@ -51,9 +57,9 @@ func TestPurchaseService_GetPurchasesByUserID_Success(t *testing.T) {
 		{ID: uuid.New(), CartID: uuid.New(), Status: entity.StatusCompleted},
 	}
 
-	purchaseRepo.EXPECT().GetByUserId(userID).Return(mockPurchases, nil)
+	purchaseRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(mockPurchases, nil)
 
-	resp, err := service.GetByUserId(userID)
+	resp, err := service.GetByUserId(context.Background(), userID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
@@ -68,14 +74,15 @@ func TestPurchaseService_AddPurchase_InvalidCartID(t *testing.T) {
 	invalidCartID := uuid.Nil
 
 	purchaseRequest := dto.PurchaseRequest{
-		CartID:         invalidCartID,
-		Address:        "123 Street",
-		PaymentMethod:  dto.PaymentMethodCard,
-		DeliveryMethod: dto.DeliveryMethodPickup,
+		CartID:          invalidCartID,
+		Address:         "123 Street",
+		PaymentMethod:   dto.PaymentMethodCard,
+		DeliveryMethod:  dto.DeliveryMethodPickup,
+		PurchaseAddress: testPurchaseAddress(),
 	}
 
-	purchaseRepo.EXPECT().BeginTransaction().Return(nil, errors.New("invalid cart ID"))
-	resp, err := service.Add(purchaseRequest, uuid.New())
+	purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(nil, errors.New("invalid cart ID"))
+	resp, err := service.Add(context.Background(), purchaseRequest, uuid.New())
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
@@ -88,9 +95,9 @@ func TestPurchaseService_GetPurchasesByUserID_NoPurchases(t *testing.T) {
 
 	userID := uuid.New()
 
-	purchaseRepo.EXPECT().GetByUserId(userID).Return([]*entity.Purchase{}, nil)
+	purchaseRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return([]*entity.Purchase{}, nil)
 
-	resp, err := service.GetByUserId(userID)
+	resp, err := service.GetByUserId(context.Background(), userID)
 
 	assert.NoError(t, err)
 	assert.Nil(t, resp)
@@ -103,11 +110,201 @@ func TestPurchaseService_GetPurchasesByUserID_RepositoryError(t *testing.T) {
 
 	userID := uuid.New()
 
-	purchaseRepo.EXPECT().GetByUserId(userID).Return(nil, errors.New("repository error"))
+	purchaseRepo.EXPECT().GetByUserId(gomock.Any(), userID).Return(nil, errors.New("repository error"))
 
-	resp, err := service.GetByUserId(userID)
+	resp, err := service.GetByUserId(context.Background(), userID)
 
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "repository error")
 }
+
+// purchaseTx возвращает транзакцию, которая должна завершиться commit или rollback
+func purchaseTx(t *testing.T, commit bool) (pgx.Tx, pgxmock.PgxPoolIface) {
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	pool.ExpectBegin()
+	tx, err := pool.Begin(context.Background())
+	require.NoError(t, err)
+	if commit {
+		pool.ExpectCommit()
+	} else {
+		pool.ExpectRollback()
+	}
+	return tx, pool
+}
+
+// testPurchaseAddress возвращает проверенный адрес покупки
+func testPurchaseAddress() *dto.PurchaseAddress {
+	return &dto.PurchaseAddress{
+		AddressDetails: dto.AddressDetails{RecipientName: "Иван Иванов", Phone: "+79991234567", City: "Москва",
+			Street: "Тверская ул.", Building: "1"},
+	}
+}
+
+func TestPurchaseService_AddPurchase_ReservesAdverts(t *testing.T) {
+	service, purchaseRepo, cartRepo, advertRepo, ctrl := setup(t)
+	defer ctrl.Finish()
+
+	userID, cartID, advertID := uuid.New(), uuid.New(), uuid.New()
+	tx, pool := purchaseTx(t, true)
+	purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	advertRepo.EXPECT().LockByCartId(gomock.Any(), tx, cartID).Return([]*entity.AdvertSaleState{
+		{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: uuid.New()},
+	}, nil)
+	purchaseRepo.EXPECT().Add(gomock.Any(), tx, gomock.Any()).
+		Return(&entity.Purchase{ID: uuid.New(), CartID: cartID, Status: entity.StatusPending}, nil)
+	purchaseRepo.EXPECT().AddAddress(gomock.Any(), tx, gomock.Any()).Return(nil)
+	cartRepo.EXPECT().UpdateStatus(gomock.Any(), tx, cartID, entity.CartStatusInactive).Return(nil)
+	advertRepo.EXPECT().UpdateStatus(gomock.Any(), tx, advertID, entity.AdvertStatusReserved).Return(nil)
+
+	resp, err := service.Add(context.Background(), dto.PurchaseRequest{CartID: cartID, PurchaseAddress: testPurchaseAddress()}, userID)
+
+	require.NoError(t, err)
+	assert.Equal(t, cartID, resp.CartID)
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
+
+func TestPurchaseService_AddPurchase_CommitFailure(t *testing.T) {
+	service, purchaseRepo, cartRepo, advertRepo, ctrl := setup(t)
+	defer ctrl.Finish()
+
+	userID, cartID, advertID := uuid.New(), uuid.New(), uuid.New()
+	pool, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer pool.Close()
+	pool.ExpectBegin()
+	tx, err := pool.Begin(context.Background())
+	require.NoError(t, err)
+	pool.ExpectCommit().WillReturnError(errors.New("connection lost"))
+
+	purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	advertRepo.EXPECT().LockByCartId(gomock.Any(), tx, cartID).Return([]*entity.AdvertSaleState{
+		{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: uuid.New()},
+	}, nil)
+	purchaseRepo.EXPECT().Add(gomock.Any(), tx, gomock.Any()).
+		Return(&entity.Purchase{ID: uuid.New(), CartID: cartID, Status: entity.StatusPending}, nil)
+	purchaseRepo.EXPECT().AddAddress(gomock.Any(), tx, gomock.Any()).Return(nil)
+	cartRepo.EXPECT().UpdateStatus(gomock.Any(), tx, cartID, entity.CartStatusInactive).Return(nil)
+	advertRepo.EXPECT().UpdateStatus(gomock.Any(), tx, advertID, entity.AdvertStatusReserved).Return(nil)
+
+	resp, err := service.Add(context.Background(), dto.PurchaseRequest{CartID: cartID, PurchaseAddress: testPurchaseAddress()}, userID)
+
+	assert.Nil(t, resp)
+	assert.ErrorContains(t, err, "connection lost")
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
+
+func TestPurchaseService_AddPurchase_EmptyCart(t *testing.T) {
+	service, purchaseRepo, _, advertRepo, ctrl := setup(t)
+	defer ctrl.Finish()
+
+	userID, cartID := uuid.New(), uuid.New()
+	tx, pool := purchaseTx(t, false)
+	purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	advertRepo.EXPECT().LockByCartId(gomock.Any(), tx, cartID).Return(nil, nil)
+
+	resp, err := service.Add(context.Background(), dto.PurchaseRequest{CartID: cartID, PurchaseAddress: testPurchaseAddress()}, userID)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, usecase.ErrPurchaseEmptyCart)
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
+
+func TestPurchaseService_AddPurchase_AddressRequired(t *testing.T) {
+	service, _, _, _, ctrl := setup(t)
+	defer ctrl.Finish()
+
+	resp, err := service.Add(context.Background(), dto.PurchaseRequest{CartID: uuid.New()}, uuid.New())
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, usecase.ErrPurchaseAddressRequired)
+}
+
+func TestPurchaseService_AddPurchase_Address(t *testing.T) {
+	userID, cartID, advertID, purchaseID, addressID := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	address := &dto.PurchaseAddress{
+		AddressID: &addressID,
+		AddressDetails: dto.AddressDetails{RecipientName: "Иван Иванов", Phone: "+79991234567", City: "Москва",
+			Street: "Тверская ул.", Building: "1"},
+	}
+	snapshot := &entity.PurchaseAddress{
+		PurchaseID: purchaseID,
+		AddressID:  &addressID,
+		AddressFields: entity.AddressFields{RecipientName: "Иван Иванов", Phone: "+79991234567", City: "Москва",
+			Street: "Тверская ул.", Building: "1"},
+	}
+	request := dto.PurchaseRequest{CartID: cartID, PurchaseAddress: address}
+
+	expectPurchase := func(purchaseRepo *mocks.MockPurchaseRepository, advertRepo *mocks.MockAdvertRepository, tx pgx.Tx) {
+		purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+		advertRepo.EXPECT().LockByCartId(gomock.Any(), tx, cartID).Return([]*entity.AdvertSaleState{
+			{AdvertID: advertID, Status: entity.AdvertStatusActive, SellerUserID: uuid.New()},
+		}, nil)
+		purchaseRepo.EXPECT().Add(gomock.Any(), tx, gomock.Any()).
+			Return(&entity.Purchase{ID: purchaseID, CartID: cartID, Status: entity.StatusPending}, nil)
+	}
+
+	t.Run("SavedInPurchaseTransaction", func(t *testing.T) {
+		service, purchaseRepo, cartRepo, advertRepo, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		tx, pool := purchaseTx(t, true)
+		expectPurchase(purchaseRepo, advertRepo, tx)
+		purchaseRepo.EXPECT().AddAddress(gomock.Any(), tx, snapshot).Return(nil)
+		cartRepo.EXPECT().UpdateStatus(gomock.Any(), tx, cartID, entity.CartStatusInactive).Return(nil)
+		advertRepo.EXPECT().UpdateStatus(gomock.Any(), tx, advertID, entity.AdvertStatusReserved).Return(nil)
+
+		resp, err := service.Add(context.Background(), request, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, address, resp.AddressDetails)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+
+	t.Run("FailureRollsBackPurchase", func(t *testing.T) {
+		service, purchaseRepo, _, advertRepo, ctrl := setup(t)
+		defer ctrl.Finish()
+
+		tx, pool := purchaseTx(t, false)
+		expectPurchase(purchaseRepo, advertRepo, tx)
+		purchaseRepo.EXPECT().AddAddress(gomock.Any(), tx, snapshot).Return(entity.ErrPSQL)
+
+		resp, err := service.Add(context.Background(), request, userID)
+
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, entity.ErrPSQL)
+		assert.NoError(t, pool.ExpectationsWereMet())
+	})
+}
+
+func TestPurchaseService_AddPurchase_UnavailableAdverts(t *testing.T) {
+	service, purchaseRepo, _, advertRepo, ctrl := setup(t)
+	defer ctrl.Finish()
+
+	userID, cartID := uuid.New(), uuid.New()
+	activeID, reservedID, ownID, draftID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	tx, pool := purchaseTx(t, false)
+	purchaseRepo.EXPECT().BeginTransaction(gomock.Any()).Return(tx, nil)
+	advertRepo.EXPECT().LockByCartId(gomock.Any(), tx, cartID).Return([]*entity.AdvertSaleState{
+		{AdvertID: activeID, Status: entity.AdvertStatusActive, SellerUserID: uuid.New()},
+		{AdvertID: reservedID, Status: entity.AdvertStatusReserved, SellerUserID: uuid.New()},
+		{AdvertID: ownID, Status: entity.AdvertStatusActive, SellerUserID: userID},
+		{AdvertID: draftID, Status: entity.AdvertStatusDraft, SellerUserID: uuid.New()},
+	}, nil)
+
+	resp, err := service.Add(context.Background(), dto.PurchaseRequest{CartID: cartID, PurchaseAddress: testPurchaseAddress()}, userID)
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, usecase.ErrCartAdvertUnavailable)
+	var unavailable *usecase.UnavailableAdvertsError
+	require.ErrorAs(t, err, &unavailable)
+	assert.Equal(t, []dto.UnavailableAdvert{
+		{AdvertID: reservedID, Status: entity.AdvertStatusReserved, Reason: dto.UnavailableReserved},
+		{AdvertID: ownID, Status: entity.AdvertStatusActive, Reason: dto.UnavailableOwnAdvert},
+		{AdvertID: draftID, Status: entity.AdvertStatusDraft, Reason: dto.UnavailableInactive},
+	}, unavailable.Adverts)
+	assert.NoError(t, pool.ExpectationsWereMet())
+}
@@ -3,16 +3,18 @@ package service
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"crypto/sha256"
+	"encoding/hex"
 	"image"
 	"image/draw"
+	_ "image/gif"
 	_ "image/png"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
-
-	"github.com/chai2010/webp"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
@@ -24,66 +26,100 @@ import (
 )
 
 type StaticService struct {
-	staticRepo repository.StaticRepository
+	staticRepo    repository.StaticRepository
+	blobStore     repository.BlobStore
+	uriPrefix     string
+	gcGracePeriod time.Duration
+	gcBatchSize   int
+	video         usecase.VideoProcessor
+	inspectors    []usecase.ImageInspector
 }
 
-func NewStaticService(staticRepo repository.StaticRepository) *StaticService {
+// NewStaticService создает сервис статики. uriPrefix - префикс путей статики, под которым
+// они хранятся в базе и отдаются клиентам; ключ объекта в blobStore - путь без этого префикса.
+// Сборщик мусора не трогает статику моложе gcGracePeriod: ее могли загрузить для еще не сохраненного объявления.
+// Каждое загружаемое изображение и обложка видео проходят все inspectors до сохранения.
+// Если video = nil, загрузка видео отключена
+func NewStaticService(staticRepo repository.StaticRepository,
+	blobStore repository.BlobStore,
+	uriPrefix string,
+	gcGracePeriod time.Duration,
+	gcBatchSize int,
+	video usecase.VideoProcessor,
+	inspectors ...usecase.ImageInspector) *StaticService {
 	return &StaticService{
-		staticRepo: staticRepo,
+		staticRepo:    staticRepo,
+		blobStore:     blobStore,
+		uriPrefix:     uriPrefix,
+		gcGracePeriod: gcGracePeriod,
+		gcBatchSize:   gcBatchSize,
+		video:         video,
+		inspectors:    inspectors,
 	}
 }
 
-func (s *StaticService) GetAvatar(staticID uuid.UUID) (string, error) {
-	logger := middleware.GetLogger(context.Background())
+func (s *StaticService) GetAvatar(ctx context.Context, staticID uuid.UUID) (string, error) {
+	logger := middleware.GetLogger(ctx)
 	logger.Info("GetAvatar request", zap.String("staticID", staticID.String()))
-	path, err := s.staticRepo.Get(staticID)
+	path, err := s.staticRepo.Get(ctx, staticID)
 	if err != nil {
 		return "", err
 	}
 	return path, nil
 }
 
-func (s *StaticService) UploadStatic(reader io.ReadSeeker) (uuid.UUID, error) {
-	size, err := reader.Seek(0, io.SeekEnd)
-	if err != nil {
-		return uuid.Nil, entity.UsecaseWrap(err, errors.New("error determining file size"))
+func (s *StaticService) UploadStatic(ctx context.Context, reader io.Reader, metadata entity.UploadMetadata) (uuid.UUID, error) {
+	logger := middleware.GetLogger(ctx)
+
+	rule, ok := uploadRules[metadata.Purpose]
+	if !ok {
+		return uuid.Nil, usecase.ErrStaticUnknownPurpose
 	}
-	if size > int64(s.staticRepo.GetMaxSize()) {
+
+	limit := rule.limit(s.staticRepo.GetMaxSize())
+	if metadata.Size > limit {
 		return uuid.Nil, usecase.ErrStaticTooBigFile
 	}
-	_, err = reader.Seek(0, io.SeekStart)
-	if err != nil {
-		return uuid.Nil, entity.UsecaseWrap(err, errors.New("error returning io.ReadSeeker to the start of the file"))
+	if metadata.ContentType != "" && !rule.contentTypes()[metadata.ContentType] {
+		return uuid.Nil, rule.notAllowedType()
 	}
 
-	headerBytes := make([]byte, 512)
-	_, err = reader.Read(headerBytes)
+	upload, err := spoolUpload(reader, limit)
 	if err != nil {
-		return uuid.Nil, entity.UsecaseWrap(err, errors.New("error reading file header"))
+		return uuid.Nil, err
 	}
-	contentType := http.DetectContentType(headerBytes)
-	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" && contentType != "image/webp" {
-		return uuid.Nil, usecase.ErrStaticNotImage
+	defer upload.Close()
+
+	contentType := http.DetectContentType(upload.head)
+	if !rule.contentTypes()[contentType] {
+		return uuid.Nil, rule.notAllowedType()
+	}
+	if rule.video {
+		return s.uploadVideo(ctx, upload, entity.VideoFormats[contentType], rule)
 	}
-	_, err = reader.Seek(0, io.SeekStart)
+
+	// Размеры читаются из заголовка до декодирования, чтобы маленький файл
+	// с огромными заявленными размерами не занял всю память при распаковке
+	config, _, err := image.DecodeConfig(upload.reader())
 	if err != nil {
-		return uuid.Nil, entity.UsecaseWrap(err, errors.New("error returning io.ReadSeeker to the start of the file"))
+		return uuid.Nil, usecase.ErrStaticNotImage
+	}
+	if err := rule.checkDimensions(config.Width, config.Height); err != nil {
+		return uuid.Nil, err
 	}
 
-	img, _, err := image.Decode(reader)
+	img, _, err := image.Decode(upload.reader())
 	if err != nil {
 		return uuid.Nil, usecase.ErrStaticNotImage
 	}
+	// Пиксели хранятся так, как их записала камера, а поворот указан в EXIF,
+	// который при перекодировании теряется
+	img = orientImage(img, fileExifOrientation(upload.file, upload.size))
 
-	const minImageWidth, minImageHeight = 100, 100
-	if img.Bounds().Dx() < minImageWidth || img.Bounds().Dy() < minImageHeight {
-		return uuid.Nil, entity.UsecaseWrap(
-			usecase.ErrStaticImageDimensions,
-			fmt.Errorf(
-				"image dimensions are %dx%d, but must be at least %dx%d",
-				img.Bounds().Dx(), img.Bounds().Dy(), minImageWidth, minImageHeight,
-			),
-		)
+	inspection := s.inspectImage(ctx, img)
+	if inspection.Verdict == entity.ImageVerdictReject {
+		logger.Info("static image rejected", zap.String("reason", inspection.Reason))
+		return uuid.Nil, usecase.ErrStaticImageRejected
 	}
 
 	width, height := img.Bounds().Dx(), img.Bounds().Dy()
@@ -100,16 +136,15 @@ func (s *StaticService) UploadStatic(reader io.ReadSeeker) (uuid.UUID, error) {
 	squareImage = image.NewRGBA(image.Rect(0, 0, squareSize, squareSize))
 	draw.Draw(squareImage, squareImage.Bounds(), img, start, draw.Src)
 
-	var out bytes.Buffer
-	var opts webp.Options
-	opts.Lossless = false
-	opts.Quality = 60
-	if err = webp.Encode(&out, squareImage, &opts); err != nil {
+	out, err := encodeImage(entity.ImageFormatWebP, squareImage, uploadQuality)
+	if err != nil {
 		return uuid.Nil, errors.Wrap(err, "error converting image to WEBP format")
 	}
 
-	newUUID := uuid.New()
-	id, err := s.staticRepo.Upload("images", newUUID.String()+".webp", out.Bytes())
+	// Имя файла - хэш содержимого, поэтому одинаковые изображения хранятся один раз
+	hash := sha256.Sum256(out)
+	name := hex.EncodeToString(hash[:]) + ".webp"
+	id, err := s.staticRepo.Upload(ctx, "images", name, bytes.NewReader(out), int64(len(out)))
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -118,26 +153,297 @@ func (s *StaticService) UploadStatic(reader io.ReadSeeker) (uuid.UUID, error) {
 		return uuid.Nil, errors.New("failed to generate UUID for static file")
 	}
 
+	s.flagForModeration(ctx, id, inspection)
+
+	s.prepareVariants(ctx, name, img)
+
 	return id, nil
 }
 
-func (s *StaticService) GetStaticFile(staticURI string) (io.ReadSeeker, error) {
-	absolutePath, err := filepath.Abs(staticURI)
+// spooledUpload - загружаемый файл, сохраненный во временный файл
+type spooledUpload struct {
+	file *os.File
+	size int64
+	// hash - SHA-256 содержимого в hex, посчитанный при записи
+	hash string
+	// head - начало файла, по которому определяется тип содержимого
+	head []byte
+}
+
+// spoolUpload пишет поток во временный файл и считает хэш содержимого, не держа файл в памяти.
+// Читается не больше limit+1 байт: лишний байт означает, что файл превышает лимит,
+// и остаток потока уже не принимается
+func spoolUpload(reader io.Reader, limit int64) (*spooledUpload, error) {
+	file, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error creating temporary upload file"))
+	}
+	upload := &spooledUpload{file: file}
+
+	hasher := sha256.New()
+	upload.size, err = io.Copy(file, io.TeeReader(io.LimitReader(reader, limit+1), hasher))
+	if err != nil {
+		upload.Close()
+		return nil, entity.UsecaseWrap(err, errors.New("error reading uploaded file"))
+	}
+	if upload.size > limit {
+		upload.Close()
+		return nil, usecase.ErrStaticTooBigFile
+	}
+	upload.hash = hex.EncodeToString(hasher.Sum(nil))
+
+	upload.head = make([]byte, min(upload.size, 512))
+	if _, err := file.ReadAt(upload.head, 0); err != nil {
+		upload.Close()
+		return nil, entity.UsecaseWrap(err, errors.New("error reading uploaded file"))
+	}
+	return upload, nil
+}
+
+// reader возвращает поток чтения файла с начала
+func (u *spooledUpload) reader() io.ReadSeeker {
+	return io.NewSectionReader(u.file, 0, u.size)
+}
+
+// Close закрывает и удаляет временный файл
+func (u *spooledUpload) Close() error {
+	err := u.file.Close()
+	if removeErr := os.Remove(u.file.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// flagForModeration отправляет статику на ручную проверку, если этого требует результат inspection
+func (s *StaticService) flagForModeration(ctx context.Context, id uuid.UUID, inspection entity.ImageInspection) {
+	if inspection.Verdict != entity.ImageVerdictFlag {
+		return
+	}
+	if err := s.staticRepo.Flag(ctx, id, inspection.Reason); err != nil {
+		logger := middleware.GetLogger(ctx)
+		logger.Error("failed to flag static", zap.String("static_id", id.String()), zap.Error(err))
+	}
+}
+
+// originalKey возвращает ключ копии изображения с исходными пропорциями, из которой строятся
+// варианты. Для видео это кадр-обложка
+func originalKey(name string) string {
+	return "originals/" + name
+}
+
+// variantSource возвращает имя файла без расширения, по которому группируются его варианты
+func variantSource(key string) string {
+	name := path.Base(key)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// prepareVariants сохраняет копию с исходными пропорциями и заранее создает ходовые пресеты.
+// Ошибки не прерывают загрузку: недостающие варианты будут созданы при первом запросе
+func (s *StaticService) prepareVariants(ctx context.Context, name string, img image.Image) {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.blobStore.Stat(ctx, originalKey(name)); err == nil {
+		logger.Info("image variants already prepared", zap.String("name", name))
+		return
+	}
+
+	original := resizeImage(img, entity.ImageVariant{
+		Width:  entity.MaxImageVariantSide,
+		Height: entity.MaxImageVariantSide,
+		Fit:    entity.ImageFitContain,
+	})
+	out, err := encodeImage(entity.ImageFormatWebP, original, originalQuality)
 	if err != nil {
-		return nil, entity.UsecaseWrap(err, errors.New("error determining absolute path"))
+		logger.Warn("failed to encode original image", zap.String("name", name), zap.Error(err))
+		return
+	}
+	if err := s.blobStore.Put(ctx, originalKey(name), bytes.NewReader(out), int64(len(out)), entity.ImageFormatWebP.ContentType()); err != nil {
+		logger.Warn("failed to store original image", zap.String("name", name), zap.Error(err))
+		return
 	}
 
-	if _, err := os.Stat(absolutePath); os.IsNotExist(err) {
+	for _, preset := range entity.EagerImagePresets {
+		variant, err := entity.ImagePreset(preset, entity.ImageFormatWebP)
+		if err == nil {
+			_, err = s.storeVariant(ctx, variantSource(name), original, variant)
+		}
+		if err != nil {
+			logger.Warn("failed to prepare image variant", zap.String("name", name),
+				zap.String("preset", preset), zap.Error(err))
+		}
+	}
+}
+
+// storeVariant кодирует вариант изображения и сохраняет его в хранилище
+func (s *StaticService) storeVariant(ctx context.Context, source string, img image.Image, variant entity.ImageVariant) ([]byte, error) {
+	variant, err := variant.Normalize()
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, usecase.ErrStaticInvalidVariant)
+	}
+	if _, ok := imageEncoders[variant.Format]; !ok {
+		return nil, usecase.ErrStaticUnsupportedFormat
+	}
+
+	data, err := encodeImage(variant.Format, resizeImage(img, variant), variantQuality)
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error encoding image variant"))
+	}
+	if err := s.blobStore.Put(ctx, variant.Key(source), bytes.NewReader(data), int64(len(data)), variant.Format.ContentType()); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error storing image variant"))
+	}
+	return data, nil
+}
+
+// blobKey возвращает ключ файла статики id в хранилище
+func (s *StaticService) blobKey(ctx context.Context, id uuid.UUID) (string, error) {
+	uri, err := s.staticRepo.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	key, ok := strings.CutPrefix(uri, s.uriPrefix)
+	if !ok {
+		return "", usecase.ErrStaticNotFound
+	}
+	return key, nil
+}
+
+// loadVariantSource декодирует изображение, из которого строятся варианты. Файлы, загруженные
+// до появления вариантов, не имеют копии с исходными пропорциями, для них берется квадратная
+func (s *StaticService) loadVariantSource(ctx context.Context, key string) (image.Image, error) {
+	for _, sourceKey := range []string{originalKey(path.Base(key)), key} {
+		file, err := s.blobStore.Get(ctx, sourceKey)
+		if errors.Is(err, repository.ErrBlobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, entity.UsecaseWrap(err, errors.New("error opening file"))
+		}
+		img, _, err := image.Decode(file)
+		file.Close()
+		if err != nil {
+			return nil, entity.UsecaseWrap(err, usecase.ErrStaticNotImage)
+		}
+		return img, nil
+	}
+	return nil, usecase.ErrStaticNotFound
+}
+
+func (s *StaticService) GetStaticVariant(ctx context.Context, id uuid.UUID, variant entity.ImageVariant) (io.ReadSeeker, error) {
+	variant, err := variant.Normalize()
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, usecase.ErrStaticInvalidVariant)
+	}
+	if _, ok := imageEncoders[variant.Format]; !ok {
+		return nil, usecase.ErrStaticUnsupportedFormat
+	}
+
+	key, err := s.blobKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	source := variantSource(key)
+
+	file, err := s.blobStore.Get(ctx, variant.Key(source))
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, repository.ErrBlobNotFound) {
+		return nil, entity.UsecaseWrap(err, errors.New("error opening image variant"))
+	}
+
+	img, err := s.loadVariantSource(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.storeVariant(ctx, source, img, variant)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// CollectGarbage удаляет статику без ссылок, а вместе с освободившимися файлами - их копии и варианты
+func (s *StaticService) CollectGarbage(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	deleted, err := s.staticRepo.CollectGarbage(ctx, time.Now().Add(-s.gcGracePeriod), s.gcBatchSize)
+	if err != nil {
+		return entity.UsecaseWrap(err, err)
+	}
+
+	for _, key := range deleted {
+		if err := s.blobStore.Delete(ctx, originalKey(path.Base(key))); err != nil {
+			logger.Warn("failed to delete original image", zap.String("key", key), zap.Error(err))
+		}
+		if err := s.blobStore.DeletePrefix(ctx, entity.ImageVariantsPrefix(variantSource(key))); err != nil {
+			logger.Warn("failed to delete image variants", zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	logger.Info("static garbage collected", zap.Int("deleted", len(deleted)))
+	return nil
+}
+
+// fileSource возвращает имя файла статики без расширения, к которому относится ключ key:
+// сам файл, его копия с исходными пропорциями или вариант
+func fileSource(key string) string {
+	if rest, ok := strings.CutPrefix(key, entity.ImageVariantsPrefix("")); ok {
+		source, _, _ := strings.Cut(rest, "/")
+		return source
+	}
+	return variantSource(key)
+}
+
+// checkModeration не дает отдать по пути файл статики, которую модератор еще не одобрил
+func (s *StaticService) checkModeration(ctx context.Context, key string) error {
+	flagged, err := s.staticRepo.IsFlagged(ctx, fileSource(key))
+	if err != nil {
+		return entity.UsecaseWrap(err, errors.New("error checking static moderation"))
+	}
+	if flagged {
+		return usecase.ErrStaticNotFound
+	}
+	return nil
+}
+
+func (s *StaticService) GetStaticFile(ctx context.Context, staticURI string) (io.ReadSeeker, error) {
+	key, ok := strings.CutPrefix(staticURI, s.uriPrefix)
+	if !ok {
 		return nil, usecase.ErrStaticNotFound
 	}
+	if err := s.checkModeration(ctx, key); err != nil {
+		return nil, err
+	}
 
-	file, err := os.Open(absolutePath)
+	file, err := s.blobStore.Get(ctx, key)
 	if err != nil {
+		if errors.Is(err, repository.ErrBlobNotFound) || errors.Is(err, repository.ErrBlobInvalidKey) {
+			return nil, usecase.ErrStaticNotFound
+		}
 		return nil, entity.UsecaseWrap(err, errors.New("error opening file"))
 	}
 	return file, nil
 }
 
-func (s *StaticService) GetStatic(id uuid.UUID) (string, error) {
-	return s.staticRepo.Get(id)
+func (s *StaticService) GetStaticFileInfo(ctx context.Context, staticURI string) (*entity.BlobInfo, error) {
+	key, ok := strings.CutPrefix(staticURI, s.uriPrefix)
+	if !ok {
+		return nil, usecase.ErrStaticNotFound
+	}
+	if err := s.checkModeration(ctx, key); err != nil {
+		return nil, err
+	}
+
+	info, err := s.blobStore.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrBlobNotFound) || errors.Is(err, repository.ErrBlobInvalidKey) {
+			return nil, usecase.ErrStaticNotFound
+		}
+		return nil, entity.UsecaseWrap(err, errors.New("error getting file info"))
+	}
+	return info, nil
+}
+
+func (s *StaticService) GetStatic(ctx context.Context, id uuid.UUID) (string, error) {
+	return s.staticRepo.Get(ctx, id)
 }
@@ -2,25 +2,46 @@ package service
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
 	"image"
 	"image/color"
+	"image/draw"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/chai2010/webp"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
 )
 
+const testStaticURIPrefix = "static_files/"
+
 func setupStaticTest(t *testing.T) (*StaticService, *mocks.MockStaticRepository, *gomock.Controller) {
+	service, mockRepo, _, ctrl := setupStaticBlobTest(t)
+	return service, mockRepo, ctrl
+}
+
+func setupStaticBlobTest(t *testing.T) (*StaticService, *mocks.MockStaticRepository, *mocks.MockBlobStore, *gomock.Controller) {
 	ctrl := gomock.NewController(t)
 	mockRepo := mocks.NewMockStaticRepository(ctrl)
-	service := NewStaticService(mockRepo)
-	return service, mockRepo, ctrl
+	mockStore := mocks.NewMockBlobStore(ctrl)
+	service := NewStaticService(mockRepo, mockStore, testStaticURIPrefix, 24*time.Hour, 100, nil)
+	return service, mockRepo, mockStore, ctrl
 }
 
 func TestStaticService_UploadStatic_FileTooLarge(t *testing.T) {
@@ -32,7 +53,7 @@ func TestStaticService_UploadStatic_FileTooLarge(t *testing.T) {
 
 	mockRepo.EXPECT().GetMaxSize().Return(1024 * 1024) // 1MB
 
-	id, err := service.UploadStatic(reader)
+	id, err := service.UploadStatic(context.Background(), reader, entity.UploadMetadata{})
 
 	assert.Error(t, err)
 	assert.Equal(t, uuid.Nil, id)
@@ -47,12 +68,143 @@ func TestStaticService_UploadStatic_InvalidImage(t *testing.T) {
 
 	mockRepo.EXPECT().GetMaxSize().Return(1024 * 1024)
 
-	id, err := service.UploadStatic(reader)
+	id, err := service.UploadStatic(context.Background(), reader, entity.UploadMetadata{})
 
 	assert.Error(t, err)
 	assert.Equal(t, uuid.Nil, id)
 }
 
+// endlessReader отдает бесконечный поток байт и считает, сколько из него прочитано
+type endlessReader struct {
+	read int64
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func TestStaticService_UploadStatic_StreamStopsAtLimit(t *testing.T) {
+	service, mockRepo, ctrl := setupStaticTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().GetMaxSize().Return(1024 * 1024)
+
+	reader := &endlessReader{}
+	id, err := service.UploadStatic(context.Background(), reader, entity.UploadMetadata{})
+
+	assert.ErrorIs(t, err, usecase.ErrStaticTooBigFile)
+	assert.Equal(t, uuid.Nil, id)
+	assert.LessOrEqual(t, reader.read, int64(2*1024*1024))
+}
+
+func TestSpoolUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("content"), 100)
+
+	upload, err := spoolUpload(bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	hash := sha256.Sum256(content)
+	assert.Equal(t, hex.EncodeToString(hash[:]), upload.hash)
+	assert.Equal(t, int64(len(content)), upload.size)
+	assert.Equal(t, content[:512], upload.head)
+	stored, err := io.ReadAll(upload.reader())
+	assert.NoError(t, err)
+	assert.Equal(t, content, stored)
+
+	assert.NoError(t, upload.Close())
+	_, err = os.Stat(upload.file.Name())
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = spoolUpload(bytes.NewReader(content), int64(len(content))-1)
+	assert.ErrorIs(t, err, usecase.ErrStaticTooBigFile)
+}
+
+func TestStaticService_UploadStatic_DeclaredSizeTooLarge(t *testing.T) {
+	service, mockRepo, ctrl := setupStaticTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+
+	reader := &endlessReader{}
+	id, err := service.UploadStatic(context.Background(), reader, entity.UploadMetadata{
+		Purpose: entity.UploadPurposeAvatar,
+		Size:    6 * 1024 * 1024,
+	})
+
+	assert.ErrorIs(t, err, usecase.ErrStaticTooBigFile)
+	assert.Equal(t, uuid.Nil, id)
+	assert.Zero(t, reader.read)
+}
+
+func TestStaticService_UploadStatic_DeclaredContentType(t *testing.T) {
+	service, mockRepo, ctrl := setupStaticTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+
+	id, err := service.UploadStatic(context.Background(), bytes.NewReader([]byte("%PDF-1.4")), entity.UploadMetadata{
+		Purpose:     entity.UploadPurposeAdvert,
+		ContentType: "application/pdf",
+	})
+
+	assert.ErrorIs(t, err, usecase.ErrStaticNotImage)
+	assert.Equal(t, uuid.Nil, id)
+}
+
+func TestStaticService_UploadStatic_UnknownPurpose(t *testing.T) {
+	service, _, ctrl := setupStaticTest(t)
+	defer ctrl.Finish()
+
+	id, err := service.UploadStatic(context.Background(), bytes.NewReader(nil), entity.UploadMetadata{Purpose: "banner"})
+
+	assert.ErrorIs(t, err, usecase.ErrStaticUnknownPurpose)
+	assert.Equal(t, uuid.Nil, id)
+}
+
+// pngHeader возвращает начало PNG-файла с заголовком IHDR, заявляющим размеры width x height
+func pngHeader(width, height uint32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+
+	chunk := make([]byte, 0, 17)
+	chunk = append(chunk, "IHDR"...)
+	chunk = binary.BigEndian.AppendUint32(chunk, width)
+	chunk = binary.BigEndian.AppendUint32(chunk, height)
+	chunk = append(chunk, 8, 6, 0, 0, 0) // 8 бит, RGBA, без чересстрочности
+
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(chunk)-4))
+	buf.Write(chunk)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	return buf.Bytes()
+}
+
+func TestStaticService_UploadStatic_Dimensions(t *testing.T) {
+	tests := []struct {
+		name     string
+		width    uint32
+		height   uint32
+		metadata entity.UploadMetadata
+	}{
+		{"DecompressionBomb", 100000, 100000, entity.UploadMetadata{}},
+		{"TooSmall", 50, 50, entity.UploadMetadata{Purpose: entity.UploadPurposeAdvert}},
+		{"AvatarTooLarge", 5000, 5000, entity.UploadMetadata{Purpose: entity.UploadPurposeAvatar}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, ctrl := setupStaticTest(t)
+			defer ctrl.Finish()
+
+			mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+
+			id, err := service.UploadStatic(context.Background(), bytes.NewReader(pngHeader(tt.width, tt.height)), tt.metadata)
+
+			assert.ErrorIs(t, err, usecase.ErrStaticImageDimensions)
+			assert.Equal(t, uuid.Nil, id)
+		})
+	}
+}
+
 func generateValidWEBPImage() ([]byte, error) {
 	width, height := 100, 100
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -73,7 +225,7 @@ func generateValidWEBPImage() ([]byte, error) {
 }
 
 func TestStaticService_UploadStatic_Success(t *testing.T) {
-	service, mockRepo, ctrl := setupStaticTest(t)
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
 	defer ctrl.Finish()
 
 	imageData, err := generateValidWEBPImage()
@@ -83,23 +235,177 @@ func TestStaticService_UploadStatic_Success(t *testing.T) {
 	reader := bytes.NewReader(imageData)
 
 	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024) // 10MB
-	mockRepo.EXPECT().Upload("images", gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+	mockRepo.EXPECT().Upload(gomock.Any(), "images", gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+	mockStore.EXPECT().Stat(gomock.Any(), gomock.Any()).Return(nil, repository.ErrBlobNotFound)
+	mockStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "image/webp").Return(nil).Times(1 + len(entity.EagerImagePresets))
 
-	id, err := service.UploadStatic(reader)
+	id, err := service.UploadStatic(context.Background(), reader, entity.UploadMetadata{})
 
 	assert.NoError(t, err)
 	assert.NotEqual(t, uuid.Nil, id)
 }
 
+func TestStaticService_UploadStatic_ContentAddressed(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	imageData, err := generateValidWEBPImage()
+	assert.NoError(t, err)
+
+	var names []string
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024).Times(2)
+	mockRepo.EXPECT().Upload(gomock.Any(), "images", gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, name string, _ io.Reader, _ int64) (uuid.UUID, error) {
+			names = append(names, name)
+			return uuid.New(), nil
+		}).Times(2)
+	// Копия с исходными пропорциями уже есть, варианты второй раз не создаются
+	mockStore.EXPECT().Stat(gomock.Any(), gomock.Any()).Return(&entity.BlobInfo{}, nil).Times(2)
+
+	firstID, err := service.UploadStatic(context.Background(), bytes.NewReader(imageData), entity.UploadMetadata{})
+	assert.NoError(t, err)
+	secondID, err := service.UploadStatic(context.Background(), bytes.NewReader(imageData), entity.UploadMetadata{})
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, firstID, secondID)
+	assert.Len(t, names, 2)
+	assert.Equal(t, names[0], names[1])
+	assert.Regexp(t, "^[0-9a-f]{64}\\.webp$", names[0])
+}
+
+type stubNSFWScorer struct {
+	score float64
+	err   error
+}
+
+func (s stubNSFWScorer) Score(context.Context, image.Image) (float64, error) {
+	return s.score, s.err
+}
+
+func TestSkinToneScorer(t *testing.T) {
+	skin := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(skin, skin.Bounds(), image.NewUniform(color.RGBA{R: 224, G: 172, B: 140, A: 255}), image.Point{}, draw.Src)
+	score, err := NewSkinToneScorer().Score(context.Background(), skin)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0, score, 0.01)
+
+	sky := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(sky, sky.Bounds(), image.NewUniform(color.RGBA{R: 80, G: 140, B: 230, A: 255}), image.Point{}, draw.Src)
+	score, err = NewSkinToneScorer().Score(context.Background(), sky)
+	assert.NoError(t, err)
+	assert.Zero(t, score)
+}
+
+func TestImageInspectors(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 0, 255})
+		}
+	}
+	hash := ImageHash(img)
+	assert.Equal(t, hash, ImageHash(resizeImage(img, entity.ImageVariant{Width: 32, Height: 32})))
+
+	banned, err := ParseImageHashes([]string{fmt.Sprintf("%016x", hash^0b11)})
+	assert.NoError(t, err)
+	_, err = ParseImageHashes([]string{"not a hash"})
+	assert.Error(t, err)
+
+	inspection, err := NewPerceptualHashInspector(banned, 2).Inspect(context.Background(), img)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ImageVerdictReject, inspection.Verdict)
+	inspection, err = NewPerceptualHashInspector(banned, 1).Inspect(context.Background(), img)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.ImageVerdictAllow, inspection.Verdict)
+
+	for score, verdict := range map[float64]entity.ImageVerdict{
+		0.1:  entity.ImageVerdictAllow,
+		0.7:  entity.ImageVerdictFlag,
+		0.95: entity.ImageVerdictReject,
+	} {
+		inspection, err := NewNSFWInspector(stubNSFWScorer{score: score}, 0.6, 0.9).Inspect(context.Background(), img)
+		assert.NoError(t, err)
+		assert.Equal(t, verdict, inspection.Verdict, "score %v", score)
+	}
+}
+
+func TestStaticService_UploadStatic_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockStaticRepository(ctrl)
+	service := NewStaticService(mockRepo, mocks.NewMockBlobStore(ctrl), testStaticURIPrefix, 24*time.Hour, 100, nil,
+		NewNSFWInspector(stubNSFWScorer{score: 0.1}, 0.6, 0.9),
+		NewNSFWInspector(stubNSFWScorer{score: 0.95}, 0.6, 0.9))
+
+	imageData, err := generateValidWEBPImage()
+	assert.NoError(t, err)
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+
+	id, err := service.UploadStatic(context.Background(), bytes.NewReader(imageData), entity.UploadMetadata{})
+	assert.ErrorIs(t, err, usecase.ErrStaticImageRejected)
+	assert.Equal(t, uuid.Nil, id)
+}
+
+func TestStaticService_UploadStatic_Flagged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockStaticRepository(ctrl)
+	mockStore := mocks.NewMockBlobStore(ctrl)
+	// Ошибка проверки не мешает загрузке, но изображение уходит на ручную проверку
+	service := NewStaticService(mockRepo, mockStore, testStaticURIPrefix, 24*time.Hour, 100, nil,
+		NewNSFWInspector(stubNSFWScorer{err: errors.New("scorer unavailable")}, 0.6, 0.9))
+
+	imageData, err := generateValidWEBPImage()
+	assert.NoError(t, err)
+	staticID := uuid.New()
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+	mockRepo.EXPECT().Upload(gomock.Any(), "images", gomock.Any(), gomock.Any(), gomock.Any()).Return(staticID, nil)
+	mockRepo.EXPECT().Flag(gomock.Any(), staticID, "inspection failed: scorer unavailable").Return(nil)
+	mockStore.EXPECT().Stat(gomock.Any(), gomock.Any()).Return(&entity.BlobInfo{}, nil)
+
+	id, err := service.UploadStatic(context.Background(), bytes.NewReader(imageData), entity.UploadMetadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, staticID, id)
+}
+
+func TestStaticService_UploadStatic_Orientation(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	// Снимок 200x100 с ориентацией 6 выглядит как 100x200: вариант с исходными
+	// пропорциями должен сохраниться повернутым и без EXIF
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	imageData := jpegWithExif(t, img, exifWithOrientation(binary.LittleEndian, 6))
+
+	var original []byte
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+	mockRepo.EXPECT().Upload(gomock.Any(), "images", gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.New(), nil)
+	mockStore.EXPECT().Stat(gomock.Any(), gomock.Any()).Return(nil, repository.ErrBlobNotFound)
+	mockStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), "image/webp").
+		DoAndReturn(func(_ context.Context, key string, data io.Reader, _ int64, _ string) error {
+			if original == nil {
+				original, _ = io.ReadAll(data)
+			}
+			return nil
+		}).Times(1 + len(entity.EagerImagePresets))
+
+	_, err := service.UploadStatic(context.Background(), bytes.NewReader(imageData), entity.UploadMetadata{})
+	assert.NoError(t, err)
+
+	config, err := webp.DecodeConfig(bytes.NewReader(original))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, config.Width)
+	assert.Equal(t, 200, config.Height)
+	assert.False(t, bytes.Contains(original, exifHeader))
+}
+
 func TestStaticService_GetAvatar_Success(t *testing.T) {
 	service, mockRepo, ctrl := setupStaticTest(t)
 	defer ctrl.Finish()
 
 	expectedPath := "path/to/avatar.jpg"
 	mockID := uuid.New()
-	mockRepo.EXPECT().Get(mockID).Return(expectedPath, nil)
+	mockRepo.EXPECT().Get(gomock.Any(), mockID).Return(expectedPath, nil)
 
-	path, err := service.GetAvatar(mockID)
+	path, err := service.GetAvatar(context.Background(), mockID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedPath, path)
@@ -114,8 +420,193 @@ func TestStaticService_GetStaticFile_FileNotFound(t *testing.T) {
 	file, _ := os.Create(expectedPath)
 	defer file.Close()
 
-	reader, err := service.GetStaticFile(mockPath)
+	reader, err := service.GetStaticFile(context.Background(), mockPath)
 
 	assert.Error(t, err)
 	assert.Nil(t, reader)
 }
+
+func TestStaticService_GetStaticFile_FromBlobStore(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().IsFlagged(gomock.Any(), "avatar").Return(false, nil)
+	mockStore.EXPECT().Get(gomock.Any(), "images/avatar.webp").Return(nopReadSeekCloser{bytes.NewReader([]byte("content"))}, nil)
+
+	reader, err := service.GetStaticFile(context.Background(), testStaticURIPrefix+"images/avatar.webp")
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	mockRepo.EXPECT().IsFlagged(gomock.Any(), "missing").Return(false, nil)
+	mockStore.EXPECT().Get(gomock.Any(), "images/missing.webp").Return(nil, repository.ErrBlobNotFound)
+
+	reader, err = service.GetStaticFile(context.Background(), testStaticURIPrefix+"images/missing.webp")
+	assert.ErrorIs(t, err, usecase.ErrStaticNotFound)
+	assert.Nil(t, reader)
+}
+
+func TestStaticService_GetStaticFile_Flagged(t *testing.T) {
+	service, mockRepo, _, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	// файл, копия и варианты помеченной статики не отдаются и по прямому пути
+	for _, key := range []string{"images/abc.webp", "originals/abc.webp", "variants/abc/100x100-cover.webp"} {
+		mockRepo.EXPECT().IsFlagged(gomock.Any(), "abc").Return(true, nil).Times(2)
+
+		reader, err := service.GetStaticFile(context.Background(), testStaticURIPrefix+key)
+		assert.ErrorIs(t, err, usecase.ErrStaticNotFound)
+		assert.Nil(t, reader)
+
+		_, err = service.GetStaticFileInfo(context.Background(), testStaticURIPrefix+key)
+		assert.ErrorIs(t, err, usecase.ErrStaticNotFound)
+	}
+
+	mockRepo.EXPECT().IsFlagged(gomock.Any(), "abc").Return(false, entity.PSQLWrap(errors.New("db down")))
+	_, err := service.GetStaticFile(context.Background(), testStaticURIPrefix+"images/abc.webp")
+	assert.ErrorIs(t, err, entity.ErrPSQL)
+}
+
+func TestStaticService_GetStaticVariant_Cached(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	staticID := uuid.New()
+	variant := entity.ImageVariant{Width: 150, Height: 150}
+	mockRepo.EXPECT().Get(gomock.Any(), staticID).Return(testStaticURIPrefix+"images/abc.webp", nil)
+	mockStore.EXPECT().Get(gomock.Any(), "variants/abc/200x200-cover.webp").Return(nopReadSeekCloser{bytes.NewReader([]byte("cached"))}, nil)
+
+	reader, err := service.GetStaticVariant(context.Background(), staticID, variant)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", string(content))
+}
+
+func TestStaticService_GetStaticVariant_Generate(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	imageData, err := generateValidWEBPImage()
+	assert.NoError(t, err)
+
+	staticID := uuid.New()
+	variant := entity.ImageVariant{Width: 64, Fit: entity.ImageFitContain, Format: entity.ImageFormatJPEG}
+	key := "variants/photo/64x0-contain.jpg"
+
+	gomock.InOrder(
+		mockRepo.EXPECT().Get(gomock.Any(), staticID).Return(testStaticURIPrefix+"images/photo.webp", nil),
+		mockStore.EXPECT().Get(gomock.Any(), key).Return(nil, repository.ErrBlobNotFound),
+		mockStore.EXPECT().Get(gomock.Any(), "originals/photo.webp").Return(nil, repository.ErrBlobNotFound),
+		mockStore.EXPECT().Get(gomock.Any(), "images/photo.webp").Return(nopReadSeekCloser{bytes.NewReader(imageData)}, nil),
+		mockStore.EXPECT().Put(gomock.Any(), key, gomock.Any(), gomock.Any(), "image/jpeg").Return(nil),
+	)
+
+	reader, err := service.GetStaticVariant(context.Background(), staticID, variant)
+	assert.NoError(t, err)
+
+	img, format, err := image.Decode(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, image.Rect(0, 0, 64, 64), img.Bounds())
+}
+
+func TestStaticService_GetStaticVariant_Errors(t *testing.T) {
+	service, mockRepo, _, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	staticID := uuid.New()
+
+	_, err := service.GetStaticVariant(context.Background(), staticID, entity.ImageVariant{Width: 100, Fit: "stretch"})
+	assert.ErrorIs(t, err, usecase.ErrStaticInvalidVariant)
+
+	_, err = service.GetStaticVariant(context.Background(), staticID, entity.ImageVariant{Width: -1})
+	assert.ErrorIs(t, err, usecase.ErrStaticInvalidVariant)
+
+	mockRepo.EXPECT().Get(gomock.Any(), staticID).Return("", repository.ErrStaticNotFound)
+
+	_, err = service.GetStaticVariant(context.Background(), staticID, entity.ImageVariant{Width: 100})
+	assert.ErrorIs(t, err, repository.ErrStaticNotFound)
+}
+
+func TestEncodeImageFormats(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 64, 32))
+
+	for _, format := range entity.ImageFormatsByPreference {
+		data, err := encodeImage(format, src, variantQuality)
+		assert.NoError(t, err, format)
+
+		decoded, name, err := image.Decode(bytes.NewReader(data))
+		assert.NoError(t, err, format)
+		assert.Equal(t, string(format), name)
+		assert.Equal(t, src.Bounds(), decoded.Bounds())
+	}
+}
+
+func TestResizeImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	tests := []struct {
+		name     string
+		variant  entity.ImageVariant
+		expected image.Rectangle
+	}{
+		{"cover crops to frame", entity.ImageVariant{Width: 100, Height: 100, Fit: entity.ImageFitCover}, image.Rect(0, 0, 100, 100)},
+		{"contain keeps aspect", entity.ImageVariant{Width: 100, Height: 100, Fit: entity.ImageFitContain}, image.Rect(0, 0, 100, 50)},
+		{"unbounded height", entity.ImageVariant{Width: 200, Fit: entity.ImageFitContain}, image.Rect(0, 0, 200, 100)},
+		{"no upscale", entity.ImageVariant{Width: 800, Height: 800, Fit: entity.ImageFitContain}, image.Rect(0, 0, 400, 200)},
+		{"cover without upscale", entity.ImageVariant{Width: 800, Height: 400, Fit: entity.ImageFitCover}, image.Rect(0, 0, 400, 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resizeImage(src, tt.variant).Bounds())
+		})
+	}
+}
+
+func TestStaticService_CollectGarbage(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().CollectGarbage(gomock.Any(), gomock.Any(), 100).
+		DoAndReturn(func(_ context.Context, olderThan time.Time, _ int) ([]string, error) {
+			assert.WithinDuration(t, time.Now().Add(-24*time.Hour), olderThan, time.Minute)
+			return []string{"images/abc.webp"}, nil
+		})
+	mockStore.EXPECT().Delete(gomock.Any(), "originals/abc.webp").Return(nil)
+	mockStore.EXPECT().DeletePrefix(gomock.Any(), "variants/abc").Return(nil)
+
+	assert.NoError(t, service.CollectGarbage(context.Background()))
+
+	mockRepo.EXPECT().CollectGarbage(gomock.Any(), gomock.Any(), 100).Return(nil, errors.New("db error"))
+	assert.Error(t, service.CollectGarbage(context.Background()))
+}
+
+func TestStaticService_GetStaticFileInfo(t *testing.T) {
+	service, mockRepo, mockStore, ctrl := setupStaticBlobTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().IsFlagged(gomock.Any(), gomock.Any()).Return(false, nil).Times(2)
+	expected := &entity.BlobInfo{Key: "images/avatar.webp", Size: 7, ETag: "abc"}
+	mockStore.EXPECT().Stat(gomock.Any(), "images/avatar.webp").Return(expected, nil)
+
+	info, err := service.GetStaticFileInfo(context.Background(), testStaticURIPrefix+"images/avatar.webp")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, info)
+
+	mockStore.EXPECT().Stat(gomock.Any(), "images/missing.webp").Return(nil, repository.ErrBlobNotFound)
+
+	_, err = service.GetStaticFileInfo(context.Background(), testStaticURIPrefix+"images/missing.webp")
+	assert.ErrorIs(t, err, usecase.ErrStaticNotFound)
+
+	_, err = service.GetStaticFileInfo(context.Background(), "other/avatar.webp")
+	assert.ErrorIs(t, err, usecase.ErrStaticNotFound)
+}
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type StaticUploadService struct {
+	uploadRepo   repository.StaticUploadRepository
+	staticRepo   repository.StaticRepository
+	staticUC     usecase.StaticUseCase
+	ttl          time.Duration
+	maxChunkSize int64
+	batchSize    int
+}
+
+// NewStaticUploadService создает сервис загрузок по частям. Загрузка, в которую не приходило
+// частей дольше ttl, считается брошенной; за один запрос принимается не больше maxChunkSize байт.
+// Полностью полученный файл обрабатывается staticUC так же, как загруженный за один запрос
+func NewStaticUploadService(uploadRepo repository.StaticUploadRepository,
+	staticRepo repository.StaticRepository,
+	staticUC usecase.StaticUseCase,
+	ttl time.Duration,
+	maxChunkSize int64,
+	batchSize int) *StaticUploadService {
+	return &StaticUploadService{
+		uploadRepo:   uploadRepo,
+		staticRepo:   staticRepo,
+		staticUC:     staticUC,
+		ttl:          ttl,
+		maxChunkSize: maxChunkSize,
+		batchSize:    batchSize,
+	}
+}
+
+func (s *StaticUploadService) CreateUpload(ctx context.Context, ownerID uuid.UUID, metadata entity.UploadMetadata) (*entity.ResumableUpload, error) {
+	rule, ok := uploadRules[metadata.Purpose]
+	if !ok || metadata.Purpose == "" {
+		return nil, usecase.ErrStaticUnknownPurpose
+	}
+	if metadata.Size <= 0 {
+		return nil, usecase.ErrStaticUploadLength
+	}
+	if metadata.Size > rule.limit(s.staticRepo.GetMaxSize()) {
+		return nil, usecase.ErrStaticTooBigFile
+	}
+	if metadata.ContentType != "" && !rule.contentTypes()[metadata.ContentType] {
+		return nil, rule.notAllowedType()
+	}
+
+	upload := &entity.ResumableUpload{
+		ID:        uuid.New(),
+		OwnerID:   ownerID,
+		Metadata:  metadata,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.uploadRepo.Create(ctx, upload); err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error creating static upload"))
+	}
+	return upload, nil
+}
+
+func (s *StaticUploadService) GetUpload(ctx context.Context, uploadID, ownerID uuid.UUID) (*entity.ResumableUpload, error) {
+	upload, err := s.uploadRepo.Get(ctx, uploadID)
+	if err != nil {
+		if errors.Is(err, repository.ErrStaticUploadNotFound) {
+			return nil, usecase.ErrStaticUploadNotFound
+		}
+		return nil, entity.UsecaseWrap(err, errors.New("error getting static upload"))
+	}
+	// Чужая загрузка неотличима от несуществующей
+	if upload.OwnerID != ownerID {
+		return nil, usecase.ErrStaticUploadNotFound
+	}
+	return upload, nil
+}
+
+func (s *StaticUploadService) AppendUpload(ctx context.Context, uploadID, ownerID uuid.UUID, offset int64, data io.Reader) (*entity.ResumableUpload, error) {
+	upload, err := s.GetUpload(ctx, uploadID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return upload, usecase.ErrStaticUploadOffset
+	}
+
+	// Часть читается не больше, чем осталось до конца файла и допускается за один запрос
+	limit := min(upload.Metadata.Size-offset, s.maxChunkSize)
+	chunk, err := io.ReadAll(io.LimitReader(data, limit+1))
+	if err != nil {
+		return nil, entity.UsecaseWrap(err, errors.New("error reading static upload chunk"))
+	}
+	if int64(len(chunk)) > limit {
+		return upload, usecase.ErrStaticTooBigFile
+	}
+	if len(chunk) == 0 {
+		return upload, nil
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	received, err := s.uploadRepo.AppendChunk(ctx, uploadID, offset, chunk, expiresAt)
+	switch {
+	case errors.Is(err, repository.ErrStaticUploadNotFound):
+		return nil, usecase.ErrStaticUploadNotFound
+	case errors.Is(err, repository.ErrStaticUploadOffset):
+		// Другой запрос успел дописать эту часть раньше
+		upload.Offset = received
+		return upload, usecase.ErrStaticUploadOffset
+	case errors.Is(err, repository.ErrStaticUploadOverflow):
+		return upload, usecase.ErrStaticTooBigFile
+	case err != nil:
+		return nil, entity.UsecaseWrap(err, errors.New("error appending static upload chunk"))
+	}
+
+	upload.Offset, upload.ExpiresAt = received, expiresAt
+	return upload, nil
+}
+
+func (s *StaticUploadService) FinishUpload(ctx context.Context, uploadID, ownerID uuid.UUID, purpose entity.UploadPurpose) (uuid.UUID, error) {
+	logger := middleware.GetLogger(ctx)
+
+	upload, err := s.GetUpload(ctx, uploadID, ownerID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	// Ограничения проверялись по назначению загрузки, поэтому использовать ее для другого нельзя
+	if upload.Metadata.Purpose != purpose {
+		return uuid.Nil, usecase.ErrStaticUploadPurpose
+	}
+	if !upload.Complete() {
+		return uuid.Nil, usecase.ErrStaticUploadIncomplete
+	}
+
+	content, err := s.uploadRepo.Open(ctx, uploadID)
+	if err != nil {
+		return uuid.Nil, entity.UsecaseWrap(err, errors.New("error opening static upload"))
+	}
+	defer content.Close()
+
+	staticID, err := s.staticUC.UploadStatic(ctx, content, upload.Metadata)
+	// Файл, не прошедший проверки, не исправится повторной попыткой, а после
+	// временной ошибки клиент может повторить завершение
+	if err != nil && !isRejectedUpload(err) {
+		return uuid.Nil, err
+	}
+	if deleteErr := s.uploadRepo.Delete(ctx, uploadID); deleteErr != nil {
+		logger.Warn("failed to delete finished static upload", zap.String("upload_id", uploadID.String()), zap.Error(deleteErr))
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	logger.Info("static upload finished", zap.String("upload_id", uploadID.String()), zap.String("static_id", staticID.String()))
+	return staticID, nil
+}
+
+// isRejectedUpload сообщает, что файл отклонен из-за содержимого
+func isRejectedUpload(err error) bool {
+	return errors.Is(err, usecase.ErrStaticTooBigFile) ||
+		errors.Is(err, usecase.ErrStaticNotImage) ||
+		errors.Is(err, usecase.ErrStaticImageDimensions) ||
+		errors.Is(err, usecase.ErrStaticImageRejected) ||
+		errors.Is(err, usecase.ErrStaticNotVideo) ||
+		errors.Is(err, usecase.ErrStaticVideoDuration)
+}
+
+func (s *StaticUploadService) CancelUpload(ctx context.Context, uploadID, ownerID uuid.UUID) error {
+	if _, err := s.GetUpload(ctx, uploadID, ownerID); err != nil {
+		return err
+	}
+	if err := s.uploadRepo.Delete(ctx, uploadID); err != nil {
+		return entity.UsecaseWrap(err, errors.New("error deleting static upload"))
+	}
+	return nil
+}
+
+func (s *StaticUploadService) ExpireUploads(ctx context.Context) error {
+	logger := middleware.GetLogger(ctx)
+
+	deleted, err := s.uploadRepo.DeleteExpired(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		return entity.UsecaseWrap(err, errors.New("error deleting expired static uploads"))
+	}
+
+	logger.Info("expired static uploads deleted", zap.Int("deleted", deleted))
+	return nil
+}
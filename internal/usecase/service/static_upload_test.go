@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	ucMocks "github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase/mocks"
+)
+
+const testMaxChunkSize = 8
+
+type staticUploadTest struct {
+	service    *StaticUploadService
+	uploadRepo *mocks.MockStaticUploadRepository
+	staticRepo *mocks.MockStaticRepository
+	staticUC   *ucMocks.MockStaticUseCase
+}
+
+func setupStaticUploadTest(t *testing.T) staticUploadTest {
+	ctrl := gomock.NewController(t)
+	test := staticUploadTest{
+		uploadRepo: mocks.NewMockStaticUploadRepository(ctrl),
+		staticRepo: mocks.NewMockStaticRepository(ctrl),
+		staticUC:   ucMocks.NewMockStaticUseCase(ctrl),
+	}
+	test.service = NewStaticUploadService(test.uploadRepo, test.staticRepo, test.staticUC, time.Hour, testMaxChunkSize, 100)
+	return test
+}
+
+func testUpload(ownerID uuid.UUID, size, offset int64) *entity.ResumableUpload {
+	return &entity.ResumableUpload{
+		ID:        uuid.New(),
+		OwnerID:   ownerID,
+		Metadata:  entity.UploadMetadata{Purpose: entity.UploadPurposeAdvert, ContentType: "image/png", Size: size},
+		Offset:    offset,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestStaticUploadService_CreateUpload(t *testing.T) {
+	ownerID := uuid.New()
+	tests := []struct {
+		name     string
+		metadata entity.UploadMetadata
+		err      error
+	}{
+		{"NoPurpose", entity.UploadMetadata{Size: 10}, usecase.ErrStaticUnknownPurpose},
+		{"UnknownPurpose", entity.UploadMetadata{Purpose: "banner", Size: 10}, usecase.ErrStaticUnknownPurpose},
+		{"NoLength", entity.UploadMetadata{Purpose: entity.UploadPurposeAdvert}, usecase.ErrStaticUploadLength},
+		{"TooBig", entity.UploadMetadata{Purpose: entity.UploadPurposeAvatar, Size: 6 << 20}, usecase.ErrStaticTooBigFile},
+		{"NotImage", entity.UploadMetadata{Purpose: entity.UploadPurposeAdvert, Size: 10, ContentType: "video/mp4"}, usecase.ErrStaticNotImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupStaticUploadTest(t)
+			test.staticRepo.EXPECT().GetMaxSize().Return(10 << 20).AnyTimes()
+
+			upload, err := test.service.CreateUpload(context.Background(), ownerID, tt.metadata)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Nil(t, upload)
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		metadata := entity.UploadMetadata{Purpose: entity.UploadPurposeAvatar, ContentType: "image/png", Size: 1 << 20}
+		test.staticRepo.EXPECT().GetMaxSize().Return(10 << 20)
+		test.uploadRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, upload *entity.ResumableUpload) error {
+			assert.Equal(t, ownerID, upload.OwnerID)
+			assert.Equal(t, metadata, upload.Metadata)
+			assert.Zero(t, upload.Offset)
+			return nil
+		})
+
+		upload, err := test.service.CreateUpload(context.Background(), ownerID, metadata)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, upload.ID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), upload.ExpiresAt, time.Minute)
+	})
+}
+
+func TestStaticUploadService_GetUpload_OtherOwner(t *testing.T) {
+	test := setupStaticUploadTest(t)
+	upload := testUpload(uuid.New(), 10, 0)
+	test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+
+	_, err := test.service.GetUpload(context.Background(), upload.ID, uuid.New())
+	assert.ErrorIs(t, err, usecase.ErrStaticUploadNotFound)
+}
+
+func TestStaticUploadService_AppendUpload(t *testing.T) {
+	ownerID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 4)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+		test.uploadRepo.EXPECT().AppendChunk(gomock.Any(), upload.ID, int64(4), []byte("chunk"), gomock.Any()).Return(int64(9), nil)
+
+		result, err := test.service.AppendUpload(context.Background(), upload.ID, ownerID, 4, strings.NewReader("chunk"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), result.Offset)
+	})
+
+	t.Run("OffsetMismatch", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 4)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+
+		result, err := test.service.AppendUpload(context.Background(), upload.ID, ownerID, 0, strings.NewReader("chunk"))
+		assert.ErrorIs(t, err, usecase.ErrStaticUploadOffset)
+		assert.Equal(t, int64(4), result.Offset)
+	})
+
+	t.Run("ConcurrentAppend", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 4)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+		test.uploadRepo.EXPECT().AppendChunk(gomock.Any(), upload.ID, int64(4), gomock.Any(), gomock.Any()).
+			Return(int64(8), entity.PSQLWrap(repository.ErrStaticUploadOffset))
+
+		result, err := test.service.AppendUpload(context.Background(), upload.ID, ownerID, 4, strings.NewReader("chunk"))
+		assert.ErrorIs(t, err, usecase.ErrStaticUploadOffset)
+		assert.Equal(t, int64(8), result.Offset)
+	})
+
+	t.Run("ChunkTooBig", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 100, 0)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+
+		_, err := test.service.AppendUpload(context.Background(), upload.ID, ownerID, 0, strings.NewReader("more than eight bytes"))
+		assert.ErrorIs(t, err, usecase.ErrStaticTooBigFile)
+	})
+
+	t.Run("PastLength", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 8)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+
+		_, err := test.service.AppendUpload(context.Background(), upload.ID, ownerID, 8, strings.NewReader("abc"))
+		assert.ErrorIs(t, err, usecase.ErrStaticTooBigFile)
+	})
+}
+
+func TestStaticUploadService_FinishUpload(t *testing.T) {
+	ownerID := uuid.New()
+
+	t.Run("Incomplete", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 4)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+
+		_, err := test.service.FinishUpload(context.Background(), upload.ID, ownerID, entity.UploadPurposeAdvert)
+		assert.ErrorIs(t, err, usecase.ErrStaticUploadIncomplete)
+	})
+
+	t.Run("PurposeMismatch", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 10)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+
+		_, err := test.service.FinishUpload(context.Background(), upload.ID, ownerID, entity.UploadPurposeAvatar)
+		assert.ErrorIs(t, err, usecase.ErrStaticUploadPurpose)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 10)
+		staticID := uuid.New()
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+		test.uploadRepo.EXPECT().Open(gomock.Any(), upload.ID).Return(io.NopCloser(strings.NewReader("0123456789")), nil)
+		test.staticUC.EXPECT().UploadStatic(gomock.Any(), gomock.Any(), upload.Metadata).Return(staticID, nil)
+		test.uploadRepo.EXPECT().Delete(gomock.Any(), upload.ID).Return(nil)
+
+		id, err := test.service.FinishUpload(context.Background(), upload.ID, ownerID, entity.UploadPurposeAdvert)
+		assert.NoError(t, err)
+		assert.Equal(t, staticID, id)
+	})
+
+	t.Run("RejectedFileIsDeleted", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 10)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+		test.uploadRepo.EXPECT().Open(gomock.Any(), upload.ID).Return(io.NopCloser(strings.NewReader("0123456789")), nil)
+		test.staticUC.EXPECT().UploadStatic(gomock.Any(), gomock.Any(), upload.Metadata).Return(uuid.Nil, usecase.ErrStaticNotImage)
+		test.uploadRepo.EXPECT().Delete(gomock.Any(), upload.ID).Return(nil)
+
+		_, err := test.service.FinishUpload(context.Background(), upload.ID, ownerID, entity.UploadPurposeAdvert)
+		assert.ErrorIs(t, err, usecase.ErrStaticNotImage)
+	})
+
+	t.Run("TemporaryErrorKeepsUpload", func(t *testing.T) {
+		test := setupStaticUploadTest(t)
+		upload := testUpload(ownerID, 10, 10)
+		test.uploadRepo.EXPECT().Get(gomock.Any(), upload.ID).Return(upload, nil)
+		test.uploadRepo.EXPECT().Open(gomock.Any(), upload.ID).Return(io.NopCloser(strings.NewReader("0123456789")), nil)
+		test.staticUC.EXPECT().UploadStatic(gomock.Any(), gomock.Any(), upload.Metadata).Return(uuid.Nil, errors.New("db is down"))
+
+		_, err := test.service.FinishUpload(context.Background(), upload.ID, ownerID, entity.UploadPurposeAdvert)
+		assert.Error(t, err)
+	})
+}
+
+func TestStaticUploadService_ExpireUploads(t *testing.T) {
+	test := setupStaticUploadTest(t)
+	test.uploadRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), 100).Return(3, nil)
+
+	assert.NoError(t, test.service.ExpireUploads(context.Background()))
+}
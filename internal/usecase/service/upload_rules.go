@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+)
+
+var allowedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+var allowedVideoTypes = map[string]bool{
+	entity.VideoFormatMP4.ContentType():  true,
+	entity.VideoFormatWebM.ContentType(): true,
+}
+
+// uploadRule - ограничения загрузки для одного назначения файла.
+// maxSize = 0 означает общий лимит хранилища статики. Для видео размеры
+// ограничивают кадр, а maxDuration - длительность
+type uploadRule struct {
+	video       bool
+	maxDuration time.Duration
+	maxSize     int64
+	minWidth    int
+	minHeight   int
+	maxWidth    int
+	maxHeight   int
+	maxPixels   int
+}
+
+// uploadRules - ограничения по назначениям. Пустое назначение - для клиентов,
+// которые не передают метаданные загрузки
+var uploadRules = map[entity.UploadPurpose]uploadRule{
+	"": {
+		minWidth: 100, minHeight: 100,
+		maxWidth: 10000, maxHeight: 10000,
+		maxPixels: 40_000_000,
+	},
+	entity.UploadPurposeAvatar: {
+		maxSize:  5 << 20,
+		minWidth: 100, minHeight: 100,
+		maxWidth: 4096, maxHeight: 4096,
+		maxPixels: 16_000_000,
+	},
+	entity.UploadPurposeAdvert: {
+		minWidth: 100, minHeight: 100,
+		maxWidth: 10000, maxHeight: 10000,
+		maxPixels: 40_000_000,
+	},
+	entity.UploadPurposeAdvertVideo: {
+		video: true, maxDuration: time.Minute,
+		minWidth: 160, minHeight: 160,
+		maxWidth: 3840, maxHeight: 3840,
+		maxPixels: 3840 * 2160,
+	},
+}
+
+// contentTypes возвращает MIME-типы, которые принимаются по правилу
+func (r uploadRule) contentTypes() map[string]bool {
+	if r.video {
+		return allowedVideoTypes
+	}
+	return allowedImageTypes
+}
+
+// notAllowedType возвращает ошибку для файла неподходящего типа
+func (r uploadRule) notAllowedType() error {
+	if r.video {
+		return usecase.ErrStaticNotVideo
+	}
+	return usecase.ErrStaticNotImage
+}
+
+func (r uploadRule) checkDuration(duration time.Duration) error {
+	if duration <= 0 || duration > r.maxDuration {
+		return entity.UsecaseWrap(
+			usecase.ErrStaticVideoDuration,
+			fmt.Errorf("video duration is %s, but must be at most %s", duration, r.maxDuration),
+		)
+	}
+	return nil
+}
+
+// limit возвращает максимальный размер файла по правилу с учетом общего лимита хранилища storageLimit
+func (r uploadRule) limit(storageLimit int) int64 {
+	limit := int64(storageLimit)
+	if r.maxSize > 0 && r.maxSize < limit {
+		limit = r.maxSize
+	}
+	return limit
+}
+
+func (r uploadRule) checkDimensions(width, height int) error {
+	if width < r.minWidth || height < r.minHeight {
+		return entity.UsecaseWrap(
+			usecase.ErrStaticImageDimensions,
+			fmt.Errorf("image dimensions are %dx%d, but must be at least %dx%d", width, height, r.minWidth, r.minHeight),
+		)
+	}
+	if width > r.maxWidth || height > r.maxHeight || width*height > r.maxPixels {
+		return entity.UsecaseWrap(
+			usecase.ErrStaticImageDimensions,
+			fmt.Errorf("image dimensions are %dx%d, but must be at most %dx%d and %d pixels",
+				width, height, r.maxWidth, r.maxHeight, r.maxPixels),
+		)
+	}
+	return nil
+}
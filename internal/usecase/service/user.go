@@ -41,7 +41,7 @@ func (u *UserService) handleRepoError(err error) error {
 	return nil
 }
 
-func (u *UserService) Signup(signupInfo *dto.Signup) (uuid.UUID, error) {
+func (u *UserService) Signup(ctx context.Context, signupInfo *dto.Signup) (uuid.UUID, error) {
 	if err := entity.ValidateEmail(signupInfo.Email); err != nil {
 		return uuid.Nil, usecase.UserIncorrectDataError{Err: err}
 	}
@@ -54,8 +54,7 @@ func (u *UserService) Signup(signupInfo *dto.Signup) (uuid.UUID, error) {
 		return uuid.Nil, entity.UsecaseWrap(errors.New("error hashing password"), err)
 	}
 
-	ctx := context.Background()
-	tx, err := u.userRepo.BeginTransaction()
+	tx, err := u.userRepo.BeginTransaction(ctx)
 	if err != nil {
 		logger := middleware.GetLogger(ctx)
 		logger.Error("failed to begin transaction", zap.Error(err))
@@ -69,13 +68,13 @@ func (u *UserService) Signup(signupInfo *dto.Signup) (uuid.UUID, error) {
 		}
 	}()
 
-	userID, err := u.userRepo.Add(tx, signupInfo.Email, hash, salt)
+	userID, err := u.userRepo.Add(ctx, tx, signupInfo.Email, hash, salt)
 	if err != nil {
 		err = u.handleRepoError(err)
 		return uuid.Nil, err
 	}
 
-	_, err = u.sellerRepo.Add(tx, userID)
+	_, err = u.sellerRepo.Add(ctx, tx, userID)
 	if err != nil {
 		err = u.handleRepoError(err)
 		return uuid.Nil, err
@@ -84,7 +83,7 @@ func (u *UserService) Signup(signupInfo *dto.Signup) (uuid.UUID, error) {
 	return userID, nil
 }
 
-func (u *UserService) Login(loginInfo *dto.Login) (uuid.UUID, error) {
+func (u *UserService) Login(ctx context.Context, loginInfo *dto.Login) (uuid.UUID, error) {
 	if err := entity.ValidateEmail(loginInfo.Email); err != nil {
 		return uuid.Nil, usecase.UserIncorrectDataError{Err: err}
 	}
@@ -92,7 +91,7 @@ func (u *UserService) Login(loginInfo *dto.Login) (uuid.UUID, error) {
 		return uuid.Nil, usecase.UserIncorrectDataError{Err: err}
 	}
 
-	user, err := u.userRepo.GetByEmail(loginInfo.Email)
+	user, err := u.userRepo.GetByEmail(ctx, loginInfo.Email)
 	if err != nil {
 		return uuid.Nil, u.handleRepoError(err)
 	}
@@ -104,7 +103,7 @@ func (u *UserService) Login(loginInfo *dto.Login) (uuid.UUID, error) {
 	return user.ID, nil
 }
 
-func (u *UserService) UpdateInfo(user *dto.UserUpdate) error {
+func (u *UserService) UpdateInfo(ctx context.Context, user *dto.UserUpdate) error {
 	entityUser := &entity.User{
 		ID:       user.ID,
 		Email:    user.Email,
@@ -112,23 +111,23 @@ func (u *UserService) UpdateInfo(user *dto.UserUpdate) error {
 		Phone:    user.Phone,
 	}
 
-	err := u.userRepo.Update(entityUser)
+	err := u.userRepo.Update(ctx, entityUser)
 	if err != nil {
 		return u.handleRepoError(err)
 	}
 	return nil
 }
 
-func (u *UserService) Delete(userID uuid.UUID) error {
-	err := u.userRepo.Delete(userID)
+func (u *UserService) Delete(ctx context.Context, userID uuid.UUID) error {
+	err := u.userRepo.Delete(ctx, userID)
 	if err != nil {
 		return u.handleRepoError(err)
 	}
 	return nil
 }
 
-func (u *UserService) Get(userID uuid.UUID) (*dto.User, error) {
-	entityUser, err := u.userRepo.GetById(userID)
+func (u *UserService) Get(ctx context.Context, userID uuid.UUID) (*dto.User, error) {
+	entityUser, err := u.userRepo.GetById(ctx, userID)
 	if err != nil {
 		return nil, u.handleRepoError(err)
 	}
@@ -144,11 +143,11 @@ func (u *UserService) Get(userID uuid.UUID) (*dto.User, error) {
 	}, nil
 }
 
-func (u *UserService) ChangePassword(userID uuid.UUID, password *dto.UpdatePassword) error {
+func (u *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, password *dto.UpdatePassword) error {
 	if err := entity.ValidatePassword(password.NewPassword); err != nil {
 		return usecase.UserIncorrectDataError{Err: err}
 	}
-	user, err := u.userRepo.GetById(userID)
+	user, err := u.userRepo.GetById(ctx, userID)
 	switch {
 	case err != nil:
 		return u.handleRepoError(err)
@@ -169,15 +168,15 @@ func (u *UserService) ChangePassword(userID uuid.UUID, password *dto.UpdatePassw
 		PasswordSalt: salt,
 	}
 
-	err = u.userRepo.Update(entityUser)
+	err = u.userRepo.Update(ctx, entityUser)
 	if err != nil {
 		return u.handleRepoError(err)
 	}
 	return nil
 }
 
-func (u *UserService) UploadImage(userID uuid.UUID, imageId uuid.UUID) error {
-	if err := u.userRepo.UploadImage(userID, imageId); err != nil {
+func (u *UserService) UploadImage(ctx context.Context, userID uuid.UUID, imageId uuid.UUID) error {
+	if err := u.userRepo.UploadImage(ctx, userID, imageId); err != nil {
 		return entity.UsecaseWrap(ErrAdvertBadRequest, ErrAdvertBadRequest)
 	}
 
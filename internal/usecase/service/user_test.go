@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -54,7 +55,7 @@ func TestUserService_Signup_EmailValidationError(t *testing.T) {
 		Password: "SecureP@ssw0rd",
 	}
 
-	result, err := service.Signup(signupInfo)
+	result, err := service.Signup(context.Background(), signupInfo)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid email")
@@ -70,7 +71,7 @@ func TestUserService_Signup_PasswordValidationError(t *testing.T) {
 		Password: "short",
 	}
 
-	result, err := service.Signup(signupInfo)
+	result, err := service.Signup(context.Background(), signupInfo)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "password must contain at least 8 characters")
@@ -90,11 +91,11 @@ func TestUserService_Login_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 	mockUserRepo.EXPECT().
-		GetByEmail(loginInfo.Email).
+		GetByEmail(gomock.Any(), loginInfo.Email).
 		Return(user, nil).
 		Times(1)
 
-	result, err := service.Login(loginInfo)
+	result, err := service.Login(context.Background(), loginInfo)
 
 	assert.NoError(t, err)
 	assert.Equal(t, user.ID, result)
@@ -113,11 +114,11 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 	assert.NoError(t, err)
 
 	mockUserRepo.EXPECT().
-		GetByEmail(loginInfo.Email).
+		GetByEmail(gomock.Any(), loginInfo.Email).
 		Return(user, nil).
 		Times(1)
 
-	result, err := service.Login(loginInfo)
+	result, err := service.Login(context.Background(), loginInfo)
 
 	assert.Error(t, err)
 	assert.Equal(t, uuid.Nil, result)
@@ -134,11 +135,11 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		GetByEmail(loginInfo.Email).
+		GetByEmail(gomock.Any(), loginInfo.Email).
 		Return(nil, repository.ErrUserNotFound).
 		Times(1)
 
-	result, err := service.Login(loginInfo)
+	result, err := service.Login(context.Background(), loginInfo)
 
 	assert.Error(t, err)
 	assert.Equal(t, uuid.Nil, result)
@@ -162,11 +163,11 @@ func TestUserService_GetUser_Success(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		GetById(userID).
+		GetById(gomock.Any(), userID).
 		Return(user, nil).
 		Times(1)
 
-	result, err := service.Get(userID)
+	result, err := service.Get(context.Background(), userID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -185,11 +186,11 @@ func TestUserService_GetUser_UserNotFound(t *testing.T) {
 	userID := uuid.New()
 
 	mockUserRepo.EXPECT().
-		GetById(userID).
+		GetById(gomock.Any(), userID).
 		Return(nil, repository.ErrUserNotFound).
 		Times(1)
 
-	result, err := service.Get(userID)
+	result, err := service.Get(context.Background(), userID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -208,8 +209,8 @@ func TestUserService_UpdateInfo_Success(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		Update(gomock.Any()).
-		DoAndReturn(func(user *entity.User) error {
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, user *entity.User) error {
 			assert.Equal(t, userUpdateDTO.ID, user.ID)
 			assert.Equal(t, userUpdateDTO.Email, user.Email)
 			assert.Equal(t, userUpdateDTO.Username, user.Username)
@@ -218,7 +219,7 @@ func TestUserService_UpdateInfo_Success(t *testing.T) {
 		}).
 		Times(1)
 
-	err := service.UpdateInfo(userUpdateDTO)
+	err := service.UpdateInfo(context.Background(), userUpdateDTO)
 
 	assert.NoError(t, err)
 }
@@ -230,11 +231,11 @@ func TestUserService_DeleteUser_Success(t *testing.T) {
 	userID := uuid.New()
 
 	mockUserRepo.EXPECT().
-		Delete(userID).
+		Delete(gomock.Any(), userID).
 		Return(nil).
 		Times(1)
 
-	err := service.Delete(userID)
+	err := service.Delete(context.Background(), userID)
 
 	assert.NoError(t, err)
 }
@@ -246,11 +247,11 @@ func TestUserService_DeleteUser_Error(t *testing.T) {
 	userID := uuid.New()
 
 	mockUserRepo.EXPECT().
-		Delete(userID).
+		Delete(gomock.Any(), userID).
 		Return(errors.New("delete error")).
 		Times(1)
 
-	err := service.Delete(userID)
+	err := service.Delete(context.Background(), userID)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "repository error")
@@ -274,16 +275,16 @@ func TestUserService_ChangePassword_Success(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		GetById(userID).
+		GetById(gomock.Any(), userID).
 		Return(user, nil).
 		Times(1)
 
 	mockUserRepo.EXPECT().
-		Update(gomock.Any()).
+		Update(gomock.Any(), gomock.Any()).
 		Return(nil).
 		Times(1)
 
-	err = service.ChangePassword(userID, updatePassword)
+	err = service.ChangePassword(context.Background(), userID, updatePassword)
 
 	assert.NoError(t, err)
 }
@@ -307,11 +308,11 @@ func TestUserService_ChangePassword_InvalidOldPassword(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		GetById(userID).
+		GetById(gomock.Any(), userID).
 		Return(user, nil).
 		Times(1)
 
-	err = service.ChangePassword(userID, updatePassword)
+	err = service.ChangePassword(context.Background(), userID, updatePassword)
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, usecase.ErrInvalidCredentials))
@@ -331,11 +332,11 @@ func TestUserService_ChangePassword_GetUserError(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		GetById(userID).
+		GetById(gomock.Any(), userID).
 		Return(nil, repository.ErrUserNotFound).
 		Times(1)
 
-	err := service.ChangePassword(userID, updatePassword)
+	err := service.ChangePassword(context.Background(), userID, updatePassword)
 
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, usecase.ErrUserNotFound))
@@ -359,16 +360,16 @@ func TestUserService_ChangePassword_UpdateUserError(t *testing.T) {
 	}
 
 	mockUserRepo.EXPECT().
-		GetById(userID).
+		GetById(gomock.Any(), userID).
 		Return(user, nil).
 		Times(1)
 
 	mockUserRepo.EXPECT().
-		Update(gomock.Any()).
+		Update(gomock.Any(), gomock.Any()).
 		Return(errors.New("update error")).
 		Times(1)
 
-	err = service.ChangePassword(userID, updatePassword)
+	err = service.ChangePassword(context.Background(), userID, updatePassword)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "repository error")
@@ -382,11 +383,11 @@ func TestUserService_UploadImage_Success(t *testing.T) {
 	imageID := uuid.New()
 
 	mockUserRepo.EXPECT().
-		UploadImage(userID, imageID).
+		UploadImage(gomock.Any(), userID, imageID).
 		Return(nil).
 		Times(1)
 
-	err := service.UploadImage(userID, imageID)
+	err := service.UploadImage(context.Background(), userID, imageID)
 
 	assert.NoError(t, err)
 }
@@ -396,11 +397,11 @@ func TestUserService_UploadImage_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo.EXPECT().
-		UploadImage(gomock.Any(), gomock.Any()).
+		UploadImage(gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(errors.New("upload error")).
 		Times(1)
 
-	err := service.UploadImage(uuid.New(), uuid.New())
+	err := service.UploadImage(context.Background(), uuid.New(), uuid.New())
 
 	assert.Error(t, err)
 }
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/delivery/http/middleware"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// posterOffset - момент, из которого берется кадр-обложка: первый кадр часто черный
+const posterOffset = time.Second
+
+// uploadVideo проверяет длительность и размеры кадра видео, сохраняет файл как есть и готовит
+// из кадра-обложки варианты изображения. Обложка проходит ту же модерацию, что и изображения
+func (s *StaticService) uploadVideo(ctx context.Context, upload *spooledUpload, format entity.VideoFormat, rule uploadRule) (uuid.UUID, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if s.video == nil {
+		return uuid.Nil, usecase.ErrStaticVideoDisabled
+	}
+
+	info, err := s.video.Probe(ctx, upload.file.Name())
+	if err != nil {
+		return uuid.Nil, entity.UsecaseWrap(err, usecase.ErrStaticNotVideo)
+	}
+	if err := rule.checkDuration(info.Duration); err != nil {
+		return uuid.Nil, err
+	}
+	if err := rule.checkDimensions(info.Width, info.Height); err != nil {
+		return uuid.Nil, err
+	}
+
+	poster, err := s.video.Frame(ctx, upload.file.Name(), min(posterOffset, info.Duration/2))
+	if err != nil {
+		return uuid.Nil, entity.UsecaseWrap(err, usecase.ErrStaticNotVideo)
+	}
+
+	inspection := s.inspectImage(ctx, poster)
+	if inspection.Verdict == entity.ImageVerdictReject {
+		logger.Info("static video rejected", zap.String("reason", inspection.Reason))
+		return uuid.Nil, usecase.ErrStaticImageRejected
+	}
+
+	// Видео сохраняется как есть, поэтому его имя - хэш, посчитанный при загрузке
+	name := upload.hash + "." + string(format)
+	id, err := s.staticRepo.Upload(ctx, "videos", name, upload.reader(), upload.size)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.flagForModeration(ctx, id, inspection)
+	s.prepareVariants(ctx, name, poster)
+
+	logger.Info("static video uploaded", zap.String("static_id", id.String()),
+		zap.Duration("duration", info.Duration), zap.Int("width", info.Width), zap.Int("height", info.Height))
+	return id, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/pkg/errors"
+)
+
+// FFmpegVideoProcessor читает сведения о видео через ffprobe и извлекает кадры через ffmpeg
+type FFmpegVideoProcessor struct {
+	ffmpegPath  string
+	ffprobePath string
+	timeout     time.Duration
+}
+
+// NewFFmpegVideoProcessor создает обработчик видео. Каждый запуск ffmpeg или ffprobe
+// прерывается, если длится дольше timeout
+func NewFFmpegVideoProcessor(ffmpegPath, ffprobePath string, timeout time.Duration) *FFmpegVideoProcessor {
+	return &FFmpegVideoProcessor{
+		ffmpegPath:  ffmpegPath,
+		ffprobePath: ffprobePath,
+		timeout:     timeout,
+	}
+}
+
+func (p *FFmpegVideoProcessor) Probe(ctx context.Context, path string) (entity.VideoInfo, error) {
+	output, err := p.run(ctx, p.ffprobePath,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:stream_side_data=rotation:format=duration",
+		"-of", "json",
+		path)
+	if err != nil {
+		return entity.VideoInfo{}, err
+	}
+	return parseProbeOutput(output)
+}
+
+type probeOutput struct {
+	Streams []struct {
+		Width        int `json:"width"`
+		Height       int `json:"height"`
+		SideDataList []struct {
+			Rotation int `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+	Format struct {
+		Duration string `json:"duration"`
+	} `json:"format"`
+}
+
+// parseProbeOutput разбирает ответ ffprobe. Кадры снятого вертикально видео хранятся повернутыми,
+// а ffmpeg поворачивает их при декодировании, поэтому размеры возвращаются с учетом поворота
+func parseProbeOutput(output []byte) (entity.VideoInfo, error) {
+	var probe probeOutput
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return entity.VideoInfo{}, errors.Wrap(err, "error parsing ffprobe output")
+	}
+	if len(probe.Streams) == 0 {
+		return entity.VideoInfo{}, errors.New("file has no video stream")
+	}
+
+	seconds, err := strconv.ParseFloat(probe.Format.Duration, 64)
+	if err != nil {
+		return entity.VideoInfo{}, errors.Wrap(err, "error parsing video duration")
+	}
+
+	stream := probe.Streams[0]
+	info := entity.VideoInfo{
+		Duration: time.Duration(seconds * float64(time.Second)),
+		Width:    stream.Width,
+		Height:   stream.Height,
+	}
+	for _, sideData := range stream.SideDataList {
+		if sideData.Rotation%180 != 0 {
+			info.Width, info.Height = info.Height, info.Width
+		}
+	}
+	return info, nil
+}
+
+func (p *FFmpegVideoProcessor) Frame(ctx context.Context, path string, at time.Duration) (image.Image, error) {
+	output, err := p.run(ctx, p.ffmpegPath,
+		"-v", "error",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64),
+		"-i", path,
+		"-frames:v", "1",
+		"-f", "image2pipe",
+		"-vcodec", "png",
+		"-")
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(output))
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding video frame")
+	}
+	return img, nil
+}
+
+func (p *FFmpegVideoProcessor) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", name, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
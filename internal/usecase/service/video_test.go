@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/repository/mocks"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/usecase"
+)
+
+// testMP4 - начало файла MP4, по которому определяется тип содержимого
+var testMP4 = append([]byte("\x00\x00\x00\x10ftypmp42\x00\x00\x00\x00"), make([]byte, 64)...)
+
+// stubVideoProcessor подменяет ffmpeg в тестах и запоминает, с какими аргументами его вызвали
+type stubVideoProcessor struct {
+	info     entity.VideoInfo
+	frameAt  time.Duration
+	probeErr error
+}
+
+func (p *stubVideoProcessor) Probe(_ context.Context, path string) (entity.VideoInfo, error) {
+	if _, err := os.Stat(path); err != nil {
+		return entity.VideoInfo{}, err
+	}
+	return p.info, p.probeErr
+}
+
+func (p *stubVideoProcessor) Frame(_ context.Context, _ string, at time.Duration) (image.Image, error) {
+	p.frameAt = at
+	return image.NewRGBA(image.Rect(0, 0, p.info.Width, p.info.Height)), nil
+}
+
+func setupVideoTest(t *testing.T, video usecase.VideoProcessor) (*StaticService, *mocks.MockStaticRepository, *mocks.MockBlobStore) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockStaticRepository(ctrl)
+	mockStore := mocks.NewMockBlobStore(ctrl)
+	return NewStaticService(mockRepo, mockStore, testStaticURIPrefix, 24*time.Hour, 100, video), mockRepo, mockStore
+}
+
+func TestStaticService_UploadStatic_Video(t *testing.T) {
+	video := &stubVideoProcessor{info: entity.VideoInfo{Duration: 10 * time.Second, Width: 1280, Height: 720}}
+	service, mockRepo, mockStore := setupVideoTest(t, video)
+
+	staticID := uuid.New()
+	var name string
+	mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+	mockRepo.EXPECT().Upload(gomock.Any(), "videos", gomock.Any(), gomock.Any(), int64(len(testMP4))).
+		DoAndReturn(func(_ context.Context, _, filename string, data io.Reader, _ int64) (uuid.UUID, error) {
+			name = filename
+			// видео передается из временного файла без перекодирования
+			content, err := io.ReadAll(data)
+			assert.NoError(t, err)
+			assert.Equal(t, testMP4, content)
+			return staticID, nil
+		})
+	// Обложка уже подготовлена при загрузке того же видео
+	mockStore.EXPECT().Stat(gomock.Any(), gomock.Any()).Return(&entity.BlobInfo{}, nil)
+
+	id, err := service.UploadStatic(context.Background(), bytes.NewReader(testMP4), entity.UploadMetadata{
+		Purpose:     entity.UploadPurposeAdvertVideo,
+		ContentType: "video/mp4",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, staticID, id)
+	hash := sha256.Sum256(testMP4)
+	assert.Equal(t, hex.EncodeToString(hash[:])+".mp4", name)
+	assert.Equal(t, posterOffset, video.frameAt)
+}
+
+func TestStaticService_UploadStatic_VideoRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		video    usecase.VideoProcessor
+		data     []byte
+		metadata entity.UploadMetadata
+		err      error
+	}{
+		{
+			name:     "Disabled",
+			data:     testMP4,
+			metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvertVideo},
+			err:      usecase.ErrStaticVideoDisabled,
+		},
+		{
+			name:     "DeclaredImage",
+			video:    &stubVideoProcessor{},
+			data:     testMP4,
+			metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvertVideo, ContentType: "image/png"},
+			err:      usecase.ErrStaticNotVideo,
+		},
+		{
+			name:     "ImageContent",
+			video:    &stubVideoProcessor{},
+			data:     pngHeader(200, 200),
+			metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvertVideo},
+			err:      usecase.ErrStaticNotVideo,
+		},
+		{
+			name:     "VideoAsImage",
+			video:    &stubVideoProcessor{},
+			data:     testMP4,
+			metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvert},
+			err:      usecase.ErrStaticNotImage,
+		},
+		{
+			name:     "TooLong",
+			video:    &stubVideoProcessor{info: entity.VideoInfo{Duration: 2 * time.Minute, Width: 1280, Height: 720}},
+			data:     testMP4,
+			metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvertVideo},
+			err:      usecase.ErrStaticVideoDuration,
+		},
+		{
+			name:     "FrameTooBig",
+			video:    &stubVideoProcessor{info: entity.VideoInfo{Duration: 10 * time.Second, Width: 7680, Height: 4320}},
+			data:     testMP4,
+			metadata: entity.UploadMetadata{Purpose: entity.UploadPurposeAdvertVideo},
+			err:      usecase.ErrStaticImageDimensions,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, _ := setupVideoTest(t, tt.video)
+			mockRepo.EXPECT().GetMaxSize().Return(10 * 1024 * 1024)
+
+			id, err := service.UploadStatic(context.Background(), bytes.NewReader(tt.data), tt.metadata)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, uuid.Nil, id)
+		})
+	}
+}
+
+func TestParseProbeOutput(t *testing.T) {
+	info, err := parseProbeOutput([]byte(`{
+		"streams": [{"width": 1920, "height": 1080, "side_data_list": [{"rotation": -90}]}],
+		"format": {"duration": "12.500000"}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, entity.VideoInfo{Duration: 12500 * time.Millisecond, Width: 1080, Height: 1920}, info)
+
+	_, err = parseProbeOutput([]byte(`{"streams": [], "format": {"duration": "1.0"}}`))
+	assert.Error(t, err)
+	_, err = parseProbeOutput([]byte(`{"streams": [{"width": 640, "height": 480}], "format": {"duration": "N/A"}}`))
+	assert.Error(t, err)
+}
@@ -1,28 +1,74 @@
 package usecase
 
 import (
+	"context"
+	"image"
 	"io"
 	"errors"
+	"time"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
 	"github.com/google/uuid"
 )
 
 type StaticUseCase interface {
 	// GetAvatar возвращает url аватара по id	
-	GetAvatar(staticID uuid.UUID) (string, error)
+	GetAvatar(ctx context.Context, staticID uuid.UUID) (string, error)
 
-	// UploadStatic загружает файл и возвращает id загруженного файла
-	UploadStatic(data io.ReadSeeker) (uuid.UUID, error)
+	// UploadStatic читает файл из потока, проверяя ограничения назначения metadata.Purpose
+	// по мере чтения, и возвращает id загруженного файла
+	UploadStatic(ctx context.Context, data io.Reader, metadata entity.UploadMetadata) (uuid.UUID, error)
 
 	// GetStatic возвращает url статики по id
-	GetStatic(id uuid.UUID) (string, error)
+	GetStatic(ctx context.Context, id uuid.UUID) (string, error)
 
-	// GetStaticFile возвращает файл по uri
-	GetStaticFile(uri string) (io.ReadSeeker, error)
+	// GetStaticFile возвращает файл по uri. Файлы статики, которую модератор еще не одобрил,
+	// вместе с их копиями и вариантами считаются ненайденными
+	GetStaticFile(ctx context.Context, uri string) (io.ReadSeeker, error)
+
+	// GetStaticFileInfo возвращает размер, хэш содержимого и время изменения файла по uri.
+	// Файлы статики, которую модератор еще не одобрил, считаются ненайденными
+	GetStaticFileInfo(ctx context.Context, uri string) (*entity.BlobInfo, error)
+
+	// GetStaticVariant возвращает вариант изображения по id, создавая и кэшируя его при первом запросе
+	GetStaticVariant(ctx context.Context, id uuid.UUID, variant entity.ImageVariant) (io.ReadSeeker, error)
+
+	// CollectGarbage удаляет статику, на которую не ссылаются пользователи и объявления,
+	// и файлы, на которые больше не ссылается ни одна статика
+	CollectGarbage(ctx context.Context) error
+}
+
+// ImageInspector проверяет загружаемое изображение до сохранения: например, сравнивает
+// его с запрещенными изображениями или оценивает откровенность содержимого
+type ImageInspector interface {
+	// Inspect возвращает вердикт по изображению с уже исправленной ориентацией
+	Inspect(ctx context.Context, img image.Image) (entity.ImageInspection, error)
+}
+
+// NSFWScorer оценивает вероятность того, что изображение недопустимо для площадки, числом от 0 до 1
+type NSFWScorer interface {
+	Score(ctx context.Context, img image.Image) (float64, error)
+}
+
+// VideoProcessor читает сведения о видео и извлекает из него кадры. Файлы передаются
+// путем на диске: контейнер MP4 может хранить оглавление в конце файла
+type VideoProcessor interface {
+	// Probe возвращает длительность и размеры видео
+	Probe(ctx context.Context, path string) (entity.VideoInfo, error)
+
+	// Frame возвращает кадр видео в момент at от начала
+	Frame(ctx context.Context, path string, at time.Duration) (image.Image, error)
 }
 
 var ErrStaticFileNotFound = errors.New("static file not found")
 var ErrStaticTooBigFile = errors.New("static file too big")
 var ErrStaticNotImage = errors.New("static file is not image")
 var ErrStaticImageDimensions = errors.New("static image dimensions are invalid")
-var ErrStaticNotFound = errors.New("static not found")
\ No newline at end of file
+var ErrStaticNotFound = errors.New("static not found")
+var ErrStaticInvalidVariant = errors.New("static image variant is invalid")
+var ErrStaticUnsupportedFormat = errors.New("static image format is not supported")
+var ErrStaticUnknownPurpose = errors.New("static upload purpose is unknown")
+var ErrStaticImageRejected = errors.New("static image rejected by moderation")
+var ErrStaticNotVideo = errors.New("static file is not video")
+var ErrStaticVideoDuration = errors.New("static video duration is invalid")
+var ErrStaticVideoDisabled = errors.New("static video uploads are disabled")
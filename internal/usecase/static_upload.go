@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity"
+	"github.com/google/uuid"
+)
+
+// StaticUpload - загрузка файлов частями с возможностью продолжить после обрыва связи.
+// Загрузка доступна только создавшему ее пользователю ownerID
+type StaticUpload interface {
+	// CreateUpload начинает загрузку файла размера metadata.Size, проверяя ограничения назначения
+	CreateUpload(ctx context.Context, ownerID uuid.UUID, metadata entity.UploadMetadata) (*entity.ResumableUpload, error)
+
+	// GetUpload возвращает состояние загрузки, в том числе сколько байт уже получено
+	GetUpload(ctx context.Context, uploadID, ownerID uuid.UUID) (*entity.ResumableUpload, error)
+
+	// AppendUpload дописывает часть data, начинающуюся с offset, и продлевает срок жизни загрузки
+	AppendUpload(ctx context.Context, uploadID, ownerID uuid.UUID, offset int64, data io.Reader) (*entity.ResumableUpload, error)
+
+	// FinishUpload обрабатывает полностью полученный файл как обычную загрузку статики
+	// с назначением purpose и возвращает id статики. Загрузка после этого удаляется
+	FinishUpload(ctx context.Context, uploadID, ownerID uuid.UUID, purpose entity.UploadPurpose) (uuid.UUID, error)
+
+	// CancelUpload удаляет загрузку и полученные части
+	CancelUpload(ctx context.Context, uploadID, ownerID uuid.UUID) error
+
+	// ExpireUploads удаляет брошенные загрузки, срок жизни которых истек
+	ExpireUploads(ctx context.Context) error
+}
+
+var (
+	ErrStaticUploadNotFound   = errors.New("static upload not found")
+	ErrStaticUploadOffset     = errors.New("static upload offset mismatch")
+	ErrStaticUploadIncomplete = errors.New("static upload is incomplete")
+	ErrStaticUploadPurpose    = errors.New("static upload purpose mismatch")
+	ErrStaticUploadLength     = errors.New("static upload length is invalid")
+)
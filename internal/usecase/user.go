@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 
 	"github.com/go-park-mail-ru/2024_2_BogoSort/internal/entity/dto"
@@ -9,17 +10,17 @@ import (
 
 type User interface {
 	// Signup регистрация пользователя
-	Signup(*dto.Signup) (uuid.UUID, error)
+	Signup(ctx context.Context, signup *dto.Signup) (uuid.UUID, error)
 	// Login авторизация пользователя
-	Login(*dto.Login) (uuid.UUID, error)
+	Login(ctx context.Context, login *dto.Login) (uuid.UUID, error)
 	// UpdateInfo обновление данных пользователя
-	UpdateInfo(*dto.UserUpdate) error
+	UpdateInfo(ctx context.Context, user *dto.UserUpdate) error
 	// ChangePassword изменение пароля
-	ChangePassword(userID uuid.UUID, password *dto.UpdatePassword) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, password *dto.UpdatePassword) error
 	// Get получение данных пользователя
-	Get(userID uuid.UUID) (*dto.User, error)
+	Get(ctx context.Context, userID uuid.UUID) (*dto.User, error)
 	// UploadImage обновление аватара пользователя
-	UploadImage(userID uuid.UUID, imageID uuid.UUID) error
+	UploadImage(ctx context.Context, userID uuid.UUID, imageID uuid.UUID) error
 }
 
 type UserIncorrectDataError struct {
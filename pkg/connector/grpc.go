@@ -0,0 +1,213 @@
+package connector
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // регистрирует клиентскую проверку здоровья из healthCheckConfig
+	healthProto "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval - пауза между проверками здоровья сервиса при старте клиента
+const healthCheckInterval = 500 * time.Millisecond
+
+// GetGrpcConnector открывает соединение с сервисом service (полное имя из proto, например
+// auth.AuthService) и ждет, пока он ответит SERVING на стандартную проверку здоровья.
+// Вызовам без дедлайна в контексте назначается cfg.Timeout, вызовы из idempotent
+// повторяются, пока сервис недоступен, каждый вызов попадает в трассу запроса.
+// opts дополняют параметры соединения, например перехватчиками
+func GetGrpcConnector(addr, service string, cfg config.GRPCConfig, idempotent []string,
+	opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	creds, err := grpcClientCredentials(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	serviceConfig, err := grpcServiceConfig(service, cfg, idempotent)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.NewClient(addr, append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(timeoutInterceptor(cfg.Timeout)),
+		tracing.GRPCDialOption(),
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := waitForServing(conn, service, cfg.HealthCheckTimeout); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// GetGrpcServerCredentials возвращает параметры транспорта сервера. При включенном TLS
+// сервер принимает только клиентов с сертификатом, подписанным cfg.CAFile
+func GetGrpcServerCredentials(cfg config.GRPCTLSConfig) (grpc.ServerOption, error) {
+	if !cfg.Enabled {
+		return grpc.Creds(insecure.NewCredentials()), nil
+	}
+
+	tlsConfig, err := loadTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ClientCAs = tlsConfig.RootCAs
+	tlsConfig.RootCAs = nil
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return grpc.Creds(credentials.NewTLS(tlsConfig)), nil
+}
+
+func grpcClientCredentials(cfg config.GRPCTLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := loadTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.ServerName = cfg.ServerName
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// loadTLSConfig читает собственный сертификат сервиса и центр сертификации, которому он доверяет
+func loadTLSConfig(cfg config.GRPCTLSConfig) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load grpc certificate")
+	}
+
+	ca, err := os.ReadFile(cfg.CAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read grpc CA file")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.Errorf("no certificates found in %s", cfg.CAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+type grpcMethodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type grpcRetryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type grpcMethodConfig struct {
+	Name        []grpcMethodName `json:"name"`
+	RetryPolicy *grpcRetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type grpcHealthCheckConfig struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type grpcServiceConfigJSON struct {
+	LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig"`
+	HealthCheckConfig   grpcHealthCheckConfig `json:"healthCheckConfig"`
+	MethodConfig        []grpcMethodConfig    `json:"methodConfig,omitempty"`
+}
+
+// grpcServiceConfig собирает service config: адреса, не прошедшие проверку здоровья, исключаются
+// из балансировки, а идемпотентные вызовы повторяются при UNAVAILABLE. gRPC не допускает
+// повторов при RetryAttempts меньше двух, тогда политика не задается
+func grpcServiceConfig(service string, cfg config.GRPCConfig, idempotent []string) (string, error) {
+	serviceConfig := grpcServiceConfigJSON{
+		LoadBalancingConfig: []map[string]struct{}{{"round_robin": {}}},
+		HealthCheckConfig:   grpcHealthCheckConfig{ServiceName: service},
+	}
+
+	if cfg.RetryAttempts > 1 && len(idempotent) > 0 {
+		methods := make([]grpcMethodName, 0, len(idempotent))
+		for _, method := range idempotent {
+			methods = append(methods, grpcMethodName{Service: service, Method: method})
+		}
+		serviceConfig.MethodConfig = []grpcMethodConfig{{
+			Name: methods,
+			RetryPolicy: &grpcRetryPolicy{
+				MaxAttempts:          cfg.RetryAttempts,
+				InitialBackoff:       durationJSON(cfg.RetryBackoff),
+				MaxBackoff:           durationJSON(cfg.RetryMaxBackoff),
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+
+	data, err := json.Marshal(serviceConfig)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// durationJSON записывает длительность в формате google.protobuf.Duration, например "0.1s"
+func durationJSON(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// timeoutInterceptor назначает вызову дедлайн timeout, если в контексте его еще нет,
+// например когда вызов сделан не из обработчика HTTP-запроса
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// waitForServing проверяет здоровье сервиса, пока он не ответит SERVING или не истечет timeout.
+// Сервис, запущенный позже клиента, не приводит к ошибке старта
+func waitForServing(conn *grpc.ClientConn, service string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client := healthProto.NewHealthClient(conn)
+	for {
+		response, err := client.Check(ctx, &healthProto.HealthCheckRequest{Service: service}, grpc.WaitForReady(true))
+		if err == nil && response.GetStatus() == healthProto.HealthCheckResponse_SERVING {
+			return nil
+		}
+		if err == nil {
+			err = errors.Errorf("health status %s", response.GetStatus())
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(err, "service %s is not serving", service)
+		case <-time.After(healthCheckInterval):
+		}
+	}
+}
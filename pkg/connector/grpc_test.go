@@ -0,0 +1,127 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthProto "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const testService = "test.TestService"
+
+func testGRPCConfig() config.GRPCConfig {
+	return config.GRPCConfig{
+		Timeout:            time.Second,
+		RetryAttempts:      3,
+		RetryBackoff:       100 * time.Millisecond,
+		RetryMaxBackoff:    time.Second,
+		HealthCheckTimeout: 2 * time.Second,
+	}
+}
+
+// startHealthServer запускает сервер, отвечающий на проверку здоровья testService статусом status
+func startHealthServer(t *testing.T, status healthProto.HealthCheckResponse_ServingStatus) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthProto.RegisterHealthServer(server, healthServer)
+	healthServer.SetServingStatus(testService, status)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestGrpcServiceConfig(t *testing.T) {
+	t.Run("Retries", func(t *testing.T) {
+		data, err := grpcServiceConfig(testService, testGRPCConfig(), []string{"Get"})
+		require.NoError(t, err)
+
+		var serviceConfig grpcServiceConfigJSON
+		require.NoError(t, json.Unmarshal([]byte(data), &serviceConfig))
+		assert.Equal(t, testService, serviceConfig.HealthCheckConfig.ServiceName)
+		require.Len(t, serviceConfig.MethodConfig, 1)
+		assert.Equal(t, []grpcMethodName{{Service: testService, Method: "Get"}}, serviceConfig.MethodConfig[0].Name)
+
+		policy := serviceConfig.MethodConfig[0].RetryPolicy
+		assert.Equal(t, 3, policy.MaxAttempts)
+		assert.Equal(t, "0.1s", policy.InitialBackoff)
+		assert.Equal(t, "1s", policy.MaxBackoff)
+		assert.Equal(t, []string{"UNAVAILABLE"}, policy.RetryableStatusCodes)
+	})
+
+	t.Run("NoRetries", func(t *testing.T) {
+		cfg := testGRPCConfig()
+		cfg.RetryAttempts = 1
+
+		data, err := grpcServiceConfig(testService, cfg, []string{"Get"})
+		require.NoError(t, err)
+		assert.NotContains(t, data, "retryPolicy")
+	})
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	interceptor := timeoutInterceptor(time.Minute)
+
+	t.Run("NoDeadline", func(t *testing.T) {
+		err := interceptor(context.Background(), "/test.TestService/Get", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				deadline, ok := ctx.Deadline()
+				assert.True(t, ok)
+				assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+				return nil
+			})
+		assert.NoError(t, err)
+	})
+
+	t.Run("RequestDeadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		expected, _ := ctx.Deadline()
+
+		err := interceptor(ctx, "/test.TestService/Get", nil, nil, nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				deadline, _ := ctx.Deadline()
+				assert.Equal(t, expected, deadline)
+				return nil
+			})
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetGrpcConnector(t *testing.T) {
+	t.Run("Serving", func(t *testing.T) {
+		addr := startHealthServer(t, healthProto.HealthCheckResponse_SERVING)
+
+		conn, err := GetGrpcConnector(addr, testService, testGRPCConfig(), []string{"Get"})
+		require.NoError(t, err)
+		assert.NoError(t, conn.Close())
+	})
+
+	t.Run("NotServing", func(t *testing.T) {
+		addr := startHealthServer(t, healthProto.HealthCheckResponse_NOT_SERVING)
+		cfg := testGRPCConfig()
+		cfg.HealthCheckTimeout = time.Second
+
+		_, err := GetGrpcConnector(addr, testService, cfg, nil)
+		assert.ErrorContains(t, err, "service test.TestService is not serving")
+	})
+
+	t.Run("MissingCertificate", func(t *testing.T) {
+		cfg := testGRPCConfig()
+		cfg.TLS = config.GRPCTLSConfig{Enabled: true, CertFile: "missing.pem", KeyFile: "missing-key.pem"}
+
+		_, err := GetGrpcConnector("127.0.0.1:0", testService, cfg, nil)
+		assert.ErrorContains(t, err, "failed to load grpc certificate")
+	})
+}
@@ -3,11 +3,18 @@ package connector
 import (
 	"context"
 
+	"github.com/go-park-mail-ru/2024_2_BogoSort/pkg/tracing"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func GetPostgresConnector(dsn string) (*pgxpool.Pool, error) {
-	var dbPool, err = pgxpool.New(context.Background(), dsn)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
+
+	dbPool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, err
 	}
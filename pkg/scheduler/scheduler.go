@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job описывает фоновую задачу, которая выполняется раз в Interval. Run получает контекст
+// планировщика и прерывается вместе с ним
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Start запускает каждую задачу в отдельной горутине до отмены ctx.
+// Ошибки задач логируются и не останавливают последующие запуски
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job.Run(ctx); err != nil {
+				zap.L().Error("scheduled job failed", zap.String("job", job.Name), zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-park-mail-ru/2024_2_BogoSort/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupInMemoryProvider(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewProvider("test", exporter, 1)
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func flush(t *testing.T) {
+	provider, ok := otel.GetTracerProvider().(interface{ ForceFlush(context.Context) error })
+	require.True(t, ok)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+}
+
+func spanAttribute(attrs []attribute.KeyValue, key attribute.Key) attribute.Value {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestPgxTracer(t *testing.T) {
+	t.Run("Query", func(t *testing.T) {
+		exporter := setupInMemoryProvider(t)
+		tracer := NewPgxTracer()
+		sql := "\n\t\tselect id from advert where id = $1"
+
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+		flush(t)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "postgres SELECT", spans[0].Name)
+		assert.Equal(t, "postgresql", spanAttribute(spans[0].Attributes, "db.system").AsString())
+		assert.Equal(t, sql, spanAttribute(spans[0].Attributes, "db.query.text").AsString())
+		assert.Equal(t, int64(1), spanAttribute(spans[0].Attributes, "db.rows_affected").AsInt64())
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		exporter := setupInMemoryProvider(t)
+		tracer := NewPgxTracer()
+
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "UPDATE cart SET status = $1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+		flush(t)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "postgres UPDATE", spans[0].Name)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, "connection reset", spans[0].Status.Description)
+	})
+
+	t.Run("NoRows", func(t *testing.T) {
+		exporter := setupInMemoryProvider(t)
+		tracer := NewPgxTracer()
+
+		ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+		flush(t)
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	})
+}
+
+func TestLogFields(t *testing.T) {
+	assert.Empty(t, LogFields(context.Background()))
+
+	setupInMemoryProvider(t)
+	ctx, span := otel.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	fields := LogFields(ctx)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "trace_id", fields[0].Key)
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields[0].String)
+	assert.Equal(t, "span_id", fields[1].Key)
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields[1].String)
+}
+
+func TestInit(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	t.Run("None", func(t *testing.T) {
+		shutdown, err := Init(context.Background(), "test", config.TracingConfig{Exporter: config.TracingExporterNone})
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		shutdown, err := Init(context.Background(), "test", config.TracingConfig{
+			Exporter:    config.TracingExporterStdout,
+			SampleRatio: 1,
+		})
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := Init(context.Background(), "test", config.TracingConfig{Exporter: "zipkin"})
+		assert.ErrorContains(t, err, `unknown tracing exporter "zipkin"`)
+	})
+}